@@ -0,0 +1,95 @@
+import React from 'react'
+
+import classNames from 'classnames'
+
+import { pluralize } from '@sourcegraph/common'
+import { displayRepoName } from '@sourcegraph/shared/src/components/RepoLink'
+import { UserAvatar } from '@sourcegraph/shared/src/components/UserAvatar'
+import {
+    getContributorMatchUrl,
+    getRepositoryUrl,
+    type CommitAuthorMatch,
+    type ContributorMatch,
+} from '@sourcegraph/shared/src/search/stream'
+import { Link } from '@sourcegraph/wildcard'
+
+import { ResultContainer } from './ResultContainer'
+
+import styles from './OwnerSearchResult.module.scss'
+import resultStyles from './ResultContainer.module.scss'
+
+export interface ContributorSearchResultProps {
+    result: CommitAuthorMatch | ContributorMatch
+    onSelect: () => void
+    containerClassName?: string
+    as?: React.ElementType
+    index: number
+}
+
+/**
+ * Renders a commit author selected by `select:commit.author`, or a repository
+ * contributor selected by `select:repo.contributors`.
+ */
+export const ContributorSearchResult: React.FunctionComponent<ContributorSearchResultProps> = ({
+    result,
+    onSelect,
+    containerClassName,
+    as,
+    index,
+}) => {
+    const displayName = result.name || result.email || 'Unknown person'
+
+    const title = (
+        <div className="d-flex align-items-center">
+            <UserAvatar
+                user={{ username: displayName, avatarURL: null, displayName }}
+                className={styles.avatar}
+                size={16}
+            />
+            {result.email ? (
+                <Link to={`mailto:${result.email}`} className="text-muted">
+                    {displayName}
+                </Link>
+            ) : (
+                <span className="text-muted">{displayName}</span>
+            )}
+            {result.type === 'contributor' && (
+                <>
+                    <span className="text-muted mx-1">in</span>
+                    <Link to={getRepositoryUrl(result.repository, result.branches)}>
+                        {displayRepoName(result.repository)}
+                    </Link>
+                </>
+            )}
+        </div>
+    )
+
+    return (
+        <ResultContainer
+            index={index}
+            title={title}
+            resultType={result.type}
+            onResultClicked={onSelect}
+            repoName={result.type === 'contributor' ? result.repository : undefined}
+            className={containerClassName}
+            as={as}
+        >
+            <div
+                className={classNames(resultStyles.searchResultMatch, 'p-2 flex-column')}
+                data-testid="contributor-search-result"
+            >
+                <small className={resultStyles.matchType}>
+                    {result.type === 'contributor' ? (
+                        <Link to={getContributorMatchUrl(result)}>
+                            {result.count} {pluralize('commit', result.count)}
+                        </Link>
+                    ) : (
+                        <span>
+                            {result.count} matching {pluralize('commit', result.count)}
+                        </span>
+                    )}
+                </small>
+            </div>
+        </ResultContainer>
+    )
+}
@@ -0,0 +1,58 @@
+import React from 'react'
+
+import classNames from 'classnames'
+
+import { pluralize } from '@sourcegraph/common'
+import { getLanguageMatchUrl, type LanguageMatch } from '@sourcegraph/shared/src/search/stream'
+import { Link } from '@sourcegraph/wildcard'
+
+import { ResultContainer } from './ResultContainer'
+
+import resultStyles from './ResultContainer.module.scss'
+
+export interface LanguageSearchResultProps {
+    result: LanguageMatch
+    onSelect: () => void
+    containerClassName?: string
+    as?: React.ElementType
+    index: number
+}
+
+/**
+ * Renders a language selected by `select:file.language`.
+ */
+export const LanguageSearchResult: React.FunctionComponent<LanguageSearchResultProps> = ({
+    result,
+    onSelect,
+    containerClassName,
+    as,
+    index,
+}) => {
+    const title = (
+        <Link to={getLanguageMatchUrl(result)} className="text-muted">
+            {result.language}
+        </Link>
+    )
+
+    return (
+        <ResultContainer
+            index={index}
+            title={title}
+            resultType={result.type}
+            onResultClicked={onSelect}
+            className={containerClassName}
+            as={as}
+        >
+            <div
+                className={classNames(resultStyles.searchResultMatch, 'p-2 flex-column')}
+                data-testid="language-search-result"
+            >
+                <small className={resultStyles.matchType}>
+                    <span>
+                        {result.count} matching {pluralize('file', result.count)}
+                    </span>
+                </small>
+            </div>
+        </ResultContainer>
+    )
+}
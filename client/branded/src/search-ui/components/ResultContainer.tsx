@@ -36,6 +36,9 @@ const accessibleResultType: Record<SearchMatch['type'], string> = {
     commit: 'commit',
     person: 'person',
     team: 'team',
+    language: 'language',
+    author: 'commit author',
+    contributor: 'contributor',
 }
 
 /**
@@ -27,6 +27,8 @@ import {
     RepoSearchResult,
     SymbolSearchResult,
 } from '../components'
+import { ContributorSearchResult } from '../components/ContributorSearchResult'
+import { LanguageSearchResult } from '../components/LanguageSearchResult'
 import { OwnerSearchResult } from '../components/OwnerSearchResult'
 
 import { NoResultsPage } from './NoResultsPage'
@@ -219,6 +221,29 @@ export const StreamingSearchResultsList: React.FunctionComponent<
                             />
                         )
                     }
+                    case 'language': {
+                        return (
+                            <LanguageSearchResult
+                                index={index}
+                                result={result}
+                                as="li"
+                                onSelect={() => logSearchResultClicked?.(index, 'language', resultsNumber)}
+                                containerClassName={resultClassName}
+                            />
+                        )
+                    }
+                    case 'author':
+                    case 'contributor': {
+                        return (
+                            <ContributorSearchResult
+                                index={index}
+                                result={result}
+                                as="li"
+                                onSelect={() => logSearchResultClicked?.(index, result.type, resultsNumber)}
+                                containerClassName={resultClassName}
+                            />
+                        )
+                    }
                 }
             }
 
@@ -311,5 +336,10 @@ function itemKey(item: SearchMatch): string {
     if (item.type === 'symbol') {
         return `file:${getMatchUrl(item)}`
     }
+    if (item.type === 'author' || item.type === 'contributor') {
+        // Several people can share an email address, and contributors link to
+        // their repository.
+        return `${item.type}:${getMatchUrl(item)}:${item.name}:${item.email}`
+    }
     return getMatchUrl(item)
 }
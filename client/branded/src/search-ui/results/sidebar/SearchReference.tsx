@@ -257,11 +257,14 @@ To use this filter, the search query must contain \`type:diff\` or \`type:commit
         description: `Shows only query results for a given type. For example, \`select:repo\` displays only distinct repository paths from search results. The following values are available:
 
 - \`select:repo\`
+- \`select:repo.contributors\`
 - \`select:commit.diff.added\`
 - \`select:commit.diff.removed\`
+- \`select:commit.author\`
 - \`select:file\`
 - \`select:file.directory\`
 - \`select:file.path\`
+- \`select:file.language\`
 - \`select:content\`
 - \`select:symbol.symboltype\`
 
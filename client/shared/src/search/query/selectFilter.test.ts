@@ -58,7 +58,25 @@ describe('selectorCompletion', () => {
             commit,
             commit.diff,
             commit.diff.added,
-            commit.diff.removed
+            commit.diff.removed,
+            commit.author
+        `)
+    })
+
+    test('suggest depth 1 file completions', () => {
+        expect(selectorCompletion(create('file.'))).toMatchInlineSnapshot(`
+            file,
+            file.directory,
+            file.path,
+            file.owners,
+            file.language
+        `)
+    })
+
+    test('suggest depth 1 repo completions', () => {
+        expect(selectorCompletion(create('repo.'))).toMatchInlineSnapshot(`
+            repo,
+            repo.contributors
         `)
     })
 })
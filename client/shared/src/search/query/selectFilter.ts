@@ -8,10 +8,11 @@ interface Access {
 export const SELECTORS: Access[] = [
     {
         name: 'repo',
+        fields: [{ name: 'contributors' }],
     },
     {
         name: 'file',
-        fields: [{ name: 'directory' }, { name: 'path' }, { name: 'owners' }],
+        fields: [{ name: 'directory' }, { name: 'path' }, { name: 'owners' }, { name: 'language' }],
     },
     {
         name: 'content',
@@ -49,7 +50,7 @@ export const SELECTORS: Access[] = [
     },
     {
         name: 'commit',
-        fields: [{ name: 'diff', fields: [{ name: 'added' }, { name: 'removed' }] }],
+        fields: [{ name: 'diff', fields: [{ name: 'added' }, { name: 'removed' }] }, { name: 'author' }],
     },
 ]
 const kinds = new Set(SELECTORS.map(value => value.name))
@@ -37,7 +37,16 @@ export type SearchEvent =
     | { type: 'error'; data: ErrorLike }
     | { type: 'done'; data: {} }
 
-export type SearchMatch = ContentMatch | RepositoryMatch | CommitMatch | SymbolMatch | PathMatch | OwnerMatch
+export type SearchMatch =
+    | ContentMatch
+    | RepositoryMatch
+    | CommitMatch
+    | SymbolMatch
+    | PathMatch
+    | OwnerMatch
+    | LanguageMatch
+    | CommitAuthorMatch
+    | ContributorMatch
 
 export interface PathMatch {
     type: 'path'
@@ -191,6 +200,40 @@ export interface TeamMatch extends BaseOwnerMatch {
     email?: string
 }
 
+/**
+ * A language of matching files, as selected by `select:file.language`.
+ */
+export interface LanguageMatch {
+    type: 'language'
+    language: string
+    // The number of matching files in the language.
+    count: number
+}
+
+/**
+ * An author of matching commits, as selected by `select:commit.author`.
+ */
+export interface CommitAuthorMatch {
+    type: 'author'
+    name: string
+    email: string
+    // The number of matching commits by the author.
+    count: number
+}
+
+/**
+ * A top committer of a matching repository, as selected by `select:repo.contributors`.
+ */
+export interface ContributorMatch {
+    type: 'contributor'
+    repository: string
+    branches?: string[]
+    name: string
+    email: string
+    // The number of commits by the contributor.
+    count: number
+}
+
 /**
  * An aggregate type representing a progress update.
  * Should be replaced when a new ones come in.
@@ -287,7 +330,7 @@ interface Alert {
 }
 
 // Same key values from internal/search/alert.go
-export type AnnotationName = 'ResultCount'
+export type AnnotationName = 'ResultCount' | 'Line'
 
 export interface ProposedQuery {
     description?: string | null
@@ -651,6 +694,17 @@ export function getOwnerMatchUrl(ownerMatch: OwnerMatch, ignoreUnknownPerson: bo
     return '/unknown-person/' + encodeURI(ownerMatch.handle || 'unknown')
 }
 
+export function getLanguageMatchUrl(languageMatch: LanguageMatch): string {
+    const language = /\s/.test(languageMatch.language)
+        ? JSON.stringify(languageMatch.language)
+        : languageMatch.language
+    return '/search?q=' + encodeURIComponent(`lang:${language}`)
+}
+
+export function getContributorMatchUrl(contributorMatch: ContributorMatch): string {
+    return getRepositoryUrl(contributorMatch.repository, contributorMatch.branches) + '/-/stats/contributors'
+}
+
 export function getMatchUrl(match: SearchMatch): string {
     switch (match.type) {
         case 'path':
@@ -668,6 +722,15 @@ export function getMatchUrl(match: SearchMatch): string {
         case 'team': {
             return getOwnerMatchUrl(match)
         }
+        case 'language': {
+            return getLanguageMatchUrl(match)
+        }
+        case 'author': {
+            return `mailto:${match.email}`
+        }
+        case 'contributor': {
+            return getContributorMatchUrl(match)
+        }
     }
 }
 
@@ -32,6 +32,8 @@ export {
     getRepoMatchLabel,
     getRepoMatchUrl,
     getMatchUrl,
+    getLanguageMatchUrl,
+    getContributorMatchUrl,
     type RepositoryMatch,
     type SymbolMatch,
     type PathMatch,
@@ -43,6 +45,9 @@ export {
     type TeamMatch,
     type PersonMatch,
     type CommitMatch,
+    type LanguageMatch,
+    type CommitAuthorMatch,
+    type ContributorMatch,
     type Progress,
     type Range,
     type Filter,
@@ -3,8 +3,10 @@ import type { ComponentType, SvelteComponent } from 'svelte'
 import type { SearchMatch } from '$lib/shared'
 
 import CommitSearchResult from './CommitSearchResult.svelte'
+import ContributorSearchResult from './ContributorSearchResult.svelte'
 import FileContentSearchResult from './FileContentSearchResult.svelte'
 import FilePathSearchResult from './FilePathSearchResult.svelte'
+import LanguageSearchResult from './LanguageSearchResult.svelte'
 import PersonSearchResult from './PersonSearchResult.svelte'
 import RepoSearchResult from './RepoSearchResult.svelte'
 import SymbolSearchResult from './SymbolSearchResult.svelte'
@@ -26,6 +28,9 @@ const searchResultComponents: SearchResultUIMap = {
     person: PersonSearchResult,
     team: TeamSearchResult,
     commit: CommitSearchResult,
+    language: LanguageSearchResult,
+    author: ContributorSearchResult,
+    contributor: ContributorSearchResult,
 }
 
 export function getSearchResultComponent<T extends SearchMatchType>(result: {
@@ -638,6 +638,7 @@ describe('Search', () => {
                                 notifySlack: false,
                                 query: 'context:global Batch Change patternType:literal',
                                 slackWebhookURL: null,
+                                variables: [],
                             },
                         ],
                         totalCount: 1,
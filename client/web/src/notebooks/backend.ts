@@ -13,6 +13,10 @@ import {
     type DeleteNotebookStarResult,
     type DeleteNotebookStarVariables,
     type DeleteNotebookVariables,
+    type FetchNotebookInsightSeriesResult,
+    type FetchNotebookInsightSeriesVariables,
+    type FetchNotebookReferencesResult,
+    type FetchNotebookReferencesVariables,
     type FetchNotebookResult,
     type FetchNotebookVariables,
     type ListNotebooksResult,
@@ -25,6 +29,8 @@ import {
     NotebooksOrderBy,
 } from '../graphql-operations'
 
+import type { InsightBlockOutput, ReferenceLocation } from '.'
+
 const notebooksFragment = gql`
     fragment NotebookFields on Notebook {
         __typename
@@ -89,6 +95,33 @@ const notebooksFragment = gql`
                     symbolKind
                 }
             }
+            ... on ComputeBlock {
+                __typename
+                id
+                computeInput {
+                    __typename
+                    text
+                    output
+                }
+            }
+            ... on InsightBlock {
+                __typename
+                id
+                insightSeriesID
+            }
+            ... on ReferencesBlock {
+                __typename
+                id
+                referencesInput {
+                    __typename
+                    repositoryName
+                    filePath
+                    commit
+                    line
+                    character
+                    symbolName
+                }
+            }
         }
     }
 `
@@ -268,3 +301,127 @@ export function deleteNotebookStar(notebookID: Scalars['ID']): Observable<Delete
         notebookID,
     }).pipe(map(dataOrThrowErrors))
 }
+
+const fetchNotebookInsightSeriesQuery = gql`
+    query FetchNotebookInsightSeries($seriesId: String!) {
+        insightViews(seriesId: $seriesId, first: 1) {
+            nodes {
+                presentation {
+                    __typename
+                    ... on LineChartInsightViewPresentation {
+                        title
+                    }
+                    ... on PieChartInsightViewPresentation {
+                        title
+                    }
+                }
+                dataSeries {
+                    seriesId
+                    label
+                    points {
+                        dateTime
+                        value
+                    }
+                }
+            }
+        }
+    }
+`
+
+export function fetchNotebookInsightSeries(seriesId: string): Observable<InsightBlockOutput> {
+    return requestGraphQL<FetchNotebookInsightSeriesResult, FetchNotebookInsightSeriesVariables>(
+        fetchNotebookInsightSeriesQuery,
+        { seriesId }
+    ).pipe(
+        map(dataOrThrowErrors),
+        map(data => {
+            const view = data.insightViews.nodes[0]
+            const series = view?.dataSeries.find(series => series.seriesId === seriesId)
+            if (!view || !series) {
+                throw new Error('Insight series not found')
+            }
+            return { insightTitle: view.presentation.title, seriesLabel: series.label, points: series.points }
+        })
+    )
+}
+
+const fetchNotebookReferencesQuery = gql`
+    query FetchNotebookReferences(
+        $repositoryName: String!
+        $commit: String!
+        $filePath: String!
+        $line: Int!
+        $character: Int!
+        $first: Int!
+    ) {
+        repository(name: $repositoryName) {
+            commit(rev: $commit) {
+                blob(path: $filePath) {
+                    lsif {
+                        references(line: $line, character: $character, first: $first) {
+                            nodes {
+                                resource {
+                                    path
+                                    repository {
+                                        name
+                                    }
+                                    commit {
+                                        oid
+                                    }
+                                }
+                                range {
+                                    start {
+                                        line
+                                        character
+                                    }
+                                    end {
+                                        line
+                                        character
+                                    }
+                                }
+                                url
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    }
+`
+
+// The maximum number of references listed in a notebook references block.
+const MAX_NOTEBOOK_REFERENCES = 100
+
+export function fetchNotebookReferences(
+    variables: Omit<FetchNotebookReferencesVariables, 'first'>
+): Observable<ReferenceLocation[]> {
+    return requestGraphQL<FetchNotebookReferencesResult, FetchNotebookReferencesVariables>(
+        fetchNotebookReferencesQuery,
+        { ...variables, first: MAX_NOTEBOOK_REFERENCES }
+    ).pipe(
+        map(dataOrThrowErrors),
+        map(data => {
+            const lsif = data.repository?.commit?.blob?.lsif
+            if (!lsif) {
+                throw new Error('No precise code intelligence is available for this commit')
+            }
+            return lsif.references.nodes.flatMap(({ resource, range, url }) =>
+                range
+                    ? [
+                          {
+                              repositoryName: resource.repository.name,
+                              filePath: resource.path,
+                              commit: resource.commit.oid,
+                              // Ranges in the code intel API are 0-based, UI ranges are 1-based.
+                              range: {
+                                  start: { line: range.start.line + 1, character: range.start.character + 1 },
+                                  end: { line: range.end.line + 1, character: range.end.character + 1 },
+                              },
+                              url,
+                          },
+                      ]
+                    : []
+            )
+        })
+    )
+}
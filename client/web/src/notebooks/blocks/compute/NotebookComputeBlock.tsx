@@ -0,0 +1,144 @@
+import React, { useCallback, useEffect, useMemo, useState } from 'react'
+
+import { EditorView } from '@codemirror/view'
+import { mdiPlayCircleOutline, mdiCalculatorVariantOutline } from '@mdi/js'
+import { of } from 'rxjs'
+import { startWith } from 'rxjs/operators'
+
+import { CodeMirrorQueryInput } from '@sourcegraph/branded'
+import { isErrorLike } from '@sourcegraph/common'
+import { editorHeight } from '@sourcegraph/shared/src/components/CodeMirrorEditor'
+import { Alert, Code, Icon, LoadingSpinner, useObservable } from '@sourcegraph/wildcard'
+
+import type { BlockProps, ComputeBlock } from '../..'
+import { SearchPatternType } from '../../../graphql-operations'
+import { blockKeymap, focusEditor as focusCodeMirrorInput } from '../../codemirror-utils'
+import type { BlockMenuAction } from '../menu/NotebookBlockMenu'
+import { useCommonBlockMenuActions } from '../menu/useCommonBlockMenuActions'
+import { NotebookBlock } from '../NotebookBlock'
+import { useModifierKeyLabel } from '../useModifierKeyLabel'
+
+import styles from './NotebookComputeBlock.module.scss'
+
+const LOADING = 'LOADING' as const
+
+// Defines the max height for the CodeMirror editor
+const maxEditorHeight = editorHeight({ maxHeight: '300px' })
+const editorAttributes = [
+    EditorView.editorAttributes.of({
+        'data-testid': 'notebook-compute-block-input',
+    }),
+    EditorView.contentAttributes.of({
+        'aria-label': 'Compute expression input',
+    }),
+]
+
+export const NotebookComputeBlock: React.FunctionComponent<React.PropsWithChildren<BlockProps<ComputeBlock>>> =
+    React.memo(({ id, input, output, isSelected, isReadOnly, onBlockInputChange, onRunBlock, ...props }) => {
+        const [editor, setEditor] = useState<EditorView | null>(null)
+        const computeOutput = useObservable(
+            useMemo(() => output?.pipe(startWith(LOADING)) ?? of(undefined), [output])
+        )
+
+        const onInputChange = useCallback(
+            (text: string) => onBlockInputChange(id, { type: 'compute', input: { ...input, text } }),
+            [id, input, onBlockInputChange]
+        )
+
+        // Store the output of the last run with the block, so that the notebook
+        // can be displayed without running the expression again.
+        useEffect(() => {
+            if (
+                !isReadOnly &&
+                typeof computeOutput === 'string' &&
+                computeOutput !== LOADING &&
+                computeOutput !== input.output
+            ) {
+                onBlockInputChange(id, { type: 'compute', input: { ...input, output: computeOutput } })
+            }
+        }, [id, input, isReadOnly, computeOutput, onBlockInputChange])
+
+        const runBlock = useCallback(() => onRunBlock(id), [id, onRunBlock])
+
+        const modifierKeyLabel = useModifierKeyLabel()
+        const mainMenuAction: BlockMenuAction = useMemo(() => {
+            const isLoading = computeOutput === LOADING
+            return {
+                type: 'button',
+                label: isLoading ? 'Computing...' : 'Run compute',
+                isDisabled: isLoading,
+                icon: <Icon aria-hidden={true} svgPath={mdiPlayCircleOutline} />,
+                onClick: onRunBlock,
+                keyboardShortcutLabel: isSelected ? `${modifierKeyLabel} + ↵` : '',
+            }
+        }, [onRunBlock, isSelected, modifierKeyLabel, computeOutput])
+
+        const commonMenuActions = useCommonBlockMenuActions({ id, isReadOnly, ...props })
+
+        const focusInput = useCallback(() => {
+            if (editor) {
+                focusCodeMirrorInput(editor)
+            }
+        }, [editor])
+
+        // Focus editor on component creation if necessary
+        useEffect(() => {
+            if (editor && input.initialFocusInput) {
+                focusCodeMirrorInput(editor)
+            }
+        }, [input.initialFocusInput, editor])
+
+        const displayedOutput = computeOutput === undefined ? input.output : computeOutput
+
+        return (
+            <NotebookBlock
+                id={id}
+                aria-label="Notebook compute block"
+                isSelected={isSelected}
+                isReadOnly={isReadOnly}
+                isInputVisible={true}
+                focusInput={focusInput}
+                mainAction={isReadOnly ? undefined : mainMenuAction}
+                actions={isSelected ? commonMenuActions : []}
+                {...props}
+            >
+                <div className={styles.content}>
+                    <div className={styles.inputWrapper}>
+                        <Icon aria-hidden={true} svgPath={mdiCalculatorVariantOutline} />
+                        <div className={styles.codeMirrorWrapper}>
+                            <CodeMirrorQueryInput
+                                ref={setEditor}
+                                value={input.text}
+                                patternType={SearchPatternType.regexp}
+                                interpretComments={false}
+                                onChange={onInputChange}
+                                multiLine={true}
+                                readOnly={isReadOnly}
+                                extension={useMemo(
+                                    () => [blockKeymap({ runBlock }), maxEditorHeight, editorAttributes],
+                                    [runBlock]
+                                )}
+                            />
+                        </div>
+                    </div>
+                    {displayedOutput === LOADING && (
+                        <div className="d-flex justify-content-center py-3">
+                            <LoadingSpinner inline={false} />
+                        </div>
+                    )}
+                    {isErrorLike(displayedOutput) && (
+                        <Alert className="mt-3 mb-0" variant="danger">
+                            {displayedOutput.message}
+                        </Alert>
+                    )}
+                    {typeof displayedOutput === 'string' && displayedOutput !== LOADING && (
+                        <pre className={styles.output} data-testid="notebook-compute-block-output">
+                            <Code>{displayedOutput}</Code>
+                        </pre>
+                    )}
+                </div>
+            </NotebookBlock>
+        )
+    })
+
+NotebookComputeBlock.displayName = 'NotebookComputeBlock'
@@ -0,0 +1,131 @@
+import React, { useCallback, useMemo, useRef, useState } from 'react'
+
+import { mdiChartLine, mdiCheck, mdiPencil } from '@mdi/js'
+import { of } from 'rxjs'
+import { startWith } from 'rxjs/operators'
+
+import { isErrorLike } from '@sourcegraph/common'
+import { Alert, Icon, Input, LineChart, LoadingSpinner, ParentSize, useObservable } from '@sourcegraph/wildcard'
+
+import type { BlockProps, InsightBlock, InsightSeriesPoint } from '../..'
+import type { BlockMenuAction } from '../menu/NotebookBlockMenu'
+import { useCommonBlockMenuActions } from '../menu/useCommonBlockMenuActions'
+import { NotebookBlock } from '../NotebookBlock'
+import { useModifierKeyLabel } from '../useModifierKeyLabel'
+
+import styles from './NotebookInsightBlock.module.scss'
+
+const LOADING = 'LOADING' as const
+
+const getXValue = (point: InsightSeriesPoint): Date => new Date(point.dateTime)
+const getYValue = (point: InsightSeriesPoint): number => point.value
+
+export const NotebookInsightBlock: React.FunctionComponent<React.PropsWithChildren<BlockProps<InsightBlock>>> =
+    React.memo(({ id, input, output, isSelected, isReadOnly, onBlockInputChange, onRunBlock, ...props }) => {
+        const [showInputs, setShowInputs] = useState(input.seriesId.length === 0)
+        const [seriesIdInput, setSeriesIdInput] = useState(input.seriesId)
+        const inputReference = useRef<HTMLInputElement>(null)
+        const insight = useObservable(useMemo(() => output?.pipe(startWith(LOADING)) ?? of(undefined), [output]))
+
+        const onSave = useCallback(() => {
+            setShowInputs(false)
+            onBlockInputChange(id, { type: 'insight', input: { seriesId: seriesIdInput.trim() } })
+            onRunBlock(id)
+        }, [id, seriesIdInput, onBlockInputChange, onRunBlock])
+
+        const modifierKeyLabel = useModifierKeyLabel()
+        const toggleEditMenuAction: BlockMenuAction[] = useMemo(
+            () => [
+                {
+                    type: 'button',
+                    label: showInputs ? 'Save' : 'Edit',
+                    icon: <Icon aria-hidden={true} svgPath={showInputs ? mdiCheck : mdiPencil} />,
+                    onClick: showInputs ? onSave : () => setShowInputs(true),
+                    keyboardShortcutLabel: showInputs ? `${modifierKeyLabel} + ↵` : '↵',
+                },
+            ],
+            [showInputs, onSave, modifierKeyLabel]
+        )
+        const commonMenuActions = useCommonBlockMenuActions({ id, isReadOnly, ...props })
+        const menuActions = useMemo(
+            () => (!isReadOnly ? toggleEditMenuAction : []).concat(commonMenuActions),
+            [isReadOnly, toggleEditMenuAction, commonMenuActions]
+        )
+
+        const focusInput = useCallback(() => inputReference.current?.focus(), [])
+
+        return (
+            <NotebookBlock
+                className={styles.block}
+                id={id}
+                aria-label="Notebook insight block"
+                isSelected={isSelected}
+                isReadOnly={isReadOnly}
+                isInputVisible={showInputs}
+                setIsInputVisible={setShowInputs}
+                focusInput={focusInput}
+                actions={isSelected ? menuActions : []}
+                {...props}
+            >
+                <div className={styles.header}>
+                    <Icon aria-hidden={true} svgPath={mdiChartLine} />
+                    <div className={styles.separator} />
+                    {insight && insight !== LOADING && !isErrorLike(insight) ? (
+                        <span>
+                            {insight.insightTitle} › <strong>{insight.seriesLabel}</strong>
+                        </span>
+                    ) : (
+                        <span>{input.seriesId || 'No insight series selected.'}</span>
+                    )}
+                </div>
+                {showInputs && (
+                    <form
+                        onSubmit={event => {
+                            event.preventDefault()
+                            onSave()
+                        }}
+                    >
+                        <Input
+                            ref={inputReference}
+                            label="Code Insights series ID"
+                            value={seriesIdInput}
+                            onChange={event => setSeriesIdInput(event.target.value)}
+                            data-testid="notebook-insight-block-input"
+                        />
+                    </form>
+                )}
+                {insight === LOADING && (
+                    <div className="d-flex justify-content-center py-3">
+                        <LoadingSpinner inline={false} />
+                    </div>
+                )}
+                {isErrorLike(insight) && (
+                    <Alert className="m-3" variant="danger">
+                        {insight.message}
+                    </Alert>
+                )}
+                {insight && insight !== LOADING && !isErrorLike(insight) && (
+                    <ParentSize className={styles.chart}>
+                        {parent => (
+                            <LineChart
+                                width={parent.width}
+                                height={parent.height}
+                                series={[
+                                    {
+                                        id: input.seriesId,
+                                        name: insight.seriesLabel,
+                                        data: insight.points,
+                                        getXValue,
+                                        getYValue,
+                                        color: 'var(--blue)',
+                                    },
+                                ]}
+                            />
+                        )}
+                    </ParentSize>
+                )}
+            </NotebookBlock>
+        )
+    })
+
+NotebookInsightBlock.displayName = 'NotebookInsightBlock'
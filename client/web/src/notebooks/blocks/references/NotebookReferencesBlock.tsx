@@ -0,0 +1,175 @@
+import React, { useCallback, useMemo, useRef, useState } from 'react'
+
+import { mdiCheck, mdiOpenInNew, mdiPencil, mdiVectorLink } from '@mdi/js'
+import { of } from 'rxjs'
+import { startWith } from 'rxjs/operators'
+
+import { isErrorLike } from '@sourcegraph/common'
+import { displayRepoName } from '@sourcegraph/shared/src/components/RepoLink'
+import { getRepositoryUrl } from '@sourcegraph/shared/src/search/stream'
+import { toPrettyBlobURL } from '@sourcegraph/shared/src/util/url'
+import { Alert, Icon, Input, Link, LoadingSpinner, useObservable } from '@sourcegraph/wildcard'
+
+import type { BlockProps, ReferencesBlock } from '../..'
+import { parseReferencesBlockInput } from '../../serialize'
+import type { BlockMenuAction } from '../menu/NotebookBlockMenu'
+import { useCommonBlockMenuActions } from '../menu/useCommonBlockMenuActions'
+import { NotebookBlock } from '../NotebookBlock'
+import { RepoFileSymbolLink } from '../RepoFileSymbolLink'
+import { useModifierKeyLabel } from '../useModifierKeyLabel'
+
+import styles from './NotebookReferencesBlock.module.scss'
+
+const LOADING = 'LOADING' as const
+
+export const NotebookReferencesBlock: React.FunctionComponent<React.PropsWithChildren<BlockProps<ReferencesBlock>>> =
+    React.memo(({ id, input, output, isSelected, isReadOnly, onBlockInputChange, onRunBlock, ...props }) => {
+        const [showInputs, setShowInputs] = useState(input.repositoryName.length === 0)
+        const [urlInput, setURLInput] = useState('')
+        const [symbolNameInput, setSymbolNameInput] = useState(input.symbolName)
+        const inputReference = useRef<HTMLInputElement>(null)
+        const references = useObservable(
+            useMemo(() => output?.pipe(startWith(LOADING)) ?? of(undefined), [output])
+        )
+
+        const symbolURL = useMemo(
+            () =>
+                toPrettyBlobURL({
+                    repoName: input.repositoryName,
+                    revision: input.commit,
+                    filePath: input.filePath,
+                    position: { line: input.line + 1, character: input.character + 1 },
+                }),
+            [input]
+        )
+
+        const onSave = useCallback(() => {
+            setShowInputs(false)
+            // Keep the current location if no new link was provided.
+            const location = urlInput.trim() ? parseReferencesBlockInput(urlInput.trim()) : input
+            onBlockInputChange(id, {
+                type: 'references',
+                input: { ...location, symbolName: symbolNameInput.trim() },
+            })
+            onRunBlock(id)
+        }, [id, input, urlInput, symbolNameInput, onBlockInputChange, onRunBlock])
+
+        const modifierKeyLabel = useModifierKeyLabel()
+        const toggleEditMenuAction: BlockMenuAction[] = useMemo(
+            () => [
+                {
+                    type: 'button',
+                    label: showInputs ? 'Save' : 'Edit',
+                    icon: <Icon aria-hidden={true} svgPath={showInputs ? mdiCheck : mdiPencil} />,
+                    onClick: showInputs ? onSave : () => setShowInputs(true),
+                    keyboardShortcutLabel: showInputs ? `${modifierKeyLabel} + ↵` : '↵',
+                },
+            ],
+            [showInputs, onSave, modifierKeyLabel]
+        )
+        const linkMenuAction: BlockMenuAction[] = useMemo(
+            () => [
+                {
+                    type: 'link',
+                    label: 'Open in new tab',
+                    icon: <Icon aria-hidden={true} svgPath={mdiOpenInNew} />,
+                    url: `${symbolURL}#tab=references`,
+                },
+            ],
+            [symbolURL]
+        )
+        const commonMenuActions = useCommonBlockMenuActions({ id, isReadOnly, ...props })
+        const menuActions = useMemo(
+            () => (!isReadOnly ? toggleEditMenuAction : []).concat(linkMenuAction).concat(commonMenuActions),
+            [isReadOnly, toggleEditMenuAction, linkMenuAction, commonMenuActions]
+        )
+
+        const focusInput = useCallback(() => inputReference.current?.focus(), [])
+
+        return (
+            <NotebookBlock
+                className={styles.block}
+                id={id}
+                aria-label="Notebook references block"
+                isSelected={isSelected}
+                isReadOnly={isReadOnly}
+                isInputVisible={showInputs}
+                setIsInputVisible={setShowInputs}
+                focusInput={focusInput}
+                actions={isSelected ? menuActions : linkMenuAction}
+                {...props}
+            >
+                <div className={styles.header}>
+                    <Icon aria-hidden={true} svgPath={mdiVectorLink} />
+                    <div className={styles.separator} />
+                    {input.repositoryName ? (
+                        <RepoFileSymbolLink
+                            repoName={input.repositoryName}
+                            repoURL={getRepositoryUrl(input.repositoryName, [input.commit])}
+                            filePath={input.filePath}
+                            fileURL={toPrettyBlobURL({
+                                repoName: input.repositoryName,
+                                revision: input.commit,
+                                filePath: input.filePath,
+                            })}
+                            symbolName={input.symbolName || `L${input.line + 1}:${input.character + 1}`}
+                            symbolURL={symbolURL}
+                        />
+                    ) : (
+                        <>No symbol selected.</>
+                    )}
+                </div>
+                {showInputs && (
+                    <form
+                        className={styles.inputs}
+                        onSubmit={event => {
+                            event.preventDefault()
+                            onSave()
+                        }}
+                    >
+                        <Input
+                            ref={inputReference}
+                            label="Link to the symbol at a full commit ID"
+                            placeholder="https://sourcegraph.example.com/repo@<commit>/-/blob/file.go?L10:5"
+                            value={urlInput}
+                            onChange={event => setURLInput(event.target.value)}
+                            data-testid="notebook-references-block-url-input"
+                        />
+                        <Input
+                            label="Symbol name"
+                            value={symbolNameInput}
+                            onChange={event => setSymbolNameInput(event.target.value)}
+                            className="mt-2"
+                        />
+                        {/* Allows submitting the form with the Enter key. */}
+                        <button type="submit" hidden={true} />
+                    </form>
+                )}
+                {references === LOADING && (
+                    <div className="d-flex justify-content-center py-3">
+                        <LoadingSpinner inline={false} />
+                    </div>
+                )}
+                {isErrorLike(references) && (
+                    <Alert className="m-3" variant="danger">
+                        {references.message}
+                    </Alert>
+                )}
+                {references && references !== LOADING && !isErrorLike(references) && (
+                    <ul className={styles.references} data-testid="notebook-references-block-list">
+                        {references.length === 0 && <li className="text-muted">No references found.</li>}
+                        {references.map(reference => (
+                            <li key={reference.url}>
+                                <Link to={reference.url}>
+                                    {displayRepoName(reference.repositoryName)} › {reference.filePath}:
+                                    {reference.range.start.line}:{reference.range.start.character}
+                                </Link>
+                            </li>
+                        ))}
+                    </ul>
+                )}
+            </NotebookBlock>
+        )
+    })
+
+NotebookReferencesBlock.displayName = 'NotebookReferencesBlock'
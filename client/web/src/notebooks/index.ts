@@ -8,7 +8,7 @@ import type { UIRangeSpec } from '@sourcegraph/shared/src/util/url'
 import type { HighlightLineRange, SymbolKind } from '../graphql-operations'
 
 // When adding a new block type, make sure to track its usage in internal/usagestats/notebooks.go.
-export type BlockType = 'md' | 'query' | 'file' | 'compute' | 'symbol'
+export type BlockType = 'md' | 'query' | 'file' | 'compute' | 'symbol' | 'insight' | 'references'
 
 interface BaseBlock<I, O> {
     id: string
@@ -71,25 +71,86 @@ export interface SymbolBlock extends BaseBlock<SymbolBlockInput, Observable<Symb
     type: 'symbol'
 }
 
-export type Block = QueryBlock | MarkdownBlock | FileBlock | SymbolBlock
+export interface ComputeBlockInput {
+    text: string
+    // The rendered output of the last run, stored with the notebook so that it can
+    // be displayed without running the expression again.
+    output: string | null
+    initialFocusInput?: boolean
+}
+
+export interface ComputeBlock extends BaseBlock<ComputeBlockInput, Observable<string | Error>> {
+    type: 'compute'
+}
+
+export interface InsightBlockInput {
+    seriesId: string
+}
+
+export interface InsightSeriesPoint {
+    dateTime: string
+    value: number
+}
+
+export interface InsightBlockOutput {
+    insightTitle: string
+    seriesLabel: string
+    points: InsightSeriesPoint[]
+}
+
+export interface InsightBlock extends BaseBlock<InsightBlockInput, Observable<InsightBlockOutput | Error>> {
+    type: 'insight'
+}
+
+export interface ReferencesBlockInput {
+    repositoryName: string
+    filePath: string
+    commit: string
+    line: number
+    character: number
+    symbolName: string
+}
+
+export interface ReferenceLocation {
+    repositoryName: string
+    filePath: string
+    commit: string
+    range: UIRangeSpec['range']
+    url: string
+}
+
+export interface ReferencesBlock extends BaseBlock<ReferencesBlockInput, Observable<ReferenceLocation[] | Error>> {
+    type: 'references'
+}
+
+export type Block = QueryBlock | MarkdownBlock | FileBlock | SymbolBlock | ComputeBlock | InsightBlock | ReferencesBlock
 
 export type BlockInput =
     | Pick<FileBlock, 'type' | 'input'>
     | Pick<MarkdownBlock, 'type' | 'input'>
     | Pick<QueryBlock, 'type' | 'input'>
     | Pick<SymbolBlock, 'type' | 'input'>
+    | Pick<ComputeBlock, 'type' | 'input'>
+    | Pick<InsightBlock, 'type' | 'input'>
+    | Pick<ReferencesBlock, 'type' | 'input'>
 
 export type BlockInit =
     | Omit<FileBlock, 'output'>
     | Omit<MarkdownBlock, 'output'>
     | Omit<QueryBlock, 'output'>
     | Omit<SymbolBlock, 'output'>
+    | Omit<ComputeBlock, 'output'>
+    | Omit<InsightBlock, 'output'>
+    | Omit<ReferencesBlock, 'output'>
 
 export type SerializableBlock =
     | Pick<FileBlock, 'type' | 'input'>
     | Pick<MarkdownBlock, 'type' | 'input'>
     | Pick<QueryBlock, 'type' | 'input'>
     | Pick<SymbolBlock, 'type' | 'input' | 'output'>
+    | Pick<ComputeBlock, 'type' | 'input'>
+    | Pick<InsightBlock, 'type' | 'input'>
+    | Pick<ReferencesBlock, 'type' | 'input'>
 
 export type BlockDirection = 'up' | 'down'
 
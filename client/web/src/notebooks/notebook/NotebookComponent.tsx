@@ -19,9 +19,12 @@ import type { NotebookFields } from '../../graphql-operations'
 import type { OwnConfigProps } from '../../own/OwnConfigProps'
 import { PageRoutes } from '../../routes.constants'
 import type { SearchStreamingProps } from '../../search'
+import { NotebookComputeBlock } from '../blocks/compute/NotebookComputeBlock'
 import { NotebookFileBlock } from '../blocks/file/NotebookFileBlock'
+import { NotebookInsightBlock } from '../blocks/insight/NotebookInsightBlock'
 import { NotebookMarkdownBlock } from '../blocks/markdown/NotebookMarkdownBlock'
 import { NotebookQueryBlock } from '../blocks/query/NotebookQueryBlock'
+import { NotebookReferencesBlock } from '../blocks/references/NotebookReferencesBlock'
 import { NotebookSymbolBlock } from '../blocks/symbol/NotebookSymbolBlock'
 
 import { Notebook, type CopyNotebookProps } from '.'
@@ -58,6 +61,8 @@ function countBlockTypes(blocks: Block[]): BlockCounts {
         query: 0,
         compute: 0,
         symbol: 0,
+        insight: 0,
+        references: 0,
     })
 }
 
@@ -421,6 +426,15 @@ export const NotebookComponent: React.FunctionComponent<React.PropsWithChildren<
                             />
                         )
                     }
+                    case 'compute': {
+                        return <NotebookComputeBlock {...block} {...blockProps} />
+                    }
+                    case 'insight': {
+                        return <NotebookInsightBlock {...block} {...blockProps} />
+                    }
+                    case 'references': {
+                        return <NotebookReferencesBlock {...block} {...blockProps} />
+                    }
                 }
             },
             [
@@ -4,7 +4,7 @@ import { escapeRegExp } from 'lodash'
 // eslint-disable-next-line no-restricted-imports
 import { type marked, Renderer } from 'marked'
 import { type Observable, forkJoin, of } from 'rxjs'
-import { startWith, catchError, mapTo, map, switchMap } from 'rxjs/operators'
+import { startWith, catchError, last, mapTo, map, switchMap } from 'rxjs/operators'
 import * as uuid from 'uuid'
 
 import { renderMarkdown, asError, isErrorLike } from '@sourcegraph/common'
@@ -12,6 +12,7 @@ import {
     aggregateStreamingSearch,
     emptyAggregateResults,
     LATEST_VERSION,
+    streamComputeQuery,
     type SymbolMatch,
 } from '@sourcegraph/shared/src/search/stream'
 import type { UIRangeSpec } from '@sourcegraph/shared/src/util/url'
@@ -19,7 +20,7 @@ import type { UIRangeSpec } from '@sourcegraph/shared/src/util/url'
 import type { Block, BlockInit, BlockDependencies, BlockInput, BlockDirection, SymbolBlockInput } from '..'
 import { type NotebookFields, SearchPatternType } from '../../graphql-operations'
 import { parseBrowserRepoURL } from '../../util/url'
-import { createNotebook } from '../backend'
+import { createNotebook, fetchNotebookInsightSeries, fetchNotebookReferences } from '../backend'
 import { fetchSuggestions } from '../blocks/suggestions/suggestions'
 import { blockToGQLInput, serializeBlockToMarkdown } from '../serialize'
 
@@ -77,6 +78,26 @@ function findSymbolAtRevision(
     )
 }
 
+interface ComputeResult {
+    kind: string
+    value?: string
+    matches?: { value: string }[]
+}
+
+// Renders the compute stream events as plain text. Text results (e.g. from
+// content:output) already contain their separators, matches are rendered one per line.
+export function renderComputeOutput(events: string[]): string {
+    return events
+        .flatMap(event => JSON.parse(event) as ComputeResult | ComputeResult[])
+        .flatMap(result => {
+            if (typeof result.value === 'string') {
+                return [result.value]
+            }
+            return result.matches?.map(match => `${match.value}\n`) ?? []
+        })
+        .join('')
+}
+
 export class NotebookHeadingMarkdownRenderer extends Renderer {
     public heading(
         this: marked.Renderer<never>,
@@ -107,7 +128,13 @@ export class Notebook {
 
         // Pre-run certain blocks, for a better user experience.
         for (const block of blocks) {
-            if (block.type === 'md' || block.type === 'file' || block.type === 'symbol') {
+            if (
+                block.type === 'md' ||
+                block.type === 'file' ||
+                block.type === 'symbol' ||
+                block.type === 'insight' ||
+                block.type === 'references'
+            ) {
                 this.runBlockById(block.id)
             }
         }
@@ -246,6 +273,37 @@ export class Notebook {
                 this.blocks.set(block.id, { ...block, output })
                 break
             }
+            case 'compute': {
+                this.blocks.set(block.id, {
+                    ...block,
+                    output: streamComputeQuery(block.input.text).pipe(
+                        // Only the complete output is stored with the block.
+                        last(undefined, []),
+                        map(renderComputeOutput),
+                        catchError(error => [asError(error)])
+                    ),
+                })
+                break
+            }
+            case 'insight': {
+                this.blocks.set(block.id, {
+                    ...block,
+                    output: fetchNotebookInsightSeries(block.input.seriesId).pipe(
+                        catchError(error => [asError(error)])
+                    ),
+                })
+                break
+            }
+            case 'references': {
+                const { repositoryName, commit, filePath, line, character } = block.input
+                this.blocks.set(block.id, {
+                    ...block,
+                    output: fetchNotebookReferences({ repositoryName, commit, filePath, line, character }).pipe(
+                        catchError(error => [asError(error)])
+                    ),
+                })
+                break
+            }
         }
     }
 
@@ -268,6 +326,12 @@ export class Notebook {
                 observables.push(block.output.pipe(mapTo(DONE)))
             } else if (block.type === 'symbol') {
                 observables.push(block.output.pipe(mapTo(DONE)))
+            } else if (block.type === 'compute') {
+                observables.push(block.output.pipe(mapTo(DONE)))
+            } else if (block.type === 'insight') {
+                observables.push(block.output.pipe(mapTo(DONE)))
+            } else if (block.type === 'references') {
+                observables.push(block.output.pipe(mapTo(DONE)))
             }
         }
         // We store output observables and join them into a single observable,
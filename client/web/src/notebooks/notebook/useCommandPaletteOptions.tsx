@@ -1,6 +1,14 @@
 import { type ReactElement, useMemo } from 'react'
 
-import { mdiLanguageMarkdownOutline, mdiMagnify, mdiCodeTags, mdiFunction } from '@mdi/js'
+import {
+    mdiLanguageMarkdownOutline,
+    mdiMagnify,
+    mdiCodeTags,
+    mdiFunction,
+    mdiCalculatorVariantOutline,
+    mdiChartLine,
+    mdiVectorLink,
+} from '@mdi/js'
 
 import { Icon } from '@sourcegraph/wildcard'
 
@@ -25,6 +33,14 @@ export const EMPTY_SYMBOL_BLOCK_INPUT = {
     symbolKind: SymbolKind.UNKNOWN,
     lineContext: 3,
 }
+export const EMPTY_REFERENCES_BLOCK_INPUT = {
+    repositoryName: '',
+    filePath: '',
+    commit: '',
+    line: 0,
+    character: 0,
+    symbolName: '',
+}
 
 interface UseCommandPaletteOptionsProps {
     input: string
@@ -61,6 +77,25 @@ export const useCommandPaletteOptions = ({ input, addBlock }: UseCommandPaletteO
                     icon: <Icon aria-hidden={true} size="md" svgPath={mdiFunction} />,
                     onSelect: () => addBlock({ type: 'symbol', input: EMPTY_SYMBOL_BLOCK_INPUT }),
                 },
+                {
+                    id: 'add-compute-block',
+                    label: 'Add a compute expression',
+                    icon: <Icon aria-hidden={true} size="md" svgPath={mdiCalculatorVariantOutline} />,
+                    onSelect: () =>
+                        addBlock({ type: 'compute', input: { text: '', output: null, initialFocusInput: true } }),
+                },
+                {
+                    id: 'add-insight-block',
+                    label: 'Add a Code Insights series',
+                    icon: <Icon aria-hidden={true} size="md" svgPath={mdiChartLine} />,
+                    onSelect: () => addBlock({ type: 'insight', input: { seriesId: '' } }),
+                },
+                {
+                    id: 'add-references-block',
+                    label: 'Add precise references',
+                    icon: <Icon aria-hidden={true} size="md" svgPath={mdiVectorLink} />,
+                    onSelect: () => addBlock({ type: 'references', input: EMPTY_REFERENCES_BLOCK_INPUT }),
+                },
             ].filter(option => option.label.toLowerCase().includes(inputQuery))
         }
 
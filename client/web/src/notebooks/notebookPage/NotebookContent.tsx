@@ -74,6 +74,19 @@ export const NotebookContent: React.FunctionComponent<React.PropsWithChildren<No
                                 input: { ...block.symbolInput, revision: block.symbolInput.revision ?? '' },
                             }
                         }
+                        case 'ComputeBlock': {
+                            return {
+                                id: block.id,
+                                type: 'compute',
+                                input: { text: block.computeInput.text, output: block.computeInput.output },
+                            }
+                        }
+                        case 'InsightBlock': {
+                            return { id: block.id, type: 'insight', input: { seriesId: block.insightSeriesID } }
+                        }
+                        case 'ReferencesBlock': {
+                            return { id: block.id, type: 'references', input: block.referencesInput }
+                        }
                     }
                 }),
             [blocks]
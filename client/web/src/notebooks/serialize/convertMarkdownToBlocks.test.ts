@@ -37,6 +37,34 @@ Paragraph`
         ])
     })
 
+    it('should handle compute, insight, and references blocks', () => {
+        const markdown = `\`\`\`sourcegraph-compute
+content:output(.* -> $author) type:commit
+\`\`\`
+
+\`\`\`sourcegraph-insight
+series-1
+\`\`\`
+
+https://sourcegraph.com/github.com/sourcegraph/sourcegraph@a9505a2947d3df53558e8c88ff8bcef390fc4e3e/-/blob/client/web/index.ts?L11:6#tab=references&symbolName=a`
+
+        expect(convertMarkdownToBlocks(markdown)).toStrictEqual([
+            { type: 'compute', input: { text: 'content:output(.* -> $author) type:commit', output: null } },
+            { type: 'insight', input: { seriesId: 'series-1' } },
+            {
+                type: 'references',
+                input: {
+                    repositoryName: 'github.com/sourcegraph/sourcegraph',
+                    filePath: 'client/web/index.ts',
+                    commit: 'a9505a2947d3df53558e8c88ff8bcef390fc4e3e',
+                    line: 10,
+                    character: 5,
+                    symbolName: 'a',
+                },
+            },
+        ])
+    })
+
     it('should handle interleaved markdown, query, and file blocks', () => {
         const markdown = `# Title
 
@@ -3,7 +3,7 @@ import { markdownLexer } from '@sourcegraph/common'
 import type { BlockInput } from '..'
 import { parseBrowserRepoURL } from '../../util/url'
 
-import { deserializeBlockInput } from '.'
+import { deserializeBlockInput, isReferencesBlockURL } from '.'
 
 function isSourcegraphFileBlobURL(url: string): boolean {
     return !!parseBrowserRepoURL(url).filePath
@@ -35,6 +35,12 @@ export function convertMarkdownToBlocks(markdown: string): BlockInput[] {
         if (token.type === 'code' && token.lang === 'sourcegraph') {
             addMarkdownBlock()
             blocks.push(deserializeBlockInput('query', token.text))
+        } else if (token.type === 'code' && token.lang === 'sourcegraph-compute') {
+            addMarkdownBlock()
+            blocks.push(deserializeBlockInput('compute', token.text))
+        } else if (token.type === 'code' && token.lang === 'sourcegraph-insight') {
+            addMarkdownBlock()
+            blocks.push(deserializeBlockInput('insight', token.text))
         } else if (
             token.type === 'paragraph' &&
             token.tokens.length === 1 &&
@@ -42,7 +48,11 @@ export function convertMarkdownToBlocks(markdown: string): BlockInput[] {
             isSourcegraphFileBlobURL(token.tokens[0].href)
         ) {
             addMarkdownBlock()
-            const blockType = isSymbolBlockURL(token.text) ? 'symbol' : 'file'
+            const blockType = isReferencesBlockURL(token.text)
+                ? 'references'
+                : isSymbolBlockURL(token.text)
+                ? 'symbol'
+                : 'file'
             blocks.push(deserializeBlockInput(blockType, token.text))
         } else {
             markdownRawTokens.push(token.raw)
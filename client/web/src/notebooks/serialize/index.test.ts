@@ -3,7 +3,7 @@ import { describe, expect, it } from 'vitest'
 
 import { SymbolKind } from '../../graphql-operations'
 
-import { parseLineRange, serializeBlockInput, serializeLineRange } from '.'
+import { parseLineRange, serializeBlockInput, serializeBlockToMarkdown, serializeLineRange } from '.'
 
 const SOURCEGRAPH_URL = 'https://sourcegraph.com'
 
@@ -111,6 +111,43 @@ describe('serialize', () => {
         )
     })
 
+    it('should serialize a compute block', async () => {
+        const serialized = await serializeBlockToMarkdown(
+            { type: 'compute', input: { text: 'content:output(.* -> $author) type:commit', output: 'a' } },
+            SOURCEGRAPH_URL
+        ).toPromise()
+        expect(serialized).toStrictEqual('```sourcegraph-compute\ncontent:output(.* -> $author) type:commit\n```')
+    })
+
+    it('should serialize an insight block', async () => {
+        const serialized = await serializeBlockToMarkdown(
+            { type: 'insight', input: { seriesId: 'series-1' } },
+            SOURCEGRAPH_URL
+        ).toPromise()
+        expect(serialized).toStrictEqual('```sourcegraph-insight\nseries-1\n```')
+    })
+
+    it('should serialize a references block', async () => {
+        const serialized = await serializeBlockInput(
+            {
+                type: 'references',
+                input: {
+                    repositoryName: 'github.com/sourcegraph/sourcegraph',
+                    filePath: 'client/web/index.ts',
+                    commit: 'a9505a2947d3df53558e8c88ff8bcef390fc4e3e',
+                    line: 10,
+                    character: 5,
+                    symbolName: 'a',
+                },
+            },
+            SOURCEGRAPH_URL
+        ).toPromise()
+
+        expect(serialized).toStrictEqual(
+            `${SOURCEGRAPH_URL}/github.com/sourcegraph/sourcegraph@a9505a2947d3df53558e8c88ff8bcef390fc4e3e/-/blob/client/web/index.ts?L11:6#tab=references&symbolName=a`
+        )
+    })
+
     it('should serialize single line range', () =>
         expect(serializeLineRange({ startLine: 123, endLine: 124 })).toStrictEqual('124'))
 
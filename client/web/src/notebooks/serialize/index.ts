@@ -4,7 +4,15 @@ import { map } from 'rxjs/operators'
 import { isErrorLike } from '@sourcegraph/common'
 import { toAbsoluteBlobURL } from '@sourcegraph/shared/src/util/url'
 
-import type { Block, BlockInit, BlockInput, FileBlockInput, SerializableBlock, SymbolBlockInput } from '..'
+import type {
+    Block,
+    BlockInit,
+    BlockInput,
+    FileBlockInput,
+    ReferencesBlockInput,
+    SerializableBlock,
+    SymbolBlockInput,
+} from '..'
 import {
     type CreateNotebookBlockInput,
     NotebookBlockType,
@@ -23,8 +31,15 @@ export function serializeBlockToMarkdown(block: SerializableBlock, sourcegraphUR
         case 'query': {
             return serializedInput.pipe(map(input => `\`\`\`sourcegraph\n${input}\n\`\`\``))
         }
+        case 'compute': {
+            return serializedInput.pipe(map(input => `\`\`\`sourcegraph-compute\n${input}\n\`\`\``))
+        }
+        case 'insight': {
+            return serializedInput.pipe(map(input => `\`\`\`sourcegraph-insight\n${input}\n\`\`\``))
+        }
         case 'file':
-        case 'symbol': {
+        case 'symbol':
+        case 'references': {
             return serializedInput
         }
     }
@@ -78,6 +93,25 @@ export function serializeBlockInput(block: SerializableBlock, sourcegraphURL: st
                 })
             )
         }
+        case 'compute': {
+            return of(block.input.text)
+        }
+        case 'insight': {
+            return of(block.input.seriesId)
+        }
+        case 'references': {
+            const blobURL = toAbsoluteBlobURL(sourcegraphURL, {
+                repoName: block.input.repositoryName,
+                revision: block.input.commit,
+                filePath: block.input.filePath,
+                position: { line: block.input.line + 1, character: block.input.character + 1 },
+            })
+            const referencesParameters = new URLSearchParams([
+                ['tab', 'references'],
+                ['symbolName', block.input.symbolName],
+            ])
+            return of(blobURL + '#' + referencesParameters.toString())
+        }
     }
 }
 
@@ -127,6 +161,33 @@ function parseSymbolBlockInput(input: string): SymbolBlockInput {
     }
 }
 
+export function isReferencesBlockURL(input: string): boolean {
+    try {
+        const url = new URL(input)
+        return new URLSearchParams(url.hash.slice(1)).get('tab') === 'references'
+    } catch {
+        return false
+    }
+}
+
+export function parseReferencesBlockInput(input: string): ReferencesBlockInput {
+    try {
+        const { repoName, rawRevision, filePath, position } = parseBrowserRepoURL(input)
+        const url = new URL(input)
+        const referencesParameters = new URLSearchParams(url.hash.slice(1))
+        return {
+            repositoryName: repoName,
+            filePath: filePath ?? '',
+            commit: rawRevision ?? '',
+            line: position ? position.line - 1 : 0,
+            character: position ? position.character - 1 : 0,
+            symbolName: referencesParameters.get('symbolName') ?? '',
+        }
+    } catch {
+        return { repositoryName: '', filePath: '', commit: '', line: 0, character: 0, symbolName: '' }
+    }
+}
+
 export function deserializeBlockInput(type: Block['type'], input: string): BlockInput {
     switch (type) {
         case 'md': {
@@ -141,6 +202,15 @@ export function deserializeBlockInput(type: Block['type'], input: string): Block
         case 'symbol': {
             return { type, input: parseSymbolBlockInput(input) }
         }
+        case 'compute': {
+            return { type, input: { text: input, output: null } }
+        }
+        case 'insight': {
+            return { type, input: { seriesId: input.trim() } }
+        }
+        case 'references': {
+            return { type, input: parseReferencesBlockInput(input) }
+        }
     }
 }
 
@@ -184,6 +254,19 @@ export function blockToGQLInput(block: BlockInit): CreateNotebookBlockInput {
         case 'symbol': {
             return { id: block.id, type: NotebookBlockType.SYMBOL, symbolInput: block.input }
         }
+        case 'compute': {
+            return {
+                id: block.id,
+                type: NotebookBlockType.COMPUTE,
+                computeInput: { text: block.input.text, output: block.input.output },
+            }
+        }
+        case 'insight': {
+            return { id: block.id, type: NotebookBlockType.INSIGHT, insightSeriesID: block.input.seriesId }
+        }
+        case 'references': {
+            return { id: block.id, type: NotebookBlockType.REFERENCES, referencesInput: block.input }
+        }
     }
 }
 
@@ -209,6 +292,23 @@ export function GQLBlockToGQLInput(block: NotebookFields['blocks'][number]): Cre
                 symbolInput: block.symbolInput,
             }
         }
+        case 'ComputeBlock': {
+            return {
+                id: block.id,
+                type: NotebookBlockType.COMPUTE,
+                computeInput: { text: block.computeInput.text, output: block.computeInput.output },
+            }
+        }
+        case 'InsightBlock': {
+            return { id: block.id, type: NotebookBlockType.INSIGHT, insightSeriesID: block.insightSeriesID }
+        }
+        case 'ReferencesBlock': {
+            return {
+                id: block.id,
+                type: NotebookBlockType.REFERENCES,
+                referencesInput: block.referencesInput,
+            }
+        }
     }
 }
 
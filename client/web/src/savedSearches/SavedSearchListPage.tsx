@@ -13,6 +13,8 @@ import type { SearchPatternTypeProps } from '@sourcegraph/shared/src/search'
 import { buildSearchURLQuery } from '@sourcegraph/shared/src/util/url'
 import {
     Container,
+    Form,
+    Input,
     PageHeader,
     LoadingSpinner,
     Button,
@@ -78,18 +80,22 @@ class SavedSearchNode extends React.PureComponent<NodeProps, NodeState> {
             <div className={classNames(styles.row, 'list-group-item test-saved-search-list-page-row')}>
                 <div className="d-flex">
                     <Icon className={styles.rowIcon} aria-hidden={true} svgPath={mdiMessageTextOutline} />
-                    <Link
-                        to={
-                            '/search?' +
-                            buildSearchURLQuery(this.props.savedSearch.query, this.props.patternType, false)
-                        }
-                        ref={this.props.linkRef}
-                    >
-                        <div className="test-saved-search-list-page-row-title">
-                            <VisuallyHidden>Run saved search: </VisuallyHidden>
-                            {this.props.savedSearch.description}
-                        </div>
-                    </Link>
+                    {this.props.savedSearch.variables.length > 0 ? (
+                        <SavedSearchVariablesForm savedSearch={this.props.savedSearch} />
+                    ) : (
+                        <Link
+                            to={
+                                '/search?' +
+                                buildSearchURLQuery(this.props.savedSearch.query, this.props.patternType, false)
+                            }
+                            ref={this.props.linkRef}
+                        >
+                            <div className="test-saved-search-list-page-row-title">
+                                <VisuallyHidden>Run saved search: </VisuallyHidden>
+                                {this.props.savedSearch.description}
+                            </div>
+                        </Link>
+                    )}
                 </div>
                 <div>
                     <Tooltip content="Saved search settings">
@@ -132,6 +138,36 @@ class SavedSearchNode extends React.PureComponent<NodeProps, NodeState> {
     }
 }
 
+/**
+ * Runs a saved search with variables. The values are sent as URL parameters to
+ * the backend, which fills them into the query and redirects to the search page.
+ */
+const SavedSearchVariablesForm: React.FunctionComponent<{ savedSearch: SavedSearchFields }> = ({ savedSearch }) => (
+    <Form
+        method="get"
+        action={`/search/saved/${encodeURIComponent(savedSearch.id)}`}
+        className="test-saved-search-list-page-row-title"
+    >
+        <div className="mb-2">{savedSearch.description}</div>
+        <div className="d-flex align-items-end flex-wrap">
+            {savedSearch.variables.map(variable => (
+                <Input
+                    key={variable.name}
+                    name={variable.name}
+                    required={true}
+                    label={`$${variable.name}`}
+                    placeholder={variable.type.toLowerCase()}
+                    className={classNames('mr-2 mb-0', styles.variable)}
+                />
+            ))}
+            <Button type="submit" variant="secondary" size="sm">
+                <VisuallyHidden>Run saved search: {savedSearch.description}</VisuallyHidden>
+                <span aria-hidden={true}>Run</span>
+            </Button>
+        </div>
+    </Form>
+)
+
 interface Props extends NamespaceProps {}
 
 export const SavedSearchListPage: React.FunctionComponent<Props> = props => {
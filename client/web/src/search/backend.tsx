@@ -58,6 +58,10 @@ const savedSearchFragment = gql`
             namespaceName
         }
         slackWebhookURL
+        variables {
+            name
+            type
+        }
     }
 `
 
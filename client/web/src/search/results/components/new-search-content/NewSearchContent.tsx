@@ -268,7 +268,11 @@ export const NewSearchContent: FC<NewSearchContentProps> = props => {
                         />
 
                         {results?.alert?.kind && isSmartSearchAlert(results.alert.kind) && (
-                            <SmartSearch alert={results?.alert} onDisableSmartSearch={onDisableSmartSearch} />
+                            <SmartSearch
+                                alert={results?.alert}
+                                results={results?.results}
+                                onDisableSmartSearch={onDisableSmartSearch}
+                            />
                         )}
 
                         <GettingStartedTour.Info
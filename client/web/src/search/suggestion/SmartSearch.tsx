@@ -4,10 +4,15 @@ import { mdiChevronDown, mdiChevronUp, mdiArrowRight } from '@mdi/js'
 
 import { smartSearchIconSvgPath, SyntaxHighlightedSearchQuery } from '@sourcegraph/branded'
 import { pluralize, formatSearchParameters } from '@sourcegraph/common'
-import type {
-    AggregateStreamingSearchResults,
-    AlertKind,
-    SmartSearchAlertKind,
+import {
+    getFileMatchUrl,
+    type AggregateStreamingSearchResults,
+    type AlertKind,
+    type ContentMatch,
+    type PathMatch,
+    type ProposedQuery,
+    type SearchMatch,
+    type SmartSearchAlertKind,
 } from '@sourcegraph/shared/src/search/stream'
 import { useTemporarySetting } from '@sourcegraph/shared/src/settings/temporary/useTemporarySetting'
 import {
@@ -28,6 +33,7 @@ import styles from './QuerySuggestion.module.scss'
 
 interface SmartSearchProps {
     alert: Required<AggregateStreamingSearchResults>['alert'] | undefined
+    results?: SearchMatch[]
     onDisableSmartSearch: () => void
 }
 
@@ -64,6 +70,7 @@ const alertContent: {
 
 export const SmartSearch: React.FunctionComponent<React.PropsWithChildren<SmartSearchProps>> = ({
     alert,
+    results,
     onDisableSmartSearch,
 }) => {
     const [isCollapsed, setIsCollapsed] = useTemporarySetting('search.results.collapseSmartSearch')
@@ -128,10 +135,13 @@ export const SmartSearch: React.FunctionComponent<React.PropsWithChildren<SmartS
                         {alert?.proposedQueries?.map(entry => (
                             <li key={entry.query} className={styles.listItem}>
                                 <Link
-                                    to={createLinkUrl({
-                                        pathname: '/search',
-                                        search: formatSearchParameters(new URLSearchParams({ q: entry.query })),
-                                    })}
+                                    to={
+                                        lineUrl(alert, entry, results) ??
+                                        createLinkUrl({
+                                            pathname: '/search',
+                                            search: formatSearchParameters(new URLSearchParams({ q: entry.query })),
+                                        })
+                                    }
                                     className={styles.link}
                                 >
                                     <Text className="mb-0">
@@ -164,6 +174,30 @@ export const SmartSearch: React.FunctionComponent<React.PropsWithChildren<SmartS
     )
 }
 
+/**
+ * Returns the URL of the line that a proposed query is annotated with, like the
+ * line of a pasted stack trace frame, if the query found a single file.
+ */
+const lineUrl = (
+    alert: NonNullable<SmartSearchProps['alert']>,
+    entry: ProposedQuery,
+    results: SearchMatch[] | undefined
+): string | undefined => {
+    const line = entry.annotations?.find(({ name }) => name === 'Line')?.value
+    // Only the results of a pure Smart Search with a single proposed query
+    // are known to come from that query.
+    if (!line || alert.kind !== 'smart-search-pure-results' || alert.proposedQueries?.length !== 1) {
+        return undefined
+    }
+    const files = results?.filter(
+        (match): match is ContentMatch | PathMatch => match.type === 'content' || match.type === 'path'
+    )
+    if (files?.length !== 1) {
+        return undefined
+    }
+    return `${getFileMatchUrl(files[0])}?L${line}`
+}
+
 const processDescription = (description: string): string => {
     const split = description.split(' ⚬ ')
 
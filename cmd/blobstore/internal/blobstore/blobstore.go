@@ -19,6 +19,7 @@ import (
 
 	"github.com/sourcegraph/log"
 
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -29,10 +30,15 @@ type Service struct {
 	Log            log.Logger
 	ObservationCtx *observation.Context
 
+	// EncryptionKey, if set, is used to encrypt objects at rest. Objects written without it remain
+	// readable after it has been set.
+	EncryptionKey encryption.Key
+
 	initOnce              sync.Once
 	bucketLocksMu         sync.Mutex
 	bucketLocks           map[string]*sync.RWMutex
 	mutatePendingUploadMu sync.Mutex
+	versionsMu            sync.Mutex
 	MockObjectAge         map[string]time.Time
 }
 
@@ -93,6 +99,10 @@ func (s *Service) createBucket(ctx context.Context, name string) error {
 type objectMetadata struct {
 	LastModified time.Time
 	Name         string
+
+	// VersionID is the version ID of the object in buckets with versioning enabled, and empty
+	// otherwise.
+	VersionID string
 }
 
 func (s *Service) putObject(ctx context.Context, bucketName, objectName string, data io.ReadCloser) (*objectMetadata, error) {
@@ -109,6 +119,10 @@ func (s *Service) putObject(ctx context.Context, bucketName, objectName string,
 	if _, err := os.Stat(bucketDir); err != nil {
 		return nil, ErrNoSuchBucket
 	}
+	config, err := s.readBucketConfig(bucketName)
+	if err != nil {
+		return nil, err
+	}
 
 	// Write the object, relying on an atomic filesystem rename operation to prevent any parallel
 	// read/write issues.
@@ -123,9 +137,20 @@ func (s *Service) putObject(ctx context.Context, bucketName, objectName string,
 		tmpFile.Close()
 		os.Remove(tmpFile.Name())
 	}()
-	if _, err := io.Copy(tmpFile, data); err != nil {
+	var dst io.WriteCloser = tmpFile
+	if s.EncryptionKey != nil {
+		if dst, err = newEncryptingWriter(ctx, s.EncryptionKey, tmpFile); err != nil {
+			return nil, errors.Wrap(err, "encrypting object")
+		}
+	}
+	if _, err := io.Copy(dst, data); err != nil {
 		return nil, errors.Wrap(err, "copying data into tmp file")
 	}
+	if dst != tmpFile {
+		if err := dst.Close(); err != nil {
+			return nil, errors.Wrap(err, "encrypting object")
+		}
+	}
 	// Ensure file bytes are on disk before renaming
 	// see https://github.com/sourcegraph/sourcegraph/pull/46972#discussion_r1088293666
 	if err := tmpFile.Sync(); err != nil {
@@ -133,6 +158,21 @@ func (s *Service) putObject(ctx context.Context, bucketName, objectName string,
 	}
 	objectFile := s.objectFilePath(bucketName, objectName)
 	tmpFile.Close()
+
+	// In buckets with versioning enabled, the new object is linked as a version before it becomes
+	// the current object. This must not interleave with other version changes of the bucket.
+	var versionID string
+	if config.versioningEnabled() {
+		s.versionsMu.Lock()
+		defer s.versionsMu.Unlock()
+
+		if err := s.preserveNullVersion(bucketName, objectName); err != nil {
+			return nil, err
+		}
+		if versionID, err = s.linkNewVersion(bucketName, objectName, tmpFile.Name()); err != nil {
+			return nil, err
+		}
+	}
 	if err := os.Rename(tmpFile.Name(), objectFile); err != nil {
 		return nil, errors.Wrap(err, "renaming object file")
 	}
@@ -150,12 +190,11 @@ func (s *Service) putObject(ctx context.Context, bucketName, objectName string,
 	return &objectMetadata{
 		LastModified: age,
 		Name:         objectName,
+		VersionID:    versionID,
 	}, nil
 }
 
-func (s *Service) getObject(ctx context.Context, bucketName, objectName string) (io.ReadCloser, error) {
-	_ = ctx
-
+func (s *Service) getObject(ctx context.Context, bucketName, objectName string) (*objectReader, error) {
 	// Ensure the bucket cannot be created/deleted while we look at it.
 	bucketLock := s.bucketLock(bucketName)
 	bucketLock.RLock()
@@ -164,7 +203,7 @@ func (s *Service) getObject(ctx context.Context, bucketName, objectName string)
 	// Read the object
 	// Note that we return an io.ReadCloser here, so f.Close is intentionally NOT called.
 	objectFile := s.objectFilePath(bucketName, objectName)
-	f, err := os.Open(objectFile)
+	f, err := s.openObjectFile(ctx, objectFile)
 	if err != nil {
 		s.Log.Debug("get object", sglog.String("key", bucketName+"/"+objectName), sglog.Error(err))
 		if os.IsNotExist(err) {
@@ -177,6 +216,14 @@ func (s *Service) getObject(ctx context.Context, bucketName, objectName string)
 }
 
 func (s *Service) deleteObject(ctx context.Context, bucketName, objectName string) error {
+	_, err := s.deleteCurrentObject(ctx, bucketName, objectName)
+	return err
+}
+
+// deleteCurrentObject deletes the current object. In buckets with versioning enabled or suspended,
+// a delete marker is recorded instead of deleting the versions of the object, and its version ID
+// is returned.
+func (s *Service) deleteCurrentObject(ctx context.Context, bucketName, objectName string) (deleteMarkerVersionID string, err error) {
 	_ = ctx
 
 	// Ensure the bucket cannot be created/deleted while we look at it.
@@ -184,16 +231,40 @@ func (s *Service) deleteObject(ctx context.Context, bucketName, objectName strin
 	bucketLock.RLock()
 	defer bucketLock.RUnlock()
 
-	// Delete the object
+	config, err := s.readBucketConfig(bucketName)
+	if err != nil {
+		return "", err
+	}
 	objectFile := s.objectFilePath(bucketName, objectName)
+	if _, err := os.Stat(objectFile); err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrNoSuchKey
+		}
+		return "", errors.Wrap(err, "Stat")
+	}
+
+	if config.Versioning != "" {
+		s.versionsMu.Lock()
+		defer s.versionsMu.Unlock()
+
+		// With versioning suspended, the null version is deleted instead of being retained.
+		deleteMarkerVersionID, err = s.putDeleteMarker(bucketName, objectName, config.versioningEnabled())
+		if err != nil {
+			return "", err
+		}
+		s.Log.Debug("delete object", sglog.String("key", bucketName+"/"+objectName), sglog.String("deleteMarker", deleteMarkerVersionID))
+		return deleteMarkerVersionID, nil
+	}
+
+	// Delete the object
 	if err := os.Remove(objectFile); err != nil {
 		if os.IsNotExist(err) {
-			return ErrNoSuchKey
+			return "", ErrNoSuchKey
 		}
-		return errors.Wrap(err, "Remove")
+		return "", errors.Wrap(err, "Remove")
 	}
 	s.Log.Debug("delete object", sglog.String("key", bucketName+"/"+objectName))
-	return nil
+	return "", nil
 }
 
 func (s *Service) listObjects(_ context.Context, bucketName string, prefix string) ([]objectMetadata, error) {
@@ -5,15 +5,22 @@ import (
 	"fmt"
 	"io"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/hexops/autogold/v2"
 	"github.com/sourcegraph/log/logtest"
 	"github.com/stretchr/testify/require"
 
 	"github.com/sourcegraph/sourcegraph/cmd/blobstore/internal/blobstore"
+	enctest "github.com/sourcegraph/sourcegraph/internal/encryption/testing"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
 )
@@ -311,3 +318,269 @@ func initTestStore(ctx context.Context, t *testing.T, dataDir string) (uploadsto
 	}
 	return store, ts, svc
 }
+
+// Enable versioning, overwrite and delete an object, and read and delete its versions
+func TestVersioning(t *testing.T) {
+	ctx := context.Background()
+	client, server, _ := initTestS3Client(t, t.TempDir())
+	defer server.Close()
+
+	createBucket(ctx, t, client, "versioned")
+	putObject(ctx, t, client, "versioned", "foo", "v0")
+
+	_, err := client.PutBucketVersioning(ctx, &s3.PutBucketVersioningInput{
+		Bucket:                  aws.String("versioned"),
+		VersioningConfiguration: &s3types.VersioningConfiguration{Status: s3types.BucketVersioningStatusEnabled},
+	})
+	require.NoError(t, err)
+	versioning, err := client.GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{Bucket: aws.String("versioned")})
+	require.NoError(t, err)
+	require.Equal(t, s3types.BucketVersioningStatusEnabled, versioning.Status)
+
+	v1 := putObject(ctx, t, client, "versioned", "foo", "v1")
+	v2 := putObject(ctx, t, client, "versioned", "foo", "v2")
+	require.NotEmpty(t, v1)
+	require.NotEqual(t, v1, v2)
+	require.Equal(t, "v2", getObject(ctx, t, client, "versioned", "foo", ""))
+	require.Equal(t, "v1", getObject(ctx, t, client, "versioned", "foo", v1))
+
+	deleted, err := client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: aws.String("versioned"), Key: aws.String("foo")})
+	require.NoError(t, err)
+	require.True(t, deleted.DeleteMarker)
+	_, err = client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String("versioned"), Key: aws.String("foo")})
+	require.ErrorContains(t, err, "NoSuchKey")
+
+	// The object written before versioning was enabled is retained as well.
+	versions, markers := listObjectVersions(ctx, t, client, "versioned")
+	autogold.Expect([]string{"v2", "v1", "v0"}).Equal(t, versionContents(ctx, t, client, "versioned", versions))
+	require.Len(t, markers, 1)
+	require.True(t, markers[0].IsLatest)
+	require.Equal(t, *deleted.VersionId, *markers[0].VersionId)
+
+	// Deleting the delete marker restores the object.
+	_, err = client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: aws.String("versioned"), Key: aws.String("foo"), VersionId: deleted.VersionId})
+	require.NoError(t, err)
+	require.Equal(t, "v2", getObject(ctx, t, client, "versioned", "foo", ""))
+
+	// Deleting the latest version makes the previous version current.
+	_, err = client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: aws.String("versioned"), Key: aws.String("foo"), VersionId: aws.String(v2)})
+	require.NoError(t, err)
+	require.Equal(t, "v1", getObject(ctx, t, client, "versioned", "foo", ""))
+
+	_, err = client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String("versioned"), Key: aws.String("foo"), VersionId: aws.String(v2)})
+	require.ErrorContains(t, err, "NoSuchVersion")
+}
+
+// Configure lifecycle rules and expire objects
+func TestLifecycle(t *testing.T) {
+	ctx := context.Background()
+	client, server, svc := initTestS3Client(t, t.TempDir())
+	defer server.Close()
+
+	createBucket(ctx, t, client, "lifecycle")
+	_, err := client.GetBucketLifecycleConfiguration(ctx, &s3.GetBucketLifecycleConfigurationInput{Bucket: aws.String("lifecycle")})
+	require.ErrorContains(t, err, "NoSuchLifecycleConfiguration")
+
+	_, err = client.PutBucketLifecycleConfiguration(ctx, &s3.PutBucketLifecycleConfigurationInput{
+		Bucket: aws.String("lifecycle"),
+		LifecycleConfiguration: &s3types.BucketLifecycleConfiguration{Rules: []s3types.LifecycleRule{{
+			ID:     aws.String("expire-tmp"),
+			Status: s3types.ExpirationStatusEnabled,
+			Filter: &s3types.LifecycleRuleFilterMemberPrefix{Value: "tmp-"},
+			Expiration: &s3types.LifecycleExpiration{
+				Days: 1,
+			},
+		}}},
+	})
+	require.NoError(t, err)
+
+	config, err := client.GetBucketLifecycleConfiguration(ctx, &s3.GetBucketLifecycleConfigurationInput{Bucket: aws.String("lifecycle")})
+	require.NoError(t, err)
+	require.Len(t, config.Rules, 1)
+	require.Equal(t, "expire-tmp", *config.Rules[0].ID)
+	require.Equal(t, &s3types.LifecycleRuleFilterMemberPrefix{Value: "tmp-"}, config.Rules[0].Filter)
+	require.Equal(t, int32(1), config.Rules[0].Expiration.Days)
+
+	putObject(ctx, t, client, "lifecycle", "tmp-old", "old")
+	putObject(ctx, t, client, "lifecycle", "tmp-new", "new")
+	putObject(ctx, t, client, "lifecycle", "keep-old", "old")
+	svc.MockObjectAge = map[string]time.Time{
+		"tmp-old":  time.Now().Add(-48 * time.Hour),
+		"keep-old": time.Now().Add(-48 * time.Hour),
+	}
+
+	require.NoError(t, svc.ApplyLifecycleRules(ctx, time.Now()))
+
+	objects, err := client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{Bucket: aws.String("lifecycle")})
+	require.NoError(t, err)
+	var keys []string
+	for _, obj := range objects.Contents {
+		keys = append(keys, *obj.Key)
+	}
+	autogold.Expect([]string{"keep-old", "tmp-new"}).Equal(t, keys)
+
+	// Rules that cannot be applied are rejected.
+	_, err = client.PutBucketLifecycleConfiguration(ctx, &s3.PutBucketLifecycleConfigurationInput{
+		Bucket: aws.String("lifecycle"),
+		LifecycleConfiguration: &s3types.BucketLifecycleConfiguration{Rules: []s3types.LifecycleRule{{
+			Status: s3types.ExpirationStatusEnabled,
+			Filter: &s3types.LifecycleRuleFilterMemberPrefix{Value: "tmp-"},
+			Transitions: []s3types.Transition{{
+				Days:         1,
+				StorageClass: s3types.TransitionStorageClassGlacier,
+			}},
+		}}},
+	})
+	require.ErrorContains(t, err, "NotImplemented")
+
+	_, err = client.DeleteBucketLifecycle(ctx, &s3.DeleteBucketLifecycleInput{Bucket: aws.String("lifecycle")})
+	require.NoError(t, err)
+	_, err = client.GetBucketLifecycleConfiguration(ctx, &s3.GetBucketLifecycleConfigurationInput{Bucket: aws.String("lifecycle")})
+	require.ErrorContains(t, err, "NoSuchLifecycleConfiguration")
+}
+
+// Expire noncurrent versions with lifecycle rules
+func TestLifecycle_NoncurrentVersions(t *testing.T) {
+	ctx := context.Background()
+	client, server, svc := initTestS3Client(t, t.TempDir())
+	defer server.Close()
+
+	createBucket(ctx, t, client, "lifecycle")
+	_, err := client.PutBucketVersioning(ctx, &s3.PutBucketVersioningInput{
+		Bucket:                  aws.String("lifecycle"),
+		VersioningConfiguration: &s3types.VersioningConfiguration{Status: s3types.BucketVersioningStatusEnabled},
+	})
+	require.NoError(t, err)
+	_, err = client.PutBucketLifecycleConfiguration(ctx, &s3.PutBucketLifecycleConfigurationInput{
+		Bucket: aws.String("lifecycle"),
+		LifecycleConfiguration: &s3types.BucketLifecycleConfiguration{Rules: []s3types.LifecycleRule{{
+			Status:                      s3types.ExpirationStatusEnabled,
+			Filter:                      &s3types.LifecycleRuleFilterMemberPrefix{Value: ""},
+			NoncurrentVersionExpiration: &s3types.NoncurrentVersionExpiration{NoncurrentDays: 1},
+		}}},
+	})
+	require.NoError(t, err)
+
+	putObject(ctx, t, client, "lifecycle", "foo", "v1")
+	putObject(ctx, t, client, "lifecycle", "foo", "v2")
+	putObject(ctx, t, client, "lifecycle", "bar", "v1")
+	_, err = client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: aws.String("lifecycle"), Key: aws.String("bar")})
+	require.NoError(t, err)
+
+	// Nothing has been noncurrent for a day yet.
+	require.NoError(t, svc.ApplyLifecycleRules(ctx, time.Now()))
+	versions, markers := listObjectVersions(ctx, t, client, "lifecycle")
+	require.Len(t, versions, 3)
+	require.Len(t, markers, 1)
+
+	// Noncurrent versions expire, and so does the delete marker left without versions.
+	require.NoError(t, svc.ApplyLifecycleRules(ctx, time.Now().Add(48*time.Hour)))
+	versions, markers = listObjectVersions(ctx, t, client, "lifecycle")
+	autogold.Expect([]string{"v2"}).Equal(t, versionContents(ctx, t, client, "lifecycle", versions))
+	require.Empty(t, markers)
+	require.Equal(t, "v2", getObject(ctx, t, client, "lifecycle", "foo", ""))
+}
+
+// Encrypt objects at rest, and read objects written before encryption was enabled
+func TestServerSideEncryption(t *testing.T) {
+	ctx := context.Background()
+	dataDir := t.TempDir()
+
+	client, server, svc := initTestS3Client(t, dataDir)
+	createBucket(ctx, t, client, "encrypted")
+	putObject(ctx, t, client, "encrypted", "plain", "written before encryption was enabled")
+	server.Close()
+
+	client, server, svc = initTestS3Client(t, dataDir)
+	defer server.Close()
+	svc.EncryptionKey = enctest.TestKey{}
+
+	content := strings.Repeat("Hello world! ", 20000)
+	put, err := client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String("encrypted"),
+		Key:    aws.String("secret"),
+		Body:   strings.NewReader(content),
+	})
+	require.NoError(t, err)
+	require.Equal(t, s3types.ServerSideEncryptionAes256, put.ServerSideEncryption)
+
+	raw, err := os.ReadFile(filepath.Join(dataDir, "buckets", "encrypted", "secret"))
+	require.NoError(t, err)
+	require.NotContains(t, string(raw), "Hello world!")
+
+	get, err := client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String("encrypted"), Key: aws.String("secret")})
+	require.NoError(t, err)
+	defer get.Body.Close()
+	require.Equal(t, s3types.ServerSideEncryptionAes256, get.ServerSideEncryption)
+	data, err := io.ReadAll(get.Body)
+	require.NoError(t, err)
+	require.Equal(t, content, string(data))
+
+	require.Equal(t, "written before encryption was enabled", getObject(ctx, t, client, "encrypted", "plain", ""))
+
+	// Encrypted objects cannot be read without the key.
+	svc.EncryptionKey = nil
+	_, err = client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String("encrypted"), Key: aws.String("secret")})
+	require.Error(t, err)
+}
+
+func initTestS3Client(t *testing.T, dataDir string) (*s3.Client, *httptest.Server, *blobstore.Service) {
+	svc := &blobstore.Service{
+		DataDir:        dataDir,
+		Log:            logtest.Scoped(t),
+		ObservationCtx: observation.TestContextTB(t),
+	}
+	ts := httptest.NewServer(svc)
+
+	client := s3.New(s3.Options{
+		Region:           "us-east-1",
+		Credentials:      credentials.NewStaticCredentialsProvider("", "", ""),
+		EndpointResolver: s3.EndpointResolverFromURL(ts.URL),
+		UsePathStyle:     true,
+		RetryMaxAttempts: 1,
+	})
+	return client, ts, svc
+}
+
+func createBucket(ctx context.Context, t *testing.T, client *s3.Client, bucket string) {
+	_, err := client.CreateBucket(ctx, &s3.CreateBucketInput{Bucket: aws.String(bucket)})
+	require.NoError(t, err)
+}
+
+func putObject(ctx context.Context, t *testing.T, client *s3.Client, bucket, key, content string) (versionID string) {
+	resp, err := client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Body:   strings.NewReader(content),
+	})
+	require.NoError(t, err)
+	return aws.ToString(resp.VersionId)
+}
+
+func getObject(ctx context.Context, t *testing.T, client *s3.Client, bucket, key, versionID string) string {
+	input := &s3.GetObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)}
+	if versionID != "" {
+		input.VersionId = aws.String(versionID)
+	}
+	resp, err := client.GetObject(ctx, input)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func listObjectVersions(ctx context.Context, t *testing.T, client *s3.Client, bucket string) ([]s3types.ObjectVersion, []s3types.DeleteMarkerEntry) {
+	resp, err := client.ListObjectVersions(ctx, &s3.ListObjectVersionsInput{Bucket: aws.String(bucket)})
+	require.NoError(t, err)
+	return resp.Versions, resp.DeleteMarkers
+}
+
+// versionContents returns the content of each of the given versions.
+func versionContents(ctx context.Context, t *testing.T, client *s3.Client, bucket string, versions []s3types.ObjectVersion) []string {
+	contents := make([]string, 0, len(versions))
+	for _, v := range versions {
+		contents = append(contents, getObject(ctx, t, client, bucket, *v.Key, *v.VersionId))
+	}
+	return contents
+}
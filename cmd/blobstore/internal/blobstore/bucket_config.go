@@ -0,0 +1,137 @@
+package blobstore
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// bucketConfig is the configuration of a bucket, as set through the S3 bucket configuration APIs.
+// It is stored as JSON next to the buckets directory.
+type bucketConfig struct {
+	// Versioning is the S3 versioning status, i.e. "Enabled", "Suspended", or empty if versioning
+	// has never been enabled.
+	Versioning string `json:",omitempty"`
+
+	// LifecycleRules are the expiration rules of the bucket.
+	LifecycleRules []lifecycleRule `json:",omitempty"`
+}
+
+const (
+	versioningEnabled   = "Enabled"
+	versioningSuspended = "Suspended"
+)
+
+func (c *bucketConfig) versioningEnabled() bool {
+	return c.Versioning == versioningEnabled
+}
+
+func (s *Service) bucketConfigPath(bucketName string) string {
+	return filepath.Join(s.DataDir, "bucket-config", bucketName+".json")
+}
+
+// getBucketConfig returns the configuration of the given bucket.
+func (s *Service) getBucketConfig(ctx context.Context, bucketName string) (*bucketConfig, error) {
+	_ = ctx
+
+	// Ensure the bucket cannot be created/deleted while we look at it.
+	bucketLock := s.bucketLock(bucketName)
+	bucketLock.RLock()
+	defer bucketLock.RUnlock()
+
+	if _, err := os.Stat(s.bucketDir(bucketName)); err != nil {
+		return nil, ErrNoSuchBucket
+	}
+	return s.readBucketConfig(bucketName)
+}
+
+// readBucketConfig returns the configuration of the given bucket. Buckets without stored
+// configuration have the zero configuration.
+//
+// The caller must hold the bucket lock.
+func (s *Service) readBucketConfig(bucketName string) (*bucketConfig, error) {
+	data, err := os.ReadFile(s.bucketConfigPath(bucketName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &bucketConfig{}, nil
+		}
+		return nil, errors.Wrap(err, "reading bucket config")
+	}
+
+	var config bucketConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, errors.Wrap(err, "decoding bucket config")
+	}
+	return &config, nil
+}
+
+// updateBucketConfig atomically mutates the configuration of the given bucket.
+func (s *Service) updateBucketConfig(bucketName string, mutate func(*bucketConfig)) error {
+	// Hold the bucket exclusively, so that no object operations observe a partially updated
+	// configuration.
+	bucketLock := s.bucketLock(bucketName)
+	bucketLock.Lock()
+	defer bucketLock.Unlock()
+
+	if _, err := os.Stat(s.bucketDir(bucketName)); err != nil {
+		return ErrNoSuchBucket
+	}
+
+	config, err := s.readBucketConfig(bucketName)
+	if err != nil {
+		return err
+	}
+	mutate(config)
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		return errors.Wrap(err, "encoding bucket config")
+	}
+
+	configPath := s.bucketConfigPath(bucketName)
+	if err := os.MkdirAll(filepath.Dir(configPath), os.ModePerm); err != nil {
+		return errors.Wrap(err, "MkdirAll")
+	}
+	tmpFile, err := os.CreateTemp(filepath.Dir(configPath), "*-"+bucketName+".tmp")
+	if err != nil {
+		return errors.Wrap(err, "creating tmp file")
+	}
+	defer func() {
+		tmpFile.Close()
+		os.Remove(tmpFile.Name())
+	}()
+	if _, err := tmpFile.Write(data); err != nil {
+		return errors.Wrap(err, "writing tmp file")
+	}
+	if err := tmpFile.Sync(); err != nil {
+		return errors.Wrap(err, "sync tmp file")
+	}
+	tmpFile.Close()
+	if err := os.Rename(tmpFile.Name(), configPath); err != nil {
+		return errors.Wrap(err, "renaming bucket config file")
+	}
+	return nil
+}
+
+// configuredBuckets returns the names of all buckets with stored configuration.
+func (s *Service) configuredBuckets() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(s.DataDir, "bucket-config"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "ReadDir")
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".json"); ok {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
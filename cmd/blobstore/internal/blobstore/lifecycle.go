@@ -0,0 +1,225 @@
+package blobstore
+
+import (
+	"context"
+	"time"
+
+	sglog "github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// lifecycleRule expires objects with a key prefix once they reach a certain age. It is the subset
+// of S3 lifecycle rules supported by blobstore.
+type lifecycleRule struct {
+	ID      string
+	Prefix  string
+	Enabled bool
+
+	// ExpirationDays is the age in days after which current objects are deleted. In buckets with
+	// versioning enabled, they become noncurrent versions instead. Zero means never.
+	ExpirationDays int
+
+	// NoncurrentDays is the number of days after which noncurrent versions are deleted, counted
+	// from when they became noncurrent. Zero means never.
+	NoncurrentDays int
+}
+
+const day = 24 * time.Hour
+
+// ApplyLifecycleRules deletes all objects that are expired according to the lifecycle rules of
+// their bucket, as of now. It is meant to be called periodically.
+func (s *Service) ApplyLifecycleRules(ctx context.Context, now time.Time) error {
+	s.init()
+
+	bucketNames, err := s.configuredBuckets()
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	for _, bucketName := range bucketNames {
+		if err := s.applyBucketLifecycleRules(ctx, bucketName, now); err != nil {
+			if err == ErrNoSuchBucket {
+				continue
+			}
+			errs = errors.Append(errs, errors.Wrapf(err, "bucket %q", bucketName))
+		}
+	}
+	return errs
+}
+
+func (s *Service) applyBucketLifecycleRules(ctx context.Context, bucketName string, now time.Time) error {
+	bucketLock := s.bucketLock(bucketName)
+	bucketLock.RLock()
+	config, err := s.readBucketConfig(bucketName)
+	bucketLock.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range config.LifecycleRules {
+		if !rule.Enabled {
+			continue
+		}
+
+		if rule.ExpirationDays > 0 {
+			if err := s.expireCurrentObjects(ctx, bucketName, rule, now); err != nil {
+				return err
+			}
+		}
+		if rule.NoncurrentDays > 0 {
+			if err := s.expireNoncurrentVersions(ctx, bucketName, rule, now); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Service) expireCurrentObjects(ctx context.Context, bucketName string, rule lifecycleRule, now time.Time) error {
+	objects, err := s.listObjects(ctx, bucketName, rule.Prefix)
+	if err != nil {
+		return err
+	}
+
+	maxAge := time.Duration(rule.ExpirationDays) * day
+	for _, obj := range objects {
+		if now.Sub(obj.LastModified) < maxAge {
+			continue
+		}
+		if err := s.deleteObject(ctx, bucketName, obj.Name); err != nil && err != ErrNoSuchKey {
+			s.Log.Warn("error expiring object", sglog.String("key", bucketName+"/"+obj.Name), sglog.String("rule", rule.ID), sglog.Error(err))
+			continue
+		}
+		s.Log.Debug("expired object", sglog.String("key", bucketName+"/"+obj.Name), sglog.String("rule", rule.ID))
+	}
+	return nil
+}
+
+func (s *Service) expireNoncurrentVersions(ctx context.Context, bucketName string, rule lifecycleRule, now time.Time) error {
+	versions, err := s.listObjectVersions(ctx, bucketName, rule.Prefix)
+	if err != nil {
+		return err
+	}
+
+	maxAge := time.Duration(rule.NoncurrentDays) * day
+	// Versions are ordered from newest to oldest per object. A version becomes noncurrent when
+	// the next newer version or delete marker is created.
+	var noncurrentSince time.Time
+	for i, v := range versions {
+		if i == 0 || versions[i-1].Name != v.Name {
+			noncurrentSince = time.Time{}
+		}
+		if !v.IsLatest && !noncurrentSince.IsZero() && now.Sub(noncurrentSince) >= maxAge {
+			if _, err := s.deleteObjectVersion(ctx, bucketName, v.Name, v.VersionID); err != nil && err != ErrNoSuchVersion {
+				s.Log.Warn("error expiring object version", sglog.String("key", bucketName+"/"+v.Name), sglog.String("versionID", v.VersionID), sglog.Error(err))
+			}
+		}
+		noncurrentSince = v.LastModified
+	}
+
+	// Delete markers without any remaining versions are not useful anymore, S3 calls them expired
+	// object delete markers.
+	versions, err = s.listObjectVersions(ctx, bucketName, rule.Prefix)
+	if err != nil {
+		return err
+	}
+	for i, v := range versions {
+		onlyVersion := (i == 0 || versions[i-1].Name != v.Name) && (i == len(versions)-1 || versions[i+1].Name != v.Name)
+		if v.IsDeleteMarker && onlyVersion {
+			if _, err := s.deleteObjectVersion(ctx, bucketName, v.Name, v.VersionID); err != nil && err != ErrNoSuchVersion {
+				s.Log.Warn("error removing expired delete marker", sglog.String("key", bucketName+"/"+v.Name), sglog.Error(err))
+			}
+		}
+	}
+	return nil
+}
+
+// lifecycleRulesFromS3 converts an S3 lifecycle configuration into lifecycle rules. Only prefix
+// filters and expiration after a number of days are supported.
+func lifecycleRulesFromS3(config *s3LifecycleConfiguration) ([]lifecycleRule, error) {
+	rules := make([]lifecycleRule, 0, len(config.Rules))
+	for _, r := range config.Rules {
+		rule := lifecycleRule{
+			ID:      r.ID,
+			Enabled: r.Status == "Enabled",
+		}
+		if r.Status != "Enabled" && r.Status != "Disabled" {
+			return nil, errors.Newf("invalid rule status %q", r.Status)
+		}
+
+		if r.Prefix != nil {
+			rule.Prefix = *r.Prefix
+		}
+		if r.Filter != nil {
+			if r.Filter.And != nil || r.Filter.Tag != nil || r.Filter.ObjectSizeGreaterThan != nil || r.Filter.ObjectSizeLessThan != nil {
+				return nil, errNotImplemented("only prefix filters are supported in lifecycle rules")
+			}
+			if r.Filter.Prefix != nil {
+				rule.Prefix = *r.Filter.Prefix
+			}
+		}
+
+		if r.Expiration != nil {
+			if r.Expiration.Date != "" {
+				return nil, errNotImplemented("only expiration after a number of days is supported in lifecycle rules")
+			}
+			rule.ExpirationDays = r.Expiration.Days
+		}
+		if r.NoncurrentVersionExpiration != nil {
+			if r.NoncurrentVersionExpiration.NewerNoncurrentVersions != 0 {
+				return nil, errNotImplemented("NewerNoncurrentVersions is not supported in lifecycle rules")
+			}
+			rule.NoncurrentDays = r.NoncurrentVersionExpiration.NoncurrentDays
+		}
+		if r.Transitions != nil || r.NoncurrentVersionTransitions != nil || r.AbortIncompleteMultipartUpload != nil {
+			return nil, errNotImplemented("only expiration actions are supported in lifecycle rules")
+		}
+
+		if rule.ExpirationDays < 0 || rule.NoncurrentDays < 0 {
+			return nil, errors.New("expiration days must be positive")
+		}
+		if rule.ExpirationDays == 0 && rule.NoncurrentDays == 0 {
+			return nil, errors.Newf("rule %q has no expiration action", rule.ID)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// lifecycleRulesToS3 converts lifecycle rules into an S3 lifecycle configuration.
+func lifecycleRulesToS3(rules []lifecycleRule) s3LifecycleConfiguration {
+	var config s3LifecycleConfiguration
+	for _, rule := range rules {
+		prefix := rule.Prefix
+		r := s3LifecycleRule{
+			ID:     rule.ID,
+			Status: "Disabled",
+			Filter: &s3LifecycleFilter{Prefix: &prefix},
+		}
+		if rule.Enabled {
+			r.Status = "Enabled"
+		}
+		if rule.ExpirationDays > 0 {
+			r.Expiration = &s3LifecycleExpiration{Days: rule.ExpirationDays}
+		}
+		if rule.NoncurrentDays > 0 {
+			r.NoncurrentVersionExpiration = &s3NoncurrentVersionExpiration{NoncurrentDays: rule.NoncurrentDays}
+		}
+		config.Rules = append(config.Rules, r)
+	}
+	return config
+}
+
+// notImplementedError is returned for valid S3 requests that blobstore does not support.
+type notImplementedError struct{ msg string }
+
+func errNotImplemented(msg string) error { return &notImplementedError{msg: msg} }
+
+func (e *notImplementedError) Error() string { return e.msg }
+
+func isNotImplemented(err error) bool {
+	var e *notImplementedError
+	return errors.As(err, &e)
+}
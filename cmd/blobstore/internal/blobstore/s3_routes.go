@@ -23,15 +23,34 @@ func (s *Service) serveS3(w http.ResponseWriter, r *http.Request) error {
 	switch len(path) {
 	case 1:
 		bucketName := path[0]
+		query := r.URL.Query()
 		switch r.Method {
 		case "GET":
+			switch {
+			case query.Has("lifecycle"):
+				return s.serveGetBucketLifecycleConfiguration(w, r, bucketName)
+			case query.Has("versioning"):
+				return s.serveGetBucketVersioning(w, r, bucketName)
+			case query.Has("versions"):
+				return s.serveListObjectVersions(w, r, bucketName)
+			}
 			return s.serveListObjectsV2(w, r, bucketName)
 		case "PUT":
+			switch {
+			case query.Has("lifecycle"):
+				return s.servePutBucketLifecycleConfiguration(w, r, bucketName)
+			case query.Has("versioning"):
+				return s.servePutBucketVersioning(w, r, bucketName)
+			}
 			return s.serveCreateBucket(w, r, bucketName)
 		case "POST":
-			if r.URL.Query().Has("delete") {
+			if query.Has("delete") {
 				return s.serveDeleteObjects(w, r, bucketName)
 			}
+		case "DELETE":
+			if query.Has("lifecycle") {
+				return s.serveDeleteBucketLifecycle(w, r, bucketName)
+			}
 		}
 	case 2:
 		bucketName := path[0]
@@ -108,12 +127,9 @@ func (s *Service) serveCreateBucket(w http.ResponseWriter, r *http.Request, buck
 // https://docs.aws.amazon.com/AmazonS3/latest/API/API_HeadObject.html
 func (s *Service) serveHeadObject(w http.ResponseWriter, r *http.Request, bucketName, objectName string) error {
 	// TODO(blobstore): HEAD should not need to actually read the entire file, implement this with os.Stat
-	reader, err := s.getObject(r.Context(), bucketName, objectName)
-	if err != nil {
-		if err == ErrNoSuchKey {
-			return writeS3Error(w, s3ErrorNoSuchKey, bucketName, err, http.StatusNotFound)
-		}
-		return errors.Wrap(err, "getObject")
+	reader, err := s.openObject(w, r, bucketName, objectName)
+	if err != nil || reader == nil {
+		return err
 	}
 	defer reader.Close()
 	var numBytes int
@@ -135,18 +151,48 @@ func (s *Service) serveHeadObject(w http.ResponseWriter, r *http.Request, bucket
 // GET /<bucket>/<object>
 // https://docs.aws.amazon.com/AmazonS3/latest/API/API_GetObject.html
 func (s *Service) serveGetObject(w http.ResponseWriter, r *http.Request, bucketName, objectName string) error {
-	reader, err := s.getObject(r.Context(), bucketName, objectName)
-	if err != nil {
-		if err == ErrNoSuchKey {
-			return writeS3Error(w, s3ErrorNoSuchKey, bucketName, err, http.StatusNotFound)
-		}
-		return errors.Wrap(err, "getObject")
+	reader, err := s.openObject(w, r, bucketName, objectName)
+	if err != nil || reader == nil {
+		return err
 	}
 	defer reader.Close()
 	_, err = io.Copy(w, reader)
 	return errors.Wrap(err, "Copy")
 }
 
+// openObject opens the object, or the object version given by the ?versionId query parameter, for
+// GetObject and HeadObject requests and sets the response headers describing it. If the object
+// does not exist, it writes an error response and returns a nil reader.
+func (s *Service) openObject(w http.ResponseWriter, r *http.Request, bucketName, objectName string) (*objectReader, error) {
+	var (
+		reader *objectReader
+		err    error
+	)
+	versionID := r.URL.Query().Get("versionId")
+	if versionID != "" {
+		reader, err = s.getObjectVersion(r.Context(), bucketName, objectName, versionID)
+	} else {
+		reader, err = s.getObject(r.Context(), bucketName, objectName)
+	}
+	if err != nil {
+		switch err {
+		case ErrNoSuchKey:
+			return nil, writeS3Error(w, s3ErrorNoSuchKey, bucketName, err, http.StatusNotFound)
+		case ErrNoSuchVersion:
+			return nil, writeS3Error(w, s3ErrorNoSuchVersion, bucketName, err, http.StatusNotFound)
+		}
+		return nil, errors.Wrap(err, "getObject")
+	}
+
+	if versionID != "" {
+		w.Header().Set("x-amz-version-id", versionID)
+	}
+	if reader.encrypted {
+		w.Header().Set("x-amz-server-side-encryption", sseHeader)
+	}
+	return reader, nil
+}
+
 // PUT /<bucket>/<object>?uploadId=foobar&partNumber=123
 // https://docs.aws.amazon.com/AmazonS3/latest/API/API_UploadPartCopy.html
 func (s *Service) serveUploadPartCopy(w http.ResponseWriter, r *http.Request, bucketName, objectName string) error {
@@ -206,12 +252,19 @@ func (s *Service) serveUploadPart(w http.ResponseWriter, r *http.Request, bucket
 // PUT /<bucket>/<object>
 // https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutObject.html
 func (s *Service) servePutObject(w http.ResponseWriter, r *http.Request, bucketName, objectName string) error {
-	if _, err := s.putObject(r.Context(), bucketName, objectName, r.Body); err != nil {
+	metadata, err := s.putObject(r.Context(), bucketName, objectName, r.Body)
+	if err != nil {
 		if err == ErrNoSuchBucket {
 			return writeS3Error(w, s3ErrorNoSuchBucket, bucketName, err, http.StatusNotFound)
 		}
 		return errors.Wrap(err, "putObject")
 	}
+	if metadata.VersionID != "" {
+		w.Header().Set("x-amz-version-id", metadata.VersionID)
+	}
+	if s.EncryptionKey != nil {
+		w.Header().Set("x-amz-server-side-encryption", sseHeader)
+	}
 	return nil
 }
 
@@ -280,12 +333,32 @@ func (s *Service) serveAbortMultipartUpload(w http.ResponseWriter, r *http.Reque
 // DELETE /<bucket>/<object>
 // https://docs.aws.amazon.com/AmazonS3/latest/API/API_DeleteObject.html
 func (s *Service) serveDeleteObject(w http.ResponseWriter, r *http.Request, bucketName, objectName string) error {
-	if err := s.deleteObject(r.Context(), bucketName, objectName); err != nil {
+	if versionID := r.URL.Query().Get("versionId"); versionID != "" {
+		isDeleteMarker, err := s.deleteObjectVersion(r.Context(), bucketName, objectName, versionID)
+		if err != nil {
+			if err == ErrNoSuchVersion {
+				return writeS3Error(w, s3ErrorNoSuchVersion, bucketName, err, http.StatusNotFound)
+			}
+			return errors.Wrap(err, "deleteObjectVersion")
+		}
+		w.Header().Set("x-amz-version-id", versionID)
+		if isDeleteMarker {
+			w.Header().Set("x-amz-delete-marker", "true")
+		}
+		return nil
+	}
+
+	deleteMarkerVersionID, err := s.deleteCurrentObject(r.Context(), bucketName, objectName)
+	if err != nil {
 		if err == ErrNoSuchKey {
 			return writeS3Error(w, s3ErrorNoSuchKey, bucketName, err, http.StatusNotFound)
 		}
 		return errors.Wrap(err, "deleteObject")
 	}
+	if deleteMarkerVersionID != "" {
+		w.Header().Set("x-amz-version-id", deleteMarkerVersionID)
+		w.Header().Set("x-amz-delete-marker", "true")
+	}
 	return nil
 }
 
@@ -303,8 +376,14 @@ func (s *Service) serveDeleteObjects(_ http.ResponseWriter, r *http.Request, buc
 	// our client do with that info?
 	for _, obj := range req.Object {
 		objectName := obj.Key
-		if err := s.deleteObject(r.Context(), bucketName, objectName); err != nil {
-			if err == ErrNoSuchKey {
+		var err error
+		if obj.VersionId != "" {
+			_, err = s.deleteObjectVersion(r.Context(), bucketName, objectName, obj.VersionId)
+		} else {
+			err = s.deleteObject(r.Context(), bucketName, objectName)
+		}
+		if err != nil {
+			if err == ErrNoSuchKey || err == ErrNoSuchVersion {
 				continue
 			}
 			s.Log.Warn("error deleting object", sglog.String("key", bucketName+"/"+objectName), sglog.Error(err))
@@ -313,3 +392,137 @@ func (s *Service) serveDeleteObjects(_ http.ResponseWriter, r *http.Request, buc
 	}
 	return nil
 }
+
+// GET /<bucket>?versions
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_ListObjectVersions.html
+func (s *Service) serveListObjectVersions(w http.ResponseWriter, r *http.Request, bucketName string) error {
+	prefix := r.URL.Query().Get("prefix")
+
+	versions, err := s.listObjectVersions(r.Context(), bucketName, prefix)
+	if err != nil {
+		if err == ErrNoSuchBucket {
+			return writeS3Error(w, s3ErrorNoSuchBucket, bucketName, err, http.StatusNotFound)
+		}
+		return errors.Wrap(err, "listObjectVersions")
+	}
+	result := s3ListVersionsResult{
+		Name:        bucketName,
+		Prefix:      prefix,
+		IsTruncated: false,
+	}
+	for _, v := range versions {
+		version := s3ObjectVersion{
+			Key:          v.Name,
+			VersionId:    v.VersionID,
+			IsLatest:     v.IsLatest,
+			LastModified: v.LastModified.Format(time.RFC3339Nano),
+		}
+		if v.IsDeleteMarker {
+			result.DeleteMarkers = append(result.DeleteMarkers, version)
+		} else {
+			result.Versions = append(result.Versions, version)
+		}
+	}
+	return writeXML(w, http.StatusOK, result)
+}
+
+// GET /<bucket>?versioning
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_GetBucketVersioning.html
+func (s *Service) serveGetBucketVersioning(w http.ResponseWriter, r *http.Request, bucketName string) error {
+	config, err := s.getBucketConfig(r.Context(), bucketName)
+	if err != nil {
+		if err == ErrNoSuchBucket {
+			return writeS3Error(w, s3ErrorNoSuchBucket, bucketName, err, http.StatusNotFound)
+		}
+		return errors.Wrap(err, "getBucketConfig")
+	}
+	return writeXML(w, http.StatusOK, s3VersioningConfiguration{Status: config.Versioning})
+}
+
+// PUT /<bucket>?versioning
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutBucketVersioning.html
+func (s *Service) servePutBucketVersioning(w http.ResponseWriter, r *http.Request, bucketName string) error {
+	var req s3VersioningConfiguration
+	defer r.Body.Close()
+	if err := xml.NewDecoder(r.Body).Decode(&req); err != nil {
+		return writeS3Error(w, s3ErrorMalformedXML, bucketName, err, http.StatusBadRequest)
+	}
+	if req.Status != versioningEnabled && req.Status != versioningSuspended {
+		return writeS3Error(w, s3ErrorMalformedXML, bucketName, errors.Newf("invalid versioning status %q", req.Status), http.StatusBadRequest)
+	}
+	if req.MfaDelete == "Enabled" {
+		return writeS3Error(w, s3ErrorNotImplemented, bucketName, errors.New("MFA delete is not supported"), http.StatusNotImplemented)
+	}
+
+	if err := s.updateBucketConfig(bucketName, func(config *bucketConfig) {
+		config.Versioning = req.Status
+	}); err != nil {
+		if err == ErrNoSuchBucket {
+			return writeS3Error(w, s3ErrorNoSuchBucket, bucketName, err, http.StatusNotFound)
+		}
+		return errors.Wrap(err, "updateBucketConfig")
+	}
+	s.Log.Info("updated bucket versioning", sglog.String("name", bucketName), sglog.String("status", req.Status))
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// GET /<bucket>?lifecycle
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_GetBucketLifecycleConfiguration.html
+func (s *Service) serveGetBucketLifecycleConfiguration(w http.ResponseWriter, r *http.Request, bucketName string) error {
+	config, err := s.getBucketConfig(r.Context(), bucketName)
+	if err != nil {
+		if err == ErrNoSuchBucket {
+			return writeS3Error(w, s3ErrorNoSuchBucket, bucketName, err, http.StatusNotFound)
+		}
+		return errors.Wrap(err, "getBucketConfig")
+	}
+	if len(config.LifecycleRules) == 0 {
+		return writeS3Error(w, s3ErrorNoSuchLifecycleConfig, bucketName, errors.New("the lifecycle configuration does not exist"), http.StatusNotFound)
+	}
+	return writeXML(w, http.StatusOK, lifecycleRulesToS3(config.LifecycleRules))
+}
+
+// PUT /<bucket>?lifecycle
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutBucketLifecycleConfiguration.html
+func (s *Service) servePutBucketLifecycleConfiguration(w http.ResponseWriter, r *http.Request, bucketName string) error {
+	var req s3LifecycleConfiguration
+	defer r.Body.Close()
+	if err := xml.NewDecoder(r.Body).Decode(&req); err != nil {
+		return writeS3Error(w, s3ErrorMalformedXML, bucketName, err, http.StatusBadRequest)
+	}
+	rules, err := lifecycleRulesFromS3(&req)
+	if err != nil {
+		if isNotImplemented(err) {
+			return writeS3Error(w, s3ErrorNotImplemented, bucketName, err, http.StatusNotImplemented)
+		}
+		return writeS3Error(w, s3ErrorMalformedXML, bucketName, err, http.StatusBadRequest)
+	}
+
+	if err := s.updateBucketConfig(bucketName, func(config *bucketConfig) {
+		config.LifecycleRules = rules
+	}); err != nil {
+		if err == ErrNoSuchBucket {
+			return writeS3Error(w, s3ErrorNoSuchBucket, bucketName, err, http.StatusNotFound)
+		}
+		return errors.Wrap(err, "updateBucketConfig")
+	}
+	s.Log.Info("updated bucket lifecycle configuration", sglog.String("name", bucketName), sglog.Int("rules", len(rules)))
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// DELETE /<bucket>?lifecycle
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_DeleteBucketLifecycle.html
+func (s *Service) serveDeleteBucketLifecycle(w http.ResponseWriter, _ *http.Request, bucketName string) error {
+	if err := s.updateBucketConfig(bucketName, func(config *bucketConfig) {
+		config.LifecycleRules = nil
+	}); err != nil {
+		if err == ErrNoSuchBucket {
+			return writeS3Error(w, s3ErrorNoSuchBucket, bucketName, err, http.StatusNotFound)
+		}
+		return errors.Wrap(err, "updateBucketConfig")
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
@@ -13,6 +13,10 @@ var (
 	s3ErrorNoSuchKey               = "NoSuchKey"
 	s3ErrorNoSuchUpload            = "NoSuchUpload"
 	s3ErrorInvalidPartOrder        = "InvalidPartOrder"
+	s3ErrorNoSuchLifecycleConfig   = "NoSuchLifecycleConfiguration"
+	s3ErrorNoSuchVersion           = "NoSuchVersion"
+	s3ErrorMalformedXML            = "MalformedXML"
+	s3ErrorNotImplemented          = "NotImplemented"
 )
 
 type s3Error struct {
@@ -97,6 +101,80 @@ type s3DeleteObjectsRequest struct {
 	Quiet   bool
 }
 
+type s3VersioningConfiguration struct {
+	XMLName   xml.Name `xml:"VersioningConfiguration"`
+	Status    string   `xml:",omitempty"`
+	MfaDelete string   `xml:",omitempty"`
+}
+
+type s3LifecycleConfiguration struct {
+	XMLName xml.Name          `xml:"LifecycleConfiguration"`
+	Rules   []s3LifecycleRule `xml:"Rule"`
+}
+
+type s3LifecycleRule struct {
+	ID     string `xml:",omitempty"`
+	Status string
+
+	// Prefix is deprecated in favor of Filter, but still accepted by S3.
+	Prefix *string            `xml:",omitempty"`
+	Filter *s3LifecycleFilter `xml:",omitempty"`
+
+	Expiration                  *s3LifecycleExpiration         `xml:",omitempty"`
+	NoncurrentVersionExpiration *s3NoncurrentVersionExpiration `xml:",omitempty"`
+
+	// Unsupported actions, only decoded to reject them.
+	Transitions                    *s3Unsupported `xml:"Transition,omitempty"`
+	NoncurrentVersionTransitions   *s3Unsupported `xml:"NoncurrentVersionTransition,omitempty"`
+	AbortIncompleteMultipartUpload *s3Unsupported `xml:",omitempty"`
+}
+
+type s3LifecycleFilter struct {
+	Prefix *string `xml:",omitempty"`
+
+	// Unsupported filters, only decoded to reject them.
+	And                   *s3Unsupported `xml:",omitempty"`
+	Tag                   *s3Unsupported `xml:",omitempty"`
+	ObjectSizeGreaterThan *s3Unsupported `xml:",omitempty"`
+	ObjectSizeLessThan    *s3Unsupported `xml:",omitempty"`
+}
+
+type s3LifecycleExpiration struct {
+	Days int    `xml:",omitempty"`
+	Date string `xml:",omitempty"`
+}
+
+type s3NoncurrentVersionExpiration struct {
+	NoncurrentDays          int `xml:",omitempty"`
+	NewerNoncurrentVersions int `xml:",omitempty"`
+}
+
+// s3Unsupported holds the raw content of XML elements that blobstore does not support.
+type s3Unsupported struct {
+	Inner string `xml:",innerxml"`
+}
+
+type s3ObjectVersion struct {
+	Key          string
+	VersionId    string
+	IsLatest     bool
+	LastModified string
+	Size         int    `xml:",omitempty"`
+	StorageClass string `xml:",omitempty"`
+}
+
+type s3ListVersionsResult struct {
+	XMLName         xml.Name `xml:"ListVersionsResult"`
+	IsTruncated     bool
+	Name            string
+	Prefix          string
+	KeyMarker       string
+	VersionIdMarker string
+	MaxKeys         int
+	Versions        []s3ObjectVersion `xml:"Version"`
+	DeleteMarkers   []s3ObjectVersion `xml:"DeleteMarker"`
+}
+
 func writeS3Error(w http.ResponseWriter, code, bucketName string, err error, statusCode int) error {
 	return writeXML(w, statusCode,
 		s3Error{Code: code},
@@ -0,0 +1,244 @@
+package blobstore
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"os"
+
+	"github.com/sourcegraph/sourcegraph/internal/encryption"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Objects are encrypted at rest with envelope encryption: every object is encrypted with its own
+// random AES-256 data key, which is stored next to the data wrapped (encrypted) by the configured
+// encryption.Key. Objects can be large, so the data is split into chunks that are sealed with
+// AES-GCM individually. The file format is:
+//
+//	magic | uint16 length of wrapped key | wrapped key | chunk...
+//
+// Each chunk holds sseChunkSize bytes of plaintext, except the last one which holds less (possibly
+// zero bytes). The nonce of a chunk is its index, with a flag marking the last chunk, so that
+// reordered or truncated objects fail to decrypt.
+
+// sseMagic identifies encrypted object files. Objects written before encryption was enabled don't
+// start with it, and are read as-is.
+var sseMagic = []byte("SGBSSE\x00\x01")
+
+const sseChunkSize = 64 * 1024
+
+var errTruncatedObject = errors.New("encrypted object is truncated")
+
+// sseHeader is the value of the x-amz-server-side-encryption header for encrypted objects.
+const sseHeader = "AES256"
+
+type encryptingWriter struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	buf     []byte
+	sealed  []byte
+	counter uint64
+}
+
+// newEncryptingWriter writes the header of an encrypted object to w, and returns a writer that
+// encrypts everything written to it into w. Close must be called to write the last chunk, it does
+// not close w.
+func newEncryptingWriter(ctx context.Context, key encryption.Key, w io.Writer) (io.WriteCloser, error) {
+	dataKey := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, errors.Wrap(err, "generating data key")
+	}
+	wrappedKey, err := key.Encrypt(ctx, dataKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "wrapping data key")
+	}
+	if len(wrappedKey) > 0xFFFF {
+		return nil, errors.New("wrapped data key is too long")
+	}
+	aead, err := newChunkAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, len(sseMagic)+2+len(wrappedKey))
+	header = append(header, sseMagic...)
+	header = binary.BigEndian.AppendUint16(header, uint16(len(wrappedKey)))
+	header = append(header, wrappedKey...)
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &encryptingWriter{
+		w:      w,
+		aead:   aead,
+		buf:    make([]byte, 0, sseChunkSize),
+		sealed: make([]byte, 0, sseChunkSize+aead.Overhead()),
+	}, nil
+}
+
+func (e *encryptingWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		m := copy(e.buf[len(e.buf):cap(e.buf)], p)
+		e.buf = e.buf[:len(e.buf)+m]
+		p = p[m:]
+
+		// A full chunk is never the last one, the last chunk is written by Close.
+		if len(e.buf) == sseChunkSize {
+			if err := e.seal(false); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return n, nil
+}
+
+func (e *encryptingWriter) Close() error {
+	return e.seal(true)
+}
+
+func (e *encryptingWriter) seal(last bool) error {
+	e.sealed = e.aead.Seal(e.sealed[:0], chunkNonce(e.counter, last), e.buf, nil)
+	e.counter++
+	e.buf = e.buf[:0]
+	_, err := e.w.Write(e.sealed)
+	return err
+}
+
+type decryptingReader struct {
+	r       io.Reader
+	aead    cipher.AEAD
+	sealed  []byte
+	plain   []byte
+	next    []byte
+	counter uint64
+	done    bool
+}
+
+// newDecryptingReader reads the header of an encrypted object from r, and returns a reader of the
+// decrypted content.
+func newDecryptingReader(ctx context.Context, key encryption.Key, r io.Reader) (io.Reader, error) {
+	header := make([]byte, len(sseMagic)+2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, errors.Wrap(err, "reading encryption header")
+	}
+	if !bytes.Equal(header[:len(sseMagic)], sseMagic) {
+		return nil, errors.New("object is not encrypted")
+	}
+	wrappedKey := make([]byte, binary.BigEndian.Uint16(header[len(sseMagic):]))
+	if _, err := io.ReadFull(r, wrappedKey); err != nil {
+		return nil, errors.Wrap(err, "reading wrapped data key")
+	}
+	dataKey, err := key.Decrypt(ctx, wrappedKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "unwrapping data key")
+	}
+	aead, err := newChunkAEAD([]byte(dataKey.Secret()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &decryptingReader{
+		r:      r,
+		aead:   aead,
+		sealed: make([]byte, sseChunkSize+aead.Overhead()),
+		plain:  make([]byte, 0, sseChunkSize),
+	}, nil
+}
+
+func (d *decryptingReader) Read(p []byte) (int, error) {
+	for len(d.next) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, d.next)
+	d.next = d.next[n:]
+	return n, nil
+}
+
+func (d *decryptingReader) open() error {
+	n, err := io.ReadFull(d.r, d.sealed)
+	last := false
+	switch err {
+	case nil:
+	case io.ErrUnexpectedEOF:
+		// Only the last chunk is shorter than a full chunk.
+		last = true
+	case io.EOF:
+		return errTruncatedObject
+	default:
+		return err
+	}
+
+	plain, err := d.aead.Open(d.plain[:0], chunkNonce(d.counter, last), d.sealed[:n], nil)
+	if err != nil {
+		return errors.Wrap(err, "decrypting object")
+	}
+	d.counter++
+	d.next = plain
+	d.done = last
+	return nil
+}
+
+func newChunkAEAD(dataKey []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating AES cipher")
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating GCM cipher")
+	}
+	return aead, nil
+}
+
+func chunkNonce(counter uint64, last bool) []byte {
+	nonce := make([]byte, 12)
+	binary.BigEndian.PutUint64(nonce, counter)
+	if last {
+		nonce[8] = 1
+	}
+	return nonce
+}
+
+// objectReader reads an object file, decrypting it if necessary.
+type objectReader struct {
+	io.Reader
+	io.Closer
+	encrypted bool
+}
+
+// openObjectFile opens the object file at the given path. Encrypted objects are decrypted
+// transparently, which requires the encryption key to be configured.
+func (s *Service) openObjectFile(ctx context.Context, path string) (*objectReader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	br := bufio.NewReader(f)
+	if magic, _ := br.Peek(len(sseMagic)); !bytes.Equal(magic, sseMagic) {
+		return &objectReader{Reader: br, Closer: f}, nil
+	}
+
+	if s.EncryptionKey == nil {
+		f.Close()
+		return nil, errors.New("object is encrypted, but no encryption key is configured")
+	}
+	r, err := newDecryptingReader(ctx, s.EncryptionKey, br)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &objectReader{Reader: r, Closer: f, encrypted: true}, nil
+}
@@ -0,0 +1,62 @@
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	enctest "github.com/sourcegraph/sourcegraph/internal/encryption/testing"
+)
+
+func TestEncryptingWriter(t *testing.T) {
+	ctx := context.Background()
+	key := enctest.TestKey{}
+
+	encrypt := func(t *testing.T, plaintext []byte) []byte {
+		var buf bytes.Buffer
+		w, err := newEncryptingWriter(ctx, key, &buf)
+		require.NoError(t, err)
+		_, err = w.Write(plaintext)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		return buf.Bytes()
+	}
+	decrypt := func(ciphertext []byte) ([]byte, error) {
+		r, err := newDecryptingReader(ctx, key, bytes.NewReader(ciphertext))
+		if err != nil {
+			return nil, err
+		}
+		return io.ReadAll(r)
+	}
+
+	for _, size := range []int{0, 1, sseChunkSize - 1, sseChunkSize, 3*sseChunkSize + 7} {
+		plaintext := bytes.Repeat([]byte{'a'}, size)
+		ciphertext := encrypt(t, plaintext)
+		require.True(t, bytes.HasPrefix(ciphertext, sseMagic))
+
+		decrypted, err := decrypt(ciphertext)
+		require.NoError(t, err, "size %d", size)
+		require.Equal(t, plaintext, decrypted, "size %d", size)
+	}
+
+	t.Run("truncated", func(t *testing.T) {
+		ciphertext := encrypt(t, bytes.Repeat([]byte{'a'}, 2*sseChunkSize+1))
+
+		// Dropping the last chunk must not go unnoticed, even though the
+		// remaining chunks are intact.
+		lastChunk := 1 + 16 // one byte of plaintext plus the GCM tag
+		_, err := decrypt(ciphertext[:len(ciphertext)-lastChunk])
+		require.Error(t, err)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		ciphertext := encrypt(t, []byte("Hello world!"))
+		ciphertext[len(ciphertext)-1] ^= 1
+
+		_, err := decrypt(ciphertext)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,389 @@
+package blobstore
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	sglog "github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Object versioning
+//
+// In buckets with versioning enabled, every version of an object is stored in its own file below
+// versions/<bucket>/<object>/<version ID>. The current object file in the bucket directory is a
+// hard link to the latest version, so reading objects works the same way in all buckets. Deleting
+// an object without a version ID removes the current object file and records a delete marker,
+// which is an empty file named <version ID>.delete-marker.
+//
+// Version IDs start with the zero-padded creation time in nanoseconds, so that sorting them
+// lexicographically orders versions from oldest to newest, followed by random bytes to avoid
+// collisions.
+//
+// Objects that were written while versioning was not enabled are not linked to a version. They
+// are listed as the "null" version, like S3 does, and are linked to a version when they are
+// replaced or deleted once versioning is enabled. There are no versions newer than the null
+// version, as it is always the current object.
+
+var ErrNoSuchVersion = errors.New("no such version")
+
+const (
+	nullVersionID      = "null"
+	deleteMarkerSuffix = ".delete-marker"
+)
+
+type objectVersion struct {
+	Name           string
+	VersionID      string
+	IsLatest       bool
+	IsDeleteMarker bool
+	LastModified   time.Time
+}
+
+func newVersionID(now time.Time) string {
+	var suffix [4]byte
+	_, _ = rand.Read(suffix[:])
+	return fmt.Sprintf("%020d%08x", now.UnixNano(), binary.BigEndian.Uint32(suffix[:]))
+}
+
+// versionTime returns the creation time of the version with the given ID.
+func versionTime(versionID string) time.Time {
+	if len(versionID) < 20 {
+		return time.Time{}
+	}
+	nanos, err := strconv.ParseInt(versionID[:20], 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos).UTC()
+}
+
+func (s *Service) versionsDir(bucketName, objectName string) string {
+	return filepath.Join(s.DataDir, "versions", bucketName, objectFileName(objectName))
+}
+
+// versionEntry is a version or delete marker file of an object.
+type versionEntry struct {
+	versionID      string
+	isDeleteMarker bool
+}
+
+func (e versionEntry) fileName() string {
+	if e.isDeleteMarker {
+		return e.versionID + deleteMarkerSuffix
+	}
+	return e.versionID
+}
+
+// readVersions returns the versions and delete markers of an object, newest first.
+func (s *Service) readVersions(bucketName, objectName string) ([]versionEntry, error) {
+	entries, err := os.ReadDir(s.versionsDir(bucketName, objectName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "ReadDir")
+	}
+
+	versions := make([]versionEntry, 0, len(entries))
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".tmp") {
+			continue
+		}
+		versionID, isDeleteMarker := strings.CutSuffix(entry.Name(), deleteMarkerSuffix)
+		versions = append(versions, versionEntry{versionID: versionID, isDeleteMarker: isDeleteMarker})
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].versionID > versions[j].versionID })
+	return versions, nil
+}
+
+// currentVersionID returns the ID of the version the current object file is linked to. It returns
+// the null version ID if the current object is not linked to a version, and an empty string if
+// there is no current object.
+func (s *Service) currentVersionID(bucketName, objectName string, versions []versionEntry) string {
+	current, err := os.Stat(s.objectFilePath(bucketName, objectName))
+	if err != nil {
+		return ""
+	}
+	for _, v := range versions {
+		if v.isDeleteMarker {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(s.versionsDir(bucketName, objectName), v.fileName())); err == nil && os.SameFile(current, info) {
+			return v.versionID
+		}
+	}
+	return nullVersionID
+}
+
+// linkNewVersion records the given (fully written) file as a new version of an object, and returns
+// its version ID. The caller must subsequently move the file into place as the current object.
+func (s *Service) linkNewVersion(bucketName, objectName, path string) (string, error) {
+	versionsDir := s.versionsDir(bucketName, objectName)
+	if err := os.MkdirAll(versionsDir, os.ModePerm); err != nil {
+		return "", errors.Wrap(err, "MkdirAll")
+	}
+	versionID := newVersionID(time.Now())
+	if err := os.Link(path, filepath.Join(versionsDir, versionID)); err != nil {
+		return "", errors.Wrap(err, "linking version")
+	}
+	if err := fsync(versionsDir); err != nil {
+		return "", errors.Wrap(err, "sync versions dir")
+	}
+	return versionID, nil
+}
+
+// preserveNullVersion links the current object as a version, if it is not linked to one already.
+// It must be called before replacing or deleting the current object in a bucket with versioning
+// enabled, so that objects written before versioning was enabled are retained.
+func (s *Service) preserveNullVersion(bucketName, objectName string) error {
+	versions, err := s.readVersions(bucketName, objectName)
+	if err != nil {
+		return err
+	}
+	if s.currentVersionID(bucketName, objectName, versions) != nullVersionID {
+		return nil
+	}
+
+	objectFile := s.objectFilePath(bucketName, objectName)
+	info, err := os.Stat(objectFile)
+	if err != nil {
+		return errors.Wrap(err, "Stat")
+	}
+	versionsDir := s.versionsDir(bucketName, objectName)
+	if err := os.MkdirAll(versionsDir, os.ModePerm); err != nil {
+		return errors.Wrap(err, "MkdirAll")
+	}
+	if err := os.Link(objectFile, filepath.Join(versionsDir, newVersionID(info.ModTime()))); err != nil {
+		return errors.Wrap(err, "linking version")
+	}
+	return nil
+}
+
+// putDeleteMarker makes the delete marker the latest version of an object, by recording it and
+// removing the current object file. If preserveNull is set, an object that is not linked to a
+// version is retained as a version.
+func (s *Service) putDeleteMarker(bucketName, objectName string, preserveNull bool) (string, error) {
+	if preserveNull {
+		if err := s.preserveNullVersion(bucketName, objectName); err != nil {
+			return "", err
+		}
+	}
+
+	versionsDir := s.versionsDir(bucketName, objectName)
+	if err := os.MkdirAll(versionsDir, os.ModePerm); err != nil {
+		return "", errors.Wrap(err, "MkdirAll")
+	}
+	marker := versionEntry{versionID: newVersionID(time.Now()), isDeleteMarker: true}
+	f, err := os.Create(filepath.Join(versionsDir, marker.fileName()))
+	if err != nil {
+		return "", errors.Wrap(err, "creating delete marker")
+	}
+	f.Close()
+
+	if err := os.Remove(s.objectFilePath(bucketName, objectName)); err != nil && !os.IsNotExist(err) {
+		return "", errors.Wrap(err, "Remove")
+	}
+	return marker.versionID, nil
+}
+
+// getObjectVersion returns a reader for a specific version of an object.
+func (s *Service) getObjectVersion(ctx context.Context, bucketName, objectName, versionID string) (*objectReader, error) {
+	bucketLock := s.bucketLock(bucketName)
+	bucketLock.RLock()
+	defer bucketLock.RUnlock()
+
+	path := filepath.Join(s.versionsDir(bucketName, objectName), versionID)
+	if versionID == nullVersionID {
+		versions, err := s.readVersions(bucketName, objectName)
+		if err != nil {
+			return nil, err
+		}
+		if s.currentVersionID(bucketName, objectName, versions) != nullVersionID {
+			return nil, ErrNoSuchVersion
+		}
+		path = s.objectFilePath(bucketName, objectName)
+	} else if strings.ContainsAny(versionID, `/\.`) {
+		return nil, ErrNoSuchVersion
+	}
+
+	r, err := s.openObjectFile(ctx, path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNoSuchVersion
+		}
+		return nil, errors.Wrap(err, "Open")
+	}
+	return r, nil
+}
+
+// deleteObjectVersion permanently deletes a version or delete marker of an object. If it was the
+// latest version, the next newest version becomes the current object.
+func (s *Service) deleteObjectVersion(ctx context.Context, bucketName, objectName, versionID string) (isDeleteMarker bool, err error) {
+	_ = ctx
+
+	bucketLock := s.bucketLock(bucketName)
+	bucketLock.RLock()
+	defer bucketLock.RUnlock()
+
+	s.versionsMu.Lock()
+	defer s.versionsMu.Unlock()
+
+	return s.deleteObjectVersionLocked(bucketName, objectName, versionID)
+}
+
+func (s *Service) deleteObjectVersionLocked(bucketName, objectName, versionID string) (isDeleteMarker bool, err error) {
+	versions, err := s.readVersions(bucketName, objectName)
+	if err != nil {
+		return false, err
+	}
+	currentVersionID := s.currentVersionID(bucketName, objectName, versions)
+
+	if versionID == nullVersionID {
+		if currentVersionID != nullVersionID {
+			return false, ErrNoSuchVersion
+		}
+		if err := os.Remove(s.objectFilePath(bucketName, objectName)); err != nil {
+			return false, errors.Wrap(err, "Remove")
+		}
+		return false, s.restoreLatestVersion(bucketName, objectName)
+	}
+
+	idx := -1
+	for i, v := range versions {
+		if v.versionID == versionID {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		return false, ErrNoSuchVersion
+	}
+	deleted := versions[idx]
+
+	if err := os.Remove(filepath.Join(s.versionsDir(bucketName, objectName), deleted.fileName())); err != nil {
+		return false, errors.Wrap(err, "Remove")
+	}
+	if currentVersionID == versionID {
+		if err := os.Remove(s.objectFilePath(bucketName, objectName)); err != nil && !os.IsNotExist(err) {
+			return false, errors.Wrap(err, "Remove")
+		}
+	}
+	if currentVersionID == versionID || (idx == 0 && deleted.isDeleteMarker) {
+		if err := s.restoreLatestVersion(bucketName, objectName); err != nil {
+			return false, err
+		}
+	}
+
+	s.Log.Debug("delete object version", sglog.String("key", bucketName+"/"+objectName), sglog.String("versionID", versionID))
+	return deleted.isDeleteMarker, nil
+}
+
+// restoreLatestVersion makes the newest remaining version the current object, unless there is a
+// current object already or the newest version is a delete marker.
+func (s *Service) restoreLatestVersion(bucketName, objectName string) error {
+	objectFile := s.objectFilePath(bucketName, objectName)
+	if _, err := os.Stat(objectFile); err == nil {
+		return nil
+	}
+
+	versions, err := s.readVersions(bucketName, objectName)
+	if err != nil {
+		return err
+	}
+	if len(versions) == 0 {
+		// Best-effort cleanup, the directory is recreated when needed.
+		_ = os.Remove(s.versionsDir(bucketName, objectName))
+		return nil
+	}
+	if versions[0].isDeleteMarker {
+		return nil
+	}
+
+	// Link to a temporary file first, so that the current object appears atomically.
+	tmpPath := objectFile + "." + versions[0].versionID + ".tmp"
+	if err := os.Link(filepath.Join(s.versionsDir(bucketName, objectName), versions[0].versionID), tmpPath); err != nil {
+		return errors.Wrap(err, "linking version")
+	}
+	if err := os.Rename(tmpPath, objectFile); err != nil {
+		os.Remove(tmpPath)
+		return errors.Wrap(err, "renaming object file")
+	}
+	return fsync(s.bucketDir(bucketName))
+}
+
+// listObjectVersions returns all versions and delete markers of the objects with the given prefix,
+// ordered by name and then from newest to oldest, like S3 does.
+func (s *Service) listObjectVersions(ctx context.Context, bucketName, prefix string) ([]objectVersion, error) {
+	current, err := s.listObjects(ctx, bucketName, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	bucketLock := s.bucketLock(bucketName)
+	bucketLock.RLock()
+	defer bucketLock.RUnlock()
+
+	names := map[string]time.Time{}
+	for _, obj := range current {
+		names[obj.Name] = obj.LastModified
+	}
+	entries, err := os.ReadDir(filepath.Join(s.DataDir, "versions", bucketName))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, "ReadDir")
+	}
+	for _, entry := range entries {
+		if objectName := fnameToObjectName(entry.Name()); strings.HasPrefix(objectName, prefix) {
+			if _, ok := names[objectName]; !ok {
+				names[objectName] = time.Time{}
+			}
+		}
+	}
+
+	var versions []objectVersion
+	for objectName, lastModified := range names {
+		entries, err := s.readVersions(bucketName, objectName)
+		if err != nil {
+			return nil, err
+		}
+		currentVersionID := s.currentVersionID(bucketName, objectName, entries)
+		if currentVersionID == nullVersionID {
+			versions = append(versions, objectVersion{
+				Name:         objectName,
+				VersionID:    nullVersionID,
+				IsLatest:     true,
+				LastModified: lastModified,
+			})
+		}
+		for i, v := range entries {
+			versions = append(versions, objectVersion{
+				Name:           objectName,
+				VersionID:      v.versionID,
+				IsLatest:       v.versionID == currentVersionID || (i == 0 && v.isDeleteMarker && currentVersionID == ""),
+				IsDeleteMarker: v.isDeleteMarker,
+				LastModified:   versionTime(v.versionID),
+			})
+		}
+	}
+
+	sort.SliceStable(versions, func(i, j int) bool {
+		if versions[i].Name != versions[j].Name {
+			return versions[i].Name < versions[j].Name
+		}
+		// The null version is always the latest, and there are no other versions newer than it.
+		if versions[i].VersionID == nullVersionID || versions[j].VersionID == nullVersionID {
+			return versions[i].VersionID == nullVersionID
+		}
+		return versions[i].VersionID > versions[j].VersionID
+	})
+	return versions, nil
+}
@@ -2,6 +2,7 @@ package shared
 
 import (
 	"context"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/internal/debugserver"
 	"github.com/sourcegraph/sourcegraph/internal/env"
@@ -26,11 +27,15 @@ var Service service.Service = svc{}
 type Config struct {
 	env.BaseConfig
 
-	DataDir string
+	DataDir           string
+	EncryptObjects    bool
+	LifecycleInterval time.Duration
 }
 
 func (c *Config) Load() {
 	c.DataDir = c.Get("BLOBSTORE_DATA_DIR", "/data", "directory to store blobstore buckets and objects")
+	c.EncryptObjects = c.GetBool("BLOBSTORE_ENCRYPT_OBJECTS", "false", "encrypt objects at rest with the blobstoreKey from the encryption.keys site configuration")
+	c.LifecycleInterval = c.GetInterval("BLOBSTORE_LIFECYCLE_INTERVAL", "1h", "interval between runs applying bucket lifecycle rules")
 }
 
 func LoadConfig() *Config {
@@ -19,11 +19,13 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/conf/deploy"
+	"github.com/sourcegraph/sourcegraph/internal/encryption/keyring"
 	"github.com/sourcegraph/sourcegraph/internal/goroutine"
 	"github.com/sourcegraph/sourcegraph/internal/instrumentation"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/service"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 func shutdownOnSignal(ctx context.Context, server *http.Server) error {
@@ -55,15 +57,27 @@ func shutdownOnSignal(ctx context.Context, server *http.Server) error {
 func Start(ctx context.Context, observationCtx *observation.Context, config *Config, ready service.ReadyFunc) error {
 	logger := observationCtx.Logger
 
-	// Ready immediately
-	ready()
-
 	bsService := &blobstore.Service{
 		DataDir:        config.DataDir,
 		Log:            logger,
 		ObservationCtx: observation.NewContext(logger),
 	}
 
+	// Only initialize the keyring when encryption is enabled, as it requires the site
+	// configuration to be available.
+	if config.EncryptObjects {
+		if err := keyring.Init(ctx); err != nil {
+			return errors.Wrap(err, "initializing keyring")
+		}
+		bsService.EncryptionKey = keyring.Default().BlobstoreKey
+		if bsService.EncryptionKey == nil {
+			return errors.New("BLOBSTORE_ENCRYPT_OBJECTS is set, but no blobstoreKey is configured in encryption.keys")
+		}
+	}
+
+	// Ready once we are able to serve requests
+	ready()
+
 	// Set up handler middleware
 	handler := actor.HTTPMiddleware(logger, bsService)
 	handler = trace.HTTPMiddleware(logger, handler, conf.DefaultClient())
@@ -102,6 +116,22 @@ func Start(ctx context.Context, observationCtx *observation.Context, config *Con
 		return nil
 	})
 
+	// Apply bucket lifecycle rules periodically
+	lifecycle := goroutine.NewPeriodicGoroutine(
+		ctx,
+		goroutine.HandlerFunc(func(ctx context.Context) error {
+			return bsService.ApplyLifecycleRules(ctx, time.Now())
+		}),
+		goroutine.WithName("blobstore.lifecycle"),
+		goroutine.WithDescription("deletes objects expired by bucket lifecycle rules"),
+		goroutine.WithInterval(config.LifecycleInterval),
+	)
+	g.Go(func() error {
+		// Returns once ctx is canceled.
+		lifecycle.Start()
+		return nil
+	})
+
 	// Shutdown
 	g.Go(func() error {
 		return shutdownOnSignal(ctx, server)
@@ -240,6 +240,63 @@ func (a *Actor) Limiter(
 	}, true
 }
 
+// TokenLimiter returns the limiter for the number of input and output tokens
+// the actor may consume for the given feature, if the actor has a token limit.
+// Usage should be committed once the number of tokens consumed by a request is
+// known.
+func (a *Actor) TokenLimiter(
+	redis limiter.RedisStore,
+	feature codygateway.Feature,
+) (limiter.Limiter, bool) {
+	if a == nil {
+		return nil, false
+	}
+	limit, ok := a.RateLimits[feature]
+	if !ok || limit.TokenLimit <= 0 || limit.TokenInterval <= 0 {
+		return nil, false
+	}
+
+	return limiter.StaticLimiter{
+		LimiterName: "actor.TokenLimiter",
+		Identifier:  a.ID,
+		Redis:       limiter.NewPrefixRedisStore(fmt.Sprintf("tokens:%s:", feature), redis),
+		Limit:       limit.TokenLimit,
+		Interval:    limit.TokenInterval,
+		Unit:        "tokens",
+		// Only update rate limit TTL if the actor has been updated recently.
+		UpdateRateLimitTTL: a.LastUpdated != nil && time.Since(*a.LastUpdated) < 5*time.Minute,
+		NowFunc:            time.Now,
+	}, true
+}
+
+// inFlightTTL bounds how long slots of requests that are never released are
+// held. It should be longer than the longest request we expect to serve.
+const inFlightTTL = 10 * time.Minute
+
+// InFlightLimiter returns the limiter for the number of requests the actor may
+// have in flight at the same time for the given feature, if the actor has such
+// a limit.
+func (a *Actor) InFlightLimiter(
+	redis limiter.RedisStore,
+	feature codygateway.Feature,
+) (*limiter.InFlightLimiter, bool) {
+	if a == nil {
+		return nil, false
+	}
+	limit, ok := a.RateLimits[feature]
+	if !ok || limit.MaxInFlightRequests <= 0 {
+		return nil, false
+	}
+
+	return &limiter.InFlightLimiter{
+		LimiterName: "actor.InFlightLimiter",
+		Identifier:  a.ID,
+		Redis:       limiter.NewPrefixRedisStore(fmt.Sprintf("inflight:%s:", feature), redis),
+		Limit:       limit.MaxInFlightRequests,
+		TTL:         inFlightTTL,
+	}, true
+}
+
 // ErrAccessTokenDenied is returned when the access token is denied due to the
 // reason.
 type ErrAccessTokenDenied struct {
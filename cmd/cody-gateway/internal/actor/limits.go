@@ -29,6 +29,15 @@ type RateLimit struct {
 	// with NewRateLimitWithPercentageConcurrency.
 	ConcurrentRequests         int           `json:"concurrentRequests"`
 	ConcurrentRequestsInterval time.Duration `json:"concurrentRequestsInterval"`
+
+	// TokenLimit, TokenInterval limit the number of input and output tokens
+	// consumed per interval. Zero TokenLimit means tokens are not limited.
+	TokenLimit    int64         `json:"tokenLimit,omitempty"`
+	TokenInterval time.Duration `json:"tokenInterval,omitempty"`
+
+	// MaxInFlightRequests limits the number of requests, e.g. completion
+	// streams, in flight at the same time. Zero means no limit.
+	MaxInFlightRequests int `json:"maxInFlightRequests,omitempty"`
 }
 
 func NewRateLimitWithPercentageConcurrency(limit int64, interval time.Duration, allowedModels []string, concurrencyConfig codygateway.ActorConcurrencyLimitConfig) RateLimit {
@@ -51,6 +60,11 @@ func NewRateLimitWithPercentageConcurrency(limit int64, interval time.Duration,
 
 		ConcurrentRequests:         concurrencyLimit,
 		ConcurrentRequestsInterval: concurrencyConfig.Interval,
+
+		TokenLimit:    limit * concurrencyConfig.TokensPerRequest,
+		TokenInterval: interval,
+
+		MaxInFlightRequests: concurrencyConfig.MaxInFlightRequests,
 	}
 }
 
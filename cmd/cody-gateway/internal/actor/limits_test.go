@@ -56,6 +56,36 @@ func TestNewRateLimitWithPercentageConcurrency(t *testing.T) {
 			assert.Equal(t, test.wantConcurrencyLimit, got.ConcurrentRequests)
 		})
 	}
+
+	t.Run("token and in-flight limits", func(t *testing.T) {
+		got := NewRateLimitWithPercentageConcurrency(100, 24*time.Hour, []string{"model"}, codygateway.ActorConcurrencyLimitConfig{
+			Percentage:          0.1,
+			Interval:            10 * time.Second,
+			MaxInFlightRequests: 5,
+			TokensPerRequest:    1000,
+		})
+		assert.Equal(t, int64(100_000), got.TokenLimit)
+		assert.Equal(t, 24*time.Hour, got.TokenInterval)
+		assert.Equal(t, 5, got.MaxInFlightRequests)
+
+		act := &Actor{ID: "foo", RateLimits: map[codygateway.Feature]RateLimit{codygateway.FeatureChatCompletions: got}}
+		_, ok := act.TokenLimiter(limiter.MockRedisStore{}, codygateway.FeatureChatCompletions)
+		assert.True(t, ok)
+		_, ok = act.InFlightLimiter(limiter.MockRedisStore{}, codygateway.FeatureChatCompletions)
+		assert.True(t, ok)
+	})
+
+	t.Run("no token and in-flight limits by default", func(t *testing.T) {
+		got := NewRateLimitWithPercentageConcurrency(100, 24*time.Hour, []string{"model"}, concurrencyLimitConfig)
+		assert.Zero(t, got.TokenLimit)
+		assert.Zero(t, got.MaxInFlightRequests)
+
+		act := &Actor{ID: "foo", RateLimits: map[codygateway.Feature]RateLimit{codygateway.FeatureChatCompletions: got}}
+		_, ok := act.TokenLimiter(limiter.MockRedisStore{}, codygateway.FeatureChatCompletions)
+		assert.False(t, ok)
+		_, ok = act.InFlightLimiter(limiter.MockRedisStore{}, codygateway.FeatureChatCompletions)
+		assert.False(t, ok)
+	})
 }
 
 func TestConcurrencyLimiter_TryAcquire(t *testing.T) {
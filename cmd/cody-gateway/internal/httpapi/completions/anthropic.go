@@ -124,7 +124,7 @@ func NewAnthropicHandler(
 		rateLimitNotifier,
 		httpClient,
 		string(conftypes.CompletionsProviderNameAnthropic),
-		func(_ codygateway.Feature) string { return anthropicAPIURL },
+		func(_ codygateway.Feature, _ anthropicRequest) string { return anthropicAPIURL },
 		config.AllowedModels,
 		&AnthropicHandlerMethods{config: config, anthropicTokenizer: anthropicTokenizer, promptRegexps: promptRegexps, promptRecorder: promptRecorder},
 
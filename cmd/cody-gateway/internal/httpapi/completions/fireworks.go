@@ -41,7 +41,7 @@ func NewFireworksHandler(
 		rateLimitNotifier,
 		httpClient,
 		string(conftypes.CompletionsProviderNameFireworks),
-		func(feature codygateway.Feature) string {
+		func(feature codygateway.Feature, _ fireworksRequest) string {
 			if feature == codygateway.FeatureChatCompletions {
 				return fireworksChatAPIURL
 			} else {
@@ -0,0 +1,262 @@
+package completions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/cody-gateway/internal/actor"
+	"github.com/sourcegraph/sourcegraph/cmd/cody-gateway/internal/events"
+	"github.com/sourcegraph/sourcegraph/cmd/cody-gateway/internal/limiter"
+	"github.com/sourcegraph/sourcegraph/cmd/cody-gateway/internal/notify"
+	"github.com/sourcegraph/sourcegraph/cmd/cody-gateway/internal/tokenizer"
+	"github.com/sourcegraph/sourcegraph/cmd/cody-gateway/shared/config"
+	"github.com/sourcegraph/sourcegraph/internal/codygateway"
+	"github.com/sourcegraph/sourcegraph/internal/completions/client/google"
+	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+)
+
+const googleAPIURL = "https://generativelanguage.googleapis.com/v1beta/models"
+
+func NewGoogleHandler(
+	baseLogger log.Logger,
+	eventLogger events.Logger,
+	rs limiter.RedisStore,
+	rateLimitNotifier notify.RateLimitNotifier,
+	httpClient httpcli.Doer,
+	config config.GoogleConfig,
+	autoFlushStreamingResponses bool,
+) (http.Handler, error) {
+	// Google does not offer a tokenizer, so we approximate the token count of
+	// prompts with the Claude tokenizer for flagging.
+	tk, err := tokenizer.NewAnthropicClaudeTokenizer()
+	if err != nil {
+		return nil, err
+	}
+	return makeUpstreamHandler[googleRequest](
+		baseLogger,
+		eventLogger,
+		rs,
+		rateLimitNotifier,
+		httpClient,
+		string(conftypes.CompletionsProviderNameGoogle),
+		func(_ codygateway.Feature, body googleRequest) string {
+			if body.Stream {
+				return googleAPIURL + "/" + body.Model + ":streamGenerateContent?alt=sse"
+			}
+			return googleAPIURL + "/" + body.Model + ":generateContent"
+		},
+		config.AllowedModels,
+		&GoogleHandlerMethods{config: config, tokenizer: tk},
+
+		// Google uses requests-per-minute quotas, so set a retry-after higher
+		// than SRC_HTTP_CLI_EXTERNAL_RETRY_AFTER_MAX_DURATION to discourage
+		// Sourcegraph clients from retrying.
+		30, // seconds
+		autoFlushStreamingResponses,
+	), nil
+}
+
+// googleRequest captures fields from https://ai.google.dev/api/rest/v1beta/models/generateContent.
+//
+// The Gemini API expects the model and streaming mode as part of the URL, so
+// clients send them in the body and we strip them before sending the request
+// upstream.
+type googleRequest struct {
+	Model  string `json:"model,omitempty"`
+	Stream bool   `json:"stream,omitempty"`
+
+	Contents          []googleContent         `json:"contents"`
+	GenerationConfig  *googleGenerationConfig `json:"generationConfig,omitempty"`
+	SafetySettings    []googleSafetySetting   `json:"safetySettings,omitempty"`
+	SystemInstruction *googleContent          `json:"systemInstruction,omitempty"`
+}
+
+// MarshalJSON omits the model and streaming mode, which the Gemini API does
+// not accept in the request body.
+func (gr googleRequest) MarshalJSON() ([]byte, error) {
+	type upstreamRequest googleRequest
+	upstream := upstreamRequest(gr)
+	upstream.Model = ""
+	upstream.Stream = false
+	return json.Marshal(upstream)
+}
+
+func (gr googleRequest) ShouldStream() bool {
+	return gr.Stream
+}
+
+func (gr googleRequest) GetModel() string {
+	return gr.Model
+}
+
+// prompt returns the concatenated text of all contents of the request.
+func (gr googleRequest) prompt() string {
+	var sb strings.Builder
+	if gr.SystemInstruction != nil {
+		sb.WriteString(gr.SystemInstruction.text())
+	}
+	for _, c := range gr.Contents {
+		sb.WriteString(c.text())
+	}
+	return sb.String()
+}
+
+func (gr googleRequest) maxOutputTokens() int {
+	if gr.GenerationConfig == nil {
+		return 0
+	}
+	return gr.GenerationConfig.MaxOutputTokens
+}
+
+type googleContent struct {
+	Role  string              `json:"role,omitempty"`
+	Parts []googleContentPart `json:"parts"`
+}
+
+func (c googleContent) text() string {
+	var sb strings.Builder
+	for _, p := range c.Parts {
+		sb.WriteString(p.Text)
+	}
+	return sb.String()
+}
+
+type googleContentPart struct {
+	Text string `json:"text"`
+}
+
+type googleGenerationConfig struct {
+	Temperature     float32  `json:"temperature,omitempty"`
+	TopP            float32  `json:"topP,omitempty"`
+	TopK            int      `json:"topK,omitempty"`
+	MaxOutputTokens int      `json:"maxOutputTokens,omitempty"`
+	StopSequences   []string `json:"stopSequences,omitempty"`
+	CandidateCount  int      `json:"candidateCount,omitempty"`
+}
+
+type googleSafetySetting struct {
+	Category  string `json:"category"`
+	Threshold string `json:"threshold"`
+}
+
+type googleResponse struct {
+	Candidates []struct {
+		Content      googleContent `json:"content"`
+		FinishReason string        `json:"finishReason"`
+	} `json:"candidates"`
+	// UsageMetadata is included in every event of streaming responses, and
+	// contains the cumulative usage so far.
+	UsageMetadata struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+	} `json:"usageMetadata"`
+}
+
+type GoogleHandlerMethods struct {
+	tokenizer *tokenizer.Tokenizer
+	config    config.GoogleConfig
+}
+
+func (g *GoogleHandlerMethods) validateRequest(_ context.Context, logger log.Logger, _ codygateway.Feature, body googleRequest) (int, *flaggingResult, error) {
+	prompt := body.prompt()
+	tokens, err := g.tokenizer.Tokenize(prompt)
+	if err != nil {
+		logger.Error("error checking Google request - treating as non-flagged",
+			log.Error(err))
+		return 0, nil, nil
+	}
+	return 0, isFlaggedRequest(prompt, len(tokens), body.maxOutputTokens()), nil
+}
+func (g *GoogleHandlerMethods) transformBody(body *googleRequest, _ string) {
+	// We don't want to let users generate multiple responses, as this would
+	// mess with rate limit counting.
+	if body.GenerationConfig != nil && body.GenerationConfig.CandidateCount > 1 {
+		body.GenerationConfig.CandidateCount = 1
+	}
+}
+func (g *GoogleHandlerMethods) getRequestMetadata(_ context.Context, _ log.Logger, _ *actor.Actor, _ codygateway.Feature, body googleRequest) (model string, additionalMetadata map[string]any) {
+	return body.Model, map[string]any{
+		"stream":               body.Stream,
+		"max_tokens_to_sample": body.maxOutputTokens(),
+	}
+}
+func (g *GoogleHandlerMethods) transformRequest(r *http.Request) {
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("x-goog-api-key", g.config.AccessToken)
+}
+func (g *GoogleHandlerMethods) parseResponseAndUsage(logger log.Logger, reqBody googleRequest, r io.Reader) (promptUsage, completionUsage usageStats) {
+	// First, extract prompt usage details from the request.
+	promptUsage.characters = len(reqBody.prompt())
+	promptUsage.tokens = -1
+	completionUsage.tokens = -1
+
+	// Try to parse the request we saw, if it was non-streaming, we can simply parse
+	// it as JSON.
+	if !reqBody.Stream {
+		var res googleResponse
+		if err := json.NewDecoder(r).Decode(&res); err != nil {
+			logger.Error("failed to parse Google response as JSON", log.Error(err))
+			return promptUsage, completionUsage
+		}
+
+		if len(res.Candidates) > 0 {
+			completionUsage.characters = len(res.Candidates[0].Content.text())
+		}
+		g.setTokenUsage(logger, res, reqBody, &promptUsage, &completionUsage)
+		return promptUsage, completionUsage
+	}
+
+	// Otherwise, we have to parse the event stream.
+	dec := google.NewDecoder(r)
+	var lastEvent googleResponse
+	for dec.Scan() {
+		data := dec.Data()
+
+		// Gracefully skip over any data that isn't JSON-like.
+		if !bytes.HasPrefix(data, []byte("{")) {
+			continue
+		}
+
+		var event googleResponse
+		if err := json.Unmarshal(data, &event); err != nil {
+			logger.Error("failed to decode event payload", log.Error(err), log.String("body", string(data)))
+			continue
+		}
+		if len(event.Candidates) > 0 {
+			// Each event only contains the newly generated text.
+			completionUsage.characters += len(event.Candidates[0].Content.text())
+		}
+		lastEvent = event
+	}
+	if err := dec.Err(); err != nil {
+		logger.Error("failed to decode Google streaming response", log.Error(err))
+	}
+
+	g.setTokenUsage(logger, lastEvent, reqBody, &promptUsage, &completionUsage)
+	return promptUsage, completionUsage
+}
+
+// setTokenUsage extracts token counts from the usage metadata of the given
+// response, and falls back to counting prompt tokens ourselves if upstream did
+// not report usage.
+func (g *GoogleHandlerMethods) setTokenUsage(logger log.Logger, res googleResponse, reqBody googleRequest, promptUsage, completionUsage *usageStats) {
+	if res.UsageMetadata.PromptTokenCount > 0 {
+		promptUsage.tokens = res.UsageMetadata.PromptTokenCount
+		completionUsage.tokens = res.UsageMetadata.CandidatesTokenCount
+		return
+	}
+
+	logger.Warn("did not extract token counts from Google response")
+	if tokens, err := g.tokenizer.Tokenize(reqBody.prompt()); err != nil {
+		logger.Error("failed to count tokens in Google request", log.Error(err))
+	} else {
+		promptUsage.tokens = len(tokens)
+	}
+}
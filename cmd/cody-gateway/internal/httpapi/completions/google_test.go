@@ -0,0 +1,96 @@
+package completions
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/cmd/cody-gateway/internal/tokenizer"
+)
+
+func TestGoogleRequestMarshalJSON(t *testing.T) {
+	var req googleRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"model":"gemini-pro","stream":true,"contents":[{"role":"user","parts":[{"text":"Hello"}]}]}`), &req))
+	assert.Equal(t, "gemini-pro", req.GetModel())
+	assert.True(t, req.ShouldStream())
+
+	// Model and streaming mode are part of the URL, and must not be sent upstream.
+	upstreamPayload, err := json.Marshal(req)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"contents":[{"role":"user","parts":[{"text":"Hello"}]}]}`, string(upstreamPayload))
+}
+
+func TestGoogleRequestGetTokenCount(t *testing.T) {
+	logger := logtest.Scoped(t)
+	tk, err := tokenizer.NewAnthropicClaudeTokenizer()
+	require.NoError(t, err)
+	handler := &GoogleHandlerMethods{tokenizer: tk}
+
+	t.Run("streaming", func(t *testing.T) {
+		req := googleRequest{Stream: true}
+		r := openFixture(t, "google_stream.txt")
+		promptUsage, completionUsage := handler.parseResponseAndUsage(logger, req, r)
+
+		assert.Equal(t, 12, promptUsage.tokens)
+		assert.Equal(t, 89, completionUsage.tokens)
+		assert.Equal(t, 348, completionUsage.characters)
+	})
+
+	t.Run("non-streaming", func(t *testing.T) {
+		req := googleRequest{Stream: false}
+		r := openFixture(t, "google.json")
+		promptUsage, completionUsage := handler.parseResponseAndUsage(logger, req, r)
+
+		assert.Equal(t, 12, promptUsage.tokens)
+		assert.Equal(t, 89, completionUsage.tokens)
+		assert.Equal(t, 348, completionUsage.characters)
+	})
+
+	t.Run("missing usage", func(t *testing.T) {
+		req := googleRequest{Contents: []googleContent{{Role: "user", Parts: []googleContentPart{{Text: "Hello there"}}}}}
+		r := strings.NewReader(`{"candidates": [{"content": {"parts": [{"text": "Hi!"}],"role": "model"},"finishReason": "STOP"}]}`)
+		promptUsage, completionUsage := handler.parseResponseAndUsage(logger, req, r)
+
+		assert.Greater(t, promptUsage.tokens, 0)
+		assert.Equal(t, -1, completionUsage.tokens)
+		assert.Equal(t, 3, completionUsage.characters)
+	})
+}
+
+func TestGoogleValidateRequest(t *testing.T) {
+	logger := logtest.Scoped(t)
+	tk, err := tokenizer.NewAnthropicClaudeTokenizer()
+	require.NoError(t, err)
+	handler := &GoogleHandlerMethods{tokenizer: tk}
+
+	_, result, err := handler.validateRequest(context.Background(), logger, "", googleRequest{
+		Contents: []googleContent{{Role: "user", Parts: []googleContentPart{{Text: "Hello"}}}},
+	})
+	require.NoError(t, err)
+	assert.False(t, result.IsFlagged())
+
+	_, result, err = handler.validateRequest(context.Background(), logger, "", googleRequest{
+		Contents:         []googleContent{{Role: "user", Parts: []googleContentPart{{Text: "Hello"}}}},
+		GenerationConfig: &googleGenerationConfig{MaxOutputTokens: 2000},
+	})
+	require.NoError(t, err)
+	require.True(t, result.IsFlagged())
+	assert.Equal(t, []string{"high_max_tokens_to_sample"}, result.reasons)
+}
+
+// openFixture opens a response of an upstream API from the testdata directory.
+func openFixture(t *testing.T, name string) io.Reader {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	t.Cleanup(func() { f.Close() })
+	return f
+}
@@ -0,0 +1,191 @@
+package completions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/cody-gateway/internal/actor"
+	"github.com/sourcegraph/sourcegraph/cmd/cody-gateway/internal/events"
+	"github.com/sourcegraph/sourcegraph/cmd/cody-gateway/internal/limiter"
+	"github.com/sourcegraph/sourcegraph/cmd/cody-gateway/internal/notify"
+	"github.com/sourcegraph/sourcegraph/cmd/cody-gateway/internal/tokenizer"
+	"github.com/sourcegraph/sourcegraph/cmd/cody-gateway/shared/config"
+	"github.com/sourcegraph/sourcegraph/internal/codygateway"
+	"github.com/sourcegraph/sourcegraph/internal/completions/client/openai"
+	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+)
+
+const mistralAPIURL = "https://api.mistral.ai/v1/chat/completions"
+
+func NewMistralHandler(
+	baseLogger log.Logger,
+	eventLogger events.Logger,
+	rs limiter.RedisStore,
+	rateLimitNotifier notify.RateLimitNotifier,
+	httpClient httpcli.Doer,
+	config config.MistralConfig,
+	autoFlushStreamingResponses bool,
+) (http.Handler, error) {
+	// Mistral does not offer a Go tokenizer, so we approximate the token count
+	// of prompts with the Claude tokenizer for flagging.
+	tk, err := tokenizer.NewAnthropicClaudeTokenizer()
+	if err != nil {
+		return nil, err
+	}
+	return makeUpstreamHandler[mistralRequest](
+		baseLogger,
+		eventLogger,
+		rs,
+		rateLimitNotifier,
+		httpClient,
+		string(conftypes.CompletionsProviderNameMistral),
+		func(_ codygateway.Feature, _ mistralRequest) string { return mistralAPIURL },
+		config.AllowedModels,
+		&MistralHandlerMethods{config: config, tokenizer: tk},
+
+		// Mistral uses tokens-per-minute limits, so set a retry-after higher
+		// than SRC_HTTP_CLI_EXTERNAL_RETRY_AFTER_MAX_DURATION to discourage
+		// Sourcegraph clients from retrying.
+		30, // seconds
+		autoFlushStreamingResponses,
+	), nil
+}
+
+// mistralRequest captures fields from https://docs.mistral.ai/api/#operation/createChatCompletion.
+type mistralRequest struct {
+	Model       string    `json:"model"`
+	Messages    []message `json:"messages"`
+	Temperature float32   `json:"temperature,omitempty"`
+	TopP        float32   `json:"top_p,omitempty"`
+	MaxTokens   int       `json:"max_tokens,omitempty"`
+	Stream      bool      `json:"stream,omitempty"`
+	SafePrompt  bool      `json:"safe_prompt,omitempty"`
+	RandomSeed  int       `json:"random_seed,omitempty"`
+}
+
+func (mr mistralRequest) ShouldStream() bool {
+	return mr.Stream
+}
+
+func (mr mistralRequest) GetModel() string {
+	return mr.Model
+}
+
+// prompt returns the concatenated content of all messages of the request.
+func (mr mistralRequest) prompt() string {
+	var sb strings.Builder
+	for _, m := range mr.Messages {
+		sb.WriteString(m.Content)
+	}
+	return sb.String()
+}
+
+type mistralResponse struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+		Message      message `json:"message"`
+		FinishReason string  `json:"finish_reason"`
+	} `json:"choices"`
+	// Usage is included in non-streaming responses and in the last event of
+	// streaming responses.
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+type MistralHandlerMethods struct {
+	tokenizer *tokenizer.Tokenizer
+	config    config.MistralConfig
+}
+
+func (m *MistralHandlerMethods) validateRequest(_ context.Context, logger log.Logger, _ codygateway.Feature, body mistralRequest) (int, *flaggingResult, error) {
+	prompt := body.prompt()
+	tokens, err := m.tokenizer.Tokenize(prompt)
+	if err != nil {
+		logger.Error("error checking Mistral request - treating as non-flagged",
+			log.Error(err))
+		return 0, nil, nil
+	}
+	return 0, isFlaggedRequest(prompt, len(tokens), body.MaxTokens), nil
+}
+func (m *MistralHandlerMethods) transformBody(_ *mistralRequest, _ string) {}
+func (m *MistralHandlerMethods) getRequestMetadata(_ context.Context, _ log.Logger, _ *actor.Actor, _ codygateway.Feature, body mistralRequest) (model string, additionalMetadata map[string]any) {
+	return body.Model, map[string]any{
+		"stream":               body.Stream,
+		"max_tokens_to_sample": body.MaxTokens,
+	}
+}
+func (m *MistralHandlerMethods) transformRequest(r *http.Request) {
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Accept", "application/json")
+	r.Header.Set("Authorization", "Bearer "+m.config.AccessToken)
+}
+func (m *MistralHandlerMethods) parseResponseAndUsage(logger log.Logger, reqBody mistralRequest, r io.Reader) (promptUsage, completionUsage usageStats) {
+	// First, extract prompt usage details from the request.
+	promptUsage.characters = len(reqBody.prompt())
+	promptUsage.tokens = -1
+	completionUsage.tokens = -1
+
+	// Try to parse the request we saw, if it was non-streaming, we can simply parse
+	// it as JSON.
+	if !reqBody.Stream {
+		var res mistralResponse
+		if err := json.NewDecoder(r).Decode(&res); err != nil {
+			logger.Error("failed to parse Mistral response as JSON", log.Error(err))
+			return promptUsage, completionUsage
+		}
+
+		promptUsage.tokens = res.Usage.PromptTokens
+		completionUsage.tokens = res.Usage.CompletionTokens
+		if len(res.Choices) > 0 {
+			completionUsage.characters = len(res.Choices[0].Message.Content)
+		}
+		return promptUsage, completionUsage
+	}
+
+	// Otherwise, we have to parse the event stream, which uses the same format
+	// as OpenAI.
+	dec := openai.NewDecoder(r)
+	for dec.Scan() {
+		data := dec.Data()
+
+		// Gracefully skip over any data that isn't JSON-like.
+		if !bytes.HasPrefix(data, []byte("{")) {
+			continue
+		}
+
+		var event mistralResponse
+		if err := json.Unmarshal(data, &event); err != nil {
+			logger.Error("failed to decode event payload", log.Error(err), log.String("body", string(data)))
+			continue
+		}
+		if len(event.Choices) > 0 {
+			completionUsage.characters += len(event.Choices[0].Delta.Content)
+		}
+		// These are only included in the last event, so we're not worried about overwriting
+		if event.Usage.PromptTokens > 0 {
+			promptUsage.tokens = event.Usage.PromptTokens
+		}
+		if event.Usage.CompletionTokens > 0 {
+			completionUsage.tokens = event.Usage.CompletionTokens
+		}
+	}
+	if err := dec.Err(); err != nil {
+		logger.Error("failed to decode Mistral streaming response", log.Error(err))
+	}
+	if completionUsage.tokens == -1 || promptUsage.tokens == -1 {
+		logger.Warn("did not extract token counts from Mistral streaming response", log.Int("prompt-tokens", promptUsage.tokens), log.Int("completion-tokens", completionUsage.tokens))
+	}
+
+	return promptUsage, completionUsage
+}
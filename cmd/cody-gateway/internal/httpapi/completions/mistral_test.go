@@ -0,0 +1,45 @@
+package completions
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMistralRequestGetTokenCount(t *testing.T) {
+	logger := logtest.Scoped(t)
+
+	t.Run("streaming", func(t *testing.T) {
+		req := mistralRequest{Stream: true}
+		r := openFixture(t, "mistral_stream.txt")
+		handler := &MistralHandlerMethods{}
+		promptUsage, completionUsage := handler.parseResponseAndUsage(logger, req, r)
+
+		assert.Equal(t, 14, promptUsage.tokens)
+		assert.Equal(t, 91, completionUsage.tokens)
+		assert.Equal(t, 348, completionUsage.characters)
+	})
+
+	t.Run("non-streaming", func(t *testing.T) {
+		req := mistralRequest{Stream: false}
+		r := openFixture(t, "mistral.json")
+		handler := &MistralHandlerMethods{}
+		promptUsage, completionUsage := handler.parseResponseAndUsage(logger, req, r)
+
+		assert.Equal(t, 14, promptUsage.tokens)
+		assert.Equal(t, 91, completionUsage.tokens)
+		assert.Equal(t, 348, completionUsage.characters)
+	})
+
+	t.Run("malformed non-streaming", func(t *testing.T) {
+		req := mistralRequest{Stream: false}
+		r := strings.NewReader(`{"choices": [`)
+		handler := &MistralHandlerMethods{}
+		promptUsage, completionUsage := handler.parseResponseAndUsage(logger, req, r)
+
+		assert.Equal(t, -1, promptUsage.tokens)
+		assert.Equal(t, -1, completionUsage.tokens)
+	})
+}
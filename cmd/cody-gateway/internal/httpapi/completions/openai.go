@@ -40,7 +40,7 @@ func NewOpenAIHandler(
 		rateLimitNotifier,
 		httpClient,
 		string(conftypes.CompletionsProviderNameOpenAI),
-		func(_ codygateway.Feature) string { return openAIURL },
+		func(_ codygateway.Feature, _ openaiRequest) string { return openAIURL },
 		config.AllowedModels,
 		&OpenAIHandlerMethods{config: config},
 
@@ -38,6 +38,15 @@ type usageStats struct {
 	tokens int
 }
 
+// tokenCount returns the number of tokens consumed, and approximates it from
+// the number of characters if upstream did not report token counts.
+func (u usageStats) tokenCount() int {
+	if u.tokens >= 0 {
+		return u.tokens
+	}
+	return u.characters / 4
+}
+
 // Hop-by-Hop headers that should not be copied when proxying upstream requests
 // List from https://cs.opensource.google/go/go/+/master:src/net/http/httputil/reverseproxy.go;l=294;drc=7abeefd2b1a03932891e581f1f90656ffebebce4
 var hopHeaders = map[string]struct{}{
@@ -104,7 +113,9 @@ func makeUpstreamHandler[ReqT UpstreamRequest](
 	// provider names defined clientside, i.e. "anthropic" or "openai".
 	upstreamName string,
 
-	upstreamAPIURL func(feature codygateway.Feature) string,
+	// upstreamAPIURL returns the URL to send the given request to. Some
+	// upstreams encode the model or streaming mode in the URL.
+	upstreamAPIURL func(feature codygateway.Feature, body ReqT) string,
 	allowedModels []string,
 
 	methods upstreamHandlerMethods[ReqT],
@@ -115,9 +126,7 @@ func makeUpstreamHandler[ReqT UpstreamRequest](
 	defaultRetryAfterSeconds int,
 	autoFlushStreamingResponses bool,
 ) http.Handler {
-	baseLogger = baseLogger.Scoped(upstreamName).
-		// This URL is used only for logging reason so we default to the chat endpoint
-		With(log.String("upstream.url", upstreamAPIURL(codygateway.FeatureChatCompletions)))
+	baseLogger = baseLogger.Scoped(upstreamName)
 
 	// Convert allowedModels to the Cody Gateway configuration format with the
 	// provider as a prefix. This aligns with the models returned when we query
@@ -237,7 +246,9 @@ func makeUpstreamHandler[ReqT UpstreamRequest](
 			}
 
 			// Create a new request to send upstream, making sure we retain the same context.
-			req, err := http.NewRequestWithContext(r.Context(), http.MethodPost, upstreamAPIURL(feature), bytes.NewReader(upstreamPayload))
+			upstreamURL := upstreamAPIURL(feature, body)
+			logger = logger.With(log.String("upstream.url", upstreamURL))
+			req, err := http.NewRequestWithContext(r.Context(), http.MethodPost, upstreamURL, bytes.NewReader(upstreamPayload))
 			if err != nil {
 				response.JSONError(logger, w, http.StatusInternalServerError, errors.Wrap(err, "failed to create request"))
 				return
@@ -408,6 +419,7 @@ func makeUpstreamHandler[ReqT UpstreamRequest](
 			if upstreamStatusCode >= 200 && upstreamStatusCode < 300 {
 				// Pass reader to response transformer to capture token counts.
 				promptUsage, completionUsage = methods.parseResponseAndUsage(logger, body, &responseBuf)
+				featurelimiter.RecordTokenUsage(r.Context(), promptUsage.tokenCount()+completionUsage.tokenCount())
 			} else if upstreamStatusCode >= 500 {
 				logger.Error("error from upstream",
 					log.Int("status_code", upstreamStatusCode))
@@ -465,3 +477,31 @@ type flaggingResult struct {
 func (f *flaggingResult) IsFlagged() bool {
 	return f != nil
 }
+
+// isFlaggedRequest flags requests with a very large prompt or a very high
+// maximum number of tokens to sample, using the same limits as for Anthropic
+// requests.
+func isFlaggedRequest(prompt string, promptTokenCount, maxTokensToSample int) *flaggingResult {
+	var reasons []string
+	if maxTokensToSample > responseTokenFlaggingLimit {
+		reasons = append(reasons, "high_max_tokens_to_sample")
+	}
+	if promptTokenCount > promptTokenFlaggingLimit {
+		reasons = append(reasons, "high_prompt_token_count")
+	}
+	if len(reasons) == 0 {
+		return nil
+	}
+
+	promptPrefix := prompt
+	if len(promptPrefix) > logPromptPrefixLength {
+		promptPrefix = promptPrefix[0:logPromptPrefixLength]
+	}
+	return &flaggingResult{
+		reasons:           reasons,
+		maxTokensToSample: maxTokensToSample,
+		promptPrefix:      promptPrefix,
+		promptTokenCount:  promptTokenCount,
+		shouldBlock:       promptTokenCount > promptTokenBlockingLimit || maxTokensToSample > responseTokenBlockingLimit,
+	}
+}
@@ -121,6 +121,7 @@ func NewHandler(
 
 			resp, ut, err := c.GenerateEmbeddings(r.Context(), body)
 			usedTokens = ut
+			featurelimiter.RecordTokenUsage(r.Context(), usedTokens)
 			upstreamFinished = time.Since(upstreamStarted)
 			if err != nil {
 				// This is an error path, so always set a default retry-after
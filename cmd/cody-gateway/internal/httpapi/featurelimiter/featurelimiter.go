@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -34,8 +35,9 @@ func GetFeature(ctx context.Context) codygateway.Feature {
 }
 
 // Handle extracts features from codygateway.FeatureHeaderName and uses it to
-// determine the appropriate per-feature rate limits applied for an actor. It
-// only limits per-request.
+// determine the appropriate per-feature rate limits applied for an actor.
+// Request limits are per-request, while token limits are enforced based on
+// usage reported by handlers through RecordTokenUsage.
 func Handle(
 	baseLogger log.Logger,
 	eventLogger events.Logger,
@@ -95,32 +97,36 @@ func HandleFeature(
 			return
 		}
 
+		// logLimited logs that the request was rejected because of the given
+		// cause, e.g. "quota" or "concurrency".
+		logLimited := func(cause string, err error) {
+			limitMap := map[string]any{}
+			var limitExceededError limiter.RateLimitExceededError
+			if errors.As(err, &limitExceededError) {
+				limitMap["limit"] = limitExceededError.Limit
+				limitMap["retry_after"] = limitExceededError.RetryAfter
+			}
+			if loggerErr := eventLogger.LogEvent(
+				r.Context(),
+				events.Event{
+					Name:       codygateway.EventNameRateLimited,
+					Source:     act.Source.Name(),
+					Identifier: act.ID,
+					Metadata: events.MergeMaps(limitMap, map[string]any{
+						"error": err.Error(),
+						codygateway.CompletionsEventFeatureMetadataField: feature,
+						"cause": cause,
+					}),
+				},
+			); loggerErr != nil {
+				logger.Error("failed to log event", log.Error(loggerErr))
+			}
+		}
+
 		commit, err := l.TryAcquire(r.Context())
 		if err != nil {
 			limitedCause := "quota"
-			defer func() {
-				limitMap := map[string]any{}
-				var limitExceededError limiter.RateLimitExceededError
-				if errors.As(err, &limitExceededError) {
-					limitMap["limit"] = limitExceededError.Limit
-					limitMap["retry_after"] = limitExceededError.RetryAfter
-				}
-				if loggerErr := eventLogger.LogEvent(
-					r.Context(),
-					events.Event{
-						Name:       codygateway.EventNameRateLimited,
-						Source:     act.Source.Name(),
-						Identifier: act.ID,
-						Metadata: events.MergeMaps(limitMap, map[string]any{
-							"error": err.Error(),
-							codygateway.CompletionsEventFeatureMetadataField: feature,
-							"cause": limitedCause,
-						}),
-					},
-				); loggerErr != nil {
-					logger.Error("failed to log event", log.Error(loggerErr))
-				}
-			}()
+			defer func() { logLimited(limitedCause, err) }()
 
 			var concurrencyLimitExceeded actor.ErrConcurrencyLimitExceeded
 			if errors.As(err, &concurrencyLimitExceeded) {
@@ -129,21 +135,42 @@ func HandleFeature(
 				return
 			}
 
-			var rateLimitExceeded limiter.RateLimitExceededError
-			if errors.As(err, &rateLimitExceeded) {
-				rateLimitExceeded.WriteResponse(w)
+			writeLimitError(logger, w, err)
+			return
+		}
+
+		// Make sure the actor does not have too many requests in flight, e.g.
+		// long-running completion streams.
+		if inFlightLimiter, ok := act.InFlightLimiter(cache, feature); ok {
+			release, err := inFlightLimiter.Acquire(r.Context())
+			if err != nil {
+				if errors.As(err, &limiter.InFlightLimitExceededError{}) {
+					logLimited("in_flight", err)
+				}
+				writeLimitError(logger, w, err)
 				return
 			}
+			defer release()
+		}
 
-			if errors.Is(err, limiter.NoAccessError{}) {
-				response.JSONError(logger, w, http.StatusForbidden, err)
+		// Make sure the actor has token budget left. The tokens consumed by
+		// this request are only known after upstream has responded.
+		var commitTokens func(context.Context, int) error
+		if tokenLimiter, ok := act.TokenLimiter(cache, feature); ok {
+			commitTokens, err = tokenLimiter.TryAcquire(r.Context())
+			if err != nil {
+				if errors.As(err, &limiter.RateLimitExceededError{}) {
+					logLimited("tokens", err)
+				}
+				writeLimitError(logger, w, err)
 				return
 			}
-
-			response.JSONError(logger, w, http.StatusInternalServerError, err)
-			return
+			setTokenLimitHeaders(r.Context(), logger, w.Header(), tokenLimiter, act.RateLimits[feature].TokenLimit)
 		}
 
+		ctx, tokens := withTokenUsage(r.Context())
+		r = r.WithContext(ctx)
+
 		responseRecorder := response.NewStatusHeaderRecorder(w, logger)
 		next.ServeHTTP(responseRecorder, r)
 
@@ -156,10 +183,53 @@ func HandleFeature(
 			if err := commit(r.Context(), usage); err != nil {
 				logger.Error("failed to commit rate limit consumption", log.Error(err))
 			}
+			if commitTokens != nil && tokens.recorded {
+				if err := commitTokens(r.Context(), tokens.tokens); err != nil {
+					logger.Error("failed to commit token limit consumption", log.Error(err))
+				}
+			}
 		}
 	})
 }
 
+// writeLimitError writes the appropriate response for errors returned by
+// limiters.
+func writeLimitError(logger log.Logger, w http.ResponseWriter, err error) {
+	var rateLimitExceeded limiter.RateLimitExceededError
+	if errors.As(err, &rateLimitExceeded) {
+		rateLimitExceeded.WriteResponse(w)
+		return
+	}
+
+	var inFlightLimitExceeded limiter.InFlightLimitExceededError
+	if errors.As(err, &inFlightLimitExceeded) {
+		inFlightLimitExceeded.WriteResponse(w)
+		return
+	}
+
+	if errors.Is(err, limiter.NoAccessError{}) {
+		response.JSONError(logger, w, http.StatusForbidden, err)
+		return
+	}
+
+	response.JSONError(logger, w, http.StatusInternalServerError, err)
+}
+
+// setTokenLimitHeaders reports the token limit and the remaining token budget
+// of the actor in response headers.
+func setTokenLimitHeaders(ctx context.Context, logger log.Logger, h http.Header, tokenLimiter limiter.Limiter, tokenLimit int64) {
+	usage, expiry, err := tokenLimiter.Usage(ctx)
+	if err != nil {
+		logger.Warn("failed to get token usage", log.Error(err))
+		return
+	}
+	h.Set("x-ratelimit-limit-tokens", strconv.FormatInt(tokenLimit, 10))
+	h.Set("x-ratelimit-remaining-tokens", strconv.FormatInt(max(tokenLimit-int64(usage), 0), 10))
+	if !expiry.IsZero() {
+		h.Set("x-ratelimit-reset-tokens", expiry.Format(time.RFC1123))
+	}
+}
+
 // ListLimitsHandler returns a map of all features and their current rate limit usages.
 func ListLimitsHandler(baseLogger log.Logger, redisStore limiter.RedisStore) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -206,6 +276,24 @@ func ListLimitsHandler(baseLogger log.Logger, redisStore limiter.RedisStore) htt
 			if !expiry.IsZero() {
 				el.Expiry = &expiry
 			}
+			if tokenLimiter, ok := act.TokenLimiter(redisStore, f); ok {
+				tokenUsage, _, err := tokenLimiter.Usage(r.Context())
+				if err != nil {
+					response.JSONError(logger, w, http.StatusInternalServerError, errors.Wrap(err, "failed to get token usage"))
+					return
+				}
+				el.TokenLimit = rateLimit.TokenLimit
+				el.TokenUsage = int64(tokenUsage)
+			}
+			if inFlightLimiter, ok := act.InFlightLimiter(redisStore, f); ok {
+				inFlight, err := inFlightLimiter.Usage(r.Context())
+				if err != nil {
+					response.JSONError(logger, w, http.StatusInternalServerError, errors.Wrap(err, "failed to get in-flight requests"))
+					return
+				}
+				el.MaxInFlightRequests = rateLimit.MaxInFlightRequests
+				el.InFlightRequests = inFlight
+			}
 			res[f] = el
 		}
 
@@ -240,6 +328,11 @@ type listLimitElement struct {
 	Usage         int64      `json:"usage"`
 	Expiry        *time.Time `json:"expiry,omitempty"`
 	AllowedModels []string   `json:"allowedModels"`
+
+	TokenLimit          int64 `json:"tokenLimit,omitempty"`
+	TokenUsage          int64 `json:"tokenUsage,omitempty"`
+	MaxInFlightRequests int   `json:"maxInFlightRequests,omitempty"`
+	InFlightRequests    int   `json:"inFlightRequests,omitempty"`
 }
 
 func noopRateLimitNotifier(_ context.Context, _ codygateway.Actor, _ codygateway.Feature, _ float32, _ time.Duration) {
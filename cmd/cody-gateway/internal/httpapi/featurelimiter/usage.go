@@ -0,0 +1,28 @@
+package featurelimiter
+
+import "context"
+
+type tokenUsageKey struct{}
+
+// tokenUsage collects the tokens consumed by a request. It is only accessed by
+// the goroutine serving the request.
+type tokenUsage struct {
+	tokens   int
+	recorded bool
+}
+
+func withTokenUsage(ctx context.Context) (context.Context, *tokenUsage) {
+	u := &tokenUsage{}
+	return context.WithValue(ctx, tokenUsageKey{}, u), u
+}
+
+// RecordTokenUsage records the number of input and output tokens consumed by
+// the request, to be committed against the actor's token limit once the
+// request has completed. It is a no-op for requests not served through
+// HandleFeature.
+func RecordTokenUsage(ctx context.Context, tokens int) {
+	if u, ok := ctx.Value(tokenUsageKey{}).(*tokenUsage); ok && tokens > 0 {
+		u.tokens += tokens
+		u.recorded = true
+	}
+}
@@ -33,6 +33,8 @@ type Config struct {
 	Anthropic                   config.AnthropicConfig
 	OpenAI                      config.OpenAIConfig
 	Fireworks                   config.FireworksConfig
+	Google                      config.GoogleConfig
+	Mistral                     config.MistralConfig
 	EmbeddingsAllowedModels     []string
 	AutoFlushStreamingResponses bool
 	EnableAttributionSearch     bool
@@ -45,6 +47,8 @@ var (
 	attributesOpenAICompletions    = newMetricAttributes("openai", "completions")
 	attributesOpenAIEmbeddings     = newMetricAttributes("openai", "embeddings")
 	attributesFireworksCompletions = newMetricAttributes("fireworks", "completions")
+	attributesGoogleCompletions    = newMetricAttributes("google", "completions")
+	attributesMistralCompletions   = newMetricAttributes("mistral", "completions")
 )
 
 func NewHandler(
@@ -191,6 +195,66 @@ func NewHandler(
 			),
 		)
 	}
+	if config.Google.AccessToken != "" {
+		googleHandler, err := completions.NewGoogleHandler(
+			logger,
+			eventLogger,
+			rs,
+			config.RateLimitNotifier,
+			httpClient,
+			config.Google,
+			config.AutoFlushStreamingResponses,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "init Google handler")
+		}
+
+		v1router.Path("/completions/google").Methods(http.MethodPost).Handler(
+			instrumentation.HTTPMiddleware("v1.completions.google",
+				gaugeHandler(
+					counter,
+					attributesGoogleCompletions,
+					authr.Middleware(
+						requestlogger.Middleware(
+							logger,
+							googleHandler,
+						),
+					),
+				),
+				otelhttp.WithPublicEndpoint(),
+			),
+		)
+	}
+	if config.Mistral.AccessToken != "" {
+		mistralHandler, err := completions.NewMistralHandler(
+			logger,
+			eventLogger,
+			rs,
+			config.RateLimitNotifier,
+			httpClient,
+			config.Mistral,
+			config.AutoFlushStreamingResponses,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "init Mistral handler")
+		}
+
+		v1router.Path("/completions/mistral").Methods(http.MethodPost).Handler(
+			instrumentation.HTTPMiddleware("v1.completions.mistral",
+				gaugeHandler(
+					counter,
+					attributesMistralCompletions,
+					authr.Middleware(
+						requestlogger.Middleware(
+							logger,
+							mistralHandler,
+						),
+					),
+				),
+				otelhttp.WithPublicEndpoint(),
+			),
+		)
+	}
 
 	// Register a route where actors can retrieve their current rate limit state.
 	v1router.Path("/limits").Methods(http.MethodGet).Handler(
@@ -8,7 +8,10 @@ import (
 )
 
 type RateLimitExceededError struct {
-	Limit      int64
+	Limit int64
+	// Unit is what is being limited, e.g. "tokens". If empty, the limit is on
+	// requests.
+	Unit       string
 	RetryAfter time.Time
 }
 
@@ -17,8 +20,12 @@ type RateLimitExceededError struct {
 func (e RateLimitExceededError) Error() string { return "rate limit exceeded" }
 
 func (e RateLimitExceededError) Summary() string {
-	return fmt.Sprintf("you have exceeded the rate limit of %d requests. Retry after %s",
-		e.Limit, e.RetryAfter.Truncate(time.Second))
+	unit := e.Unit
+	if unit == "" {
+		unit = "requests"
+	}
+	return fmt.Sprintf("you have exceeded the rate limit of %d %s. Retry after %s",
+		e.Limit, unit, e.RetryAfter.Truncate(time.Second))
 }
 
 func (e RateLimitExceededError) WriteResponse(w http.ResponseWriter) {
@@ -35,3 +42,26 @@ type NoAccessError struct{}
 func (e NoAccessError) Error() string {
 	return "completions access has not been granted"
 }
+
+type InFlightLimitExceededError struct {
+	Limit int
+}
+
+// Error generates a simple string that is fairly static for use in logging.
+// This helps with categorizing errors. For more detailed output use Summary().
+func (e InFlightLimitExceededError) Error() string { return "in-flight request limit exceeded" }
+
+func (e InFlightLimitExceededError) Summary() string {
+	return fmt.Sprintf("you have exceeded the limit of %d requests in flight at the same time. Retry after one of your requests has completed",
+		e.Limit)
+}
+
+func (e InFlightLimitExceededError) WriteResponse(w http.ResponseWriter) {
+	// Limit exceeded, write well known headers and return correct status code.
+	// There is no reset time for in-flight requests, so we suggest retrying soon.
+	w.Header().Set("x-ratelimit-limit", strconv.Itoa(e.Limit))
+	w.Header().Set("x-ratelimit-remaining", "0")
+	w.Header().Set("retry-after", "1")
+	// Use Summary instead of Error for more informative text
+	http.Error(w, e.Summary(), http.StatusTooManyRequests)
+}
@@ -0,0 +1,109 @@
+package limiter
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// InFlightLimiter limits the number of requests that are in flight at the same
+// time, e.g. long-running completion streams. Unlike StaticLimiter, slots are
+// not consumed for an interval, but held until the request completes.
+type InFlightLimiter struct {
+	// LimiterName optionally identifies the limiter for instrumentation. If not
+	// provided, 'InFlightLimiter' is used.
+	LimiterName string
+
+	// Identifier is the key used to identify the in-flight counter.
+	Identifier string
+
+	Redis RedisStore
+	Limit int
+
+	// TTL is refreshed on every acquired slot, and bounds how long slots that
+	// are never released, e.g. because the process crashed, are held.
+	TTL time.Duration
+}
+
+// Acquire takes a slot if fewer than Limit requests are in flight, and returns
+// an InFlightLimitExceededError otherwise. The release callback must be called
+// once the request has completed. It is safe to call it more than once.
+func (l InFlightLimiter) Acquire(ctx context.Context) (release func(), err error) {
+	if l.LimiterName == "" {
+		l.LimiterName = "InFlightLimiter"
+	}
+	var inFlight int
+	var span trace.Span
+	ctx, span = tracer.Start(ctx, l.LimiterName+".Acquire",
+		trace.WithAttributes(attribute.Int("limit", l.Limit)))
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.SetAttributes(attribute.Int("inFlight", inFlight))
+		span.End()
+	}()
+
+	// Zero values implies no access - this is a fallback check, callers should
+	// be checking independently if a limit is configured.
+	if l.Identifier == "" || l.Limit <= 0 {
+		return nil, NoAccessError{}
+	}
+
+	// Optimistically take a slot, and give it back if we've exceeded the limit.
+	// This is atomic, unlike reading the counter before incrementing it.
+	inFlight, err = l.Redis.Incrby(l.Identifier, 1)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to increment in-flight counter")
+	}
+	if err := l.Redis.Expire(l.Identifier, int(l.TTL.Seconds())); err != nil {
+		l.release(ctx)
+		return nil, errors.Wrap(err, "failed to set expiry for in-flight counter")
+	}
+	if inFlight > l.Limit {
+		l.release(ctx)
+		return nil, InFlightLimitExceededError{Limit: l.Limit}
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { l.release(context.WithoutCancel(ctx)) })
+	}, nil
+}
+
+// release gives back a slot. Errors are recorded on the trace only, since the
+// TTL on the counter makes sure that we eventually recover.
+func (l InFlightLimiter) release(ctx context.Context) {
+	span := trace.SpanFromContext(ctx)
+	remaining, err := l.Redis.Incrby(l.Identifier, -1)
+	if err != nil {
+		span.RecordError(errors.Wrap(err, "failed to decrement in-flight counter"))
+		return
+	}
+	// The counter may have expired while requests were in flight, in which case
+	// releasing them makes it negative. Reset it so that we don't allow more
+	// requests than the limit later on.
+	if remaining < 0 {
+		if err := l.Redis.Del(l.Identifier); err != nil {
+			span.RecordError(errors.Wrap(err, "failed to reset in-flight counter"))
+		}
+	}
+}
+
+// Usage returns the number of requests currently in flight.
+func (l InFlightLimiter) Usage(ctx context.Context) (int, error) {
+	if l.Identifier == "" || l.Limit <= 0 {
+		return 0, NoAccessError{}
+	}
+	inFlight, err := l.Redis.GetInt(l.Identifier)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to read in-flight counter")
+	}
+	return max(inFlight, 0), nil
+}
@@ -0,0 +1,67 @@
+package limiter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hexops/autogold/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInFlightLimiterAcquire(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("no limit set", func(t *testing.T) {
+		_, err := InFlightLimiter{Identifier: "foobar", Redis: MockRedisStore{}}.Acquire(ctx)
+		require.Error(t, err)
+		autogold.Expect("completions access has not been granted").Equal(t, err.Error())
+	})
+
+	t.Run("acquire and release", func(t *testing.T) {
+		store := MockRedisStore{}
+		l := InFlightLimiter{Identifier: "foobar", Redis: store, Limit: 2, TTL: 10 * time.Minute}
+
+		release1, err := l.Acquire(ctx)
+		require.NoError(t, err)
+		release2, err := l.Acquire(ctx)
+		require.NoError(t, err)
+		autogold.Expect(MockRedisStore{"foobar": MockRedisEntry{Value: 2, TTL: 600}}).Equal(t, store)
+
+		// A third request is rejected, and does not hold a slot.
+		_, err = l.Acquire(ctx)
+		require.Error(t, err)
+		var exceeded InFlightLimitExceededError
+		require.ErrorAs(t, err, &exceeded)
+		assert.Equal(t, 2, exceeded.Limit)
+		usage, err := l.Usage(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, usage)
+
+		// Releasing more than once only gives back one slot.
+		release1()
+		release1()
+		usage, err = l.Usage(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, usage)
+
+		_, err = l.Acquire(ctx)
+		require.NoError(t, err)
+		release2()
+		autogold.Expect(MockRedisStore{"foobar": MockRedisEntry{Value: 1, TTL: 600}}).Equal(t, store)
+	})
+
+	t.Run("counter expired while in flight", func(t *testing.T) {
+		store := MockRedisStore{}
+		l := InFlightLimiter{Identifier: "foobar", Redis: store, Limit: 2, TTL: 10 * time.Minute}
+
+		release, err := l.Acquire(ctx)
+		require.NoError(t, err)
+		require.NoError(t, store.Del("foobar"))
+
+		// Releasing does not leave a negative count behind.
+		release()
+		autogold.Expect(MockRedisStore{}).Equal(t, store)
+	})
+}
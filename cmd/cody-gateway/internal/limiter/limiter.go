@@ -41,6 +41,10 @@ type StaticLimiter struct {
 	Limit    int64
 	Interval time.Duration
 
+	// Unit optionally describes what is being limited, e.g. "tokens", for
+	// errors. If not provided, 'requests' is used.
+	Unit string
+
 	// UpdateRateLimitTTL, if true, indicates that the TTL of the rate limit count should
 	// be updated if there is a significant deviance from the desired interval.
 	UpdateRateLimitTTL bool
@@ -108,6 +112,7 @@ func (l StaticLimiter) TryAcquire(ctx context.Context) (_ func(context.Context,
 
 		return nil, RateLimitExceededError{
 			Limit:      l.Limit,
+			Unit:       l.Unit,
 			RetryAfter: retryAfter,
 		}
 	}
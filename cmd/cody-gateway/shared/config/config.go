@@ -33,6 +33,10 @@ type Config struct {
 
 	Fireworks FireworksConfig
 
+	Google GoogleConfig
+
+	Mistral MistralConfig
+
 	AllowedEmbeddingsModels []string
 
 	AllowAnonymous bool
@@ -88,6 +92,16 @@ type OpenAIConfig struct {
 	OrgID         string
 }
 
+type GoogleConfig struct {
+	AllowedModels []string
+	AccessToken   string
+}
+
+type MistralConfig struct {
+	AllowedModels []string
+	AccessToken   string
+}
+
 func (c *Config) Load() {
 	c.InsecureDev = env.InsecureDev
 	c.Port = c.GetInt("PORT", "9992", "Port to serve Cody Gateway on, generally injected by Cloud Run.")
@@ -170,6 +184,24 @@ func (c *Config) Load() {
 	c.Fireworks.StarcoderCommunitySingleTenantPercent = c.GetPercent("CODY_GATEWAY_FIREWORKS_STARCODER_COMMUNITY_SINGLE_TENANT_PERCENT", "0", "The percentage of community traffic for Starcoder to be redirected to the single-tenant deployment.")
 	c.Fireworks.StarcoderEnterpriseSingleTenantPercent = c.GetPercent("CODY_GATEWAY_FIREWORKS_STARCODER_ENTERPRISE_SINGLE_TENANT_PERCENT", "100", "The percentage of Enterprise traffic for Starcoder to be redirected to the single-tenant deployment.")
 
+	c.Google.AccessToken = c.GetOptional("CODY_GATEWAY_GOOGLE_ACCESS_TOKEN", "The Google Generative Language API key to be used.")
+	c.Google.AllowedModels = splitMaybe(c.Get("CODY_GATEWAY_GOOGLE_ALLOWED_MODELS",
+		strings.Join([]string{"gemini-pro", "gemini-1.5-pro-latest"}, ","),
+		"Google models that can be used."),
+	)
+	if c.Google.AccessToken != "" && len(c.Google.AllowedModels) == 0 {
+		c.AddError(errors.New("must provide allowed models for Google"))
+	}
+
+	c.Mistral.AccessToken = c.GetOptional("CODY_GATEWAY_MISTRAL_ACCESS_TOKEN", "The Mistral access token to be used.")
+	c.Mistral.AllowedModels = splitMaybe(c.Get("CODY_GATEWAY_MISTRAL_ALLOWED_MODELS",
+		strings.Join([]string{"mistral-small-latest", "mistral-medium-latest", "mistral-large-latest", "open-mixtral-8x7b"}, ","),
+		"Mistral models that can be used."),
+	)
+	if c.Mistral.AccessToken != "" && len(c.Mistral.AllowedModels) == 0 {
+		c.AddError(errors.New("must provide allowed models for Mistral"))
+	}
+
 	c.AllowedEmbeddingsModels = splitMaybe(c.Get("CODY_GATEWAY_ALLOWED_EMBEDDINGS_MODELS", strings.Join([]string{"openai/text-embedding-ada-002"}, ","), "The models allowed for embeddings generation."))
 	if len(c.AllowedEmbeddingsModels) == 0 {
 		c.AddError(errors.New("must provide allowed models for embeddings generation"))
@@ -198,6 +230,8 @@ func (c *Config) Load() {
 
 	c.ActorConcurrencyLimit.Percentage = float32(c.GetPercent("CODY_GATEWAY_ACTOR_CONCURRENCY_LIMIT_PERCENTAGE", "50", "The percentage of daily rate limit to be allowed as concurrent requests limit from an actor.")) / 100
 	c.ActorConcurrencyLimit.Interval = c.GetInterval("CODY_GATEWAY_ACTOR_CONCURRENCY_LIMIT_INTERVAL", "10s", "The interval at which to check the concurrent requests limit from an actor.")
+	c.ActorConcurrencyLimit.MaxInFlightRequests = c.GetInt("CODY_GATEWAY_ACTOR_MAX_IN_FLIGHT_REQUESTS", "0", "The number of requests per feature an actor may have in flight at the same time. 0 disables the limit.")
+	c.ActorConcurrencyLimit.TokensPerRequest = int64(c.GetInt("CODY_GATEWAY_ACTOR_TOKENS_PER_REQUEST", "0", "The average number of input and output tokens each request of an actor's rate limit may consume, used to derive an actor's token budget. 0 disables token limits."))
 
 	c.ActorRateLimitNotify.SlackWebhookURL = c.GetOptional("CODY_GATEWAY_ACTOR_RATE_LIMIT_NOTIFY_SLACK_WEBHOOK_URL", "The Slack webhook URL to send notifications to.")
 	c.AutoFlushStreamingResponses = c.GetBool("CODY_GATEWAY_AUTO_FLUSH_STREAMING_RESPONSES", "false", "Whether we should flush streaming responses after every write.")
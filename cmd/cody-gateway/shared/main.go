@@ -166,6 +166,8 @@ func Main(ctx context.Context, obctx *observation.Context, ready service.ReadyFu
 			Anthropic:                   cfg.Anthropic,
 			OpenAI:                      cfg.OpenAI,
 			Fireworks:                   cfg.Fireworks,
+			Google:                      cfg.Google,
+			Mistral:                     cfg.Mistral,
 			EmbeddingsAllowedModels:     cfg.AllowedEmbeddingsModels,
 			AutoFlushStreamingResponses: cfg.AutoFlushStreamingResponses,
 			EnableAttributionSearch:     cfg.Attribution.Enabled,
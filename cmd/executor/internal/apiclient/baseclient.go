@@ -111,6 +111,12 @@ func (c *BaseClient) Do(ctx context.Context, req *http.Request) (hasContent bool
 			return false, nil, nil
 		}
 
+		// A missing resource is an expected outcome of a GET request that callers
+		// handle themselves, e.g. a step cache miss, so it is not logged as an error.
+		if resp.StatusCode == http.StatusNotFound && req.Method == http.MethodGet {
+			return false, nil, &UnexpectedStatusCodeErr{StatusCode: resp.StatusCode}
+		}
+
 		if content, err := io.ReadAll(resp.Body); err != nil {
 			c.logger.Error("Failed to read response body", log.Error(err))
 		} else {
@@ -20,6 +20,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/cmd/executor/internal/apiclient"
 	"github.com/sourcegraph/sourcegraph/cmd/executor/internal/worker/cmdlogger"
+	"github.com/sourcegraph/sourcegraph/cmd/executor/internal/worker/stepcache"
 	internalexecutor "github.com/sourcegraph/sourcegraph/internal/executor"
 	"github.com/sourcegraph/sourcegraph/internal/executor/types"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
@@ -40,6 +41,7 @@ type Client struct {
 // Compile time validation.
 var _ workerutil.Store[types.Job] = &Client{}
 var _ cmdlogger.ExecutionLogEntryStore = &Client{}
+var _ stepcache.Store = &Client{}
 
 func New(observationCtx *observation.Context, options Options, metricsGatherer prometheus.Gatherer) (*Client, error) {
 	logger := log.Scoped("executor-api-queue-client")
@@ -398,6 +400,58 @@ func (c *Client) UpdateExecutionLogEntry(ctx context.Context, job types.Job, ent
 	return c.client.DoAndDrop(ctx, req)
 }
 
+// GetStepCacheEntry returns a reader over the step cache entry with the given key. If
+// there is no such entry, a false-valued flag is returned.
+func (c *Client) GetStepCacheEntry(ctx context.Context, job types.Job, key string) (_ io.ReadCloser, _ bool, err error) {
+	queue := c.inferQueueName(job)
+
+	ctx, _, endObservation := c.operations.getStepCacheEntry.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("queueName", queue),
+		attribute.Int("jobID", job.ID),
+		attribute.String("key", key),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	req, err := c.client.NewRequest(job.ID, job.Token, http.MethodGet, fmt.Sprintf("%s/stepCache/%s", queue, key), nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	hasContent, body, err := c.client.Do(ctx, req)
+	if err != nil {
+		var e *apiclient.UnexpectedStatusCodeErr
+		if errors.As(err, &e) && e.StatusCode == http.StatusNotFound {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	if !hasContent {
+		return nil, false, nil
+	}
+
+	return body, true, nil
+}
+
+// PutStepCacheEntry stores the content of the given reader as the step cache entry
+// with the given key.
+func (c *Client) PutStepCacheEntry(ctx context.Context, job types.Job, key string, r io.Reader) (err error) {
+	queue := c.inferQueueName(job)
+
+	ctx, _, endObservation := c.operations.putStepCacheEntry.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("queueName", queue),
+		attribute.Int("jobID", job.ID),
+		attribute.String("key", key),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	req, err := c.client.NewRequest(job.ID, job.Token, http.MethodPut, fmt.Sprintf("%s/stepCache/%s", queue, key), r)
+	if err != nil {
+		return err
+	}
+
+	return c.client.DoAndDrop(ctx, req)
+}
+
 // inferQueueName returns the queue name if it is specified on the job, which is the case
 // when an executor is configured to listen to multiple queues. If the queue name is empty,
 // return the specific queue that is configured.
@@ -727,6 +727,96 @@ func TestUpdateExecutionLogEntryBadResponse(t *testing.T) {
 	})
 }
 
+func TestGetStepCacheEntry(t *testing.T) {
+	const key = "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+
+	spec := routeSpec{
+		expectedMethod:       "GET",
+		expectedPath:         "/.executors/queue/test_queue/stepCache/" + key,
+		expectedUsername:     "test",
+		expectedToken:        "job-token",
+		expectedJobID:        "42",
+		expectedExecutorName: "deadbeef",
+		expectedPayload:      ``,
+		responseStatus:       http.StatusOK,
+		responsePayload:      `<archive>`,
+	}
+
+	testRoute(t, spec, func(client *queue.Client) {
+		rc, ok, err := client.GetStepCacheEntry(context.Background(), types.Job{ID: 42, Token: "job-token"}, key)
+		require.NoError(t, err)
+		require.True(t, ok)
+		defer rc.Close()
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		assert.Equal(t, "<archive>", string(content))
+	})
+}
+
+func TestGetStepCacheEntryMiss(t *testing.T) {
+	const key = "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+
+	spec := routeSpec{
+		expectedMethod:       "GET",
+		expectedPath:         "/.executors/queue/test_queue/stepCache/" + key,
+		expectedUsername:     "test",
+		expectedToken:        "job-token",
+		expectedJobID:        "42",
+		expectedExecutorName: "deadbeef",
+		expectedPayload:      ``,
+		responseStatus:       http.StatusNotFound,
+		responsePayload:      ``,
+	}
+
+	testRoute(t, spec, func(client *queue.Client) {
+		_, ok, err := client.GetStepCacheEntry(context.Background(), types.Job{ID: 42, Token: "job-token"}, key)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestPutStepCacheEntry(t *testing.T) {
+	const key = "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+
+	spec := routeSpec{
+		expectedMethod:       "PUT",
+		expectedPath:         "/.executors/queue/test_queue/stepCache/" + key,
+		expectedUsername:     "test",
+		expectedToken:        "job-token",
+		expectedJobID:        "42",
+		expectedExecutorName: "deadbeef",
+		expectedPayload:      `{"archive": true}`,
+		responseStatus:       http.StatusNoContent,
+		responsePayload:      ``,
+	}
+
+	testRoute(t, spec, func(client *queue.Client) {
+		err := client.PutStepCacheEntry(context.Background(), types.Job{ID: 42, Token: "job-token"}, key, strings.NewReader(`{"archive": true}`))
+		require.NoError(t, err)
+	})
+}
+
+func TestPutStepCacheEntryBadResponse(t *testing.T) {
+	const key = "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+
+	spec := routeSpec{
+		expectedMethod:       "PUT",
+		expectedPath:         "/.executors/queue/test_queue/stepCache/" + key,
+		expectedUsername:     "test",
+		expectedToken:        "job-token",
+		expectedJobID:        "42",
+		expectedExecutorName: "deadbeef",
+		expectedPayload:      `{"archive": true}`,
+		responseStatus:       http.StatusInternalServerError,
+		responsePayload:      ``,
+	}
+
+	testRoute(t, spec, func(client *queue.Client) {
+		err := client.PutStepCacheEntry(context.Background(), types.Job{ID: 42, Token: "job-token"}, key, strings.NewReader(`{"archive": true}`))
+		assert.Error(t, err)
+	})
+}
+
 type routeSpec struct {
 	expectedMethod       string
 	expectedPath         string
@@ -15,6 +15,8 @@ type operations struct {
 	heartbeat               *observation.Operation
 	addExecutionLogEntry    *observation.Operation
 	updateExecutionLogEntry *observation.Operation
+	getStepCacheEntry       *observation.Operation
+	putStepCacheEntry       *observation.Operation
 }
 
 func newOperations(observationCtx *observation.Context) *operations {
@@ -41,5 +43,7 @@ func newOperations(observationCtx *observation.Context) *operations {
 		heartbeat:               op("Heartbeat"),
 		addExecutionLogEntry:    op("AddExecutionLogEntry"),
 		updateExecutionLogEntry: op("UpdateExecutionLogEntry"),
+		getStepCacheEntry:       op("GetStepCacheEntry"),
+		putStepCacheEntry:       op("PutStepCacheEntry"),
 	}
 }
@@ -3,6 +3,7 @@ package config
 import (
 	"encoding/json"
 	"net/url"
+	"os"
 	"path/filepath"
 	"runtime"
 	"strconv"
@@ -54,6 +55,12 @@ type Config struct {
 	DockerRegistryMirrorURL                        string
 	DockerAddHostGateway                           bool
 	DockerAuthConfig                               types.DockerAuthConfig
+	UsePodman                                      bool
+	PodmanUserNamespace                            string
+	PodmanSELinuxLabel                             string
+	UseRepoCache                                   bool
+	RepoCacheDir                                   string
+	RepoCacheMaxSize                               string
 	KubernetesConfigPath                           string
 	KubernetesNodeName                             string
 	KubernetesNodeSelector                         string
@@ -117,7 +124,13 @@ func (c *Config) Load() {
 	c.QueueNamesStr = c.GetOptional("EXECUTOR_QUEUE_NAMES", "The names of multiple queues to listen to, comma-separated.")
 	c.QueuePollInterval = c.GetInterval("EXECUTOR_QUEUE_POLL_INTERVAL", "1s", "Interval between dequeue requests.")
 	c.MaximumNumJobs = c.GetInt("EXECUTOR_MAXIMUM_NUM_JOBS", "1", "Number of virtual machines or containers that can be running at once.")
-	c.UseFirecracker = c.GetBool("EXECUTOR_USE_FIRECRACKER", strconv.FormatBool(runtime.GOOS == "linux" && !IsKubernetes()), "Whether to isolate commands in virtual machines. Requires ignite and firecracker. Linux hosts only. Kubernetes is not supported.")
+	c.UsePodman = c.GetBool("EXECUTOR_USE_PODMAN", "false", "Whether to run commands in containers using podman instead of docker. Supports rootless podman. Kubernetes and firecracker are not supported.")
+	c.PodmanUserNamespace = c.Get("EXECUTOR_PODMAN_USERNS", "keep-id", "The user namespace mode podman containers are run in. The default keep-id maps the executor user into the container, so that rootless containers can write to the workspace.")
+	c.PodmanSELinuxLabel = c.GetOptional("EXECUTOR_PODMAN_SELINUX_LABEL", "The SELinux label applied to the workspace volume mount of podman containers. Set to 'z' or 'Z' on hosts with SELinux in enforcing mode.")
+	c.UseRepoCache = c.GetBool("EXECUTOR_USE_REPO_CACHE", "false", "Whether to keep a cache of bare repositories on the host and create job workspaces from them, so that only new commits need to be fetched. Only supported with docker, podman and shell runtimes.")
+	c.RepoCacheDir = c.Get("EXECUTOR_REPO_CACHE_DIR", filepath.Join(os.TempDir(), "executor-repo-cache"), "The directory on the host the repository cache is stored in.")
+	c.RepoCacheMaxSize = c.Get("EXECUTOR_REPO_CACHE_MAX_SIZE", "50G", "The disk budget of the repository cache. The least recently used repositories are evicted by the periodic cleanup task once it is exceeded.")
+	c.UseFirecracker = c.GetBool("EXECUTOR_USE_FIRECRACKER", strconv.FormatBool(runtime.GOOS == "linux" && !IsKubernetes() && !c.UsePodman), "Whether to isolate commands in virtual machines. Requires ignite and firecracker. Linux hosts only. Kubernetes is not supported.")
 	c.FirecrackerImage = c.Get("EXECUTOR_FIRECRACKER_IMAGE", DefaultFirecrackerImage, "The base image to use for virtual machines.")
 	c.FirecrackerKernelImage = c.Get("EXECUTOR_FIRECRACKER_KERNEL_IMAGE", DefaultFirecrackerKernelImage, "The base image containing the kernel binary to use for virtual machines.")
 	c.FirecrackerSandboxImage = c.Get("EXECUTOR_FIRECRACKER_SANDBOX_IMAGE", DefaultFirecrackerSandboxImage, "The OCI image for the ignite VM sandbox.")
@@ -331,6 +344,35 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.UsePodman {
+		if c.UseFirecracker {
+			c.AddError(errors.New("EXECUTOR_USE_PODMAN and EXECUTOR_USE_FIRECRACKER cannot both be enabled"))
+		}
+		if IsKubernetes() {
+			c.AddError(errors.New("EXECUTOR_USE_PODMAN is not supported when running in Kubernetes"))
+		}
+		if c.PodmanSELinuxLabel != "" && c.PodmanSELinuxLabel != "z" && c.PodmanSELinuxLabel != "Z" {
+			c.AddError(errors.New("invalid EXECUTOR_PODMAN_SELINUX_LABEL, valid values are 'z' and 'Z'"))
+		}
+	}
+
+	if c.UseRepoCache {
+		if c.UseFirecracker {
+			c.AddError(errors.New("EXECUTOR_USE_REPO_CACHE is not supported when EXECUTOR_USE_FIRECRACKER is enabled"))
+		}
+		if IsKubernetes() {
+			c.AddError(errors.New("EXECUTOR_USE_REPO_CACHE is not supported when running in Kubernetes"))
+		}
+		// Workspaces reference the cache by its path on the executor host, which
+		// isn't the path on the docker host in docker-in-docker setups.
+		if c.DockerHostMountPath != "" {
+			c.AddError(errors.New("EXECUTOR_USE_REPO_CACHE is not supported when EXECUTOR_DOCKER_HOST_MOUNT_PATH is set"))
+		}
+		if _, err := datasize.ParseString(c.RepoCacheMaxSize); err != nil {
+			c.AddError(errors.Wrapf(err, "invalid size provided for EXECUTOR_REPO_CACHE_MAX_SIZE: %q", c.RepoCacheMaxSize))
+		}
+	}
+
 	if len(c.KubernetesNodeSelector) > 0 {
 		nodeSelectorValues := strings.Split(c.KubernetesNodeSelector, ",")
 		for _, value := range nodeSelectorValues {
@@ -1,6 +1,8 @@
 package config_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -210,6 +212,9 @@ func TestConfig_Load_Defaults(t *testing.T) {
 	assert.Equal(t, 524288000, cfg.FirecrackerBandwidthEgress)
 	assert.Equal(t, 30*time.Minute, cfg.MaximumRuntimePerJob)
 	assert.Equal(t, 1*time.Minute, cfg.CleanupTaskInterval)
+	assert.False(t, cfg.UseRepoCache)
+	assert.Equal(t, filepath.Join(os.TempDir(), "executor-repo-cache"), cfg.RepoCacheDir)
+	assert.Equal(t, "50G", cfg.RepoCacheMaxSize)
 	assert.Zero(t, cfg.NumTotalJobs)
 	assert.Empty(t, cfg.NodeExporterURL)
 	assert.Empty(t, cfg.DockerRegistryNodeExporterURL)
@@ -348,6 +353,94 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			expectedErr: errors.New("EXECUTOR_QUEUE_NAMES contains invalid queue name 'batches;codeintel', valid names are 'batches, codeintel' and should be comma-separated"),
 		},
+		{
+			name: "Podman and firecracker both enabled",
+			getterFunc: func(name string, defaultValue, description string) string {
+				switch name {
+				case "EXECUTOR_QUEUE_NAME":
+					return "batches"
+				case "EXECUTOR_FRONTEND_URL":
+					return "http://some-url.com"
+				case "EXECUTOR_FRONTEND_PASSWORD":
+					return "some-password"
+				case "EXECUTOR_USE_PODMAN":
+					return "true"
+				case "EXECUTOR_USE_FIRECRACKER":
+					return "true"
+				case "EXECUTOR_JOB_NUM_CPUS":
+					return "4"
+				case "EXECUTOR_FIRECRACKER_DISK_SPACE":
+					return "20G"
+				default:
+					return defaultValue
+				}
+			},
+			expectedErr: errors.New("EXECUTOR_USE_PODMAN and EXECUTOR_USE_FIRECRACKER cannot both be enabled"),
+		},
+		{
+			name: "Invalid EXECUTOR_PODMAN_SELINUX_LABEL",
+			getterFunc: func(name string, defaultValue, description string) string {
+				switch name {
+				case "EXECUTOR_QUEUE_NAME":
+					return "batches"
+				case "EXECUTOR_FRONTEND_URL":
+					return "http://some-url.com"
+				case "EXECUTOR_FRONTEND_PASSWORD":
+					return "some-password"
+				case "EXECUTOR_USE_PODMAN":
+					return "true"
+				case "EXECUTOR_PODMAN_SELINUX_LABEL":
+					return "shared"
+				default:
+					return defaultValue
+				}
+			},
+			expectedErr: errors.New("invalid EXECUTOR_PODMAN_SELINUX_LABEL, valid values are 'z' and 'Z'"),
+		},
+		{
+			name: "Repo cache with docker host mount path",
+			getterFunc: func(name string, defaultValue, description string) string {
+				switch name {
+				case "EXECUTOR_QUEUE_NAME":
+					return "codeintel"
+				case "EXECUTOR_FRONTEND_URL":
+					return "http://some-url.com"
+				case "EXECUTOR_FRONTEND_PASSWORD":
+					return "some-password"
+				case "EXECUTOR_USE_FIRECRACKER":
+					return "false"
+				case "EXECUTOR_USE_REPO_CACHE":
+					return "true"
+				case "EXECUTOR_DOCKER_HOST_MOUNT_PATH":
+					return "/docker/host/mount/path"
+				default:
+					return defaultValue
+				}
+			},
+			expectedErr: errors.New("EXECUTOR_USE_REPO_CACHE is not supported when EXECUTOR_DOCKER_HOST_MOUNT_PATH is set"),
+		},
+		{
+			name: "Invalid EXECUTOR_REPO_CACHE_MAX_SIZE",
+			getterFunc: func(name string, defaultValue, description string) string {
+				switch name {
+				case "EXECUTOR_QUEUE_NAME":
+					return "codeintel"
+				case "EXECUTOR_FRONTEND_URL":
+					return "http://some-url.com"
+				case "EXECUTOR_FRONTEND_PASSWORD":
+					return "some-password"
+				case "EXECUTOR_USE_FIRECRACKER":
+					return "false"
+				case "EXECUTOR_USE_REPO_CACHE":
+					return "true"
+				case "EXECUTOR_REPO_CACHE_MAX_SIZE":
+					return "lots"
+				default:
+					return defaultValue
+				}
+			},
+			expectedErr: errors.New("invalid size provided for EXECUTOR_REPO_CACHE_MAX_SIZE: \"lots\": strconv.UnmarshalText: parsing \"lots\": invalid syntax"),
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
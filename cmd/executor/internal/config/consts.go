@@ -71,6 +71,14 @@ var (
 		"git":    "Use your package manager, or build from source.",
 		"src":    "Run executor install src-cli, or refer to https://github.com/sourcegraph/src-cli to install src-cli yourself.",
 	}
+	// RequiredCLIToolsPodman contains all the programs that are expected to exist
+	// in PATH when running the executor with podman enabled and a help text on
+	// installation.
+	RequiredCLIToolsPodman = map[string]string{
+		"podman": "Check out https://podman.io/docs/installation on how to install.",
+		"git":    "Use your package manager, or build from source.",
+		"src":    "Run executor install src-cli, or refer to https://github.com/sourcegraph/src-cli to install src-cli yourself.",
+	}
 	// RequiredCLIToolsFirecracker contains all the programs that are expected to
 	// exist in PATH when running the executor with firecracker enabled.
 	RequiredCLIToolsFirecracker = []string{"dmsetup", "losetup", "mkfs.ext4", "strings"}
@@ -80,6 +88,9 @@ var (
 	CNISubnetCIDR = mustParseCIDR("10.61.0.0/16")
 	// MinGitVersionConstraint is the minimum version of git required by the executor.
 	MinGitVersionConstraint = mustParseConstraint(">= 2.26")
+	// MinPodmanVersionConstraint is the minimum version of podman required by the
+	// executor when using the podman runtime.
+	MinPodmanVersionConstraint = mustParseConstraint(">= 4.0")
 )
 
 func mustParseConstraint(constraint string) *semver.Constraints {
@@ -0,0 +1,63 @@
+package janitor
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+)
+
+// RepoCache is the cache of bare repositories shared by the workspaces of this
+// executor instance.
+type RepoCache interface {
+	// Evict removes unused repositories until the cache fits into its disk budget.
+	Evict(ctx context.Context) (evicted int, freed int64, err error)
+}
+
+type repoCacheJanitor struct {
+	logger  log.Logger
+	cache   RepoCache
+	metrics *metrics
+}
+
+var (
+	_ goroutine.Handler      = &repoCacheJanitor{}
+	_ goroutine.ErrorHandler = &repoCacheJanitor{}
+)
+
+// NewRepoCacheJanitor returns a background routine that periodically evicts the least
+// recently used repositories from the repository cache until it fits into its disk budget.
+func NewRepoCacheJanitor(
+	logger log.Logger,
+	cache RepoCache,
+	interval time.Duration,
+	metrics *metrics,
+) goroutine.BackgroundRoutine {
+	return goroutine.NewPeriodicGoroutine(
+		context.Background(),
+		&repoCacheJanitor{
+			logger:  logger,
+			cache:   cache,
+			metrics: metrics,
+		},
+		goroutine.WithName("executors.repo-cache-janitor"),
+		goroutine.WithDescription("evicts repositories from the executor repository cache"),
+		goroutine.WithInterval(interval),
+	)
+}
+
+func (j *repoCacheJanitor) Handle(ctx context.Context) error {
+	evicted, freed, err := j.cache.Evict(ctx)
+	if evicted > 0 {
+		j.logger.Info("Evicted repositories from cache", log.Int("evicted", evicted), log.Int64("freedBytes", freed))
+		j.metrics.numReposEvicted.Add(float64(evicted))
+	}
+	return err
+}
+
+func (j *repoCacheJanitor) HandleError(err error) {
+	j.metrics.numErrors.Inc()
+	j.logger.Error("Failed to evict repositories from cache", log.Error(err))
+}
@@ -41,7 +41,7 @@ func StandaloneRun(ctx context.Context, runner util.CmdRunner, logger log.Logger
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		return newQueueTelemetryOptions(ctx, runner, cfg.UseFirecracker, logger)
+		return newQueueTelemetryOptions(ctx, runner, cfg.UseFirecracker, cfg.UsePodman, logger)
 	}()
 	logger.Debug("Telemetry information gathered", log.String("info", fmt.Sprintf("%+v", queueTelemetryOptions)))
 
@@ -50,7 +50,7 @@ func StandaloneRun(ctx context.Context, runner util.CmdRunner, logger log.Logger
 	// TODO: This is too similar to the RunValidate func. Make it share even more code.
 	if runVerifyChecks {
 		// Then, validate all tools that are required are installed.
-		if err := util.ValidateRequiredTools(runner, cfg.UseFirecracker); err != nil {
+		if err := util.ValidateRequiredTools(runner, cfg.UseFirecracker, cfg.UsePodman); err != nil {
 			return err
 		}
 
@@ -101,17 +101,30 @@ func StandaloneRun(ctx context.Context, runner util.CmdRunner, logger log.Logger
 
 	routines := []goroutine.BackgroundRoutine{wrk}
 
-	if cfg.UseFirecracker {
-		routines = append(routines, janitor.NewOrphanedVMJanitor(
-			log.Scoped("orphaned-vm-janitor"),
-			cfg.VMPrefix,
-			nameSet,
-			cfg.CleanupTaskInterval,
-			janitor.NewMetrics(observationCtx),
-			runner,
-		))
-
-		mustRegisterVMCountMetric(observationCtx, runner, logger, cfg.VMPrefix)
+	if cfg.UseFirecracker || opts.RepoCache != nil {
+		janitorMetrics := janitor.NewMetrics(observationCtx)
+
+		if cfg.UseFirecracker {
+			routines = append(routines, janitor.NewOrphanedVMJanitor(
+				log.Scoped("orphaned-vm-janitor"),
+				cfg.VMPrefix,
+				nameSet,
+				cfg.CleanupTaskInterval,
+				janitorMetrics,
+				runner,
+			))
+
+			mustRegisterVMCountMetric(observationCtx, runner, logger, cfg.VMPrefix)
+		}
+
+		if opts.RepoCache != nil {
+			routines = append(routines, janitor.NewRepoCacheJanitor(
+				log.Scoped("repo-cache-janitor"),
+				opts.RepoCache,
+				cfg.CleanupTaskInterval,
+				janitorMetrics,
+			))
+		}
 	}
 
 	go func() {
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/c2h5oh/datasize"
 	"github.com/sourcegraph/log"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -18,6 +19,7 @@ import (
 	apiworker "github.com/sourcegraph/sourcegraph/cmd/executor/internal/worker"
 	"github.com/sourcegraph/sourcegraph/cmd/executor/internal/worker/command"
 	"github.com/sourcegraph/sourcegraph/cmd/executor/internal/worker/runner"
+	"github.com/sourcegraph/sourcegraph/cmd/executor/internal/worker/workspace"
 	executorutil "github.com/sourcegraph/sourcegraph/internal/executor/util"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/version"
@@ -25,7 +27,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/lib/pointers"
 )
 
-func newQueueTelemetryOptions(ctx context.Context, runner util.CmdRunner, useFirecracker bool, logger log.Logger) queue.TelemetryOptions {
+func newQueueTelemetryOptions(ctx context.Context, runner util.CmdRunner, useFirecracker, usePodman bool, logger log.Logger) queue.TelemetryOptions {
 	t := queue.TelemetryOptions{
 		OS:              runtime.GOOS,
 		Architecture:    runtime.GOARCH,
@@ -45,9 +47,11 @@ func newQueueTelemetryOptions(ctx context.Context, runner util.CmdRunner, useFir
 			logger.Error("Failed to get src-cli version", log.Error(err))
 		}
 
-		t.DockerVersion, err = util.GetDockerVersion(ctx, runner)
-		if err != nil {
-			logger.Error("Failed to get docker version", log.Error(err))
+		if !usePodman {
+			t.DockerVersion, err = util.GetDockerVersion(ctx, runner)
+			if err != nil {
+				logger.Error("Failed to get docker version", log.Error(err))
+			}
 		}
 	}
 
@@ -71,7 +75,9 @@ func apiWorkerOptions(c *config.Config, queueTelemetryOptions queue.TelemetryOpt
 			DockerOptions:      dockerOptions(c),
 			FirecrackerOptions: firecrackerOptions(c),
 			KubernetesOptions:  kubernetesOptions(c),
+			PodmanOptions:      podmanOptions(c),
 		},
+		RepoCache:      repoCache(c),
 		GitServicePath: "/.executors/git",
 		QueueOptions:   queueOptions(c, queueTelemetryOptions),
 		FilesOptions:   filesOptions(c),
@@ -101,6 +107,15 @@ func workerOptions(c *config.Config) workerutil.WorkerOptions {
 	}
 }
 
+func repoCache(c *config.Config) *workspace.RepoCache {
+	if !c.UseRepoCache {
+		return nil
+	}
+	// Validated in config.Validate.
+	maxSize, _ := datasize.ParseString(c.RepoCacheMaxSize)
+	return workspace.NewRepoCache(c.RepoCacheDir, int64(maxSize.Bytes()))
+}
+
 func dockerOptions(c *config.Config) command.DockerOptions {
 	return command.DockerOptions{
 		DockerAuthConfig: c.DockerAuthConfig,
@@ -109,6 +124,17 @@ func dockerOptions(c *config.Config) command.DockerOptions {
 	}
 }
 
+func podmanOptions(c *config.Config) command.PodmanOptions {
+	return command.PodmanOptions{
+		Enabled:          c.UsePodman,
+		DockerAuthConfig: c.DockerAuthConfig,
+		AddHostGateway:   c.DockerAddHostGateway,
+		UserNamespace:    c.PodmanUserNamespace,
+		SELinuxLabel:     c.PodmanSELinuxLabel,
+		Resources:        resourceOptions(c),
+	}
+}
+
 func firecrackerOptions(c *config.Config) runner.FirecrackerOptions {
 	var dockerMirrors []string
 	if len(c.DockerRegistryMirrorURL) > 0 {
@@ -25,7 +25,7 @@ func Validate(cliCtx *cli.Context, runner util.CmdRunner, logger log.Logger, con
 		return err
 	}
 
-	telemetryOptions := newQueueTelemetryOptions(cliCtx.Context, runner, conf.UseFirecracker, logger)
+	telemetryOptions := newQueueTelemetryOptions(cliCtx.Context, runner, conf.UseFirecracker, conf.UsePodman, logger)
 	copts := queueOptions(conf, telemetryOptions)
 	client, err := apiclient.NewBaseClient(logger, copts.BaseClientOptions)
 	if err != nil {
@@ -34,10 +34,17 @@ func Validate(cliCtx *cli.Context, runner util.CmdRunner, logger log.Logger, con
 
 	if !config.IsKubernetes() {
 		// Then, validate all tools that are required are installed.
-		if err = util.ValidateRequiredTools(runner, conf.UseFirecracker); err != nil {
+		if err = util.ValidateRequiredTools(runner, conf.UseFirecracker, conf.UsePodman); err != nil {
 			return err
 		}
 
+		if conf.UsePodman {
+			// Validate podman is recent enough to support the flags we rely on.
+			if err = util.ValidatePodmanVersion(cliCtx.Context, runner); err != nil {
+				return err
+			}
+		}
+
 		// Validate src-cli is of a good version, rely on the connected instance to tell
 		// us what "good" means.
 		if err = util.ValidateSrcCLIVersion(cliCtx.Context, runner, client); err != nil {
@@ -28,6 +28,12 @@ func GetDockerVersion(ctx context.Context, runner CmdRunner) (string, error) {
 	return execOutput(ctx, runner, "docker", "version", "-f", "{{.Server.Version}}")
 }
 
+// GetPodmanVersion returns the version of podman installed on the host. Rootless
+// podman doesn't have a server, so the client version is used.
+func GetPodmanVersion(ctx context.Context, runner CmdRunner) (string, error) {
+	return execOutput(ctx, runner, "podman", "version", "-f", "{{.Client.Version}}")
+}
+
 // GetIgniteVersion returns the version of ignite installed on the host.
 func GetIgniteVersion(ctx context.Context, runner CmdRunner) (string, error) {
 	return execOutput(ctx, runner, "ignite", "version", "-o", "short")
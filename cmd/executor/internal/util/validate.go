@@ -69,9 +69,29 @@ func ValidateSrcCLIVersion(ctx context.Context, runner CmdRunner, client *apicli
 // ErrSrcPatchBehind is the specific error if the currently installed src version is a patch behind the latest version.
 var ErrSrcPatchBehind = errors.New("installed src-cli is not the latest version")
 
-// ValidateRequiredTools validates that the tools required to run Docker and/or Firecracker are installed.
-func ValidateRequiredTools(runner CmdRunner, useFirecracker bool) error {
-	if err := ValidateDockerTools(runner); err != nil {
+// ValidatePodmanVersion validates that installed podman version meets the minimum version.
+func ValidatePodmanVersion(ctx context.Context, runner CmdRunner) error {
+	podmanVersion, err := GetPodmanVersion(ctx, runner)
+	if err != nil {
+		return errors.Wrap(err, "getting podman version")
+	}
+	have, err := semver.NewVersion(podmanVersion)
+	if err != nil {
+		return errors.Newf("failed to semver parse podman version: %s", podmanVersion)
+	} else if !config.MinPodmanVersionConstraint.Check(have) {
+		return errors.Newf("podman version is too old, install at least podman 4.0, current version: %s", podmanVersion)
+	}
+	return nil
+}
+
+// ValidateRequiredTools validates that the tools required to run Docker or Podman
+// and/or Firecracker are installed.
+func ValidateRequiredTools(runner CmdRunner, useFirecracker, usePodman bool) error {
+	if usePodman {
+		if err := ValidatePodmanTools(runner); err != nil {
+			return err
+		}
+	} else if err := ValidateDockerTools(runner); err != nil {
 		return err
 	}
 	if useFirecracker {
@@ -84,11 +104,20 @@ func ValidateRequiredTools(runner CmdRunner, useFirecracker bool) error {
 
 // ValidateDockerTools validates that the tools required to run Docker are installed.
 func ValidateDockerTools(runner CmdRunner) error {
+	return validateTools(runner, config.RequiredCLITools)
+}
+
+// ValidatePodmanTools validates that the tools required to run Podman are installed.
+func ValidatePodmanTools(runner CmdRunner) error {
+	return validateTools(runner, config.RequiredCLIToolsPodman)
+}
+
+func validateTools(runner CmdRunner, requiredTools map[string]string) error {
 	var missingTools []string
 	// So, iterating thru a map is not deterministic, breaking unit tests, so we need to sort the keys.
-	tools := make([]string, len(config.RequiredCLITools))
+	tools := make([]string, len(requiredTools))
 	i := 0
-	for t := range config.RequiredCLITools {
+	for t := range requiredTools {
 		tools[i] = t
 		i++
 	}
@@ -212,6 +241,9 @@ func (e *ErrMissingTools) Error() string {
 	var errs error
 	for _, tool := range e.Tools {
 		helpText, ok := config.RequiredCLITools[tool]
+		if !ok {
+			helpText, ok = config.RequiredCLIToolsPodman[tool]
+		}
 		// TODO: Help lines for config.RequiredCLIToolsFirecracker.
 		helpLine := ""
 		if ok {
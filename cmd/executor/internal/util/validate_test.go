@@ -274,6 +274,129 @@ func TestValidateDockerTools(t *testing.T) {
 	}
 }
 
+func TestValidatePodmanTools(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		mockFunc    func(runner *fakeCmdRunner)
+		expectedErr error
+	}{
+		{
+			name: "Podman is valid",
+			mockFunc: func(runner *fakeCmdRunner) {
+				runner.On("LookPath", "git").
+					Return("", nil)
+				runner.On("LookPath", "podman").
+					Return("", nil)
+				runner.On("LookPath", "src").
+					Return("", nil)
+			},
+		},
+		{
+			name: "Podman missing",
+			mockFunc: func(runner *fakeCmdRunner) {
+				runner.On("LookPath", "git").
+					Return("", nil)
+				runner.On("LookPath", "podman").
+					Return("", exec.ErrNotFound)
+				runner.On("LookPath", "src").
+					Return("", nil)
+			},
+			expectedErr: errors.New("podman not found in PATH, is it installed?\nCheck out https://podman.io/docs/installation on how to install."),
+		},
+		{
+			name: "Podman error",
+			mockFunc: func(runner *fakeCmdRunner) {
+				runner.On("LookPath", "git").
+					Return("", nil)
+				runner.On("LookPath", "podman").
+					Return("", errors.New("failed to find podman"))
+			},
+			expectedErr: errors.New("failed to find podman"),
+		},
+		{
+			name: "Docker is not required",
+			mockFunc: func(runner *fakeCmdRunner) {
+				runner.On("LookPath", "docker").
+					Return("", exec.ErrNotFound)
+				runner.On("LookPath", "git").
+					Return("", nil)
+				runner.On("LookPath", "podman").
+					Return("", nil)
+				runner.On("LookPath", "src").
+					Return("", nil)
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			runner := new(fakeCmdRunner)
+			if test.mockFunc != nil {
+				test.mockFunc(runner)
+			}
+
+			err := util.ValidatePodmanTools(runner)
+			if test.expectedErr != nil {
+				require.Error(t, err)
+				assert.EqualError(t, err, test.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidatePodmanVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		exitStatus  int
+		stdout      string
+		expectedErr error
+	}{
+		{
+			name:       "Version is minimum",
+			exitStatus: 0,
+			stdout:     "4.0.0",
+		},
+		{
+			name:        "Version is below minimum",
+			exitStatus:  0,
+			stdout:      "3.4.4",
+			expectedErr: errors.New("podman version is too old, install at least podman 4.0, current version: 3.4.4"),
+		},
+		{
+			name:        "Failed to parse version",
+			exitStatus:  0,
+			stdout:      "",
+			expectedErr: errors.New("failed to semver parse podman version: "),
+		},
+		{
+			name:        "Failed to get version",
+			exitStatus:  1,
+			stdout:      "failed to get version",
+			expectedErr: errors.New("getting podman version: 'podman version -f {{.Client.Version}}': failed to get version: exit status 1"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			runner := new(fakeCmdRunner)
+			runner.On("CombinedOutput", mock.Anything, "podman", []string{"version", "-f", "{{.Client.Version}}"}).
+				Return(test.exitStatus, test.stdout)
+
+			err := util.ValidatePodmanVersion(context.Background(), runner)
+			if test.expectedErr != nil {
+				require.Error(t, err)
+				assert.EqualError(t, err, test.expectedErr.Error())
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestValidateFirecrackerTools(t *testing.T) {
 	t.Parallel()
 
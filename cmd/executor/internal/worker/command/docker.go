@@ -15,6 +15,10 @@ type DockerOptions struct {
 	ConfigPath       string
 	AddHostGateway   bool
 	Resources        ResourceOptions
+	// GitDir is the path of the bare repository the workspace repository borrows
+	// its objects from, if any. It is mounted read-only at the same path into the
+	// container, as the workspace repository references it by its absolute path.
+	GitDir string
 }
 
 // ResourceOptions are the resource limits that can be applied to a container or VM.
@@ -81,6 +85,7 @@ func formatDockerCommand(hostDir string, image string, scriptPath string, spec S
 		dockerHostGatewayFlag(options.AddHostGateway),
 		dockerResourceFlags(options.Resources),
 		dockerVolumeFlags(hostDir),
+		dockerGitDirVolumeFlags(options.GitDir, ""),
 		dockerWorkingDirectoryFlags(spec.Dir),
 		dockerEnvFlags(spec.Env),
 		dockerEntrypointFlags,
@@ -119,6 +124,18 @@ func dockerVolumeFlags(wd string) []string {
 	return []string{"-v", wd + ":/data"}
 }
 
+// dockerGitDirVolumeFlags mounts the bare repository read-only, so that a job
+// can't change the repository other jobs and the executor itself use.
+func dockerGitDirVolumeFlags(gitDir string, selinuxLabel string) []string {
+	if gitDir == "" {
+		return nil
+	}
+	if selinuxLabel == "" {
+		return []string{"-v", gitDir + ":" + gitDir + ":ro"}
+	}
+	return []string{"-v", gitDir + ":" + gitDir + ":ro," + selinuxLabel}
+}
+
 func dockerConfigFlag(dockerConfigPath string) []string {
 	if dockerConfigPath == "" {
 		return nil
@@ -83,6 +83,41 @@ func TestNewDockerSpec(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:       "Git dir",
+			workingDir: "/workingDirectory",
+			image:      "some-image",
+			scriptPath: "some/path",
+			spec: command.Spec{
+				Key:     "some-key",
+				Command: []string{"some", "command"},
+				Dir:     "/some/dir",
+				Env:     []string{"FOO=BAR"},
+			},
+			options: command.DockerOptions{
+				GitDir: "/repo-cache/abc.git",
+			},
+			expectedSpec: command.Spec{
+				Key: "some-key",
+				Command: []string{
+					"docker",
+					"run",
+					"--rm",
+					"-v",
+					"/workingDirectory:/data",
+					"-v",
+					"/repo-cache/abc.git:/repo-cache/abc.git:ro",
+					"-w",
+					"/data/some/dir",
+					"-e",
+					"FOO=BAR",
+					"--entrypoint",
+					"/bin/sh",
+					"some-image",
+					"/data/.sourcegraph-executor/some/path",
+				},
+			},
+		},
 		{
 			name:       "Config Path",
 			workingDir: "/workingDirectory",
@@ -22,6 +22,7 @@ type Operations struct {
 
 	SetupFirecrackerStart     *observation.Operation
 	TeardownFirecrackerRemove *observation.Operation
+	TeardownGitRepack         *observation.Operation
 
 	Exec *observation.Operation
 
@@ -79,6 +80,7 @@ func NewOperations(observationCtx *observation.Context) *Operations {
 
 		SetupFirecrackerStart:     op("setup.firecracker.start"),
 		TeardownFirecrackerRemove: op("teardown.firecracker.remove"),
+		TeardownGitRepack:         op("teardown.git.repack"),
 
 		Exec: op("exec"),
 
@@ -0,0 +1,114 @@
+package command
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/sourcegraph/sourcegraph/cmd/executor/internal/worker/files"
+	"github.com/sourcegraph/sourcegraph/internal/executor/types"
+)
+
+// PodmanOptions are the options that are specific to running a container with podman.
+type PodmanOptions struct {
+	// Enabled determines if podman should be used instead of docker.
+	Enabled          bool
+	DockerAuthConfig types.DockerAuthConfig
+	// AuthFilePath is the path to the registry auth file passed to podman. This is
+	// set by the runner if registry credentials are configured.
+	AuthFilePath   string
+	AddHostGateway bool
+	// UserNamespace is the user namespace mode the container is run in, e.g. keep-id.
+	// When keep-id is used in rootless mode, files written to the workspace are owned
+	// by the user running the executor instead of a subordinate UID.
+	UserNamespace string
+	// SELinuxLabel is the SELinux relabeling option applied to the workspace volume
+	// mount. Either "z" (shared) or "Z" (private). Required on hosts with SELinux in
+	// enforcing mode, otherwise the container can't access the workspace.
+	SELinuxLabel string
+	Resources    ResourceOptions
+	// GitDir is the path of the bare repository the workspace repository borrows
+	// its objects from, if any. It is mounted read-only at the same path into the
+	// container.
+	GitDir string
+}
+
+// NewPodmanSpec constructs the command to run on the host in order to invoke the
+// given spec. If the spec does not specify an image, then the command will be run
+// _directly_ on the host. Otherwise, the command will be run inside a one-shot
+// podman container subject to the resource limits specified in the given options.
+func NewPodmanSpec(workingDir string, image string, scriptPath string, spec Spec, options PodmanOptions) Spec {
+	// TODO - remove this once src-cli is not required anymore for SSBC.
+	if image == "" {
+		env := spec.Env
+		if options.AuthFilePath != "" {
+			env = append(env, fmt.Sprintf("REGISTRY_AUTH_FILE=%s", options.AuthFilePath))
+		}
+		return Spec{
+			Key:       spec.Key,
+			Command:   spec.Command,
+			Dir:       filepath.Join(workingDir, spec.Dir),
+			Env:       env,
+			Operation: spec.Operation,
+		}
+	}
+
+	hostDir := workingDir
+	if options.Resources.DockerHostMountPath != "" {
+		hostDir = filepath.Join(options.Resources.DockerHostMountPath, filepath.Base(workingDir))
+	}
+
+	return Spec{
+		Key:       spec.Key,
+		Command:   formatPodmanCommand(hostDir, image, scriptPath, spec, options),
+		Operation: spec.Operation,
+	}
+}
+
+func formatPodmanCommand(hostDir string, image string, scriptPath string, spec Spec, options PodmanOptions) []string {
+	return Flatten(
+		"podman",
+		"run",
+		"--rm",
+		podmanAuthFileFlag(options.AuthFilePath),
+		podmanUserNamespaceFlag(options.UserNamespace),
+		dockerHostGatewayFlag(options.AddHostGateway),
+		dockerResourceFlags(options.Resources),
+		podmanVolumeFlags(hostDir, options.SELinuxLabel),
+		podmanGitDirVolumeFlags(options.GitDir, options.SELinuxLabel),
+		dockerWorkingDirectoryFlags(spec.Dir),
+		dockerEnvFlags(spec.Env),
+		dockerEntrypointFlags,
+		image,
+		filepath.Join("/data", files.ScriptsPath, scriptPath),
+	)
+}
+
+func podmanAuthFileFlag(authFilePath string) []string {
+	if authFilePath == "" {
+		return nil
+	}
+	return []string{"--authfile", authFilePath}
+}
+
+func podmanUserNamespaceFlag(userNamespace string) []string {
+	if userNamespace == "" {
+		return nil
+	}
+	return []string{"--userns=" + userNamespace}
+}
+
+func podmanVolumeFlags(wd string, selinuxLabel string) []string {
+	if selinuxLabel == "" {
+		return dockerVolumeFlags(wd)
+	}
+	return []string{"-v", wd + ":/data:" + selinuxLabel}
+}
+
+func podmanGitDirVolumeFlags(gitDir string, selinuxLabel string) []string {
+	// The bare repository can be shared by concurrently running jobs, so it must
+	// never get a private label.
+	if selinuxLabel != "" {
+		selinuxLabel = "z"
+	}
+	return dockerGitDirVolumeFlags(gitDir, selinuxLabel)
+}
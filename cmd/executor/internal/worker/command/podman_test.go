@@ -0,0 +1,368 @@
+package command_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sourcegraph/sourcegraph/cmd/executor/internal/worker/command"
+)
+
+func TestNewPodmanSpec(t *testing.T) {
+	tests := []struct {
+		name         string
+		workingDir   string
+		image        string
+		scriptPath   string
+		spec         command.Spec
+		options      command.PodmanOptions
+		expectedSpec command.Spec
+	}{
+		{
+			name:       "Converts to podman spec",
+			workingDir: "/workingDirectory",
+			image:      "some-image",
+			scriptPath: "script/path",
+			spec: command.Spec{
+				Key:     "some-key",
+				Command: []string{"some", "command"},
+				Dir:     "/some/dir",
+				Env:     []string{"FOO=BAR"},
+			},
+			expectedSpec: command.Spec{
+				Key: "some-key",
+				Command: []string{
+					"podman",
+					"run",
+					"--rm",
+					"-v",
+					"/workingDirectory:/data",
+					"-w",
+					"/data/some/dir",
+					"-e",
+					"FOO=BAR",
+					"--entrypoint",
+					"/bin/sh",
+					"some-image",
+					"/data/.sourcegraph-executor/script/path",
+				},
+			},
+		},
+		{
+			name:       "User namespace",
+			workingDir: "/workingDirectory",
+			image:      "some-image",
+			scriptPath: "some/path",
+			spec: command.Spec{
+				Key:     "some-key",
+				Command: []string{"some", "command"},
+				Dir:     "/some/dir",
+				Env:     []string{"FOO=BAR"},
+			},
+			options: command.PodmanOptions{
+				UserNamespace: "keep-id",
+			},
+			expectedSpec: command.Spec{
+				Key: "some-key",
+				Command: []string{
+					"podman",
+					"run",
+					"--rm",
+					"--userns=keep-id",
+					"-v",
+					"/workingDirectory:/data",
+					"-w",
+					"/data/some/dir",
+					"-e",
+					"FOO=BAR",
+					"--entrypoint",
+					"/bin/sh",
+					"some-image",
+					"/data/.sourcegraph-executor/some/path",
+				},
+			},
+		},
+		{
+			name:       "SELinux label",
+			workingDir: "/workingDirectory",
+			image:      "some-image",
+			scriptPath: "some/path",
+			spec: command.Spec{
+				Key:     "some-key",
+				Command: []string{"some", "command"},
+				Dir:     "/some/dir",
+				Env:     []string{"FOO=BAR"},
+			},
+			options: command.PodmanOptions{
+				SELinuxLabel: "Z",
+			},
+			expectedSpec: command.Spec{
+				Key: "some-key",
+				Command: []string{
+					"podman",
+					"run",
+					"--rm",
+					"-v",
+					"/workingDirectory:/data:Z",
+					"-w",
+					"/data/some/dir",
+					"-e",
+					"FOO=BAR",
+					"--entrypoint",
+					"/bin/sh",
+					"some-image",
+					"/data/.sourcegraph-executor/some/path",
+				},
+			},
+		},
+		{
+			name:       "Git dir",
+			workingDir: "/workingDirectory",
+			image:      "some-image",
+			scriptPath: "some/path",
+			spec: command.Spec{
+				Key:     "some-key",
+				Command: []string{"some", "command"},
+				Dir:     "/some/dir",
+				Env:     []string{"FOO=BAR"},
+			},
+			options: command.PodmanOptions{
+				SELinuxLabel: "Z",
+				GitDir:       "/repo-cache/abc.git",
+			},
+			expectedSpec: command.Spec{
+				Key: "some-key",
+				Command: []string{
+					"podman",
+					"run",
+					"--rm",
+					"-v",
+					"/workingDirectory:/data:Z",
+					"-v",
+					"/repo-cache/abc.git:/repo-cache/abc.git:ro,z",
+					"-w",
+					"/data/some/dir",
+					"-e",
+					"FOO=BAR",
+					"--entrypoint",
+					"/bin/sh",
+					"some-image",
+					"/data/.sourcegraph-executor/some/path",
+				},
+			},
+		},
+		{
+			name:       "Docker Host Mount Path",
+			workingDir: "/workingDirectory",
+			image:      "some-image",
+			scriptPath: "some/path",
+			spec: command.Spec{
+				Key:     "some-key",
+				Command: []string{"some", "command"},
+				Dir:     "/some/dir",
+				Env:     []string{"FOO=BAR"},
+			},
+			options: command.PodmanOptions{
+				SELinuxLabel: "z",
+				Resources: command.ResourceOptions{
+					DockerHostMountPath: "/docker/host/mount/path",
+				},
+			},
+			expectedSpec: command.Spec{
+				Key: "some-key",
+				Command: []string{
+					"podman",
+					"run",
+					"--rm",
+					"-v",
+					"/docker/host/mount/path/workingDirectory:/data:z",
+					"-w",
+					"/data/some/dir",
+					"-e",
+					"FOO=BAR",
+					"--entrypoint",
+					"/bin/sh",
+					"some-image",
+					"/data/.sourcegraph-executor/some/path",
+				},
+			},
+		},
+		{
+			name:       "Auth file",
+			workingDir: "/workingDirectory",
+			image:      "some-image",
+			scriptPath: "some/path",
+			spec: command.Spec{
+				Key:     "some-key",
+				Command: []string{"some", "command"},
+				Dir:     "/some/dir",
+				Env:     []string{"FOO=BAR"},
+			},
+			options: command.PodmanOptions{
+				AuthFilePath: "/podman/auth.json",
+			},
+			expectedSpec: command.Spec{
+				Key: "some-key",
+				Command: []string{
+					"podman",
+					"run",
+					"--rm",
+					"--authfile",
+					"/podman/auth.json",
+					"-v",
+					"/workingDirectory:/data",
+					"-w",
+					"/data/some/dir",
+					"-e",
+					"FOO=BAR",
+					"--entrypoint",
+					"/bin/sh",
+					"some-image",
+					"/data/.sourcegraph-executor/some/path",
+				},
+			},
+		},
+		{
+			name:       "Host Gateway",
+			workingDir: "/workingDirectory",
+			image:      "some-image",
+			scriptPath: "some/path",
+			spec: command.Spec{
+				Key:     "some-key",
+				Command: []string{"some", "command"},
+				Dir:     "/some/dir",
+				Env:     []string{"FOO=BAR"},
+			},
+			options: command.PodmanOptions{
+				AddHostGateway: true,
+			},
+			expectedSpec: command.Spec{
+				Key: "some-key",
+				Command: []string{
+					"podman",
+					"run",
+					"--rm",
+					"--add-host=host.docker.internal:host-gateway",
+					"-v",
+					"/workingDirectory:/data",
+					"-w",
+					"/data/some/dir",
+					"-e",
+					"FOO=BAR",
+					"--entrypoint",
+					"/bin/sh",
+					"some-image",
+					"/data/.sourcegraph-executor/some/path",
+				},
+			},
+		},
+		{
+			name:       "CPU and Memory",
+			workingDir: "/workingDirectory",
+			image:      "some-image",
+			scriptPath: "some/path",
+			spec: command.Spec{
+				Key:     "some-key",
+				Command: []string{"some", "command"},
+				Dir:     "/some/dir",
+				Env:     []string{"FOO=BAR"},
+			},
+			options: command.PodmanOptions{
+				Resources: command.ResourceOptions{
+					NumCPUs: 10,
+					Memory:  "10G",
+				},
+			},
+			expectedSpec: command.Spec{
+				Key: "some-key",
+				Command: []string{
+					"podman",
+					"run",
+					"--rm",
+					"--cpus",
+					"10",
+					"--memory",
+					"10G",
+					"-v",
+					"/workingDirectory:/data",
+					"-w",
+					"/data/some/dir",
+					"-e",
+					"FOO=BAR",
+					"--entrypoint",
+					"/bin/sh",
+					"some-image",
+					"/data/.sourcegraph-executor/some/path",
+				},
+			},
+		},
+		{
+			name:       "No environment variables",
+			workingDir: "/workingDirectory",
+			image:      "some-image",
+			scriptPath: "some/path",
+			spec: command.Spec{
+				Key:     "some-key",
+				Command: []string{"some", "command"},
+				Dir:     "/some/dir",
+			},
+			expectedSpec: command.Spec{
+				Key: "some-key",
+				Command: []string{
+					"podman",
+					"run",
+					"--rm",
+					"-v",
+					"/workingDirectory:/data",
+					"-w",
+					"/data/some/dir",
+					"--entrypoint",
+					"/bin/sh",
+					"some-image",
+					"/data/.sourcegraph-executor/some/path",
+				},
+			},
+		},
+		{
+			name:       "src-cli Spec",
+			workingDir: "/workingDirectory",
+			spec: command.Spec{
+				Key:     "some-key",
+				Command: []string{"src", "exec", "-f", "batch.yml"},
+				Dir:     "/some/dir",
+				Env:     []string{"FOO=BAR"},
+			},
+			expectedSpec: command.Spec{
+				Key:     "some-key",
+				Command: []string{"src", "exec", "-f", "batch.yml"},
+				Dir:     "/workingDirectory/some/dir",
+				Env:     []string{"FOO=BAR"},
+			},
+		},
+		{
+			name:       "src-cli Spec with Auth File",
+			workingDir: "/workingDirectory",
+			spec: command.Spec{
+				Key:     "some-key",
+				Command: []string{"src", "exec", "-f", "batch.yml"},
+				Dir:     "/some/dir",
+				Env:     []string{"FOO=BAR"},
+			},
+			options: command.PodmanOptions{
+				AuthFilePath: "/podman/auth.json",
+			},
+			expectedSpec: command.Spec{
+				Key:     "some-key",
+				Command: []string{"src", "exec", "-f", "batch.yml"},
+				Dir:     "/workingDirectory/some/dir",
+				Env:     []string{"FOO=BAR", "REGISTRY_AUTH_FILE=/podman/auth.json"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actualSpec := command.NewPodmanSpec(test.workingDir, test.image, test.scriptPath, test.spec, test.options)
+			assert.Equal(t, test.expectedSpec, actualSpec)
+		})
+	}
+}
@@ -3,6 +3,8 @@ package worker
 import (
 	"context"
 	"fmt"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,6 +19,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/executor/internal/worker/files"
 	"github.com/sourcegraph/sourcegraph/cmd/executor/internal/worker/runner"
 	"github.com/sourcegraph/sourcegraph/cmd/executor/internal/worker/runtime"
+	"github.com/sourcegraph/sourcegraph/cmd/executor/internal/worker/stepcache"
 	"github.com/sourcegraph/sourcegraph/cmd/executor/internal/worker/workspace"
 	"github.com/sourcegraph/sourcegraph/internal/executor/types"
 	executorutil "github.com/sourcegraph/sourcegraph/internal/executor/util"
@@ -35,6 +38,8 @@ type handler struct {
 	cloneOptions workspace.CloneOptions
 	operations   *command.Operations
 	jobRuntime   runtime.Runtime
+	// stepCache is the store of step outputs. If nil, the step cache is disabled.
+	stepCache stepcache.Store
 }
 
 var (
@@ -130,7 +135,7 @@ func (h *handler) Handle(ctx context.Context, logger log.Logger, job types.Job)
 		ctx,
 		commandLogger,
 		h.filesStore,
-		runtime.RunnerOptions{Path: ws.Path(), DockerAuthConfig: job.DockerAuthConfig, Name: name},
+		runtime.RunnerOptions{Path: ws.Path(), DockerAuthConfig: job.DockerAuthConfig, Name: name, GitDir: workspace.GitDir(ws)},
 	)
 	if err != nil {
 		return errors.Wrap(err, "creating runtime runner")
@@ -152,6 +157,8 @@ func (h *handler) Handle(ctx context.Context, logger log.Logger, job types.Job)
 		return errors.Wrap(err, "creating commands")
 	}
 
+	cacheKeys := h.stepCacheKeys(job, commands)
+
 	// Run all the things.
 	logger.Info("Running commands")
 	skipKey := ""
@@ -162,9 +169,15 @@ func (h *handler) Handle(ctx context.Context, logger log.Logger, job types.Job)
 			// We have a match, so reset the skip key.
 			skipKey = ""
 		}
+		if cacheKeys[i] != "" && h.restoreStepOutputs(ctx, logger, commandLogger, job, ws, spec, i, cacheKeys[i]) {
+			continue
+		}
 		if err := runtimeRunner.Run(ctx, spec); err != nil {
 			return errors.Wrapf(err, "running command %q", spec.CommandSpecs[0].Key)
 		}
+		if cacheKeys[i] != "" {
+			h.saveStepOutputs(ctx, logger, job, ws, i, cacheKeys[i])
+		}
 		if executorutil.IsPreStepKey(spec.CommandSpecs[0].Key) {
 			// Check if there is a skip file. and if so, what the next step is.
 			nextStep, err := runner.NextStep(ws.WorkingDirectory())
@@ -181,6 +194,69 @@ func (h *handler) Handle(ctx context.Context, logger log.Logger, job types.Job)
 	return nil
 }
 
+// stepCacheKeys returns the step cache key of each command. Commands that can't be
+// cached get an empty key. Caching is only supported for runtimes that run the steps
+// in a workspace directory on the host, so that the outputs can be archived and
+// restored, and only if each command corresponds to exactly one docker step.
+func (h *handler) stepCacheKeys(job types.Job, commands []runner.Spec) []string {
+	keys := make([]string, len(commands))
+	if h.stepCache == nil || len(commands) != len(job.DockerSteps) {
+		return keys
+	}
+
+	switch h.jobRuntime.Name() {
+	case runtime.NameDocker, runtime.NamePodman, runtime.NameShell:
+	default:
+		return keys
+	}
+
+	return types.StepCacheKeys(job)
+}
+
+// restoreStepOutputs restores the outputs of the i-th docker step from the step cache.
+// On a cache hit, the hit is recorded in the job logs and true is returned, in which
+// case the step does not need to run. Failures are not fatal, the step is run instead.
+func (h *handler) restoreStepOutputs(
+	ctx context.Context,
+	logger log.Logger,
+	commandLogger cmdlogger.Logger,
+	job types.Job,
+	ws workspace.Workspace,
+	spec runner.Spec,
+	i int,
+	key string,
+) bool {
+	dir := filepath.Join(ws.WorkingDirectory(), job.DockerSteps[i].Dir)
+
+	ok, err := stepcache.Restore(ctx, h.stepCache, job, key, dir)
+	if err != nil {
+		logger.Warn("Failed to restore step outputs from step cache", log.String("key", key), log.Error(err))
+		return false
+	}
+	if !ok {
+		return false
+	}
+
+	handle := commandLogger.LogEntry(spec.CommandSpecs[0].Key, nil)
+	fmt.Fprintf(handle, "Step cache hit (key %s): restored %s instead of running the step\n", key, strings.Join(job.DockerSteps[i].CacheOutputs, ", "))
+	handle.Finalize(0)
+	_ = handle.Close()
+
+	logger.Info("Restored step outputs from step cache", log.String("key", key))
+	return true
+}
+
+// saveStepOutputs stores the outputs of the i-th docker step in the step cache.
+// Failures are not fatal, as the step has already run successfully.
+func (h *handler) saveStepOutputs(ctx context.Context, logger log.Logger, job types.Job, ws workspace.Workspace, i int, key string) {
+	step := job.DockerSteps[i]
+	dir := filepath.Join(ws.WorkingDirectory(), step.Dir)
+
+	if err := stepcache.Save(ctx, h.stepCache, job, key, dir, step.CacheOutputs); err != nil {
+		logger.Warn("Failed to save step outputs to step cache", log.String("key", key), log.Error(err))
+	}
+}
+
 func createHoneyEvent(_ context.Context, job types.Job, err error, duration time.Duration) honey.Event {
 	fields := map[string]any{
 		"duration_ms":    duration.Milliseconds(),
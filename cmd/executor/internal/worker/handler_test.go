@@ -1,7 +1,11 @@
 package worker
 
 import (
+	"bytes"
 	"context"
+	"io"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/sourcegraph/log/logtest"
@@ -11,6 +15,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/executor/internal/janitor"
 	"github.com/sourcegraph/sourcegraph/cmd/executor/internal/worker/command"
 	"github.com/sourcegraph/sourcegraph/cmd/executor/internal/worker/runner"
+	"github.com/sourcegraph/sourcegraph/cmd/executor/internal/worker/runtime"
 	"github.com/sourcegraph/sourcegraph/internal/executor/types"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
@@ -627,3 +632,99 @@ func TestHandler_Handle(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_Handle_StepCache(t *testing.T) {
+	internalLogger := logtest.Scoped(t)
+	operations := command.NewOperations(&observation.TestContext)
+	stepCache := &testStepCacheStore{entries: map[string][]byte{}}
+
+	job := types.Job{
+		ID:             42,
+		RepositoryName: "my-repo",
+		Commit:         "cool-commit",
+		DockerSteps: []types.DockerStep{
+			{
+				Key:          "indexer",
+				Image:        "my-image",
+				Commands:     []string{"index"},
+				Dir:          "sub",
+				CacheOutputs: []string{"index.scip"},
+			},
+		},
+	}
+
+	runJob := func(t *testing.T, runtimeName runtime.Name) (*MockRunner, *MockExecutionLogEntryStore, string) {
+		dir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), os.ModePerm))
+
+		jobRuntime := NewMockRuntime()
+		jobRunner := NewMockRunner()
+		jobWorkspace := NewMockWorkspace()
+		logStore := NewMockExecutionLogEntryStore()
+
+		jobRuntime.NameFunc.SetDefaultReturn(runtimeName)
+		jobRuntime.PrepareWorkspaceFunc.PushReturn(jobWorkspace, nil)
+		jobRuntime.NewRunnerFunc.PushReturn(jobRunner, nil)
+		jobRuntime.NewRunnerSpecsFunc.PushReturn([]runner.Spec{
+			{
+				CommandSpecs: []command.Spec{{Key: "step.docker.indexer", Operation: operations.Exec}},
+				Image:        "my-image",
+				ScriptPath:   "./foo",
+			},
+		}, nil)
+		jobWorkspace.WorkingDirectoryFunc.SetDefaultReturn(dir)
+		jobRunner.RunFunc.SetDefaultHook(func(ctx context.Context, spec runner.Spec) error {
+			return os.WriteFile(filepath.Join(dir, "sub", "index.scip"), []byte("index"), 0o644)
+		})
+
+		h := &handler{
+			nameSet:    janitor.NewNameSet(),
+			jobRuntime: jobRuntime,
+			logStore:   logStore,
+			operations: operations,
+			stepCache:  stepCache,
+		}
+		require.NoError(t, h.Handle(context.Background(), internalLogger, job))
+
+		return jobRunner, logStore, dir
+	}
+
+	// The first run populates the cache.
+	jobRunner, _, _ := runJob(t, runtime.NameDocker)
+	require.Len(t, jobRunner.RunFunc.History(), 1)
+	require.Len(t, stepCache.entries, 1)
+
+	// The second run restores the outputs instead of running the step.
+	jobRunner, logStore, dir := runJob(t, runtime.NameDocker)
+	require.Len(t, jobRunner.RunFunc.History(), 0)
+	content, err := os.ReadFile(filepath.Join(dir, "sub", "index.scip"))
+	require.NoError(t, err)
+	assert.Equal(t, "index", string(content))
+	require.Len(t, logStore.AddExecutionLogEntryFunc.History(), 1)
+	assert.Equal(t, "step.docker.indexer", logStore.AddExecutionLogEntryFunc.History()[0].Arg2.Key)
+
+	// Runtimes without a workspace on the host don't use the cache.
+	jobRunner, _, _ = runJob(t, runtime.NameKubernetes)
+	require.Len(t, jobRunner.RunFunc.History(), 1)
+}
+
+type testStepCacheStore struct {
+	entries map[string][]byte
+}
+
+func (s *testStepCacheStore) GetStepCacheEntry(_ context.Context, _ types.Job, key string) (io.ReadCloser, bool, error) {
+	b, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	return io.NopCloser(bytes.NewReader(b)), true, nil
+}
+
+func (s *testStepCacheStore) PutStepCacheEntry(_ context.Context, _ types.Job, key string, r io.Reader) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.entries[key] = b
+	return nil
+}
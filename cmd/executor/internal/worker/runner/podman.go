@@ -0,0 +1,102 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/executor/internal/worker/cmdlogger"
+	"github.com/sourcegraph/sourcegraph/cmd/executor/internal/worker/command"
+	"github.com/sourcegraph/sourcegraph/internal/executor/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+type podmanRunner struct {
+	cmd              command.Command
+	dir              string
+	internalLogger   log.Logger
+	commandLogger    cmdlogger.Logger
+	options          command.PodmanOptions
+	dockerAuthConfig types.DockerAuthConfig
+	// tmpDir is used to store temporary files used for podman execution.
+	tmpDir string
+}
+
+var _ Runner = &podmanRunner{}
+
+func NewPodmanRunner(
+	cmd command.Command,
+	logger cmdlogger.Logger,
+	dir string,
+	options command.PodmanOptions,
+	dockerAuthConfig types.DockerAuthConfig,
+) Runner {
+	// Use the option configuration unless the user has provided a custom configuration.
+	actualDockerAuthConfig := options.DockerAuthConfig
+	if len(dockerAuthConfig.Auths) > 0 {
+		actualDockerAuthConfig = dockerAuthConfig
+	}
+
+	return &podmanRunner{
+		cmd:              cmd,
+		dir:              dir,
+		internalLogger:   log.Scoped("podman-runner"),
+		commandLogger:    logger,
+		options:          options,
+		dockerAuthConfig: actualDockerAuthConfig,
+	}
+}
+
+func (r *podmanRunner) TempDir() string {
+	return r.tmpDir
+}
+
+func (r *podmanRunner) Setup(ctx context.Context) error {
+	dir, err := os.MkdirTemp("", "executor-podman-runner")
+	if err != nil {
+		return errors.Wrap(err, "failed to create tmp dir for podman runner")
+	}
+	r.tmpDir = dir
+
+	// If docker auth config is present, write it. Podman understands the auths
+	// section of the docker config file, so we can pass it as the auth file.
+	if len(r.dockerAuthConfig.Auths) > 0 {
+		d, err := json.Marshal(r.dockerAuthConfig)
+		if err != nil {
+			return err
+		}
+
+		authDir, err := os.MkdirTemp(r.tmpDir, "podman_auth")
+		if err != nil {
+			return err
+		}
+		r.options.AuthFilePath = filepath.Join(authDir, "auth.json")
+
+		// The auth file contains credentials, so only the executor user may read it.
+		if err = os.WriteFile(r.options.AuthFilePath, d, 0600); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *podmanRunner) Teardown(ctx context.Context) error {
+	if err := os.RemoveAll(r.tmpDir); err != nil {
+		r.internalLogger.Error(
+			"Failed to remove podman state tmp dir",
+			log.String("tmpDir", r.tmpDir),
+			log.Error(err),
+		)
+	}
+
+	return nil
+}
+
+func (r *podmanRunner) Run(ctx context.Context, spec Spec) error {
+	podmanSpec := command.NewPodmanSpec(r.dir, spec.Image, spec.ScriptPath, spec.CommandSpecs[0], r.options)
+	return r.cmd.Run(ctx, r.commandLogger, podmanSpec)
+}
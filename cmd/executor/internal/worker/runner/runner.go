@@ -43,6 +43,7 @@ type Options struct {
 	DockerOptions      command.DockerOptions
 	FirecrackerOptions FirecrackerOptions
 	KubernetesOptions  KubernetesOptions
+	PodmanOptions      command.PodmanOptions
 }
 
 // NewRunner creates a new runner with the given options.
@@ -53,6 +54,10 @@ func NewRunner(cmd command.Command, dir, vmName string, logger cmdlogger.Logger,
 		return NewShellRunner(cmd, logger, dir, options.DockerOptions)
 	}
 
+	if options.PodmanOptions.Enabled {
+		return NewPodmanRunner(cmd, logger, dir, options.PodmanOptions, dockerAuthConfig)
+	}
+
 	if !options.FirecrackerOptions.Enabled {
 		return NewDockerRunner(cmd, logger, dir, options.DockerOptions, dockerAuthConfig)
 	}
@@ -40,7 +40,9 @@ func (r *dockerRuntime) PrepareWorkspace(ctx context.Context, logger cmdlogger.L
 }
 
 func (r *dockerRuntime) NewRunner(ctx context.Context, logger cmdlogger.Logger, filesStore files.Store, options RunnerOptions) (runner.Runner, error) {
-	run := runner.NewDockerRunner(r.cmd, logger, options.Path, r.dockerOpts, options.DockerAuthConfig)
+	opts := r.dockerOpts
+	opts.GitDir = options.GitDir
+	run := runner.NewDockerRunner(r.cmd, logger, options.Path, opts, options.DockerAuthConfig)
 	if err := run.Setup(ctx); err != nil {
 		return nil, errors.Wrap(err, "failed to setup docker runner")
 	}
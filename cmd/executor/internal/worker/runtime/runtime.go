@@ -36,6 +36,9 @@ type RunnerOptions struct {
 	Name             string
 	Path             string
 	DockerAuthConfig types.DockerAuthConfig
+	// GitDir is the path of the bare repository the workspace repository borrows
+	// its objects from, if any. See workspace.GitDir.
+	GitDir string
 }
 
 // New creates the runtime based on the configured environment.
@@ -60,6 +63,12 @@ func New(
 		}, nil
 	}
 
+	if runnerOpts.FirecrackerOptions.Enabled || runnerOpts.KubernetesOptions.Enabled {
+		// The workspace is copied into a VM or a volume, so it can't reference a bare
+		// repository on the host.
+		cloneOpts.RepoCache = nil
+	}
+
 	if runnerOpts.FirecrackerOptions.Enabled {
 		// We explicitly want a Firecracker runtime. So validation must pass.
 		if err := util.ValidateFirecrackerTools(runner); err != nil {
@@ -118,6 +127,27 @@ func New(
 		}, nil
 	}
 
+	if runnerOpts.PodmanOptions.Enabled {
+		// We explicitly want a Podman runtime. So validation must pass.
+		if err := util.ValidatePodmanTools(runner); err != nil {
+			var errMissingTools *util.ErrMissingTools
+			if errors.As(err, &errMissingTools) {
+				logger.Error("runtime 'podman' is not supported: missing required tools", log.Strings("podmanTools", errMissingTools.Tools))
+			} else {
+				logger.Error("failed to determine if podman tools are configured", log.Error(err))
+			}
+			return nil, err
+		}
+		logger.Info("using runtime 'podman'")
+		return &podmanRuntime{
+			operations:   ops,
+			filesStore:   filesStore,
+			cloneOptions: cloneOpts,
+			podmanOpts:   runnerOpts.PodmanOptions,
+			cmd:          cmd,
+		}, nil
+	}
+
 	// Default to Docker runtime.
 	if err := util.ValidateDockerTools(runner); err != nil {
 		var errMissingTools *util.ErrMissingTools
@@ -149,6 +179,7 @@ const (
 	NameDocker      Name = "docker"
 	NameFirecracker Name = "firecracker"
 	NameKubernetes  Name = "kubernetes"
+	NamePodman      Name = "podman"
 	NameShell       Name = "shell"
 )
 
@@ -158,7 +189,7 @@ func CommandKey(name Name, rawStepKey string, index int) string {
 	case NameKubernetes:
 		return kubernetesKey(rawStepKey, index)
 	default:
-		// shell, docker, and firecracker all use the same key format.
+		// shell, docker, podman, and firecracker all use the same key format.
 		return dockerKey(rawStepKey, index)
 	}
 }
@@ -0,0 +1,199 @@
+package stepcache
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/executor/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Store is the remote store of step cache entries.
+type Store interface {
+	// GetStepCacheEntry returns a reader over the step cache entry with the given key.
+	// If there is no such entry, a false-valued flag is returned.
+	GetStepCacheEntry(ctx context.Context, job types.Job, key string) (io.ReadCloser, bool, error)
+	// PutStepCacheEntry stores the content of the given reader as the step cache entry
+	// with the given key.
+	PutStepCacheEntry(ctx context.Context, job types.Job, key string, r io.Reader) error
+}
+
+// Restore fetches the entry with the given key from the store and extracts the
+// outputs it contains into dir. If there is no such entry, a false-valued flag is
+// returned.
+func Restore(ctx context.Context, store Store, job types.Job, key string, dir string) (bool, error) {
+	rc, ok, err := store.GetStepCacheEntry(ctx, job, key)
+	if err != nil || !ok {
+		return false, err
+	}
+	defer rc.Close()
+
+	if err := extract(rc, dir); err != nil {
+		return false, errors.Wrap(err, "extracting step outputs")
+	}
+	return true, nil
+}
+
+// Save archives the given outputs, which are paths relative to dir, and stores the
+// archive as the entry with the given key.
+func Save(ctx context.Context, store Store, job types.Job, key string, dir string, outputs []string) error {
+	// Check the outputs up front, so that we don't upload a partial archive.
+	for _, output := range outputs {
+		if !filepath.IsLocal(output) {
+			return errors.Newf("step output %q is not a relative path within the step directory", output)
+		}
+		if _, err := os.Lstat(filepath.Join(dir, output)); err != nil {
+			return errors.Wrapf(err, "step output %q", output)
+		}
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(archive(pw, dir, outputs))
+	}()
+	defer pr.Close()
+
+	return store.PutStepCacheEntry(ctx, job, key, pr)
+}
+
+// archive writes a gzipped tarball of the given outputs to w. Directories are
+// added recursively. Only regular files and directories are archived.
+func archive(w io.Writer, dir string, outputs []string) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	for _, output := range outputs {
+		err := filepath.WalkDir(filepath.Join(dir, output), func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && !d.Type().IsRegular() {
+				return errors.Newf("step output %q is not a regular file or directory", path)
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			name, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+
+			header, err := tar.FileInfoHeader(info, "")
+			if err != nil {
+				return err
+			}
+			header.Name = filepath.ToSlash(name)
+			if err := tw.WriteHeader(header); err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			_, err = io.Copy(tw, f)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// extract unpacks the gzipped tarball read from r into dir.
+func extract(r io.Reader, dir string) error {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// 🚨 SECURITY: Never write outside the step directory, even if the archive
+		// was tampered with.
+		name := filepath.FromSlash(header.Name)
+		if !filepath.IsLocal(name) {
+			return errors.Newf("invalid path %q in step cache entry", header.Name)
+		}
+		if err := checkNoSymlinks(dir, name); err != nil {
+			return err
+		}
+		path := filepath.Join(dir, name)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, os.ModePerm); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+				return err
+			}
+			if err := writeFile(path, tr, header.FileInfo().Mode().Perm()); err != nil {
+				return err
+			}
+		default:
+			return errors.Newf("unsupported entry %q in step cache entry", header.Name)
+		}
+	}
+}
+
+// checkNoSymlinks returns an error if any existing component of name, relative to
+// dir, is a symlink. Writing through a symlink that is part of the workspace could
+// otherwise place files outside the step directory.
+func checkNoSymlinks(dir, name string) error {
+	path := dir
+	for _, part := range strings.Split(name, string(filepath.Separator)) {
+		path = filepath.Join(path, part)
+
+		info, err := os.Lstat(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.Mode()&fs.ModeSymlink != 0 {
+			return errors.Newf("refusing to write step output through symlink %q", path)
+		}
+	}
+	return nil
+}
+
+func writeFile(path string, r io.Reader, perm fs.FileMode) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, r)
+	return err
+}
@@ -0,0 +1,145 @@
+package stepcache
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/executor/types"
+)
+
+const testKey = "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+
+func TestSaveAndRestore(t *testing.T) {
+	store := &testStore{entries: map[string][]byte{}}
+	job := types.Job{ID: 42}
+
+	src := t.TempDir()
+	writeTestFile(t, filepath.Join(src, "index.scip"), "index")
+	writeTestFile(t, filepath.Join(src, "out", "nested", "a.txt"), "a")
+	writeTestFile(t, filepath.Join(src, "ignored.txt"), "ignored")
+
+	err := Save(context.Background(), store, job, testKey, src, []string{"index.scip", "out"})
+	require.NoError(t, err)
+	require.Contains(t, store.entries, testKey)
+
+	dst := t.TempDir()
+	ok, err := Restore(context.Background(), store, job, testKey, dst)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	assertTestFile(t, filepath.Join(dst, "index.scip"), "index")
+	assertTestFile(t, filepath.Join(dst, "out", "nested", "a.txt"), "a")
+	assert.NoFileExists(t, filepath.Join(dst, "ignored.txt"))
+}
+
+func TestRestore_Miss(t *testing.T) {
+	store := &testStore{entries: map[string][]byte{}}
+
+	ok, err := Restore(context.Background(), store, types.Job{ID: 42}, testKey, t.TempDir())
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestSave_InvalidOutputs(t *testing.T) {
+	store := &testStore{entries: map[string][]byte{}}
+	dir := t.TempDir()
+
+	tests := map[string]string{
+		"missing":  "index.scip",
+		"absolute": "/etc/passwd",
+		"escaping": "../index.scip",
+	}
+	for name, output := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := Save(context.Background(), store, types.Job{ID: 42}, testKey, dir, []string{output})
+			assert.Error(t, err)
+			assert.Empty(t, store.entries)
+		})
+	}
+}
+
+func TestRestore_RejectsUnsafeEntries(t *testing.T) {
+	tests := map[string]*tar.Header{
+		"escaping path": {Name: "../evil.txt", Typeflag: tar.TypeReg, Mode: 0o644},
+		"absolute path": {Name: "/tmp/evil.txt", Typeflag: tar.TypeReg, Mode: 0o644},
+		"symlink":       {Name: "link", Typeflag: tar.TypeSymlink, Linkname: "/etc/passwd"},
+	}
+	for name, header := range tests {
+		t.Run(name, func(t *testing.T) {
+			store := &testStore{entries: map[string][]byte{testKey: makeArchive(t, header)}}
+
+			ok, err := Restore(context.Background(), store, types.Job{ID: 42}, testKey, t.TempDir())
+			assert.Error(t, err)
+			assert.False(t, ok)
+		})
+	}
+
+	t.Run("through existing symlink", func(t *testing.T) {
+		dir := t.TempDir()
+		outside := t.TempDir()
+		require.NoError(t, os.Symlink(outside, filepath.Join(dir, "out")))
+
+		store := &testStore{entries: map[string][]byte{testKey: makeArchive(t, &tar.Header{Name: "out/evil.txt", Typeflag: tar.TypeReg, Mode: 0o644})}}
+
+		_, err := Restore(context.Background(), store, types.Job{ID: 42}, testKey, dir)
+		assert.Error(t, err)
+		assert.NoFileExists(t, filepath.Join(outside, "evil.txt"))
+	})
+}
+
+type testStore struct {
+	entries map[string][]byte
+}
+
+func (s *testStore) GetStepCacheEntry(_ context.Context, _ types.Job, key string) (io.ReadCloser, bool, error) {
+	b, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	return io.NopCloser(bytes.NewReader(b)), true, nil
+}
+
+func (s *testStore) PutStepCacheEntry(_ context.Context, _ types.Job, key string, r io.Reader) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.entries[key] = b
+	return nil
+}
+
+func makeArchive(t *testing.T, header *tar.Header) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	require.NoError(t, tw.WriteHeader(header))
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	return buf.Bytes()
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func assertTestFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(b))
+}
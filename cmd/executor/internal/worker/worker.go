@@ -45,6 +45,10 @@ type Options struct {
 	// it to the executor to process. Only one of QueueNames and QueueName can be set.
 	QueueNames []string
 
+	// RepoCache, if set, is the cache of bare repositories job workspaces are created
+	// from. Only used by the docker, podman and shell runtimes.
+	RepoCache *workspace.RepoCache
+
 	// GitServicePath is the path to the internal git service API proxy in the frontend.
 	// This path should contain the endpoints info/refs and git-upload-pack.
 	GitServicePath string
@@ -108,8 +112,13 @@ func NewWorker(observationCtx *observation.Context, nameSet *janitor.NameSet, op
 		Logger:    log.Scoped("executor-worker.command"),
 	}
 
+	// Only the runtimes support creating workspaces from the repository cache, the
+	// legacy handler always clones.
+	runtimeCloneOptions := cloneOptions
+	runtimeCloneOptions.RepoCache = options.RepoCache
+
 	// Configure the supported runtimes
-	jobRuntime, err := runtime.New(observationCtx.Logger, commandOps, filesClient, cloneOptions, options.RunnerOptions, cmdRunner, cmd)
+	jobRuntime, err := runtime.New(observationCtx.Logger, commandOps, filesClient, runtimeCloneOptions, options.RunnerOptions, cmdRunner, cmd)
 	if err != nil {
 		return nil, err
 	}
@@ -124,6 +133,7 @@ func NewWorker(observationCtx *observation.Context, nameSet *janitor.NameSet, op
 		cloneOptions: cloneOptions,
 		operations:   commandOps,
 		jobRuntime:   jobRuntime,
+		stepCache:    queueClient,
 	}
 
 	return workerutil.NewWorker[types.Job](context.Background(), queueClient, h, options.WorkerOptions), nil
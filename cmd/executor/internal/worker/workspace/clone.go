@@ -37,17 +37,9 @@ func cloneRepo(
 	options CloneOptions,
 	operations *command.Operations,
 ) (err error) {
-	repoPath := workspaceDir
-	if job.RepositoryDirectory != "" {
-		repoPath = filepath.Join(workspaceDir, job.RepositoryDirectory)
-
-		if !strings.HasPrefix(repoPath, workspaceDir) {
-			return errors.Newf("invalid repo path %q not a subdirectory of %q", repoPath, workspaceDir)
-		}
-
-		if err := os.MkdirAll(repoPath, os.ModePerm); err != nil {
-			return errors.Wrap(err, "creating repo directory")
-		}
+	repoPath, err := makeRepoPath(workspaceDir, job)
+	if err != nil {
+		return err
 	}
 
 	proxyURL, cleanup, err := newGitProxyServer(options, job)
@@ -175,6 +167,192 @@ func cloneRepo(
 	return nil
 }
 
+// cachedHeadRef is the ref in cached bare repositories pointing to the most
+// recently fetched commit. Keeping it around makes sure that the next fetch can
+// negotiate against it and only transfers the missing objects.
+const cachedHeadRef = "refs/executor/head"
+
+// gitHostSafeFlags disable the settings that make git run programs from a
+// repository, like hooks and the file system monitor. They are passed to all git
+// commands the executor runs on the host against the repository cache, so that
+// even a tampered repository can't make the host run code.
+var gitHostSafeFlags = []string{"-c", "core.hooksPath=/dev/null", "-c", "core.fsmonitor=false"}
+
+// gitHostCommand returns a git command run on the host in the given repository,
+// with the flags of gitHostSafeFlags.
+func gitHostCommand(dir string, args ...string) []string {
+	cmd := append([]string{"git", "-C", dir}, gitHostSafeFlags...)
+	return append(cmd, args...)
+}
+
+// cachedCloneRepo creates the repository in the workspace from the cached bare
+// repository of the job's repository. Only the objects missing from the bare
+// repository are fetched from the instance. The workspace repository has its own
+// git directory, which borrows the objects of the bare repository through
+// objects/info/alternates, so jobs never write to the bare repository. The
+// returned lease must be released once the workspace is removed. The path of the
+// workspace repository is returned too.
+func cachedCloneRepo(
+	ctx context.Context,
+	workspaceDir string,
+	job types.Job,
+	cmd command.Command,
+	logger cmdlogger.Logger,
+	options CloneOptions,
+	operations *command.Operations,
+) (_ *repoLease, repoPath string, err error) {
+	repoPath, err = makeRepoPath(workspaceDir, job)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := os.MkdirAll(options.RepoCache.Dir(), os.ModePerm); err != nil {
+		return nil, "", errors.Wrap(err, "creating repo cache directory")
+	}
+
+	lease := options.RepoCache.acquire(job.RepositoryName)
+	defer func() {
+		if err != nil {
+			lease.Release()
+		}
+	}()
+
+	// Concurrent jobs for the same repository must not fetch into the same bare
+	// repository at the same time.
+	lease.Lock()
+	defer lease.Unlock()
+
+	gitDir := lease.gitDir
+	_, statErr := os.Stat(filepath.Join(gitDir, "HEAD"))
+	fresh := os.IsNotExist(statErr)
+	_, statErr = os.Stat(filepath.Join(gitDir, "shallow"))
+	shallow := statErr == nil
+
+	proxyURL, cleanup, err := newGitProxyServer(options, job)
+	defer func() {
+		err = errors.Append(err, cleanup())
+	}()
+	if err != nil {
+		return nil, "", errors.Wrap(err, "spawning git proxy server")
+	}
+
+	cloneURL, err := makeRelativeURL(proxyURL, job.RepositoryName)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if fresh {
+		initCommands := []command.Spec{
+			{Key: "setup.git.init", Env: gitStdEnv, Command: append(append([]string{"git"}, gitHostSafeFlags...), "init", "--bare", gitDir), Operation: operations.SetupGitInit},
+			// Disable gc, objects of old commits are cheap compared to refetching them and
+			// the cache is bounded by eviction instead. Workspaces also rely on the objects
+			// they borrow to never be pruned.
+			{Key: "setup.git.disable-gc", Env: gitStdEnv, Command: gitHostCommand(gitDir, "config", "--local", "gc.auto", "0"), Operation: operations.SetupGitDisableGC},
+		}
+		for _, spec := range initCommands {
+			if err = cmd.Run(ctx, logger, spec); err != nil {
+				// Don't leave a half initialized repository behind.
+				_ = os.RemoveAll(gitDir)
+				return nil, "", errors.Wrap(err, fmt.Sprintf("failed %s", spec.Key))
+			}
+		}
+	}
+
+	fetchArgs := []string{
+		"-c", "protocol.version=2",
+		"fetch",
+		"--progress",
+		"--no-recurse-submodules",
+	}
+	if job.FetchTags {
+		fetchArgs = append(fetchArgs, "--tags")
+	}
+	if job.ShallowClone && (fresh || shallow) {
+		if !job.FetchTags {
+			fetchArgs = append(fetchArgs, "--no-tags")
+		}
+		fetchArgs = append(fetchArgs, "--depth=1")
+	} else if !job.ShallowClone && shallow {
+		// A previous job only needed a shallow clone, but this one needs the history.
+		fetchArgs = append(fetchArgs, "--unshallow")
+	}
+	fetchArgs = append(fetchArgs, cloneURL.String(), "+"+job.Commit+":"+cachedHeadRef)
+
+	fetch := command.Spec{Key: "setup.git.fetch", Env: gitStdEnv, Command: gitHostCommand(gitDir, fetchArgs...), Operation: operations.SetupGitFetch}
+	if err = cmd.Run(ctx, logger, fetch); err != nil {
+		return nil, "", errors.Wrap(err, fmt.Sprintf("failed %s", fetch.Key))
+	}
+
+	if err = cmd.Run(ctx, logger, command.Spec{Key: "setup.git.init", Env: gitStdEnv, Command: gitHostCommand(repoPath, "init"), Operation: operations.SetupGitInit}); err != nil {
+		return nil, "", errors.Wrap(err, "failed setup.git.init")
+	}
+	if err = borrowObjects(repoPath, gitDir); err != nil {
+		return nil, "", err
+	}
+
+	gitCommands := []command.Spec{
+		// This is for LSIF, it relies on the origin being set to the upstream repo
+		// for indexing.
+		{Key: "setup.git.add-remote", Env: gitStdEnv, Command: gitHostCommand(repoPath, "remote", "add", "origin", job.RepositoryName), Operation: operations.SetupAddRemote},
+		// Disable gc, it would copy the borrowed objects into the workspace.
+		{Key: "setup.git.disable-gc", Env: gitStdEnv, Command: gitHostCommand(repoPath, "config", "--local", "gc.auto", "0"), Operation: operations.SetupGitDisableGC},
+		{Key: "setup.git.checkout", Env: gitStdEnv, Command: gitHostCommand(repoPath, "checkout", "--progress", "--force", job.Commit), Operation: operations.SetupGitCheckout},
+	}
+	for _, spec := range gitCommands {
+		if err = cmd.Run(ctx, logger, spec); err != nil {
+			return nil, "", errors.Wrap(err, fmt.Sprintf("failed %s", spec.Key))
+		}
+	}
+
+	return lease, repoPath, nil
+}
+
+// borrowObjects makes the repository at repoPath use the objects of the bare
+// repository gitDir, by listing them in its objects/info/alternates. If the bare
+// repository is shallow, its shallow commits are copied too, so that git in the
+// workspace doesn't look for the missing parents.
+func borrowObjects(repoPath, gitDir string) error {
+	if err := os.MkdirAll(filepath.Dir(alternatesPath(repoPath)), os.ModePerm); err != nil {
+		return errors.Wrap(err, "creating objects info directory")
+	}
+	if err := os.WriteFile(alternatesPath(repoPath), []byte(filepath.Join(gitDir, "objects")+"\n"), os.ModePerm); err != nil {
+		return errors.Wrap(err, "writing alternates")
+	}
+
+	shallow, err := os.ReadFile(filepath.Join(gitDir, "shallow"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrap(err, "reading shallow commits")
+	}
+	return errors.Wrap(os.WriteFile(filepath.Join(repoPath, ".git", "shallow"), shallow, os.ModePerm), "writing shallow commits")
+}
+
+// alternatesPath returns the path of the file listing the object directories
+// the repository at repoPath borrows objects from.
+func alternatesPath(repoPath string) string {
+	return filepath.Join(repoPath, ".git", "objects", "info", "alternates")
+}
+
+// makeRepoPath returns the directory in the workspace the repository is
+// checked out into, creating it if necessary.
+func makeRepoPath(workspaceDir string, job types.Job) (string, error) {
+	if job.RepositoryDirectory == "" {
+		return workspaceDir, nil
+	}
+
+	repoPath := filepath.Join(workspaceDir, job.RepositoryDirectory)
+	if !strings.HasPrefix(repoPath, workspaceDir) {
+		return "", errors.Newf("invalid repo path %q not a subdirectory of %q", repoPath, workspaceDir)
+	}
+
+	if err := os.MkdirAll(repoPath, os.ModePerm); err != nil {
+		return "", errors.Wrap(err, "creating repo directory")
+	}
+	return repoPath, nil
+}
+
 // newGitProxyServer creates a new HTTP proxy to the Sourcegraph instance on a random port.
 // It handles authentication and additional headers required. The cleanup function
 // should be called after the clone operations are done and _before_ the job is started.
@@ -16,6 +16,12 @@ type dockerWorkspace struct {
 	scriptFilenames []string
 	workspaceDir    string
 	logger          cmdlogger.Logger
+	// repoLease is set if the repository borrows the objects of a cached bare repository.
+	repoLease *repoLease
+	// repoPath is the path of the repository in the workspace.
+	repoPath   string
+	cmd        command.Command
+	operations *command.Operations
 }
 
 // NewDockerWorkspace creates a new workspace for docker-based execution. A path on
@@ -34,8 +40,19 @@ func NewDockerWorkspace(
 		return nil, err
 	}
 
+	var (
+		lease    *repoLease
+		repoPath string
+	)
 	if job.RepositoryName != "" {
-		if err = cloneRepo(ctx, workspaceDir, job, cmd, logger, cloneOpts, operations); err != nil {
+		// Sparse checkouts only fetch a fraction of the blobs, which doesn't mix with
+		// a bare repository shared between jobs.
+		if cloneOpts.RepoCache != nil && len(job.SparseCheckout) == 0 {
+			lease, repoPath, err = cachedCloneRepo(ctx, workspaceDir, job, cmd, logger, cloneOpts, operations)
+		} else {
+			err = cloneRepo(ctx, workspaceDir, job, cmd, logger, cloneOpts, operations)
+		}
+		if err != nil {
 			_ = os.RemoveAll(workspaceDir)
 			return nil, err
 		}
@@ -43,6 +60,9 @@ func NewDockerWorkspace(
 
 	scriptPaths, err := prepareScripts(ctx, filesStore, job, workspaceDir, logger)
 	if err != nil {
+		if lease != nil {
+			lease.Release()
+		}
 		_ = os.RemoveAll(workspaceDir)
 		return nil, err
 	}
@@ -51,6 +71,10 @@ func NewDockerWorkspace(
 		scriptFilenames: scriptPaths,
 		workspaceDir:    workspaceDir,
 		logger:          logger,
+		repoLease:       lease,
+		repoPath:        repoPath,
+		cmd:             cmd,
+		operations:      operations,
 	}, nil
 }
 
@@ -77,7 +101,25 @@ func (w dockerWorkspace) ScriptFilenames() []string {
 	return w.scriptFilenames
 }
 
+func (w dockerWorkspace) GitDir() string {
+	if w.repoLease == nil {
+		return ""
+	}
+	return w.repoLease.gitDir
+}
+
 func (w dockerWorkspace) Remove(ctx context.Context, keepWorkspace bool) {
+	if w.repoLease != nil {
+		defer w.repoLease.Release()
+
+		// The bare repository can be evicted once the lease is released, which would
+		// leave a kept repository without the objects it borrows. Copy them into the
+		// workspace first.
+		if keepWorkspace {
+			w.detachRepo(ctx)
+		}
+	}
+
 	handle := w.logger.LogEntry("teardown.fs", nil)
 	defer func() {
 		// We always finish this with exit code 0 even if it errored, because workspace
@@ -96,3 +138,20 @@ func (w dockerWorkspace) Remove(ctx context.Context, keepWorkspace bool) {
 		fmt.Fprintf(handle, "Operation failed: %s\n", rmErr.Error())
 	}
 }
+
+// detachRepo makes the repository self-contained by repacking the objects it
+// borrows from the bare repository into it and then no longer borrowing them.
+func (w dockerWorkspace) detachRepo(ctx context.Context) {
+	repack := command.Spec{
+		Key:       "teardown.git.repack",
+		Env:       gitStdEnv,
+		Command:   gitHostCommand(w.repoPath, "repack", "-a", "-d"),
+		Operation: w.operations.TeardownGitRepack,
+	}
+	if err := w.cmd.Run(ctx, w.logger, repack); err != nil {
+		// Keep borrowing the objects, the repository is still usable until the bare
+		// repository is evicted.
+		return
+	}
+	_ = os.Remove(alternatesPath(w.repoPath))
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/sourcegraph/sourcegraph/cmd/executor/internal/worker/cmdlogger"
 	"github.com/sourcegraph/sourcegraph/cmd/executor/internal/worker/command"
 	"github.com/sourcegraph/sourcegraph/cmd/executor/internal/worker/workspace"
 	"github.com/sourcegraph/sourcegraph/internal/executor/types"
@@ -497,6 +498,103 @@ func TestNewDockerWorkspace(t *testing.T) {
 	}
 }
 
+func TestNewDockerWorkspace_RepoCache(t *testing.T) {
+	operations := command.NewOperations(&observation.TestContext)
+	cacheDir := t.TempDir()
+	cloneOptions := workspace.CloneOptions{RepoCache: workspace.NewRepoCache(cacheDir, 1<<30)}
+	job := types.Job{
+		ID:             42,
+		Token:          "token",
+		Commit:         "commit",
+		RepositoryName: "my-repo",
+	}
+
+	filesStore := workspace.NewMockStore()
+	logger := workspace.NewMockLogger()
+	logger.LogEntryFunc.SetDefaultReturn(workspace.NewMockLogEntry())
+	cmd := workspace.NewMockCommand()
+	cmd.RunFunc.SetDefaultHook(func(ctx context.Context, logger cmdlogger.Logger, spec command.Spec) error {
+		// Pretend to create the bare repository.
+		if spec.Key == "setup.git.init" && spec.Command[len(spec.Command)-2] == "--bare" {
+			gitDir := spec.Command[len(spec.Command)-1]
+			require.NoError(t, os.MkdirAll(gitDir, os.ModePerm))
+			return os.WriteFile(path.Join(gitDir, "HEAD"), []byte("ref: refs/heads/main\n"), os.ModePerm)
+		}
+		return nil
+	})
+
+	keys := func(history []workspace.CommandRunFuncCall) []string {
+		var keys []string
+		for _, call := range history {
+			keys = append(keys, call.Arg2.Key)
+		}
+		return keys
+	}
+	safeFlags := []string{"-c", "core.hooksPath=/dev/null", "-c", "core.fsmonitor=false"}
+	hostGit := func(dir string, args ...string) []string {
+		return append(append([]string{"git", "-C", dir}, safeFlags...), args...)
+	}
+
+	// The first workspace initializes the bare repository.
+	ws, err := workspace.NewDockerWorkspace(context.Background(), filesStore, job, cmd, logger, cloneOptions, operations)
+	require.NoError(t, err)
+
+	gitDir := workspace.GitDir(ws)
+	assert.Equal(t, cacheDir, path.Dir(gitDir))
+	assert.Equal(t, []string{
+		"setup.git.init",
+		"setup.git.disable-gc",
+		"setup.git.fetch",
+		"setup.git.init",
+		"setup.git.add-remote",
+		"setup.git.disable-gc",
+		"setup.git.checkout",
+	}, keys(cmd.RunFunc.History()))
+
+	history := cmd.RunFunc.History()
+	assert.Equal(t, append(append([]string{"git"}, safeFlags...), "init", "--bare", gitDir), history[0].Arg2.Command)
+	fetchCommand := history[2].Arg2.Command
+	assert.Equal(t, hostGit(gitDir, "-c", "protocol.version=2", "fetch", "--progress", "--no-recurse-submodules"), fetchCommand[:12])
+	assert.Regexp(t, "^http://127.0.0.1:[0-9]+/my-repo$", fetchCommand[12])
+	assert.Equal(t, "+commit:refs/executor/head", fetchCommand[13])
+	assert.Equal(t, hostGit(ws.Path(), "init"), history[3].Arg2.Command)
+	assert.Equal(t, hostGit(ws.Path(), "remote", "add", "origin", "my-repo"), history[4].Arg2.Command)
+	assert.Equal(t, hostGit(ws.Path(), "checkout", "--progress", "--force", "commit"), history[6].Arg2.Command)
+
+	// The workspace repository borrows the objects of the bare repository.
+	alternates, err := os.ReadFile(path.Join(ws.Path(), ".git", "objects", "info", "alternates"))
+	require.NoError(t, err)
+	assert.Equal(t, path.Join(gitDir, "objects")+"\n", string(alternates))
+	ws.Remove(context.Background(), false)
+
+	// The second workspace only fetches into the existing bare repository.
+	ws, err = workspace.NewDockerWorkspace(context.Background(), filesStore, job, cmd, logger, cloneOptions, operations)
+	require.NoError(t, err)
+	t.Cleanup(func() { ws.Remove(context.Background(), false) })
+
+	assert.Equal(t, gitDir, workspace.GitDir(ws))
+	assert.Equal(t, []string{
+		"setup.git.fetch",
+		"setup.git.init",
+		"setup.git.add-remote",
+		"setup.git.disable-gc",
+		"setup.git.checkout",
+	}, keys(cmd.RunFunc.History()[7:]))
+
+	// A kept workspace stops borrowing objects, as the bare repository can be
+	// evicted once it's no longer in use.
+	kept, err := workspace.NewDockerWorkspace(context.Background(), filesStore, job, cmd, logger, cloneOptions, operations)
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(kept.Path()) })
+
+	kept.Remove(context.Background(), true)
+	history = cmd.RunFunc.History()
+	repack := history[len(history)-1].Arg2
+	assert.Equal(t, "teardown.git.repack", repack.Key)
+	assert.Equal(t, hostGit(kept.Path(), "repack", "-a", "-d"), repack.Command)
+	assert.NoFileExists(t, path.Join(kept.Path(), ".git", "objects", "info", "alternates"))
+}
+
 var expectedGitEnv = []string{"GIT_TERMINAL_PROMPT=0", "GIT_LFS_SKIP_SMUDGE=1"}
 
 func toDockerStepScript(commands ...string) string {
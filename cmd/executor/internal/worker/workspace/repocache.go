@@ -0,0 +1,219 @@
+package workspace
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// RepoCache is a directory on the executor host holding one bare repository per
+// repository name. Workspace repositories borrow the objects of these bare
+// repositories through git alternates, so that subsequent jobs for the same
+// repository only need to fetch the commits they don't have yet instead of
+// cloning from scratch.
+//
+// The total size of the cache is bounded by a disk budget, which is enforced by
+// Evict. Repositories that are in use by a running job are never evicted.
+type RepoCache struct {
+	dir     string
+	maxSize int64
+
+	mu    sync.Mutex
+	repos map[string]*cachedRepo
+}
+
+type cachedRepo struct {
+	// mu is held while the bare repository is being modified, i.e. while fetching
+	// and creating a workspace repository from it.
+	mu sync.Mutex
+	// refs is the number of workspaces currently using the bare repository.
+	// Guarded by RepoCache.mu.
+	refs int
+}
+
+// NewRepoCache creates a new repository cache in the given directory. The cache
+// will be evicted down to maxSize bytes by Evict.
+func NewRepoCache(dir string, maxSize int64) *RepoCache {
+	return &RepoCache{
+		dir:     dir,
+		maxSize: maxSize,
+		repos:   map[string]*cachedRepo{},
+	}
+}
+
+// Dir returns the directory the bare repositories are stored in.
+func (c *RepoCache) Dir() string {
+	return c.dir
+}
+
+// repoLease marks a bare repository as in use until it is released.
+type repoLease struct {
+	cache   *RepoCache
+	gitDir  string
+	repo    *cachedRepo
+	release sync.Once
+}
+
+// acquire marks the bare repository of the given repository as in use and
+// returns its path. The lease must be released once the workspace is removed.
+func (c *RepoCache) acquire(repositoryName string) *repoLease {
+	gitDir := c.gitDir(repositoryName)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	repo, ok := c.repos[gitDir]
+	if !ok {
+		repo = &cachedRepo{}
+		c.repos[gitDir] = repo
+	}
+	repo.refs++
+
+	return &repoLease{cache: c, gitDir: gitDir, repo: repo}
+}
+
+// Lock locks the bare repository for modification.
+func (l *repoLease) Lock() { l.repo.mu.Lock() }
+
+// Unlock unlocks the bare repository.
+func (l *repoLease) Unlock() { l.repo.mu.Unlock() }
+
+// Release marks the bare repository as no longer used by this lease and bumps
+// its last use time, which is used to pick eviction candidates.
+func (l *repoLease) Release() {
+	l.release.Do(func() {
+		now := time.Now()
+		_ = os.Chtimes(l.gitDir, now, now)
+
+		l.cache.mu.Lock()
+		defer l.cache.mu.Unlock()
+
+		l.repo.refs--
+		if l.repo.refs == 0 {
+			delete(l.cache.repos, l.gitDir)
+		}
+	})
+}
+
+// gitDir returns the path of the bare repository for the given repository. The
+// name is hashed so that it's always a single, safe path segment, which is also
+// usable as a container volume mount.
+func (c *RepoCache) gitDir(repositoryName string) string {
+	sum := sha256.Sum256([]byte(repositoryName))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".git")
+}
+
+// Evict removes the least recently used bare repositories that are not in use
+// until the cache fits into its disk budget. It returns the number of evicted
+// repositories and the number of bytes freed.
+func (c *RepoCache) Evict(ctx context.Context) (evicted int, freed int64, err error) {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, 0, nil
+		}
+		return 0, 0, errors.Wrap(err, "reading repo cache directory")
+	}
+
+	type candidate struct {
+		path    string
+		size    int64
+		lastUse time.Time
+	}
+
+	var (
+		candidates []candidate
+		totalSize  int64
+	)
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasSuffix(entry.Name(), ".git") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(c.dir, entry.Name())
+		size, err := dirSize(path)
+		if err != nil {
+			return evicted, freed, err
+		}
+		totalSize += size
+		candidates = append(candidates, candidate{path: path, size: size, lastUse: info.ModTime()})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].lastUse.Before(candidates[j].lastUse)
+	})
+
+	for _, candidate := range candidates {
+		if totalSize <= c.maxSize {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return evicted, freed, err
+		}
+
+		removed, err := c.remove(candidate.path)
+		if err != nil {
+			return evicted, freed, err
+		}
+		if removed {
+			evicted++
+			freed += candidate.size
+			totalSize -= candidate.size
+		}
+	}
+
+	return evicted, freed, nil
+}
+
+// remove deletes the given bare repository unless it is currently in use.
+func (c *RepoCache) remove(gitDir string) (bool, error) {
+	// Hold the cache lock for the entire removal, so no workspace can acquire
+	// the repository while it's being deleted.
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, inUse := c.repos[gitDir]; inUse {
+		return false, nil
+	}
+	if err := os.RemoveAll(gitDir); err != nil {
+		return false, errors.Wrapf(err, "removing cached repository %q", gitDir)
+	}
+	return true, nil
+}
+
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Files can disappear while a fetch is running, don't fail on them.
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, errors.Wrapf(err, "computing size of %q", dir)
+}
@@ -0,0 +1,61 @@
+package workspace
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepoCache_Evict(t *testing.T) {
+	cache := NewRepoCache(t.TempDir(), 250)
+
+	// Creates a bare repository of 100 bytes that was last used at the given time.
+	createRepo := func(name string, lastUse time.Time) string {
+		gitDir := cache.gitDir(name)
+		require.NoError(t, os.MkdirAll(filepath.Join(gitDir, "objects"), os.ModePerm))
+		require.NoError(t, os.WriteFile(filepath.Join(gitDir, "objects", "pack"), make([]byte, 100), os.ModePerm))
+		require.NoError(t, os.Chtimes(gitDir, lastUse, lastUse))
+		return gitDir
+	}
+
+	now := time.Now()
+	oldest := createRepo("oldest", now.Add(-3*time.Hour))
+	inUse := createRepo("in-use", now.Add(-2*time.Hour))
+	older := createRepo("older", now.Add(-1*time.Hour))
+	newest := createRepo("newest", now)
+
+	lease := cache.acquire("in-use")
+
+	evicted, freed, err := cache.Evict(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, evicted)
+	assert.Equal(t, int64(200), freed)
+
+	assert.NoDirExists(t, oldest)
+	assert.DirExists(t, inUse)
+	assert.NoDirExists(t, older)
+	assert.DirExists(t, newest)
+
+	// Once released, the repository is the most recently used one.
+	lease.Release()
+	cache.maxSize = 100
+	evicted, _, err = cache.Evict(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, evicted)
+	assert.DirExists(t, inUse)
+	assert.NoDirExists(t, newest)
+}
+
+func TestRepoCache_Evict_MissingDir(t *testing.T) {
+	cache := NewRepoCache(filepath.Join(t.TempDir(), "missing"), 0)
+
+	evicted, freed, err := cache.Evict(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, evicted)
+	assert.Zero(t, freed)
+}
@@ -8,6 +8,9 @@ type CloneOptions struct {
 	EndpointURL    string
 	GitServicePath string
 	ExecutorToken  string
+	// RepoCache, if set, is used to create the repository from a cached bare
+	// repository instead of cloning it from scratch.
+	RepoCache *RepoCache
 }
 
 // Workspace represents a workspace that can be used to execute a job.
@@ -24,3 +27,20 @@ type Workspace interface {
 	// the workspace contents on disk for debugging purposes.
 	Remove(ctx context.Context, keepWorkspace bool)
 }
+
+// SharedGitDir is implemented by workspaces whose repository borrows the objects
+// of a bare repository outside the workspace. Container runtimes need to make the
+// bare repository available at the same path for git to work inside the job.
+type SharedGitDir interface {
+	// GitDir returns the path of the bare repository.
+	GitDir() string
+}
+
+// GitDir returns the path of the bare repository the workspace repository borrows
+// its objects from, or an empty string if the repository is self-contained.
+func GitDir(ws Workspace) string {
+	if shared, ok := ws.(SharedGitDir); ok {
+		return shared.GitDir()
+	}
+	return ""
+}
@@ -674,6 +674,15 @@ type BatchChangeResolver interface {
 	CurrentSpec(ctx context.Context) (BatchSpecResolver, error)
 	BulkOperations(ctx context.Context, args *ListBatchChangeBulkOperationArgs) (BulkOperationConnectionResolver, error)
 	BatchSpecs(ctx context.Context, args *ListBatchSpecArgs) (BatchSpecConnectionResolver, error)
+	RolloutWaves(ctx context.Context) (*[]BatchChangeRolloutWaveResolver, error)
+}
+
+type BatchChangeRolloutWaveResolver interface {
+	Index() int32
+	State() string
+	TotalCount() int32
+	PublishedCount() int32
+	SucceededCount() int32
 }
 
 type BatchChangesConnectionResolver interface {
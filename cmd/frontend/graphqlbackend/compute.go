@@ -2,14 +2,46 @@ package graphqlbackend
 
 import (
 	"context"
+
+	"github.com/graph-gophers/graphql-go"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 type ComputeArgs struct {
 	Query string
 }
 
+type CreateBatchSpecFromComputeArgs struct {
+	Query       string
+	Name        string
+	Namespace   graphql.ID
+	BatchChange graphql.ID
+}
+
 type ComputeResolver interface {
 	Compute(ctx context.Context, args *ComputeArgs) ([]ComputeResultResolver, error)
+	// ReplaceBatchSpec returns the raw batch spec that applies the diffs of a
+	// content:replace.diff compute query.
+	ReplaceBatchSpec(ctx context.Context, query, name string) (string, error)
+}
+
+// CreateBatchSpecFromCompute creates a draft batch spec from the diffs of a
+// content:replace.diff compute query, like createBatchSpecFromRaw does for a
+// raw batch spec.
+func (r *schemaResolver) CreateBatchSpecFromCompute(ctx context.Context, args *CreateBatchSpecFromComputeArgs) (BatchSpecResolver, error) {
+	if EnterpriseResolvers.computeResolver == nil {
+		return nil, errors.New("compute is not available")
+	}
+	rawSpec, err := EnterpriseResolvers.computeResolver.ReplaceBatchSpec(ctx, args.Query, args.Name)
+	if err != nil {
+		return nil, err
+	}
+	return EnterpriseResolvers.batchChangesResolver.CreateBatchSpecFromRaw(ctx, &CreateBatchSpecFromRawArgs{
+		BatchSpec:   rawSpec,
+		Namespace:   args.Namespace,
+		BatchChange: args.BatchChange,
+	})
 }
 
 type ComputeResultResolver interface {
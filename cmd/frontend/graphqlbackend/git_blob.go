@@ -3,8 +3,10 @@ package graphqlbackend
 import (
 	"context"
 	"io"
+	"strconv"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -32,7 +34,7 @@ func (r *GitTreeEntryResolver) Blame(ctx context.Context, args *GitTreeEntryBlam
 		hunk, err := hr.Read()
 		if err != nil {
 			if errors.Is(err, io.EOF) {
-				return hunkResolvers, nil
+				break
 			}
 			return nil, err
 		}
@@ -42,4 +44,42 @@ func (r *GitTreeEntryResolver) Blame(ctx context.Context, args *GitTreeEntryBlam
 			hunk: hunk,
 		})
 	}
+
+	if err := setHunkChangelistIDs(ctx, r.db, r.commit.repoResolver, hunkResolvers); err != nil {
+		return nil, err
+	}
+	return hunkResolvers, nil
+}
+
+// setHunkChangelistIDs looks up the changelists of the hunks' commits in a
+// single query if the repository is a Perforce depot.
+func setHunkChangelistIDs(ctx context.Context, db database.DB, repo *RepositoryResolver, hunks []*hunkResolver) error {
+	if len(hunks) == 0 {
+		return nil
+	}
+	if source, err := repo.SourceType(ctx); err != nil {
+		return err
+	} else if *source != PerforceDepotSourceType {
+		return nil
+	}
+
+	commitIDs := make([]api.CommitID, 0, len(hunks))
+	seen := make(map[api.CommitID]struct{}, len(hunks))
+	for _, h := range hunks {
+		if _, ok := seen[h.hunk.CommitID]; !ok {
+			seen[h.hunk.CommitID] = struct{}{}
+			commitIDs = append(commitIDs, h.hunk.CommitID)
+		}
+	}
+
+	changelistIDs, err := db.RepoCommitsChangelists().GetChangelistIDsForCommits(ctx, repo.IDInt32(), commitIDs)
+	if err != nil {
+		return errors.Wrap(err, "looking up changelists of blame hunks")
+	}
+	for _, h := range hunks {
+		if cid, ok := changelistIDs[h.hunk.CommitID]; ok {
+			h.changelistID = strconv.FormatInt(cid, 10)
+		}
+	}
+	return nil
 }
@@ -12,6 +12,10 @@ type hunkResolver struct {
 	db   database.DB
 	repo *RepositoryResolver
 	hunk *gitdomain.Hunk
+
+	// changelistID is the Perforce changelist of the hunk's commit, if the
+	// repository is a Perforce depot and the commit has been mapped.
+	changelistID string
 }
 
 func (r *hunkResolver) Author() signatureResolver {
@@ -57,3 +61,10 @@ func (r *hunkResolver) Commit(ctx context.Context) (*GitCommitResolver, error) {
 func (r *hunkResolver) Filename() string {
 	return r.hunk.Filename
 }
+
+func (r *hunkResolver) PerforceChangelist() *PerforceChangelistResolver {
+	if r.changelistID == "" {
+		return nil
+	}
+	return newPerforceChangelistResolver(r.repo, r.changelistID, string(r.hunk.CommitID))
+}
@@ -476,6 +476,7 @@ type InsightViewQueryArgs struct {
 	ExcludeIds           *[]graphql.ID
 	Find                 *string
 	IsFrozen             *bool
+	SeriesId             *string
 	Filters              *InsightViewFiltersInput
 	SeriesDisplayOptions *SeriesDisplayOptionsInput
 }
@@ -21,6 +21,7 @@ type NotebooksResolver interface {
 	NotebookByID(ctx context.Context, id graphql.ID) (NotebookResolver, error)
 	CreateNotebook(ctx context.Context, args CreateNotebookInputArgs) (NotebookResolver, error)
 	UpdateNotebook(ctx context.Context, args UpdateNotebookInputArgs) (NotebookResolver, error)
+	ImportNotebook(ctx context.Context, args ImportNotebookArgs) (NotebookResolver, error)
 	DeleteNotebook(ctx context.Context, args DeleteNotebookArgs) (*EmptyResponse, error)
 	Notebooks(ctx context.Context, args ListNotebooksArgs) (NotebookConnectionResolver, error)
 
@@ -51,6 +52,7 @@ type NotebookResolver interface {
 	ID() graphql.ID
 	Title(ctx context.Context) string
 	Blocks(ctx context.Context) []NotebookBlockResolver
+	Export(ctx context.Context, args NotebookExportArgs) (string, error)
 	Creator(ctx context.Context) (*UserResolver, error)
 	Updater(ctx context.Context) (*UserResolver, error)
 	Namespace(ctx context.Context) (*NamespaceResolver, error)
@@ -67,6 +69,9 @@ type NotebookBlockResolver interface {
 	ToQueryBlock() (QueryBlockResolver, bool)
 	ToFileBlock() (FileBlockResolver, bool)
 	ToSymbolBlock() (SymbolBlockResolver, bool)
+	ToComputeBlock() (ComputeBlockResolver, bool)
+	ToInsightBlock() (InsightBlockResolver, bool)
+	ToReferencesBlock() (ReferencesBlockResolver, bool)
 }
 
 type MarkdownBlockResolver interface {
@@ -106,6 +111,35 @@ type SymbolBlockInputResolver interface {
 	SymbolKind() string
 }
 
+type ComputeBlockResolver interface {
+	ID() string
+	ComputeInput() ComputeBlockInputResolver
+}
+
+type ComputeBlockInputResolver interface {
+	Text() string
+	Output() *string
+}
+
+type InsightBlockResolver interface {
+	ID() string
+	InsightSeriesID() string
+}
+
+type ReferencesBlockResolver interface {
+	ID() string
+	ReferencesInput() ReferencesBlockInputResolver
+}
+
+type ReferencesBlockInputResolver interface {
+	RepositoryName() string
+	FilePath() string
+	Commit() string
+	Line() int32
+	Character() int32
+	SymbolName() string
+}
+
 type FileBlockLineRangeResolver interface {
 	StartLine() int32
 	EndLine() int32
@@ -114,12 +148,39 @@ type FileBlockLineRangeResolver interface {
 type NotebookBlockType string
 
 const (
-	NotebookMarkdownBlockType NotebookBlockType = "MARKDOWN"
-	NotebookQueryBlockType    NotebookBlockType = "QUERY"
-	NotebookFileBlockType     NotebookBlockType = "FILE"
-	NotebookSymbolBlockType   NotebookBlockType = "SYMBOL"
+	NotebookMarkdownBlockType   NotebookBlockType = "MARKDOWN"
+	NotebookQueryBlockType      NotebookBlockType = "QUERY"
+	NotebookFileBlockType       NotebookBlockType = "FILE"
+	NotebookSymbolBlockType     NotebookBlockType = "SYMBOL"
+	NotebookComputeBlockType    NotebookBlockType = "COMPUTE"
+	NotebookInsightBlockType    NotebookBlockType = "INSIGHT"
+	NotebookReferencesBlockType NotebookBlockType = "REFERENCES"
+)
+
+type NotebookExportFormat string
+
+const (
+	NotebookExportFormatMarkdown NotebookExportFormat = "MARKDOWN"
+	NotebookExportFormatJSON     NotebookExportFormat = "JSON"
 )
 
+type NotebookExportArgs struct {
+	Format NotebookExportFormat `json:"format"`
+}
+
+type ImportNotebookArgs struct {
+	ID       *graphql.ID         `json:"id"`
+	Notebook ImportNotebookInput `json:"notebook"`
+}
+
+type ImportNotebookInput struct {
+	Content   string               `json:"content"`
+	Format    NotebookExportFormat `json:"format"`
+	Title     *string              `json:"title"`
+	Namespace graphql.ID           `json:"namespace"`
+	Public    bool                 `json:"public"`
+}
+
 type CreateNotebookInputArgs struct {
 	Notebook NotebookInputArgs `json:"notebook"`
 }
@@ -141,12 +202,15 @@ type NotebookInputArgs struct {
 }
 
 type CreateNotebookBlockInputArgs struct {
-	ID            string                  `json:"id"`
-	Type          NotebookBlockType       `json:"type"`
-	MarkdownInput *string                 `json:"markdownInput"`
-	QueryInput    *string                 `json:"queryInput"`
-	FileInput     *CreateFileBlockInput   `json:"fileInput"`
-	SymbolInput   *CreateSymbolBlockInput `json:"symbolInput"`
+	ID              string                      `json:"id"`
+	Type            NotebookBlockType           `json:"type"`
+	MarkdownInput   *string                     `json:"markdownInput"`
+	QueryInput      *string                     `json:"queryInput"`
+	FileInput       *CreateFileBlockInput       `json:"fileInput"`
+	SymbolInput     *CreateSymbolBlockInput     `json:"symbolInput"`
+	ComputeInput    *CreateComputeBlockInput    `json:"computeInput"`
+	InsightSeriesID *string                     `json:"insightSeriesID"`
+	ReferencesInput *CreateReferencesBlockInput `json:"referencesInput"`
 }
 
 type CreateFileBlockInput struct {
@@ -166,6 +230,20 @@ type CreateSymbolBlockInput struct {
 	SymbolKind          string  `json:"symbolKind"`
 }
 
+type CreateComputeBlockInput struct {
+	Text   string  `json:"text"`
+	Output *string `json:"output"`
+}
+
+type CreateReferencesBlockInput struct {
+	RepositoryName string `json:"repositoryName"`
+	FilePath       string `json:"filePath"`
+	Commit         string `json:"commit"`
+	Line           int32  `json:"line"`
+	Character      int32  `json:"character"`
+	SymbolName     string `json:"symbolName"`
+}
+
 type CreateFileBlockLineRangeInput struct {
 	StartLine int32 `json:"startLine"`
 	EndLine   int32 `json:"endLine"`
@@ -2,12 +2,14 @@ package graphqlbackend
 
 import (
 	"context"
+	"strings"
 
 	"github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
-	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/savedsearches"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/auth"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
@@ -35,38 +37,25 @@ func (r *schemaResolver) savedSearchByID(ctx context.Context, id graphql.ID) (*s
 		return nil, err
 	}
 
-	ss, err := r.db.SavedSearches().GetByID(ctx, intID)
+	// 🚨 SECURITY: GetByID checks that the current user has permission to get
+	// the saved search.
+	ss, err := savedsearches.GetByID(ctx, r.db, intID)
 	if err != nil {
 		return nil, err
 	}
 
-	// 🚨 SECURITY: Make sure the current user has permission to get the saved
-	// search.
-	if ss.Config.UserID != nil {
-		if *ss.Config.UserID != actor.FromContext(ctx).UID {
-			return nil, &auth.InsufficientAuthorizationError{
-				Message: "current user has insufficient privileges to view saved search",
-			}
-		}
-	} else if ss.Config.OrgID != nil {
-		if err := auth.CheckOrgAccess(ctx, r.db, *ss.Config.OrgID); err != nil {
-			return nil, err
-		}
-	} else {
-		return nil, errors.New("failed to get saved search: no Org ID or User ID associated with saved search")
-	}
-
 	savedSearch := &savedSearchResolver{
 		db: r.db,
 		s: types.SavedSearch{
 			ID:              intID,
-			Description:     ss.Config.Description,
-			Query:           ss.Config.Query,
-			Notify:          ss.Config.Notify,
-			NotifySlack:     ss.Config.NotifySlack,
-			UserID:          ss.Config.UserID,
-			OrgID:           ss.Config.OrgID,
-			SlackWebhookURL: ss.Config.SlackWebhookURL,
+			Description:     ss.Description,
+			Query:           ss.Query,
+			Notify:          ss.Notify,
+			NotifySlack:     ss.NotifySlack,
+			UserID:          ss.UserID,
+			OrgID:           ss.OrgID,
+			SlackWebhookURL: ss.SlackWebhookURL,
+			Variables:       ss.Variables,
 		},
 	}
 	return savedSearch, nil
@@ -108,6 +97,22 @@ func (r savedSearchResolver) Namespace(ctx context.Context) (*NamespaceResolver,
 
 func (r savedSearchResolver) SlackWebhookURL() *string { return r.s.SlackWebhookURL }
 
+func (r savedSearchResolver) Variables() []*savedSearchVariableResolver {
+	variables := make([]*savedSearchVariableResolver, 0, len(r.s.Variables))
+	for _, v := range r.s.Variables {
+		variables = append(variables, &savedSearchVariableResolver{v: v})
+	}
+	return variables
+}
+
+type savedSearchVariableResolver struct {
+	v api.SavedQueryVariable
+}
+
+func (r *savedSearchVariableResolver) Name() string { return r.v.Name }
+
+func (r *savedSearchVariableResolver) Type() string { return strings.ToUpper(r.v.Type) }
+
 func (r *schemaResolver) toSavedSearchResolver(entry types.SavedSearch) *savedSearchResolver {
 	return &savedSearchResolver{db: r.db, s: entry}
 }
@@ -201,6 +206,7 @@ func (r *schemaResolver) CreateSavedSearch(ctx context.Context, args *struct {
 	NotifySlack bool
 	OrgID       *graphql.ID
 	UserID      *graphql.ID
+	Variables   *[]savedSearchVariableInput
 }) (*savedSearchResolver, error) {
 	var userID, orgID *int32
 	// 🚨 SECURITY: Make sure the current user has permission to create a saved search for the specified user or org.
@@ -230,6 +236,14 @@ func (r *schemaResolver) CreateSavedSearch(ctx context.Context, args *struct {
 		return nil, errMissingPatternType
 	}
 
+	var variables []api.SavedQueryVariable
+	if args.Variables != nil {
+		variables = toSavedQueryVariables(*args.Variables)
+	}
+	if err := savedsearches.ValidateVariables(args.Query, variables); err != nil {
+		return nil, err
+	}
+
 	ss, err := r.db.SavedSearches().Create(ctx, &types.SavedSearch{
 		Description: args.Description,
 		Query:       args.Query,
@@ -237,6 +251,7 @@ func (r *schemaResolver) CreateSavedSearch(ctx context.Context, args *struct {
 		NotifySlack: args.NotifySlack,
 		UserID:      userID,
 		OrgID:       orgID,
+		Variables:   variables,
 	})
 	if err != nil {
 		return nil, err
@@ -253,6 +268,7 @@ func (r *schemaResolver) UpdateSavedSearch(ctx context.Context, args *struct {
 	NotifySlack bool
 	OrgID       *graphql.ID
 	UserID      *graphql.ID
+	Variables   *[]savedSearchVariableInput
 }) (*savedSearchResolver, error) {
 	id, err := unmarshalSavedSearchID(args.ID)
 	if err != nil {
@@ -281,6 +297,14 @@ func (r *schemaResolver) UpdateSavedSearch(ctx context.Context, args *struct {
 		return nil, errMissingPatternType
 	}
 
+	variables := old.Config.Variables
+	if args.Variables != nil {
+		variables = toSavedQueryVariables(*args.Variables)
+	}
+	if err := savedsearches.ValidateVariables(args.Query, variables); err != nil {
+		return nil, err
+	}
+
 	ss, err := r.db.SavedSearches().Update(ctx, &types.SavedSearch{
 		ID:          id,
 		Description: args.Description,
@@ -289,6 +313,7 @@ func (r *schemaResolver) UpdateSavedSearch(ctx context.Context, args *struct {
 		NotifySlack: args.NotifySlack,
 		UserID:      old.Config.UserID,
 		OrgID:       old.Config.OrgID,
+		Variables:   variables,
 	})
 	if err != nil {
 		return nil, err
@@ -327,6 +352,59 @@ func (r *schemaResolver) DeleteSavedSearch(ctx context.Context, args *struct {
 	return &EmptyResponse{}, nil
 }
 
+type runSavedSearchArgs struct {
+	ID        graphql.ID
+	Variables *[]savedSearchVariableValue
+	Version   string
+}
+
+type savedSearchVariableValue struct {
+	Name  string
+	Value string
+}
+
+func (r *schemaResolver) RunSavedSearch(ctx context.Context, args *runSavedSearchArgs) (SearchImplementer, error) {
+	// 🚨 SECURITY: savedSearchByID checks that the current user has permission
+	// to view the saved search.
+	ss, err := r.savedSearchByID(ctx, args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]string{}
+	if args.Variables != nil {
+		for _, v := range *args.Variables {
+			if _, ok := values[v.Name]; ok {
+				return nil, errors.Errorf("variable $%s is given more than once", v.Name)
+			}
+			values[v.Name] = v.Value
+		}
+	}
+
+	q, err := savedsearches.SubstituteVariables(ss.s.Query, ss.s.Variables, values)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBatchSearchImplementer(ctx, r.logger, r.db, &SearchArgs{
+		Version: args.Version,
+		Query:   q,
+	})
+}
+
+type savedSearchVariableInput struct {
+	Name string
+	Type string
+}
+
+func toSavedQueryVariables(inputs []savedSearchVariableInput) []api.SavedQueryVariable {
+	variables := make([]api.SavedQueryVariable, 0, len(inputs))
+	for _, in := range inputs {
+		variables = append(variables, api.SavedQueryVariable{Name: in.Name, Type: strings.ToLower(in.Type)})
+	}
+	return variables
+}
+
 var patternType = lazyregexp.New(`(?i)\bpatternType:(literal|regexp|structural|standard)\b`)
 
 func queryHasPatternType(query string) bool {
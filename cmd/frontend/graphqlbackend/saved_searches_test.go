@@ -301,6 +301,7 @@ func TestCreateSavedSearch(t *testing.T) {
 		NotifySlack bool
 		OrgID       *graphql.ID
 		UserID      *graphql.ID
+		Variables   *[]savedSearchVariableInput
 	}{Description: "test query", Query: "test type:diff patternType:regexp", NotifyOwner: true, NotifySlack: false, OrgID: nil, UserID: &userID})
 	if err != nil {
 		t.Fatal(err)
@@ -329,6 +330,7 @@ func TestCreateSavedSearch(t *testing.T) {
 		NotifySlack bool
 		OrgID       *graphql.ID
 		UserID      *graphql.ID
+		Variables   *[]savedSearchVariableInput
 	}{Description: "test query", Query: "test type:diff", NotifyOwner: true, NotifySlack: false, OrgID: nil, UserID: &userID})
 	if err == nil {
 		t.Error("Expected error for createSavedSearch when query does not provide a patternType: field.")
@@ -373,6 +375,7 @@ func TestUpdateSavedSearch(t *testing.T) {
 		NotifySlack bool
 		OrgID       *graphql.ID
 		UserID      *graphql.ID
+		Variables   *[]savedSearchVariableInput
 	}{
 		ID:          marshalSavedSearchID(key),
 		Description: "updated query description",
@@ -407,12 +410,93 @@ func TestUpdateSavedSearch(t *testing.T) {
 		NotifySlack bool
 		OrgID       *graphql.ID
 		UserID      *graphql.ID
+		Variables   *[]savedSearchVariableInput
 	}{ID: marshalSavedSearchID(key), Description: "updated query description", Query: "test type:diff", NotifyOwner: true, NotifySlack: false, OrgID: nil, UserID: &userID})
 	if err == nil {
 		t.Error("Expected error for updateSavedSearch when query does not provide a patternType: field.")
 	}
 }
 
+func TestSavedSearchVariables(t *testing.T) {
+	key := int32(1)
+	users := dbmocks.NewMockUserStore()
+	users.GetByCurrentAuthUserFunc.SetDefaultReturn(&types.User{SiteAdmin: true, ID: key}, nil)
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: key})
+
+	var saved []api.SavedQueryVariable
+	ss := dbmocks.NewMockSavedSearchStore()
+	ss.CreateFunc.SetDefaultHook(func(_ context.Context, savedSearch *types.SavedSearch) (*types.SavedSearch, error) {
+		saved = savedSearch.Variables
+		return savedSearch, nil
+	})
+	ss.UpdateFunc.SetDefaultHook(func(_ context.Context, savedSearch *types.SavedSearch) (*types.SavedSearch, error) {
+		saved = savedSearch.Variables
+		return savedSearch, nil
+	})
+	ss.GetByIDFunc.SetDefaultReturn(&api.SavedQuerySpecAndConfig{
+		Config: api.ConfigSavedQuery{
+			Query:     "repo:$service type:diff patternType:literal",
+			UserID:    &key,
+			Variables: []api.SavedQueryVariable{{Name: "service", Type: "repo"}},
+		},
+	}, nil)
+
+	db := dbmocks.NewMockDB()
+	db.UsersFunc.SetDefaultReturn(users)
+	db.SavedSearchesFunc.SetDefaultReturn(ss)
+
+	userID := MarshalUserID(key)
+	create := func(query string, variables []savedSearchVariableInput) error {
+		_, err := newSchemaResolver(db, gitserver.NewTestClient(t)).CreateSavedSearch(ctx, &struct {
+			Description string
+			Query       string
+			NotifyOwner bool
+			NotifySlack bool
+			OrgID       *graphql.ID
+			UserID      *graphql.ID
+			Variables   *[]savedSearchVariableInput
+		}{Description: "test query", Query: query, UserID: &userID, Variables: &variables})
+		return err
+	}
+	update := func(query string, variables *[]savedSearchVariableInput) error {
+		_, err := newSchemaResolver(db, gitserver.NewTestClient(t)).UpdateSavedSearch(ctx, &struct {
+			ID          graphql.ID
+			Description string
+			Query       string
+			NotifyOwner bool
+			NotifySlack bool
+			OrgID       *graphql.ID
+			UserID      *graphql.ID
+			Variables   *[]savedSearchVariableInput
+		}{ID: marshalSavedSearchID(key), Description: "test query", Query: query, UserID: &userID, Variables: variables})
+		return err
+	}
+
+	err := create("repo:$service type:diff after:$since patternType:literal", []savedSearchVariableInput{
+		{Name: "service", Type: "REPO"},
+		{Name: "since", Type: "DATE"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []api.SavedQueryVariable{{Name: "service", Type: "repo"}, {Name: "since", Type: "date"}}, saved)
+
+	err = create("repo:foo type:diff patternType:literal", []savedSearchVariableInput{{Name: "service", Type: "REPO"}})
+	require.EqualError(t, err, "variable $service is declared but not used in the query")
+
+	// Updates without variables keep the existing ones, which must still be
+	// used by the new query.
+	err = update("repo:$service type:commit patternType:literal", nil)
+	require.NoError(t, err)
+	require.Equal(t, []api.SavedQueryVariable{{Name: "service", Type: "repo"}}, saved)
+
+	err = update("repo:foo type:commit patternType:literal", nil)
+	require.EqualError(t, err, "variable $service is declared but not used in the query")
+
+	err = update("repo:foo type:commit patternType:literal", &[]savedSearchVariableInput{})
+	require.NoError(t, err)
+	require.Empty(t, saved)
+}
+
 func TestUpdateSavedSearchPermissions(t *testing.T) {
 	user1 := &types.User{ID: 42}
 	user2 := &types.User{ID: 43}
@@ -500,6 +584,7 @@ func TestUpdateSavedSearchPermissions(t *testing.T) {
 				NotifySlack bool
 				OrgID       *graphql.ID
 				UserID      *graphql.ID
+				Variables   *[]savedSearchVariableInput
 			}{
 				ID:    marshalSavedSearchID(1),
 				Query: "patterntype:literal",
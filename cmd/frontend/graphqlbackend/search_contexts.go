@@ -51,6 +51,13 @@ type SearchContextResolver interface {
 	ViewerHasStarred(ctx context.Context) bool
 	Repositories(ctx context.Context) ([]SearchContextRepositoryRevisionsResolver, error)
 	Query() string
+	Dynamic() SearchContextDynamicResolver
+}
+
+type SearchContextDynamicResolver interface {
+	Source() string
+	Value() string
+	EvaluatedAt() *gqlutil.DateTime
 }
 
 type SearchContextConnectionResolver interface {
@@ -70,6 +77,7 @@ type SearchContextInputArgs struct {
 	Public      bool
 	Namespace   *graphql.ID
 	Query       string
+	Dynamic     *SearchContextDynamicInputArgs
 }
 
 type SearchContextEditInputArgs struct {
@@ -77,6 +85,12 @@ type SearchContextEditInputArgs struct {
 	Description string
 	Public      bool
 	Query       string
+	Dynamic     *SearchContextDynamicInputArgs
+}
+
+type SearchContextDynamicInputArgs struct {
+	Source string
+	Value  string
 }
 
 type SearchContextRepositoryRevisionsInputArgs struct {
@@ -33,6 +33,7 @@ const (
 	routeHome             = "home"
 	routeSearch           = "search"
 	routeSearchBadge      = "search-badge"
+	routeSavedSearch      = "saved-search"
 	routeRepo             = "repo"
 	routeRepoSettings     = "repo-settings"
 	routeRepoCodeGraph    = "repo-code-intelligence"
@@ -197,6 +198,8 @@ func InitRouter(db database.DB) {
 		}, nil, index)))
 	// streaming search
 	r.Path("/search/stream").Methods("GET").Name("search.stream").Handler(search.StreamHandler(db))
+	// saved search with values for its variables
+	r.Path("/search/saved/{ID}").Methods("GET").Name(routeSavedSearch).Handler(handler(db, serveSavedSearch(db)))
 	// search badge
 	r.Path("/search/badge").Methods("GET").Name(routeSearchBadge).Handler(searchBadgeHandler())
 
@@ -54,6 +54,13 @@ func TestRouter(t *testing.T) {
 			wantVars:  map[string]string{},
 		},
 
+		// saved search
+		{
+			path:      "/search/saved/U2F2ZWRTZWFyY2g6MQ==",
+			wantRoute: routeSavedSearch,
+			wantVars:  map[string]string{"ID": "U2F2ZWRTZWFyY2g6MQ=="},
+		},
+
 		// repo
 		{
 			path:      "/r",
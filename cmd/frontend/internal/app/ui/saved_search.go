@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"database/sql"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/savedsearches"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// serveSavedSearch redirects to the search page for the saved search with the
+// GraphQL ID in the URL path. The values of its variables are taken from the
+// URL parameters, e.g. /search/saved/U2F2ZWRTZWFyY2g6MQ==?service=github.com/foo/bar.
+func serveSavedSearch(db database.DB) handlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		var id int32
+		if err := relay.UnmarshalSpec(graphql.ID(mux.Vars(r)["ID"]), &id); err != nil {
+			http.Error(w, "invalid saved search ID", http.StatusNotFound)
+			return nil
+		}
+
+		// 🚨 SECURITY: GetByID checks that the current user has permission to
+		// view the saved search. Saved searches the user cannot view are
+		// reported as not found, so that their existence is not revealed.
+		ss, err := savedsearches.GetByID(r.Context(), db, id)
+		if err != nil {
+			if errors.IsAny(err, sql.ErrNoRows, auth.ErrNotAuthenticated, auth.ErrNotAnOrgMember) || errcode.IsUnauthorized(err) {
+				http.Error(w, "saved search not found", http.StatusNotFound)
+				return nil
+			}
+			return err
+		}
+
+		values := make(map[string]string, len(r.URL.Query()))
+		for name, vs := range r.URL.Query() {
+			if len(vs) > 1 {
+				http.Error(w, "variable $"+name+" is given more than once", http.StatusBadRequest)
+				return nil
+			}
+			values[name] = vs[0]
+		}
+
+		q, err := savedsearches.SubstituteVariables(ss.Query, ss.Variables, values)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return nil
+		}
+
+		http.Redirect(w, r, "/search?"+url.Values{"q": {q}}.Encode(), http.StatusFound)
+		return nil
+	}
+}
@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbmocks"
+)
+
+func TestServeSavedSearch(t *testing.T) {
+	userID := int32(1)
+	savedSearches := dbmocks.NewMockSavedSearchStore()
+	savedSearches.GetByIDFunc.SetDefaultHook(func(_ context.Context, id int32) (*api.SavedQuerySpecAndConfig, error) {
+		if id != 1 {
+			return nil, sql.ErrNoRows
+		}
+		return &api.SavedQuerySpecAndConfig{Config: api.ConfigSavedQuery{
+			Query:     "repo:$service type:diff after:$since patternType:literal TODO",
+			UserID:    &userID,
+			Variables: []api.SavedQueryVariable{{Name: "service", Type: "repo"}, {Name: "since", Type: "date"}},
+		}}, nil
+	})
+	db := dbmocks.NewMockDB()
+	db.SavedSearchesFunc.SetDefaultReturn(savedSearches)
+
+	serve := func(uid int32, id int32, values url.Values) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", "/search/saved/x?"+values.Encode(), nil)
+		r = mux.SetURLVars(r, map[string]string{"ID": string(relay.MarshalID("SavedSearch", id))})
+		r = r.WithContext(actor.WithActor(r.Context(), actor.FromUser(uid)))
+		w := httptest.NewRecorder()
+		handler(db, serveSavedSearch(db)).ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("redirects to the search with the values of the variables", func(t *testing.T) {
+		w := serve(userID, 1, url.Values{"service": {"github.com/foo/bar"}, "since": {"2 weeks ago"}})
+		if w.Code != http.StatusFound {
+			t.Fatalf("want status %d, got %d: %s", http.StatusFound, w.Code, w.Body)
+		}
+		u, err := url.Parse(w.Header().Get("Location"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `repo:^github\.com/foo/bar$ type:diff after:"2 weeks ago" patternType:literal TODO`
+		if have := u.Query().Get("q"); u.Path != "/search" || have != want {
+			t.Fatalf("want redirect to /search with query %q, got %q", want, w.Header().Get("Location"))
+		}
+	})
+
+	t.Run("rejects invalid values", func(t *testing.T) {
+		w := serve(userID, 1, url.Values{"service": {"github.com/foo/bar"}})
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "missing value for variable $since") {
+			t.Fatalf("want missing value error, got %d: %s", w.Code, w.Body)
+		}
+	})
+
+	t.Run("hides saved searches of other users", func(t *testing.T) {
+		if w := serve(2, 1, nil); w.Code != http.StatusNotFound {
+			t.Fatalf("want status %d, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+
+	t.Run("unknown saved search", func(t *testing.T) {
+		if w := serve(userID, 2, nil); w.Code != http.StatusNotFound {
+			t.Fatalf("want status %d, got %d", http.StatusNotFound, w.Code)
+		}
+	})
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/auth"
 	bgql "github.com/sourcegraph/sourcegraph/internal/batches/graphql"
+	"github.com/sourcegraph/sourcegraph/internal/batches/reconciler"
 	"github.com/sourcegraph/sourcegraph/internal/batches/service"
 	"github.com/sourcegraph/sourcegraph/internal/batches/state"
 	"github.com/sourcegraph/sourcegraph/internal/batches/store"
@@ -288,6 +289,19 @@ func (r *batchChangeResolver) CurrentSpec(ctx context.Context) (graphqlbackend.B
 	return &batchSpecResolver{store: r.store, batchSpec: batchSpec, logger: r.logger}, nil
 }
 
+func (r *batchChangeResolver) RolloutWaves(ctx context.Context) (*[]graphqlbackend.BatchChangeRolloutWaveResolver, error) {
+	rollout, err := reconciler.LoadRolloutWaves(ctx, r.store, r.batchChange.ID)
+	if err != nil || rollout == nil {
+		return nil, err
+	}
+
+	resolvers := make([]graphqlbackend.BatchChangeRolloutWaveResolver, 0, len(rollout.Waves))
+	for _, wave := range rollout.Waves {
+		resolvers = append(resolvers, &batchChangeRolloutWaveResolver{wave: wave})
+	}
+	return &resolvers, nil
+}
+
 func (r *batchChangeResolver) BulkOperations(
 	ctx context.Context,
 	args *graphqlbackend.ListBatchChangeBulkOperationArgs,
@@ -0,0 +1,39 @@
+package resolvers
+
+import (
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/internal/batches/reconciler"
+)
+
+type batchChangeRolloutWaveResolver struct {
+	wave reconciler.RolloutWave
+}
+
+var _ graphqlbackend.BatchChangeRolloutWaveResolver = &batchChangeRolloutWaveResolver{}
+
+func (r *batchChangeRolloutWaveResolver) Index() int32 {
+	return int32(r.wave.Index)
+}
+
+func (r *batchChangeRolloutWaveResolver) State() string {
+	switch {
+	case !r.wave.Open:
+		return "WAITING"
+	case r.wave.Complete:
+		return "COMPLETE"
+	default:
+		return "ACTIVE"
+	}
+}
+
+func (r *batchChangeRolloutWaveResolver) TotalCount() int32 {
+	return int32(r.wave.Total)
+}
+
+func (r *batchChangeRolloutWaveResolver) PublishedCount() int32 {
+	return int32(r.wave.Published)
+}
+
+func (r *batchChangeRolloutWaveResolver) SucceededCount() int32 {
+	return int32(r.wave.Succeeded)
+}
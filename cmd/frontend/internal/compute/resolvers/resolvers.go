@@ -15,6 +15,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
 
 func NewResolver(logger log.Logger, db database.DB) gql.ComputeResolver {
@@ -167,6 +168,10 @@ func toComputeResultResolver(result compute.Result, repoResolver *gql.Repository
 		return &computeResultResolver{result: toComputeMatchContextResolver(r, repoResolver, path, commit)}
 	case *compute.Text:
 		return &computeResultResolver{result: toComputeTextResolver(r, repoResolver, path, commit)}
+	case *compute.TextExtra:
+		return &computeResultResolver{result: toComputeTextResolver(&r.Text, repoResolver, path, commit)}
+	case *compute.Aggregate:
+		return &computeResultResolver{result: toComputeTextResolver(&r.Text, repoResolver, path, commit)}
 	default:
 		panic(fmt.Sprintf("unsupported compute result %T", r))
 	}
@@ -197,6 +202,7 @@ func toResultResolverList(ctx context.Context, cmd compute.Command, matches []re
 		return resolver
 	}
 
+	aggregator := compute.NewAggregator(cmd)
 	results := make([]gql.ComputeResultResolver, 0, len(matches))
 	for _, m := range matches {
 		computeResult, err := cmd.Run(ctx, gitserverClient, m)
@@ -209,22 +215,26 @@ func toResultResolverList(ctx context.Context, cmd compute.Command, matches []re
 			continue
 		}
 
+		if aggregator != nil {
+			aggregator.Add(computeResult)
+			continue
+		}
+
 		repoResolver := getRepoResolver(m.RepoName())
 		path, commit := pathAndCommitFromResult(m)
 		resolver := toComputeResultResolver(computeResult, repoResolver, path, commit)
 		results = append(results, resolver)
 	}
-	return results, nil
-}
 
-// NewBatchComputeImplementer is a function that abstracts away the need to have a
-// handle on (*schemaResolver) Compute.
-func NewBatchComputeImplementer(ctx context.Context, logger log.Logger, db database.DB, args *gql.ComputeArgs) ([]gql.ComputeResultResolver, error) {
-	computeQuery, err := compute.Parse(args.Query)
-	if err != nil {
-		return nil, err
+	if aggregator != nil {
+		// The aggregate is not specific to a repository.
+		return []gql.ComputeResultResolver{toComputeResultResolver(aggregator.Result(false), nil, "", "")}, nil
 	}
+	return results, nil
+}
 
+// searchMatches runs the search query of a compute query.
+func searchMatches(ctx context.Context, logger log.Logger, db database.DB, computeQuery *compute.Query) ([]result.Match, error) {
 	searchQuery, err := computeQuery.ToSearchQuery()
 	if err != nil {
 		return nil, err
@@ -241,9 +251,52 @@ func NewBatchComputeImplementer(ctx context.Context, logger log.Logger, db datab
 	if err != nil {
 		return nil, err
 	}
-	return toResultResolverList(ctx, computeQuery.Command, results.Matches, db)
+	return results.Matches, nil
+}
+
+// NewBatchComputeImplementer is a function that abstracts away the need to have a
+// handle on (*schemaResolver) Compute.
+func NewBatchComputeImplementer(ctx context.Context, logger log.Logger, db database.DB, args *gql.ComputeArgs) ([]gql.ComputeResultResolver, error) {
+	computeQuery, err := compute.Parse(args.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := searchMatches(ctx, logger, db, computeQuery)
+	if err != nil {
+		return nil, err
+	}
+	return toResultResolverList(ctx, computeQuery.Command, matches, db)
 }
 
 func (r *Resolver) Compute(ctx context.Context, args *gql.ComputeArgs) ([]gql.ComputeResultResolver, error) {
 	return NewBatchComputeImplementer(ctx, r.logger, r.db, args)
 }
+
+func (r *Resolver) ReplaceBatchSpec(ctx context.Context, query, name string) (string, error) {
+	computeQuery, err := compute.Parse(query)
+	if err != nil {
+		return "", err
+	}
+	if cmd, ok := computeQuery.Command.(*compute.Replace); !ok || !cmd.Diff {
+		return "", errors.New("batch specs can only be created from content:replace.diff compute queries")
+	}
+
+	matches, err := searchMatches(ctx, r.logger, r.db, computeQuery)
+	if err != nil {
+		return "", err
+	}
+
+	gitserverClient := gitserver.NewClient("graphql.compute.batchspec")
+	var diffs []*compute.TextExtra
+	for _, m := range matches {
+		computeResult, err := computeQuery.Command.Run(ctx, gitserverClient, m)
+		if err != nil {
+			return "", err
+		}
+		if diff, ok := computeResult.(*compute.TextExtra); ok {
+			diffs = append(diffs, diff)
+		}
+	}
+	return compute.NewReplaceBatchSpec(name, query, diffs)
+}
@@ -115,12 +115,27 @@ func (h *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	pingTicker := time.NewTicker(h.pingTickerInterval)
 	defer pingTicker.Stop()
 
+	// Aggregating commands are evaluated here as results stream in. Instead
+	// of the results of every match, clients receive partial aggregates on
+	// every flush and a final aggregate once the search is done.
+	aggregator := compute.NewAggregator(computeQuery.Command)
+	aggregateFlush := func(partial bool) {
+		if aggregator != nil && (aggregator.Dirty() || !partial) {
+			_ = matchesBuf.Append(aggregator.Result(partial))
+		}
+		matchesFlush()
+	}
+
 	first := true
 	handleEvent := func(event Event) {
 		progress.Dirty = true
 		progress.Stats.Update(&event.Stats)
 
 		for _, result := range event.Results {
+			if aggregator != nil {
+				aggregator.Add(result)
+				continue
+			}
 			_ = matchesBuf.Append(result)
 		}
 
@@ -140,13 +155,13 @@ LOOP:
 			}
 			handleEvent(event)
 		case <-flushTicker.C:
-			matchesFlush()
+			aggregateFlush(true)
 		case <-pingTicker.C:
 			sendProgress()
 		}
 	}
 
-	matchesFlush()
+	aggregateFlush(false)
 
 	alert, err := getResults()
 	if err != nil {
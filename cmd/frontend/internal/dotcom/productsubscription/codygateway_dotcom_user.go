@@ -18,6 +18,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/audit"
 	"github.com/sourcegraph/sourcegraph/internal/codygateway"
 	"github.com/sourcegraph/sourcegraph/internal/completions/client/fireworks"
+	"github.com/sourcegraph/sourcegraph/internal/completions/client/google"
+	"github.com/sourcegraph/sourcegraph/internal/completions/client/mistral"
 	"github.com/sourcegraph/sourcegraph/internal/completions/types"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
@@ -352,6 +354,8 @@ func allowedModels(scope types.CompletionsFeature, isProUser bool) []string {
 				"anthropic/claude-instant-v1",
 				"anthropic/claude-instant-1.2",
 				"anthropic/claude-instant-1",
+				"google/" + google.GeminiPro,
+				"mistral/" + mistral.MistralSmall,
 			}
 		}
 
@@ -366,6 +370,12 @@ func allowedModels(scope types.CompletionsFeature, isProUser bool) []string {
 			"openai/gpt-3.5-turbo",
 			"openai/gpt-4-1106-preview",
 			"fireworks/" + fireworks.Mixtral8x7bInstruct,
+			"google/" + google.GeminiPro,
+			"google/" + google.Gemini15ProLatest,
+			"mistral/" + mistral.MistralSmall,
+			"mistral/" + mistral.MistralMedium,
+			"mistral/" + mistral.MistralLarge,
+			"mistral/" + mistral.OpenMixtral8x7b,
 		}
 	case types.CompletionsFeatureCode:
 		return []string{
@@ -377,6 +387,8 @@ func allowedModels(scope types.CompletionsFeature, isProUser bool) []string {
 			// TODO: Remove the specific model identifiers below when Cody Gateway for PLG was updated.
 			"fireworks/" + fireworks.Starcoder16b,
 			"fireworks/" + fireworks.Starcoder7b,
+			"google/" + google.GeminiPro,
+			"mistral/" + mistral.MistralSmall,
 		}
 	default:
 		return []string{}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/grafana/regexp"
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/internal/executor/store"
+	executortypes "github.com/sourcegraph/sourcegraph/internal/executor/types"
+	"github.com/sourcegraph/sourcegraph/internal/workerutil"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// StepCacheHandler serves the step cache entries of the executor queues that opted
+// into the step cache.
+type StepCacheHandler struct {
+	store         store.StepCacheStore
+	jobTokenStore store.JobTokenStore
+	logger        log.Logger
+}
+
+// NewStepCacheHandler creates a new StepCacheHandler backed by the given store.
+func NewStepCacheHandler(stepCacheStore store.StepCacheStore, jobTokenStore store.JobTokenStore) *StepCacheHandler {
+	return &StepCacheHandler{
+		store:         stepCacheStore,
+		jobTokenStore: jobTokenStore,
+		logger:        log.Scoped("executor-step-cache-handler"),
+	}
+}
+
+// HandleGet streams the cache entry for the requested key. If there is no such
+// entry, a 404 is returned.
+func (h *StepCacheHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	repoID, ok := h.jobRepoID(w, r)
+	if !ok {
+		return
+	}
+
+	rc, err := h.store.Get(r.Context(), vars["queueName"], repoID, vars["key"])
+	if err != nil {
+		if errors.Is(err, store.ErrStepCacheMiss) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		h.logger.Error("Failed to get step cache entry", log.String("key", vars["key"]), log.Error(err))
+		http.Error(w, fmt.Sprintf("Failed to get step cache entry: %s", err), http.StatusInternalServerError)
+		return
+	}
+	defer rc.Close()
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, rc); err != nil {
+		h.logger.Error("Failed to write step cache entry", log.String("key", vars["key"]), log.Error(err))
+	}
+}
+
+// HandlePut stores the request body as the cache entry for the requested key.
+func (h *StepCacheHandler) HandlePut(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	repoID, ok := h.jobRepoID(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := h.store.Put(r.Context(), vars["queueName"], repoID, vars["key"], r.Body); err != nil {
+		h.logger.Error("Failed to put step cache entry", log.String("key", vars["key"]), log.Error(err))
+		http.Error(w, fmt.Sprintf("Failed to put step cache entry: %s", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// jobRepoID returns the ID of the repository the job of the request runs against.
+//
+// 🚨 SECURITY: The cache key is computed by the executor, so it can't be trusted to
+// identify the repository. Entries are scoped to the repository of the job token
+// instead, so that a job can't read or overwrite the entries of other repositories.
+// Requests authenticated with the general executor access token are rejected, as
+// they are not tied to a repository.
+func (h *StepCacheHandler) jobRepoID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+	if scheme != "Bearer" || token == "" {
+		http.Error(w, "step cache requests must be authenticated with a job token", http.StatusForbidden)
+		return 0, false
+	}
+
+	jobToken, err := h.jobTokenStore.GetByToken(r.Context(), token)
+	if err != nil {
+		h.logger.Error("Failed to retrieve job token", log.Error(err))
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return 0, false
+	}
+
+	return jobToken.RepoID, true
+}
+
+// SetupStepCacheRoutes registers the step cache route handlers for the given queue
+// with the given router. The router is expected to authenticate requests with the
+// job token, which is scoped to the queue.
+func SetupStepCacheRoutes(queueName string, handler *StepCacheHandler, router *mux.Router) {
+	subRouter := router.PathPrefix(fmt.Sprintf("/{queueName:(?:%s)}", regexp.QuoteMeta(queueName))).Subrouter()
+	subRouter.Path("/stepCache/{key:[0-9a-f]{64}}").Methods(http.MethodGet).HandlerFunc(handler.HandleGet)
+	subRouter.Path("/stepCache/{key:[0-9a-f]{64}}").Methods(http.MethodPut).HandlerFunc(handler.HandlePut)
+}
+
+// WithoutStepCache returns a copy of the given queue handler whose jobs do not
+// declare any cacheable step outputs, so that executors never consult the step
+// cache for them. This is used for queues that did not opt into the step cache.
+func WithoutStepCache[T workerutil.Record](queueHandler QueueHandler[T]) QueueHandler[T] {
+	transform := queueHandler.RecordTransformer
+	queueHandler.RecordTransformer = func(ctx context.Context, version string, record T, resourceMetadata ResourceMetadata) (executortypes.Job, error) {
+		job, err := transform(ctx, version, record, resourceMetadata)
+		for i := range job.DockerSteps {
+			job.DockerSteps[i].CacheOutputs = nil
+		}
+		return job, err
+	}
+	return queueHandler
+}
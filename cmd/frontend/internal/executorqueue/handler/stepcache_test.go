@@ -0,0 +1,163 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/executorqueue/handler"
+	executorstore "github.com/sourcegraph/sourcegraph/internal/executor/store"
+	executortypes "github.com/sourcegraph/sourcegraph/internal/executor/types"
+)
+
+const testStepCacheKey = "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+
+func TestSetupStepCacheRoutes(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		path               string
+		authorization      string
+		body               string
+		entries            map[string]string
+		expectedStatusCode int
+		expectedBody       string
+		expectedEntries    map[string]string
+	}{
+		{
+			name:               "Get hit",
+			method:             http.MethodGet,
+			path:               "/test/stepCache/" + testStepCacheKey,
+			entries:            map[string]string{"test/42/" + testStepCacheKey: "outputs"},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "outputs",
+		},
+		{
+			name:               "Get entry of other repository",
+			method:             http.MethodGet,
+			path:               "/test/stepCache/" + testStepCacheKey,
+			entries:            map[string]string{"test/43/" + testStepCacheKey: "outputs"},
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "Get miss",
+			method:             http.MethodGet,
+			path:               "/test/stepCache/" + testStepCacheKey,
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{
+			name:               "Put",
+			method:             http.MethodPut,
+			path:               "/test/stepCache/" + testStepCacheKey,
+			body:               "outputs",
+			expectedStatusCode: http.StatusNoContent,
+			expectedEntries:    map[string]string{"test/42/" + testStepCacheKey: "outputs"},
+		},
+		{
+			name:               "Put with executor access token",
+			method:             http.MethodPut,
+			path:               "/test/stepCache/" + testStepCacheKey,
+			authorization:      "token-executor hunter2",
+			body:               "outputs",
+			expectedStatusCode: http.StatusForbidden,
+			expectedBody:       "step cache requests must be authenticated with a job token\n",
+			expectedEntries:    map[string]string{},
+		},
+		{
+			name:               "Invalid key",
+			method:             http.MethodGet,
+			path:               "/test/stepCache/foo",
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       "404 page not found\n",
+		},
+		{
+			name:               "Queue not opted in",
+			method:             http.MethodGet,
+			path:               "/test1/stepCache/" + testStepCacheKey,
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       "404 page not found\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stepCacheStore := &testStepCacheStore{entries: map[string]string{}}
+			for k, v := range test.entries {
+				stepCacheStore.entries[k] = v
+			}
+
+			jobTokenStore := executorstore.NewMockJobTokenStore()
+			jobTokenStore.GetByTokenFunc.SetDefaultReturn(executorstore.JobToken{JobID: 1, Queue: "test", RepoID: 42}, nil)
+
+			router := mux.NewRouter()
+			handler.SetupStepCacheRoutes("test", handler.NewStepCacheHandler(stepCacheStore, jobTokenStore), router)
+
+			req, err := http.NewRequest(test.method, test.path, bytes.NewReader([]byte(test.body)))
+			require.NoError(t, err)
+			authorization := test.authorization
+			if authorization == "" {
+				authorization = "Bearer sometoken"
+			}
+			req.Header.Set("Authorization", authorization)
+			responseRecorder := httptest.NewRecorder()
+
+			router.ServeHTTP(responseRecorder, req)
+
+			assert.Equal(t, test.expectedStatusCode, responseRecorder.Code)
+			assert.Equal(t, test.expectedBody, responseRecorder.Body.String())
+			if test.expectedEntries != nil {
+				assert.Equal(t, test.expectedEntries, stepCacheStore.entries)
+			}
+		})
+	}
+}
+
+func TestWithoutStepCache(t *testing.T) {
+	queueHandler := handler.QueueHandler[testRecord]{
+		Name: "test",
+		RecordTransformer: func(ctx context.Context, version string, record testRecord, resourceMetadata handler.ResourceMetadata) (executortypes.Job, error) {
+			return executortypes.Job{
+				ID: record.RecordID(),
+				DockerSteps: []executortypes.DockerStep{
+					{Key: "indexer", CacheOutputs: []string{"index.scip"}},
+					{Key: "upload"},
+				},
+			}, nil
+		},
+	}
+
+	job, err := handler.WithoutStepCache(queueHandler).RecordTransformer(context.Background(), "", testRecord{id: 42}, handler.ResourceMetadata{})
+	require.NoError(t, err)
+	assert.Equal(t, 42, job.ID)
+	require.Len(t, job.DockerSteps, 2)
+	assert.Nil(t, job.DockerSteps[0].CacheOutputs)
+	assert.Nil(t, job.DockerSteps[1].CacheOutputs)
+}
+
+type testStepCacheStore struct {
+	entries map[string]string
+}
+
+func (s *testStepCacheStore) Get(_ context.Context, queue string, repoID int64, key string) (io.ReadCloser, error) {
+	v, ok := s.entries[fmt.Sprintf("%s/%d/%s", queue, repoID, key)]
+	if !ok {
+		return nil, executorstore.ErrStepCacheMiss
+	}
+	return io.NopCloser(bytes.NewReader([]byte(v))), nil
+}
+
+func (s *testStepCacheStore) Put(_ context.Context, queue string, repoID int64, key string, r io.Reader) (int64, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	s.entries[fmt.Sprintf("%s/%d/%s", queue, repoID, key)] = string(b)
+	return int64(len(b)), nil
+}
@@ -1,15 +1,22 @@
 package executorqueue
 
 import (
+	"context"
+
 	"github.com/sourcegraph/log"
 
 	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
 	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/executor/store"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/enterprise"
 )
 
+func LoadConfig() {
+	stepCacheConfigInst.Load()
+}
+
 // Init initializes the executor endpoints required for use with the executor service.
 func Init(
 	observationCtx *observation.Context,
@@ -27,6 +34,15 @@ func Init(
 
 	logger := log.Scoped("executorqueue")
 
+	if err := stepCacheConfigInst.Validate(); err != nil {
+		return err
+	}
+	stepCacheUploadStore, err := newStepCacheUploadStore(context.Background(), observationCtx, stepCacheConfigInst)
+	if err != nil {
+		return err
+	}
+	stepCacheStore := store.NewStepCacheStore(observationCtx, stepCacheUploadStore)
+
 	queueHandler := newExecutorQueuesHandler(
 		observationCtx,
 		db,
@@ -35,6 +51,8 @@ func Init(
 		codeintelUploadHandler,
 		batchesWorkspaceFileGetHandler,
 		batchesWorkspaceFileExistsHandler,
+		stepCacheStore,
+		stepCacheConfigInst.Queues,
 	)
 
 	enterpriseServices.NewExecutorProxyHandler = queueHandler
@@ -9,6 +9,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/sourcegraph/log"
+	"golang.org/x/exp/slices"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/executorqueue/handler"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/executorqueue/queues/batches"
@@ -31,6 +32,8 @@ func newExecutorQueuesHandler(
 	uploadHandler http.Handler,
 	batchesWorkspaceFileGetHandler http.Handler,
 	batchesWorkspaceFileExistsHandler http.Handler,
+	stepCacheStore store.StepCacheStore,
+	stepCacheQueues []string,
 ) func() http.Handler {
 	metricsStore := metricsstore.NewDistributedStore("executors:")
 	executorStore := db.Executors()
@@ -44,6 +47,15 @@ func newExecutorQueuesHandler(
 	codeIntelQueueHandler := codeintelqueue.QueueHandler(observationCtx, db, accessToken)
 	batchesQueueHandler := batches.QueueHandler(observationCtx, db, accessToken)
 
+	// Jobs of queues that did not opt into the step cache must not reference it.
+	if !slices.Contains(stepCacheQueues, codeIntelQueueHandler.Name) {
+		codeIntelQueueHandler = handler.WithoutStepCache(codeIntelQueueHandler)
+	}
+	if !slices.Contains(stepCacheQueues, batchesQueueHandler.Name) {
+		batchesQueueHandler = handler.WithoutStepCache(batchesQueueHandler)
+	}
+	stepCacheHandler := handler.NewStepCacheHandler(stepCacheStore, jobTokenStore)
+
 	codeintelHandler := handler.NewHandler(executorStore, jobTokenStore, metricsStore, codeIntelQueueHandler)
 	batchesHandler := handler.NewHandler(executorStore, jobTokenStore, metricsStore, batchesQueueHandler)
 	handlers := []handler.ExecutorHandler{codeintelHandler, batchesHandler}
@@ -94,6 +106,9 @@ func newExecutorQueuesHandler(
 		for _, h := range handlers {
 			handler.SetupRoutes(h, queueRouter)
 			handler.SetupJobRoutes(h, jobRouter)
+			if slices.Contains(stepCacheQueues, h.Name()) {
+				handler.SetupStepCacheRoutes(h.Name(), stepCacheHandler, jobRouter)
+			}
 		}
 
 		// Upload LSIF indexes without a sudo access token or github tokens.
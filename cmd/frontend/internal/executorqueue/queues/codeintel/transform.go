@@ -84,6 +84,11 @@ func transformRecord(ctx context.Context, db database.DB, index uploadsshared.In
 		})
 	}
 
+	outfile := index.Outfile
+	if outfile == "" {
+		outfile = defaultOutfile
+	}
+
 	if index.Indexer != "" {
 		dockerSteps = append(dockerSteps, apiclient.DockerStep{
 			Key:      "indexer",
@@ -91,6 +96,9 @@ func transformRecord(ctx context.Context, db database.DB, index uploadsshared.In
 			Commands: append(index.LocalSteps, shellquote.Join(index.IndexerArgs...)),
 			Dir:      index.Root,
 			Env:      envVars,
+			// The index is the only output of the indexer that the upload step
+			// depends on, so it can be restored from the step cache instead.
+			CacheOutputs: []string{outfile},
 		})
 	}
 
@@ -104,11 +112,6 @@ func transformRecord(ctx context.Context, db database.DB, index uploadsshared.In
 		root = "."
 	}
 
-	outfile := index.Outfile
-	if outfile == "" {
-		outfile = defaultOutfile
-	}
-
 	// TODO: Temporary workaround. LSIF-go needs tags, but they make git fetching slower.
 	fetchTags := strings.HasPrefix(index.Indexer, conf.ExecutorsLsifGoImage())
 
@@ -102,11 +102,12 @@ func TestTransformRecord(t *testing.T) {
 						Env:      testCase.expected,
 					},
 					{
-						Key:      "indexer",
-						Image:    "lsif-node",
-						Commands: []string{"index -p . -author 'Test User'"},
-						Dir:      "web",
-						Env:      testCase.expected,
+						Key:          "indexer",
+						Image:        "lsif-node",
+						Commands:     []string{"index -p . -author 'Test User'"},
+						Dir:          "web",
+						Env:          testCase.expected,
+						CacheOutputs: []string{"dump.lsif"},
 					},
 					{
 						Key:   "upload",
@@ -333,11 +334,12 @@ func TestTransformRecordWithSecrets(t *testing.T) {
 						Env:      testCase.expected,
 					},
 					{
-						Key:      "indexer",
-						Image:    "lsif-node",
-						Commands: []string{"index -p . -author 'Test User'"},
-						Dir:      "web",
-						Env:      testCase.expected,
+						Key:          "indexer",
+						Image:        "lsif-node",
+						Commands:     []string{"index -p . -author 'Test User'"},
+						Dir:          "web",
+						Env:          testCase.expected,
+						CacheOutputs: []string{"dump.lsif"},
 					},
 					{
 						Key:   "upload",
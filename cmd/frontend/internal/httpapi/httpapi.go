@@ -239,6 +239,9 @@ func RegisterInternalServices(
 		SearchContextsRepoRevs: func(ctx context.Context, repoIDs []api.RepoID) (map[api.RepoID][]string, error) {
 			return searchcontexts.RepoRevs(ctx, db, repoIDs)
 		},
+		SearchContextsForRepos: func(ctx context.Context, specs []string, repoIDs []api.RepoID) (map[api.RepoID][]string, error) {
+			return searchcontexts.RepoSearchContexts(ctx, db, specs, repoIDs)
+		},
 		Indexers:               search.Indexers(),
 		Ranking:                rankingService,
 		MinLastChangedDisabled: os.Getenv("SRC_SEARCH_INDEXER_EFFICIENT_POLLING_DISABLED") != "",
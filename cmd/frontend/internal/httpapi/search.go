@@ -157,6 +157,11 @@ type searchIndexerServer struct {
 
 	SearchContextsRepoRevs func(context.Context, []api.RepoID) (map[api.RepoID][]string, error)
 
+	// SearchContextsForRepos returns, for each repo ID, which of the given
+	// search context specs it is part of. It is used to match the
+	// search.index.overrides rules which reference a search context.
+	SearchContextsForRepos func(ctx context.Context, specs []string, repoIDs []api.RepoID) (map[api.RepoID][]string, error)
+
 	// Indexers is the subset of searchbackend.Indexers methods we
 	// use. reposListServer is used by indexed-search to get the list of
 	// repositories to index. These methods are used to return the correct
@@ -235,10 +240,21 @@ func (h *searchIndexerServer) doSearchConfiguration(ctx context.Context, paramet
 		rankingLastUpdatedAt = make(map[api.RepoID]time.Time)
 	}
 
+	var searchContextsForRepo map[api.RepoID][]string
+	var searchContextsForRepoErr error
+	if specs := searchbackend.SiteConfigOverridesSearchContexts(&siteConfig); len(specs) > 0 {
+		searchContextsForRepo, searchContextsForRepoErr = h.SearchContextsForRepos(ctx, specs, parameters.repoIDs)
+	}
+
 	getRepoIndexOptions := func(repoID api.RepoID) (*searchbackend.RepoIndexOptions, error) {
 		if loadReposErr != nil {
 			return nil, loadReposErr
 		}
+		// Fail rather than ignore the search context rules, which would
+		// flip the overrides and cause a re-index.
+		if searchContextsForRepoErr != nil {
+			return nil, searchContextsForRepoErr
+		}
 		// Replicate what database.Repos.GetByName would do here:
 		repo, ok := reposMap[repoID]
 		if !ok {
@@ -276,6 +292,8 @@ func (h *searchIndexerServer) doSearchConfiguration(ctx context.Context, paramet
 			GetVersion: getVersion,
 
 			DocumentRanksVersion: documentRanksVersion,
+			KeyValuePairs:        repo.KeyValuePairs,
+			SearchContexts:       searchContextsForRepo[repoID],
 		}, nil
 	}
 
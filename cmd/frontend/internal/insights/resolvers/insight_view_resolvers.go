@@ -1203,6 +1203,9 @@ func (r *InsightViewQueryConnectionResolver) computeViews(ctx context.Context) (
 		if r.args.Find != nil {
 			args.Find = *r.args.Find
 		}
+		if r.args.SeriesId != nil {
+			args.SeriesID = *r.args.SeriesId
+		}
 
 		var err error
 		args.UserIDs, args.OrgIDs, err = getUserPermissions(ctx, orgStore)
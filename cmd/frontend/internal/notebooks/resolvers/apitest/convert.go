@@ -30,6 +30,22 @@ func BlockToAPIResponse(block notebooks.NotebookBlock) NotebookBlock {
 			SymbolContainerName: block.SymbolInput.SymbolContainerName,
 			SymbolKind:          block.SymbolInput.SymbolKind,
 		}}
+	case notebooks.NotebookComputeBlockType:
+		return NotebookBlock{Typename: "ComputeBlock", ID: block.ID, ComputeInput: ComputeInput{
+			Text:   block.ComputeInput.Text,
+			Output: block.ComputeInput.Output,
+		}}
+	case notebooks.NotebookInsightBlockType:
+		return NotebookBlock{Typename: "InsightBlock", ID: block.ID, InsightSeriesID: block.InsightInput.SeriesID}
+	case notebooks.NotebookReferencesBlockType:
+		return NotebookBlock{Typename: "ReferencesBlock", ID: block.ID, ReferencesInput: ReferencesInput{
+			RepositoryName: block.ReferencesInput.RepositoryName,
+			FilePath:       block.ReferencesInput.FilePath,
+			Commit:         block.ReferencesInput.Commit,
+			Line:           block.ReferencesInput.Line,
+			Character:      block.ReferencesInput.Character,
+			SymbolName:     block.ReferencesInput.SymbolName,
+		}}
 	}
 	panic("unknown block type")
 }
@@ -75,6 +91,22 @@ func BlockToAPIInput(block notebooks.NotebookBlock) graphqlbackend.CreateNoteboo
 			SymbolContainerName: block.SymbolInput.SymbolContainerName,
 			SymbolKind:          block.SymbolInput.SymbolKind,
 		}}
+	case notebooks.NotebookComputeBlockType:
+		return graphqlbackend.CreateNotebookBlockInputArgs{ID: block.ID, Type: graphqlbackend.NotebookComputeBlockType, ComputeInput: &graphqlbackend.CreateComputeBlockInput{
+			Text:   block.ComputeInput.Text,
+			Output: block.ComputeInput.Output,
+		}}
+	case notebooks.NotebookInsightBlockType:
+		return graphqlbackend.CreateNotebookBlockInputArgs{ID: block.ID, Type: graphqlbackend.NotebookInsightBlockType, InsightSeriesID: &block.InsightInput.SeriesID}
+	case notebooks.NotebookReferencesBlockType:
+		return graphqlbackend.CreateNotebookBlockInputArgs{ID: block.ID, Type: graphqlbackend.NotebookReferencesBlockType, ReferencesInput: &graphqlbackend.CreateReferencesBlockInput{
+			RepositoryName: block.ReferencesInput.RepositoryName,
+			FilePath:       block.ReferencesInput.FilePath,
+			Commit:         block.ReferencesInput.Commit,
+			Line:           block.ReferencesInput.Line,
+			Character:      block.ReferencesInput.Character,
+			SymbolName:     block.ReferencesInput.SymbolName,
+		}}
 	}
 	panic("unknown block type")
 }
@@ -17,12 +17,15 @@ type NotebookUser struct {
 }
 
 type NotebookBlock struct {
-	Typename      string `json:"__typename"`
-	ID            string
-	MarkdownInput string
-	QueryInput    string
-	FileInput     FileInput
-	SymbolInput   SymbolInput
+	Typename        string `json:"__typename"`
+	ID              string
+	MarkdownInput   string
+	QueryInput      string
+	FileInput       FileInput
+	SymbolInput     SymbolInput
+	ComputeInput    ComputeInput
+	InsightSeriesID string
+	ReferencesInput ReferencesInput
 }
 
 type FileInput struct {
@@ -42,6 +45,20 @@ type SymbolInput struct {
 	SymbolKind          string
 }
 
+type ComputeInput struct {
+	Text   string
+	Output *string
+}
+
+type ReferencesInput struct {
+	RepositoryName string
+	FilePath       string
+	Commit         string
+	Line           int32
+	Character      int32
+	SymbolName     string
+}
+
 type LineRange struct {
 	StartLine int32
 	EndLine   int32
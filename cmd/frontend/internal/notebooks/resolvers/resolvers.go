@@ -2,11 +2,13 @@ package resolvers
 
 import (
 	"context"
+	"strings"
 
 	"github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
 	"github.com/sourcegraph/sourcegraph/internal/database"
@@ -108,6 +110,34 @@ func convertNotebookBlockInput(inputBlock graphqlbackend.CreateNotebookBlockInpu
 			SymbolContainerName: inputBlock.SymbolInput.SymbolContainerName,
 			SymbolKind:          inputBlock.SymbolInput.SymbolKind,
 		}
+	case graphqlbackend.NotebookComputeBlockType:
+		if inputBlock.ComputeInput == nil {
+			return nil, errors.Errorf("compute block with id %s is missing input", inputBlock.ID)
+		}
+		block.Type = notebooks.NotebookComputeBlockType
+		block.ComputeInput = &notebooks.NotebookComputeBlockInput{
+			Text:   inputBlock.ComputeInput.Text,
+			Output: inputBlock.ComputeInput.Output,
+		}
+	case graphqlbackend.NotebookInsightBlockType:
+		if inputBlock.InsightSeriesID == nil {
+			return nil, errors.Errorf("insight block with id %s is missing input", inputBlock.ID)
+		}
+		block.Type = notebooks.NotebookInsightBlockType
+		block.InsightInput = &notebooks.NotebookInsightBlockInput{SeriesID: *inputBlock.InsightSeriesID}
+	case graphqlbackend.NotebookReferencesBlockType:
+		if inputBlock.ReferencesInput == nil {
+			return nil, errors.Errorf("references block with id %s is missing input", inputBlock.ID)
+		}
+		block.Type = notebooks.NotebookReferencesBlockType
+		block.ReferencesInput = &notebooks.NotebookReferencesBlockInput{
+			RepositoryName: inputBlock.ReferencesInput.RepositoryName,
+			FilePath:       inputBlock.ReferencesInput.FilePath,
+			Commit:         inputBlock.ReferencesInput.Commit,
+			Line:           inputBlock.ReferencesInput.Line,
+			Character:      inputBlock.ReferencesInput.Character,
+			SymbolName:     inputBlock.ReferencesInput.SymbolName,
+		}
 	default:
 		return nil, errors.Newf("invalid block type: %s", inputBlock.Type)
 	}
@@ -209,6 +239,101 @@ func (r *Resolver) UpdateNotebook(ctx context.Context, args graphqlbackend.Updat
 	return &notebookResolver{updatedNotebook, r.db}, nil
 }
 
+func (r *Resolver) ImportNotebook(ctx context.Context, args graphqlbackend.ImportNotebookArgs) (graphqlbackend.NotebookResolver, error) {
+	user, err := r.db.Users().GetByCurrentAuthUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	imported, err := importNotebook(args.Notebook)
+	if err != nil {
+		return nil, err
+	}
+
+	var namespaceUserID, namespaceOrgID int32
+	err = graphqlbackend.UnmarshalNamespaceID(args.Notebook.Namespace, &namespaceUserID, &namespaceOrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	store := notebooks.Notebooks(r.db)
+	if args.ID == nil {
+		notebook := &notebooks.Notebook{
+			Title:           imported.Title,
+			Blocks:          imported.Blocks,
+			Public:          args.Notebook.Public,
+			CreatorUserID:   user.ID,
+			UpdaterUserID:   user.ID,
+			NamespaceUserID: namespaceUserID,
+			NamespaceOrgID:  namespaceOrgID,
+		}
+		err = validateNotebookWritePermissionsForUser(ctx, r.db, notebook, user.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		createdNotebook, err := store.CreateNotebook(ctx, notebook)
+		if err != nil {
+			return nil, err
+		}
+		return &notebookResolver{createdNotebook, r.db}, nil
+	}
+
+	id, err := unmarshalNotebookID(*args.ID)
+	if err != nil {
+		return nil, err
+	}
+	notebook, err := store.GetNotebook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	err = validateNotebookWritePermissionsForUser(ctx, r.db, notebook, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	notebook.Title = imported.Title
+	notebook.Blocks = imported.Blocks
+	notebook.Public = args.Notebook.Public
+	notebook.UpdaterUserID = user.ID
+	notebook.NamespaceUserID = namespaceUserID
+	notebook.NamespaceOrgID = namespaceOrgID
+	// Current user has to have write permissions for both the old and the new namespace.
+	err = validateNotebookWritePermissionsForUser(ctx, r.db, notebook, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedNotebook, err := store.UpdateNotebook(ctx, notebook)
+	if err != nil {
+		return nil, err
+	}
+	return &notebookResolver{updatedNotebook, r.db}, nil
+}
+
+func importNotebook(input graphqlbackend.ImportNotebookInput) (*notebooks.Notebook, error) {
+	switch input.Format {
+	case graphqlbackend.NotebookExportFormatMarkdown:
+		if input.Title == nil {
+			return nil, errors.New("title is required to import a notebook from Markdown")
+		}
+		return notebooks.ImportMarkdown(*input.Title, input.Content, globals.ExternalURL())
+	case graphqlbackend.NotebookExportFormatJSON:
+		notebook, err := notebooks.ImportJSON([]byte(input.Content))
+		if err != nil {
+			return nil, err
+		}
+		if input.Title != nil {
+			if strings.TrimSpace(*input.Title) == "" {
+				return nil, errors.New("notebook title cannot be empty")
+			}
+			notebook.Title = *input.Title
+		}
+		return notebook, nil
+	}
+	return nil, errors.Errorf("unsupported notebook format: %s", input.Format)
+}
+
 func (r *Resolver) DeleteNotebook(ctx context.Context, args graphqlbackend.DeleteNotebookArgs) (*graphqlbackend.EmptyResponse, error) {
 	user, err := r.db.Users().GetByCurrentAuthUser(ctx)
 	if err != nil {
@@ -383,6 +508,20 @@ func (r *notebookResolver) Blocks(ctx context.Context) []graphqlbackend.Notebook
 	return blockResolvers
 }
 
+func (r *notebookResolver) Export(ctx context.Context, args graphqlbackend.NotebookExportArgs) (string, error) {
+	switch args.Format {
+	case graphqlbackend.NotebookExportFormatMarkdown:
+		return notebooks.ExportMarkdown(r.notebook, globals.ExternalURL()), nil
+	case graphqlbackend.NotebookExportFormatJSON:
+		data, err := notebooks.ExportJSON(r.notebook)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+	return "", errors.Errorf("unsupported notebook format: %s", args.Format)
+}
+
 func (r *notebookResolver) Creator(ctx context.Context) (*graphqlbackend.UserResolver, error) {
 	if r.notebook.CreatorUserID == 0 {
 		return nil, nil
@@ -513,6 +652,27 @@ func (r *notebookBlockResolver) ToSymbolBlock() (graphqlbackend.SymbolBlockResol
 	return nil, false
 }
 
+func (r *notebookBlockResolver) ToComputeBlock() (graphqlbackend.ComputeBlockResolver, bool) {
+	if r.block.Type == notebooks.NotebookComputeBlockType {
+		return &computeBlockResolver{r.block}, true
+	}
+	return nil, false
+}
+
+func (r *notebookBlockResolver) ToInsightBlock() (graphqlbackend.InsightBlockResolver, bool) {
+	if r.block.Type == notebooks.NotebookInsightBlockType {
+		return &insightBlockResolver{r.block}, true
+	}
+	return nil, false
+}
+
+func (r *notebookBlockResolver) ToReferencesBlock() (graphqlbackend.ReferencesBlockResolver, bool) {
+	if r.block.Type == notebooks.NotebookReferencesBlockType {
+		return &referencesBlockResolver{r.block}, true
+	}
+	return nil, false
+}
+
 type markdownBlockResolver struct {
 	// block.type == NotebookMarkdownBlockType
 	block notebooks.NotebookBlock
@@ -631,3 +791,82 @@ func (r *symbolBlockInputResolver) SymbolContainerName() string {
 func (r *symbolBlockInputResolver) SymbolKind() string {
 	return r.input.SymbolKind
 }
+
+type computeBlockResolver struct {
+	// block.type == NotebookComputeBlockType
+	block notebooks.NotebookBlock
+}
+
+func (r *computeBlockResolver) ID() string {
+	return r.block.ID
+}
+
+func (r *computeBlockResolver) ComputeInput() graphqlbackend.ComputeBlockInputResolver {
+	return &computeBlockInputResolver{*r.block.ComputeInput}
+}
+
+type computeBlockInputResolver struct {
+	input notebooks.NotebookComputeBlockInput
+}
+
+func (r *computeBlockInputResolver) Text() string {
+	return r.input.Text
+}
+
+func (r *computeBlockInputResolver) Output() *string {
+	return r.input.Output
+}
+
+type insightBlockResolver struct {
+	// block.type == NotebookInsightBlockType
+	block notebooks.NotebookBlock
+}
+
+func (r *insightBlockResolver) ID() string {
+	return r.block.ID
+}
+
+func (r *insightBlockResolver) InsightSeriesID() string {
+	return r.block.InsightInput.SeriesID
+}
+
+type referencesBlockResolver struct {
+	// block.type == NotebookReferencesBlockType
+	block notebooks.NotebookBlock
+}
+
+func (r *referencesBlockResolver) ID() string {
+	return r.block.ID
+}
+
+func (r *referencesBlockResolver) ReferencesInput() graphqlbackend.ReferencesBlockInputResolver {
+	return &referencesBlockInputResolver{*r.block.ReferencesInput}
+}
+
+type referencesBlockInputResolver struct {
+	input notebooks.NotebookReferencesBlockInput
+}
+
+func (r *referencesBlockInputResolver) RepositoryName() string {
+	return r.input.RepositoryName
+}
+
+func (r *referencesBlockInputResolver) FilePath() string {
+	return r.input.FilePath
+}
+
+func (r *referencesBlockInputResolver) Commit() string {
+	return r.input.Commit
+}
+
+func (r *referencesBlockInputResolver) Line() int32 {
+	return r.input.Line
+}
+
+func (r *referencesBlockInputResolver) Character() int32 {
+	return r.input.Character
+}
+
+func (r *referencesBlockInputResolver) SymbolName() string {
+	return r.input.SymbolName
+}
@@ -73,6 +73,31 @@ const notebookFields = `
 				symbolKind
 			}
 		}
+		... on ComputeBlock {
+			__typename
+			id
+			computeInput {
+				text
+				output
+			}
+		}
+		... on InsightBlock {
+			__typename
+			id
+			insightSeriesID
+		}
+		... on ReferencesBlock {
+			__typename
+			id
+			referencesInput {
+				repositoryName
+				filePath
+				commit
+				line
+				character
+				symbolName
+			}
+		}
 	}
 `
 
@@ -109,6 +134,14 @@ mutation CreateNotebook($notebook: NotebookInput!) {
 }
 `, notebookFields)
 
+var importNotebookMutation = fmt.Sprintf(`
+mutation ImportNotebook($id: ID, $notebook: ImportNotebookInput!) {
+	importNotebook(id: $id, notebook: $notebook) {
+		%s
+	}
+}
+`, notebookFields)
+
 var updateNotebookMutation = fmt.Sprintf(`
 mutation UpdateNotebook($id: ID!, $notebook: NotebookInput!) {
 	updateNotebook(id: $id, notebook: $notebook) {
@@ -127,6 +160,7 @@ mutation DeleteNotebook($id: ID!) {
 
 func notebookFixture(creatorID int32, namespaceUserID int32, namespaceOrgID int32, public bool) *notebooks.Notebook {
 	revision := "deadbeef"
+	computeOutput := "alice\nbob"
 	blocks := notebooks.NotebookBlocks{
 		{ID: "1", Type: notebooks.NotebookQueryBlockType, QueryInput: &notebooks.NotebookQueryBlockInput{Text: "repo:a b"}},
 		{ID: "2", Type: notebooks.NotebookMarkdownBlockType, MarkdownInput: &notebooks.NotebookMarkdownBlockInput{Text: "# Title"}},
@@ -145,6 +179,16 @@ func notebookFixture(creatorID int32, namespaceUserID int32, namespaceOrgID int3
 			SymbolContainerName: "container",
 			SymbolKind:          "FUNCTION",
 		}},
+		{ID: "5", Type: notebooks.NotebookComputeBlockType, ComputeInput: &notebooks.NotebookComputeBlockInput{Text: "content:output(.* -> $author) type:commit", Output: &computeOutput}},
+		{ID: "6", Type: notebooks.NotebookInsightBlockType, InsightInput: &notebooks.NotebookInsightBlockInput{SeriesID: "series1"}},
+		{ID: "7", Type: notebooks.NotebookReferencesBlockType, ReferencesInput: &notebooks.NotebookReferencesBlockInput{
+			RepositoryName: "github.com/sourcegraph/sourcegraph",
+			FilePath:       "internal/notebooks/types.go",
+			Commit:         "a9505a2947d3df53558e8c88ff8bcef390fc4e3e",
+			Line:           10,
+			Character:      5,
+			SymbolName:     "NotebookBlock",
+		}},
 	}
 	return &notebooks.Notebook{Title: "Notebook Title", Blocks: blocks, Public: public, CreatorUserID: creatorID, UpdaterUserID: creatorID, NamespaceUserID: namespaceUserID, NamespaceOrgID: namespaceOrgID}
 }
@@ -207,6 +251,7 @@ func TestSingleNotebookCRUD(t *testing.T) {
 	testGetNotebook(t, db, schema, user1)
 	testCreateNotebook(t, schema, user1, user2, org)
 	testUpdateNotebook(t, db, schema, user1, user2, org)
+	testImportNotebook(t, db, schema, user1, user2)
 	testDeleteNotebook(t, db, schema, user1, user2, org)
 }
 
@@ -283,6 +328,64 @@ func testCreateNotebook(t *testing.T, schema *graphql.Schema, user1 *types.User,
 	}
 }
 
+func testImportNotebook(t *testing.T, db database.DB, schema *graphql.Schema, user1 *types.User, user2 *types.User) {
+	internalCtx := actor.WithInternalActor(context.Background())
+	notebook := userNotebookFixture(user1.ID, true)
+	exported, err := notebooks.ExportJSON(notebook)
+	if err != nil {
+		t.Fatal(err)
+	}
+	namespace := graphqlbackend.MarshalUserID(user1.ID)
+
+	t.Run("user can import a notebook", func(t *testing.T) {
+		input := map[string]any{"notebook": map[string]any{"content": string(exported), "format": "JSON", "namespace": namespace, "public": true}}
+		var response struct{ ImportNotebook notebooksapitest.Notebook }
+		apitest.MustExec(actor.WithActor(context.Background(), actor.FromUser(user1.ID)), t, schema, input, &response, importNotebookMutation)
+
+		wantNotebookResponse := notebooksapitest.NotebookToAPIResponse(notebook, "", user1.Username, user1.Username, true)
+		compareNotebookAPIResponses(t, wantNotebookResponse, response.ImportNotebook, true)
+	})
+
+	t.Run("user cannot import a notebook with an empty title", func(t *testing.T) {
+		input := map[string]any{"notebook": map[string]any{"content": string(exported), "format": "JSON", "title": "", "namespace": namespace, "public": true}}
+		var response struct{ ImportNotebook notebooksapitest.Notebook }
+		gotErrors := apitest.Exec(actor.WithActor(context.Background(), actor.FromUser(user1.ID)), t, schema, input, &response, importNotebookMutation)
+		if len(gotErrors) == 0 || !strings.Contains(gotErrors[0].Message, "notebook title cannot be empty") {
+			t.Fatalf("expected empty title error, got %v", gotErrors)
+		}
+	})
+
+	createdNotebook, err := notebooks.Notebooks(db).CreateNotebook(internalCtx, userNotebookFixture(user1.ID, true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := map[string]any{
+		"id":       marshalNotebookID(createdNotebook.ID),
+		"notebook": map[string]any{"content": "# Runbook\n\n```sourcegraph\nrepo:a b\n```\n", "format": "MARKDOWN", "title": "Runbook", "namespace": namespace, "public": false},
+	}
+
+	t.Run("user2 cannot replace user1 notebook", func(t *testing.T) {
+		var response struct{ ImportNotebook notebooksapitest.Notebook }
+		gotErrors := apitest.Exec(actor.WithActor(context.Background(), actor.FromUser(user2.ID)), t, schema, input, &response, importNotebookMutation)
+		if len(gotErrors) == 0 || !strings.Contains(gotErrors[0].Message, "user does not match the notebook user namespace") {
+			t.Fatalf("expected namespace error, got %v", gotErrors)
+		}
+	})
+
+	t.Run("user can replace a notebook with Markdown", func(t *testing.T) {
+		var response struct{ ImportNotebook notebooksapitest.Notebook }
+		apitest.MustExec(actor.WithActor(context.Background(), actor.FromUser(user1.ID)), t, schema, input, &response, importNotebookMutation)
+
+		got := response.ImportNotebook
+		if got.ID != string(marshalNotebookID(createdNotebook.ID)) || got.Title != "Runbook" || got.Public {
+			t.Fatalf("unexpected notebook: %+v", got)
+		}
+		if len(got.Blocks) != 2 || got.Blocks[0].MarkdownInput != "# Runbook" || got.Blocks[1].QueryInput != "repo:a b" {
+			t.Fatalf("unexpected blocks: %+v", got.Blocks)
+		}
+	})
+}
+
 func testUpdateNotebook(t *testing.T, db database.DB, schema *graphql.Schema, user1 *types.User, user2 *types.User, org *types.Org) {
 	internalCtx := actor.WithInternalActor(context.Background())
 	n := notebooks.Notebooks(db)
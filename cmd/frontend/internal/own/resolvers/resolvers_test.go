@@ -84,6 +84,10 @@ func (s fakeOwnService) AssignedTeams(context.Context, api.RepoID, api.CommitID)
 	return s.Teams, nil
 }
 
+func (s fakeOwnService) OwnedRepos(context.Context, string) ([]api.RepoID, error) {
+	return nil, nil
+}
+
 // fakeGitServer is a limited gitserver.Client that returns a file for every Stat call.
 type fakeGitserver struct {
 	gitserver.Client
@@ -9,6 +9,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/enterprise"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/repos/webhooks/resolvers"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/webhooks"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/cloneurls"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel"
 	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
@@ -193,6 +194,13 @@ func handlePushEvent[T any](ctx context.Context, db database.DB, logger log.Logg
 		return errors.Wrap(err, "handlePushEvent: EnqueueRepoUpdate failed")
 	}
 
+	// Remember that the repo is covered by webhooks, so that repo-updater can
+	// relax its polling interval. This is best effort, the update has already
+	// been queued.
+	if err := db.RepoUpdateSchedule().RecordWebhook(ctx, api.RepoID(resp.ID)); err != nil {
+		logger.Warn("failed to record push webhook", log.String("repo", resp.Name), log.Error(err))
+	}
+
 	logger.Info("successfully updated", log.String("name", resp.Name))
 	return nil
 }
@@ -15,6 +15,8 @@ import (
 	"path/filepath"
 	"testing"
 
+	mockassert "github.com/derision-test/go-mockgen/testutil/assert"
+	mockrequire "github.com/derision-test/go-mockgen/testutil/require"
 	"github.com/sourcegraph/log/logtest"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
@@ -195,6 +197,8 @@ func TestGitLabHandler(t *testing.T) {
 		return api.RepoName(repoName), nil
 	})
 	db.ReposFunc.SetDefaultReturn(repositories)
+	schedule := dbmocks.NewMockRepoUpdateScheduleStore()
+	db.RepoUpdateScheduleFunc.SetDefaultReturn(schedule)
 
 	handler := NewGitLabHandler()
 	data, err := os.ReadFile("testdata/gitlab-push.json")
@@ -220,6 +224,7 @@ func TestGitLabHandler(t *testing.T) {
 		t.Fatal(err)
 	}
 	assert.Equal(t, repoName, updateQueued)
+	mockrequire.CalledOnceWith(t, schedule.RecordWebhookFunc, mockassert.Values(mockassert.Skip, api.RepoID(1)))
 }
 
 func TestBitbucketServerHandler(t *testing.T) {
@@ -231,6 +236,8 @@ func TestBitbucketServerHandler(t *testing.T) {
 		return "bitbucket.sgdev.org/private/test-2020-06-01", nil
 	})
 	db.ReposFunc.SetDefaultReturn(repositories)
+	schedule := dbmocks.NewMockRepoUpdateScheduleStore()
+	db.RepoUpdateScheduleFunc.SetDefaultReturn(schedule)
 
 	handler := NewBitbucketServerHandler()
 	data, err := os.ReadFile("testdata/bitbucket-server-push.json")
@@ -256,6 +263,7 @@ func TestBitbucketServerHandler(t *testing.T) {
 		t.Fatal(err)
 	}
 	assert.Equal(t, repoName, updateQueued)
+	mockrequire.CalledOnceWith(t, schedule.RecordWebhookFunc, mockassert.Values(mockassert.Skip, api.RepoID(1)))
 }
 
 func TestBitbucketCloudHandler(t *testing.T) {
@@ -267,6 +275,8 @@ func TestBitbucketCloudHandler(t *testing.T) {
 		return "bitbucket.org/sourcegraph-testing/sourcegraph", nil
 	})
 	db.ReposFunc.SetDefaultReturn(repositories)
+	schedule := dbmocks.NewMockRepoUpdateScheduleStore()
+	db.RepoUpdateScheduleFunc.SetDefaultReturn(schedule)
 
 	handler := NewBitbucketCloudHandler()
 	data, err := os.ReadFile("testdata/bitbucket-cloud-push.json")
@@ -292,4 +302,5 @@ func TestBitbucketCloudHandler(t *testing.T) {
 		t.Fatal(err)
 	}
 	assert.Equal(t, repoName, updateQueued)
+	mockrequire.CalledOnceWith(t, schedule.RecordWebhookFunc, mockassert.Values(mockassert.Skip, api.RepoID(1)))
 }
@@ -0,0 +1,80 @@
+// Package savedsearches contains the logic to look up saved searches and to
+// fill in their variables that is shared by the GraphQL API and the web app.
+package savedsearches
+
+import (
+	"context"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/auth"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/internal/search/client"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// GetByID returns the saved search with the given ID.
+//
+// 🚨 SECURITY: An error is returned if the current user does not have
+// permission to view the saved search.
+func GetByID(ctx context.Context, db database.DB, id int32) (*api.ConfigSavedQuery, error) {
+	ss, err := db.SavedSearches().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if ss.Config.UserID != nil {
+		if *ss.Config.UserID != actor.FromContext(ctx).UID {
+			return nil, &auth.InsufficientAuthorizationError{
+				Message: "current user has insufficient privileges to view saved search",
+			}
+		}
+	} else if ss.Config.OrgID != nil {
+		if err := auth.CheckOrgAccess(ctx, db, *ss.Config.OrgID); err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, errors.New("failed to get saved search: no Org ID or User ID associated with saved search")
+	}
+	return &ss.Config, nil
+}
+
+// ValidateVariables checks that the query of a saved search is valid with the
+// variables it declares. Queries without variables are left to the search
+// backend to validate, like they always were.
+func ValidateVariables(q string, variables []api.SavedQueryVariable) error {
+	if len(variables) == 0 {
+		return nil
+	}
+	return query.ValidateVariables(q, searchType(q), toQueryVariables(variables))
+}
+
+// SubstituteVariables returns the query of a saved search with the references
+// to its variables replaced by values.
+func SubstituteVariables(q string, variables []api.SavedQueryVariable, values map[string]string) (string, error) {
+	return query.SubstituteVariables(q, searchType(q), toQueryVariables(variables), values)
+}
+
+func toQueryVariables(variables []api.SavedQueryVariable) []query.Variable {
+	vs := make([]query.Variable, 0, len(variables))
+	for _, v := range variables {
+		vs = append(vs, query.Variable{Name: v.Name, Type: query.VariableType(v.Type)})
+	}
+	return vs
+}
+
+// searchType returns the search type selected by the patternType: filter
+// that saved search queries are required to contain.
+func searchType(q string) query.SearchType {
+	if m := patternType.FindStringSubmatch(q); m != nil {
+		if searchType, err := client.SearchTypeFromString(strings.ToLower(m[1])); err == nil {
+			return searchType
+		}
+	}
+	return query.SearchTypeStandard
+}
+
+var patternType = lazyregexp.New(`(?i)\bpatternType:(literal|regexp|structural|standard)\b`)
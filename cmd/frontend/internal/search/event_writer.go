@@ -1,35 +1,91 @@
 package search
 
 import (
+	"net/http"
+
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming/api"
 	streamhttp "github.com/sourcegraph/sourcegraph/internal/search/streaming/http"
 )
 
-func newEventWriter(inner *streamhttp.Writer) *eventWriter {
-	return &eventWriter{inner: inner}
+// eventWriter writes the events of a frontend stream in the encoding
+// negotiated with the client.
+type eventWriter interface {
+	Done() error
+	Progress(current api.Progress) error
+	Filters(fs []*streaming.Filter, exhaustive bool) error
+	Error(err error) error
+	Alert(alert *search.Alert) error
+
+	// MatchesBuf returns the buffer matches are appended to, which writes
+	// them out in batches of matchesFlushSize.
+	MatchesBuf() matchesBuf
+}
+
+// matchesBuf batches matches before writing them out.
+type matchesBuf interface {
+	Append(streamhttp.EventMatch) error
+	Flush() error
+}
+
+// matchesFlushSize is the size at which we flush marshalled matches. 32kb
+// chosen to be smaller than bufio.MaxTokenSize. Note: we can still write more
+// than that.
+const matchesFlushSize = 32 * 1024
+
+// newEventWriterForRequest returns the eventWriter for the encoding requested
+// by the Accept header of r. Export encodings take the SARIF rule ID from the
+// rule URL parameter.
+func newEventWriterForRequest(w http.ResponseWriter, r *http.Request, statHook func(streamhttp.WriterStat)) (eventWriter, error) {
+	encoding := streamhttp.NegotiateEncoding(r.Header.Get("Accept"))
+	if encoding == streamhttp.EncodingEventStream {
+		streamWriter, err := streamhttp.NewWriter(w)
+		if err != nil {
+			return nil, err
+		}
+		streamWriter.StatHook = statHook
+		return newEventWriter(streamWriter), nil
+	}
+
+	exportWriter, err := streamhttp.NewExportWriter(w, encoding, streamhttp.ExportOptions{
+		FlushSize: matchesFlushSize,
+		Query:     r.URL.Query().Get("q"),
+		RuleID:    r.URL.Query().Get("rule"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &exportEventWriter{inner: exportWriter}, nil
+}
+
+func newEventWriter(inner *streamhttp.Writer) *eventStreamWriter {
+	return &eventStreamWriter{inner: inner}
 }
 
-// eventWriter is a type that wraps a streamhttp.Writer with typed
+// eventStreamWriter is a type that wraps a streamhttp.Writer with typed
 // methods for each of the supported evens in a frontend stream.
-type eventWriter struct {
+type eventStreamWriter struct {
 	inner *streamhttp.Writer
 }
 
-func (e *eventWriter) Done() error {
+func (e *eventStreamWriter) Done() error {
 	return e.inner.Event("done", map[string]any{})
 }
 
-func (e *eventWriter) Progress(current api.Progress) error {
+func (e *eventStreamWriter) Progress(current api.Progress) error {
 	return e.inner.Event("progress", current)
 }
 
-func (e *eventWriter) MatchesJSON(data []byte) error {
+func (e *eventStreamWriter) MatchesJSON(data []byte) error {
 	return e.inner.EventBytes("matches", data)
 }
 
-func (e *eventWriter) Filters(fs []*streaming.Filter, exhaustive bool) error {
+func (e *eventStreamWriter) MatchesBuf() matchesBuf {
+	return jsonMatchesBuf{streamhttp.NewJSONArrayBuf(matchesFlushSize, e.MatchesJSON)}
+}
+
+func (e *eventStreamWriter) Filters(fs []*streaming.Filter, exhaustive bool) error {
 	if len(fs) > 0 {
 		buf := make([]streamhttp.EventFilter, 0, len(fs))
 		for _, f := range fs {
@@ -47,11 +103,11 @@ func (e *eventWriter) Filters(fs []*streaming.Filter, exhaustive bool) error {
 	return nil
 }
 
-func (e *eventWriter) Error(err error) error {
+func (e *eventStreamWriter) Error(err error) error {
 	return e.inner.Event("error", streamhttp.EventError{Message: err.Error()})
 }
 
-func (e *eventWriter) Alert(alert *search.Alert) error {
+func (e *eventStreamWriter) Alert(alert *search.Alert) error {
 	var pqs []streamhttp.QueryDescription
 	for _, pq := range alert.ProposedQueries {
 		annotations := make([]streamhttp.Annotation, 0, len(pq.Annotations))
@@ -72,3 +128,42 @@ func (e *eventWriter) Alert(alert *search.Alert) error {
 		ProposedQueries: pqs,
 	})
 }
+
+// jsonMatchesBuf batches matches in a JSON array for the matches event.
+type jsonMatchesBuf struct {
+	*streamhttp.JSONArrayBuf
+}
+
+func (b jsonMatchesBuf) Append(m streamhttp.EventMatch) error {
+	return b.JSONArrayBuf.Append(m)
+}
+
+// exportEventWriter writes the matches, errors and alerts of a frontend stream
+// in an export encoding. Progress and filters are not part of exports.
+type exportEventWriter struct {
+	inner *streamhttp.ExportWriter
+}
+
+func (e *exportEventWriter) Done() error {
+	return e.inner.Done()
+}
+
+func (e *exportEventWriter) Progress(api.Progress) error {
+	return nil
+}
+
+func (e *exportEventWriter) MatchesBuf() matchesBuf {
+	return e.inner
+}
+
+func (e *exportEventWriter) Filters([]*streaming.Filter, bool) error {
+	return nil
+}
+
+func (e *exportEventWriter) Error(err error) error {
+	return e.inner.Error(err)
+}
+
+func (e *exportEventWriter) Alert(alert *search.Alert) error {
+	return e.inner.Alert(alert.Title, alert.Description)
+}
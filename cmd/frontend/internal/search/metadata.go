@@ -5,6 +5,8 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
@@ -28,3 +30,41 @@ func getEventRepoMetadata(ctx context.Context, db database.DB, event streaming.S
 	}
 	return repoMetadata, nil
 }
+
+// getEventChangelistIDs looks up the Perforce changelists that the commit
+// matches of the event were converted from. Only repositories synced from
+// Perforce are considered, and the changelists are read from the
+// repo_commits_changelists mapping table.
+func getEventChangelistIDs(ctx context.Context, db database.DB, event streaming.SearchEvent) (map[api.RepoID]map[api.CommitID]int64, error) {
+	commitIDs := make(map[api.RepoID][]api.CommitID)
+	for _, match := range event.Results {
+		if cm, ok := match.(*result.CommitMatch); ok {
+			commitIDs[cm.Repo.ID] = append(commitIDs[cm.Repo.ID], cm.Commit.ID)
+		}
+	}
+	if len(commitIDs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]api.RepoID, 0, len(commitIDs))
+	for id := range commitIDs {
+		ids = append(ids, id)
+	}
+	repos, err := db.Repos().List(ctx, database.ReposListOptions{IDs: ids})
+	if err != nil {
+		return nil, errors.Wrap(err, "fetch repos from db")
+	}
+
+	changelistIDs := make(map[api.RepoID]map[api.CommitID]int64)
+	for _, repo := range repos {
+		if repo.ExternalRepo.ServiceType != extsvc.TypePerforce {
+			continue
+		}
+		cids, err := db.RepoCommitsChangelists().GetChangelistIDsForCommits(ctx, repo.ID, commitIDs[repo.ID])
+		if err != nil {
+			return nil, errors.Wrap(err, "fetch changelists from db")
+		}
+		changelistIDs[repo.ID] = cids
+	}
+	return changelistIDs, nil
+}
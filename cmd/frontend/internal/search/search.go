@@ -86,16 +86,13 @@ func (h *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	defer tr.End()
 	r = r.WithContext(ctx)
 
-	streamWriter, err := streamhttp.NewWriter(w)
+	// Log events to trace
+	eventWriter, err := newEventWriterForRequest(w, r, eventStreamTraceHook(tr.AddEvent))
 	if err != nil {
 		tr.SetError(err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	// Log events to trace
-	streamWriter.StatHook = eventStreamTraceHook(tr.AddEvent)
-
-	eventWriter := newEventWriter(streamWriter)
 	defer eventWriter.Done()
 
 	err = h.serveHTTP(r, tr, eventWriter)
@@ -106,7 +103,7 @@ func (h *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func (h *streamHandler) serveHTTP(r *http.Request, tr trace.Trace, eventWriter *eventWriter) (err error) {
+func (h *streamHandler) serveHTTP(r *http.Request, tr trace.Trace, eventWriter eventWriter) (err error) {
 	ctx := r.Context()
 	start := time.Now()
 
@@ -378,7 +375,7 @@ func newEventHandler(
 	ctx context.Context,
 	logger log.Logger,
 	db database.DB,
-	eventWriter *eventWriter,
+	eventWriter eventWriter,
 	progress *streamclient.ProgressAggregator,
 	flushInterval time.Duration,
 	progressInterval time.Duration,
@@ -387,11 +384,8 @@ func newEventHandler(
 	logLatency func(),
 ) *eventHandler {
 	// Store marshalled matches and flush periodically or when we go over
-	// 32kb. 32kb chosen to be smaller than bufio.MaxTokenSize. Note: we can
-	// still write more than that.
-	matchesBuf := streamhttp.NewJSONArrayBuf(32*1024, func(data []byte) error {
-		return eventWriter.MatchesJSON(data)
-	})
+	// matchesFlushSize.
+	matchesBuf := eventWriter.MatchesBuf()
 
 	eh := &eventHandler{
 		ctx:                ctx,
@@ -435,9 +429,9 @@ type eventHandler struct {
 	// Everything below this line is protected by the mutex
 	mu sync.Mutex
 
-	eventWriter *eventWriter
+	eventWriter eventWriter
 
-	matchesBuf *streamhttp.JSONArrayBuf
+	matchesBuf matchesBuf
 	filters    *streaming.SearchFilters
 	progress   *streamclient.ProgressAggregator
 
@@ -468,6 +462,14 @@ func (h *eventHandler) Send(event streaming.SearchEvent) {
 		return
 	}
 
+	changelistIDs, err := getEventChangelistIDs(h.ctx, h.db, event)
+	if err != nil {
+		if !errors.IsContextCanceled(err) {
+			h.logger.Error("failed to get perforce changelists", log.Error(err))
+		}
+		return
+	}
+
 	for _, match := range event.Results {
 		repo := match.RepoName()
 
@@ -479,6 +481,11 @@ func (h *eventHandler) Send(event streaming.SearchEvent) {
 		}
 
 		eventMatch := search.FromMatch(match, repoMetadata, h.enableChunkMatches)
+		if commitEvent, ok := eventMatch.(*streamhttp.EventCommitMatch); ok {
+			if cid, ok := changelistIDs[repo.ID][api.CommitID(commitEvent.OID)]; ok {
+				commitEvent.PerforceChangelistID = strconv.FormatInt(cid, 10)
+			}
+		}
 		h.matchesBuf.Append(eventMatch)
 	}
 
@@ -3,11 +3,13 @@ package search
 import (
 	"compress/gzip"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,7 +18,10 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	api2 "github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbmocks"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/client"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
@@ -165,6 +170,81 @@ func TestServeStream_chunkMatches(t *testing.T) {
 	require.Len(t, chunkMatches[0].Ranges, 1)
 }
 
+func TestServeStream_export(t *testing.T) {
+	settings.MockCurrentUserFinal = &schema.Settings{}
+	t.Cleanup(func() { settings.MockCurrentUserFinal = nil })
+
+	mock := client.NewMockSearchClient()
+	mock.PlanFunc.SetDefaultReturn(&search.Inputs{}, nil)
+	mock.ExecuteFunc.SetDefaultHook(func(_ context.Context, s streaming.Sender, _ *search.Inputs) (*search.Alert, error) {
+		s.Send(streaming.SearchEvent{
+			Results: result.Matches{&result.FileMatch{
+				File: result.File{Path: "testpath", CommitID: "deadbeef"},
+				ChunkMatches: result.ChunkMatches{{
+					Content: "line1",
+					Ranges: result.Ranges{{
+						Start: result.Location{0, 0, 0},
+						End:   result.Location{1, 0, 1},
+					}},
+				}},
+			}},
+		})
+		return nil, nil
+	})
+
+	mockRepos := dbmocks.NewMockRepoStore()
+	mockRepos.MetadataFunc.SetDefaultHook(func(_ context.Context, ids ...api2.RepoID) ([]*types.SearchedRepo, error) {
+		out := make([]*types.SearchedRepo, 0, len(ids))
+		for _, id := range ids {
+			out = append(out, &types.SearchedRepo{ID: id})
+		}
+		return out, nil
+	})
+
+	db := dbmocks.NewMockDB()
+	db.ReposFunc.SetDefaultReturn(mockRepos)
+
+	ts := httptest.NewServer(gzipMiddleware(&streamHandler{
+		logger:              logtest.Scoped(t),
+		db:                  db,
+		flushTickerInternal: 1 * time.Millisecond,
+		pingTickerInterval:  1 * time.Millisecond,
+		searchClient:        mock,
+	}))
+	defer ts.Close()
+
+	get := func(t *testing.T, accept string) (string, string) {
+		req, err := http.NewRequest("GET", ts.URL+"?q=test&cm=t", nil)
+		require.NoError(t, err)
+		req.Header.Set("Accept", accept)
+
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		require.Equal(t, 200, res.StatusCode)
+
+		b, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		return res.Header.Get("Content-Type"), string(b)
+	}
+
+	t.Run("ndjson", func(t *testing.T) {
+		contentType, body := get(t, "application/x-ndjson")
+		require.Equal(t, "application/x-ndjson", contentType)
+		var match streamhttp.EventContentMatch
+		require.NoError(t, json.Unmarshal([]byte(body), &match))
+		require.Equal(t, "testpath", match.Path)
+		require.Len(t, match.ChunkMatches, 1)
+		require.True(t, strings.HasSuffix(body, "}\n"))
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		contentType, body := get(t, "text/csv")
+		require.Equal(t, "text/csv; charset=utf-8; header=present", contentType)
+		require.Equal(t, "type,repository,commit,path,line,column,preview\ncontent,,deadbeef,testpath,1,1,line1\n", body)
+	})
+}
+
 func TestDisplayLimit(t *testing.T) {
 	cases := []struct {
 		queryString         string
@@ -315,6 +395,39 @@ func TestDisplayLimit(t *testing.T) {
 	}
 }
 
+func TestGetEventChangelistIDs(t *testing.T) {
+	repos := dbmocks.NewStrictMockRepoStore()
+	repos.ListFunc.SetDefaultHook(func(_ context.Context, opts database.ReposListOptions) ([]*types.Repo, error) {
+		require.ElementsMatch(t, []api2.RepoID{1, 2}, opts.IDs)
+		return []*types.Repo{
+			{ID: 1, ExternalRepo: api2.ExternalRepoSpec{ServiceType: extsvc.TypePerforce}},
+			{ID: 2, ExternalRepo: api2.ExternalRepoSpec{ServiceType: extsvc.TypeGitHub}},
+		}, nil
+	})
+	changelists := dbmocks.NewStrictMockRepoCommitsChangelistsStore()
+	changelists.GetChangelistIDsForCommitsFunc.SetDefaultHook(func(_ context.Context, repoID api2.RepoID, commitIDs []api2.CommitID) (map[api2.CommitID]int64, error) {
+		require.Equal(t, api2.RepoID(1), repoID)
+		require.Equal(t, []api2.CommitID{"a"}, commitIDs)
+		return map[api2.CommitID]int64{"a": 42}, nil
+	})
+	db := dbmocks.NewStrictMockDB()
+	db.ReposFunc.SetDefaultReturn(repos)
+	db.RepoCommitsChangelistsFunc.SetDefaultReturn(changelists)
+
+	mkCommitMatch := func(repoID int, commitID string) *result.CommitMatch {
+		return &result.CommitMatch{
+			Repo:   types.MinimalRepo{ID: api2.RepoID(repoID), Name: api2.RepoName(fmt.Sprintf("repo%d", repoID))},
+			Commit: gitdomain.Commit{ID: api2.CommitID(commitID)},
+		}
+	}
+
+	got, err := getEventChangelistIDs(context.Background(), db, streaming.SearchEvent{
+		Results: []result.Match{mkCommitMatch(1, "a"), mkCommitMatch(2, "b"), mkRepoMatch(3)},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[api2.RepoID]map[api2.CommitID]int64{1: {"a": 42}}, got)
+}
+
 func mkRepoMatch(id int) *result.RepoMatch {
 	return &result.RepoMatch{
 		ID:   api2.RepoID(id),
@@ -2,6 +2,7 @@ package resolvers
 
 import (
 	"context"
+	"strings"
 
 	"github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
@@ -91,6 +92,7 @@ func (r *Resolver) CreateSearchContext(ctx context.Context, args graphqlbackend.
 			NamespaceUserID: namespaceUserID,
 			NamespaceOrgID:  namespaceOrgID,
 			Query:           args.SearchContext.Query,
+			Dynamic:         dynamicFromInputArgs(args.SearchContext.Dynamic),
 		},
 		repositoryRevisions,
 	)
@@ -124,6 +126,7 @@ func (r *Resolver) UpdateSearchContext(ctx context.Context, args graphqlbackend.
 	updated.Description = args.SearchContext.Description
 	updated.Public = args.SearchContext.Public
 	updated.Query = args.SearchContext.Query
+	updated.Dynamic = dynamicFromInputArgs(args.SearchContext.Dynamic)
 
 	searchContext, err := searchcontexts.UpdateSearchContextWithRepositoryRevisions(
 		ctx,
@@ -137,6 +140,16 @@ func (r *Resolver) UpdateSearchContext(ctx context.Context, args graphqlbackend.
 	return &searchContextResolver{searchContext, r.db}, nil
 }
 
+func dynamicFromInputArgs(args *graphqlbackend.SearchContextDynamicInputArgs) *types.SearchContextDynamic {
+	if args == nil {
+		return nil
+	}
+	return &types.SearchContextDynamic{
+		Source: types.SearchContextDynamicSource(strings.ToLower(args.Source)),
+		Value:  args.Value,
+	}
+}
+
 func (r *Resolver) repositoryRevisionsFromInputArgs(ctx context.Context, args []graphqlbackend.SearchContextRepositoryRevisionsInputArgs) ([]*types.SearchContextRepositoryRevisions, error) {
 	repoIDs := make([]api.RepoID, 0, len(args))
 	for _, repository := range args {
@@ -514,6 +527,29 @@ func (r *searchContextResolver) Query() string {
 	return r.sc.Query
 }
 
+func (r *searchContextResolver) Dynamic() graphqlbackend.SearchContextDynamicResolver {
+	if r.sc.Dynamic == nil {
+		return nil
+	}
+	return &searchContextDynamicResolver{r.sc.Dynamic}
+}
+
+type searchContextDynamicResolver struct {
+	dynamic *types.SearchContextDynamic
+}
+
+func (r *searchContextDynamicResolver) Source() string {
+	return strings.ToUpper(string(r.dynamic.Source))
+}
+
+func (r *searchContextDynamicResolver) Value() string {
+	return r.dynamic.Value
+}
+
+func (r *searchContextDynamicResolver) EvaluatedAt() *gqlutil.DateTime {
+	return gqlutil.FromTime(r.dynamic.EvaluatedAt)
+}
+
 type searchContextConnectionResolver struct {
 	afterCursor    int32
 	searchContexts []graphqlbackend.SearchContextResolver
@@ -6,6 +6,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/cli"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/codeintel"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/executorqueue"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/debugserver"
 	"github.com/sourcegraph/sourcegraph/internal/env"
@@ -28,6 +29,7 @@ func (s *svc) Configure() (env.Config, []debugserver.Endpoint) {
 	CLILoadConfig()
 	codeintel.LoadConfig()
 	search.LoadConfig()
+	executorqueue.LoadConfig()
 	// Signals health of startup.
 	s.ready = make(chan struct{})
 
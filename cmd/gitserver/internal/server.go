@@ -784,7 +784,7 @@ func (s *Server) CloneRepo(ctx context.Context, repo api.RepoName, opts CloneOpt
 		return "", err
 	}
 
-	if err = s.RPSLimiter.Wait(ctx); err != nil {
+	if err = s.RPSLimiter.Wait(ratelimit.WithConsumer(ctx, "gitserver-clone")); err != nil {
 		return "", err
 	}
 
@@ -853,7 +853,7 @@ func (s *Server) doClone(
 			repoCloneFailedCounter.Inc()
 		}
 	}()
-	if err := s.RPSLimiter.Wait(ctx); err != nil {
+	if err := s.RPSLimiter.Wait(ratelimit.WithConsumer(ctx, "gitserver-clone")); err != nil {
 		return err
 	}
 	ctx, cancel := context.WithTimeout(ctx, conf.GitLongCommandTimeout())
@@ -1283,7 +1283,9 @@ func (s *Server) doBackgroundRepoUpdate(repo api.RepoName, revspec string) error
 	}
 	defer cancel2()
 
-	if err = s.RPSLimiter.Wait(ctx); err != nil {
+	// Fetches are background work, so leave room for clones of repositories
+	// users are waiting on.
+	if err = s.RPSLimiter.Wait(ratelimit.WithConsumer(ratelimit.WithPriority(ctx, ratelimit.PriorityBackground), "gitserver-fetch")); err != nil {
 		return err
 	}
 
@@ -8,6 +8,7 @@ import (
 	"github.com/sourcegraph/log"
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
@@ -27,6 +28,18 @@ type schedule struct {
 	randGenerator interface {
 		Int63n(n int64) int64
 	}
+
+	// persisted holds the schedule restored from the database for repos that
+	// the scheduler doesn't know about yet. It is consumed when the repos are
+	// added to the schedule.
+	persisted map[api.RepoID]database.RepoUpdateScheduleEntry
+	// dirty and removed track the changes that haven't been written to the
+	// database yet.
+	dirty   map[api.RepoID]database.RepoUpdateScheduleEntry
+	removed map[api.RepoID]struct{}
+
+	// webhooks holds the last time a push webhook was received for a repo.
+	webhooks map[api.RepoID]time.Time
 }
 
 // upsert inserts or updates a repo in the schedule.
@@ -43,11 +56,7 @@ func (s *schedule) upsert(repo configuredRepo) (updated bool) {
 		return true
 	}
 
-	heap.Push(s, &scheduledRepoUpdate{
-		Repo:     repo,
-		Interval: minDelay,
-		Due:      timeNow().Add(minDelay),
-	})
+	heap.Push(s, s.newUpdate(repo, timeNow().Add(minDelay)))
 
 	s.rescheduleTimer()
 
@@ -68,11 +77,11 @@ func (s *schedule) prioritiseUncloned(uncloned []types.MinimalRepo) {
 	rescheduleTimer := false
 	for _, repo := range uncloned {
 		if repoUpdate := s.index[repo.ID]; repoUpdate == nil {
-			heap.Push(s, &scheduledRepoUpdate{
-				Repo:     configuredRepo{ID: repo.ID, Name: repo.Name},
-				Interval: minDelay,
-				Due:      notClonedDue,
-			})
+			update := s.newUpdate(configuredRepo{ID: repo.ID, Name: repo.Name}, notClonedDue)
+			if update.Due.After(notClonedDue) {
+				update.Due = notClonedDue
+			}
+			heap.Push(s, update)
 			rescheduleTimer = true
 		} else if repoUpdate.Due.After(notClonedDue) {
 			repoUpdate.Due = notClonedDue
@@ -107,11 +116,7 @@ func (s *schedule) insertNew(repos []types.MinimalRepo) {
 		if update := s.index[repo.ID]; update != nil {
 			continue
 		}
-		heap.Push(s, &scheduledRepoUpdate{
-			Repo:     repo,
-			Interval: minDelay,
-			Due:      due,
-		})
+		heap.Push(s, s.newUpdate(repo, due))
 		rescheduleTimer = true
 	}
 
@@ -129,21 +134,7 @@ func (s *schedule) updateInterval(repo configuredRepo, interval time.Duration) {
 
 	s.mu.Lock()
 	if update := s.index[repo.ID]; update != nil {
-		switch {
-		case interval > maxDelay:
-			update.Interval = maxDelay
-		case interval < minDelay:
-			update.Interval = minDelay
-		default:
-			update.Interval = interval
-		}
-
-		// Add a jitter of 5% on either side of the interval to avoid
-		// repos getting updated at the same time.
-		delta := int64(update.Interval) / 20
-		update.Interval = update.Interval + time.Duration(s.randGenerator.Int63n(2*delta)-delta)
-
-		update.Due = timeNow().Add(update.Interval)
+		s.setInterval(update, interval)
 		s.logger.Debug("updated repo",
 			log.Object("repo", log.String("name", string(repo.Name)), log.Duration("due", update.Due.Sub(timeNow()))),
 		)
@@ -153,6 +144,85 @@ func (s *schedule) updateInterval(repo configuredRepo, interval time.Duration) {
 	s.mu.Unlock()
 }
 
+// setInterval sets the clamped and jittered interval of update and schedules
+// its next update accordingly.
+// The caller must hold the lock on s.mu and fix the heap.
+func (s *schedule) setInterval(update *scheduledRepoUpdate, interval time.Duration) {
+	switch {
+	case interval > maxDelay:
+		update.Interval = maxDelay
+	case interval < minDelay:
+		update.Interval = minDelay
+	default:
+		update.Interval = interval
+	}
+
+	// Add a jitter of 5% on either side of the interval to avoid
+	// repos getting updated at the same time.
+	delta := int64(update.Interval) / 20
+	update.Interval = update.Interval + time.Duration(s.randGenerator.Int63n(2*delta)-delta)
+
+	update.Due = timeNow().Add(update.Interval)
+	s.markDirty(update)
+}
+
+// webhookInterval returns the interval a repo should be polled in, given the
+// interval computed from its update history. Repos that recently received a
+// push webhook are updated by the webhook, so they only need to be polled
+// rarely as a fallback for missed webhooks.
+func (s *schedule) webhookInterval(repo configuredRepo, interval time.Duration) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.coveredByWebhooks(repo.ID) && interval < webhookMinDelay {
+		return webhookMinDelay
+	}
+	return interval
+}
+
+// coveredByWebhooks returns true if the repo received a push webhook recently.
+// The caller must hold the lock on s.mu.
+func (s *schedule) coveredByWebhooks(id api.RepoID) bool {
+	at, ok := s.webhooks[id]
+	return ok && timeNow().Sub(at) < webhookCoverage
+}
+
+// recordWebhooks records the given last webhook times. Repos that are now
+// covered by webhooks have their polling interval relaxed right away.
+func (s *schedule) recordWebhooks(webhooks map[api.RepoID]time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.webhooks == nil {
+		s.webhooks = make(map[api.RepoID]time.Time, len(webhooks))
+	}
+
+	rescheduleTimer := false
+	for id, at := range webhooks {
+		if prev, ok := s.webhooks[id]; ok && !at.After(prev) {
+			continue
+		}
+		s.webhooks[id] = at
+
+		if update := s.index[id]; update != nil && update.Interval < webhookMinDelay && s.coveredByWebhooks(id) {
+			s.setInterval(update, webhookMinDelay)
+			heap.Fix(s, update.Index)
+			rescheduleTimer = true
+		}
+	}
+
+	// Forget about webhooks that no longer matter.
+	for id, at := range s.webhooks {
+		if timeNow().Sub(at) >= webhookCoverage {
+			delete(s.webhooks, id)
+		}
+	}
+
+	if rescheduleTimer {
+		s.rescheduleTimer()
+	}
+}
+
 // getCurrentInterval gets the current interval for the supplied repo and a bool
 // indicating whether it was found.
 func (s *schedule) getCurrentInterval(repo configuredRepo) (time.Duration, bool) {
@@ -185,9 +255,134 @@ func (s *schedule) remove(repo configuredRepo) (removed bool) {
 		s.rescheduleTimer()
 	}
 
+	if s.removed == nil {
+		s.removed = make(map[api.RepoID]struct{})
+	}
+	delete(s.dirty, repo.ID)
+	delete(s.webhooks, repo.ID)
+	s.removed[repo.ID] = struct{}{}
+
 	return true
 }
 
+// newUpdate returns a new schedule entry for repo that is due at the given
+// time, unless a schedule for the repo was restored from the database, in
+// which case the restored interval and due time are used.
+// The caller must hold the lock on s.mu.
+func (s *schedule) newUpdate(repo configuredRepo, due time.Time) *scheduledRepoUpdate {
+	update := &scheduledRepoUpdate{
+		Repo:     repo,
+		Interval: minDelay,
+		Due:      due,
+	}
+
+	if persisted, ok := s.persisted[repo.ID]; ok {
+		delete(s.persisted, repo.ID)
+		if persisted.Interval > 0 {
+			update.Interval = persisted.Interval
+			update.Due = persisted.Due
+		}
+	}
+
+	return update
+}
+
+// restore restores the given persisted schedule. Repos that are already in the
+// schedule are updated immediately, the others once they are added.
+func (s *schedule) restore(entries []database.RepoUpdateScheduleEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.persisted = make(map[api.RepoID]database.RepoUpdateScheduleEntry, len(entries))
+	if s.webhooks == nil {
+		s.webhooks = make(map[api.RepoID]time.Time)
+	}
+
+	rescheduleTimer := false
+	for _, e := range entries {
+		if e.LastWebhookAt != nil && timeNow().Sub(*e.LastWebhookAt) < webhookCoverage {
+			s.webhooks[e.RepoID] = *e.LastWebhookAt
+		}
+
+		update := s.index[e.RepoID]
+		if update == nil {
+			s.persisted[e.RepoID] = e
+			continue
+		}
+		if e.Interval > 0 {
+			update.Interval = e.Interval
+			update.Due = e.Due
+			heap.Fix(s, update.Index)
+			rescheduleTimer = true
+		}
+	}
+
+	if rescheduleTimer {
+		s.rescheduleTimer()
+	}
+}
+
+// markDirty records that the schedule of update needs to be persisted.
+// The caller must hold the lock on s.mu.
+func (s *schedule) markDirty(update *scheduledRepoUpdate) {
+	if s.dirty == nil {
+		s.dirty = make(map[api.RepoID]database.RepoUpdateScheduleEntry)
+	}
+	delete(s.removed, update.Repo.ID)
+	s.dirty[update.Repo.ID] = database.RepoUpdateScheduleEntry{
+		RepoID:   update.Repo.ID,
+		Interval: update.Interval,
+		Due:      update.Due,
+	}
+}
+
+// takeChanges returns and forgets the changes that haven't been persisted yet.
+func (s *schedule) takeChanges() (dirty []database.RepoUpdateScheduleEntry, removed []api.RepoID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.dirty {
+		dirty = append(dirty, e)
+	}
+	for id := range s.removed {
+		removed = append(removed, id)
+	}
+	s.dirty = nil
+	s.removed = nil
+
+	return dirty, removed
+}
+
+// returnChanges gives back changes taken by takeChanges that could not be
+// persisted, unless they have been superseded in the meantime.
+func (s *schedule) returnChanges(dirty []database.RepoUpdateScheduleEntry, removed []api.RepoID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.dirty == nil {
+		s.dirty = make(map[api.RepoID]database.RepoUpdateScheduleEntry, len(dirty))
+	}
+	if s.removed == nil {
+		s.removed = make(map[api.RepoID]struct{}, len(removed))
+	}
+
+	for _, e := range dirty {
+		if _, ok := s.dirty[e.RepoID]; ok {
+			continue
+		}
+		if _, ok := s.removed[e.RepoID]; ok {
+			continue
+		}
+		s.dirty[e.RepoID] = e
+	}
+	for _, id := range removed {
+		if _, ok := s.dirty[id]; ok {
+			continue
+		}
+		s.removed[id] = struct{}{}
+	}
+}
+
 // rescheduleTimer schedules the scheduler to wakeup
 // at the time that the next repo is due for an update.
 // The caller must hold the lock on s.mu.
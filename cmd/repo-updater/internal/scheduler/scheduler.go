@@ -15,6 +15,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/limiter"
 	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
@@ -28,6 +29,24 @@ const (
 
 	// maxDelay is the maximum amount of time between scheduled updates for a single repository.
 	maxDelay = 8 * time.Hour
+
+	// webhookMinDelay is the minimum amount of time between scheduled updates for a repository
+	// that is covered by push webhooks.
+	webhookMinDelay = 4 * time.Hour
+
+	// webhookCoverage is how long a repository is considered to be covered by push webhooks
+	// after the last webhook was received for it.
+	webhookCoverage = 24 * time.Hour
+
+	// webhookPollOverlap is how far before the most recent push webhook seen the
+	// next poll starts. Webhooks are timestamped by the database when their
+	// transaction starts, so one that commits late can carry a time older than
+	// webhooks already seen. Webhooks seen twice are ignored.
+	webhookPollOverlap = time.Minute
+
+	// persistInterval is how often the schedule is written to the database and new push
+	// webhooks are read from it.
+	persistInterval = 30 * time.Second
 )
 
 // UpdateScheduler schedules repo update (or clone) requests to gitserver.
@@ -44,10 +63,16 @@ const (
 // backoff by doubling the current interval. This ensures that problematic repos
 // don't stay in the front of the schedule clogging up the queue.
 //
+// Repos that recently received a push webhook are updated by the webhook, so they
+// are polled at most every webhookMinDelay as a fallback.
+//
+// The learned intervals are persisted in the database, so they survive restarts.
+//
 // When it is time for a repo to update, the scheduler inserts the repo into a queue.
 //
 // A worker continuously dequeues repos and sends updates to gitserver, but its concurrency
-// is limited by the gitMaxConcurrentClones site configuration.
+// is limited by the gitMaxConcurrentClones site configuration. Repos of different code host
+// connections are dequeued fairly.
 type UpdateScheduler struct {
 	db              database.DB
 	gitserverClient gitserver.Client
@@ -55,6 +80,12 @@ type UpdateScheduler struct {
 	schedule        *schedule
 	logger          log.Logger
 	cancelCtx       context.CancelFunc
+
+	// persistDone is closed once the final flush of the schedule finished.
+	persistDone chan struct{}
+	// webhooksSince is the time of the most recent push webhook seen, as
+	// recorded by the database.
+	webhooksSince time.Time
 }
 
 // A configuredRepo represents the configuration data for a given repo from
@@ -63,6 +94,9 @@ type UpdateScheduler struct {
 type configuredRepo struct {
 	ID   api.RepoID
 	Name api.RepoName
+	// ExternalServiceID is the code host connection the repo is updated
+	// through, used to fairly schedule updates across connections.
+	ExternalServiceID int64
 }
 
 // notifyChanBuffer controls the buffer size of notification channels.
@@ -97,6 +131,14 @@ func (s *UpdateScheduler) Start() {
 	ctx, cancel := context.WithCancel(actor.WithInternalActor(context.Background()))
 	s.cancelCtx = cancel
 
+	s.restoreSchedule(ctx)
+
+	s.persistDone = make(chan struct{})
+	go func() {
+		defer close(s.persistDone)
+		s.runPersistLoop(ctx)
+	}()
+
 	go s.runUpdateLoop(ctx)
 	go s.runScheduleLoop(ctx)
 }
@@ -105,6 +147,98 @@ func (s *UpdateScheduler) Stop() {
 	if s.cancelCtx != nil {
 		s.cancelCtx()
 	}
+	if s.persistDone != nil {
+		<-s.persistDone
+	}
+}
+
+// restoreSchedule loads the schedule persisted by a previous process.
+func (s *UpdateScheduler) restoreSchedule(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	// Only webhooks after the restored ones need to be polled for.
+	s.webhooksSince = timeNow().Add(-webhookCoverage)
+
+	entries, err := s.db.RepoUpdateSchedule().List(ctx)
+	if err != nil {
+		schedError.WithLabelValues("restoreSchedule").Inc()
+		s.logger.Warn("failed to restore update schedule, starting from scratch", log.Error(err))
+		return
+	}
+
+	for _, e := range entries {
+		if e.LastWebhookAt != nil && e.LastWebhookAt.After(s.webhooksSince) {
+			s.webhooksSince = *e.LastWebhookAt
+		}
+	}
+
+	s.schedule.restore(entries)
+	s.logger.Info("restored update schedule", log.Int("repos", len(entries)))
+}
+
+// runPersistLoop periodically writes changes of the schedule to the database
+// and picks up push webhooks that have been received since.
+func (s *UpdateScheduler) runPersistLoop(ctx context.Context) {
+	ticker := time.NewTicker(persistInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			// Write the final state, so we don't lose what we learned since the
+			// last flush.
+			ctx, cancel := context.WithTimeout(actor.WithInternalActor(context.Background()), 10*time.Second)
+			s.persistSchedule(ctx)
+			cancel()
+			return
+		}
+
+		s.persistSchedule(ctx)
+		s.pollWebhooks(ctx)
+	}
+}
+
+// persistSchedule writes the changes of the schedule since the last call to
+// the database.
+func (s *UpdateScheduler) persistSchedule(ctx context.Context) {
+	dirty, removed := s.schedule.takeChanges()
+	if len(dirty) == 0 && len(removed) == 0 {
+		return
+	}
+
+	store := s.db.RepoUpdateSchedule()
+	if err := store.Upsert(ctx, dirty); err != nil {
+		schedError.WithLabelValues("persistSchedule").Inc()
+		s.logger.Warn("failed to persist update schedule", log.Error(err))
+		s.schedule.returnChanges(dirty, removed)
+		return
+	}
+	if err := store.Delete(ctx, removed...); err != nil {
+		schedError.WithLabelValues("persistSchedule").Inc()
+		s.logger.Warn("failed to delete update schedule of removed repos", log.Error(err))
+		s.schedule.returnChanges(nil, removed)
+	}
+}
+
+// pollWebhooks reads the push webhooks that have been received since the last
+// poll from the database.
+func (s *UpdateScheduler) pollWebhooks(ctx context.Context) {
+	webhooks, err := s.db.RepoUpdateSchedule().ListWebhooksSince(ctx, s.webhooksSince.Add(-webhookPollOverlap))
+	if err != nil {
+		schedError.WithLabelValues("pollWebhooks").Inc()
+		s.logger.Warn("failed to poll push webhooks", log.Error(err))
+		return
+	}
+
+	for _, at := range webhooks {
+		if at.After(s.webhooksSince) {
+			s.webhooksSince = at
+		}
+	}
+
+	s.schedule.recordWebhooks(webhooks)
 }
 
 // runScheduleLoop starts the loop that schedules updates by enqueuing them into the updateQueue.
@@ -140,6 +274,7 @@ func (s *UpdateScheduler) runSchedule() {
 		schedAutoFetch.Inc()
 		s.updateQueue.enqueue(repoUpdate.Repo, priorityLow)
 		repoUpdate.Due = timeNow().Add(repoUpdate.Interval)
+		s.schedule.markDirty(repoUpdate)
 		heap.Fix(s.schedule, 0)
 	}
 }
@@ -201,13 +336,13 @@ func (s *UpdateScheduler) runUpdateLoop(ctx context.Context) {
 					// On error we will double the current interval so that we back off and don't
 					// get stuck with problematic repos with low intervals.
 					if currentInterval, ok := s.schedule.getCurrentInterval(repo); ok {
-						s.schedule.updateInterval(repo, currentInterval*2)
+						s.schedule.updateInterval(repo, s.schedule.webhookInterval(repo, currentInterval*2))
 					}
 				} else if resp != nil && resp.LastFetched != nil && resp.LastChanged != nil {
 					// This is the heuristic that is described in the UpdateScheduler documentation.
 					// Update that documentation if you update this logic.
 					interval := resp.LastFetched.Sub(*resp.LastChanged) / 2
-					s.schedule.updateInterval(repo, interval)
+					s.schedule.updateInterval(repo, s.schedule.webhookInterval(repo, interval))
 				}
 			}(ctx, repo, cancel)
 		}
@@ -347,6 +482,14 @@ func configuredRepoFromRepo(r *types.Repo) configuredRepo {
 		Name: r.Name,
 	}
 
+	// Attribute the repo to the oldest connection it belongs to, so that it's
+	// stable across syncs.
+	for urn := range r.Sources {
+		if _, id := extsvc.DecodeURN(urn); id != 0 && (repo.ExternalServiceID == 0 || id < repo.ExternalServiceID) {
+			repo.ExternalServiceID = id
+		}
+	}
+
 	return repo
 }
 
@@ -357,6 +500,13 @@ func (s *UpdateScheduler) UpdateOnce(id api.RepoID, name api.RepoName) {
 		ID:   id,
 		Name: name,
 	}
+
+	s.schedule.mu.Lock()
+	if update := s.schedule.index[id]; update != nil {
+		repo.ExternalServiceID = update.Repo.ExternalServiceID
+	}
+	s.schedule.mu.Unlock()
+
 	schedManualFetch.Inc()
 	s.updateQueue.enqueue(repo, priorityHigh)
 }
@@ -367,6 +517,7 @@ func (s *UpdateScheduler) DebugDump(ctx context.Context) any {
 		Name        string
 		UpdateQueue []*repoUpdate
 		Schedule    []*scheduledRepoUpdate
+		Webhooks    map[api.RepoID]time.Time
 		SyncJobs    []*types.ExternalServiceSyncJob
 	}{
 		Name: "repos",
@@ -383,6 +534,10 @@ func (s *UpdateScheduler) DebugDump(ctx context.Context) any {
 		updateCopy := *update
 		schedule.heap[i] = &updateCopy
 	}
+	data.Webhooks = make(map[api.RepoID]time.Time, len(s.schedule.webhooks))
+	for id, at := range s.schedule.webhooks {
+		data.Webhooks[id] = at
+	}
 	s.schedule.mu.Unlock()
 
 	for len(schedule.heap) > 0 {
@@ -3,6 +3,7 @@ package scheduler
 import (
 	"container/heap"
 	"context"
+	"fmt"
 	"reflect"
 	"testing"
 	"time"
@@ -14,11 +15,13 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbmocks"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	gitserverprotocol "github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/limiter"
 	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
 	"github.com/sourcegraph/sourcegraph/lib/pointers"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
@@ -1530,6 +1533,14 @@ func Test_updateQueue_Less(t *testing.T) {
 			},
 			expVal: true,
 		},
+		{
+			name: "connection",
+			heap: []*repoUpdate{
+				{Seq: 1, Repo: configuredRepo{ExternalServiceID: 1}},
+				{Seq: 1, Repo: configuredRepo{ExternalServiceID: 2}},
+			},
+			expVal: true,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -1614,3 +1625,189 @@ func TestGetCustomInterval(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateQueue_fairness(t *testing.T) {
+	_, stop := startRecording()
+	defer stop()
+
+	s := NewUpdateScheduler(logtest.Scoped(t), dbmocks.NewMockDB(), gitserver.NewMockClient())
+
+	// A big connection enqueues all of its repos at once.
+	for i := 1; i <= 4; i++ {
+		s.updateQueue.enqueue(configuredRepo{ID: api.RepoID(i), Name: api.RepoName(fmt.Sprintf("big/%d", i)), ExternalServiceID: 1}, priorityLow)
+	}
+
+	// The first one is being updated when a small connection enqueues its repos.
+	repo, ok := s.updateQueue.acquireNext()
+	if !ok || repo.ID != 1 {
+		t.Fatalf("expected to acquire repo 1, got %v", repo)
+	}
+	s.updateQueue.enqueue(configuredRepo{ID: 10, Name: "small/1", ExternalServiceID: 2}, priorityLow)
+	s.updateQueue.enqueue(configuredRepo{ID: 11, Name: "small/2", ExternalServiceID: 2}, priorityLow)
+
+	var got []api.RepoName
+	for {
+		repo, ok := s.updateQueue.acquireNext()
+		if !ok {
+			break
+		}
+		got = append(got, repo.Name)
+	}
+
+	want := []api.RepoName{"big/2", "small/1", "big/3", "small/2", "big/4"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected update order (-want +got):\n%s", diff)
+	}
+}
+
+func TestSchedule_webhooks(t *testing.T) {
+	a := configuredRepo{ID: 1, Name: "a"}
+	b := configuredRepo{ID: 2, Name: "b"}
+
+	_, stop := startRecording()
+	defer stop()
+
+	s := NewUpdateScheduler(logtest.Scoped(t), dbmocks.NewMockDB(), gitserver.NewMockClient())
+	s.schedule.randGenerator = &mockRandomGenerator{}
+
+	setupInitialSchedule(s, []*scheduledRepoUpdate{
+		{Repo: a, Interval: minDelay, Due: defaultTime.Add(minDelay)},
+		{Repo: b, Interval: minDelay, Due: defaultTime.Add(minDelay)},
+	})
+
+	s.schedule.recordWebhooks(map[api.RepoID]time.Time{
+		a.ID: defaultTime.Add(-time.Minute),
+		// Too old to still count.
+		b.ID: defaultTime.Add(-webhookCoverage),
+	})
+
+	if got := s.schedule.webhookInterval(a, time.Minute); got != webhookMinDelay {
+		t.Fatalf("expected interval of repo covered by webhooks to be relaxed to %s, got %s", webhookMinDelay, got)
+	}
+	if got := s.schedule.webhookInterval(b, time.Minute); got != time.Minute {
+		t.Fatalf("expected interval of repo not covered by webhooks to stay %s, got %s", time.Minute, got)
+	}
+	if got := s.schedule.webhookInterval(a, maxDelay); got != maxDelay {
+		t.Fatalf("expected interval above the webhook minimum to stay %s, got %s", maxDelay, got)
+	}
+
+	verifySchedule(t, s, []*scheduledRepoUpdate{
+		{Repo: b, Interval: minDelay, Due: defaultTime.Add(minDelay)},
+		{Repo: a, Interval: webhookMinDelay, Due: defaultTime.Add(webhookMinDelay)},
+	})
+}
+
+func TestUpdateScheduler_persistence(t *testing.T) {
+	a := configuredRepo{ID: 1, Name: "a"}
+	b := configuredRepo{ID: 2, Name: "b"}
+	c := configuredRepo{ID: 3, Name: "c"}
+
+	_, stop := startRecording()
+	defer stop()
+
+	store := dbmocks.NewMockRepoUpdateScheduleStore()
+	store.ListFunc.SetDefaultReturn([]database.RepoUpdateScheduleEntry{
+		{RepoID: a.ID, Interval: time.Hour, Due: defaultTime.Add(30 * time.Minute)},
+		{RepoID: b.ID, Interval: 2 * time.Hour, Due: defaultTime.Add(time.Hour), LastWebhookAt: pointers.Ptr(defaultTime.Add(-time.Hour))},
+		// Created by a webhook, nothing learned yet.
+		{RepoID: c.ID, Due: defaultTime, LastWebhookAt: pointers.Ptr(defaultTime.Add(-time.Minute))},
+	}, nil)
+	db := dbmocks.NewMockDB()
+	db.RepoUpdateScheduleFunc.SetDefaultReturn(store)
+
+	s := NewUpdateScheduler(logtest.Scoped(t), db, gitserver.NewMockClient())
+	s.schedule.randGenerator = &mockRandomGenerator{}
+
+	// a is known before the persisted schedule is restored, b and c after.
+	s.schedule.upsert(a)
+	s.restoreSchedule(context.Background())
+	s.schedule.upsert(b)
+	s.schedule.upsert(c)
+
+	if want := defaultTime.Add(-time.Minute); !s.webhooksSince.Equal(want) {
+		t.Fatalf("expected to poll webhooks since %s, got %s", want, s.webhooksSince)
+	}
+
+	verifySchedule(t, s, []*scheduledRepoUpdate{
+		{Repo: c, Interval: minDelay, Due: defaultTime.Add(minDelay)},
+		{Repo: a, Interval: time.Hour, Due: defaultTime.Add(30 * time.Minute)},
+		{Repo: b, Interval: 2 * time.Hour, Due: defaultTime.Add(time.Hour)},
+	})
+	if got := s.schedule.webhookInterval(c, time.Minute); got != webhookMinDelay {
+		t.Fatalf("expected restored webhook to relax interval to %s, got %s", webhookMinDelay, got)
+	}
+
+	// Nothing changed yet, so nothing is written.
+	s.persistSchedule(context.Background())
+	if len(store.UpsertFunc.History()) != 0 || len(store.DeleteFunc.History()) != 0 {
+		t.Fatal("expected no writes")
+	}
+
+	setupInitialSchedule(s, []*scheduledRepoUpdate{
+		{Repo: a, Interval: time.Hour, Due: defaultTime.Add(30 * time.Minute)},
+		{Repo: b, Interval: 2 * time.Hour, Due: defaultTime.Add(time.Hour)},
+	})
+	s.schedule.updateInterval(a, 3*time.Hour)
+	s.schedule.remove(b)
+
+	s.persistSchedule(context.Background())
+
+	if history := store.UpsertFunc.History(); len(history) != 1 {
+		t.Fatalf("expected one upsert, got %d", len(history))
+	} else if diff := cmp.Diff([]database.RepoUpdateScheduleEntry{
+		{RepoID: a.ID, Interval: 3 * time.Hour, Due: defaultTime.Add(3 * time.Hour)},
+	}, history[0].Arg1); diff != "" {
+		t.Fatalf("unexpected upserted entries (-want +got):\n%s", diff)
+	}
+	if history := store.DeleteFunc.History(); len(history) != 1 {
+		t.Fatalf("expected one delete, got %d", len(history))
+	} else if diff := cmp.Diff([]api.RepoID{b.ID}, history[0].Arg1); diff != "" {
+		t.Fatalf("unexpected deleted repos (-want +got):\n%s", diff)
+	}
+
+	// Failed writes are retried on the next flush.
+	store.UpsertFunc.PushReturn(errors.New("boom"))
+	s.schedule.updateInterval(a, 4*time.Hour)
+	s.persistSchedule(context.Background())
+	s.persistSchedule(context.Background())
+
+	if history := store.UpsertFunc.History(); len(history) != 3 {
+		t.Fatalf("expected three upserts, got %d", len(history))
+	} else if diff := cmp.Diff(history[1].Arg1, history[2].Arg1); diff != "" {
+		t.Fatalf("expected failed upsert to be retried (-first +retry):\n%s", diff)
+	}
+}
+
+func TestUpdateScheduler_pollWebhooks(t *testing.T) {
+	a := configuredRepo{ID: 1, Name: "a"}
+
+	_, stop := startRecording()
+	defer stop()
+
+	store := dbmocks.NewMockRepoUpdateScheduleStore()
+	store.ListWebhooksSinceFunc.PushReturn(map[api.RepoID]time.Time{a.ID: defaultTime.Add(-time.Hour)}, nil)
+	// A webhook that committed late, with a time before the one already seen.
+	store.ListWebhooksSinceFunc.PushReturn(map[api.RepoID]time.Time{a.ID: defaultTime.Add(-time.Hour - time.Second)}, nil)
+	db := dbmocks.NewMockDB()
+	db.RepoUpdateScheduleFunc.SetDefaultReturn(store)
+
+	s := NewUpdateScheduler(logtest.Scoped(t), db, gitserver.NewMockClient())
+	s.webhooksSince = defaultTime.Add(-webhookCoverage)
+
+	s.pollWebhooks(context.Background())
+	s.pollWebhooks(context.Background())
+
+	history := store.ListWebhooksSinceFunc.History()
+	if len(history) != 2 {
+		t.Fatalf("expected two polls, got %d", len(history))
+	}
+	// Each poll overlaps with the previous one, starting before the most
+	// recent webhook seen.
+	if want := defaultTime.Add(-time.Hour - webhookPollOverlap); !history[1].Arg1.Equal(want) {
+		t.Fatalf("expected second poll since %s, got %s", want, history[1].Arg1)
+	}
+	// The watermark does not move backwards.
+	if want := defaultTime.Add(-time.Hour); !s.webhooksSince.Equal(want) {
+		t.Fatalf("expected to poll webhooks since %s, got %s", want, s.webhooksSince)
+	}
+}
@@ -10,13 +10,22 @@ import (
 // updateQueue is a priority queue of repos to update.
 // A repo can't have more than one location in the queue.
 // Implements heap.Interface and sort.Interface.
+//
+// Within a priority, repos are ordered fairly across code host connections:
+// every connection has its own sequence of updates which starts at the
+// sequence number of the update that was acquired last. That way a
+// connection that enqueues many repos at once doesn't delay the updates of
+// the other connections until all of its repos are updated.
 type updateQueue struct {
 	mu sync.Mutex
 
 	heap  []*repoUpdate
 	index map[api.RepoID]*repoUpdate
 
-	seq uint64
+	// seq is the last sequence number handed out per code host connection.
+	seq map[int64]uint64
+	// vtime is the highest sequence number that has been acquired for update.
+	vtime uint64
 
 	// The queue performs a non-blocking send on this channel
 	// when a new value is enqueued so that the update loop
@@ -30,7 +39,8 @@ func (q *updateQueue) reset() {
 
 	q.heap = q.heap[:0]
 	q.index = map[api.RepoID]*repoUpdate{}
-	q.seq = 0
+	q.seq = map[int64]uint64{}
+	q.vtime = 0
 	q.notifyEnqueue = make(chan struct{}, notifyChanBuffer)
 
 	schedUpdateQueueLength.Set(0)
@@ -72,19 +82,24 @@ func (q *updateQueue) enqueue(repo configuredRepo, p priority) (updated bool) {
 	}
 
 	// Repo is in the queue at a lower priority.
-	update.Priority = p      // bump the priority
-	update.Seq = q.nextSeq() // put it after all existing updates with this priority
+	update.Priority = p          // bump the priority
+	update.Seq = q.nextSeq(repo) // put it after all existing updates of its connection with this priority
 	heap.Fix(q, update.Index)
 	notify(q.notifyEnqueue)
 
 	return true
 }
 
-// nextSeq increments and returns the next sequence number.
+// nextSeq increments and returns the next sequence number of the code host
+// connection of repo.
 // The caller must hold the lock on q.mu.
-func (q *updateQueue) nextSeq() uint64 {
-	q.seq++
-	return q.seq
+func (q *updateQueue) nextSeq(repo configuredRepo) uint64 {
+	if q.seq == nil {
+		q.seq = map[int64]uint64{}
+	}
+	seq := max(q.seq[repo.ExternalServiceID], q.vtime) + 1
+	q.seq[repo.ExternalServiceID] = seq
+	return seq
 }
 
 // remove removes the repo from the queue if the repo.Updating matches the updating argument.
@@ -120,6 +135,9 @@ func (q *updateQueue) acquireNext() (configuredRepo, bool) {
 		return configuredRepo{}, false
 	}
 	update.Updating = true
+	if update.Seq > q.vtime {
+		q.vtime = update.Seq
+	}
 	heap.Fix(q, update.Index)
 	return update.Repo, true
 }
@@ -148,7 +166,11 @@ func (q *updateQueue) Less(i, j int) bool {
 		return qi.Priority > qj.Priority
 	}
 	// Queue semantics for items with the same priority.
-	return qi.Seq < qj.Seq
+	if qi.Seq != qj.Seq {
+		return qi.Seq < qj.Seq
+	}
+	// Round-robin between connections for items with the same sequence number.
+	return qi.Repo.ExternalServiceID < qj.Repo.ExternalServiceID
 }
 
 func (q *updateQueue) Swap(i, j int) {
@@ -161,7 +183,7 @@ func (q *updateQueue) Push(x any) {
 	n := len(q.heap)
 	item := x.(*repoUpdate)
 	item.Index = n
-	item.Seq = q.nextSeq()
+	item.Seq = q.nextSeq(item.Repo)
 	q.heap = append(q.heap, item)
 	q.index[item.Repo.ID] = item
 }
@@ -23,7 +23,6 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/comby"
-	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
 	"github.com/sourcegraph/sourcegraph/internal/trace"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -118,15 +117,6 @@ type rangeChunk struct {
 	ranges []protocol.Range
 }
 
-var isValidMatcher = lazyregexp.New(`\.(s|sh|bib|c|cs|css|dart|clj|elm|erl|ex|f|fsx|go|html|hs|java|js|json|jl|kt|tex|lisp|nim|md|ml|org|pas|php|py|re|rb|rs|rst|scala|sql|swift|tex|txt|ts)$`)
-
-func extensionToMatcher(extension string) string {
-	if isValidMatcher.MatchString(extension) {
-		return extension
-	}
-	return ".generic"
-}
-
 // lookupMatcher looks up a key for specifying -matcher in comby. Comby accepts
 // a representative file extension to set a language, so this lookup does not
 // need to consider all possible file extensions for a language. There is a generic
@@ -298,7 +288,7 @@ func toMatcher(languages []string, extensionHint string) string {
 	}
 
 	if extensionHint != "" {
-		extension := extensionToMatcher(extensionHint)
+		extension := comby.ExtensionToMatcher(extensionHint)
 		metricRequestTotalStructuralSearch.WithLabelValues("inferred:" + extension).Inc()
 		return extension
 	}
@@ -14,6 +14,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker"
 	dbworkerstore "github.com/sourcegraph/sourcegraph/internal/workerutil/dbworker/store"
@@ -73,9 +74,26 @@ func (h *permsSyncerWorker) Handle(ctx context.Context, _ log.Logger, record *da
 		log.Int("priority", int(record.Priority)),
 	)
 
+	// Syncs triggered by users get code host rate limit budget before scheduled
+	// background syncs.
+	ctx = ratelimit.WithConsumer(ratelimit.WithPriority(ctx, rateLimitPriority(record.Priority)), "perms-sync")
+
 	return h.handlePermsSync(ctx, reqType, reqID, record.ID, record.NoPerms, record.InvalidateCaches)
 }
 
+// rateLimitPriority maps the priority of a permissions sync job to the priority
+// class of its code host requests.
+func rateLimitPriority(p database.PermissionsSyncJobPriority) ratelimit.Priority {
+	switch p {
+	case database.HighPriorityPermissionsSync:
+		return ratelimit.PriorityInteractive
+	case database.MediumPriorityPermissionsSync:
+		return ratelimit.PriorityDefault
+	default:
+		return ratelimit.PriorityBackground
+	}
+}
+
 // handlePermsSync is effectively a sync version of `perms_syncer.syncPerms`
 // which calls `perms_syncer.syncUserPerms` or `perms_syncer.syncRepoPerms`
 // depending on a request type and logs/adds metrics of sync statistics
@@ -35,6 +35,8 @@ func (j *schedulerJob) Routines(_ context.Context, observationCtx *observation.C
 
 	routines := []goroutine.BackgroundRoutine{
 		scheduler.NewScheduler(workCtx, bstore),
+		scheduler.NewRolloutAdvancer(workCtx, bstore),
+		scheduler.NewContinuousRunner(workCtx, bstore),
 	}
 
 	return routines, nil
@@ -0,0 +1,110 @@
+package searchcontexts
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/log"
+
+	"github.com/sourcegraph/sourcegraph/cmd/worker/job"
+	workerdb "github.com/sourcegraph/sourcegraph/cmd/worker/shared/init/db"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/dependencies"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/own"
+	"github.com/sourcegraph/sourcegraph/internal/search/searchcontexts"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const (
+	// evaluationInterval is how often the repositories of a dynamic search
+	// context are recomputed.
+	evaluationInterval = time.Hour
+	// batchSize is the maximum number of search contexts evaluated per run.
+	batchSize = 100
+)
+
+type dynamicUpdater struct{}
+
+var _ job.Job = &dynamicUpdater{}
+
+func NewDynamicUpdater() job.Job {
+	return &dynamicUpdater{}
+}
+
+func (j *dynamicUpdater) Description() string {
+	return "searchcontexts.DynamicUpdater periodically recomputes the repositories of dynamic search contexts."
+}
+
+func (j *dynamicUpdater) Config() []env.Config {
+	return nil
+}
+
+func (j *dynamicUpdater) Routines(_ context.Context, observationCtx *observation.Context) ([]goroutine.BackgroundRoutine, error) {
+	db, err := workerdb.InitDB(observationCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	return []goroutine.BackgroundRoutine{
+		goroutine.NewPeriodicGoroutine(
+			actor.WithInternalActor(context.Background()),
+			&handler{
+				db:              db,
+				ownedRepos:      own.NewService(gitserver.NewClient("searchcontexts.dynamic"), db),
+				dependencyRepos: dependencies.NewService(observationCtx, db),
+				logger:          observationCtx.Logger,
+			},
+			goroutine.WithName("search.dynamic-search-context-updater"),
+			goroutine.WithDescription("recomputes the repositories of dynamic search contexts"),
+			goroutine.WithInterval(5*time.Minute),
+		),
+	}, nil
+}
+
+type handler struct {
+	db              database.DB
+	ownedRepos      searchcontexts.OwnedReposLister
+	dependencyRepos searchcontexts.DependencyReposLister
+	logger          log.Logger
+}
+
+var (
+	_ goroutine.Handler      = &handler{}
+	_ goroutine.ErrorHandler = &handler{}
+)
+
+func (h *handler) Handle(ctx context.Context) error {
+	stale, err := h.db.SearchContexts().ListDynamicSearchContexts(ctx, time.Now().Add(-evaluationInterval), batchSize)
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	for _, sc := range stale {
+		repoIDs, err := searchcontexts.EvaluateDynamicSearchContext(ctx, h.db, h.ownedRepos, h.dependencyRepos, sc)
+		if err == nil {
+			err = h.db.SearchContexts().SetDynamicSearchContextRepositories(ctx, sc.ID, repoIDs)
+		}
+		if err == nil {
+			continue
+		}
+
+		// Keep the previous repositories and only retry once the search context is
+		// stale again, so that failing search contexts don't starve the others and
+		// each failure is reported once per evaluation interval.
+		h.logger.Warn("failed to evaluate dynamic search context", log.Int64("searchContextID", sc.ID), log.Error(err))
+		if err := h.db.SearchContexts().MarkDynamicSearchContextEvaluated(ctx, sc.ID); err != nil {
+			errs = errors.Append(errs, errors.Wrapf(err, "search context %d", sc.ID))
+		}
+	}
+	return errs
+}
+
+func (h *handler) HandleError(err error) {
+	h.logger.Error("error updating dynamic search contexts", log.Error(err))
+}
@@ -169,6 +169,15 @@ type ConfigSavedQuery struct {
 	UserID          *int32  `json:"userID"`
 	OrgID           *int32  `json:"orgID"`
 	SlackWebhookURL *string `json:"slackWebhookURL"`
+
+	Variables []SavedQueryVariable `json:"variables,omitempty"`
+}
+
+// SavedQueryVariable is a typed variable declared by a saved query, which its
+// query references as $name.
+type SavedQueryVariable struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
 }
 
 // SavedQuerySpecAndConfig represents a saved query configuration its unique ID.
@@ -0,0 +1,92 @@
+package subrepoperms
+
+import (
+	"strings"
+)
+
+// ruleTrie indexes rules by the directories in the literal prefix of their
+// pattern, i.e. the part before the first glob meta character. A rule can only
+// match paths that start with its literal prefix, so to find the rules that
+// may match a path we only need to walk the trie along the directories of the
+// path instead of trying every rule. Perforce protection tables commonly
+// consist of thousands of `//depot/some/dir/...` lines, which all have a long
+// literal prefix.
+type ruleTrie struct {
+	children map[string]*ruleTrie
+	// rules are indexes into compiledRules.paths in ascending order.
+	rules []int
+}
+
+// newRuleTrie builds a trie of the given rules. Rules with the same pattern
+// as a later rule are left out, since the later rule always takes
+// precedence.
+func newRuleTrie(paths []path) *ruleTrie {
+	last := make(map[string]int, len(paths))
+	for i, p := range paths {
+		last[p.original] = i
+	}
+
+	root := &ruleTrie{}
+	for i, p := range paths {
+		if last[p.original] != i {
+			continue
+		}
+		node := root
+		for _, dir := range literalDirs(p.original) {
+			child, ok := node.children[dir]
+			if !ok {
+				if node.children == nil {
+					node.children = map[string]*ruleTrie{}
+				}
+				child = &ruleTrie{}
+				node.children[dir] = child
+			}
+			node = child
+		}
+		node.rules = append(node.rules, i)
+	}
+	return root
+}
+
+// literalDirs returns the directories of the literal prefix of a rule
+// pattern. For example, the directories of "/depot/main/*.go" are "depot" and
+// "main".
+func literalDirs(pattern string) []string {
+	if end := strings.IndexAny(pattern, `*?[{\`); end >= 0 {
+		pattern = pattern[:end]
+	}
+	lastSlash := strings.LastIndexByte(pattern, '/')
+	if lastSlash <= 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimPrefix(pattern[:lastSlash], "/"), "/")
+}
+
+// appendCandidates appends the rule indexes of all nodes along the
+// directories of path, which has to start with a slash, to lists. Each
+// appended element is in ascending order.
+func (t *ruleTrie) appendCandidates(lists [][]int, path string) [][]int {
+	if len(t.rules) > 0 {
+		lists = append(lists, t.rules)
+	}
+	node := t
+	// Skip the leading slash, the directories of a path are the segments that
+	// are followed by a slash.
+	rest := path[1:]
+	for {
+		slash := strings.IndexByte(rest, '/')
+		if slash < 0 {
+			break
+		}
+		child, ok := node.children[rest[:slash]]
+		if !ok {
+			break
+		}
+		node = child
+		if len(node.rules) > 0 {
+			lists = append(lists, node.rules)
+		}
+		rest = rest[slash+1:]
+	}
+	return lists
+}
@@ -74,16 +74,44 @@ type path struct {
 
 type compiledRules struct {
 	paths []path
+	// trie indexes paths by their literal prefix, so that we only need to try
+	// the rules that can possibly match a path.
+	trie *ruleTrie
+}
+
+func newCompiledRules(paths []path) compiledRules {
+	return compiledRules{paths: paths, trie: newRuleTrie(paths)}
 }
 
 // GetPermissionsForPath tries to match a given path to a list of rules.
-// Since the last applicable rule is the one that applies, the list is
-// traversed in reverse, and the function returns as soon as a match is found.
-// If no match is found, None is returned.
+// Since the last applicable rule is the one that applies, the candidate rules
+// are traversed from last to first, and the function returns as soon as a
+// match is found. If no match is found, None is returned.
 func (rules compiledRules) GetPermissionsForPath(path string) authz.Perms {
-	for i := len(rules.paths) - 1; i >= 0; i-- {
-		if rules.paths[i].globPath.Match(path) {
-			if rules.paths[i].exclusion {
+	// The candidate lists are each sorted in ascending order, so we walk them
+	// backwards in lockstep, always trying the highest remaining rule index.
+	// Paths are rarely deeper than the buffers, which avoids allocations.
+	var listsBuf [16][]int
+	var cursorsBuf [16]int
+	lists := rules.trie.appendCandidates(listsBuf[:0], path)
+	cursors := cursorsBuf[:0]
+	for _, l := range lists {
+		cursors = append(cursors, len(l)-1)
+	}
+	for {
+		best := -1
+		for i, l := range lists {
+			if cursors[i] >= 0 && (best < 0 || l[cursors[i]] > lists[best][cursors[best]]) {
+				best = i
+			}
+		}
+		if best < 0 {
+			break
+		}
+		rule := rules.paths[lists[best][cursors[best]]]
+		cursors[best]--
+		if rule.globPath.Match(path) {
+			if rule.exclusion {
 				return authz.None
 			}
 			return authz.Read
@@ -334,9 +362,7 @@ func (s *SubRepoPermsClient) getCompiledRules(ctx context.Context, userID int32)
 				}
 			}
 
-			toCache.rules[repo] = compiledRules{
-				paths: paths,
-			}
+			toCache.rules[repo] = newCompiledRules(paths)
 		}
 		toCache.timestamp = s.clock()
 		s.cache.Add(userID, toCache)
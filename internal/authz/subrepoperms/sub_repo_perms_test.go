@@ -252,6 +252,145 @@ func BenchmarkFilterActorPaths(b *testing.B) {
 	b.ReportMetric(float64(len(paths))*float64(b.N)/time.Since(start).Seconds(), "paths/s")
 }
 
+// BenchmarkFilterActorPathsManyRules simulates a Perforce depot with a large
+// protection table, where most lines exclude a single directory.
+func BenchmarkFilterActorPathsManyRules(b *testing.B) {
+	const (
+		pathCount = 5_000
+		ruleCount = 10_000
+	)
+	var paths []string
+	for i := 0; len(paths) < pathCount; i++ {
+		paths = append(paths,
+			fmt.Sprintf("team%d/src/main.go", i%100),
+			fmt.Sprintf("team%d/secret/%d/key.pem", i%100, i),
+			fmt.Sprintf("team%d/docs/%d/README.md", i%100, i),
+		)
+	}
+	paths = paths[:pathCount]
+	sort.Strings(paths)
+
+	rules := []string{"/**"}
+	for i := 0; len(rules) < ruleCount; i++ {
+		rules = append(rules,
+			fmt.Sprintf("-/team%d/secret/%d/**", i%100, i),
+			fmt.Sprintf("/team%d/docs/%d/*.md", i%100, i),
+		)
+	}
+	rules = append(rules, "-/**/*.pem")
+
+	conf.Mock(&conf.Unified{
+		SiteConfiguration: schema.SiteConfiguration{
+			ExperimentalFeatures: &schema.ExperimentalFeatures{
+				SubRepoPermissions: &schema.SubRepoPermissions{
+					Enabled: true,
+				},
+			},
+		},
+	})
+	defer conf.Mock(nil)
+	repo := api.RepoName("repo")
+
+	getter := NewMockSubRepoPermissionsGetter()
+	getter.GetByUserFunc.SetDefaultReturn(map[api.RepoName]authz.SubRepoPermissions{
+		repo: {Paths: rules},
+	}, nil)
+	checker := NewSubRepoPermsClient(getter)
+
+	a := &actor.Actor{
+		UID: 1,
+	}
+	ctx := actor.WithActor(context.Background(), a)
+
+	b.ResetTimer()
+	start := time.Now()
+
+	for n := 0; n <= b.N; n++ {
+		filtered, err := authz.FilterActorPaths(ctx, checker, a, repo, paths)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(filtered) == 0 {
+			b.Fatal("expected paths to be returned")
+		}
+		if len(filtered) == len(paths) {
+			b.Fatal("expected to filter out some paths")
+		}
+	}
+
+	b.ReportMetric(float64(len(paths))*float64(b.N)/time.Since(start).Seconds(), "paths/s")
+}
+
+func TestCompiledRulesTrie(t *testing.T) {
+	conf.Mock(&conf.Unified{
+		SiteConfiguration: schema.SiteConfiguration{
+			ExperimentalFeatures: &schema.ExperimentalFeatures{
+				SubRepoPermissions: &schema.SubRepoPermissions{
+					Enabled: true,
+				},
+			},
+		},
+	})
+	t.Cleanup(func() { conf.Mock(nil) })
+
+	rules := []string{
+		"/depot/**",
+		"-/depot/secret/**",
+		"/depot/secret/public/*.md",
+		"-/depot/*/generated/**",
+		"/**/README.md",
+		"-/depot/main/README.md",
+		"/depot/main/",
+		"-/depot/secret/**",
+		"/depot/secret/public/",
+		"/other/[ab]/file",
+		"-/depot/main/x/**",
+		"/depot/main/x/**",
+	}
+	getter := NewMockSubRepoPermissionsGetter()
+	getter.GetByUserFunc.SetDefaultReturn(map[api.RepoName]authz.SubRepoPermissions{
+		"repo": {Paths: rules},
+	}, nil)
+	client := NewSubRepoPermsClient(getter)
+	compiled, err := client.getCompiledRules(context.Background(), 1)
+	require.NoError(t, err)
+	repoRules := compiled["repo"]
+
+	// linear is the reference implementation, which tries every rule.
+	linear := func(path string) authz.Perms {
+		for i := len(repoRules.paths) - 1; i >= 0; i-- {
+			if repoRules.paths[i].globPath.Match(path) {
+				if repoRules.paths[i].exclusion {
+					return authz.None
+				}
+				return authz.Read
+			}
+		}
+		return authz.None
+	}
+
+	for _, path := range []string{
+		"/",
+		"/README.md",
+		"/depot/",
+		"/depot/file.go",
+		"/depot/main/",
+		"/depot/main/README.md",
+		"/depot/main/x/y.go",
+		"/depot/main/generated/y.go",
+		"/depot/secret/",
+		"/depot/secret/key.pem",
+		"/depot/secret/public/",
+		"/depot/secret/public/doc.md",
+		"/depot/secret/public/README.md",
+		"/depot/secret/public/nested/doc.md",
+		"/other/a/file",
+		"/other/c/file",
+	} {
+		require.Equal(t, linear(path), repoRules.GetPermissionsForPath(path), path)
+	}
+}
+
 func TestSubRepoPermissionsCanReadDirectoriesInPath(t *testing.T) {
 	conf.Mock(&conf.Unified{
 		SiteConfiguration: schema.SiteConfiguration{
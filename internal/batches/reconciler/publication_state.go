@@ -5,6 +5,14 @@ import (
 	"github.com/sourcegraph/sourcegraph/lib/batches"
 )
 
+// WouldPublish returns whether the reconciler publishes the changeset, either
+// because of the published field of its spec or because of its UI publication
+// state. Drafts are only published if the code host supports them.
+func WouldPublish(spec *btypes.ChangesetSpec, c *btypes.Changeset) bool {
+	calc := calculatePublicationState(spec.Published, c.UiPublicationState)
+	return calc.IsPublished() || (calc.IsDraft() && c.SupportsDraft())
+}
+
 // publicationStateCalculator calculates the desired publication state based on
 // the published field of a changeset spec and the UI publication state of the
 // changeset, if any.
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	btypes "github.com/sourcegraph/sourcegraph/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/lib/batches"
 	"github.com/sourcegraph/sourcegraph/lib/pointers"
 )
@@ -71,3 +72,34 @@ func TestPublicationStateCalculator(t *testing.T) {
 		})
 	}
 }
+
+func TestWouldPublish(t *testing.T) {
+	published := batches.PublishedValue{Val: true}
+	draft := batches.PublishedValue{Val: "draft"}
+	uiPublished := btypes.ChangesetUiPublicationStatePublished
+	uiDraft := btypes.ChangesetUiPublicationStateDraft
+
+	for name, tc := range map[string]struct {
+		published batches.PublishedValue
+		ui        *btypes.ChangesetUiPublicationState
+		extsvc    string
+		want      bool
+	}{
+		"published in spec":                   {published: published, extsvc: extsvc.TypeGitHub, want: true},
+		"not published in spec":               {published: batches.PublishedValue{Val: false}, extsvc: extsvc.TypeGitHub},
+		"draft in spec":                       {published: draft, extsvc: extsvc.TypeGitHub, want: true},
+		"draft in spec without draft support": {published: draft, extsvc: extsvc.TypeBitbucketServer},
+		"published in UI":                     {ui: &uiPublished, extsvc: extsvc.TypeGitHub, want: true},
+		"draft in UI":                         {ui: &uiDraft, extsvc: extsvc.TypeGitHub, want: true},
+		"draft in UI without draft support":   {ui: &uiDraft, extsvc: extsvc.TypeBitbucketServer},
+		"neither in spec nor in UI":           {extsvc: extsvc.TypeGitHub},
+	} {
+		t.Run(name, func(t *testing.T) {
+			spec := &btypes.ChangesetSpec{Published: tc.published}
+			c := &btypes.Changeset{UiPublicationState: tc.ui, ExternalServiceType: tc.extsvc}
+			if have := WouldPublish(spec, c); have != tc.want {
+				t.Errorf("want %t, have %t", tc.want, have)
+			}
+		})
+	}
+}
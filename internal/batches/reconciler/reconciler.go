@@ -11,6 +11,7 @@ import (
 	btypes "github.com/sourcegraph/sourcegraph/internal/batches/types"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/metrics"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
 	"github.com/sourcegraph/sourcegraph/internal/workerutil"
 )
 
@@ -22,6 +23,10 @@ type Reconciler struct {
 	sourcer sources.Sourcer
 	store   *store.Store
 
+	// rollouts caches the rollout waves of batch changes across the
+	// changesets we reconcile.
+	rollouts rolloutWavesCache
+
 	// This is used to disable a time.Sleep for operationSleep so that the
 	// tests don't run slower.
 	noSleepBeforeSync bool
@@ -45,6 +50,9 @@ func (r *Reconciler) HandlerFunc() workerutil.HandlerFunc[*btypes.Changeset] {
 		}
 
 		ctx = metrics.ContextWithTask(ctx, "Batches.Reconciler")
+		// Publishing and updating changesets happens because users applied a batch
+		// change, so it gets code host rate limit budget before background work.
+		ctx = ratelimit.WithConsumer(ratelimit.WithPriority(ctx, ratelimit.PriorityInteractive), "changeset-reconciler")
 		afterDone, err := r.process(ctx, logger, tx, job)
 
 		defer func() {
@@ -97,6 +105,14 @@ func (r *Reconciler) process(ctx context.Context, logger log.Logger, tx *store.S
 		return nil, err
 	}
 
+	held, err := applyRolloutGate(ctx, tx, &r.rollouts, plan)
+	if err != nil {
+		return nil, err
+	}
+	if held {
+		logger.Info("Reconciler holding back changeset until its rollout wave is open", log.Int64("changeset", ch.ID))
+	}
+
 	logger.Info("Reconciler processing changeset", log.Int64("changeset", ch.ID), log.String("operations", fmt.Sprintf("%+v", plan.Ops)))
 
 	return executePlan(
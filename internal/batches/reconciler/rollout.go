@@ -0,0 +1,272 @@
+package reconciler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
+)
+
+// RolloutWave summarizes the state of the changesets in a single wave of a
+// staged rollout.
+type RolloutWave struct {
+	// Index is the zero-based position of the wave in the rollout.
+	Index int
+	// Total is the number of changesets in the wave.
+	Total int
+	// Published is the number of published changesets in the wave.
+	Published int
+	// Succeeded is the number of changesets in the wave that have been merged
+	// or have passing checks.
+	Succeeded int
+	// Open is true when the changesets in the wave are allowed to be
+	// published.
+	Open bool
+	// Complete is true when enough changesets of the wave succeeded for the
+	// next wave to be opened.
+	Complete bool
+}
+
+// RolloutWaves contains the computed waves of a staged rollout.
+type RolloutWaves struct {
+	Waves []RolloutWave
+
+	// changesetWaves maps changeset IDs to the index of their wave.
+	changesetWaves map[int64]int
+}
+
+// contains returns whether the changeset was assigned to a wave when the
+// waves were computed.
+func (w *RolloutWaves) contains(changesetID int64) bool {
+	_, ok := w.changesetWaves[changesetID]
+	return ok
+}
+
+// IsOpen returns whether the wave of the given changeset is allowed to be
+// published. Changesets that aren't part of the rollout are never held back.
+func (w *RolloutWaves) IsOpen(changesetID int64) bool {
+	idx, ok := w.changesetWaves[changesetID]
+	if !ok {
+		return true
+	}
+	return w.Waves[idx].Open
+}
+
+// ComputeRolloutWaves assigns the given changesets to the waves of the
+// rollout and determines which waves may be published.
+//
+// The first wave is always open. Every following wave opens once the fraction
+// of changesets in the previous wave that are merged or have passing checks
+// reaches the threshold of the rollout. Empty waves don't hold back the waves
+// after them.
+func ComputeRolloutWaves(rollout *batcheslib.Rollout, cs btypes.Changesets, repos map[api.RepoID]*types.Repo) (*RolloutWaves, error) {
+	if err := rollout.Compile(); err != nil {
+		return nil, err
+	}
+
+	res := &RolloutWaves{
+		Waves:          make([]RolloutWave, rollout.NumWaves()),
+		changesetWaves: make(map[int64]int, len(cs)),
+	}
+	for i := range res.Waves {
+		res.Waves[i].Index = i
+	}
+
+	for _, c := range cs {
+		repo, ok := repos[c.RepoID]
+		if !ok {
+			continue
+		}
+		idx, err := rollout.WaveForRepo(string(repo.Name))
+		if err != nil {
+			return nil, err
+		}
+		res.changesetWaves[c.ID] = idx
+
+		wave := &res.Waves[idx]
+		wave.Total++
+		if c.Published() {
+			wave.Published++
+		}
+		if c.ExternalState == btypes.ChangesetExternalStateMerged || c.ExternalCheckState == btypes.ChangesetCheckStatePassed {
+			wave.Succeeded++
+		}
+	}
+
+	open := true
+	for i := range res.Waves {
+		wave := &res.Waves[i]
+		wave.Open = open
+		wave.Complete = wave.Total == 0 || float64(wave.Succeeded)/float64(wave.Total) >= rollout.RequiredFraction()
+		open = open && wave.Complete
+	}
+
+	return res, nil
+}
+
+// LoadRolloutWaves computes the rollout waves of the given batch change. If
+// the batch spec of the batch change doesn't define a rollout, nil is
+// returned.
+func LoadRolloutWaves(ctx context.Context, tx *store.Store, batchChangeID int64) (*RolloutWaves, error) {
+	waves, _, err := loadRolloutWaves(ctx, tx, batchChangeID)
+	return waves, err
+}
+
+// loadRolloutWaves is LoadRolloutWaves, but also returns the ID of the batch
+// spec the waves were computed for.
+func loadRolloutWaves(ctx context.Context, tx *store.Store, batchChangeID int64) (_ *RolloutWaves, batchSpecID int64, err error) {
+	batchChange, err := tx.GetBatchChange(ctx, store.GetBatchChangeOpts{ID: batchChangeID})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	batchSpec, err := tx.GetBatchSpec(ctx, store.GetBatchSpecOpts{ID: batchChange.BatchSpecID})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if batchSpec.Spec == nil || batchSpec.Spec.Rollout == nil {
+		return nil, batchSpec.ID, nil
+	}
+
+	cs, _, err := tx.ListChangesets(ctx, store.ListChangesetsOpts{OwnedByBatchChangeID: batchChangeID})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	repoIDs := make([]api.RepoID, 0, len(cs))
+	for _, c := range cs {
+		repoIDs = append(repoIDs, c.RepoID)
+	}
+	repos, err := tx.Repos().GetReposSetByIDs(ctx, repoIDs...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	waves, err := ComputeRolloutWaves(batchSpec.Spec.Rollout, cs, repos)
+	return waves, batchSpec.ID, err
+}
+
+// rolloutWavesCacheTTL is how long the rollout waves of a batch change are
+// reused by the reconciler. A wave that opened in the meantime is picked up
+// by the rollout advancer, which enqueues its changesets again.
+const rolloutWavesCacheTTL = 30 * time.Second
+
+// rolloutWavesCache caches the rollout waves of batch changes, so that
+// reconciling the changesets of a batch change doesn't load all of its
+// changesets and repositories again for every single one of them. The zero
+// value is ready to use.
+type rolloutWavesCache struct {
+	mu      sync.Mutex
+	entries map[int64]rolloutWavesCacheEntry
+}
+
+type rolloutWavesCacheEntry struct {
+	waves       *RolloutWaves
+	batchSpecID int64
+	expiresAt   time.Time
+}
+
+type rolloutWavesLoader func(batchChangeID int64) (*RolloutWaves, int64, error)
+
+// get returns the rollout waves of the given batch change. Cached waves are
+// only used if they were computed for the given batch spec and, if there is
+// a rollout, already contain the given changeset. The waves are loaded without
+// holding the lock, so that the reconciler workers don't wait for each other's
+// database reads.
+func (c *rolloutWavesCache) get(batchChangeID, batchSpecID, changesetID int64, load rolloutWavesLoader) (*RolloutWaves, error) {
+	if waves, ok := c.cached(batchChangeID, batchSpecID, changesetID); ok {
+		return waves, nil
+	}
+
+	waves, loadedBatchSpecID, err := load(batchChangeID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if c.entries == nil {
+		c.entries = make(map[int64]rolloutWavesCacheEntry)
+	}
+	for id, e := range c.entries {
+		if !now.Before(e.expiresAt) {
+			delete(c.entries, id)
+		}
+	}
+	c.entries[batchChangeID] = rolloutWavesCacheEntry{
+		waves:       waves,
+		batchSpecID: loadedBatchSpecID,
+		expiresAt:   now.Add(rolloutWavesCacheTTL),
+	}
+
+	return waves, nil
+}
+
+func (c *rolloutWavesCache) cached(batchChangeID, batchSpecID, changesetID int64) (*RolloutWaves, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[batchChangeID]
+	if !ok || !time.Now().Before(e.expiresAt) || e.batchSpecID != batchSpecID {
+		return nil, false
+	}
+	if e.waves != nil && !e.waves.contains(changesetID) {
+		return nil, false
+	}
+	return e.waves, true
+}
+
+// applyRolloutGate removes the publishing operations from the plan if the
+// changeset belongs to a rollout wave that isn't open yet. The changeset is
+// enqueued again by the rollout advancer once its wave opens.
+func applyRolloutGate(ctx context.Context, tx *store.Store, cache *rolloutWavesCache, pl *Plan) (held bool, err error) {
+	ch := pl.Changeset
+	if ch.OwnedByBatchChangeID == 0 {
+		return false, nil
+	}
+	if !pl.Ops.Contains(btypes.ReconcilerOperationPublish) && !pl.Ops.Contains(btypes.ReconcilerOperationPublishDraft) {
+		return false, nil
+	}
+
+	var batchSpecID int64
+	if pl.ChangesetSpec != nil {
+		batchSpecID = pl.ChangesetSpec.BatchSpecID
+	}
+	waves, err := cache.get(ch.OwnedByBatchChangeID, batchSpecID, ch.ID, func(batchChangeID int64) (*RolloutWaves, int64, error) {
+		return loadRolloutWaves(ctx, tx, batchChangeID)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return gateRolloutPlan(pl, waves), nil
+}
+
+// gateRolloutPlan removes the publishing operations from the plan if the
+// changeset belongs to a rollout wave that isn't open. waves is nil if the
+// batch change has no rollout.
+func gateRolloutPlan(pl *Plan, waves *RolloutWaves) (held bool) {
+	if waves == nil || waves.IsOpen(pl.Changeset.ID) {
+		return false
+	}
+
+	ops := Operations{}
+	for _, op := range pl.Ops {
+		switch op {
+		case btypes.ReconcilerOperationPublish, btypes.ReconcilerOperationPublishDraft, btypes.ReconcilerOperationPush:
+			continue
+		}
+		ops = append(ops, op)
+	}
+	pl.Ops = ops
+
+	return true
+}
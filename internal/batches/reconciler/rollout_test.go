@@ -0,0 +1,217 @@
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	btypes "github.com/sourcegraph/sourcegraph/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/batches"
+)
+
+func TestComputeRolloutWaves(t *testing.T) {
+	repos := map[api.RepoID]*types.Repo{
+		1: {ID: 1, Name: "github.com/sourcegraph/canary-1"},
+		2: {ID: 2, Name: "github.com/sourcegraph/canary-2"},
+		3: {ID: 3, Name: "github.com/sourcegraph/service"},
+		4: {ID: 4, Name: "gitlab.com/sourcegraph/other"},
+	}
+	rollout := &batches.Rollout{
+		Waves: []batches.RolloutWave{
+			{Repositories: []string{"github.com/sourcegraph/canary-*"}},
+			{Repositories: []string{"github.com/*"}},
+		},
+		Threshold: 0.5,
+	}
+
+	unpublished := func(id int64, repo api.RepoID) *btypes.Changeset {
+		return &btypes.Changeset{ID: id, RepoID: repo, PublicationState: btypes.ChangesetPublicationStateUnpublished}
+	}
+	published := func(id int64, repo api.RepoID, state btypes.ChangesetExternalState, checks btypes.ChangesetCheckState) *btypes.Changeset {
+		return &btypes.Changeset{
+			ID:                 id,
+			RepoID:             repo,
+			PublicationState:   btypes.ChangesetPublicationStatePublished,
+			ExternalState:      state,
+			ExternalCheckState: checks,
+		}
+	}
+
+	for name, tc := range map[string]struct {
+		changesets btypes.Changesets
+		want       []RolloutWave
+		wantOpen   map[int64]bool
+	}{
+		"nothing published": {
+			changesets: btypes.Changesets{unpublished(1, 1), unpublished(2, 2), unpublished(3, 3), unpublished(4, 4)},
+			want: []RolloutWave{
+				{Index: 0, Total: 2, Open: true},
+				{Index: 1, Total: 1},
+				{Index: 2, Total: 1},
+			},
+			wantOpen: map[int64]bool{1: true, 2: true, 3: false, 4: false},
+		},
+		"first wave above threshold": {
+			changesets: btypes.Changesets{
+				published(1, 1, btypes.ChangesetExternalStateMerged, btypes.ChangesetCheckStateUnknown),
+				published(2, 2, btypes.ChangesetExternalStateOpen, btypes.ChangesetCheckStateFailed),
+				unpublished(3, 3),
+				unpublished(4, 4),
+			},
+			want: []RolloutWave{
+				{Index: 0, Total: 2, Published: 2, Succeeded: 1, Open: true, Complete: true},
+				{Index: 1, Total: 1, Open: true},
+				{Index: 2, Total: 1},
+			},
+			wantOpen: map[int64]bool{1: true, 2: true, 3: true, 4: false},
+		},
+		"passing checks count as success": {
+			changesets: btypes.Changesets{
+				published(1, 1, btypes.ChangesetExternalStateOpen, btypes.ChangesetCheckStatePassed),
+				published(2, 2, btypes.ChangesetExternalStateOpen, btypes.ChangesetCheckStatePassed),
+				published(3, 3, btypes.ChangesetExternalStateOpen, btypes.ChangesetCheckStatePassed),
+				unpublished(4, 4),
+			},
+			want: []RolloutWave{
+				{Index: 0, Total: 2, Published: 2, Succeeded: 2, Open: true, Complete: true},
+				{Index: 1, Total: 1, Published: 1, Succeeded: 1, Open: true, Complete: true},
+				{Index: 2, Total: 1, Open: true},
+			},
+			wantOpen: map[int64]bool{1: true, 2: true, 3: true, 4: true},
+		},
+		"empty waves don't hold back later waves": {
+			changesets: btypes.Changesets{unpublished(3, 3), unpublished(4, 4)},
+			want: []RolloutWave{
+				{Index: 0, Open: true, Complete: true},
+				{Index: 1, Total: 1, Open: true},
+				{Index: 2, Total: 1},
+			},
+			wantOpen: map[int64]bool{3: true, 4: false, 5: true},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			have, err := ComputeRolloutWaves(rollout, tc.changesets, repos)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, have.Waves); diff != "" {
+				t.Errorf("wrong waves (-want +have):\n%s", diff)
+			}
+			for id, want := range tc.wantOpen {
+				if have := have.IsOpen(id); have != want {
+					t.Errorf("wrong open state for changeset %d: want=%t have=%t", id, want, have)
+				}
+			}
+		})
+	}
+}
+
+func TestGateRolloutPlan(t *testing.T) {
+	repos := map[api.RepoID]*types.Repo{
+		1: {ID: 1, Name: "github.com/sourcegraph/canary"},
+		2: {ID: 2, Name: "github.com/sourcegraph/service"},
+	}
+	rollout := &batches.Rollout{
+		Waves: []batches.RolloutWave{{Repositories: []string{"github.com/sourcegraph/canary"}}},
+	}
+	cs := btypes.Changesets{
+		{ID: 1, RepoID: 1, PublicationState: btypes.ChangesetPublicationStateUnpublished},
+		{ID: 2, RepoID: 2, PublicationState: btypes.ChangesetPublicationStateUnpublished},
+	}
+	waves, err := ComputeRolloutWaves(rollout, cs, repos)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publish := Operations{
+		btypes.ReconcilerOperationPush,
+		btypes.ReconcilerOperationPublish,
+		btypes.ReconcilerOperationArchive,
+	}
+
+	for name, tc := range map[string]struct {
+		changeset *btypes.Changeset
+		waves     *RolloutWaves
+		wantHeld  bool
+		wantOps   Operations
+	}{
+		"open wave": {
+			changeset: cs[0],
+			waves:     waves,
+			wantOps:   publish,
+		},
+		"closed wave": {
+			changeset: cs[1],
+			waves:     waves,
+			wantHeld:  true,
+			wantOps:   Operations{btypes.ReconcilerOperationArchive},
+		},
+		"no rollout": {
+			changeset: cs[1],
+			wantOps:   publish,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			pl := &Plan{Changeset: tc.changeset, Ops: append(Operations{}, publish...)}
+			if have := gateRolloutPlan(pl, tc.waves); have != tc.wantHeld {
+				t.Errorf("wrong held: want=%t have=%t", tc.wantHeld, have)
+			}
+			if diff := cmp.Diff(tc.wantOps, pl.Ops); diff != "" {
+				t.Errorf("wrong operations (-want +have):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRolloutWavesCache(t *testing.T) {
+	var (
+		cache rolloutWavesCache
+		loads int
+	)
+	waves := &RolloutWaves{changesetWaves: map[int64]int{1: 0}, Waves: []RolloutWave{{Open: true}}}
+	load := func(batchSpecID int64) rolloutWavesLoader {
+		return func(int64) (*RolloutWaves, int64, error) {
+			if !cache.mu.TryLock() {
+				t.Fatal("waves loaded while holding the cache lock")
+			}
+			cache.mu.Unlock()
+			loads++
+			return waves, batchSpecID, nil
+		}
+	}
+
+	get := func(batchSpecID, changesetID int64) {
+		t.Helper()
+		have, err := cache.get(10, batchSpecID, changesetID, load(batchSpecID))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have != waves {
+			t.Fatalf("unexpected waves %+v", have)
+		}
+	}
+
+	get(100, 1)
+	get(100, 1)
+	if loads != 1 {
+		t.Fatalf("waves of known changeset not cached: %d loads", loads)
+	}
+
+	get(100, 2)
+	if loads != 2 {
+		t.Fatalf("waves not reloaded for unknown changeset: %d loads", loads)
+	}
+
+	get(101, 1)
+	if loads != 3 {
+		t.Fatalf("waves not reloaded for new batch spec: %d loads", loads)
+	}
+
+	cache.entries[10] = rolloutWavesCacheEntry{waves: waves, batchSpecID: 101}
+	get(101, 1)
+	if loads != 4 {
+		t.Fatalf("expired waves not reloaded: %d loads", loads)
+	}
+}
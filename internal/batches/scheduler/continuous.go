@@ -0,0 +1,51 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/batches/service"
+	"github.com/sourcegraph/sourcegraph/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const continuousRunInterval = 5 * time.Minute
+
+// NewContinuousRunner creates a new goroutine.PeriodicGoroutine that keeps
+// batch changes in continuous mode up to date by periodically re-running
+// their batch specs.
+func NewContinuousRunner(ctx context.Context, s *store.Store) goroutine.BackgroundRoutine {
+	svc := service.New(s)
+	return goroutine.NewPeriodicGoroutine(
+		ctx,
+		goroutine.HandlerFunc(func(ctx context.Context) error {
+			return runContinuousBatchChanges(ctx, s, svc)
+		}),
+		goroutine.WithName("batchchanges.continuous-runner"),
+		goroutine.WithDescription("re-runs the batch specs of batch changes in continuous mode"),
+		goroutine.WithInterval(continuousRunInterval),
+	)
+}
+
+func runContinuousBatchChanges(ctx context.Context, s *store.Store, svc *service.Service) (errs error) {
+	opts := store.ListBatchChangesOpts{States: []btypes.BatchChangeState{btypes.BatchChangeStateOpen}}
+	for {
+		batchChanges, next, err := s.ListBatchChanges(ctx, opts)
+		if err != nil {
+			return errors.Append(errs, err)
+		}
+
+		for _, batchChange := range batchChanges {
+			if err := svc.RunContinuousBatchChange(ctx, batchChange.ID); err != nil {
+				errs = errors.Append(errs, errors.Wrapf(err, "running continuous batch change %d", batchChange.ID))
+			}
+		}
+
+		if next == 0 {
+			return errs
+		}
+		opts.Cursor = next
+	}
+}
@@ -0,0 +1,109 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/batches/global"
+	"github.com/sourcegraph/sourcegraph/internal/batches/reconciler"
+	"github.com/sourcegraph/sourcegraph/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+)
+
+const rolloutAdvanceInterval = 1 * time.Minute
+
+// NewRolloutAdvancer creates a new goroutine.PeriodicGoroutine that enqueues
+// the changesets of batch changes with a staged rollout once their rollout
+// wave opens. The reconciler holds back the publication of changesets in
+// waves that aren't open yet, so without this they'd never be published.
+func NewRolloutAdvancer(ctx context.Context, s *store.Store) goroutine.BackgroundRoutine {
+	return goroutine.NewPeriodicGoroutine(
+		ctx,
+		goroutine.HandlerFunc(func(ctx context.Context) error {
+			return advanceRollouts(ctx, s)
+		}),
+		goroutine.WithName("batchchanges.rollout-advancer"),
+		goroutine.WithDescription("enqueues changesets of staged rollouts once their wave opens"),
+		goroutine.WithInterval(rolloutAdvanceInterval),
+	)
+}
+
+func advanceRollouts(ctx context.Context, s *store.Store) (errs error) {
+	opts := store.ListBatchChangesOpts{States: []btypes.BatchChangeState{btypes.BatchChangeStateOpen}}
+	for {
+		batchChanges, next, err := s.ListBatchChanges(ctx, opts)
+		if err != nil {
+			return errors.Append(errs, err)
+		}
+
+		for _, batchChange := range batchChanges {
+			if err := advanceRollout(ctx, s, batchChange.ID); err != nil {
+				errs = errors.Append(errs, errors.Wrapf(err, "advancing rollout of batch change %d", batchChange.ID))
+			}
+		}
+
+		if next == 0 {
+			return errs
+		}
+		opts.Cursor = next
+	}
+}
+
+func advanceRollout(ctx context.Context, s *store.Store, batchChangeID int64) error {
+	waves, err := reconciler.LoadRolloutWaves(ctx, s, batchChangeID)
+	if err != nil || waves == nil {
+		return err
+	}
+
+	cs, _, err := s.ListChangesets(ctx, store.ListChangesetsOpts{
+		OwnedByBatchChangeID: batchChangeID,
+		PublicationState:     pointers.Ptr(btypes.ChangesetPublicationStateUnpublished),
+		ReconcilerStates:     []btypes.ReconcilerState{btypes.ReconcilerStateCompleted},
+	})
+	if err != nil {
+		return err
+	}
+
+	specIDs := make([]int64, 0, len(cs))
+	for _, c := range cs {
+		if c.CurrentSpecID != 0 && waves.IsOpen(c.ID) {
+			specIDs = append(specIDs, c.CurrentSpecID)
+		}
+	}
+	if len(specIDs) == 0 {
+		return nil
+	}
+
+	specs, _, err := s.ListChangesetSpecs(ctx, store.ListChangesetSpecsOpts{IDs: specIDs})
+	if err != nil {
+		return err
+	}
+	specsByID := make(map[int64]*btypes.ChangesetSpec, len(specs))
+	for _, spec := range specs {
+		specsByID[spec.ID] = spec
+	}
+
+	for _, c := range changesetsToAdvance(waves, cs, specsByID) {
+		if err := s.EnqueueChangeset(ctx, c, global.DefaultReconcilerEnqueueState(), btypes.ReconcilerStateCompleted); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// changesetsToAdvance returns the changesets whose rollout wave is open and
+// that the reconciler would publish once enqueued.
+func changesetsToAdvance(waves *reconciler.RolloutWaves, cs btypes.Changesets, specsByID map[int64]*btypes.ChangesetSpec) (advance btypes.Changesets) {
+	for _, c := range cs {
+		spec, ok := specsByID[c.CurrentSpecID]
+		if !ok || !waves.IsOpen(c.ID) || !reconciler.WouldPublish(spec, c) {
+			continue
+		}
+		advance = append(advance, c)
+	}
+	return advance
+}
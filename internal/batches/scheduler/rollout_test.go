@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/batches/reconciler"
+	btypes "github.com/sourcegraph/sourcegraph/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
+)
+
+func TestChangesetsToAdvance(t *testing.T) {
+	repos := map[api.RepoID]*types.Repo{
+		1: {ID: 1, Name: "github.com/sourcegraph/canary"},
+		2: {ID: 2, Name: "github.com/sourcegraph/service"},
+	}
+	rollout := &batcheslib.Rollout{
+		Waves: []batcheslib.RolloutWave{{Repositories: []string{"github.com/sourcegraph/canary"}}},
+	}
+
+	unpublished := func(id int64, repo api.RepoID, specID int64) *btypes.Changeset {
+		return &btypes.Changeset{
+			ID:                  id,
+			RepoID:              repo,
+			CurrentSpecID:       specID,
+			PublicationState:    btypes.ChangesetPublicationStateUnpublished,
+			ExternalServiceType: extsvc.TypeGitHub,
+		}
+	}
+	spec := func(id int64, published any) *btypes.ChangesetSpec {
+		return &btypes.ChangesetSpec{ID: id, Published: batcheslib.PublishedValue{Val: published}}
+	}
+
+	specs := map[int64]*btypes.ChangesetSpec{
+		10: spec(10, true),
+		11: spec(11, false),
+		12: spec(12, "draft"),
+		20: spec(20, true),
+	}
+
+	for name, tc := range map[string]struct {
+		changesets btypes.Changesets
+		want       []int64
+	}{
+		"only open waves are advanced": {
+			changesets: btypes.Changesets{unpublished(1, 1, 10), unpublished(2, 2, 20)},
+			want:       []int64{1},
+		},
+		"changesets that stay unpublished are skipped": {
+			changesets: btypes.Changesets{unpublished(1, 1, 11), unpublished(3, 1, 12)},
+			want:       []int64{3},
+		},
+		"changesets without a known spec are skipped": {
+			changesets: btypes.Changesets{unpublished(1, 1, 99)},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			waves, err := reconciler.ComputeRolloutWaves(rollout, tc.changesets, repos)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var have []int64
+			for _, c := range changesetsToAdvance(waves, tc.changesets, specs) {
+				have = append(have, c.ID)
+			}
+			if diff := cmp.Diff(tc.want, have); diff != "" {
+				t.Errorf("wrong changesets (-want +have):\n%s", diff)
+			}
+		})
+	}
+}
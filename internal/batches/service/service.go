@@ -95,6 +95,7 @@ type operations struct {
 	applyBatchChange                     *observation.Operation
 	reconcileBatchChange                 *observation.Operation
 	validateChangesetSpecs               *observation.Operation
+	runContinuousBatchChange             *observation.Operation
 }
 
 var (
@@ -145,6 +146,7 @@ func newOperations(observationCtx *observation.Context) *operations {
 			applyBatchChange:                     op("ApplyBatchChange"),
 			reconcileBatchChange:                 op("ReconcileBatchChange"),
 			validateChangesetSpecs:               op("ValidateChangesetSpecs"),
+			runContinuousBatchChange:             op("RunContinuousBatchChange"),
 		}
 	})
 
@@ -471,6 +473,9 @@ type CreateBatchSpecFromRawOpts struct {
 	NoCache          bool
 
 	BatchChange int64
+	// ContinuousRevision marks the batch spec as a revision created by the
+	// continuous mode of the batch change.
+	ContinuousRevision bool
 }
 
 // CreateBatchSpecFromRaw creates the BatchSpec.
@@ -499,6 +504,7 @@ func (s *Service) CreateBatchSpecFromRaw(ctx context.Context, opts CreateBatchSp
 	spec.UserID = a.UID
 
 	spec.BatchChangeID = opts.BatchChange
+	spec.ContinuousRevision = opts.ContinuousRevision
 
 	tx, err := s.store.Transact(ctx)
 	if err != nil {
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	sgactor "github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/batches/store"
+	btypes "github.com/sourcegraph/sourcegraph/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+// RunContinuousBatchChange advances a batch change whose applied batch spec
+// enables continuous mode by at most one step:
+//
+//  1. Once the interval of the batch spec passed, a new revision of the
+//     applied batch spec is created, which resolves the workspaces again.
+//  2. Once the workspaces are resolved, the revision is executed if repositories
+//     started or stopped matching or if a base branch moved. Unchanged
+//     workspaces are served from the execution cache. If nothing changed, the
+//     revision is left unexecuted and cleaned up by the next run.
+//  3. Once the execution completed, the revision is applied if the batch spec
+//     enables autoApply. Otherwise it waits for the owner to preview and apply
+//     it.
+//
+// All steps are run on behalf of the user who last applied the batch change,
+// so that the same permissions apply as if they had re-run it themselves.
+func (s *Service) RunContinuousBatchChange(ctx context.Context, batchChangeID int64) (err error) {
+	ctx, _, endObservation := s.operations.runContinuousBatchChange.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int64("batchChangeID", batchChangeID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	batchChange, err := s.store.GetBatchChange(ctx, store.GetBatchChangeOpts{ID: batchChangeID})
+	if err != nil {
+		return err
+	}
+	if batchChange.Closed() || batchChange.LastApplierID == 0 {
+		return nil
+	}
+
+	applied, err := s.store.GetBatchSpec(ctx, store.GetBatchSpecOpts{ID: batchChange.BatchSpecID})
+	if err != nil {
+		return err
+	}
+	// Batch specs that were executed with src-cli can't be re-run on the
+	// server.
+	if applied.Spec.Continuous == nil || !applied.CreatedFromRaw {
+		return nil
+	}
+
+	ctx = sgactor.WithActor(ctx, sgactor.FromUser(batchChange.LastApplierID))
+
+	// Only revisions created by a previous run are advanced. Users can create
+	// batch specs for the batch change in the editor too, which are theirs to
+	// execute and apply.
+	specs, _, err := s.store.ListBatchSpecs(ctx, store.ListBatchSpecsOpts{
+		LimitOpts:               store.LimitOpts{Limit: 1},
+		BatchChangeID:           batchChange.ID,
+		NewestFirst:             true,
+		OnlyContinuousRevisions: true,
+	})
+	if err != nil {
+		return err
+	}
+	latest := applied
+	if len(specs) > 0 && specs[0].ID > applied.ID {
+		latest = specs[0]
+	}
+
+	if latest.ID != applied.ID {
+		settled, err := s.advanceContinuousRevision(ctx, batchChange, applied, latest)
+		if err != nil || !settled {
+			return err
+		}
+	}
+
+	if s.clock().Sub(latest.CreatedAt) < applied.Spec.Continuous.IntervalDuration() {
+		return nil
+	}
+
+	if latest.ID != applied.ID {
+		if err := s.pruneContinuousRevision(ctx, applied, latest); err != nil {
+			return err
+		}
+	}
+
+	_, err = s.CreateBatchSpecFromRaw(ctx, CreateBatchSpecFromRawOpts{
+		RawSpec:            applied.RawSpec,
+		NamespaceUserID:    batchChange.NamespaceUserID,
+		NamespaceOrgID:     batchChange.NamespaceOrgID,
+		AllowIgnored:       applied.AllowIgnored,
+		AllowUnsupported:   applied.AllowUnsupported,
+		BatchChange:        batchChange.ID,
+		ContinuousRevision: true,
+	})
+	return err
+}
+
+// advanceContinuousRevision moves the given revision of the applied batch
+// spec on to its next step. It returns true if the revision has settled, i.e.
+// if there is nothing left to do for it and it can be superseded by a new
+// revision once the interval passed.
+func (s *Service) advanceContinuousRevision(ctx context.Context, batchChange *btypes.BatchChange, applied, revision *btypes.BatchSpec) (settled bool, err error) {
+	resolutionJob, err := s.store.GetBatchSpecResolutionJob(ctx, store.GetBatchSpecResolutionJobOpts{BatchSpecID: revision.ID})
+	if err != nil {
+		return false, err
+	}
+	switch resolutionJob.State {
+	case btypes.BatchSpecResolutionJobStateCompleted:
+	case btypes.BatchSpecResolutionJobStateFailed:
+		return true, nil
+	default:
+		// Still resolving the workspaces.
+		return false, nil
+	}
+
+	stats, err := loadBatchSpecStats(ctx, s.store, revision)
+	if err != nil {
+		return false, err
+	}
+
+	state := btypes.ComputeBatchSpecState(revision, stats)
+	switch {
+	case state == btypes.BatchSpecStatePending:
+		changed, err := s.continuousWorkspacesChanged(ctx, applied, revision)
+		if err != nil || !changed {
+			return !changed, err
+		}
+		_, err = s.ExecuteBatchSpec(ctx, ExecuteBatchSpecOpts{BatchSpecRandID: revision.RandID})
+		return false, err
+
+	case state == btypes.BatchSpecStateCompleted && applied.Spec.Continuous.AutoApply:
+		// Revisions whose workspaces were all skipped complete without being
+		// executed. Only apply them if they change something.
+		if stats.Executions == 0 {
+			changed, err := s.continuousWorkspacesChanged(ctx, applied, revision)
+			if err != nil || !changed {
+				return !changed, err
+			}
+		}
+		_, err = s.ApplyBatchChange(ctx, ApplyBatchChangeOpts{
+			BatchSpecRandID:     revision.RandID,
+			EnsureBatchChangeID: batchChange.ID,
+		})
+		return false, err
+
+	default:
+		// Either still executing, or waiting for the owner to apply the
+		// revision.
+		return state.Finished(), nil
+	}
+}
+
+// pruneContinuousRevision deletes a revision of the applied batch spec that
+// is about to be superseded if it didn't change anything: it was never
+// executed and its workspaces are the same as those of the applied batch
+// spec. Revisions whose workspaces were all served from the cache, or that
+// dropped a repository, still change something and are kept for the owner to
+// apply them.
+func (s *Service) pruneContinuousRevision(ctx context.Context, applied, revision *btypes.BatchSpec) error {
+	stats, err := loadBatchSpecStats(ctx, s.store, revision)
+	if err != nil {
+		return err
+	}
+	if !stats.ResolutionDone || stats.Executions != 0 {
+		return nil
+	}
+
+	changed, err := s.continuousWorkspacesChanged(ctx, applied, revision)
+	if err != nil || changed {
+		return err
+	}
+	return s.store.DeleteBatchSpec(ctx, revision.ID)
+}
+
+// continuousWorkspacesChanged returns whether the resolved workspaces of the
+// revision differ from those of the applied batch spec.
+func (s *Service) continuousWorkspacesChanged(ctx context.Context, applied, revision *btypes.BatchSpec) (bool, error) {
+	prev, _, err := s.store.ListBatchSpecWorkspaces(ctx, store.ListBatchSpecWorkspacesOpts{BatchSpecID: applied.ID})
+	if err != nil {
+		return false, err
+	}
+	next, _, err := s.store.ListBatchSpecWorkspaces(ctx, store.ListBatchSpecWorkspacesOpts{BatchSpecID: revision.ID})
+	if err != nil {
+		return false, err
+	}
+	return workspacesChanged(prev, next), nil
+}
+
+type workspaceKey struct {
+	repoID api.RepoID
+	branch string
+	commit string
+	path   string
+}
+
+// workspacesChanged returns true if a workspace was added or removed, or if
+// the commit a workspace is based on changed.
+func workspacesChanged(prev, next []*btypes.BatchSpecWorkspace) bool {
+	if len(prev) != len(next) {
+		return true
+	}
+
+	keys := make(map[workspaceKey]struct{}, len(prev))
+	for _, w := range prev {
+		keys[workspaceKey{repoID: w.RepoID, branch: w.Branch, commit: w.Commit, path: w.Path}] = struct{}{}
+	}
+	for _, w := range next {
+		if _, ok := keys[workspaceKey{repoID: w.RepoID, branch: w.Branch, commit: w.Commit, path: w.Path}]; !ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/log/logtest"
+
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/batches/store"
+	bt "github.com/sourcegraph/sourcegraph/internal/batches/testing"
+	btypes "github.com/sourcegraph/sourcegraph/internal/batches/types"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestWorkspacesChanged(t *testing.T) {
+	ws := func(repo int32, commit, path string) *btypes.BatchSpecWorkspace {
+		return &btypes.BatchSpecWorkspace{RepoID: api.RepoID(repo), Branch: "refs/heads/main", Commit: commit, Path: path}
+	}
+
+	for name, tc := range map[string]struct {
+		prev, next []*btypes.BatchSpecWorkspace
+		want       bool
+	}{
+		"no workspaces": {
+			want: false,
+		},
+		"unchanged": {
+			prev: []*btypes.BatchSpecWorkspace{ws(1, "a", ""), ws(2, "b", "sub")},
+			next: []*btypes.BatchSpecWorkspace{ws(2, "b", "sub"), ws(1, "a", "")},
+			want: false,
+		},
+		"repo added": {
+			prev: []*btypes.BatchSpecWorkspace{ws(1, "a", "")},
+			next: []*btypes.BatchSpecWorkspace{ws(1, "a", ""), ws(2, "b", "")},
+			want: true,
+		},
+		"repo removed": {
+			prev: []*btypes.BatchSpecWorkspace{ws(1, "a", ""), ws(2, "b", "")},
+			next: []*btypes.BatchSpecWorkspace{ws(1, "a", "")},
+			want: true,
+		},
+		"base branch moved": {
+			prev: []*btypes.BatchSpecWorkspace{ws(1, "a", ""), ws(2, "b", "")},
+			next: []*btypes.BatchSpecWorkspace{ws(1, "a", ""), ws(2, "c", "")},
+			want: true,
+		},
+		"workspace moved": {
+			prev: []*btypes.BatchSpecWorkspace{ws(1, "a", "one")},
+			next: []*btypes.BatchSpecWorkspace{ws(1, "a", "two")},
+			want: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if have := workspacesChanged(tc.prev, tc.next); have != tc.want {
+				t.Fatalf("wrong result: want=%t have=%t", tc.want, have)
+			}
+		})
+	}
+}
+
+func TestContinuousRevisions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	logger := logtest.Scoped(t)
+	ctx := actor.WithInternalActor(context.Background())
+	db := database.NewDB(logger, dbtest.NewDB(t))
+
+	admin := bt.CreateTestUser(t, db, true)
+	adminCtx := actor.WithActor(context.Background(), actor.FromUser(admin.ID))
+
+	s := store.New(db, &observation.TestContext, nil)
+	rs, _ := bt.CreateTestRepos(t, ctx, db, 2)
+
+	svc := New(s)
+
+	// createSpec creates a batch spec whose workspaces are resolved by a
+	// resolution job in the given state. There is one workspace per given
+	// commit.
+	createSpec := func(t *testing.T, autoApply bool, resolution btypes.BatchSpecResolutionJobState, skipped bool, commits ...string) *btypes.BatchSpec {
+		t.Helper()
+
+		spec := testBatchSpec(admin.ID)
+		spec.CreatedFromRaw = true
+		spec.Spec.Continuous = &batcheslib.Continuous{AutoApply: autoApply}
+		if err := s.CreateBatchSpec(ctx, spec); err != nil {
+			t.Fatal(err)
+		}
+
+		job := &btypes.BatchSpecResolutionJob{
+			State:       resolution,
+			BatchSpecID: spec.ID,
+			InitiatorID: admin.ID,
+		}
+		if err := s.CreateBatchSpecResolutionJob(ctx, job); err != nil {
+			t.Fatal(err)
+		}
+
+		for i, commit := range commits {
+			ws := &btypes.BatchSpecWorkspace{
+				BatchSpecID:       spec.ID,
+				RepoID:            rs[i].ID,
+				Branch:            "refs/heads/main",
+				Commit:            commit,
+				Skipped:           skipped,
+				CachedResultFound: skipped,
+			}
+			if err := s.CreateBatchSpecWorkspace(ctx, ws); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		return spec
+	}
+
+	executions := func(t *testing.T, spec *btypes.BatchSpec) int {
+		t.Helper()
+
+		jobs, err := s.ListBatchSpecWorkspaceExecutionJobs(ctx, store.ListBatchSpecWorkspaceExecutionJobsOpts{BatchSpecID: spec.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(jobs)
+	}
+
+	t.Run("advanceContinuousRevision", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			autoApply      bool
+			resolution     btypes.BatchSpecResolutionJobState
+			skipped        bool
+			commits        []string
+			wantSettled    bool
+			wantExecutions int
+		}{
+			"still resolving": {
+				resolution: btypes.BatchSpecResolutionJobStateProcessing,
+				commits:    []string{"b"},
+			},
+			"resolution failed": {
+				resolution:  btypes.BatchSpecResolutionJobStateFailed,
+				wantSettled: true,
+			},
+			"unchanged workspaces are not executed": {
+				resolution:  btypes.BatchSpecResolutionJobStateCompleted,
+				commits:     []string{"a"},
+				wantSettled: true,
+			},
+			"changed workspaces are executed": {
+				resolution:     btypes.BatchSpecResolutionJobStateCompleted,
+				commits:        []string{"b"},
+				wantExecutions: 1,
+			},
+			"added repository is executed": {
+				resolution:     btypes.BatchSpecResolutionJobStateCompleted,
+				commits:        []string{"a", "a"},
+				wantExecutions: 2,
+			},
+			"cached revision waits for the owner": {
+				resolution:  btypes.BatchSpecResolutionJobStateCompleted,
+				skipped:     true,
+				commits:     []string{"b"},
+				wantSettled: true,
+			},
+			"unchanged cached revision is not applied": {
+				autoApply:   true,
+				resolution:  btypes.BatchSpecResolutionJobStateCompleted,
+				skipped:     true,
+				commits:     []string{"a"},
+				wantSettled: true,
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				applied := createSpec(t, tc.autoApply, btypes.BatchSpecResolutionJobStateCompleted, false, "a")
+				batchChange := testBatchChange(admin.ID, applied)
+				if err := s.CreateBatchChange(ctx, batchChange); err != nil {
+					t.Fatal(err)
+				}
+				revision := createSpec(t, tc.autoApply, tc.resolution, tc.skipped, tc.commits...)
+
+				settled, err := svc.advanceContinuousRevision(adminCtx, batchChange, applied, revision)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if settled != tc.wantSettled {
+					t.Errorf("wrong settled: want=%t have=%t", tc.wantSettled, settled)
+				}
+				if have := executions(t, revision); have != tc.wantExecutions {
+					t.Errorf("wrong number of executions: want=%d have=%d", tc.wantExecutions, have)
+				}
+
+				reloaded, err := s.GetBatchChange(ctx, store.GetBatchChangeOpts{ID: batchChange.ID})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if reloaded.BatchSpecID != applied.ID {
+					t.Errorf("revision was applied")
+				}
+			})
+		}
+	})
+
+	t.Run("drafts are left alone", func(t *testing.T) {
+		applied := createSpec(t, true, btypes.BatchSpecResolutionJobStateCompleted, false, "a")
+		batchChange := testBatchChange(admin.ID, applied)
+		if err := s.CreateBatchChange(ctx, batchChange); err != nil {
+			t.Fatal(err)
+		}
+
+		// A batch spec created in the editor for the batch change, which would be
+		// executed if it were a revision.
+		draft := createSpec(t, true, btypes.BatchSpecResolutionJobStateCompleted, false, "b")
+		draft.Spec.Name = batchChange.Name
+		draft.BatchChangeID = batchChange.ID
+		if err := s.UpdateBatchSpec(ctx, draft); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := svc.RunContinuousBatchChange(ctx, batchChange.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		if have := executions(t, draft); have != 0 {
+			t.Errorf("draft was executed: %d executions", have)
+		}
+		if _, err := s.GetBatchSpec(ctx, store.GetBatchSpecOpts{ID: draft.ID}); err != nil {
+			t.Errorf("draft was deleted: %v", err)
+		}
+	})
+
+	t.Run("pruneContinuousRevision", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			skipped     bool
+			commits     []string
+			wantDeleted bool
+		}{
+			"unchanged revision is deleted": {
+				commits:     []string{"a"},
+				wantDeleted: true,
+			},
+			"changed cached revision is kept": {
+				skipped: true,
+				commits: []string{"b"},
+			},
+			"revision that dropped a repository is kept": {
+				skipped: true,
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				applied := createSpec(t, false, btypes.BatchSpecResolutionJobStateCompleted, false, "a")
+				revision := createSpec(t, false, btypes.BatchSpecResolutionJobStateCompleted, tc.skipped, tc.commits...)
+
+				if err := svc.pruneContinuousRevision(adminCtx, applied, revision); err != nil {
+					t.Fatal(err)
+				}
+
+				_, err := s.GetBatchSpec(ctx, store.GetBatchSpecOpts{ID: revision.ID})
+				if deleted := errors.Is(err, store.ErrNoResults); deleted != tc.wantDeleted {
+					t.Errorf("wrong deleted: want=%t have=%t (err=%v)", tc.wantDeleted, deleted, err)
+				}
+			})
+		}
+	})
+}
@@ -32,6 +32,7 @@ var batchSpecColumns = []*sqlf.Query{
 	sqlf.Sprintf("batch_specs.allow_ignored"),
 	sqlf.Sprintf("batch_specs.no_cache"),
 	sqlf.Sprintf("batch_specs.batch_change_id"),
+	sqlf.Sprintf("batch_specs.continuous_revision"),
 	sqlf.Sprintf("batch_specs.created_at"),
 	sqlf.Sprintf("batch_specs.updated_at"),
 }
@@ -50,11 +51,12 @@ var batchSpecInsertColumns = []*sqlf.Query{
 	sqlf.Sprintf("allow_ignored"),
 	sqlf.Sprintf("no_cache"),
 	sqlf.Sprintf("batch_change_id"),
+	sqlf.Sprintf("continuous_revision"),
 	sqlf.Sprintf("created_at"),
 	sqlf.Sprintf("updated_at"),
 }
 
-const batchSpecInsertColsFmt = `(%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)`
+const batchSpecInsertColsFmt = `(%s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s, %s)`
 
 // CreateBatchSpec creates the given BatchSpec.
 func (s *Store) CreateBatchSpec(ctx context.Context, c *btypes.BatchSpec) (err error) {
@@ -107,6 +109,7 @@ func (s *Store) createBatchSpecQuery(c *btypes.BatchSpec) (*sqlf.Query, error) {
 		c.AllowIgnored,
 		c.NoCache,
 		dbutil.NullInt64Column(c.BatchChangeID),
+		c.ContinuousRevision,
 		c.CreatedAt,
 		c.UpdatedAt,
 		sqlf.Join(batchSpecColumns, ", "),
@@ -158,6 +161,7 @@ func (s *Store) updateBatchSpecQuery(c *btypes.BatchSpec) (*sqlf.Query, error) {
 		c.AllowIgnored,
 		c.NoCache,
 		dbutil.NullInt64Column(c.BatchChangeID),
+		c.ContinuousRevision,
 		c.CreatedAt,
 		c.UpdatedAt,
 		c.ID,
@@ -388,6 +392,7 @@ type ListBatchSpecsOpts struct {
 	ExcludeCreatedFromRawNotOwnedByUser int32
 	IncludeLocallyExecutedSpecs         bool
 	ExcludeEmptySpecs                   bool
+	OnlyContinuousRevisions             bool
 }
 
 // ListBatchSpecs lists BatchSpecs with the given filters.
@@ -452,6 +457,10 @@ ON
 		preds = append(preds, sqlf.Sprintf("(EXISTS (SELECT * FROM jsonb_object_keys(batch_specs.spec) AS t (k) WHERE t.k NOT LIKE 'name'))"))
 	}
 
+	if opts.OnlyContinuousRevisions {
+		preds = append(preds, sqlf.Sprintf("batch_specs.continuous_revision IS TRUE"))
+	}
+
 	if opts.NewestFirst {
 		order = sqlf.Sprintf("batch_specs.id DESC")
 		if opts.Cursor != 0 {
@@ -669,6 +678,7 @@ func scanBatchSpec(c *btypes.BatchSpec, s dbutil.Scanner) error {
 		&c.AllowIgnored,
 		&c.NoCache,
 		&dbutil.NullInt64{N: &c.BatchChangeID},
+		&c.ContinuousRevision,
 		&c.CreatedAt,
 		&c.UpdatedAt,
 	)
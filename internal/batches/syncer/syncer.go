@@ -21,6 +21,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 	"github.com/sourcegraph/sourcegraph/internal/metrics"
 	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/ratelimit"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 	"github.com/sourcegraph/sourcegraph/lib/errors"
 )
@@ -407,7 +408,7 @@ func (s *changesetSyncer) Run(ctx context.Context) {
 			s.metrics.behindSchedule.WithLabelValues(s.codeHostURL).Set(float64(behindSchedule))
 		case <-timerChan:
 			start := s.syncStore.Clock()()
-			err := s.syncFunc(ctx, next.changesetID)
+			err := s.syncFunc(rateLimitContext(ctx, next.priority), next.changesetID)
 			labelValues := []string{s.codeHostURL, strconv.FormatBool(err == nil)}
 			s.metrics.syncDuration.WithLabelValues(labelValues...).Observe(s.syncStore.Clock()().Sub(start).Seconds())
 			s.metrics.syncs.WithLabelValues(labelValues...).Inc()
@@ -463,6 +464,17 @@ func (s *changesetSyncer) computeSchedule(ctx context.Context) ([]scheduledSync,
 	return ss, nil
 }
 
+// rateLimitContext attributes the code host requests of a sync with priority p
+// to the changeset syncer. Syncs requested by users are interactive, scheduled
+// syncs are background work.
+func rateLimitContext(ctx context.Context, p priority) context.Context {
+	rp := ratelimit.PriorityBackground
+	if p == priorityHigh {
+		rp = ratelimit.PriorityInteractive
+	}
+	return ratelimit.WithConsumer(ratelimit.WithPriority(ctx, rp), "changeset-sync")
+}
+
 // SyncChangeset will sync a single changeset given its id.
 func (s *changesetSyncer) SyncChangeset(ctx context.Context, id int64) error {
 	syncLogger := s.logger.With(log.Int64("id", id))
@@ -39,6 +39,12 @@ type BatchSpec struct {
 	AllowIgnored     bool
 	NoCache          bool
 
+	// ContinuousRevision is true when the BatchSpec was created by the
+	// continuous mode of its batch change as a new revision of the applied
+	// BatchSpec. Other BatchSpecs of the batch change, like drafts created in
+	// the editor, are never executed, applied or deleted by it.
+	ContinuousRevision bool
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -20,6 +20,8 @@ type operations struct {
 
 	shouldRefilterPackageRepoRefs *observation.Operation
 	updateAllBlockedStatuses      *observation.Operation
+
+	listTransitiveDependencyRepoIDs *observation.Operation
 }
 
 var m = new(metrics.SingletonREDMetrics)
@@ -55,5 +57,7 @@ func newOperations(observationCtx *observation.Context) *operations {
 
 		shouldRefilterPackageRepoRefs: op("ShouldRefilterPackageRepoRefs"),
 		updateAllBlockedStatuses:      op("UpdateAllBlockedStatuses"),
+
+		listTransitiveDependencyRepoIDs: op("ListTransitiveDependencyRepoIDs"),
 	}
 }
@@ -39,6 +39,8 @@ type Store interface {
 
 	ShouldRefilterPackageRepoRefs(ctx context.Context) (exists bool, err error)
 	UpdateAllBlockedStatuses(ctx context.Context, pkgs []shared.PackageRepoReference, startTime time.Time) (pkgsUpdated, versionsUpdated int, err error)
+
+	ListTransitiveDependencyRepoIDs(ctx context.Context, repositoryID, maxDepth int) (_ []int, err error)
 }
 
 // store manages the database tables for package dependencies.
@@ -836,3 +838,40 @@ SELECT (
 	FROM updated_package_repo_versions
 ) AS versions_changed
 `
+
+// ListTransitiveDependencyRepoIDs returns the identifiers of the repositories providing the
+// packages referenced by the precise code intelligence uploads visible at the tip of the
+// default branch of the given repository, and so on recursively up to maxDepth levels. The
+// given repository is not part of the result.
+func (s *store) ListTransitiveDependencyRepoIDs(ctx context.Context, repositoryID, maxDepth int) (_ []int, err error) {
+	ctx, _, endObservation := s.operations.listTransitiveDependencyRepoIDs.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("repositoryID", repositoryID),
+		attribute.Int("maxDepth", maxDepth),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return basestore.ScanInts(s.db.Query(ctx, sqlf.Sprintf(listTransitiveDependencyRepoIDsQuery, repositoryID, maxDepth, repositoryID)))
+}
+
+const listTransitiveDependencyRepoIDsQuery = `
+WITH RECURSIVE dependency_repos(repository_id, depth) AS (
+	SELECT %s::integer, 0
+	UNION
+	SELECT pu.repository_id, d.depth + 1
+	FROM dependency_repos d
+	JOIN lsif_uploads_visible_at_tip vt ON vt.repository_id = d.repository_id AND vt.is_default_branch
+	JOIN lsif_references r ON r.dump_id = vt.upload_id
+	JOIN lsif_packages p ON
+		p.scheme = r.scheme AND
+		p.manager = r.manager AND
+		p.name = r.name AND
+		p.version = r.version AND
+		p.dump_id != r.dump_id
+	JOIN lsif_uploads pu ON pu.id = p.dump_id AND pu.state = 'completed'
+	WHERE d.depth < %s
+)
+SELECT DISTINCT repository_id
+FROM dependency_repos
+WHERE repository_id != %s
+ORDER BY repository_id
+`
@@ -2,9 +2,11 @@ package store
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/keegancsmith/sqlf"
 	"github.com/sourcegraph/log/logtest"
 
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/dependencies/shared"
@@ -368,3 +370,77 @@ func TestDeletePackageRepoRefsByID(t *testing.T) {
 		t.Fatalf("mismatch (-want, +got): %s", diff)
 	}
 }
+
+func TestListTransitiveDependencyRepoIDs(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	logger := logtest.Scoped(t)
+	ctx := context.Background()
+	db := database.NewDB(logger, dbtest.NewDB(t))
+	store := New(&observation.TestContext, db)
+
+	// Repository N has the upload N visible at the tip of its default branch. Each upload
+	// provides one package and references another, which forms the dependency graph
+	// 1 -> 2 -> 3 -> 4 -> 2. Repository 5 provides a version of the package of repository 2
+	// that nothing references.
+	for _, upload := range []struct {
+		id         int
+		provides   string
+		version    string
+		references string
+	}{
+		{id: 1, provides: "service", version: "1.0.0", references: "a"},
+		{id: 2, provides: "a", version: "1.0.0", references: "b"},
+		{id: 3, provides: "b", version: "1.0.0", references: "c"},
+		{id: 4, provides: "c", version: "1.0.0", references: "a"},
+		{id: 5, provides: "a", version: "2.0.0"},
+	} {
+		if err := store.db.Exec(ctx, sqlf.Sprintf(`
+			INSERT INTO lsif_uploads (id, commit, state, repository_id, indexer, num_parts, uploaded_parts)
+			VALUES (%s, %s, 'completed', %s, 'scip-typescript', 1, '{}')
+		`, upload.id, fmt.Sprintf("%040d", upload.id), upload.id)); err != nil {
+			t.Fatalf("unexpected error inserting upload: %s", err)
+		}
+		if err := store.db.Exec(ctx, sqlf.Sprintf(`
+			INSERT INTO lsif_uploads_visible_at_tip (repository_id, upload_id, is_default_branch)
+			VALUES (%s, %s, true)
+		`, upload.id, upload.id)); err != nil {
+			t.Fatalf("unexpected error inserting visible upload: %s", err)
+		}
+		if err := store.db.Exec(ctx, sqlf.Sprintf(`
+			INSERT INTO lsif_packages (scheme, manager, name, version, dump_id)
+			VALUES ('npm', 'npm', %s, %s, %s)
+		`, upload.provides, upload.version, upload.id)); err != nil {
+			t.Fatalf("unexpected error inserting package: %s", err)
+		}
+		if upload.references == "" {
+			continue
+		}
+		if err := store.db.Exec(ctx, sqlf.Sprintf(`
+			INSERT INTO lsif_references (scheme, manager, name, version, dump_id)
+			VALUES ('npm', 'npm', %s, '1.0.0', %s)
+		`, upload.references, upload.id)); err != nil {
+			t.Fatalf("unexpected error inserting reference: %s", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		repositoryID int
+		maxDepth     int
+		want         []int
+	}{
+		{repositoryID: 1, maxDepth: 10, want: []int{2, 3, 4}},
+		{repositoryID: 1, maxDepth: 1, want: []int{2}},
+		{repositoryID: 3, maxDepth: 10, want: []int{2, 4}},
+		{repositoryID: 5, maxDepth: 10, want: nil},
+	} {
+		got, err := store.ListTransitiveDependencyRepoIDs(ctx, tc.repositoryID, tc.maxDepth)
+		if err != nil {
+			t.Fatalf("unexpected error listing dependency repos: %s", err)
+		}
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("unexpected dependency repos of %d at depth %d (-want +got):\n%s", tc.repositoryID, tc.maxDepth, diff)
+		}
+	}
+}
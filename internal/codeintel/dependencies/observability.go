@@ -21,6 +21,8 @@ type operations struct {
 	isPackageRepoVersionAllowed  *observation.Operation
 	isPackageRepoAllowed         *observation.Operation
 	pkgsOrVersionsMatchingFilter *observation.Operation
+
+	listTransitiveDependencyRepoIDs *observation.Operation
 }
 
 var m = new(metrics.SingletonREDMetrics)
@@ -57,5 +59,7 @@ func newOperations(observationCtx *observation.Context) *operations {
 		isPackageRepoVersionAllowed:  op("IsPackageRepoVersionAllowed"),
 		isPackageRepoAllowed:         op("IsPackageRepoAllowed"),
 		pkgsOrVersionsMatchingFilter: op("PkgsOrVersionsMatchingFilter"),
+
+		listTransitiveDependencyRepoIDs: op("ListTransitiveDependencyRepoIDs"),
 	}
 }
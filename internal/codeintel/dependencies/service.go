@@ -322,3 +322,20 @@ func (s *Service) PackagesOrVersionsMatchingFilter(ctx context.Context, filter s
 
 	return matchingPkgs, totalCount, hasMore, nil
 }
+
+// MaxTransitiveDependencyDepth bounds how many levels of dependencies of dependencies
+// ListTransitiveDependencyRepoIDs follows.
+const MaxTransitiveDependencyDepth = 10
+
+// ListTransitiveDependencyRepoIDs returns the identifiers of the repositories the given repository
+// transitively depends on, as far as precise code intelligence knows. Only dependencies that have
+// been indexed themselves are found, and each repository contributes the dependencies of the tip
+// of its default branch.
+func (s *Service) ListTransitiveDependencyRepoIDs(ctx context.Context, repositoryID int) (_ []int, err error) {
+	ctx, _, endObservation := s.operations.listTransitiveDependencyRepoIDs.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.Int("repositoryID", repositoryID),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return s.store.ListTransitiveDependencyRepoIDs(ctx, repositoryID, MaxTransitiveDependencyDepth)
+}
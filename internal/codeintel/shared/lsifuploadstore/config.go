@@ -27,16 +27,24 @@ type Config struct {
 	GCSProjectID               string
 	GCSCredentialsFile         string
 	GCSCredentialsFileContents string
+
+	AzureAccountName             string
+	AzureEndpoint                string
+	AzureAccountKey              string
+	AzureSASToken                string
+	AzureManagedIdentityClientID string
+
+	FilesystemDir string
 }
 
 func (c *Config) Load() {
-	c.Backend = strings.ToLower(c.Get("PRECISE_CODE_INTEL_UPLOAD_BACKEND", "blobstore", "The target file service for code intelligence uploads. S3, GCS, and Blobstore are supported."))
+	c.Backend = strings.ToLower(c.Get("PRECISE_CODE_INTEL_UPLOAD_BACKEND", "blobstore", "The target file service for code intelligence uploads. S3, GCS, Azure, Filesystem, and Blobstore are supported."))
 	c.ManageBucket = c.GetBool("PRECISE_CODE_INTEL_UPLOAD_MANAGE_BUCKET", "false", "Whether or not the client should manage the target bucket configuration.")
 	c.Bucket = c.Get("PRECISE_CODE_INTEL_UPLOAD_BUCKET", "lsif-uploads", "The name of the bucket to store LSIF uploads in.")
 	c.TTL = c.GetInterval("PRECISE_CODE_INTEL_UPLOAD_TTL", "168h", "The maximum age of an upload before deletion.")
 
-	if c.Backend != "blobstore" && c.Backend != "s3" && c.Backend != "gcs" {
-		c.AddError(errors.Errorf("invalid backend %q for PRECISE_CODE_INTEL_UPLOAD_BACKEND: must be S3, GCS, or Blobstore", c.Backend))
+	if c.Backend != "blobstore" && c.Backend != "s3" && c.Backend != "gcs" && c.Backend != "azure" && c.Backend != "filesystem" {
+		c.AddError(errors.Errorf("invalid backend %q for PRECISE_CODE_INTEL_UPLOAD_BACKEND: must be S3, GCS, Azure, Filesystem, or Blobstore", c.Backend))
 	}
 
 	if c.Backend == "blobstore" || c.Backend == "s3" {
@@ -54,5 +62,17 @@ func (c *Config) Load() {
 		c.GCSProjectID = c.Get("PRECISE_CODE_INTEL_UPLOAD_GCP_PROJECT_ID", "", "The project containing the GCS bucket.")
 		c.GCSCredentialsFile = c.GetOptional("PRECISE_CODE_INTEL_UPLOAD_GOOGLE_APPLICATION_CREDENTIALS_FILE", "The path to a service account key file with access to GCS.")
 		c.GCSCredentialsFileContents = c.GetOptional("PRECISE_CODE_INTEL_UPLOAD_GOOGLE_APPLICATION_CREDENTIALS_FILE_CONTENT", "The contents of a service account key file with access to GCS.")
+	} else if c.Backend == "azure" {
+		c.AzureAccountName = c.GetOptional("PRECISE_CODE_INTEL_UPLOAD_AZURE_ACCOUNT_NAME", "The Azure storage account containing the container.")
+		c.AzureEndpoint = c.GetOptional("PRECISE_CODE_INTEL_UPLOAD_AZURE_ENDPOINT", "The blob service endpoint. Defaults to the public endpoint of the storage account.")
+		c.AzureAccountKey = c.GetOptional("PRECISE_CODE_INTEL_UPLOAD_AZURE_ACCOUNT_KEY", "An access key of the storage account.")
+		c.AzureSASToken = c.GetOptional("PRECISE_CODE_INTEL_UPLOAD_AZURE_SAS_TOKEN", "A shared access signature with access to the container.")
+		c.AzureManagedIdentityClientID = c.GetOptional("PRECISE_CODE_INTEL_UPLOAD_AZURE_MANAGED_IDENTITY_CLIENT_ID", "The client ID of a user-assigned managed identity with access to the container. The system-assigned identity is used if neither this, an account key, nor a SAS token are set.")
+
+		if c.AzureAccountName == "" && c.AzureEndpoint == "" {
+			c.AddError(errors.New("one of PRECISE_CODE_INTEL_UPLOAD_AZURE_ACCOUNT_NAME or PRECISE_CODE_INTEL_UPLOAD_AZURE_ENDPOINT must be set"))
+		}
+	} else if c.Backend == "filesystem" {
+		c.FilesystemDir = c.Get("PRECISE_CODE_INTEL_UPLOAD_FILESYSTEM_DIR", "/data/uploadstore", "The directory in which uploads are stored.")
 	}
 }
@@ -106,6 +106,64 @@ func TestConfigGCS(t *testing.T) {
 	}
 }
 
+func TestConfigAzure(t *testing.T) {
+	env := map[string]string{
+		"PRECISE_CODE_INTEL_UPLOAD_BACKEND":            "Azure",
+		"PRECISE_CODE_INTEL_UPLOAD_BUCKET":             "lsif-uploads",
+		"PRECISE_CODE_INTEL_UPLOAD_AZURE_ACCOUNT_NAME": "test-account",
+		"PRECISE_CODE_INTEL_UPLOAD_AZURE_SAS_TOKEN":    "test-sas-token",
+	}
+
+	config := Config{}
+	config.SetMockGetter(mapGetter(env))
+	config.Load()
+
+	if err := config.Validate(); err != nil {
+		t.Fatalf("unexpected validation error: %s", err)
+	}
+
+	if config.AzureAccountName != "test-account" {
+		t.Errorf("unexpected value for Azure.AccountName. want=%s have=%s", "test-account", config.AzureAccountName)
+	}
+	if config.AzureSASToken != "test-sas-token" {
+		t.Errorf("unexpected value for Azure.SASToken. want=%s have=%s", "test-sas-token", config.AzureSASToken)
+	}
+	if config.S3AccessKeyID != "" {
+		t.Errorf("unexpected value for S3.AccessKeyID. want=%s have=%s", "", config.S3AccessKeyID)
+	}
+}
+
+func TestConfigAzureMissingAccount(t *testing.T) {
+	config := Config{}
+	config.SetMockGetter(mapGetter(map[string]string{
+		"PRECISE_CODE_INTEL_UPLOAD_BACKEND": "azure",
+	}))
+	config.Load()
+
+	if err := config.Validate(); err == nil {
+		t.Fatalf("expected validation error")
+	}
+}
+
+func TestConfigFilesystem(t *testing.T) {
+	env := map[string]string{
+		"PRECISE_CODE_INTEL_UPLOAD_BACKEND":        "Filesystem",
+		"PRECISE_CODE_INTEL_UPLOAD_FILESYSTEM_DIR": "/tmp/uploads",
+	}
+
+	config := Config{}
+	config.SetMockGetter(mapGetter(env))
+	config.Load()
+
+	if err := config.Validate(); err != nil {
+		t.Fatalf("unexpected validation error: %s", err)
+	}
+
+	if config.FilesystemDir != "/tmp/uploads" {
+		t.Errorf("unexpected value for Filesystem.Dir. want=%s have=%s", "/tmp/uploads", config.FilesystemDir)
+	}
+}
+
 func mapGetter(env map[string]string) func(name, defaultValue, description string) string {
 	return func(name, defaultValue, description string) string {
 		if v, ok := env[name]; ok {
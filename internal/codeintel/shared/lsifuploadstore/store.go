@@ -26,6 +26,16 @@ func New(ctx context.Context, observationCtx *observation.Context, conf *Config)
 			CredentialsFile:         conf.GCSCredentialsFile,
 			CredentialsFileContents: conf.GCSCredentialsFileContents,
 		},
+		Azure: uploadstore.AzureConfig{
+			AccountName:             conf.AzureAccountName,
+			Endpoint:                conf.AzureEndpoint,
+			AccountKey:              conf.AzureAccountKey,
+			SASToken:                conf.AzureSASToken,
+			ManagedIdentityClientID: conf.AzureManagedIdentityClientID,
+		},
+		Filesystem: uploadstore.FilesystemConfig{
+			Dir: conf.FilesystemDir,
+		},
 	}
 
 	return uploadstore.CreateLazy(ctx, c, uploadstore.NewOperations(observationCtx, "codeintel", "uploadstore"))
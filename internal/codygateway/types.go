@@ -65,13 +65,21 @@ type EmbeddingsResponse struct {
 }
 
 // ActorConcurrencyLimitConfig is the configuration for the concurrent requests
-// limit of an actor.
+// limit of an actor, and the limits derived from an actor's rate limit.
 type ActorConcurrencyLimitConfig struct {
 	// Percentage is the percentage of the daily rate limit to be used to compute the
 	// concurrency limit.
 	Percentage float32
 	// Interval is the time interval of the limit bucket.
 	Interval time.Duration
+	// MaxInFlightRequests is the number of requests an actor may have in flight
+	// at the same time per feature. Zero means no limit.
+	MaxInFlightRequests int
+	// TokensPerRequest is the average number of input and output tokens each
+	// request of an actor's rate limit may consume. The token budget of an actor
+	// is the request limit times TokensPerRequest over the same interval. Zero
+	// means no limit.
+	TokensPerRequest int64
 }
 
 // ActorRateLimitNotifyConfig is the configuration for the rate limit
@@ -0,0 +1,17 @@
+package comby
+
+import "github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+
+// isValidMatcher matches the file extensions for which comby has a dedicated,
+// language-aware matcher.
+var isValidMatcher = lazyregexp.New(`\.(s|sh|bib|c|cs|css|dart|clj|elm|erl|ex|f|fsx|go|html|hs|java|js|json|jl|kt|tex|lisp|nim|md|ml|org|pas|php|py|re|rb|rs|rst|scala|sql|swift|tex|txt|ts)$`)
+
+// ExtensionToMatcher returns the value to pass as -matcher to comby for files
+// with the given extension (e.g. ".go"). It falls back to the generic matcher
+// for extensions comby has no language-aware matcher for.
+func ExtensionToMatcher(extension string) string {
+	if isValidMatcher.MatchString(extension) {
+		return extension
+	}
+	return ".generic"
+}
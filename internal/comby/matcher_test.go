@@ -0,0 +1,17 @@
+package comby
+
+import "testing"
+
+func TestExtensionToMatcher(t *testing.T) {
+	for extension, want := range map[string]string{
+		".go":   ".go",
+		".ts":   ".ts",
+		".tsx":  ".generic",
+		".yaml": ".generic",
+		"":      ".generic",
+	} {
+		if got := ExtensionToMatcher(extension); got != want {
+			t.Errorf("ExtensionToMatcher(%q) = %q, want %q", extension, got, want)
+		}
+	}
+}
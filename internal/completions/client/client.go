@@ -8,6 +8,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/completions/client/azureopenai"
 	"github.com/sourcegraph/sourcegraph/internal/completions/client/codygateway"
 	"github.com/sourcegraph/sourcegraph/internal/completions/client/fireworks"
+	"github.com/sourcegraph/sourcegraph/internal/completions/client/google"
+	"github.com/sourcegraph/sourcegraph/internal/completions/client/mistral"
 	"github.com/sourcegraph/sourcegraph/internal/completions/client/openai"
 	"github.com/sourcegraph/sourcegraph/internal/completions/types"
 	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
@@ -44,6 +46,10 @@ func getBasic(endpoint string, provider conftypes.CompletionsProviderName, acces
 		return fireworks.NewClient(httpcli.UncachedExternalDoer, endpoint, accessToken), nil
 	case conftypes.CompletionsProviderNameAWSBedrock:
 		return awsbedrock.NewClient(httpcli.UncachedExternalDoer, endpoint, accessToken), nil
+	case conftypes.CompletionsProviderNameGoogle:
+		return google.NewClient(httpcli.UncachedExternalDoer, endpoint, accessToken, false), nil
+	case conftypes.CompletionsProviderNameMistral:
+		return mistral.NewClient(httpcli.UncachedExternalDoer, endpoint, accessToken), nil
 	default:
 		return nil, errors.Newf("unknown completion stream provider: %s", provider)
 	}
@@ -0,0 +1,95 @@
+package google
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const maxPayloadSize = 10 * 1024 * 1024 // 10mb
+
+// decoder decodes streaming events from a Server Sent Event stream. It only supports
+// streams generated by the Gemini streamGenerateContent API with alt=sse. IE this is
+// not a fully compliant Server Sent Events decoder.
+//
+// Adapted from internal/search/streaming/http/decoder.go.
+type decoder struct {
+	scanner *bufio.Scanner
+	data    []byte
+	err     error
+}
+
+func NewDecoder(r io.Reader) *decoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxPayloadSize)
+	// bufio.ScanLines, except we look for \r\n\r\n or \n\n which separate
+	// events. The API uses \r\n\r\n, but proxies may normalize line endings.
+	split := func(data []byte, atEOF bool) (int, []byte, error) {
+		if atEOF && len(data) == 0 {
+			return 0, nil, nil
+		}
+		crlf := bytes.Index(data, []byte("\r\n\r\n"))
+		lf := bytes.Index(data, []byte("\n\n"))
+		if crlf >= 0 && (lf < 0 || crlf < lf) {
+			return crlf + 4, data[:crlf], nil
+		}
+		if lf >= 0 {
+			return lf + 2, data[:lf], nil
+		}
+		// If we're at EOF, we have a final, non-terminated event. This should
+		// be empty.
+		if atEOF {
+			return len(data), data, nil
+		}
+		// Request more data.
+		return 0, nil, nil
+	}
+	scanner.Split(split)
+	return &decoder{
+		scanner: scanner,
+	}
+}
+
+// Scan advances the decoder to the next event in the stream. It returns
+// false when it either hits the end of the stream or an error.
+func (d *decoder) Scan() bool {
+	for d.scanner.Scan() {
+		// data: json($data)
+		line := bytes.TrimSpace(d.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		typ, data := splitColon(line)
+		switch {
+		case bytes.Equal(typ, []byte("data")):
+			d.data = data
+			return true
+		default:
+			d.err = errors.Errorf("malformed data, expected data: %s", typ)
+			return false
+		}
+	}
+
+	d.err = d.scanner.Err()
+	return false
+}
+
+// Event returns the event data of the last decoded event
+func (d *decoder) Data() []byte {
+	return d.data
+}
+
+// Err returns the last encountered error
+func (d *decoder) Err() error {
+	return d.err
+}
+
+func splitColon(data []byte) ([]byte, []byte) {
+	i := bytes.Index(data, []byte(":"))
+	if i < 0 {
+		return bytes.TrimSpace(data), nil
+	}
+	return bytes.TrimSpace(data[:i]), bytes.TrimSpace(data[i+1:])
+}
@@ -0,0 +1,251 @@
+package google
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/completions/types"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Model identifiers
+const GeminiPro = "gemini-pro"
+const Gemini15ProLatest = "gemini-1.5-pro-latest"
+
+// NewClient returns a completions client for the Gemini generateContent API.
+//
+// Requests to Cody Gateway can't encode the model and streaming mode in the URL
+// like the Gemini API expects, so if viaGateway is true they are sent as part of
+// the request body instead.
+func NewClient(cli httpcli.Doer, endpoint, accessToken string, viaGateway bool) types.CompletionsClient {
+	return &googleCompletionStreamClient{
+		cli:         cli,
+		accessToken: accessToken,
+		endpoint:    endpoint,
+		viaGateway:  viaGateway,
+	}
+}
+
+type googleCompletionStreamClient struct {
+	cli         httpcli.Doer
+	accessToken string
+	endpoint    string
+	viaGateway  bool
+}
+
+func (c *googleCompletionStreamClient) Complete(
+	ctx context.Context,
+	feature types.CompletionsFeature,
+	requestParams types.CompletionRequestParameters,
+) (*types.CompletionResponse, error) {
+	resp, err := c.makeRequest(ctx, requestParams, false)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var response googleResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Candidates) == 0 {
+		// Empty response.
+		return &types.CompletionResponse{}, nil
+	}
+
+	return &types.CompletionResponse{
+		Completion: response.Candidates[0].text(),
+		StopReason: response.Candidates[0].FinishReason,
+	}, nil
+}
+
+func (c *googleCompletionStreamClient) Stream(
+	ctx context.Context,
+	feature types.CompletionsFeature,
+	requestParams types.CompletionRequestParameters,
+	sendEvent types.SendCompletionEvent,
+) error {
+	resp, err := c.makeRequest(ctx, requestParams, true)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	dec := NewDecoder(resp.Body)
+	var content string
+	for dec.Scan() {
+		if ctx.Err() != nil && ctx.Err() == context.Canceled {
+			return nil
+		}
+
+		data := dec.Data()
+		// Gracefully skip over any data that isn't JSON-like.
+		if !bytes.HasPrefix(data, []byte("{")) {
+			continue
+		}
+
+		var event googleResponse
+		if err := json.Unmarshal(data, &event); err != nil {
+			return errors.Errorf("failed to decode event payload: %w - body: %s", err, string(data))
+		}
+
+		if len(event.Candidates) > 0 {
+			// Each event only contains the newly generated text.
+			content += event.Candidates[0].text()
+			err = sendEvent(types.CompletionResponse{
+				Completion: content,
+				StopReason: event.Candidates[0].FinishReason,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return dec.Err()
+}
+
+func (c *googleCompletionStreamClient) makeRequest(ctx context.Context, requestParams types.CompletionRequestParameters, stream bool) (*http.Response, error) {
+	if requestParams.TopK < 0 {
+		requestParams.TopK = 0
+	}
+	if requestParams.TopP < 0 {
+		requestParams.TopP = 0
+	}
+
+	contents, err := getContents(requestParams.Messages)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := googleRequest{
+		Contents: contents,
+		GenerationConfig: googleGenerationConfig{
+			Temperature:     requestParams.Temperature,
+			TopP:            requestParams.TopP,
+			TopK:            requestParams.TopK,
+			MaxOutputTokens: requestParams.MaxTokensToSample,
+			StopSequences:   requestParams.StopSequences,
+			CandidateCount:  1,
+		},
+	}
+	if c.viaGateway {
+		payload.Model = requestParams.Model
+		payload.Stream = stream
+	}
+
+	reqBody, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.url(requestParams.Model, stream), bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if c.accessToken != "" {
+		req.Header.Set("x-goog-api-key", c.accessToken)
+	}
+
+	resp, err := c.cli.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, types.NewErrStatusNotOK("Google", resp)
+	}
+
+	return resp, nil
+}
+
+// url returns the URL of the generateContent method of the given model, e.g.
+// https://generativelanguage.googleapis.com/v1beta/models/gemini-pro:generateContent.
+func (c *googleCompletionStreamClient) url(model string, stream bool) string {
+	endpoint := strings.TrimSuffix(c.endpoint, "/")
+	if stream {
+		return endpoint + "/" + model + ":streamGenerateContent?alt=sse"
+	}
+	return endpoint + "/" + model + ":generateContent"
+}
+
+// getContents converts messages into the Gemini contents format. Gemini calls
+// the assistant "model", and does not accept empty messages, so the trailing
+// empty assistant message our clients send to prompt for a response is
+// dropped.
+func getContents(messages []types.Message) ([]googleContent, error) {
+	contents := make([]googleContent, 0, len(messages))
+	for i, m := range messages {
+		var role string
+		switch m.Speaker {
+		case types.HUMAN_MESSAGE_SPEAKER:
+			role = "user"
+		case types.ASISSTANT_MESSAGE_SPEAKER:
+			if m.Text == "" && i == len(messages)-1 {
+				continue
+			}
+			role = "model"
+		default:
+			return nil, errors.Newf("expected message speaker to be 'human' or 'assistant', got %s", m.Speaker)
+		}
+		contents = append(contents, googleContent{
+			Role:  role,
+			Parts: []googleContentPart{{Text: m.Text}},
+		})
+	}
+	return contents, nil
+}
+
+// googleRequest is the request body of the Gemini generateContent API
+// https://ai.google.dev/api/rest/v1beta/models/generateContent.
+type googleRequest struct {
+	// Model and Stream are only set for requests to Cody Gateway, which
+	// removes them before forwarding the request.
+	Model  string `json:"model,omitempty"`
+	Stream bool   `json:"stream,omitempty"`
+
+	Contents         []googleContent        `json:"contents"`
+	GenerationConfig googleGenerationConfig `json:"generationConfig,omitempty"`
+}
+
+type googleContent struct {
+	Role  string              `json:"role,omitempty"`
+	Parts []googleContentPart `json:"parts"`
+}
+
+type googleContentPart struct {
+	Text string `json:"text"`
+}
+
+type googleGenerationConfig struct {
+	Temperature     float32  `json:"temperature,omitempty"`
+	TopP            float32  `json:"topP,omitempty"`
+	TopK            int      `json:"topK,omitempty"`
+	MaxOutputTokens int      `json:"maxOutputTokens,omitempty"`
+	StopSequences   []string `json:"stopSequences,omitempty"`
+	CandidateCount  int      `json:"candidateCount,omitempty"`
+}
+
+type googleResponse struct {
+	Candidates []googleCandidate `json:"candidates"`
+}
+
+type googleCandidate struct {
+	Content      googleContent `json:"content"`
+	FinishReason string        `json:"finishReason"`
+}
+
+func (c googleCandidate) text() string {
+	var sb strings.Builder
+	for _, p := range c.Content.Parts {
+		sb.WriteString(p.Text)
+	}
+	return sb.String()
+}
@@ -0,0 +1,217 @@
+package mistral
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/sourcegraph/sourcegraph/internal/completions/client/openai"
+	"github.com/sourcegraph/sourcegraph/internal/completions/types"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Model identifiers
+const MistralSmall = "mistral-small-latest"
+const MistralMedium = "mistral-medium-latest"
+const MistralLarge = "mistral-large-latest"
+const OpenMixtral8x7b = "open-mixtral-8x7b"
+
+func NewClient(cli httpcli.Doer, endpoint, accessToken string) types.CompletionsClient {
+	return &mistralChatCompletionStreamClient{
+		cli:         cli,
+		accessToken: accessToken,
+		endpoint:    endpoint,
+	}
+}
+
+type mistralChatCompletionStreamClient struct {
+	cli         httpcli.Doer
+	accessToken string
+	endpoint    string
+}
+
+func (c *mistralChatCompletionStreamClient) Complete(
+	ctx context.Context,
+	feature types.CompletionsFeature,
+	requestParams types.CompletionRequestParameters,
+) (*types.CompletionResponse, error) {
+	resp, err := c.makeRequest(ctx, requestParams, false)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var response mistralResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	if len(response.Choices) == 0 {
+		// Empty response.
+		return &types.CompletionResponse{}, nil
+	}
+
+	return &types.CompletionResponse{
+		Completion: response.Choices[0].Message.Content,
+		StopReason: response.Choices[0].FinishReason,
+	}, nil
+}
+
+func (c *mistralChatCompletionStreamClient) Stream(
+	ctx context.Context,
+	feature types.CompletionsFeature,
+	requestParams types.CompletionRequestParameters,
+	sendEvent types.SendCompletionEvent,
+) error {
+	resp, err := c.makeRequest(ctx, requestParams, true)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Mistral streams events in the same format as OpenAI.
+	dec := openai.NewDecoder(resp.Body)
+	var content string
+	for dec.Scan() {
+		if ctx.Err() != nil && ctx.Err() == context.Canceled {
+			return nil
+		}
+
+		data := dec.Data()
+		// Gracefully skip over any data that isn't JSON-like.
+		if !bytes.HasPrefix(data, []byte("{")) {
+			continue
+		}
+
+		var event mistralResponse
+		if err := json.Unmarshal(data, &event); err != nil {
+			return errors.Errorf("failed to decode event payload: %w - body: %s", err, string(data))
+		}
+
+		if len(event.Choices) > 0 {
+			content += event.Choices[0].Delta.Content
+			err = sendEvent(types.CompletionResponse{
+				Completion: content,
+				StopReason: event.Choices[0].FinishReason,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return dec.Err()
+}
+
+func (c *mistralChatCompletionStreamClient) makeRequest(ctx context.Context, requestParams types.CompletionRequestParameters, stream bool) (*http.Response, error) {
+	if requestParams.TopP < 0 {
+		requestParams.TopP = 0
+	}
+
+	messages, err := getMessages(requestParams.Messages)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := mistralRequest{
+		Model:       requestParams.Model,
+		Messages:    messages,
+		Temperature: requestParams.Temperature,
+		TopP:        requestParams.TopP,
+		MaxTokens:   requestParams.MaxTokensToSample,
+		Stream:      stream,
+	}
+
+	reqBody, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	url, err := url.Parse(c.endpoint)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse configured endpoint")
+	}
+	url.Path = "v1/chat/completions"
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url.String(), bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if c.accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	}
+
+	resp, err := c.cli.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, types.NewErrStatusNotOK("Mistral", resp)
+	}
+
+	return resp, nil
+}
+
+// getMessages converts messages into the Mistral chat format. Mistral expects
+// the last message to be a user message, so the trailing empty assistant
+// message our clients send to prompt for a response is dropped.
+func getMessages(messages []types.Message) ([]message, error) {
+	result := make([]message, 0, len(messages))
+	for i, m := range messages {
+		var role string
+		switch m.Speaker {
+		case types.HUMAN_MESSAGE_SPEAKER:
+			role = "user"
+		case types.ASISSTANT_MESSAGE_SPEAKER:
+			if m.Text == "" && i == len(messages)-1 {
+				continue
+			}
+			role = "assistant"
+		default:
+			return nil, errors.Newf("expected message speaker to be 'human' or 'assistant', got %s", m.Speaker)
+		}
+		result = append(result, message{
+			Role:    role,
+			Content: m.Text,
+		})
+	}
+	return result, nil
+}
+
+// mistralRequest is the request body of the Mistral chat completions API
+// https://docs.mistral.ai/api/#operation/createChatCompletion.
+type mistralRequest struct {
+	Model       string    `json:"model"`
+	Messages    []message `json:"messages"`
+	Temperature float32   `json:"temperature,omitempty"`
+	TopP        float32   `json:"top_p,omitempty"`
+	MaxTokens   int       `json:"max_tokens,omitempty"`
+	Stream      bool      `json:"stream,omitempty"`
+}
+
+type message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type mistralChoiceDelta struct {
+	Content string `json:"content"`
+}
+
+type mistralChoice struct {
+	Delta        mistralChoiceDelta `json:"delta"`
+	Message      message            `json:"message"`
+	FinishReason string             `json:"finish_reason"`
+}
+
+type mistralResponse struct {
+	Model   string          `json:"model"`
+	Choices []mistralChoice `json:"choices"`
+}
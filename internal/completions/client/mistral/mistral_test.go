@@ -0,0 +1,117 @@
+package mistral
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hexops/autogold/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/completions/types"
+)
+
+type mockDoer struct {
+	do func(*http.Request) (*http.Response, error)
+}
+
+func (c *mockDoer) Do(r *http.Request) (*http.Response, error) {
+	return c.do(r)
+}
+
+var params = types.CompletionRequestParameters{
+	Model: "mistral-small-latest",
+	Messages: []types.Message{
+		{Speaker: types.HUMAN_MESSAGE_SPEAKER, Text: "Hello"},
+		{Speaker: types.ASISSTANT_MESSAGE_SPEAKER, Text: ""},
+	},
+	MaxTokensToSample: 100,
+}
+
+func TestStream(t *testing.T) {
+	var gotURL string
+	var gotBody map[string]any
+	mockClient := NewClient(&mockDoer{
+		func(r *http.Request) (*http.Response, error) {
+			gotURL = r.URL.String()
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&gotBody))
+			return &http.Response{StatusCode: http.StatusOK, Body: fixture(t, "stream.txt")}, nil
+		},
+	}, "https://api.mistral.ai", "key")
+
+	events := []types.CompletionResponse{}
+	err := mockClient.Stream(context.Background(), types.CompletionsFeatureChat, params, func(event types.CompletionResponse) error {
+		events = append(events, event)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.mistral.ai/v1/chat/completions", gotURL)
+	autogold.Expect(map[string]any{
+		"max_tokens": 100.0,
+		"messages":   []any{map[string]any{"content": "Hello", "role": "user"}},
+		"model":      "mistral-small-latest",
+		"stream":     true,
+	}).Equal(t, gotBody)
+	autogold.ExpectFile(t, events)
+}
+
+func TestComplete(t *testing.T) {
+	mockClient := NewClient(&mockDoer{
+		func(r *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: fixture(t, "chat_completion.json")}, nil
+		},
+	}, "https://api.mistral.ai", "key")
+
+	resp, err := mockClient.Complete(context.Background(), types.CompletionsFeatureChat, params)
+	require.NoError(t, err)
+	assert.Equal(t, "stop", resp.StopReason)
+	assert.Equal(t, fixtureCompletion, resp.Completion)
+}
+
+func TestErrStatusNotOK(t *testing.T) {
+	mockClient := NewClient(&mockDoer{
+		func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Body:       io.NopCloser(bytes.NewReader([]byte("oh no, please slow down!"))),
+			}, nil
+		},
+	}, "", "")
+
+	t.Run("Complete", func(t *testing.T) {
+		resp, err := mockClient.Complete(context.Background(), types.CompletionsFeatureChat, types.CompletionRequestParameters{})
+		require.Error(t, err)
+		assert.Nil(t, resp)
+
+		autogold.Expect("Mistral: unexpected status code 429: oh no, please slow down!").Equal(t, err.Error())
+		_, ok := types.IsErrStatusNotOK(err)
+		assert.True(t, ok)
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		err := mockClient.Stream(context.Background(), types.CompletionsFeatureChat, types.CompletionRequestParameters{}, func(event types.CompletionResponse) error { return nil })
+		require.Error(t, err)
+
+		autogold.Expect("Mistral: unexpected status code 429: oh no, please slow down!").Equal(t, err.Error())
+		_, ok := types.IsErrStatusNotOK(err)
+		assert.True(t, ok)
+	})
+}
+
+// fixtureCompletion is the text generated in the responses in testdata.
+const fixtureCompletion = "Sure! Here's a recursive Fibonacci function in Python:\n\n```python\ndef fibonacci(n):\n    if n < 2:\n        return n\n    return fibonacci(n - 1) + fibonacci(n - 2)\n```\n\nFor large `n`, an iterative version avoids the exponential running time:\n\n```python\ndef fibonacci(n):\n    a, b = 0, 1\n    for _ in range(n):\n        a, b = b, a + b\n    return a\n```"
+
+// fixture returns a response body with the contents of the given file in
+// testdata.
+func fixture(t *testing.T, name string) io.ReadCloser {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	return f
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/sourcegraph/log"
 
 	"github.com/sourcegraph/sourcegraph/internal/completions/client/fireworks"
+	"github.com/sourcegraph/sourcegraph/internal/completions/client/google"
+	"github.com/sourcegraph/sourcegraph/internal/completions/client/mistral"
 	"github.com/sourcegraph/sourcegraph/internal/completions/types"
 	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
 	"github.com/sourcegraph/sourcegraph/internal/database"
@@ -83,7 +85,13 @@ func isAllowedCustomChatModel(model string, isProUser bool) bool {
 			"anthropic/claude-instant-1",
 			"openai/gpt-3.5-turbo",
 			"openai/gpt-4-1106-preview",
-			"fireworks/" + fireworks.Mixtral8x7bInstruct:
+			"fireworks/" + fireworks.Mixtral8x7bInstruct,
+			"google/" + google.GeminiPro,
+			"google/" + google.Gemini15ProLatest,
+			"mistral/" + mistral.MistralSmall,
+			"mistral/" + mistral.MistralMedium,
+			"mistral/" + mistral.MistralLarge,
+			"mistral/" + mistral.OpenMixtral8x7b:
 			return true
 		}
 	} else {
@@ -91,7 +99,9 @@ func isAllowedCustomChatModel(model string, isProUser bool) bool {
 		case "anthropic/claude-2",
 			"anthropic/claude-2.0",
 			"anthropic/claude-instant-v1",
-			"anthropic/claude-instant-1":
+			"anthropic/claude-instant-1",
+			"google/" + google.GeminiPro,
+			"mistral/" + mistral.MistralSmall:
 			return true
 		}
 	}
@@ -0,0 +1,195 @@
+package compute
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// AggregationOperator is the operator that combines the values of an output
+// command into a single result.
+type AggregationOperator string
+
+const (
+	// AggregationCount counts the values.
+	AggregationCount AggregationOperator = "count"
+	// AggregationDistinct returns the distinct values in lexicographic order.
+	AggregationDistinct AggregationOperator = "distinct"
+	// AggregationHistogram counts the occurrences of every value, ordered by
+	// count.
+	AggregationHistogram AggregationOperator = "histogram"
+	// AggregationTop is a histogram limited to the N most frequent values.
+	AggregationTop AggregationOperator = "top"
+)
+
+// Aggregation describes how the values of an output command are aggregated.
+type Aggregation struct {
+	Operator AggregationOperator
+	// N is the number of values to return for AggregationTop.
+	N int
+}
+
+func (a *Aggregation) String() string {
+	if a.Operator == AggregationTop {
+		return fmt.Sprintf("%s %d", a.Operator, a.N)
+	}
+	return string(a.Operator)
+}
+
+var (
+	// aggregationSuffix matches an aggregation operator appended to an output
+	// template with a pipe, like `$1 | top 10`.
+	aggregationSuffix = lazyregexp.New(`^(?s)(.*?)\s*\|\s*(count|distinct|histogram|top\s+\d+)\s*$`)
+	// countBy matches `count by <template>`, which is shorthand for
+	// `<template> | histogram`.
+	countBy = lazyregexp.New(`^(?s)count\s+by\s+(.+)$`)
+)
+
+// parseAggregation splits an output template into the template and an
+// optional aggregation.
+func parseAggregation(template string) (string, *Aggregation, error) {
+	if m := countBy.FindStringSubmatch(template); m != nil {
+		return m[1], &Aggregation{Operator: AggregationHistogram}, nil
+	}
+	m := aggregationSuffix.FindStringSubmatch(template)
+	if m == nil {
+		return template, nil, nil
+	}
+	template, op := m[1], m[2]
+	if rest, ok := strings.CutPrefix(op, string(AggregationTop)); ok {
+		n, err := strconv.Atoi(strings.TrimSpace(rest))
+		if err != nil || n < 1 {
+			return "", nil, errors.Errorf("invalid aggregation %q, top expects a positive number", op)
+		}
+		return template, &Aggregation{Operator: AggregationTop, N: n}, nil
+	}
+	return template, &Aggregation{Operator: AggregationOperator(op)}, nil
+}
+
+// AggregateValue is a value and the number of times it was output.
+type AggregateValue struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Aggregate is the result of aggregating output values. Text holds a human
+// readable rendering of the aggregate, so that clients that only understand
+// text results can display it.
+type Aggregate struct {
+	Text
+	Operator string           `json:"operator"`
+	Total    int              `json:"total"`
+	Values   []AggregateValue `json:"values"`
+	// Partial is true for intermediate aggregates sent while results are
+	// still streaming in.
+	Partial bool `json:"partial"`
+}
+
+// Aggregator aggregates the results of an output command incrementally. It
+// is safe for concurrent use.
+type Aggregator struct {
+	aggregation Aggregation
+	separator   string
+
+	mu     sync.Mutex
+	counts map[string]int
+	total  int
+	dirty  bool
+}
+
+// NewAggregator returns an aggregator for cmd, or nil if cmd does not
+// aggregate its results.
+func NewAggregator(cmd Command) *Aggregator {
+	output, ok := cmd.(*Output)
+	if !ok || output.Aggregation == nil {
+		return nil
+	}
+	return &Aggregator{
+		aggregation: *output.Aggregation,
+		separator:   output.Separator,
+		counts:      map[string]int{},
+	}
+}
+
+// Add adds the values of an output command result to the aggregate.
+func (a *Aggregator) Add(r Result) {
+	var value string
+	switch v := r.(type) {
+	case *Text:
+		value = v.Value
+	case *TextExtra:
+		value = v.Value
+	default:
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, v := range strings.Split(value, a.separator) {
+		if v == "" {
+			continue
+		}
+		a.counts[v]++
+		a.total++
+		a.dirty = true
+	}
+}
+
+// Dirty returns true if values were added since the last call to Result.
+func (a *Aggregator) Dirty() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.dirty
+}
+
+// Result returns the current aggregate.
+func (a *Aggregator) Result(partial bool) *Aggregate {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.dirty = false
+
+	res := &Aggregate{
+		Text:     Text{Kind: "aggregate"},
+		Operator: a.aggregation.String(),
+		Total:    a.total,
+		Partial:  partial,
+		Values:   []AggregateValue{},
+	}
+	if a.aggregation.Operator == AggregationCount {
+		res.Value = strconv.Itoa(a.total)
+		return res
+	}
+
+	for v, c := range a.counts {
+		res.Values = append(res.Values, AggregateValue{Value: v, Count: c})
+	}
+	if a.aggregation.Operator == AggregationDistinct {
+		sort.Slice(res.Values, func(i, j int) bool { return res.Values[i].Value < res.Values[j].Value })
+	} else {
+		sort.Slice(res.Values, func(i, j int) bool {
+			if res.Values[i].Count != res.Values[j].Count {
+				return res.Values[i].Count > res.Values[j].Count
+			}
+			return res.Values[i].Value < res.Values[j].Value
+		})
+	}
+	if a.aggregation.Operator == AggregationTop && len(res.Values) > a.aggregation.N {
+		res.Values = res.Values[:a.aggregation.N]
+	}
+
+	var b strings.Builder
+	for _, v := range res.Values {
+		if a.aggregation.Operator == AggregationDistinct {
+			fmt.Fprintf(&b, "%s\n", v.Value)
+		} else {
+			fmt.Fprintf(&b, "%d %s\n", v.Count, v.Value)
+		}
+	}
+	res.Value = b.String()
+	return res
+}
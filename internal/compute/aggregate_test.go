@@ -0,0 +1,82 @@
+package compute
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hexops/autogold/v2"
+)
+
+func TestParseAggregation(t *testing.T) {
+	test := func(template string) string {
+		rest, aggregation, err := parseAggregation(template)
+		if err != nil {
+			return err.Error()
+		}
+		if aggregation == nil {
+			return rest
+		}
+		return rest + " | " + aggregation.String()
+	}
+
+	autogold.Expect("$1").Equal(t, test("$1"))
+	autogold.Expect("$1 | count").Equal(t, test("$1 | count"))
+	autogold.Expect("$1 | histogram").Equal(t, test("count by $1"))
+	autogold.Expect("$repo: $1 | top 10").Equal(t, test("$repo: $1|top 10"))
+	autogold.Expect("$1 | distinct").Equal(t, test("$1 |distinct "))
+	autogold.Expect(`invalid aggregation "top 0", top expects a positive number`).Equal(t, test("$1 | top 0"))
+	autogold.Expect("a | b").Equal(t, test("a | b"))
+}
+
+func TestAggregator(t *testing.T) {
+	test := func(aggregation Aggregation, results ...Result) string {
+		a := NewAggregator(&Output{Separator: "\n", Aggregation: &aggregation})
+		for _, r := range results {
+			a.Add(r)
+		}
+		out, _ := json.Marshal(a.Result(false))
+		return string(out)
+	}
+
+	results := []Result{
+		&Text{Value: "1.2\n1.3\n", Kind: "output"},
+		&TextExtra{Text: Text{Value: "1.2\n", Kind: "output"}, Repository: "a"},
+		&Text{Value: "1.1\n1.2\n", Kind: "output"},
+	}
+
+	autogold.Expect(`{"value":"5","kind":"aggregate","operator":"count","total":5,"values":[],"partial":false}`).
+		Equal(t, test(Aggregation{Operator: AggregationCount}, results...))
+
+	autogold.Expect(`{"value":"1.1\n1.2\n1.3\n","kind":"aggregate","operator":"distinct","total":5,"values":[{"value":"1.1","count":1},{"value":"1.2","count":3},{"value":"1.3","count":1}],"partial":false}`).
+		Equal(t, test(Aggregation{Operator: AggregationDistinct}, results...))
+
+	autogold.Expect(`{"value":"3 1.2\n1 1.1\n1 1.3\n","kind":"aggregate","operator":"histogram","total":5,"values":[{"value":"1.2","count":3},{"value":"1.1","count":1},{"value":"1.3","count":1}],"partial":false}`).
+		Equal(t, test(Aggregation{Operator: AggregationHistogram}, results...))
+
+	autogold.Expect(`{"value":"3 1.2\n1 1.1\n","kind":"aggregate","operator":"top 2","total":5,"values":[{"value":"1.2","count":3},{"value":"1.1","count":1}],"partial":false}`).
+		Equal(t, test(Aggregation{Operator: AggregationTop, N: 2}, results...))
+
+	autogold.Expect(`{"value":"0","kind":"aggregate","operator":"count","total":0,"values":[],"partial":false}`).
+		Equal(t, test(Aggregation{Operator: AggregationCount}))
+}
+
+func TestAggregatorPartial(t *testing.T) {
+	a := NewAggregator(&Output{Separator: "\n", Aggregation: &Aggregation{Operator: AggregationCount}})
+	if a.Dirty() {
+		t.Fatal("expected new aggregator to be clean")
+	}
+	a.Add(&Text{Value: "x\n"})
+	if !a.Dirty() {
+		t.Fatal("expected aggregator to be dirty after adding a value")
+	}
+	if got := a.Result(true); !got.Partial || got.Total != 1 {
+		t.Fatalf("unexpected partial result %+v", got)
+	}
+	if a.Dirty() {
+		t.Fatal("expected aggregator to be clean after getting the result")
+	}
+
+	if NewAggregator(&Output{Separator: "\n"}) != nil {
+		t.Fatal("expected no aggregator for output without aggregation")
+	}
+}
@@ -0,0 +1,99 @@
+package compute
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	batcheslib "github.com/sourcegraph/sourcegraph/lib/batches"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// patchDir is the directory in the step container that patches are mounted to.
+const patchDir = "/tmp/compute"
+
+type replaceBatchSpec struct {
+	Name              string                   `yaml:"name"`
+	Description       string                   `yaml:"description"`
+	On                []replaceBatchSpecOn     `yaml:"on"`
+	Steps             []replaceBatchSpecStep   `yaml:"steps"`
+	ChangesetTemplate replaceChangesetTemplate `yaml:"changesetTemplate"`
+}
+
+type replaceBatchSpecOn struct {
+	Repository string `yaml:"repository"`
+}
+
+type replaceBatchSpecStep struct {
+	Run       string            `yaml:"run"`
+	Container string            `yaml:"container"`
+	Files     map[string]string `yaml:"files"`
+}
+
+type replaceChangesetTemplate struct {
+	Title  string `yaml:"title"`
+	Body   string `yaml:"body"`
+	Branch string `yaml:"branch"`
+	Commit struct {
+		Message string `yaml:"message"`
+	} `yaml:"commit"`
+}
+
+// NewReplaceBatchSpec returns the YAML of a batch spec that applies the diffs
+// produced by a `content:replace.diff(...)` compute query. The batch spec runs
+// a single step, which applies the combined patch of each repository. The
+// createBatchSpecFromCompute mutation creates a draft batch spec from it, which
+// is then previewed and applied like any other.
+func NewReplaceBatchSpec(name, computeQuery string, diffs []*TextExtra) (string, error) {
+	if len(diffs) == 0 {
+		return "", errors.New("compute query produced no changes")
+	}
+
+	patches := map[string]*strings.Builder{}
+	for _, d := range diffs {
+		if d.Kind != "replace-diff" {
+			return "", errors.Errorf("unexpected compute result of kind %q, expected replace-diff", d.Kind)
+		}
+		if _, ok := patches[d.Repository]; !ok {
+			patches[d.Repository] = &strings.Builder{}
+		}
+		patches[d.Repository].WriteString(d.Value)
+	}
+
+	repos := make([]string, 0, len(patches))
+	for repo := range patches {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+
+	spec := replaceBatchSpec{
+		Name:        name,
+		Description: fmt.Sprintf("Changes produced by the compute query `%s`.", computeQuery),
+		Steps: []replaceBatchSpecStep{{
+			Run:       fmt.Sprintf(`git apply -p1 "%s/${{ repository.name }}.patch"`, patchDir),
+			Container: "alpine/git",
+			Files:     make(map[string]string, len(repos)),
+		}},
+	}
+	for _, repo := range repos {
+		spec.On = append(spec.On, replaceBatchSpecOn{Repository: repo})
+		spec.Steps[0].Files[fmt.Sprintf("%s/%s.patch", patchDir, repo)] = patches[repo].String()
+	}
+	spec.ChangesetTemplate.Title = name
+	spec.ChangesetTemplate.Body = fmt.Sprintf("Created by the compute query `%s`.", computeQuery)
+	spec.ChangesetTemplate.Branch = "compute/" + name
+	spec.ChangesetTemplate.Commit.Message = fmt.Sprintf("Apply compute query `%s`", computeQuery)
+
+	raw, err := yaml.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	// Make sure we hand off something that batch changes accepts, e.g. the
+	// name is restricted to a subset of characters.
+	if _, err := batcheslib.ParseBatchSpec(raw); err != nil {
+		return "", errors.Wrap(err, "invalid batch spec")
+	}
+	return string(raw), nil
+}
@@ -0,0 +1,63 @@
+package compute
+
+import (
+	"testing"
+
+	"github.com/hexops/autogold/v2"
+)
+
+func TestNewReplaceBatchSpec(t *testing.T) {
+	test := func(name string, diffs ...*TextExtra) string {
+		spec, err := NewReplaceBatchSpec(name, "content:replace.diff(foo -> bar)", diffs)
+		if err != nil {
+			return err.Error()
+		}
+		return spec
+	}
+
+	diff := func(repo, path string) *TextExtra {
+		return &TextExtra{
+			Text:       Text{Value: unifiedDiff(path, "foo\n", "bar\n"), Kind: "replace-diff"},
+			Repository: repo,
+		}
+	}
+
+	autogold.Expect(`name: rename-foo
+description: Changes produced by the compute query `+"`content:replace.diff(foo -> bar)`"+`.
+"on":
+    - repository: github.com/a/a
+    - repository: github.com/b/b
+steps:
+    - run: git apply -p1 "/tmp/compute/${{ repository.name }}.patch"
+      container: alpine/git
+      files:
+        /tmp/compute/github.com/a/a.patch: |
+            --- a/x.go
+            +++ b/x.go
+            @@ -1 +1 @@
+            -foo
+            +bar
+            --- a/y.go
+            +++ b/y.go
+            @@ -1 +1 @@
+            -foo
+            +bar
+        /tmp/compute/github.com/b/b.patch: |
+            --- a/z.go
+            +++ b/z.go
+            @@ -1 +1 @@
+            -foo
+            +bar
+changesetTemplate:
+    title: rename-foo
+    body: Created by the compute query `+"`content:replace.diff(foo -> bar)`"+`.
+    branch: compute/rename-foo
+    commit:
+        message: Apply compute query `+"`content:replace.diff(foo -> bar)`"+`
+`).Equal(t, test("rename-foo", diff("github.com/b/b", "z.go"), diff("github.com/a/a", "x.go"), diff("github.com/a/a", "y.go")))
+
+	autogold.Expect("compute query produced no changes").Equal(t, test("rename-foo"))
+
+	autogold.Expect("invalid batch spec: The batch change name can only contain word characters, dots and dashes. No whitespace or newlines allowed.").
+		Equal(t, test("rename foo", diff("github.com/a/a", "x.go")))
+}
@@ -20,6 +20,10 @@ type Output struct {
 	Selector      string
 	TypeValue     string
 	Kind          string
+
+	// Aggregation, if set, combines the output values of all matches into a
+	// single result. See Aggregator.
+	Aggregation *Aggregation
 }
 
 func (c *Output) ToSearchPattern() string {
@@ -27,6 +31,9 @@ func (c *Output) ToSearchPattern() string {
 }
 
 func (c *Output) String() string {
+	if c.Aggregation != nil {
+		return fmt.Sprintf("Output with separator: (%s) -> (%s) separator: %s aggregation: %s", c.SearchPattern.String(), c.OutputPattern, c.Separator, c.Aggregation)
+	}
 	return fmt.Sprintf("Output with separator: (%s) -> (%s) separator: %s", c.SearchPattern.String(), c.OutputPattern, c.Separator)
 }
 
@@ -103,6 +110,12 @@ func resultChunks(r result.Match, kind string, onlyPath bool) []string {
 		return []string{content}
 	case *result.OwnerMatch:
 		return []string{m.ResolvedOwner.Identifier()}
+	case *result.LanguageMatch:
+		return []string{m.Language}
+	case *result.CommitAuthorMatch:
+		return []string{m.Name + " <" + m.Email + ">"}
+	case *result.ContributorMatch:
+		return []string{m.Name + " <" + m.Email + ">"}
 	default:
 		panic("unsupported result kind in compute output command")
 	}
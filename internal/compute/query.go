@@ -2,6 +2,7 @@ package compute
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/grafana/regexp"
 
@@ -97,13 +98,16 @@ func toRegexpPattern(value string) (MatchPattern, error) {
 
 var ComputePredicateRegistry = query.PredicateRegistry{
 	query.FieldContent: {
-		"replace":            func() query.Predicate { return query.EmptyPredicate{} },
-		"replace.regexp":     func() query.Predicate { return query.EmptyPredicate{} },
-		"replace.structural": func() query.Predicate { return query.EmptyPredicate{} },
-		"output":             func() query.Predicate { return query.EmptyPredicate{} },
-		"output.regexp":      func() query.Predicate { return query.EmptyPredicate{} },
-		"output.structural":  func() query.Predicate { return query.EmptyPredicate{} },
-		"output.extra":       func() query.Predicate { return query.EmptyPredicate{} },
+		"replace":                 func() query.Predicate { return query.EmptyPredicate{} },
+		"replace.regexp":          func() query.Predicate { return query.EmptyPredicate{} },
+		"replace.structural":      func() query.Predicate { return query.EmptyPredicate{} },
+		"replace.diff":            func() query.Predicate { return query.EmptyPredicate{} },
+		"replace.diff.regexp":     func() query.Predicate { return query.EmptyPredicate{} },
+		"replace.diff.structural": func() query.Predicate { return query.EmptyPredicate{} },
+		"output":                  func() query.Predicate { return query.EmptyPredicate{} },
+		"output.regexp":           func() query.Predicate { return query.EmptyPredicate{} },
+		"output.structural":       func() query.Predicate { return query.EmptyPredicate{} },
+		"output.extra":            func() query.Predicate { return query.EmptyPredicate{} },
 	},
 }
 
@@ -147,13 +151,13 @@ func parseReplace(q *query.Basic) (Command, bool, error) {
 
 	var matchPattern MatchPattern
 	switch name {
-	case "replace", "replace.regexp":
+	case "replace", "replace.regexp", "replace.diff", "replace.diff.regexp":
 		var err error
 		matchPattern, err = toRegexpPattern(left)
 		if err != nil {
 			return nil, false, errors.Wrap(err, "replace command")
 		}
-	case "replace.structural":
+	case "replace.structural", "replace.diff.structural":
 		// structural search doesn't do any match pattern validation
 		matchPattern = &Comby{Value: left}
 	default:
@@ -164,6 +168,7 @@ func parseReplace(q *query.Basic) (Command, bool, error) {
 	return &Replace{
 		SearchPattern:  matchPattern,
 		ReplacePattern: right,
+		Diff:           strings.HasPrefix(name, "replace.diff"),
 	}, true, nil
 }
 
@@ -199,6 +204,11 @@ func parseOutput(q *query.Basic) (Command, bool, error) {
 		return nil, false, nil
 	}
 
+	right, aggregation, err := parseAggregation(right)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "output command")
+	}
+
 	var typeValue string
 	query.VisitField(q.ToParseTree(), query.FieldType, func(value string, _ bool, _ query.Annotation) {
 		typeValue = value
@@ -217,6 +227,7 @@ func parseOutput(q *query.Basic) (Command, bool, error) {
 		TypeValue:     typeValue,
 		Selector:      selector,
 		Kind:          name,
+		Aggregation:   aggregation,
 	}, true, nil
 }
 
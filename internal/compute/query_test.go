@@ -41,6 +41,18 @@ func TestParse(t *testing.T) {
 
 	autogold.Expect("Command: `Replace in place: () -> (b)`").
 		Equal(t, test("content:replace(->b)"))
+
+	autogold.Expect("Command: `Replace as diff: (a) -> (b)`").
+		Equal(t, test("content:replace.diff(a -> b)"))
+
+	autogold.Expect("Command: `Replace as diff: (foo(:[x])) -> (bar(:[x]))`").
+		Equal(t, test("content:replace.diff.structural(foo(:[x]) -> bar(:[x]))"))
+
+	autogold.Expect("Command: `Output with separator: (version: (\\S+)) -> ($1) separator: \n aggregation: top 5`").
+		Equal(t, test("content:output(version: (\\S+) -> $1 | top 5)"))
+
+	autogold.Expect("Command: `Output with separator: (version: (\\S+)) -> ($1) separator: \n aggregation: histogram`").
+		Equal(t, test("content:output(version: (\\S+) -> count by $1)"))
 }
 
 func TestToSearchQuery(t *testing.T) {
@@ -4,7 +4,10 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"path/filepath"
 
+	"github.com/hexops/gotextdiff"
+	"github.com/hexops/gotextdiff/myers"
 	"github.com/sourcegraph/log"
 
 	"github.com/sourcegraph/sourcegraph/internal/comby"
@@ -16,6 +19,10 @@ import (
 type Replace struct {
 	SearchPattern  MatchPattern
 	ReplacePattern string
+
+	// Diff, when set, makes Run return a unified diff of the replacement
+	// instead of the new file content.
+	Diff bool
 }
 
 func (c *Replace) ToSearchPattern() string {
@@ -23,10 +30,23 @@ func (c *Replace) ToSearchPattern() string {
 }
 
 func (c *Replace) String() string {
+	if c.Diff {
+		return fmt.Sprintf("Replace as diff: (%s) -> (%s)", c.SearchPattern.String(), c.ReplacePattern)
+	}
 	return fmt.Sprintf("Replace in place: (%s) -> (%s)", c.SearchPattern.String(), c.ReplacePattern)
 }
 
-func replace(ctx context.Context, content []byte, matchPattern MatchPattern, replacePattern string) (*Text, error) {
+// combyMatcher returns the comby matcher to use for the file at path. Diffs
+// use the language-aware matcher for the file's extension, like structural
+// search does. In-place replacements keep using the generic matcher.
+func (c *Replace) combyMatcher(path string) string {
+	if !c.Diff {
+		return ".generic"
+	}
+	return comby.ExtensionToMatcher(filepath.Ext(path))
+}
+
+func replace(ctx context.Context, content []byte, matchPattern MatchPattern, replacePattern, matcher string) (*Text, error) {
 	var newContent string
 	switch match := matchPattern.(type) {
 	case *Regexp:
@@ -36,7 +56,7 @@ func replace(ctx context.Context, content []byte, matchPattern MatchPattern, rep
 			Input:           comby.FileContent(content),
 			MatchTemplate:   match.Value,
 			RewriteTemplate: replacePattern,
-			Matcher:         ".generic", // TODO(search): use language or file filter
+			Matcher:         matcher,
 			ResultKind:      comby.Replacement,
 			NumWorkers:      0, // Just a single file's content.
 		})
@@ -51,6 +71,17 @@ func replace(ctx context.Context, content []byte, matchPattern MatchPattern, rep
 	return &Text{Value: newContent, Kind: "replace-in-place"}, nil
 }
 
+// unifiedDiff returns a git-style unified diff between the old and new content
+// of the file at path, which can be applied with `git apply`. It returns the
+// empty string if the contents are equal.
+func unifiedDiff(path, oldContent, newContent string) string {
+	edits := myers.ComputeEdits("", oldContent, newContent)
+	if len(edits) == 0 {
+		return ""
+	}
+	return fmt.Sprint(gotextdiff.ToUnified("a/"+path, "b/"+path, oldContent, edits))
+}
+
 func (c *Replace) Run(ctx context.Context, gitserverClient gitserver.Client, r result.Match) (Result, error) {
 	switch m := r.(type) {
 	case *result.FileMatch:
@@ -63,7 +94,23 @@ func (c *Replace) Run(ctx context.Context, gitserverClient gitserver.Client, r r
 		if err != nil {
 			return nil, err
 		}
-		return replace(ctx, content, c.SearchPattern, c.ReplacePattern)
+		replaced, err := replace(ctx, content, c.SearchPattern, c.ReplacePattern, c.combyMatcher(m.Path))
+		if err != nil {
+			return nil, err
+		}
+		if !c.Diff {
+			return replaced, nil
+		}
+		diff := unifiedDiff(m.Path, string(content), replaced.Value)
+		if diff == "" {
+			// Nothing to change in this file.
+			return nil, nil
+		}
+		return &TextExtra{
+			Text:         Text{Value: diff, Kind: "replace-diff"},
+			RepositoryID: int32(m.Repo.ID),
+			Repository:   string(m.Repo.Name),
+		}, nil
 	}
 	return nil, nil
 }
@@ -13,7 +13,7 @@ import (
 
 func Test_replace(t *testing.T) {
 	test := func(input string, cmd *Replace) string {
-		result, err := replace(context.Background(), []byte(input), cmd.SearchPattern, cmd.ReplacePattern)
+		result, err := replace(context.Background(), []byte(input), cmd.SearchPattern, cmd.ReplacePattern, ".generic")
 		if err != nil {
 			return err.Error()
 		}
@@ -37,3 +37,26 @@ func Test_replace(t *testing.T) {
 			ReplacePattern: "foo(:[y], :[x])",
 		}))
 }
+
+func Test_unifiedDiff(t *testing.T) {
+	autogold.Expect(`--- a/main.go
++++ b/main.go
+@@ -1,3 +1,3 @@
+ package main
+ 
+-var x = foo(bar, baz)
++var x = foo(baz, bar)
+`).Equal(t, unifiedDiff("main.go", "package main\n\nvar x = foo(bar, baz)\n", "package main\n\nvar x = foo(baz, bar)\n"))
+
+	autogold.Expect("").Equal(t, unifiedDiff("main.go", "unchanged\n", "unchanged\n"))
+}
+
+func Test_combyMatcher(t *testing.T) {
+	diff := &Replace{Diff: true}
+	autogold.Expect(".go").Equal(t, diff.combyMatcher("cmd/main.go"))
+	autogold.Expect(".generic").Equal(t, diff.combyMatcher("Dockerfile"))
+	autogold.Expect(".generic").Equal(t, diff.combyMatcher("notes.go.orig"))
+
+	// In-place replacements keep using the generic matcher.
+	autogold.Expect(".generic").Equal(t, (&Replace{}).combyMatcher("cmd/main.go"))
+}
@@ -8,8 +8,10 @@ var (
 	_ Result = (*MatchContext)(nil)
 	_ Result = (*Text)(nil)
 	_ Result = (*TextExtra)(nil)
+	_ Result = (*Aggregate)(nil)
 )
 
 func (*MatchContext) result() {}
 func (*Text) result()         {}
 func (*TextExtra) result()    {}
+func (*Aggregate) result()    {}
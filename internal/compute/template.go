@@ -310,6 +310,24 @@ func NewMetaEnvironment(r searchresult.Match, content string) *MetaEnvironment {
 			Owner:   m.ResolvedOwner.Identifier(),
 			Content: content,
 		}
+	case *searchresult.LanguageMatch:
+		return &MetaEnvironment{
+			Lang:    m.Language,
+			Content: content,
+		}
+	case *searchresult.CommitAuthorMatch:
+		return &MetaEnvironment{
+			Author:  m.Name,
+			Email:   m.Email,
+			Content: content,
+		}
+	case *searchresult.ContributorMatch:
+		return &MetaEnvironment{
+			Repo:    string(m.Repo.Name),
+			Author:  m.Name,
+			Email:   m.Email,
+			Content: content,
+		}
 	}
 	return &MetaEnvironment{}
 }
@@ -808,6 +808,56 @@ func GetCompletionsConfig(siteConfig schema.SiteConfiguration) (c *conftypes.Com
 		if completionsConfig.CompletionModel == "" {
 			completionsConfig.CompletionModel = "anthropic.claude-instant-v1"
 		}
+	} else if completionsConfig.Provider == string(conftypes.CompletionsProviderNameGoogle) {
+		// If no endpoint is configured, use a default value.
+		if completionsConfig.Endpoint == "" {
+			completionsConfig.Endpoint = "https://generativelanguage.googleapis.com/v1beta/models"
+		}
+
+		// If not access token is set, we cannot talk to Google. Bail.
+		if completionsConfig.AccessToken == "" {
+			return nil
+		}
+
+		// Set a default chat model.
+		if completionsConfig.ChatModel == "" {
+			completionsConfig.ChatModel = "gemini-pro"
+		}
+
+		// Set a default fast chat model.
+		if completionsConfig.FastChatModel == "" {
+			completionsConfig.FastChatModel = "gemini-pro"
+		}
+
+		// Set a default completions model.
+		if completionsConfig.CompletionModel == "" {
+			completionsConfig.CompletionModel = "gemini-pro"
+		}
+	} else if completionsConfig.Provider == string(conftypes.CompletionsProviderNameMistral) {
+		// If no endpoint is configured, use a default value.
+		if completionsConfig.Endpoint == "" {
+			completionsConfig.Endpoint = "https://api.mistral.ai"
+		}
+
+		// If not access token is set, we cannot talk to Mistral. Bail.
+		if completionsConfig.AccessToken == "" {
+			return nil
+		}
+
+		// Set a default chat model.
+		if completionsConfig.ChatModel == "" {
+			completionsConfig.ChatModel = "mistral-large-latest"
+		}
+
+		// Set a default fast chat model.
+		if completionsConfig.FastChatModel == "" {
+			completionsConfig.FastChatModel = "mistral-small-latest"
+		}
+
+		// Set a default completions model.
+		if completionsConfig.CompletionModel == "" {
+			completionsConfig.CompletionModel = "mistral-small-latest"
+		}
 	}
 
 	// Make sure models are always treated case-insensitive.
@@ -1167,6 +1217,12 @@ func defaultMaxPromptTokens(provider conftypes.CompletionsProviderName, model st
 		if strings.HasPrefix(model, "anthropic/") {
 			return anthropicDefaultMaxPromptTokens(strings.TrimPrefix(model, "anthropic/"))
 		}
+		if strings.HasPrefix(model, "google/") {
+			return googleDefaultMaxPromptTokens(strings.TrimPrefix(model, "google/"))
+		}
+		if strings.HasPrefix(model, "mistral/") {
+			return mistralDefaultMaxPromptTokens(strings.TrimPrefix(model, "mistral/"))
+		}
 		// Fallback for weird values.
 		return 9_000
 	case conftypes.CompletionsProviderNameAnthropic:
@@ -1175,6 +1231,10 @@ func defaultMaxPromptTokens(provider conftypes.CompletionsProviderName, model st
 		return openaiDefaultMaxPromptTokens(model)
 	case conftypes.CompletionsProviderNameFireworks:
 		return fireworksDefaultMaxPromptTokens(model)
+	case conftypes.CompletionsProviderNameGoogle:
+		return googleDefaultMaxPromptTokens(model)
+	case conftypes.CompletionsProviderNameMistral:
+		return mistralDefaultMaxPromptTokens(model)
 	case conftypes.CompletionsProviderNameAzureOpenAI:
 		// We cannot know based on the model name what model is actually used,
 		// this is a sane default for GPT in general.
@@ -1234,6 +1294,22 @@ func fireworksDefaultMaxPromptTokens(model string) int {
 	return 4_000
 }
 
+func googleDefaultMaxPromptTokens(model string) int {
+	if strings.HasPrefix(model, "gemini-1.5") {
+		// Gemini 1.5 has a context window of 1M tokens, but we don't want to
+		// send prompts of that size by default.
+		return 100_000
+	}
+
+	// Gemini 1.0 Pro has a context window of 32k tokens
+	return 28_000
+}
+
+func mistralDefaultMaxPromptTokens(model string) int {
+	// All Mistral API models have a context window of 32k tokens
+	return 28_000
+}
+
 // RepoListUpdateInterval returns the repository list update interval.
 //
 // If the RepoListUpdateInterval site configuration setting is 0, it defaults to 1 minute.
@@ -42,6 +42,8 @@ const (
 	CompletionsProviderNameSourcegraph CompletionsProviderName = "sourcegraph"
 	CompletionsProviderNameFireworks   CompletionsProviderName = "fireworks"
 	CompletionsProviderNameAWSBedrock  CompletionsProviderName = "aws-bedrock"
+	CompletionsProviderNameGoogle      CompletionsProviderName = "google"
+	CompletionsProviderNameMistral     CompletionsProviderName = "mistral"
 )
 
 type EmbeddingsConfig struct {
@@ -57,6 +57,7 @@ type DB interface {
 	RepoCommitsChangelists() RepoCommitsChangelistsStore
 	RepoKVPs() RepoKVPStore
 	RepoPaths() RepoPathStore
+	RepoUpdateSchedule() RepoUpdateScheduleStore
 	RolePermissions() RolePermissionStore
 	Roles() RoleStore
 	SavedSearches() SavedSearchStore
@@ -274,6 +275,10 @@ func (d *db) RepoPaths() RepoPathStore {
 	return &repoPathStore{d.Store}
 }
 
+func (d *db) RepoUpdateSchedule() RepoUpdateScheduleStore {
+	return RepoUpdateScheduleWith(d.Store)
+}
+
 func (d *db) RolePermissions() RolePermissionStore {
 	return RolePermissionsWith(d.Store)
 }
@@ -15170,6 +15170,9 @@ type MockDB struct {
 	// RepoStatisticsFunc is an instance of a mock function object
 	// controlling the behavior of the method RepoStatistics.
 	RepoStatisticsFunc *DBRepoStatisticsFunc
+	// RepoUpdateScheduleFunc is an instance of a mock function object
+	// controlling the behavior of the method RepoUpdateSchedule.
+	RepoUpdateScheduleFunc *DBRepoUpdateScheduleFunc
 	// ReposFunc is an instance of a mock function object controlling the
 	// behavior of the method Repos.
 	ReposFunc *DBReposFunc
@@ -15467,6 +15470,11 @@ func NewMockDB() *MockDB {
 				return
 			},
 		},
+		RepoUpdateScheduleFunc: &DBRepoUpdateScheduleFunc{
+			defaultHook: func() (r0 database.RepoUpdateScheduleStore) {
+				return
+			},
+		},
 		ReposFunc: &DBReposFunc{
 			defaultHook: func() (r0 database.RepoStore) {
 				return
@@ -15804,6 +15812,11 @@ func NewStrictMockDB() *MockDB {
 				panic("unexpected invocation of MockDB.RepoStatistics")
 			},
 		},
+		RepoUpdateScheduleFunc: &DBRepoUpdateScheduleFunc{
+			defaultHook: func() database.RepoUpdateScheduleStore {
+				panic("unexpected invocation of MockDB.RepoUpdateSchedule")
+			},
+		},
 		ReposFunc: &DBReposFunc{
 			defaultHook: func() database.RepoStore {
 				panic("unexpected invocation of MockDB.Repos")
@@ -16049,6 +16062,9 @@ func NewMockDBFrom(i database.DB) *MockDB {
 		RepoStatisticsFunc: &DBRepoStatisticsFunc{
 			defaultHook: i.RepoStatistics,
 		},
+		RepoUpdateScheduleFunc: &DBRepoUpdateScheduleFunc{
+			defaultHook: i.RepoUpdateSchedule,
+		},
 		ReposFunc: &DBReposFunc{
 			defaultHook: i.Repos,
 		},
@@ -20711,6 +20727,105 @@ func (c DBRepoStatisticsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// DBRepoUpdateScheduleFunc describes the behavior when the
+// RepoUpdateSchedule method of the parent MockDB instance is invoked.
+type DBRepoUpdateScheduleFunc struct {
+	defaultHook func() database.RepoUpdateScheduleStore
+	hooks       []func() database.RepoUpdateScheduleStore
+	history     []DBRepoUpdateScheduleFuncCall
+	mutex       sync.Mutex
+}
+
+// RepoUpdateSchedule delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockDB) RepoUpdateSchedule() database.RepoUpdateScheduleStore {
+	r0 := m.RepoUpdateScheduleFunc.nextHook()()
+	m.RepoUpdateScheduleFunc.appendCall(DBRepoUpdateScheduleFuncCall{r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the RepoUpdateSchedule
+// method of the parent MockDB instance is invoked and the hook queue is
+// empty.
+func (f *DBRepoUpdateScheduleFunc) SetDefaultHook(hook func() database.RepoUpdateScheduleStore) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// RepoUpdateSchedule method of the parent MockDB instance invokes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *DBRepoUpdateScheduleFunc) PushHook(hook func() database.RepoUpdateScheduleStore) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *DBRepoUpdateScheduleFunc) SetDefaultReturn(r0 database.RepoUpdateScheduleStore) {
+	f.SetDefaultHook(func() database.RepoUpdateScheduleStore {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *DBRepoUpdateScheduleFunc) PushReturn(r0 database.RepoUpdateScheduleStore) {
+	f.PushHook(func() database.RepoUpdateScheduleStore {
+		return r0
+	})
+}
+
+func (f *DBRepoUpdateScheduleFunc) nextHook() func() database.RepoUpdateScheduleStore {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBRepoUpdateScheduleFunc) appendCall(r0 DBRepoUpdateScheduleFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DBRepoUpdateScheduleFuncCall objects
+// describing the invocations of this function.
+func (f *DBRepoUpdateScheduleFunc) History() []DBRepoUpdateScheduleFuncCall {
+	f.mutex.Lock()
+	history := make([]DBRepoUpdateScheduleFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBRepoUpdateScheduleFuncCall is an object that describes an invocation of
+// method RepoUpdateSchedule on an instance of MockDB.
+type DBRepoUpdateScheduleFuncCall struct {
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 database.RepoUpdateScheduleStore
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBRepoUpdateScheduleFuncCall) Args() []interface{} {
+	return []interface{}{}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBRepoUpdateScheduleFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // DBReposFunc describes the behavior when the Repos method of the parent
 // MockDB instance is invoked.
 type DBReposFunc struct {
@@ -49847,6 +49962,9 @@ type MockOwnershipStatsStore struct {
 	// QueryIndividualCountsFunc is an instance of a mock function object
 	// controlling the behavior of the method QueryIndividualCounts.
 	QueryIndividualCountsFunc *OwnershipStatsStoreQueryIndividualCountsFunc
+	// QueryOwnedReposFunc is an instance of a mock function object
+	// controlling the behavior of the method QueryOwnedRepos.
+	QueryOwnedReposFunc *OwnershipStatsStoreQueryOwnedReposFunc
 	// UpdateAggregateCountsFunc is an instance of a mock function object
 	// controlling the behavior of the method UpdateAggregateCounts.
 	UpdateAggregateCountsFunc *OwnershipStatsStoreUpdateAggregateCountsFunc
@@ -49870,6 +49988,11 @@ func NewMockOwnershipStatsStore() *MockOwnershipStatsStore {
 				return
 			},
 		},
+		QueryOwnedReposFunc: &OwnershipStatsStoreQueryOwnedReposFunc{
+			defaultHook: func(context.Context, string) (r0 []api.RepoID, r1 error) {
+				return
+			},
+		},
 		UpdateAggregateCountsFunc: &OwnershipStatsStoreUpdateAggregateCountsFunc{
 			defaultHook: func(context.Context, api.RepoID, database.TreeAggregateStats, time.Time) (r0 int, r1 error) {
 				return
@@ -49898,6 +50021,11 @@ func NewStrictMockOwnershipStatsStore() *MockOwnershipStatsStore {
 				panic("unexpected invocation of MockOwnershipStatsStore.QueryIndividualCounts")
 			},
 		},
+		QueryOwnedReposFunc: &OwnershipStatsStoreQueryOwnedReposFunc{
+			defaultHook: func(context.Context, string) ([]api.RepoID, error) {
+				panic("unexpected invocation of MockOwnershipStatsStore.QueryOwnedRepos")
+			},
+		},
 		UpdateAggregateCountsFunc: &OwnershipStatsStoreUpdateAggregateCountsFunc{
 			defaultHook: func(context.Context, api.RepoID, database.TreeAggregateStats, time.Time) (int, error) {
 				panic("unexpected invocation of MockOwnershipStatsStore.UpdateAggregateCounts")
@@ -49922,6 +50050,9 @@ func NewMockOwnershipStatsStoreFrom(i database.OwnershipStatsStore) *MockOwnersh
 		QueryIndividualCountsFunc: &OwnershipStatsStoreQueryIndividualCountsFunc{
 			defaultHook: i.QueryIndividualCounts,
 		},
+		QueryOwnedReposFunc: &OwnershipStatsStoreQueryOwnedReposFunc{
+			defaultHook: i.QueryOwnedRepos,
+		},
 		UpdateAggregateCountsFunc: &OwnershipStatsStoreUpdateAggregateCountsFunc{
 			defaultHook: i.UpdateAggregateCounts,
 		},
@@ -50158,6 +50289,117 @@ func (c OwnershipStatsStoreQueryIndividualCountsFuncCall) Results() []interface{
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// OwnershipStatsStoreQueryOwnedReposFunc describes the behavior when the
+// QueryOwnedRepos method of the parent MockOwnershipStatsStore instance is
+// invoked.
+type OwnershipStatsStoreQueryOwnedReposFunc struct {
+	defaultHook func(context.Context, string) ([]api.RepoID, error)
+	hooks       []func(context.Context, string) ([]api.RepoID, error)
+	history     []OwnershipStatsStoreQueryOwnedReposFuncCall
+	mutex       sync.Mutex
+}
+
+// QueryOwnedRepos delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockOwnershipStatsStore) QueryOwnedRepos(v0 context.Context, v1 string) ([]api.RepoID, error) {
+	r0, r1 := m.QueryOwnedReposFunc.nextHook()(v0, v1)
+	m.QueryOwnedReposFunc.appendCall(OwnershipStatsStoreQueryOwnedReposFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the QueryOwnedRepos
+// method of the parent MockOwnershipStatsStore instance is invoked and the
+// hook queue is empty.
+func (f *OwnershipStatsStoreQueryOwnedReposFunc) SetDefaultHook(hook func(context.Context, string) ([]api.RepoID, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// QueryOwnedRepos method of the parent MockOwnershipStatsStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *OwnershipStatsStoreQueryOwnedReposFunc) PushHook(hook func(context.Context, string) ([]api.RepoID, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *OwnershipStatsStoreQueryOwnedReposFunc) SetDefaultReturn(r0 []api.RepoID, r1 error) {
+	f.SetDefaultHook(func(context.Context, string) ([]api.RepoID, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *OwnershipStatsStoreQueryOwnedReposFunc) PushReturn(r0 []api.RepoID, r1 error) {
+	f.PushHook(func(context.Context, string) ([]api.RepoID, error) {
+		return r0, r1
+	})
+}
+
+func (f *OwnershipStatsStoreQueryOwnedReposFunc) nextHook() func(context.Context, string) ([]api.RepoID, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *OwnershipStatsStoreQueryOwnedReposFunc) appendCall(r0 OwnershipStatsStoreQueryOwnedReposFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of OwnershipStatsStoreQueryOwnedReposFuncCall
+// objects describing the invocations of this function.
+func (f *OwnershipStatsStoreQueryOwnedReposFunc) History() []OwnershipStatsStoreQueryOwnedReposFuncCall {
+	f.mutex.Lock()
+	history := make([]OwnershipStatsStoreQueryOwnedReposFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// OwnershipStatsStoreQueryOwnedReposFuncCall is an object that describes an
+// invocation of method QueryOwnedRepos on an instance of
+// MockOwnershipStatsStore.
+type OwnershipStatsStoreQueryOwnedReposFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []api.RepoID
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c OwnershipStatsStoreQueryOwnedReposFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c OwnershipStatsStoreQueryOwnedReposFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // OwnershipStatsStoreUpdateAggregateCountsFunc describes the behavior when
 // the UpdateAggregateCounts method of the parent MockOwnershipStatsStore
 // instance is invoked.
@@ -59082,6 +59324,10 @@ type MockRepoCommitsChangelistsStore struct {
 	// mock function object controlling the behavior of the method
 	// BatchInsertCommitSHAsWithPerforceChangelistID.
 	BatchInsertCommitSHAsWithPerforceChangelistIDFunc *RepoCommitsChangelistsStoreBatchInsertCommitSHAsWithPerforceChangelistIDFunc
+	// GetChangelistIDsForCommitsFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// GetChangelistIDsForCommits.
+	GetChangelistIDsForCommitsFunc *RepoCommitsChangelistsStoreGetChangelistIDsForCommitsFunc
 	// GetLatestForRepoFunc is an instance of a mock function object
 	// controlling the behavior of the method GetLatestForRepo.
 	GetLatestForRepoFunc *RepoCommitsChangelistsStoreGetLatestForRepoFunc
@@ -59100,6 +59346,11 @@ func NewMockRepoCommitsChangelistsStore() *MockRepoCommitsChangelistsStore {
 				return
 			},
 		},
+		GetChangelistIDsForCommitsFunc: &RepoCommitsChangelistsStoreGetChangelistIDsForCommitsFunc{
+			defaultHook: func(context.Context, api.RepoID, []api.CommitID) (r0 map[api.CommitID]int64, r1 error) {
+				return
+			},
+		},
 		GetLatestForRepoFunc: &RepoCommitsChangelistsStoreGetLatestForRepoFunc{
 			defaultHook: func(context.Context, api.RepoID) (r0 *types.RepoCommit, r1 error) {
 				return
@@ -59123,6 +59374,11 @@ func NewStrictMockRepoCommitsChangelistsStore() *MockRepoCommitsChangelistsStore
 				panic("unexpected invocation of MockRepoCommitsChangelistsStore.BatchInsertCommitSHAsWithPerforceChangelistID")
 			},
 		},
+		GetChangelistIDsForCommitsFunc: &RepoCommitsChangelistsStoreGetChangelistIDsForCommitsFunc{
+			defaultHook: func(context.Context, api.RepoID, []api.CommitID) (map[api.CommitID]int64, error) {
+				panic("unexpected invocation of MockRepoCommitsChangelistsStore.GetChangelistIDsForCommits")
+			},
+		},
 		GetLatestForRepoFunc: &RepoCommitsChangelistsStoreGetLatestForRepoFunc{
 			defaultHook: func(context.Context, api.RepoID) (*types.RepoCommit, error) {
 				panic("unexpected invocation of MockRepoCommitsChangelistsStore.GetLatestForRepo")
@@ -59144,6 +59400,9 @@ func NewMockRepoCommitsChangelistsStoreFrom(i database.RepoCommitsChangelistsSto
 		BatchInsertCommitSHAsWithPerforceChangelistIDFunc: &RepoCommitsChangelistsStoreBatchInsertCommitSHAsWithPerforceChangelistIDFunc{
 			defaultHook: i.BatchInsertCommitSHAsWithPerforceChangelistID,
 		},
+		GetChangelistIDsForCommitsFunc: &RepoCommitsChangelistsStoreGetChangelistIDsForCommitsFunc{
+			defaultHook: i.GetChangelistIDsForCommits,
+		},
 		GetLatestForRepoFunc: &RepoCommitsChangelistsStoreGetLatestForRepoFunc{
 			defaultHook: i.GetLatestForRepo,
 		},
@@ -59269,6 +59528,122 @@ func (c RepoCommitsChangelistsStoreBatchInsertCommitSHAsWithPerforceChangelistID
 	return []interface{}{c.Result0}
 }
 
+// RepoCommitsChangelistsStoreGetChangelistIDsForCommitsFunc describes the
+// behavior when the GetChangelistIDsForCommits method of the parent
+// MockRepoCommitsChangelistsStore instance is invoked.
+type RepoCommitsChangelistsStoreGetChangelistIDsForCommitsFunc struct {
+	defaultHook func(context.Context, api.RepoID, []api.CommitID) (map[api.CommitID]int64, error)
+	hooks       []func(context.Context, api.RepoID, []api.CommitID) (map[api.CommitID]int64, error)
+	history     []RepoCommitsChangelistsStoreGetChangelistIDsForCommitsFuncCall
+	mutex       sync.Mutex
+}
+
+// GetChangelistIDsForCommits delegates to the next hook function in the
+// queue and stores the parameter and result values of this invocation.
+func (m *MockRepoCommitsChangelistsStore) GetChangelistIDsForCommits(v0 context.Context, v1 api.RepoID, v2 []api.CommitID) (map[api.CommitID]int64, error) {
+	r0, r1 := m.GetChangelistIDsForCommitsFunc.nextHook()(v0, v1, v2)
+	m.GetChangelistIDsForCommitsFunc.appendCall(RepoCommitsChangelistsStoreGetChangelistIDsForCommitsFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// GetChangelistIDsForCommits method of the parent
+// MockRepoCommitsChangelistsStore instance is invoked and the hook queue is
+// empty.
+func (f *RepoCommitsChangelistsStoreGetChangelistIDsForCommitsFunc) SetDefaultHook(hook func(context.Context, api.RepoID, []api.CommitID) (map[api.CommitID]int64, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetChangelistIDsForCommits method of the parent
+// MockRepoCommitsChangelistsStore instance invokes the hook at the front of
+// the queue and discards it. After the queue is empty, the default hook
+// function is invoked for any future action.
+func (f *RepoCommitsChangelistsStoreGetChangelistIDsForCommitsFunc) PushHook(hook func(context.Context, api.RepoID, []api.CommitID) (map[api.CommitID]int64, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *RepoCommitsChangelistsStoreGetChangelistIDsForCommitsFunc) SetDefaultReturn(r0 map[api.CommitID]int64, r1 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoID, []api.CommitID) (map[api.CommitID]int64, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *RepoCommitsChangelistsStoreGetChangelistIDsForCommitsFunc) PushReturn(r0 map[api.CommitID]int64, r1 error) {
+	f.PushHook(func(context.Context, api.RepoID, []api.CommitID) (map[api.CommitID]int64, error) {
+		return r0, r1
+	})
+}
+
+func (f *RepoCommitsChangelistsStoreGetChangelistIDsForCommitsFunc) nextHook() func(context.Context, api.RepoID, []api.CommitID) (map[api.CommitID]int64, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *RepoCommitsChangelistsStoreGetChangelistIDsForCommitsFunc) appendCall(r0 RepoCommitsChangelistsStoreGetChangelistIDsForCommitsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// RepoCommitsChangelistsStoreGetChangelistIDsForCommitsFuncCall objects
+// describing the invocations of this function.
+func (f *RepoCommitsChangelistsStoreGetChangelistIDsForCommitsFunc) History() []RepoCommitsChangelistsStoreGetChangelistIDsForCommitsFuncCall {
+	f.mutex.Lock()
+	history := make([]RepoCommitsChangelistsStoreGetChangelistIDsForCommitsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// RepoCommitsChangelistsStoreGetChangelistIDsForCommitsFuncCall is an
+// object that describes an invocation of method GetChangelistIDsForCommits
+// on an instance of MockRepoCommitsChangelistsStore.
+type RepoCommitsChangelistsStoreGetChangelistIDsForCommitsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoID
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 []api.CommitID
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 map[api.CommitID]int64
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c RepoCommitsChangelistsStoreGetChangelistIDsForCommitsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c RepoCommitsChangelistsStoreGetChangelistIDsForCommitsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // RepoCommitsChangelistsStoreGetLatestForRepoFunc describes the behavior
 // when the GetLatestForRepo method of the parent
 // MockRepoCommitsChangelistsStore instance is invoked.
@@ -63563,6 +63938,896 @@ func (c RepoStoreWithFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// MockRepoUpdateScheduleStore is a mock implementation of the
+// RepoUpdateScheduleStore interface (from the package
+// github.com/sourcegraph/sourcegraph/internal/database) used for unit
+// testing.
+type MockRepoUpdateScheduleStore struct {
+	// DeleteFunc is an instance of a mock function object controlling the
+	// behavior of the method Delete.
+	DeleteFunc *RepoUpdateScheduleStoreDeleteFunc
+	// HandleFunc is an instance of a mock function object controlling the
+	// behavior of the method Handle.
+	HandleFunc *RepoUpdateScheduleStoreHandleFunc
+	// ListFunc is an instance of a mock function object controlling the
+	// behavior of the method List.
+	ListFunc *RepoUpdateScheduleStoreListFunc
+	// ListWebhooksSinceFunc is an instance of a mock function object
+	// controlling the behavior of the method ListWebhooksSince.
+	ListWebhooksSinceFunc *RepoUpdateScheduleStoreListWebhooksSinceFunc
+	// RecordWebhookFunc is an instance of a mock function object
+	// controlling the behavior of the method RecordWebhook.
+	RecordWebhookFunc *RepoUpdateScheduleStoreRecordWebhookFunc
+	// UpsertFunc is an instance of a mock function object controlling the
+	// behavior of the method Upsert.
+	UpsertFunc *RepoUpdateScheduleStoreUpsertFunc
+	// WithFunc is an instance of a mock function object controlling the
+	// behavior of the method With.
+	WithFunc *RepoUpdateScheduleStoreWithFunc
+}
+
+// NewMockRepoUpdateScheduleStore creates a new mock of the
+// RepoUpdateScheduleStore interface. All methods return zero values for all
+// results, unless overwritten.
+func NewMockRepoUpdateScheduleStore() *MockRepoUpdateScheduleStore {
+	return &MockRepoUpdateScheduleStore{
+		DeleteFunc: &RepoUpdateScheduleStoreDeleteFunc{
+			defaultHook: func(context.Context, ...api.RepoID) (r0 error) {
+				return
+			},
+		},
+		HandleFunc: &RepoUpdateScheduleStoreHandleFunc{
+			defaultHook: func() (r0 basestore.TransactableHandle) {
+				return
+			},
+		},
+		ListFunc: &RepoUpdateScheduleStoreListFunc{
+			defaultHook: func(context.Context) (r0 []database.RepoUpdateScheduleEntry, r1 error) {
+				return
+			},
+		},
+		ListWebhooksSinceFunc: &RepoUpdateScheduleStoreListWebhooksSinceFunc{
+			defaultHook: func(context.Context, time.Time) (r0 map[api.RepoID]time.Time, r1 error) {
+				return
+			},
+		},
+		RecordWebhookFunc: &RepoUpdateScheduleStoreRecordWebhookFunc{
+			defaultHook: func(context.Context, api.RepoID) (r0 error) {
+				return
+			},
+		},
+		UpsertFunc: &RepoUpdateScheduleStoreUpsertFunc{
+			defaultHook: func(context.Context, []database.RepoUpdateScheduleEntry) (r0 error) {
+				return
+			},
+		},
+		WithFunc: &RepoUpdateScheduleStoreWithFunc{
+			defaultHook: func(basestore.ShareableStore) (r0 database.RepoUpdateScheduleStore) {
+				return
+			},
+		},
+	}
+}
+
+// NewStrictMockRepoUpdateScheduleStore creates a new mock of the
+// RepoUpdateScheduleStore interface. All methods panic on invocation,
+// unless overwritten.
+func NewStrictMockRepoUpdateScheduleStore() *MockRepoUpdateScheduleStore {
+	return &MockRepoUpdateScheduleStore{
+		DeleteFunc: &RepoUpdateScheduleStoreDeleteFunc{
+			defaultHook: func(context.Context, ...api.RepoID) error {
+				panic("unexpected invocation of MockRepoUpdateScheduleStore.Delete")
+			},
+		},
+		HandleFunc: &RepoUpdateScheduleStoreHandleFunc{
+			defaultHook: func() basestore.TransactableHandle {
+				panic("unexpected invocation of MockRepoUpdateScheduleStore.Handle")
+			},
+		},
+		ListFunc: &RepoUpdateScheduleStoreListFunc{
+			defaultHook: func(context.Context) ([]database.RepoUpdateScheduleEntry, error) {
+				panic("unexpected invocation of MockRepoUpdateScheduleStore.List")
+			},
+		},
+		ListWebhooksSinceFunc: &RepoUpdateScheduleStoreListWebhooksSinceFunc{
+			defaultHook: func(context.Context, time.Time) (map[api.RepoID]time.Time, error) {
+				panic("unexpected invocation of MockRepoUpdateScheduleStore.ListWebhooksSince")
+			},
+		},
+		RecordWebhookFunc: &RepoUpdateScheduleStoreRecordWebhookFunc{
+			defaultHook: func(context.Context, api.RepoID) error {
+				panic("unexpected invocation of MockRepoUpdateScheduleStore.RecordWebhook")
+			},
+		},
+		UpsertFunc: &RepoUpdateScheduleStoreUpsertFunc{
+			defaultHook: func(context.Context, []database.RepoUpdateScheduleEntry) error {
+				panic("unexpected invocation of MockRepoUpdateScheduleStore.Upsert")
+			},
+		},
+		WithFunc: &RepoUpdateScheduleStoreWithFunc{
+			defaultHook: func(basestore.ShareableStore) database.RepoUpdateScheduleStore {
+				panic("unexpected invocation of MockRepoUpdateScheduleStore.With")
+			},
+		},
+	}
+}
+
+// NewMockRepoUpdateScheduleStoreFrom creates a new mock of the
+// MockRepoUpdateScheduleStore interface. All methods delegate to the given
+// implementation, unless overwritten.
+func NewMockRepoUpdateScheduleStoreFrom(i database.RepoUpdateScheduleStore) *MockRepoUpdateScheduleStore {
+	return &MockRepoUpdateScheduleStore{
+		DeleteFunc: &RepoUpdateScheduleStoreDeleteFunc{
+			defaultHook: i.Delete,
+		},
+		HandleFunc: &RepoUpdateScheduleStoreHandleFunc{
+			defaultHook: i.Handle,
+		},
+		ListFunc: &RepoUpdateScheduleStoreListFunc{
+			defaultHook: i.List,
+		},
+		ListWebhooksSinceFunc: &RepoUpdateScheduleStoreListWebhooksSinceFunc{
+			defaultHook: i.ListWebhooksSince,
+		},
+		RecordWebhookFunc: &RepoUpdateScheduleStoreRecordWebhookFunc{
+			defaultHook: i.RecordWebhook,
+		},
+		UpsertFunc: &RepoUpdateScheduleStoreUpsertFunc{
+			defaultHook: i.Upsert,
+		},
+		WithFunc: &RepoUpdateScheduleStoreWithFunc{
+			defaultHook: i.With,
+		},
+	}
+}
+
+// RepoUpdateScheduleStoreDeleteFunc describes the behavior when the Delete
+// method of the parent MockRepoUpdateScheduleStore instance is invoked.
+type RepoUpdateScheduleStoreDeleteFunc struct {
+	defaultHook func(context.Context, ...api.RepoID) error
+	hooks       []func(context.Context, ...api.RepoID) error
+	history     []RepoUpdateScheduleStoreDeleteFuncCall
+	mutex       sync.Mutex
+}
+
+// Delete delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockRepoUpdateScheduleStore) Delete(v0 context.Context, v1 ...api.RepoID) error {
+	r0 := m.DeleteFunc.nextHook()(v0, v1...)
+	m.DeleteFunc.appendCall(RepoUpdateScheduleStoreDeleteFuncCall{v0, v1, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the Delete method of the
+// parent MockRepoUpdateScheduleStore instance is invoked and the hook queue
+// is empty.
+func (f *RepoUpdateScheduleStoreDeleteFunc) SetDefaultHook(hook func(context.Context, ...api.RepoID) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Delete method of the parent MockRepoUpdateScheduleStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *RepoUpdateScheduleStoreDeleteFunc) PushHook(hook func(context.Context, ...api.RepoID) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *RepoUpdateScheduleStoreDeleteFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, ...api.RepoID) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *RepoUpdateScheduleStoreDeleteFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, ...api.RepoID) error {
+		return r0
+	})
+}
+
+func (f *RepoUpdateScheduleStoreDeleteFunc) nextHook() func(context.Context, ...api.RepoID) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *RepoUpdateScheduleStoreDeleteFunc) appendCall(r0 RepoUpdateScheduleStoreDeleteFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of RepoUpdateScheduleStoreDeleteFuncCall
+// objects describing the invocations of this function.
+func (f *RepoUpdateScheduleStoreDeleteFunc) History() []RepoUpdateScheduleStoreDeleteFuncCall {
+	f.mutex.Lock()
+	history := make([]RepoUpdateScheduleStoreDeleteFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// RepoUpdateScheduleStoreDeleteFuncCall is an object that describes an
+// invocation of method Delete on an instance of
+// MockRepoUpdateScheduleStore.
+type RepoUpdateScheduleStoreDeleteFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is a slice containing the values of the variadic arguments
+	// passed to this method invocation.
+	Arg1 []api.RepoID
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation. The variadic slice argument is flattened in this array such
+// that one positional argument and three variadic arguments would result in
+// a slice of four, not two.
+func (c RepoUpdateScheduleStoreDeleteFuncCall) Args() []interface{} {
+	trailing := []interface{}{}
+	for _, val := range c.Arg1 {
+		trailing = append(trailing, val)
+	}
+
+	return append([]interface{}{c.Arg0}, trailing...)
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c RepoUpdateScheduleStoreDeleteFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// RepoUpdateScheduleStoreHandleFunc describes the behavior when the Handle
+// method of the parent MockRepoUpdateScheduleStore instance is invoked.
+type RepoUpdateScheduleStoreHandleFunc struct {
+	defaultHook func() basestore.TransactableHandle
+	hooks       []func() basestore.TransactableHandle
+	history     []RepoUpdateScheduleStoreHandleFuncCall
+	mutex       sync.Mutex
+}
+
+// Handle delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockRepoUpdateScheduleStore) Handle() basestore.TransactableHandle {
+	r0 := m.HandleFunc.nextHook()()
+	m.HandleFunc.appendCall(RepoUpdateScheduleStoreHandleFuncCall{r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the Handle method of the
+// parent MockRepoUpdateScheduleStore instance is invoked and the hook queue
+// is empty.
+func (f *RepoUpdateScheduleStoreHandleFunc) SetDefaultHook(hook func() basestore.TransactableHandle) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Handle method of the parent MockRepoUpdateScheduleStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *RepoUpdateScheduleStoreHandleFunc) PushHook(hook func() basestore.TransactableHandle) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *RepoUpdateScheduleStoreHandleFunc) SetDefaultReturn(r0 basestore.TransactableHandle) {
+	f.SetDefaultHook(func() basestore.TransactableHandle {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *RepoUpdateScheduleStoreHandleFunc) PushReturn(r0 basestore.TransactableHandle) {
+	f.PushHook(func() basestore.TransactableHandle {
+		return r0
+	})
+}
+
+func (f *RepoUpdateScheduleStoreHandleFunc) nextHook() func() basestore.TransactableHandle {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *RepoUpdateScheduleStoreHandleFunc) appendCall(r0 RepoUpdateScheduleStoreHandleFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of RepoUpdateScheduleStoreHandleFuncCall
+// objects describing the invocations of this function.
+func (f *RepoUpdateScheduleStoreHandleFunc) History() []RepoUpdateScheduleStoreHandleFuncCall {
+	f.mutex.Lock()
+	history := make([]RepoUpdateScheduleStoreHandleFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// RepoUpdateScheduleStoreHandleFuncCall is an object that describes an
+// invocation of method Handle on an instance of
+// MockRepoUpdateScheduleStore.
+type RepoUpdateScheduleStoreHandleFuncCall struct {
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 basestore.TransactableHandle
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c RepoUpdateScheduleStoreHandleFuncCall) Args() []interface{} {
+	return []interface{}{}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c RepoUpdateScheduleStoreHandleFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// RepoUpdateScheduleStoreListFunc describes the behavior when the List
+// method of the parent MockRepoUpdateScheduleStore instance is invoked.
+type RepoUpdateScheduleStoreListFunc struct {
+	defaultHook func(context.Context) ([]database.RepoUpdateScheduleEntry, error)
+	hooks       []func(context.Context) ([]database.RepoUpdateScheduleEntry, error)
+	history     []RepoUpdateScheduleStoreListFuncCall
+	mutex       sync.Mutex
+}
+
+// List delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockRepoUpdateScheduleStore) List(v0 context.Context) ([]database.RepoUpdateScheduleEntry, error) {
+	r0, r1 := m.ListFunc.nextHook()(v0)
+	m.ListFunc.appendCall(RepoUpdateScheduleStoreListFuncCall{v0, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the List method of the
+// parent MockRepoUpdateScheduleStore instance is invoked and the hook queue
+// is empty.
+func (f *RepoUpdateScheduleStoreListFunc) SetDefaultHook(hook func(context.Context) ([]database.RepoUpdateScheduleEntry, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// List method of the parent MockRepoUpdateScheduleStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *RepoUpdateScheduleStoreListFunc) PushHook(hook func(context.Context) ([]database.RepoUpdateScheduleEntry, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *RepoUpdateScheduleStoreListFunc) SetDefaultReturn(r0 []database.RepoUpdateScheduleEntry, r1 error) {
+	f.SetDefaultHook(func(context.Context) ([]database.RepoUpdateScheduleEntry, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *RepoUpdateScheduleStoreListFunc) PushReturn(r0 []database.RepoUpdateScheduleEntry, r1 error) {
+	f.PushHook(func(context.Context) ([]database.RepoUpdateScheduleEntry, error) {
+		return r0, r1
+	})
+}
+
+func (f *RepoUpdateScheduleStoreListFunc) nextHook() func(context.Context) ([]database.RepoUpdateScheduleEntry, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *RepoUpdateScheduleStoreListFunc) appendCall(r0 RepoUpdateScheduleStoreListFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of RepoUpdateScheduleStoreListFuncCall objects
+// describing the invocations of this function.
+func (f *RepoUpdateScheduleStoreListFunc) History() []RepoUpdateScheduleStoreListFuncCall {
+	f.mutex.Lock()
+	history := make([]RepoUpdateScheduleStoreListFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// RepoUpdateScheduleStoreListFuncCall is an object that describes an
+// invocation of method List on an instance of MockRepoUpdateScheduleStore.
+type RepoUpdateScheduleStoreListFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []database.RepoUpdateScheduleEntry
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c RepoUpdateScheduleStoreListFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c RepoUpdateScheduleStoreListFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// RepoUpdateScheduleStoreListWebhooksSinceFunc describes the behavior when
+// the ListWebhooksSince method of the parent MockRepoUpdateScheduleStore
+// instance is invoked.
+type RepoUpdateScheduleStoreListWebhooksSinceFunc struct {
+	defaultHook func(context.Context, time.Time) (map[api.RepoID]time.Time, error)
+	hooks       []func(context.Context, time.Time) (map[api.RepoID]time.Time, error)
+	history     []RepoUpdateScheduleStoreListWebhooksSinceFuncCall
+	mutex       sync.Mutex
+}
+
+// ListWebhooksSince delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockRepoUpdateScheduleStore) ListWebhooksSince(v0 context.Context, v1 time.Time) (map[api.RepoID]time.Time, error) {
+	r0, r1 := m.ListWebhooksSinceFunc.nextHook()(v0, v1)
+	m.ListWebhooksSinceFunc.appendCall(RepoUpdateScheduleStoreListWebhooksSinceFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the ListWebhooksSince
+// method of the parent MockRepoUpdateScheduleStore instance is invoked and
+// the hook queue is empty.
+func (f *RepoUpdateScheduleStoreListWebhooksSinceFunc) SetDefaultHook(hook func(context.Context, time.Time) (map[api.RepoID]time.Time, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ListWebhooksSince method of the parent MockRepoUpdateScheduleStore
+// instance invokes the hook at the front of the queue and discards it.
+// After the queue is empty, the default hook function is invoked for any
+// future action.
+func (f *RepoUpdateScheduleStoreListWebhooksSinceFunc) PushHook(hook func(context.Context, time.Time) (map[api.RepoID]time.Time, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *RepoUpdateScheduleStoreListWebhooksSinceFunc) SetDefaultReturn(r0 map[api.RepoID]time.Time, r1 error) {
+	f.SetDefaultHook(func(context.Context, time.Time) (map[api.RepoID]time.Time, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *RepoUpdateScheduleStoreListWebhooksSinceFunc) PushReturn(r0 map[api.RepoID]time.Time, r1 error) {
+	f.PushHook(func(context.Context, time.Time) (map[api.RepoID]time.Time, error) {
+		return r0, r1
+	})
+}
+
+func (f *RepoUpdateScheduleStoreListWebhooksSinceFunc) nextHook() func(context.Context, time.Time) (map[api.RepoID]time.Time, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *RepoUpdateScheduleStoreListWebhooksSinceFunc) appendCall(r0 RepoUpdateScheduleStoreListWebhooksSinceFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// RepoUpdateScheduleStoreListWebhooksSinceFuncCall objects describing the
+// invocations of this function.
+func (f *RepoUpdateScheduleStoreListWebhooksSinceFunc) History() []RepoUpdateScheduleStoreListWebhooksSinceFuncCall {
+	f.mutex.Lock()
+	history := make([]RepoUpdateScheduleStoreListWebhooksSinceFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// RepoUpdateScheduleStoreListWebhooksSinceFuncCall is an object that
+// describes an invocation of method ListWebhooksSince on an instance of
+// MockRepoUpdateScheduleStore.
+type RepoUpdateScheduleStoreListWebhooksSinceFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 time.Time
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 map[api.RepoID]time.Time
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c RepoUpdateScheduleStoreListWebhooksSinceFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c RepoUpdateScheduleStoreListWebhooksSinceFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// RepoUpdateScheduleStoreRecordWebhookFunc describes the behavior when the
+// RecordWebhook method of the parent MockRepoUpdateScheduleStore instance
+// is invoked.
+type RepoUpdateScheduleStoreRecordWebhookFunc struct {
+	defaultHook func(context.Context, api.RepoID) error
+	hooks       []func(context.Context, api.RepoID) error
+	history     []RepoUpdateScheduleStoreRecordWebhookFuncCall
+	mutex       sync.Mutex
+}
+
+// RecordWebhook delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockRepoUpdateScheduleStore) RecordWebhook(v0 context.Context, v1 api.RepoID) error {
+	r0 := m.RecordWebhookFunc.nextHook()(v0, v1)
+	m.RecordWebhookFunc.appendCall(RepoUpdateScheduleStoreRecordWebhookFuncCall{v0, v1, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the RecordWebhook method
+// of the parent MockRepoUpdateScheduleStore instance is invoked and the
+// hook queue is empty.
+func (f *RepoUpdateScheduleStoreRecordWebhookFunc) SetDefaultHook(hook func(context.Context, api.RepoID) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// RecordWebhook method of the parent MockRepoUpdateScheduleStore instance
+// invokes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *RepoUpdateScheduleStoreRecordWebhookFunc) PushHook(hook func(context.Context, api.RepoID) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *RepoUpdateScheduleStoreRecordWebhookFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, api.RepoID) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *RepoUpdateScheduleStoreRecordWebhookFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, api.RepoID) error {
+		return r0
+	})
+}
+
+func (f *RepoUpdateScheduleStoreRecordWebhookFunc) nextHook() func(context.Context, api.RepoID) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *RepoUpdateScheduleStoreRecordWebhookFunc) appendCall(r0 RepoUpdateScheduleStoreRecordWebhookFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// RepoUpdateScheduleStoreRecordWebhookFuncCall objects describing the
+// invocations of this function.
+func (f *RepoUpdateScheduleStoreRecordWebhookFunc) History() []RepoUpdateScheduleStoreRecordWebhookFuncCall {
+	f.mutex.Lock()
+	history := make([]RepoUpdateScheduleStoreRecordWebhookFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// RepoUpdateScheduleStoreRecordWebhookFuncCall is an object that describes
+// an invocation of method RecordWebhook on an instance of
+// MockRepoUpdateScheduleStore.
+type RepoUpdateScheduleStoreRecordWebhookFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 api.RepoID
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c RepoUpdateScheduleStoreRecordWebhookFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c RepoUpdateScheduleStoreRecordWebhookFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// RepoUpdateScheduleStoreUpsertFunc describes the behavior when the Upsert
+// method of the parent MockRepoUpdateScheduleStore instance is invoked.
+type RepoUpdateScheduleStoreUpsertFunc struct {
+	defaultHook func(context.Context, []database.RepoUpdateScheduleEntry) error
+	hooks       []func(context.Context, []database.RepoUpdateScheduleEntry) error
+	history     []RepoUpdateScheduleStoreUpsertFuncCall
+	mutex       sync.Mutex
+}
+
+// Upsert delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockRepoUpdateScheduleStore) Upsert(v0 context.Context, v1 []database.RepoUpdateScheduleEntry) error {
+	r0 := m.UpsertFunc.nextHook()(v0, v1)
+	m.UpsertFunc.appendCall(RepoUpdateScheduleStoreUpsertFuncCall{v0, v1, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the Upsert method of the
+// parent MockRepoUpdateScheduleStore instance is invoked and the hook queue
+// is empty.
+func (f *RepoUpdateScheduleStoreUpsertFunc) SetDefaultHook(hook func(context.Context, []database.RepoUpdateScheduleEntry) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Upsert method of the parent MockRepoUpdateScheduleStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *RepoUpdateScheduleStoreUpsertFunc) PushHook(hook func(context.Context, []database.RepoUpdateScheduleEntry) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *RepoUpdateScheduleStoreUpsertFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, []database.RepoUpdateScheduleEntry) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *RepoUpdateScheduleStoreUpsertFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, []database.RepoUpdateScheduleEntry) error {
+		return r0
+	})
+}
+
+func (f *RepoUpdateScheduleStoreUpsertFunc) nextHook() func(context.Context, []database.RepoUpdateScheduleEntry) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *RepoUpdateScheduleStoreUpsertFunc) appendCall(r0 RepoUpdateScheduleStoreUpsertFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of RepoUpdateScheduleStoreUpsertFuncCall
+// objects describing the invocations of this function.
+func (f *RepoUpdateScheduleStoreUpsertFunc) History() []RepoUpdateScheduleStoreUpsertFuncCall {
+	f.mutex.Lock()
+	history := make([]RepoUpdateScheduleStoreUpsertFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// RepoUpdateScheduleStoreUpsertFuncCall is an object that describes an
+// invocation of method Upsert on an instance of
+// MockRepoUpdateScheduleStore.
+type RepoUpdateScheduleStoreUpsertFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 []database.RepoUpdateScheduleEntry
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c RepoUpdateScheduleStoreUpsertFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c RepoUpdateScheduleStoreUpsertFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// RepoUpdateScheduleStoreWithFunc describes the behavior when the With
+// method of the parent MockRepoUpdateScheduleStore instance is invoked.
+type RepoUpdateScheduleStoreWithFunc struct {
+	defaultHook func(basestore.ShareableStore) database.RepoUpdateScheduleStore
+	hooks       []func(basestore.ShareableStore) database.RepoUpdateScheduleStore
+	history     []RepoUpdateScheduleStoreWithFuncCall
+	mutex       sync.Mutex
+}
+
+// With delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockRepoUpdateScheduleStore) With(v0 basestore.ShareableStore) database.RepoUpdateScheduleStore {
+	r0 := m.WithFunc.nextHook()(v0)
+	m.WithFunc.appendCall(RepoUpdateScheduleStoreWithFuncCall{v0, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the With method of the
+// parent MockRepoUpdateScheduleStore instance is invoked and the hook queue
+// is empty.
+func (f *RepoUpdateScheduleStoreWithFunc) SetDefaultHook(hook func(basestore.ShareableStore) database.RepoUpdateScheduleStore) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// With method of the parent MockRepoUpdateScheduleStore instance invokes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *RepoUpdateScheduleStoreWithFunc) PushHook(hook func(basestore.ShareableStore) database.RepoUpdateScheduleStore) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *RepoUpdateScheduleStoreWithFunc) SetDefaultReturn(r0 database.RepoUpdateScheduleStore) {
+	f.SetDefaultHook(func(basestore.ShareableStore) database.RepoUpdateScheduleStore {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *RepoUpdateScheduleStoreWithFunc) PushReturn(r0 database.RepoUpdateScheduleStore) {
+	f.PushHook(func(basestore.ShareableStore) database.RepoUpdateScheduleStore {
+		return r0
+	})
+}
+
+func (f *RepoUpdateScheduleStoreWithFunc) nextHook() func(basestore.ShareableStore) database.RepoUpdateScheduleStore {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *RepoUpdateScheduleStoreWithFunc) appendCall(r0 RepoUpdateScheduleStoreWithFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of RepoUpdateScheduleStoreWithFuncCall objects
+// describing the invocations of this function.
+func (f *RepoUpdateScheduleStoreWithFunc) History() []RepoUpdateScheduleStoreWithFuncCall {
+	f.mutex.Lock()
+	history := make([]RepoUpdateScheduleStoreWithFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// RepoUpdateScheduleStoreWithFuncCall is an object that describes an
+// invocation of method With on an instance of MockRepoUpdateScheduleStore.
+type RepoUpdateScheduleStoreWithFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 basestore.ShareableStore
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 database.RepoUpdateScheduleStore
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c RepoUpdateScheduleStoreWithFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c RepoUpdateScheduleStoreWithFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // MockRolePermissionStore is a mock implementation of the
 // RolePermissionStore interface (from the package
 // github.com/sourcegraph/sourcegraph/internal/database) used for unit
@@ -67777,9 +69042,21 @@ type MockSearchContextsStore struct {
 	// HandleFunc is an instance of a mock function object controlling the
 	// behavior of the method Handle.
 	HandleFunc *SearchContextsStoreHandleFunc
+	// ListDynamicSearchContextsFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// ListDynamicSearchContexts.
+	ListDynamicSearchContextsFunc *SearchContextsStoreListDynamicSearchContextsFunc
 	// ListSearchContextsFunc is an instance of a mock function object
 	// controlling the behavior of the method ListSearchContexts.
 	ListSearchContextsFunc *SearchContextsStoreListSearchContextsFunc
+	// MarkDynamicSearchContextEvaluatedFunc is an instance of a mock
+	// function object controlling the behavior of the method
+	// MarkDynamicSearchContextEvaluated.
+	MarkDynamicSearchContextEvaluatedFunc *SearchContextsStoreMarkDynamicSearchContextEvaluatedFunc
+	// SetDynamicSearchContextRepositoriesFunc is an instance of a mock
+	// function object controlling the behavior of the method
+	// SetDynamicSearchContextRepositories.
+	SetDynamicSearchContextRepositoriesFunc *SearchContextsStoreSetDynamicSearchContextRepositoriesFunc
 	// SetSearchContextRepositoryRevisionsFunc is an instance of a mock
 	// function object controlling the behavior of the method
 	// SetSearchContextRepositoryRevisions.
@@ -67867,11 +69144,26 @@ func NewMockSearchContextsStore() *MockSearchContextsStore {
 				return
 			},
 		},
+		ListDynamicSearchContextsFunc: &SearchContextsStoreListDynamicSearchContextsFunc{
+			defaultHook: func(context.Context, time.Time, int32) (r0 []*types.SearchContext, r1 error) {
+				return
+			},
+		},
 		ListSearchContextsFunc: &SearchContextsStoreListSearchContextsFunc{
 			defaultHook: func(context.Context, database.ListSearchContextsPageOptions, database.ListSearchContextsOptions) (r0 []*types.SearchContext, r1 error) {
 				return
 			},
 		},
+		MarkDynamicSearchContextEvaluatedFunc: &SearchContextsStoreMarkDynamicSearchContextEvaluatedFunc{
+			defaultHook: func(context.Context, int64) (r0 error) {
+				return
+			},
+		},
+		SetDynamicSearchContextRepositoriesFunc: &SearchContextsStoreSetDynamicSearchContextRepositoriesFunc{
+			defaultHook: func(context.Context, int64, []api.RepoID) (r0 error) {
+				return
+			},
+		},
 		SetSearchContextRepositoryRevisionsFunc: &SearchContextsStoreSetSearchContextRepositoryRevisionsFunc{
 			defaultHook: func(context.Context, int64, []*types.SearchContextRepositoryRevisions) (r0 error) {
 				return
@@ -67965,11 +69257,26 @@ func NewStrictMockSearchContextsStore() *MockSearchContextsStore {
 				panic("unexpected invocation of MockSearchContextsStore.Handle")
 			},
 		},
+		ListDynamicSearchContextsFunc: &SearchContextsStoreListDynamicSearchContextsFunc{
+			defaultHook: func(context.Context, time.Time, int32) ([]*types.SearchContext, error) {
+				panic("unexpected invocation of MockSearchContextsStore.ListDynamicSearchContexts")
+			},
+		},
 		ListSearchContextsFunc: &SearchContextsStoreListSearchContextsFunc{
 			defaultHook: func(context.Context, database.ListSearchContextsPageOptions, database.ListSearchContextsOptions) ([]*types.SearchContext, error) {
 				panic("unexpected invocation of MockSearchContextsStore.ListSearchContexts")
 			},
 		},
+		MarkDynamicSearchContextEvaluatedFunc: &SearchContextsStoreMarkDynamicSearchContextEvaluatedFunc{
+			defaultHook: func(context.Context, int64) error {
+				panic("unexpected invocation of MockSearchContextsStore.MarkDynamicSearchContextEvaluated")
+			},
+		},
+		SetDynamicSearchContextRepositoriesFunc: &SearchContextsStoreSetDynamicSearchContextRepositoriesFunc{
+			defaultHook: func(context.Context, int64, []api.RepoID) error {
+				panic("unexpected invocation of MockSearchContextsStore.SetDynamicSearchContextRepositories")
+			},
+		},
 		SetSearchContextRepositoryRevisionsFunc: &SearchContextsStoreSetSearchContextRepositoryRevisionsFunc{
 			defaultHook: func(context.Context, int64, []*types.SearchContextRepositoryRevisions) error {
 				panic("unexpected invocation of MockSearchContextsStore.SetSearchContextRepositoryRevisions")
@@ -68037,9 +69344,18 @@ func NewMockSearchContextsStoreFrom(i database.SearchContextsStore) *MockSearchC
 		HandleFunc: &SearchContextsStoreHandleFunc{
 			defaultHook: i.Handle,
 		},
+		ListDynamicSearchContextsFunc: &SearchContextsStoreListDynamicSearchContextsFunc{
+			defaultHook: i.ListDynamicSearchContexts,
+		},
 		ListSearchContextsFunc: &SearchContextsStoreListSearchContextsFunc{
 			defaultHook: i.ListSearchContexts,
 		},
+		MarkDynamicSearchContextEvaluatedFunc: &SearchContextsStoreMarkDynamicSearchContextEvaluatedFunc{
+			defaultHook: i.MarkDynamicSearchContextEvaluated,
+		},
+		SetDynamicSearchContextRepositoriesFunc: &SearchContextsStoreSetDynamicSearchContextRepositoriesFunc{
+			defaultHook: i.SetDynamicSearchContextRepositories,
+		},
 		SetSearchContextRepositoryRevisionsFunc: &SearchContextsStoreSetSearchContextRepositoryRevisionsFunc{
 			defaultHook: i.SetSearchContextRepositoryRevisions,
 		},
@@ -69480,6 +70796,121 @@ func (c SearchContextsStoreHandleFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// SearchContextsStoreListDynamicSearchContextsFunc describes the behavior
+// when the ListDynamicSearchContexts method of the parent
+// MockSearchContextsStore instance is invoked.
+type SearchContextsStoreListDynamicSearchContextsFunc struct {
+	defaultHook func(context.Context, time.Time, int32) ([]*types.SearchContext, error)
+	hooks       []func(context.Context, time.Time, int32) ([]*types.SearchContext, error)
+	history     []SearchContextsStoreListDynamicSearchContextsFuncCall
+	mutex       sync.Mutex
+}
+
+// ListDynamicSearchContexts delegates to the next hook function in the
+// queue and stores the parameter and result values of this invocation.
+func (m *MockSearchContextsStore) ListDynamicSearchContexts(v0 context.Context, v1 time.Time, v2 int32) ([]*types.SearchContext, error) {
+	r0, r1 := m.ListDynamicSearchContextsFunc.nextHook()(v0, v1, v2)
+	m.ListDynamicSearchContextsFunc.appendCall(SearchContextsStoreListDynamicSearchContextsFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// ListDynamicSearchContexts method of the parent MockSearchContextsStore
+// instance is invoked and the hook queue is empty.
+func (f *SearchContextsStoreListDynamicSearchContextsFunc) SetDefaultHook(hook func(context.Context, time.Time, int32) ([]*types.SearchContext, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ListDynamicSearchContexts method of the parent MockSearchContextsStore
+// instance invokes the hook at the front of the queue and discards it.
+// After the queue is empty, the default hook function is invoked for any
+// future action.
+func (f *SearchContextsStoreListDynamicSearchContextsFunc) PushHook(hook func(context.Context, time.Time, int32) ([]*types.SearchContext, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *SearchContextsStoreListDynamicSearchContextsFunc) SetDefaultReturn(r0 []*types.SearchContext, r1 error) {
+	f.SetDefaultHook(func(context.Context, time.Time, int32) ([]*types.SearchContext, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *SearchContextsStoreListDynamicSearchContextsFunc) PushReturn(r0 []*types.SearchContext, r1 error) {
+	f.PushHook(func(context.Context, time.Time, int32) ([]*types.SearchContext, error) {
+		return r0, r1
+	})
+}
+
+func (f *SearchContextsStoreListDynamicSearchContextsFunc) nextHook() func(context.Context, time.Time, int32) ([]*types.SearchContext, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *SearchContextsStoreListDynamicSearchContextsFunc) appendCall(r0 SearchContextsStoreListDynamicSearchContextsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// SearchContextsStoreListDynamicSearchContextsFuncCall objects describing
+// the invocations of this function.
+func (f *SearchContextsStoreListDynamicSearchContextsFunc) History() []SearchContextsStoreListDynamicSearchContextsFuncCall {
+	f.mutex.Lock()
+	history := make([]SearchContextsStoreListDynamicSearchContextsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// SearchContextsStoreListDynamicSearchContextsFuncCall is an object that
+// describes an invocation of method ListDynamicSearchContexts on an
+// instance of MockSearchContextsStore.
+type SearchContextsStoreListDynamicSearchContextsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 time.Time
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int32
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []*types.SearchContext
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c SearchContextsStoreListDynamicSearchContextsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c SearchContextsStoreListDynamicSearchContextsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // SearchContextsStoreListSearchContextsFunc describes the behavior when the
 // ListSearchContexts method of the parent MockSearchContextsStore instance
 // is invoked.
@@ -69595,6 +71026,229 @@ func (c SearchContextsStoreListSearchContextsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// SearchContextsStoreMarkDynamicSearchContextEvaluatedFunc describes the
+// behavior when the MarkDynamicSearchContextEvaluated method of the parent
+// MockSearchContextsStore instance is invoked.
+type SearchContextsStoreMarkDynamicSearchContextEvaluatedFunc struct {
+	defaultHook func(context.Context, int64) error
+	hooks       []func(context.Context, int64) error
+	history     []SearchContextsStoreMarkDynamicSearchContextEvaluatedFuncCall
+	mutex       sync.Mutex
+}
+
+// MarkDynamicSearchContextEvaluated delegates to the next hook function in
+// the queue and stores the parameter and result values of this invocation.
+func (m *MockSearchContextsStore) MarkDynamicSearchContextEvaluated(v0 context.Context, v1 int64) error {
+	r0 := m.MarkDynamicSearchContextEvaluatedFunc.nextHook()(v0, v1)
+	m.MarkDynamicSearchContextEvaluatedFunc.appendCall(SearchContextsStoreMarkDynamicSearchContextEvaluatedFuncCall{v0, v1, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the
+// MarkDynamicSearchContextEvaluated method of the parent
+// MockSearchContextsStore instance is invoked and the hook queue is empty.
+func (f *SearchContextsStoreMarkDynamicSearchContextEvaluatedFunc) SetDefaultHook(hook func(context.Context, int64) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// MarkDynamicSearchContextEvaluated method of the parent
+// MockSearchContextsStore instance invokes the hook at the front of the
+// queue and discards it. After the queue is empty, the default hook
+// function is invoked for any future action.
+func (f *SearchContextsStoreMarkDynamicSearchContextEvaluatedFunc) PushHook(hook func(context.Context, int64) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *SearchContextsStoreMarkDynamicSearchContextEvaluatedFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int64) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *SearchContextsStoreMarkDynamicSearchContextEvaluatedFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int64) error {
+		return r0
+	})
+}
+
+func (f *SearchContextsStoreMarkDynamicSearchContextEvaluatedFunc) nextHook() func(context.Context, int64) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *SearchContextsStoreMarkDynamicSearchContextEvaluatedFunc) appendCall(r0 SearchContextsStoreMarkDynamicSearchContextEvaluatedFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// SearchContextsStoreMarkDynamicSearchContextEvaluatedFuncCall objects
+// describing the invocations of this function.
+func (f *SearchContextsStoreMarkDynamicSearchContextEvaluatedFunc) History() []SearchContextsStoreMarkDynamicSearchContextEvaluatedFuncCall {
+	f.mutex.Lock()
+	history := make([]SearchContextsStoreMarkDynamicSearchContextEvaluatedFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// SearchContextsStoreMarkDynamicSearchContextEvaluatedFuncCall is an object
+// that describes an invocation of method MarkDynamicSearchContextEvaluated
+// on an instance of MockSearchContextsStore.
+type SearchContextsStoreMarkDynamicSearchContextEvaluatedFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int64
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c SearchContextsStoreMarkDynamicSearchContextEvaluatedFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c SearchContextsStoreMarkDynamicSearchContextEvaluatedFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// SearchContextsStoreSetDynamicSearchContextRepositoriesFunc describes the
+// behavior when the SetDynamicSearchContextRepositories method of the
+// parent MockSearchContextsStore instance is invoked.
+type SearchContextsStoreSetDynamicSearchContextRepositoriesFunc struct {
+	defaultHook func(context.Context, int64, []api.RepoID) error
+	hooks       []func(context.Context, int64, []api.RepoID) error
+	history     []SearchContextsStoreSetDynamicSearchContextRepositoriesFuncCall
+	mutex       sync.Mutex
+}
+
+// SetDynamicSearchContextRepositories delegates to the next hook function
+// in the queue and stores the parameter and result values of this
+// invocation.
+func (m *MockSearchContextsStore) SetDynamicSearchContextRepositories(v0 context.Context, v1 int64, v2 []api.RepoID) error {
+	r0 := m.SetDynamicSearchContextRepositoriesFunc.nextHook()(v0, v1, v2)
+	m.SetDynamicSearchContextRepositoriesFunc.appendCall(SearchContextsStoreSetDynamicSearchContextRepositoriesFuncCall{v0, v1, v2, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the
+// SetDynamicSearchContextRepositories method of the parent
+// MockSearchContextsStore instance is invoked and the hook queue is empty.
+func (f *SearchContextsStoreSetDynamicSearchContextRepositoriesFunc) SetDefaultHook(hook func(context.Context, int64, []api.RepoID) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SetDynamicSearchContextRepositories method of the parent
+// MockSearchContextsStore instance invokes the hook at the front of the
+// queue and discards it. After the queue is empty, the default hook
+// function is invoked for any future action.
+func (f *SearchContextsStoreSetDynamicSearchContextRepositoriesFunc) PushHook(hook func(context.Context, int64, []api.RepoID) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultHook with a function that returns the
+// given values.
+func (f *SearchContextsStoreSetDynamicSearchContextRepositoriesFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int64, []api.RepoID) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushHook with a function that returns the given values.
+func (f *SearchContextsStoreSetDynamicSearchContextRepositoriesFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int64, []api.RepoID) error {
+		return r0
+	})
+}
+
+func (f *SearchContextsStoreSetDynamicSearchContextRepositoriesFunc) nextHook() func(context.Context, int64, []api.RepoID) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *SearchContextsStoreSetDynamicSearchContextRepositoriesFunc) appendCall(r0 SearchContextsStoreSetDynamicSearchContextRepositoriesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of
+// SearchContextsStoreSetDynamicSearchContextRepositoriesFuncCall objects
+// describing the invocations of this function.
+func (f *SearchContextsStoreSetDynamicSearchContextRepositoriesFunc) History() []SearchContextsStoreSetDynamicSearchContextRepositoriesFuncCall {
+	f.mutex.Lock()
+	history := make([]SearchContextsStoreSetDynamicSearchContextRepositoriesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// SearchContextsStoreSetDynamicSearchContextRepositoriesFuncCall is an
+// object that describes an invocation of method
+// SetDynamicSearchContextRepositories on an instance of
+// MockSearchContextsStore.
+type SearchContextsStoreSetDynamicSearchContextRepositoriesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int64
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 []api.RepoID
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c SearchContextsStoreSetDynamicSearchContextRepositoriesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c SearchContextsStoreSetDynamicSearchContextRepositoriesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // SearchContextsStoreSetSearchContextRepositoryRevisionsFunc describes the
 // behavior when the SetSearchContextRepositoryRevisions method of the
 // parent MockSearchContextsStore instance is invoked.
@@ -82,6 +82,12 @@ type OwnershipStatsStore interface {
 	// this point these include total count of files that are owned via CODEOWNERS
 	// and assigned ownership.
 	QueryAggregateCounts(context.Context, TreeLocationOpts) (PathAggregateCounts, error)
+
+	// QueryOwnedRepos returns the IDs of the repositories in which the given owner owns
+	// files, either via CODEOWNERS or via assigned ownership. The owner is a handle without
+	// @ in front, which matches CODEOWNERS references, usernames and team names, or an email,
+	// which matches CODEOWNERS references and verified user emails.
+	QueryOwnedRepos(ctx context.Context, owner string) ([]api.RepoID, error)
 }
 
 var _ OwnershipStatsStore = &ownershipStats{}
@@ -252,3 +258,32 @@ func (s *ownershipStats) QueryAggregateCounts(ctx context.Context, opts TreeLoca
 	)
 	return cs, err
 }
+
+const ownedReposFmtstr = `
+	SELECT p.repo_id
+	FROM codeowners_individual_stats AS s
+	INNER JOIN repo_paths AS p ON s.file_path_id = p.id
+	INNER JOIN codeowners_owners AS o ON o.id = s.owner_id
+	WHERE p.absolute_path = '' AND s.tree_owned_files_count > 0 AND o.reference = %s
+	UNION
+	SELECT p.repo_id
+	FROM assigned_owners AS a
+	INNER JOIN repo_paths AS p ON a.file_path_id = p.id
+	INNER JOIN users AS u ON u.id = a.owner_user_id
+	WHERE u.deleted_at IS NULL AND (
+		u.username = %s
+		OR EXISTS (SELECT FROM user_emails AS e WHERE e.user_id = u.id AND e.email = %s AND e.verified_at IS NOT NULL)
+	)
+	UNION
+	SELECT p.repo_id
+	FROM assigned_teams AS a
+	INNER JOIN repo_paths AS p ON a.file_path_id = p.id
+	INNER JOIN teams AS t ON t.id = a.owner_team_id
+	WHERE t.name = %s
+	ORDER BY 1
+`
+
+func (s *ownershipStats) QueryOwnedRepos(ctx context.Context, owner string) ([]api.RepoID, error) {
+	q := sqlf.Sprintf(ownedReposFmtstr, owner, owner, owner, owner)
+	return scanRepoIDs(s.Store.Query(ctx, q))
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
 	"github.com/sourcegraph/sourcegraph/internal/types"
 )
@@ -337,3 +338,43 @@ func TestQueryAggregateCounts(t *testing.T) {
 		assert.Equal(t, want, got)
 	})
 }
+
+func TestQueryOwnedRepos(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(t))
+	ctx := context.Background()
+	// 1. Setup repos, a user and a team:
+	repo1 := mustCreate(ctx, t, db, &types.Repo{Name: "a/b"})
+	repo2 := mustCreate(ctx, t, db, &types.Repo{Name: "a/c"})
+	repo3 := mustCreate(ctx, t, db, &types.Repo{Name: "a/d"})
+	user, err := db.Users().Create(ctx, NewUser{Username: "alice"})
+	require.NoError(t, err)
+	team := createTeam(t, ctx, db, "search")
+	// 2. Insert CODEOWNERS counts and assigned ownership:
+	timestamp := time.Now()
+	_, err = db.OwnershipStats().UpdateIndividualCounts(ctx, repo1.ID, fakeCodeownersStats{
+		"":      {{CodeownersReference: "search", CodeownedFileCount: 2}},
+		"file1": {{CodeownersReference: "search", CodeownedFileCount: 1}},
+	}, timestamp)
+	require.NoError(t, err)
+	_, err = db.OwnershipStats().UpdateIndividualCounts(ctx, repo2.ID, fakeCodeownersStats{
+		"": {{CodeownersReference: "alice", CodeownedFileCount: 1}},
+	}, timestamp)
+	require.NoError(t, err)
+	require.NoError(t, db.AssignedTeams().Insert(ctx, team.ID, repo3.ID, "src", user.ID))
+	require.NoError(t, db.AssignedOwners().Insert(ctx, user.ID, repo3.ID, "", user.ID))
+	// 3. Query owned repos:
+	for owner, want := range map[string][]api.RepoID{
+		"search": {repo1.ID, repo3.ID},
+		"alice":  {repo2.ID, repo3.ID},
+		"bob":    nil,
+	} {
+		got, err := db.OwnershipStats().QueryOwnedRepos(ctx, owner)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, owner)
+	}
+}
@@ -25,6 +25,10 @@ type RepoCommitsChangelistsStore interface {
 	// GetRepoCommit will return the mathcing row from the table for the given repo ID and the
 	// given changelist ID.
 	GetRepoCommitChangelist(ctx context.Context, repoID api.RepoID, changelistID int64) (*types.RepoCommit, error)
+
+	// GetChangelistIDsForCommits returns the changelist IDs of the given commits
+	// of a repo. Commits that are not mapped to a changelist are left out.
+	GetChangelistIDsForCommits(ctx context.Context, repoID api.RepoID, commitSHAs []api.CommitID) (map[api.CommitID]int64, error)
 }
 
 type repoCommitsChangelistsStore struct {
@@ -119,3 +123,45 @@ func (s *repoCommitsChangelistsStore) GetRepoCommitChangelist(ctx context.Contex
 	}
 	return repoCommit, nil
 }
+
+var getChangelistIDsForCommitsFmtStr = `
+SELECT
+	commit_sha,
+	perforce_changelist_id
+FROM
+	repo_commits_changelists
+WHERE
+	repo_id = %s
+	AND commit_sha IN (%s);
+`
+
+func (s *repoCommitsChangelistsStore) GetChangelistIDsForCommits(ctx context.Context, repoID api.RepoID, commitSHAs []api.CommitID) (_ map[api.CommitID]int64, err error) {
+	changelistIDs := make(map[api.CommitID]int64, len(commitSHAs))
+	if len(commitSHAs) == 0 {
+		return changelistIDs, nil
+	}
+
+	shas := make([]*sqlf.Query, 0, len(commitSHAs))
+	for _, sha := range commitSHAs {
+		shas = append(shas, sqlf.Sprintf("%s", dbutil.CommitBytea(sha)))
+	}
+	q := sqlf.Sprintf(getChangelistIDsForCommitsFmtStr, repoID, sqlf.Join(shas, ","))
+
+	rows, err := s.Query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = basestore.CloseRows(rows, err) }()
+
+	for rows.Next() {
+		var (
+			sha          dbutil.CommitBytea
+			changelistID int64
+		)
+		if err := rows.Scan(&sha, &changelistID); err != nil {
+			return nil, err
+		}
+		changelistIDs[api.CommitID(sha)] = changelistID
+	}
+	return changelistIDs, nil
+}
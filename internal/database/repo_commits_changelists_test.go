@@ -163,4 +163,25 @@ func TestRepoCommitsChangelists(t *testing.T) {
 			}
 		})
 	})
+
+	t.Run("GetChangelistIDsForCommits", func(t *testing.T) {
+		got, err := s.GetChangelistIDsForCommits(ctx, 1, []api.CommitID{
+			api.CommitID(commitSHA1),
+			api.CommitID(commitSHA3),
+			"0000000000000000000000000000000000000000",
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[api.CommitID]int64{
+			api.CommitID(commitSHA1): 123,
+			api.CommitID(commitSHA3): 125,
+		}, got)
+
+		got, err = s.GetChangelistIDsForCommits(ctx, 2, []api.CommitID{api.CommitID(commitSHA1)})
+		require.NoError(t, err)
+		require.Empty(t, got)
+
+		got, err = s.GetChangelistIDsForCommits(ctx, 1, nil)
+		require.NoError(t, err)
+		require.Empty(t, got)
+	})
 }
@@ -0,0 +1,179 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/basestore"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbutil"
+)
+
+// RepoUpdateScheduleEntry is the persisted scheduling state of a single
+// repository in the repo-updater update scheduler.
+type RepoUpdateScheduleEntry struct {
+	RepoID api.RepoID
+	// Interval is the learned update interval of the repository. It is zero if
+	// the scheduler has not learned an interval yet, e.g. because the row was
+	// created by a webhook.
+	Interval time.Duration
+	// Due is the time the repository is next due for an update.
+	Due time.Time
+	// LastWebhookAt is the last time a push webhook was received for the
+	// repository, if ever.
+	LastWebhookAt *time.Time
+}
+
+// RepoUpdateScheduleStore persists the state of the repo-updater update
+// scheduler, so that learned update intervals survive restarts.
+type RepoUpdateScheduleStore interface {
+	basestore.ShareableStore
+	With(basestore.ShareableStore) RepoUpdateScheduleStore
+
+	// List returns the persisted schedule of all repositories.
+	List(ctx context.Context) ([]RepoUpdateScheduleEntry, error)
+	// Upsert stores the interval and due time of the given entries. Entries of
+	// repositories that no longer exist are ignored. LastWebhookAt is not
+	// modified.
+	Upsert(ctx context.Context, entries []RepoUpdateScheduleEntry) error
+	// Delete removes the persisted schedule of the given repositories.
+	Delete(ctx context.Context, ids ...api.RepoID) error
+	// RecordWebhook records that a push webhook was received for the given
+	// repository.
+	RecordWebhook(ctx context.Context, id api.RepoID) error
+	// ListWebhooksSince returns the last webhook time of all repositories that
+	// received a push webhook at or after since.
+	ListWebhooksSince(ctx context.Context, since time.Time) (map[api.RepoID]time.Time, error)
+}
+
+type repoUpdateScheduleStore struct {
+	*basestore.Store
+}
+
+var _ RepoUpdateScheduleStore = (*repoUpdateScheduleStore)(nil)
+
+// RepoUpdateScheduleWith instantiates and returns a new RepoUpdateScheduleStore
+// using the other store handle.
+func RepoUpdateScheduleWith(other basestore.ShareableStore) RepoUpdateScheduleStore {
+	return &repoUpdateScheduleStore{Store: basestore.NewWithHandle(other.Handle())}
+}
+
+func (s *repoUpdateScheduleStore) With(other basestore.ShareableStore) RepoUpdateScheduleStore {
+	return &repoUpdateScheduleStore{Store: s.Store.With(other)}
+}
+
+const listRepoUpdateScheduleQuery = `
+SELECT repo_id, update_interval_seconds, due_at, last_webhook_at
+FROM repo_update_schedule
+ORDER BY repo_id
+`
+
+func (s *repoUpdateScheduleStore) List(ctx context.Context) ([]RepoUpdateScheduleEntry, error) {
+	return scanRepoUpdateScheduleEntries(s.Query(ctx, sqlf.Sprintf(listRepoUpdateScheduleQuery)))
+}
+
+var scanRepoUpdateScheduleEntries = basestore.NewSliceScanner(scanRepoUpdateScheduleEntry)
+
+func scanRepoUpdateScheduleEntry(sc dbutil.Scanner) (RepoUpdateScheduleEntry, error) {
+	var (
+		e               RepoUpdateScheduleEntry
+		intervalSeconds int
+	)
+	if err := sc.Scan(&e.RepoID, &intervalSeconds, &e.Due, &e.LastWebhookAt); err != nil {
+		return e, err
+	}
+	e.Interval = time.Duration(intervalSeconds) * time.Second
+	return e, nil
+}
+
+const upsertRepoUpdateScheduleQuery = `
+INSERT INTO repo_update_schedule (repo_id, update_interval_seconds, due_at)
+SELECT u.repo_id, u.update_interval_seconds, to_timestamp(u.due_at)
+FROM unnest(%s::integer[], %s::integer[], %s::bigint[]) AS u(repo_id, update_interval_seconds, due_at)
+-- Skip repositories that have been deleted in the meantime.
+JOIN repo ON repo.id = u.repo_id
+ON CONFLICT (repo_id) DO UPDATE SET
+	update_interval_seconds = EXCLUDED.update_interval_seconds,
+	due_at = EXCLUDED.due_at,
+	updated_at = now()
+`
+
+func (s *repoUpdateScheduleStore) Upsert(ctx context.Context, entries []RepoUpdateScheduleEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var (
+		ids       = make([]int32, 0, len(entries))
+		intervals = make([]int64, 0, len(entries))
+		dues      = make([]int64, 0, len(entries))
+	)
+	for _, e := range entries {
+		ids = append(ids, int32(e.RepoID))
+		intervals = append(intervals, int64(e.Interval/time.Second))
+		dues = append(dues, e.Due.Unix())
+	}
+
+	return s.Exec(ctx, sqlf.Sprintf(upsertRepoUpdateScheduleQuery, pq.Array(ids), pq.Array(intervals), pq.Array(dues)))
+}
+
+const deleteRepoUpdateScheduleQuery = `
+DELETE FROM repo_update_schedule
+WHERE repo_id = ANY(%s)
+`
+
+func (s *repoUpdateScheduleStore) Delete(ctx context.Context, ids ...api.RepoID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	repoIDs := make([]int32, 0, len(ids))
+	for _, id := range ids {
+		repoIDs = append(repoIDs, int32(id))
+	}
+
+	return s.Exec(ctx, sqlf.Sprintf(deleteRepoUpdateScheduleQuery, pq.Array(repoIDs)))
+}
+
+const recordRepoUpdateScheduleWebhookQuery = `
+INSERT INTO repo_update_schedule (repo_id, update_interval_seconds, due_at, last_webhook_at)
+VALUES (%s, 0, now(), now())
+ON CONFLICT (repo_id) DO UPDATE SET
+	last_webhook_at = now(),
+	updated_at = now()
+`
+
+func (s *repoUpdateScheduleStore) RecordWebhook(ctx context.Context, id api.RepoID) error {
+	return s.Exec(ctx, sqlf.Sprintf(recordRepoUpdateScheduleWebhookQuery, id))
+}
+
+const listRepoUpdateScheduleWebhooksSinceQuery = `
+SELECT repo_id, last_webhook_at
+FROM repo_update_schedule
+WHERE last_webhook_at >= %s
+`
+
+func (s *repoUpdateScheduleStore) ListWebhooksSince(ctx context.Context, since time.Time) (map[api.RepoID]time.Time, error) {
+	rows, err := s.Query(ctx, sqlf.Sprintf(listRepoUpdateScheduleWebhooksSinceQuery, since))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := make(map[api.RepoID]time.Time)
+	for rows.Next() {
+		var (
+			id api.RepoID
+			at time.Time
+		)
+		if err := rows.Scan(&id, &at); err != nil {
+			return nil, err
+		}
+		webhooks[id] = at
+	}
+
+	return webhooks, rows.Err()
+}
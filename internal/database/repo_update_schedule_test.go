@@ -0,0 +1,96 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestRepoUpdateSchedule(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	t.Parallel()
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(t))
+	ctx := context.Background()
+	store := db.RepoUpdateSchedule()
+
+	repo1 := mustCreate(ctx, t, db, &types.Repo{Name: "a/b"})
+	repo2 := mustCreate(ctx, t, db, &types.Repo{Name: "c/d"})
+
+	due := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	// Unknown repositories are skipped.
+	err := store.Upsert(ctx, []RepoUpdateScheduleEntry{
+		{RepoID: repo1.ID, Interval: time.Minute, Due: due},
+		{RepoID: repo2.ID, Interval: 2 * time.Minute, Due: due},
+		{RepoID: api.RepoID(9999), Interval: time.Minute, Due: due},
+	})
+	require.NoError(t, err)
+
+	entries, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, repo1.ID, entries[0].RepoID)
+	assert.Equal(t, time.Minute, entries[0].Interval)
+	assert.True(t, due.Equal(entries[0].Due))
+	assert.Nil(t, entries[0].LastWebhookAt)
+	assert.Equal(t, repo2.ID, entries[1].RepoID)
+	assert.Equal(t, 2*time.Minute, entries[1].Interval)
+
+	// Updating an entry overwrites interval and due time.
+	err = store.Upsert(ctx, []RepoUpdateScheduleEntry{{RepoID: repo1.ID, Interval: time.Hour, Due: due.Add(time.Hour)}})
+	require.NoError(t, err)
+
+	before := time.Now().Add(-time.Minute)
+
+	// Webhooks only touch last_webhook_at of existing entries.
+	require.NoError(t, store.RecordWebhook(ctx, repo1.ID))
+
+	webhooks, err := store.ListWebhooksSince(ctx, before)
+	require.NoError(t, err)
+	require.Len(t, webhooks, 1)
+	assert.Contains(t, webhooks, repo1.ID)
+
+	// Webhooks at exactly since are included.
+	webhooks, err = store.ListWebhooksSince(ctx, webhooks[repo1.ID])
+	require.NoError(t, err)
+	assert.Contains(t, webhooks, repo1.ID)
+
+	webhooks, err = store.ListWebhooksSince(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, webhooks)
+
+	entries, err = store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, time.Hour, entries[0].Interval)
+	assert.True(t, due.Add(time.Hour).Equal(entries[0].Due))
+	assert.NotNil(t, entries[0].LastWebhookAt)
+
+	// Webhooks for repositories without a schedule create an entry without an
+	// interval.
+	require.NoError(t, store.Delete(ctx, repo2.ID))
+	require.NoError(t, store.RecordWebhook(ctx, repo2.ID))
+
+	entries, err = store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, repo2.ID, entries[1].RepoID)
+	assert.Equal(t, time.Duration(0), entries[1].Interval)
+	assert.NotNil(t, entries[1].LastWebhookAt)
+
+	require.NoError(t, store.Delete(ctx, repo1.ID, repo2.ID))
+	entries, err = store.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
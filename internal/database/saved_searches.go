@@ -84,7 +84,8 @@ func (s *savedSearchStore) ListAll(ctx context.Context) (savedSearches []api.Sav
 		notify_slack,
 		user_id,
 		org_id,
-		slack_webhook_url FROM saved_searches
+		slack_webhook_url,
+		variables FROM saved_searches
 	`)
 	rows, err := s.Query(ctx, q)
 	if err != nil {
@@ -101,7 +102,8 @@ func (s *savedSearchStore) ListAll(ctx context.Context) (savedSearches []api.Sav
 			&sq.Config.NotifySlack,
 			&sq.Config.UserID,
 			&sq.Config.OrgID,
-			&sq.Config.SlackWebhookURL); err != nil {
+			&sq.Config.SlackWebhookURL,
+			dbutil.JSONMessage(&sq.Config.Variables)); err != nil {
 			return nil, errors.Wrap(err, "Scan")
 		}
 		sq.Spec.Key = sq.Config.Key
@@ -131,7 +133,8 @@ func (s *savedSearchStore) GetByID(ctx context.Context, id int32) (*api.SavedQue
 		notify_slack,
 		user_id,
 		org_id,
-		slack_webhook_url
+		slack_webhook_url,
+		variables
 		FROM saved_searches WHERE id=$1`, id).Scan(
 		&sq.Config.Key,
 		&sq.Config.Description,
@@ -140,7 +143,8 @@ func (s *savedSearchStore) GetByID(ctx context.Context, id int32) (*api.SavedQue
 		&sq.Config.NotifySlack,
 		&sq.Config.UserID,
 		&sq.Config.OrgID,
-		&sq.Config.SlackWebhookURL)
+		&sq.Config.SlackWebhookURL,
+		dbutil.JSONMessage(&sq.Config.Variables))
 	if err != nil {
 		return nil, err
 	}
@@ -188,7 +192,8 @@ func (s *savedSearchStore) ListSavedSearchesByUserID(ctx context.Context, userID
 		notify_slack,
 		user_id,
 		org_id,
-		slack_webhook_url
+		slack_webhook_url,
+		variables
 		FROM saved_searches %v`, conds)
 
 	rows, err := s.Query(ctx, query)
@@ -197,7 +202,7 @@ func (s *savedSearchStore) ListSavedSearchesByUserID(ctx context.Context, userID
 	}
 	for rows.Next() {
 		var ss types.SavedSearch
-		if err := rows.Scan(&ss.ID, &ss.Description, &ss.Query, &ss.Notify, &ss.NotifySlack, &ss.UserID, &ss.OrgID, &ss.SlackWebhookURL); err != nil {
+		if err := rows.Scan(&ss.ID, &ss.Description, &ss.Query, &ss.Notify, &ss.NotifySlack, &ss.UserID, &ss.OrgID, &ss.SlackWebhookURL, dbutil.JSONMessage(&ss.Variables)); err != nil {
 			return nil, errors.Wrap(err, "Scan(2)")
 		}
 		savedSearches = append(savedSearches, &ss)
@@ -223,7 +228,8 @@ func (s *savedSearchStore) ListSavedSearchesByOrgID(ctx context.Context, orgID i
 		notify_slack,
 		user_id,
 		org_id,
-		slack_webhook_url
+		slack_webhook_url,
+		variables
 		FROM saved_searches %v`, conds)
 
 	rows, err := s.Query(ctx, query)
@@ -232,7 +238,7 @@ func (s *savedSearchStore) ListSavedSearchesByOrgID(ctx context.Context, orgID i
 	}
 	for rows.Next() {
 		var ss types.SavedSearch
-		if err := rows.Scan(&ss.ID, &ss.Description, &ss.Query, &ss.Notify, &ss.NotifySlack, &ss.UserID, &ss.OrgID, &ss.SlackWebhookURL); err != nil {
+		if err := rows.Scan(&ss.ID, &ss.Description, &ss.Query, &ss.Notify, &ss.NotifySlack, &ss.UserID, &ss.OrgID, &ss.SlackWebhookURL, dbutil.JSONMessage(&ss.Variables)); err != nil {
 			return nil, errors.Wrap(err, "Scan")
 		}
 
@@ -281,7 +287,8 @@ SELECT
 	notify_slack,
 	user_id,
 	org_id,
-	slack_webhook_url
+	slack_webhook_url,
+	variables
 FROM saved_searches %v
 `
 
@@ -289,7 +296,7 @@ var scanSavedSearches = basestore.NewSliceScanner(scanSavedSearch)
 
 func scanSavedSearch(s dbutil.Scanner) (*types.SavedSearch, error) {
 	var ss types.SavedSearch
-	if err := s.Scan(&ss.ID, &ss.Description, &ss.Query, &ss.Notify, &ss.NotifySlack, &ss.UserID, &ss.OrgID, &ss.SlackWebhookURL); err != nil {
+	if err := s.Scan(&ss.ID, &ss.Description, &ss.Query, &ss.Notify, &ss.NotifySlack, &ss.UserID, &ss.OrgID, &ss.SlackWebhookURL, dbutil.JSONMessage(&ss.Variables)); err != nil {
 		return nil, errors.Wrap(err, "Scan")
 	}
 	return &ss, nil
@@ -329,6 +336,7 @@ func (s *savedSearchStore) Create(ctx context.Context, newSavedSearch *types.Sav
 		NotifySlack: newSavedSearch.NotifySlack,
 		UserID:      newSavedSearch.UserID,
 		OrgID:       newSavedSearch.OrgID,
+		Variables:   newSavedSearch.Variables,
 	}
 
 	err = s.Handle().QueryRowContext(ctx, `INSERT INTO saved_searches(
@@ -337,14 +345,16 @@ func (s *savedSearchStore) Create(ctx context.Context, newSavedSearch *types.Sav
 			notify_owner,
 			notify_slack,
 			user_id,
-			org_id
-		) VALUES($1, $2, $3, $4, $5, $6) RETURNING id`,
+			org_id,
+			variables
+		) VALUES($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
 		newSavedSearch.Description,
 		savedQuery.Query,
 		newSavedSearch.Notify,
 		newSavedSearch.NotifySlack,
 		newSavedSearch.UserID,
 		newSavedSearch.OrgID,
+		savedSearchVariables(newSavedSearch.Variables),
 	).Scan(&savedQuery.ID)
 	if err != nil {
 		return nil, err
@@ -369,6 +379,7 @@ func (s *savedSearchStore) Update(ctx context.Context, savedSearch *types.SavedS
 		UserID:          savedSearch.UserID,
 		OrgID:           savedSearch.OrgID,
 		SlackWebhookURL: savedSearch.SlackWebhookURL,
+		Variables:       savedSearch.Variables,
 	}
 
 	fieldUpdates := []*sqlf.Query{
@@ -380,6 +391,7 @@ func (s *savedSearchStore) Update(ctx context.Context, savedSearch *types.SavedS
 		sqlf.Sprintf("user_id=%v", savedSearch.UserID),
 		sqlf.Sprintf("org_id=%v", savedSearch.OrgID),
 		sqlf.Sprintf("slack_webhook_url=%v", savedSearch.SlackWebhookURL),
+		sqlf.Sprintf("variables=%v", savedSearchVariables(savedSearch.Variables)),
 	}
 
 	updateQuery := sqlf.Sprintf(`UPDATE saved_searches SET %s WHERE ID=%v RETURNING id`, sqlf.Join(fieldUpdates, ", "), savedSearch.ID)
@@ -389,6 +401,15 @@ func (s *savedSearchStore) Update(ctx context.Context, savedSearch *types.SavedS
 	return savedQuery, nil
 }
 
+// savedSearchVariables returns the value to write to the variables column,
+// which is NULL if the saved search declares no variables.
+func savedSearchVariables(variables []api.SavedQueryVariable) any {
+	if len(variables) == 0 {
+		return nil
+	}
+	return dbutil.JSONMessage(&variables)
+}
+
 // Delete hard-deletes an existing saved search.
 //
 // 🚨 SECURITY: This method does NOT verify the user's identity or that the
@@ -270,6 +270,57 @@ func TestSavedSearchesGetByID(t *testing.T) {
 	}
 }
 
+func TestSavedSearchesVariables(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	t.Parallel()
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(t))
+	ctx := context.Background()
+	_, err := db.Users().Create(ctx, NewUser{DisplayName: "test", Email: "test@test.com", Username: "test", Password: "test", EmailVerificationCode: "c2"})
+	if err != nil {
+		t.Fatal("can't create user", err)
+	}
+	userID := int32(1)
+	variables := []api.SavedQueryVariable{
+		{Name: "service", Type: "repo"},
+		{Name: "since", Type: "date"},
+	}
+	ss, err := db.SavedSearches().Create(ctx, &types.SavedSearch{
+		Query:       "repo:$service type:diff after:$since",
+		Description: "test",
+		UserID:      &userID,
+		Variables:   variables,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	savedSearch, err := db.SavedSearches().GetByID(ctx, ss.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(variables, savedSearch.Config.Variables); diff != "" {
+		t.Fatalf("Mismatch (-want +got):\n%s", diff)
+	}
+
+	// Removing all variables stores NULL.
+	ss.Query = "repo:foo type:diff"
+	ss.Variables = nil
+	if _, err := db.SavedSearches().Update(ctx, ss); err != nil {
+		t.Fatal(err)
+	}
+	list, err := db.SavedSearches().ListSavedSearchesByUserID(ctx, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Variables != nil {
+		t.Fatalf("unexpected saved searches %+v", list)
+	}
+}
+
 func TestListSavedSearchesByUserID(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
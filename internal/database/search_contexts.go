@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/keegancsmith/sqlf"
 	"github.com/lib/pq"
@@ -36,8 +37,11 @@ type SearchContextsStore interface {
 	GetSearchContext(context.Context, GetSearchContextOptions) (*types.SearchContext, error)
 	GetSearchContextRepositoryRevisions(context.Context, int64) ([]*types.SearchContextRepositoryRevisions, error)
 	ListSearchContexts(context.Context, ListSearchContextsPageOptions, ListSearchContextsOptions) ([]*types.SearchContext, error)
+	ListDynamicSearchContexts(ctx context.Context, evaluatedBefore time.Time, limit int32) ([]*types.SearchContext, error)
 	GetAllQueries(context.Context) ([]string, error)
 	SetSearchContextRepositoryRevisions(context.Context, int64, []*types.SearchContextRepositoryRevisions) error
+	SetDynamicSearchContextRepositories(ctx context.Context, searchContextID int64, repoIDs []api.RepoID) error
+	MarkDynamicSearchContextEvaluated(ctx context.Context, searchContextID int64) error
 	Transact(context.Context) (SearchContextsStore, error)
 	UpdateSearchContextWithRepositoryRevisions(context.Context, *types.SearchContext, []*types.SearchContextRepositoryRevisions) (*types.SearchContext, error)
 	SetUserDefaultSearchContextID(ctx context.Context, userID int32, searchContextID int64) error
@@ -91,6 +95,9 @@ const searchContextQueryFmtStr = `
 		NULL as namespace_org_id,
 		TIMESTAMP WITH TIME ZONE 'epoch' as updated_at, -- Timestamp is not used for global context, but we need to return something.
 		NULL as query,
+		NULL as dynamic_source,
+		NULL as dynamic_value,
+		NULL as dynamic_evaluated_at,
 		NULL as namespace_name,
 		NULL as namespace_username,
 		NULL as namespace_org_name,
@@ -107,6 +114,9 @@ const searchContextQueryFmtStr = `
 		sc.namespace_org_id as namespace_org_id,
 		sc.updated_at as updated_at,
 		sc.query as query,
+		sc.dynamic_source as dynamic_source,
+		sc.dynamic_value as dynamic_value,
+		sc.dynamic_evaluated_at as dynamic_evaluated_at,
 		COALESCE(u.username, o.name) as namespace_name,
 		u.username as namespace_username,
 		o.name as namespace_org_name,
@@ -132,6 +142,9 @@ SELECT
 	namespace_org_id,
 	updated_at,
 	query,
+	dynamic_source,
+	dynamic_value,
+	dynamic_evaluated_at,
 	namespace_username,
 	namespace_org_name,
 	user_default,
@@ -355,8 +368,8 @@ func (s *searchContextsStore) DeleteSearchContext(ctx context.Context, searchCon
 
 const insertSearchContextFmtStr = `
 INSERT INTO search_contexts
-(name, description, public, namespace_user_id, namespace_org_id, query)
-VALUES (%s, %s, %s, %s, %s, %s)
+(name, description, public, namespace_user_id, namespace_org_id, query, dynamic_source, dynamic_value)
+VALUES (%s, %s, %s, %s, %s, %s, %s, %s)
 `
 
 // 🚨 SECURITY: The caller must ensure that the actor is a site admin or has permission to create the search context.
@@ -386,6 +399,10 @@ SET
 	description = %s,
 	public = %s,
 	query = %s,
+	dynamic_source = %s,
+	dynamic_value = %s,
+	-- The repositories of a dynamic search context are recomputed after every update.
+	dynamic_evaluated_at = NULL,
 	updated_at = now()
 WHERE id = %d
 `
@@ -442,7 +459,63 @@ func (s *searchContextsStore) SetSearchContextRepositoryRevisions(ctx context.Co
 	))
 }
 
+const listDynamicSearchContextsCondFmtStr = `
+dynamic_source IS NOT NULL AND (dynamic_evaluated_at IS NULL OR dynamic_evaluated_at < %s)
+`
+
+// ListDynamicSearchContexts returns up to limit dynamic search contexts whose repositories
+// haven't been computed since evaluatedBefore, least recently computed first.
+//
+// 🚨 SECURITY: This method does not check permissions and must only be called by an internal actor.
+func (s *searchContextsStore) ListDynamicSearchContexts(ctx context.Context, evaluatedBefore time.Time, limit int32) ([]*types.SearchContext, error) {
+	if a := actor.FromContext(ctx); !a.IsInternal() {
+		return nil, errors.New("ListDynamicSearchContexts can only be accessed by an internal actor")
+	}
+
+	return s.listSearchContexts(
+		ctx,
+		sqlf.Sprintf(listDynamicSearchContextsCondFmtStr, evaluatedBefore),
+		sqlf.Sprintf("dynamic_evaluated_at ASC NULLS FIRST, id ASC"),
+		limit,
+		0,
+	)
+}
+
+// SetDynamicSearchContextRepositories replaces the repositories of a dynamic search context
+// with the default branch of each of the given repositories, and records when they were
+// computed.
+func (s *searchContextsStore) SetDynamicSearchContextRepositories(ctx context.Context, searchContextID int64, repoIDs []api.RepoID) (err error) {
+	tx, err := s.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	repositoryRevisions := make([]*types.SearchContextRepositoryRevisions, 0, len(repoIDs))
+	for _, repoID := range repoIDs {
+		repositoryRevisions = append(repositoryRevisions, &types.SearchContextRepositoryRevisions{
+			Repo:      types.MinimalRepo{ID: repoID},
+			Revisions: []string{"HEAD"},
+		})
+	}
+
+	err = tx.SetSearchContextRepositoryRevisions(ctx, searchContextID, repositoryRevisions)
+	if err != nil {
+		return err
+	}
+
+	return tx.MarkDynamicSearchContextEvaluated(ctx, searchContextID)
+}
+
+// MarkDynamicSearchContextEvaluated records that the repositories of a dynamic search
+// context were just evaluated, without changing them. It is used when the evaluation
+// failed, so that the search context is retried later rather than immediately.
+func (s *searchContextsStore) MarkDynamicSearchContextEvaluated(ctx context.Context, searchContextID int64) error {
+	return s.Exec(ctx, sqlf.Sprintf("UPDATE search_contexts SET dynamic_evaluated_at = now() WHERE id = %d", searchContextID))
+}
+
 func createSearchContext(ctx context.Context, s SearchContextsStore, searchContext *types.SearchContext) (*types.SearchContext, error) {
+	dynamicSource, dynamicValue := dynamicColumns(searchContext)
 	q := sqlf.Sprintf(
 		insertSearchContextFmtStr,
 		searchContext.Name,
@@ -451,6 +524,8 @@ func createSearchContext(ctx context.Context, s SearchContextsStore, searchConte
 		dbutil.NullInt32Column(searchContext.NamespaceUserID),
 		dbutil.NullInt32Column(searchContext.NamespaceOrgID),
 		dbutil.NullStringColumn(searchContext.Query),
+		dynamicSource,
+		dynamicValue,
 	)
 	_, err := s.Handle().ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
 	if err != nil {
@@ -464,12 +539,15 @@ func createSearchContext(ctx context.Context, s SearchContextsStore, searchConte
 }
 
 func updateSearchContext(ctx context.Context, s SearchContextsStore, searchContext *types.SearchContext) (*types.SearchContext, error) {
+	dynamicSource, dynamicValue := dynamicColumns(searchContext)
 	q := sqlf.Sprintf(
 		updateSearchContextFmtStr,
 		searchContext.Name,
 		searchContext.Description,
 		searchContext.Public,
 		dbutil.NullStringColumn(searchContext.Query),
+		dynamicSource,
+		dynamicValue,
 		searchContext.ID,
 	)
 	_, err := s.Handle().ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
@@ -483,6 +561,15 @@ func updateSearchContext(ctx context.Context, s SearchContextsStore, searchConte
 	})
 }
 
+// dynamicColumns returns the values of the dynamic_source and dynamic_value columns of the
+// search context, which are NULL unless it is dynamic.
+func dynamicColumns(searchContext *types.SearchContext) (source, value any) {
+	if searchContext.Dynamic == nil {
+		return nil, nil
+	}
+	return string(searchContext.Dynamic.Source), searchContext.Dynamic.Value
+}
+
 func scanSingleSearchContext(rows *sql.Rows) (*types.SearchContext, error) {
 	searchContexts, err := scanSearchContexts(rows)
 	if err != nil {
@@ -498,6 +585,10 @@ func scanSearchContexts(rows *sql.Rows) ([]*types.SearchContext, error) {
 	var out []*types.SearchContext
 	for rows.Next() {
 		sc := &types.SearchContext{}
+		var (
+			dynamicSource, dynamicValue string
+			dynamicEvaluatedAt          time.Time
+		)
 		err := rows.Scan(
 			&sc.ID,
 			&sc.Name,
@@ -508,6 +599,9 @@ func scanSearchContexts(rows *sql.Rows) ([]*types.SearchContext, error) {
 			&dbutil.NullInt32{N: &sc.NamespaceOrgID},
 			&sc.UpdatedAt,
 			&dbutil.NullString{S: &sc.Query},
+			&dbutil.NullString{S: &dynamicSource},
+			&dbutil.NullString{S: &dynamicValue},
+			&dbutil.NullTime{Time: &dynamicEvaluatedAt},
 			&dbutil.NullString{S: &sc.NamespaceUserName},
 			&dbutil.NullString{S: &sc.NamespaceOrgName},
 			&sc.Default,
@@ -516,6 +610,13 @@ func scanSearchContexts(rows *sql.Rows) ([]*types.SearchContext, error) {
 		if err != nil {
 			return nil, err
 		}
+		if dynamicSource != "" {
+			sc.Dynamic = &types.SearchContextDynamic{
+				Source:      types.SearchContextDynamicSource(dynamicSource),
+				Value:       dynamicValue,
+				EvaluatedAt: dynamicEvaluatedAt,
+			}
+		}
 		out = append(out, sc)
 	}
 	return out, nil
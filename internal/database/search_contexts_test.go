@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 
@@ -427,6 +428,121 @@ func TestSearchContexts_CreateAndSetRepositoryRevisions(t *testing.T) {
 	}
 }
 
+func TestSearchContexts_Dynamic(t *testing.T) {
+	logger := logtest.Scoped(t)
+	db := NewDB(logger, dbtest.NewDB(t))
+	t.Parallel()
+	ctx := actor.WithInternalActor(context.Background())
+	sc := db.SearchContexts()
+	r := db.Repos()
+
+	err := r.Create(ctx, &types.Repo{Name: "testA", URI: "https://example.com/a"}, &types.Repo{Name: "testB", URI: "https://example.com/b"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	repoA, err := r.GetByName(ctx, "testA")
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	repoB, err := r.GetByName(ctx, "testB")
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+
+	created, err := createSearchContexts(ctx, sc, []*types.SearchContext{
+		{Name: "static", Public: true},
+		{Name: "owned", Public: true, Dynamic: &types.SearchContextDynamic{Source: types.SearchContextDynamicSourceOwner, Value: "@team"}},
+		{Name: "tagged", Public: true, Dynamic: &types.SearchContextDynamic{Source: types.SearchContextDynamicSourceMeta, Value: "team:search"}},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	owned, tagged := created[1], created[2]
+
+	if created[0].Dynamic != nil {
+		t.Fatalf("wanted static search context, got dynamic %+v", created[0].Dynamic)
+	}
+	wantDynamic := &types.SearchContextDynamic{Source: types.SearchContextDynamicSourceOwner, Value: "@team"}
+	if diff := cmp.Diff(wantDynamic, owned.Dynamic); diff != "" {
+		t.Fatalf("unexpected dynamic definition (-want +got):\n%s", diff)
+	}
+
+	listIDs := func(evaluatedBefore time.Time) []int64 {
+		t.Helper()
+		contexts, err := sc.ListDynamicSearchContexts(ctx, evaluatedBefore, 10)
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+		ids := make([]int64, 0, len(contexts))
+		for _, c := range contexts {
+			ids = append(ids, c.ID)
+		}
+		return ids
+	}
+
+	if diff := cmp.Diff([]int64{owned.ID, tagged.ID}, listIDs(time.Now())); diff != "" {
+		t.Fatalf("unexpected dynamic search contexts (-want +got):\n%s", diff)
+	}
+
+	if err := sc.SetDynamicSearchContextRepositories(ctx, owned.ID, []api.RepoID{repoA.ID, repoB.ID}); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	gotRepositoryRevisions, err := sc.GetSearchContextRepositoryRevisions(ctx, owned.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	wantRepositoryRevisions := []*types.SearchContextRepositoryRevisions{
+		{Repo: types.MinimalRepo{ID: repoA.ID, Name: repoA.Name}, Revisions: []string{"HEAD"}},
+		{Repo: types.MinimalRepo{ID: repoB.ID, Name: repoB.Name}, Revisions: []string{"HEAD"}},
+	}
+	if !reflect.DeepEqual(wantRepositoryRevisions, gotRepositoryRevisions) {
+		t.Fatalf("wanted %v repository revisions, got %v", wantRepositoryRevisions, gotRepositoryRevisions)
+	}
+
+	// The evaluated search context is listed again only once it is stale, after the
+	// search contexts that have never been evaluated.
+	if diff := cmp.Diff([]int64{tagged.ID}, listIDs(time.Now().Add(-time.Hour))); diff != "" {
+		t.Fatalf("unexpected dynamic search contexts (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int64{tagged.ID, owned.ID}, listIDs(time.Now().Add(time.Hour))); diff != "" {
+		t.Fatalf("unexpected dynamic search contexts (-want +got):\n%s", diff)
+	}
+
+	// Updating the definition resets the evaluation.
+	owned.Dynamic.Value = "@other-team"
+	updated, err := sc.UpdateSearchContextWithRepositoryRevisions(ctx, owned, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	wantDynamic = &types.SearchContextDynamic{Source: types.SearchContextDynamicSourceOwner, Value: "@other-team"}
+	if diff := cmp.Diff(wantDynamic, updated.Dynamic); diff != "" {
+		t.Fatalf("unexpected dynamic definition (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]int64{owned.ID, tagged.ID}, listIDs(time.Now().Add(-time.Hour))); diff != "" {
+		t.Fatalf("unexpected dynamic search contexts (-want +got):\n%s", diff)
+	}
+
+	// Marking a failed evaluation defers the search context without changing its
+	// repositories.
+	if err := sc.MarkDynamicSearchContextEvaluated(ctx, tagged.ID); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if diff := cmp.Diff([]int64{owned.ID}, listIDs(time.Now().Add(-time.Hour))); diff != "" {
+		t.Fatalf("unexpected dynamic search contexts (-want +got):\n%s", diff)
+	}
+	gotRepositoryRevisions, err = sc.GetSearchContextRepositoryRevisions(ctx, tagged.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if len(gotRepositoryRevisions) != 0 {
+		t.Fatalf("wanted no repository revisions, got %v", gotRepositoryRevisions)
+	}
+
+	if _, err := sc.ListDynamicSearchContexts(context.Background(), time.Now(), 10); err == nil {
+		t.Fatal("Expected an error for a non-internal actor, got none")
+	}
+}
+
 func TestSearchContexts_Permissions(t *testing.T) {
 	logger := logtest.Scoped(t)
 	db := NewDB(logger, dbtest.NewDB(t))
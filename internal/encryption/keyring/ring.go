@@ -92,6 +92,13 @@ func NewRing(ctx context.Context, keyConfig *schema.EncryptionKeys) (*Ring, erro
 		}
 	}
 
+	if keyConfig.BlobstoreKey != nil {
+		r.BlobstoreKey, err = NewKey(ctx, keyConfig.BlobstoreKey, keyConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if keyConfig.ExternalServiceKey != nil {
 		r.ExternalServiceKey, err = NewKey(ctx, keyConfig.ExternalServiceKey, keyConfig)
 		if err != nil {
@@ -139,6 +146,7 @@ func NewRing(ctx context.Context, keyConfig *schema.EncryptionKeys) (*Ring, erro
 
 type Ring struct {
 	BatchChangesCredentialKey encryption.Key
+	BlobstoreKey              encryption.Key
 	ExternalServiceKey        encryption.Key
 	GitHubAppKey              encryption.Key
 	OutboundWebhookKey        encryption.Key
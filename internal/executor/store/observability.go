@@ -43,3 +43,30 @@ func newOperations(observationCtx *observation.Context) *operations {
 		updateExecutionLogEntry: op("UpdateExecutionLogEntry"),
 	}
 }
+
+type stepCacheOperations struct {
+	get *observation.Operation
+	put *observation.Operation
+}
+
+func newStepCacheOperations(observationCtx *observation.Context) *stepCacheOperations {
+	m := metrics.NewREDMetrics(
+		observationCtx.Registerer,
+		"executor_step_cache",
+		metrics.WithLabels("op"),
+		metrics.WithCountHelp("Total number of method invocations."),
+	)
+
+	op := func(name string) *observation.Operation {
+		return observationCtx.Operation(observation.Op{
+			Name:              fmt.Sprintf("executor.stepcache.%s", name),
+			MetricLabelValues: []string{name},
+			Metrics:           m,
+		})
+	}
+
+	return &stepCacheOperations{
+		get: op("Get"),
+		put: op("Put"),
+	}
+}
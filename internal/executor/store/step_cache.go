@@ -0,0 +1,120 @@
+package store
+
+import (
+	"context"
+	"io"
+	"path"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/executor/types"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// ErrStepCacheMiss is returned by StepCacheStore.Get when there is no entry for the
+// requested key.
+var ErrStepCacheMiss = errors.New("step cache miss")
+
+// StepCacheStore stores the outputs of executor job steps in the blobstore, keyed
+// by the content-addressed key of the step (see types.StepCacheKeys). Entries are
+// namespaced by queue and repository, so that a job can only read and write entries
+// of jobs of the same queue that run against the same repository.
+type StepCacheStore interface {
+	// Get returns a reader over the archived outputs stored for the given key.
+	// ErrStepCacheMiss is returned if there is no such entry.
+	Get(ctx context.Context, queue string, repoID int64, key string) (io.ReadCloser, error)
+	// Put stores the archived outputs read from r under the given key.
+	Put(ctx context.Context, queue string, repoID int64, key string, r io.Reader) (int64, error)
+}
+
+type stepCacheStore struct {
+	uploadStore uploadstore.Store
+	operations  *stepCacheOperations
+}
+
+// NewStepCacheStore creates a new StepCacheStore that stores entries in the given
+// upload store.
+func NewStepCacheStore(observationCtx *observation.Context, uploadStore uploadstore.Store) StepCacheStore {
+	return &stepCacheStore{
+		uploadStore: uploadStore,
+		operations:  newStepCacheOperations(observationCtx),
+	}
+}
+
+// StepCacheKeyPrefix is the prefix of all objects written by the StepCacheStore.
+const StepCacheKeyPrefix = "step-cache"
+
+func stepCacheObjectKey(queue string, repoID int64, key string) (string, error) {
+	if queue == "" {
+		return "", errors.New("missing queue")
+	}
+	if repoID <= 0 {
+		return "", errors.New("missing repository")
+	}
+	// 🚨 SECURITY: The key is supplied by the executor, make sure it can't be used to
+	// address objects outside the step cache of the queue.
+	if !types.IsValidStepCacheKey(key) {
+		return "", errors.Newf("invalid step cache key %q", key)
+	}
+
+	return path.Join(StepCacheKeyPrefix, queue, strconv.FormatInt(repoID, 10), key), nil
+}
+
+func (s *stepCacheStore) Get(ctx context.Context, queue string, repoID int64, key string) (_ io.ReadCloser, err error) {
+	ctx, _, endObservation := s.operations.get.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("queue", queue),
+		attribute.Int64("repoID", repoID),
+		attribute.String("key", key),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	objectKey, err := stepCacheObjectKey(queue, repoID, key)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get is lazy and only fails once the content is read, so we check whether the
+	// object exists first to be able to distinguish a miss from a broken entry.
+	it, err := s.uploadStore.List(ctx, objectKey)
+	if err != nil {
+		return nil, err
+	}
+	exists := false
+	for it.Next() {
+		if it.Current() == objectKey {
+			exists = true
+			break
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrStepCacheMiss
+	}
+
+	return s.uploadStore.Get(ctx, objectKey)
+}
+
+func (s *stepCacheStore) Put(ctx context.Context, queue string, repoID int64, key string, r io.Reader) (_ int64, err error) {
+	ctx, _, endObservation := s.operations.put.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("queue", queue),
+		attribute.Int64("repoID", repoID),
+		attribute.String("key", key),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	objectKey, err := stepCacheObjectKey(queue, repoID, key)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := s.uploadStore.Upload(ctx, objectKey, r)
+	if err != nil {
+		return n, errors.Wrapf(err, "uploading step cache entry %q", key)
+	}
+	return n, nil
+}
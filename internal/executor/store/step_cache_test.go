@@ -0,0 +1,83 @@
+package store_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/executor/store"
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/internal/uploadstore/mocks"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/iterator"
+)
+
+const testStepCacheKey = "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+
+func TestStepCacheStore_Get(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("hit", func(t *testing.T) {
+		uploadStore := mocks.NewMockStore()
+		uploadStore.ListFunc.SetDefaultReturn(iterator.From([]string{"step-cache/codeintel/42/" + testStepCacheKey}), nil)
+		uploadStore.GetFunc.SetDefaultReturn(io.NopCloser(strings.NewReader("outputs")), nil)
+		stepCacheStore := store.NewStepCacheStore(&observation.TestContext, uploadStore)
+
+		rc, err := stepCacheStore.Get(ctx, "codeintel", 42, testStepCacheKey)
+		require.NoError(t, err)
+		defer rc.Close()
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		assert.Equal(t, "outputs", string(content))
+
+		require.Len(t, uploadStore.ListFunc.History(), 1)
+		assert.Equal(t, "step-cache/codeintel/42/"+testStepCacheKey, uploadStore.ListFunc.History()[0].Arg1)
+		require.Len(t, uploadStore.GetFunc.History(), 1)
+		assert.Equal(t, "step-cache/codeintel/42/"+testStepCacheKey, uploadStore.GetFunc.History()[0].Arg1)
+	})
+
+	t.Run("miss", func(t *testing.T) {
+		uploadStore := mocks.NewMockStore()
+		uploadStore.ListFunc.SetDefaultReturn(iterator.From([]string{}), nil)
+		stepCacheStore := store.NewStepCacheStore(&observation.TestContext, uploadStore)
+
+		_, err := stepCacheStore.Get(ctx, "codeintel", 42, testStepCacheKey)
+		assert.True(t, errors.Is(err, store.ErrStepCacheMiss))
+		assert.Len(t, uploadStore.GetFunc.History(), 0)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		uploadStore := mocks.NewMockStore()
+		stepCacheStore := store.NewStepCacheStore(&observation.TestContext, uploadStore)
+
+		_, err := stepCacheStore.Get(ctx, "codeintel", 42, "../batches/"+testStepCacheKey)
+		assert.Error(t, err)
+		assert.Len(t, uploadStore.ListFunc.History(), 0)
+	})
+}
+
+func TestStepCacheStore_Put(t *testing.T) {
+	ctx := context.Background()
+
+	uploadStore := mocks.NewMockStore()
+	uploadStore.UploadFunc.SetDefaultHook(func(_ context.Context, _ string, r io.Reader) (int64, error) {
+		return io.Copy(io.Discard, r)
+	})
+	stepCacheStore := store.NewStepCacheStore(&observation.TestContext, uploadStore)
+
+	n, err := stepCacheStore.Put(ctx, "codeintel", 42, testStepCacheKey, bytes.NewReader([]byte("outputs")))
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), n)
+	require.Len(t, uploadStore.UploadFunc.History(), 1)
+	assert.Equal(t, "step-cache/codeintel/42/"+testStepCacheKey, uploadStore.UploadFunc.History()[0].Arg1)
+
+	_, err = stepCacheStore.Put(ctx, "", 42, testStepCacheKey, bytes.NewReader(nil))
+	assert.Error(t, err)
+	_, err = stepCacheStore.Put(ctx, "codeintel", 0, testStepCacheKey, bytes.NewReader(nil))
+	assert.Error(t, err)
+}
@@ -280,6 +280,12 @@ type DockerStep struct {
 
 	// Env specifies a set of NAME=value pairs to supply to the docker command.
 	Env []string `json:"env"`
+
+	// CacheOutputs is the set of paths, relative to Dir, that the step produces. If
+	// set, the step opts into the step cache: when the executor finds a cache entry
+	// for the step, the outputs are restored into the workspace and the step is not
+	// run. See StepCacheKeys for how entries are keyed.
+	CacheOutputs []string `json:"cacheOutputs,omitempty"`
 }
 
 // CliStep is a step that runs a src-cli command.
@@ -0,0 +1,69 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"sort"
+	"strings"
+)
+
+// StepCacheKeys returns the cache key for each docker step of the given job. Steps
+// that do not declare any CacheOutputs are not cacheable and get an empty key.
+//
+// The key of a step is content-addressed: it is derived from the repository and
+// commit the job runs against, the full definition of the step, and the key of the
+// previous step. Chaining the keys ensures that a change to any earlier step also
+// invalidates the cache entries of all steps that follow it.
+func StepCacheKeys(job Job) []string {
+	keys := make([]string, len(job.DockerSteps))
+
+	previous := ""
+	for i, step := range job.DockerSteps {
+		key := stepCacheKey(previous, job, step)
+		if len(step.CacheOutputs) > 0 {
+			keys[i] = key
+		}
+		previous = key
+	}
+
+	return keys
+}
+
+func stepCacheKey(previous string, job Job, step DockerStep) string {
+	h := sha256.New()
+
+	writeField := func(values ...string) {
+		for _, v := range values {
+			// Length-prefix the values so that adjacent fields can't be shifted into
+			// each other to produce the same digest.
+			_ = binary.Write(h, binary.BigEndian, uint32(len(v)))
+			_, _ = io.WriteString(h, v)
+		}
+		_, _ = io.WriteString(h, "\x00")
+	}
+
+	outputs := append([]string(nil), step.CacheOutputs...)
+	sort.Strings(outputs)
+
+	writeField(previous)
+	writeField(job.RepositoryName, job.RepositoryDirectory, job.Commit)
+	writeField(step.Image)
+	writeField(step.Commands...)
+	writeField(step.Dir)
+	writeField(step.Env...)
+	writeField(outputs...)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// IsValidStepCacheKey returns true if the given value has the shape of a key
+// returned by StepCacheKeys.
+func IsValidStepCacheKey(key string) bool {
+	if len(key) != hex.EncodedLen(sha256.Size) || strings.ToLower(key) != key {
+		return false
+	}
+	_, err := hex.DecodeString(key)
+	return err == nil
+}
@@ -0,0 +1,72 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepCacheKeys(t *testing.T) {
+	job := Job{
+		RepositoryName: "github.com/sourcegraph/sourcegraph",
+		Commit:         "deadbeef",
+		DockerSteps: []DockerStep{
+			{Key: "pre-index.0", Image: "alpine", Commands: []string{"yarn install"}},
+			{Key: "indexer", Image: "scip-typescript", Commands: []string{"scip-typescript index"}, Dir: "web", CacheOutputs: []string{"index.scip"}},
+			{Key: "upload", Image: "src-cli", Commands: []string{"src code-intel upload"}},
+		},
+	}
+
+	keys := StepCacheKeys(job)
+	require.Len(t, keys, 3)
+	assert.Empty(t, keys[0])
+	assert.True(t, IsValidStepCacheKey(keys[1]))
+	assert.Empty(t, keys[2])
+
+	t.Run("stable", func(t *testing.T) {
+		assert.Equal(t, keys, StepCacheKeys(job))
+	})
+
+	t.Run("output order does not matter", func(t *testing.T) {
+		a := Job{DockerSteps: []DockerStep{{Image: "alpine", CacheOutputs: []string{"a", "b"}}}}
+		b := Job{DockerSteps: []DockerStep{{Image: "alpine", CacheOutputs: []string{"b", "a"}}}}
+		assert.Equal(t, StepCacheKeys(a), StepCacheKeys(b))
+	})
+
+	mutations := map[string]func(j *Job){
+		"commit":           func(j *Job) { j.Commit = "cafebabe" },
+		"repository":       func(j *Job) { j.RepositoryName = "github.com/sourcegraph/zoekt" },
+		"image":            func(j *Job) { j.DockerSteps[1].Image = "scip-typescript:v2" },
+		"commands":         func(j *Job) { j.DockerSteps[1].Commands = []string{"scip-typescript index --infer-tsconfig"} },
+		"dir":              func(j *Job) { j.DockerSteps[1].Dir = "client" },
+		"env":              func(j *Job) { j.DockerSteps[1].Env = []string{"FOO=bar"} },
+		"previous step":    func(j *Job) { j.DockerSteps[0].Commands = []string{"npm install"} },
+		"shifted commands": func(j *Job) { j.DockerSteps[1].Commands = []string{"scip-typescript", "index"} },
+	}
+	for name, mutate := range mutations {
+		t.Run(name, func(t *testing.T) {
+			changed := job
+			changed.DockerSteps = append([]DockerStep(nil), job.DockerSteps...)
+			mutate(&changed)
+
+			assert.NotEqual(t, keys[1], StepCacheKeys(changed)[1])
+		})
+	}
+
+	t.Run("later steps do not matter", func(t *testing.T) {
+		changed := job
+		changed.DockerSteps = append([]DockerStep(nil), job.DockerSteps...)
+		changed.DockerSteps[2].Commands = []string{"true"}
+
+		assert.Equal(t, keys[1], StepCacheKeys(changed)[1])
+	})
+}
+
+func TestIsValidStepCacheKey(t *testing.T) {
+	assert.True(t, IsValidStepCacheKey("2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"))
+	assert.False(t, IsValidStepCacheKey(""))
+	assert.False(t, IsValidStepCacheKey("2C26B46B68FFC68FF99B453C1D30413413422D706483BFA0F98A5E886266E7AE"))
+	assert.False(t, IsValidStepCacheKey("../../../../etc/passwd"))
+	assert.False(t, IsValidStepCacheKey("2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7"))
+}
@@ -56,6 +56,7 @@ type InsightQueryArgs struct {
 	Limit    int
 	IsFrozen *bool
 	Find     string
+	SeriesID string
 
 	// This field will disable user level authorization checks on the insight views. This should only be used
 	// when fetching insights from a container that also has authorization checks, such as a dashboard.
@@ -132,6 +133,9 @@ func (s *InsightStore) GetAll(ctx context.Context, args InsightQueryArgs) ([]typ
 	if args.Find != "" {
 		preds = append(preds, sqlf.Sprintf("(iv.title ILIKE %s OR ivs.label ILIKE %s)", "%"+args.Find+"%", "%"+args.Find+"%"))
 	}
+	if args.SeriesID != "" {
+		preds = append(preds, sqlf.Sprintf("i.series_id = %s", args.SeriesID))
+	}
 
 	limit := sqlf.Sprintf("")
 	if args.Limit > 0 {
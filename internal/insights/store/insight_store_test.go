@@ -790,6 +790,21 @@ func TestGetAll(t *testing.T) {
 			t.Errorf("unexpected insight view series want/got: %s", diff)
 		}
 	})
+	t.Run("find by series id", func(t *testing.T) {
+		got, err := store.GetAll(ctx, InsightQueryArgs{SeriesID: "series-id-2"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var gotViews []string
+		for _, series := range got {
+			if len(gotViews) == 0 || gotViews[len(gotViews)-1] != series.UniqueID {
+				gotViews = append(gotViews, series.UniqueID)
+			}
+		}
+		if diff := cmp.Diff([]string{"b", "d"}, gotViews); diff != "" {
+			t.Errorf("unexpected insight views want/got: %s", diff)
+		}
+	})
 	t.Run("exclude insight ids from results", func(t *testing.T) {
 		got, err := store.GetAll(ctx, InsightQueryArgs{ExcludeIDs: []string{"b", "e"}})
 		if err != nil {
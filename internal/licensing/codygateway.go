@@ -33,6 +33,12 @@ func NewCodyGatewayChatRateLimit(plan Plan, userCount *int, licenseTags []string
 		"anthropic/claude-instant-v1",
 		"anthropic/claude-instant-1",
 		"anthropic/claude-instant-1.2",
+		"google/gemini-pro",
+		"google/gemini-1.5-pro-latest",
+		"mistral/mistral-small-latest",
+		"mistral/mistral-medium-latest",
+		"mistral/mistral-large-latest",
+		"mistral/open-mixtral-8x7b",
 	}
 	// Switch on GPT models by default if the customer license has the GPT tag.
 	if slices.Contains(licenseTags, GPTLLMAccessTag) {
@@ -72,6 +78,8 @@ func NewCodyGatewayCodeRateLimit(plan Plan, userCount *int, licenseTags []string
 		"anthropic/claude-instant-1",
 		"anthropic/claude-instant-1.2",
 		"fireworks/starcoder",
+		"google/gemini-pro",
+		"mistral/mistral-small-latest",
 	}
 	// Switch on GPT models by default if the customer license has the GPT tag.
 	if slices.Contains(licenseTags, GPTLLMAccessTag) {
@@ -0,0 +1,61 @@
+package notebooks
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// notebookJSONVersion is the current version of the JSON format. It has to be
+// incremented when blocks change in a way that older versions of Sourcegraph
+// cannot import.
+const notebookJSONVersion = 1
+
+// notebookJSON is the JSON format of exported notebooks. Blocks use the same
+// format as stored in the database.
+type notebookJSON struct {
+	Version int            `json:"version"`
+	Title   string         `json:"title"`
+	Blocks  NotebookBlocks `json:"blocks"`
+}
+
+// ExportJSON returns the notebook in the versioned JSON format.
+func ExportJSON(n *Notebook) ([]byte, error) {
+	blocks := n.Blocks
+	if blocks == nil {
+		blocks = NotebookBlocks{}
+	}
+	return json.MarshalIndent(notebookJSON{
+		Version: notebookJSONVersion,
+		Title:   n.Title,
+		Blocks:  blocks,
+	}, "", "  ")
+}
+
+// ImportJSON creates a notebook from the versioned JSON format produced by
+// ExportJSON. The returned notebook is not persisted.
+func ImportJSON(data []byte) (*Notebook, error) {
+	var n notebookJSON
+	if err := json.Unmarshal(data, &n); err != nil {
+		return nil, errors.Wrap(err, "invalid notebook JSON")
+	}
+	if n.Version < 1 || n.Version > notebookJSONVersion {
+		return nil, errors.Errorf("unsupported notebook JSON version: %d", n.Version)
+	}
+	return newImportedNotebook(n.Title, n.Blocks)
+}
+
+// newImportedNotebook validates the title and blocks of an imported notebook.
+func newImportedNotebook(title string, blocks NotebookBlocks) (*Notebook, error) {
+	if strings.TrimSpace(title) == "" {
+		return nil, errors.New("notebook title cannot be empty")
+	}
+	if blocks == nil {
+		blocks = NotebookBlocks{}
+	}
+	if err := validateNotebookBlocks(blocks); err != nil {
+		return nil, err
+	}
+	return &Notebook{Title: title, Blocks: blocks}, nil
+}
@@ -0,0 +1,64 @@
+package notebooks
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hexops/autogold/v2"
+)
+
+func TestExportJSON(t *testing.T) {
+	got, err := ExportJSON(&Notebook{Title: "Runbook", Blocks: NotebookBlocks{
+		{ID: "1", Type: NotebookQueryBlockType, QueryInput: &NotebookQueryBlockInput{Text: "repo:a b"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	autogold.Expect(`{
+  "version": 1,
+  "title": "Runbook",
+  "blocks": [
+    {
+      "id": "1",
+      "type": "query",
+      "queryInput": {
+        "text": "repo:a b"
+      }
+    }
+  ]
+}`).Equal(t, string(got))
+}
+
+func TestImportJSON(t *testing.T) {
+	want := exportFixture()
+	data, err := ExportJSON(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ImportJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("unexpected notebook (-want +got):\n%s", diff)
+	}
+
+	tests := []struct {
+		json    string
+		wantErr string
+	}{
+		{json: `{`, wantErr: "invalid notebook JSON: unexpected end of JSON input"},
+		{json: `{"title":"a","blocks":[]}`, wantErr: "unsupported notebook JSON version: 0"},
+		{json: `{"version":2,"title":"a","blocks":[]}`, wantErr: "unsupported notebook JSON version: 2"},
+		{json: `{"version":1,"title":" ","blocks":[]}`, wantErr: "notebook title cannot be empty"},
+		{json: `{"version":1,"title":"a","blocks":[{"id":"1","type":"query"}]}`, wantErr: "invalid query block with id: 1"},
+	}
+	for _, tt := range tests {
+		_, err := ImportJSON([]byte(tt.json))
+		if err == nil {
+			t.Fatalf("expected error for %s", tt.json)
+		} else if err.Error() != tt.wantErr {
+			t.Fatalf("wanted '%s' error, got '%s'", tt.wantErr, err.Error())
+		}
+	}
+}
@@ -0,0 +1,394 @@
+package notebooks
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// The Markdown format matches the one used by the web app to export and import
+// notebooks (see client/web/src/notebooks/serialize):
+//
+//   - Markdown blocks are included as-is.
+//   - Query blocks are fenced code blocks with the "sourcegraph" language.
+//   - File blocks are permalinks to the file on their own line.
+//   - Symbol blocks are permalinks to the file on their own line, with the
+//     symbol in the URL fragment.
+//
+// Blocks that the web app does not support yet are fenced code blocks with a
+// "sourcegraph-<type>" language. Adjacent Markdown blocks are merged into a
+// single block on import.
+const (
+	markdownQueryLanguage         = "sourcegraph"
+	markdownComputeLanguage       = "sourcegraph-compute"
+	markdownComputeOutputLanguage = "sourcegraph-compute-output"
+	markdownInsightLanguage       = "sourcegraph-insight"
+	markdownReferencesLanguage    = "sourcegraph-references"
+)
+
+// ExportMarkdown renders the blocks of the notebook as Markdown. Permalinks are
+// relative to externalURL. The title is not part of the Markdown, and is
+// usually used as the file name instead.
+func ExportMarkdown(n *Notebook, externalURL *url.URL) string {
+	serialized := make([]string, 0, len(n.Blocks))
+	for _, block := range n.Blocks {
+		if s := exportMarkdownBlock(block, externalURL); s != "" {
+			serialized = append(serialized, s)
+		}
+	}
+	return strings.Join(serialized, "\n\n") + "\n"
+}
+
+func exportMarkdownBlock(block NotebookBlock, externalURL *url.URL) string {
+	switch block.Type {
+	case NotebookMarkdownBlockType:
+		return strings.TrimRightFunc(block.MarkdownInput.Text, unicode.IsSpace)
+	case NotebookQueryBlockType:
+		return fencedCodeBlock(markdownQueryLanguage, block.QueryInput.Text)
+	case NotebookFileBlockType:
+		input := block.FileInput
+		var position string
+		if input.LineRange != nil {
+			position = formatLineRange(*input.LineRange)
+		}
+		return blobPermalink(externalURL, input.RepositoryName, input.Revision, input.FilePath, position, "")
+	case NotebookSymbolBlockType:
+		input := block.SymbolInput
+		return blobPermalink(externalURL, input.RepositoryName, input.Revision, input.FilePath, "", encodeParams(
+			"symbolName", input.SymbolName,
+			"symbolContainerName", input.SymbolContainerName,
+			"symbolKind", input.SymbolKind,
+			"lineContext", strconv.Itoa(int(input.LineContext)),
+		))
+	case NotebookComputeBlockType:
+		s := fencedCodeBlock(markdownComputeLanguage, block.ComputeInput.Text)
+		if block.ComputeInput.Output != nil {
+			s += "\n\n" + fencedCodeBlock(markdownComputeOutputLanguage, *block.ComputeInput.Output)
+		}
+		return s
+	case NotebookInsightBlockType:
+		return fencedCodeBlock(markdownInsightLanguage, block.InsightInput.SeriesID)
+	case NotebookReferencesBlockType:
+		input := block.ReferencesInput
+		position := fmt.Sprintf("L%d:%d", input.Line+1, input.Character+1)
+		return fencedCodeBlock(markdownReferencesLanguage, blobPermalink(externalURL, input.RepositoryName, &input.Commit, input.FilePath, position, encodeParams(
+			"symbolName", input.SymbolName,
+		)))
+	}
+	return ""
+}
+
+// fencedCodeBlock returns a fenced code block with the given content. The fence
+// is longer than any run of backticks in the content.
+func fencedCodeBlock(language, content string) string {
+	longestRun, run := 0, 0
+	for _, r := range content {
+		if r == '`' {
+			run++
+			longestRun = max(longestRun, run)
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", max(3, longestRun+1))
+	return fence + language + "\n" + content + "\n" + fence
+}
+
+// blobPermalink returns the URL of the file on the Sourcegraph instance, e.g.
+// https://sourcegraph.com/github.com/sourcegraph/sourcegraph@main/-/blob/README.md?L1-10.
+func blobPermalink(externalURL *url.URL, repositoryName string, revision *string, filePath, position, fragment string) string {
+	var u url.URL
+	if externalURL != nil {
+		u = *externalURL
+	}
+	repoRevision := repositoryName
+	if revision != nil && *revision != "" {
+		repoRevision += "@" + *revision
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + repoRevision + "/-/blob/" + filePath
+	u.RawPath = ""
+	u.RawQuery = position
+	u.RawFragment = ""
+	u.Fragment = ""
+	s := u.String()
+	if fragment != "" {
+		s += "#" + fragment
+	}
+	return s
+}
+
+// encodeParams encodes the given key-value pairs as URL query parameters,
+// preserving their order.
+func encodeParams(keyValues ...string) string {
+	params := make([]string, 0, len(keyValues)/2)
+	for i := 0; i+1 < len(keyValues); i += 2 {
+		params = append(params, url.QueryEscape(keyValues[i])+"="+url.QueryEscape(keyValues[i+1]))
+	}
+	return strings.Join(params, "&")
+}
+
+func formatLineRange(lineRange LineRange) string {
+	if lineRange.StartLine+1 >= lineRange.EndLine {
+		return fmt.Sprintf("L%d", lineRange.StartLine+1)
+	}
+	return fmt.Sprintf("L%d-%d", lineRange.StartLine+1, lineRange.EndLine)
+}
+
+// ImportMarkdown creates a notebook with the given title from Markdown in the
+// format produced by ExportMarkdown. externalURL is used to resolve permalinks
+// if the instance is served from a sub-path. The returned notebook is not
+// persisted.
+func ImportMarkdown(title, markdown string, externalURL *url.URL) (*Notebook, error) {
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+
+	var blocks NotebookBlocks
+	var markdownLines []string
+	flushMarkdown := func() {
+		if text := strings.TrimSpace(strings.Join(markdownLines, "\n")); text != "" {
+			blocks = append(blocks, NotebookBlock{ID: newBlockID(), Type: NotebookMarkdownBlockType, MarkdownInput: &NotebookMarkdownBlockInput{Text: text}})
+		}
+		markdownLines = markdownLines[:0]
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		fence, language, ok := parseFenceOpening(line)
+		if !ok {
+			// Permalinks on their own line are file or symbol blocks.
+			isParagraph := (i == 0 || strings.TrimSpace(lines[i-1]) == "") && (i == len(lines)-1 || strings.TrimSpace(lines[i+1]) == "")
+			if isParagraph {
+				if block, ok := parsePermalinkBlock(strings.TrimSpace(line), externalURL); ok {
+					flushMarkdown()
+					blocks = append(blocks, block)
+					continue
+				}
+			}
+			markdownLines = append(markdownLines, line)
+			continue
+		}
+
+		end := i + 1
+		for end < len(lines) && !isFenceClosing(lines[end], fence) {
+			end++
+		}
+		if language != markdownQueryLanguage && !strings.HasPrefix(language, markdownQueryLanguage+"-") {
+			// Regular code blocks are part of the Markdown.
+			markdownLines = append(markdownLines, lines[i:min(end+1, len(lines))]...)
+			i = end
+			continue
+		}
+		if end == len(lines) {
+			return nil, errors.Errorf("unterminated %q code block on line %d", language, i+1)
+		}
+		content := strings.Join(lines[i+1:end], "\n")
+		i = end
+
+		flushMarkdown()
+		if language == markdownComputeOutputLanguage {
+			if len(blocks) == 0 || blocks[len(blocks)-1].Type != NotebookComputeBlockType {
+				return nil, errors.Errorf("%q code block on line %d does not follow a compute block", language, i+1)
+			}
+			blocks[len(blocks)-1].ComputeInput.Output = &content
+			continue
+		}
+		block, err := parseCodeBlock(language, content, externalURL)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %q code block", language)
+		}
+		blocks = append(blocks, block)
+	}
+	flushMarkdown()
+
+	return newImportedNotebook(title, blocks)
+}
+
+func newBlockID() string {
+	return uuid.NewString()
+}
+
+// parseFenceOpening returns the fence and language of a line opening a fenced
+// code block.
+func parseFenceOpening(line string) (fence, language string, ok bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return "", "", false
+	}
+	for _, c := range []string{"`", "~"} {
+		n := len(trimmed) - len(strings.TrimLeft(trimmed, c))
+		if n >= 3 {
+			info := strings.TrimSpace(trimmed[n:])
+			if c == "`" && strings.Contains(info, "`") {
+				return "", "", false
+			}
+			language, _, _ = strings.Cut(info, " ")
+			return trimmed[:n], language, true
+		}
+	}
+	return "", "", false
+}
+
+func isFenceClosing(line, fence string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == ""
+}
+
+func parseCodeBlock(language, content string, externalURL *url.URL) (NotebookBlock, error) {
+	block := NotebookBlock{ID: newBlockID()}
+	switch language {
+	case markdownQueryLanguage:
+		block.Type = NotebookQueryBlockType
+		block.QueryInput = &NotebookQueryBlockInput{Text: content}
+	case markdownComputeLanguage:
+		block.Type = NotebookComputeBlockType
+		block.ComputeInput = &NotebookComputeBlockInput{Text: content}
+	case markdownInsightLanguage:
+		block.Type = NotebookInsightBlockType
+		block.InsightInput = &NotebookInsightBlockInput{SeriesID: strings.TrimSpace(content)}
+	case markdownReferencesLanguage:
+		permalink, err := parseBlobPermalink(strings.TrimSpace(content), externalURL)
+		if err != nil {
+			return block, err
+		}
+		if permalink.revision == nil {
+			return block, errors.New("permalink is missing a commit")
+		}
+		line, character, ok := parsePosition(permalink.position)
+		if !ok {
+			return block, errors.Newf("invalid position %q", permalink.position)
+		}
+		block.Type = NotebookReferencesBlockType
+		block.ReferencesInput = &NotebookReferencesBlockInput{
+			RepositoryName: permalink.repositoryName,
+			FilePath:       permalink.filePath,
+			Commit:         *permalink.revision,
+			Line:           line,
+			Character:      character,
+			SymbolName:     permalink.fragment.Get("symbolName"),
+		}
+	default:
+		return block, errors.New("unknown block type")
+	}
+	return block, nil
+}
+
+func parsePermalinkBlock(line string, externalURL *url.URL) (NotebookBlock, bool) {
+	if !strings.HasPrefix(line, "http://") && !strings.HasPrefix(line, "https://") {
+		return NotebookBlock{}, false
+	}
+	permalink, err := parseBlobPermalink(line, externalURL)
+	if err != nil {
+		return NotebookBlock{}, false
+	}
+
+	if symbolName := permalink.fragment.Get("symbolName"); symbolName != "" {
+		lineContext, err := strconv.Atoi(permalink.fragment.Get("lineContext"))
+		if err != nil {
+			lineContext = defaultSymbolLineContext
+		}
+		return NotebookBlock{ID: newBlockID(), Type: NotebookSymbolBlockType, SymbolInput: &NotebookSymbolBlockInput{
+			RepositoryName:      permalink.repositoryName,
+			FilePath:            permalink.filePath,
+			Revision:            permalink.revision,
+			LineContext:         int32(lineContext),
+			SymbolName:          symbolName,
+			SymbolContainerName: permalink.fragment.Get("symbolContainerName"),
+			SymbolKind:          permalink.fragment.Get("symbolKind"),
+		}}, true
+	}
+
+	block := NotebookBlock{ID: newBlockID(), Type: NotebookFileBlockType, FileInput: &NotebookFileBlockInput{
+		RepositoryName: permalink.repositoryName,
+		FilePath:       permalink.filePath,
+		Revision:       permalink.revision,
+	}}
+	if lineRange, ok := parseLineRange(permalink.position); ok {
+		block.FileInput.LineRange = &lineRange
+	}
+	return block, true
+}
+
+// defaultSymbolLineContext matches the default of the web app.
+const defaultSymbolLineContext = 3
+
+type blobPermalinkParts struct {
+	repositoryName string
+	revision       *string
+	filePath       string
+	// position is the line range or position in the query, e.g. L1-10.
+	position string
+	fragment url.Values
+}
+
+func parseBlobPermalink(s string, externalURL *url.URL) (blobPermalinkParts, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return blobPermalinkParts{}, err
+	}
+	path := u.Path
+	if externalURL != nil {
+		// Links to other hosts are not permalinks of this instance, even if
+		// their path looks like one.
+		if u.Host != externalURL.Host {
+			return blobPermalinkParts{}, errors.Newf("not a permalink of %s: %q", externalURL.Host, s)
+		}
+		path = strings.TrimPrefix(path, strings.TrimSuffix(externalURL.Path, "/"))
+	}
+	repoRevision, filePath, ok := strings.Cut(strings.TrimPrefix(path, "/"), "/-/blob/")
+	if !ok || repoRevision == "" || filePath == "" {
+		return blobPermalinkParts{}, errors.Newf("not a file permalink: %q", s)
+	}
+	fragment, err := url.ParseQuery(u.Fragment)
+	if err != nil {
+		return blobPermalinkParts{}, err
+	}
+
+	parts := blobPermalinkParts{filePath: filePath, fragment: fragment}
+	repositoryName, revision, ok := strings.Cut(repoRevision, "@")
+	parts.repositoryName = repositoryName
+	if ok && revision != "" {
+		parts.revision = &revision
+	}
+	// The position is a query parameter without a value, e.g. ?L1-10.
+	for _, param := range strings.Split(u.RawQuery, "&") {
+		if strings.HasPrefix(param, "L") {
+			parts.position = param
+		}
+	}
+	return parts, nil
+}
+
+var (
+	lineRangePattern = lazyregexp.New(`^L(\d+)(?:-(\d+))?$`)
+	positionPattern  = lazyregexp.New(`^L(\d+):(\d+)$`)
+)
+
+func parseLineRange(s string) (LineRange, bool) {
+	m := lineRangePattern.FindStringSubmatch(s)
+	if m == nil {
+		return LineRange{}, false
+	}
+	start, _ := strconv.Atoi(m[1])
+	end := start
+	if m[2] != "" {
+		end, _ = strconv.Atoi(m[2])
+	}
+	return LineRange{StartLine: int32(start - 1), EndLine: int32(end)}, true
+}
+
+func parsePosition(s string) (line, character int32, ok bool) {
+	m := positionPattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0, 0, false
+	}
+	l, _ := strconv.Atoi(m[1])
+	c, _ := strconv.Atoi(m[2])
+	return int32(l - 1), int32(c - 1), true
+}
@@ -0,0 +1,128 @@
+package notebooks
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hexops/autogold/v2"
+)
+
+func exportFixture() *Notebook {
+	revision := "main"
+	computeOutput := "alice\nbob"
+	return &Notebook{Title: "Runbook", Blocks: NotebookBlocks{
+		{ID: "1", Type: NotebookMarkdownBlockType, MarkdownInput: &NotebookMarkdownBlockInput{Text: "# Runbook\n\n```go\nfmt.Println(1)\n```\n"}},
+		{ID: "2", Type: NotebookQueryBlockType, QueryInput: &NotebookQueryBlockInput{Text: "repo:a b"}},
+		{ID: "3", Type: NotebookFileBlockType, FileInput: &NotebookFileBlockInput{
+			RepositoryName: "github.com/sourcegraph/sourcegraph",
+			FilePath:       "client/web/file name.tsx",
+			Revision:       &revision,
+			LineRange:      &LineRange{StartLine: 9, EndLine: 12},
+		}},
+		{ID: "4", Type: NotebookFileBlockType, FileInput: &NotebookFileBlockInput{
+			RepositoryName: "github.com/sourcegraph/sourcegraph",
+			FilePath:       "README.md",
+		}},
+		{ID: "5", Type: NotebookSymbolBlockType, SymbolInput: &NotebookSymbolBlockInput{
+			RepositoryName:      "github.com/sourcegraph/sourcegraph",
+			FilePath:            "internal/notebooks/types.go",
+			Revision:            &revision,
+			LineContext:         1,
+			SymbolName:          "NotebookBlock",
+			SymbolContainerName: "notebooks",
+			SymbolKind:          "STRUCT",
+		}},
+		{ID: "6", Type: NotebookComputeBlockType, ComputeInput: &NotebookComputeBlockInput{Text: "content:output(.* -> $author) type:commit", Output: &computeOutput}},
+		{ID: "7", Type: NotebookInsightBlockType, InsightInput: &NotebookInsightBlockInput{SeriesID: "series1"}},
+		{ID: "8", Type: NotebookReferencesBlockType, ReferencesInput: &NotebookReferencesBlockInput{
+			RepositoryName: "github.com/sourcegraph/sourcegraph",
+			FilePath:       "internal/notebooks/types.go",
+			Commit:         "a9505a2947d3df53558e8c88ff8bcef390fc4e3e",
+			Line:           10,
+			Character:      5,
+			SymbolName:     "NotebookBlock",
+		}},
+	}}
+}
+
+func TestExportMarkdown(t *testing.T) {
+	externalURL, _ := url.Parse("https://sourcegraph.example.com")
+	autogold.Expect("# Runbook\n\n```go\nfmt.Println(1)\n```\n\n```sourcegraph\nrepo:a b\n```\n\nhttps://sourcegraph.example.com/github.com/sourcegraph/sourcegraph@main/-/blob/client/web/file%20name.tsx?L10-12\n\nhttps://sourcegraph.example.com/github.com/sourcegraph/sourcegraph/-/blob/README.md\n\nhttps://sourcegraph.example.com/github.com/sourcegraph/sourcegraph@main/-/blob/internal/notebooks/types.go#symbolName=NotebookBlock&symbolContainerName=notebooks&symbolKind=STRUCT&lineContext=1\n\n```sourcegraph-compute\ncontent:output(.* -> $author) type:commit\n```\n\n```sourcegraph-compute-output\nalice\nbob\n```\n\n```sourcegraph-insight\nseries1\n```\n\n```sourcegraph-references\nhttps://sourcegraph.example.com/github.com/sourcegraph/sourcegraph@a9505a2947d3df53558e8c88ff8bcef390fc4e3e/-/blob/internal/notebooks/types.go?L11:6#symbolName=NotebookBlock\n```\n").Equal(t, ExportMarkdown(exportFixture(), externalURL))
+}
+
+func TestImportMarkdown(t *testing.T) {
+	for _, externalURL := range []string{"https://sourcegraph.example.com", "https://example.com/sourcegraph/"} {
+		t.Run(externalURL, func(t *testing.T) {
+			u, _ := url.Parse(externalURL)
+			want := exportFixture()
+
+			got, err := ImportMarkdown(want.Title, ExportMarkdown(want, u), u)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range got.Blocks {
+				got.Blocks[i].ID = want.Blocks[i].ID
+			}
+			// Trailing whitespace of Markdown blocks is not preserved.
+			want.Blocks[0].MarkdownInput.Text = "# Runbook\n\n```go\nfmt.Println(1)\n```"
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Fatalf("unexpected notebook (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("merges adjacent markdown", func(t *testing.T) {
+		got, err := ImportMarkdown("Title", "# A\n\nsome text https://sourcegraph.com/a/-/blob/b\n\n~~~\n```sourcegraph\nnot a query\n```\n~~~\n\n```sourcegraph\nrepo:a\n```\n", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got.Blocks) != 2 {
+			t.Fatalf("expected 2 blocks, got %d", len(got.Blocks))
+		}
+		autogold.Expect("# A\n\nsome text https://sourcegraph.com/a/-/blob/b\n\n~~~\n```sourcegraph\nnot a query\n```\n~~~").Equal(t, got.Blocks[0].MarkdownInput.Text)
+		autogold.Expect("repo:a").Equal(t, got.Blocks[1].QueryInput.Text)
+	})
+
+	t.Run("links to other hosts are not permalinks", func(t *testing.T) {
+		u, _ := url.Parse("https://sourcegraph.example.com")
+		got, err := ImportMarkdown("Title", "https://evil.example.com/a/-/blob/b\n", u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got.Blocks) != 1 || got.Blocks[0].Type != NotebookMarkdownBlockType {
+			t.Fatalf("expected a single markdown block, got %+v", got.Blocks)
+		}
+
+		_, err = ImportMarkdown("Title", "```sourcegraph-references\nhttps://evil.example.com/a@abc/-/blob/b?L1:1\n```", u)
+		if err == nil || !strings.Contains(err.Error(), "not a permalink of sourcegraph.example.com") {
+			t.Fatalf("expected host error, got %v", err)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			title    string
+			markdown string
+			wantErr  string
+		}{
+			{title: "", markdown: "# A", wantErr: "notebook title cannot be empty"},
+			{title: "A", markdown: "```sourcegraph\nrepo:a", wantErr: `unterminated "sourcegraph" code block on line 1`},
+			{title: "A", markdown: "```sourcegraph-unknown\na\n```", wantErr: `invalid "sourcegraph-unknown" code block: unknown block type`},
+			{title: "A", markdown: "```sourcegraph-compute-output\na\n```", wantErr: `"sourcegraph-compute-output" code block on line 3 does not follow a compute block`},
+			{title: "A", markdown: "```sourcegraph-references\nhttps://sourcegraph.com/a/-/blob/b?L1:1\n```", wantErr: `invalid "sourcegraph-references" code block: permalink is missing a commit`},
+			{title: "A", markdown: "```sourcegraph-insight\n\n```", wantErr: "insight block series id cannot be empty, block id: "},
+		}
+		for _, tt := range tests {
+			_, err := ImportMarkdown(tt.title, tt.markdown, nil)
+			if err == nil {
+				t.Fatalf("expected error for %q", tt.markdown)
+			}
+			// Block IDs are random.
+			if got := err.Error(); got[:min(len(got), len(tt.wantErr))] != tt.wantErr {
+				t.Fatalf("wanted '%s' error, got '%s'", tt.wantErr, got)
+			}
+		}
+	})
+}
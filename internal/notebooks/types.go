@@ -7,10 +7,13 @@ import (
 type NotebookBlockType string
 
 const (
-	NotebookQueryBlockType    NotebookBlockType = "query"
-	NotebookMarkdownBlockType NotebookBlockType = "md"
-	NotebookFileBlockType     NotebookBlockType = "file"
-	NotebookSymbolBlockType   NotebookBlockType = "symbol"
+	NotebookQueryBlockType      NotebookBlockType = "query"
+	NotebookMarkdownBlockType   NotebookBlockType = "md"
+	NotebookFileBlockType       NotebookBlockType = "file"
+	NotebookSymbolBlockType     NotebookBlockType = "symbol"
+	NotebookComputeBlockType    NotebookBlockType = "compute"
+	NotebookInsightBlockType    NotebookBlockType = "insight"
+	NotebookReferencesBlockType NotebookBlockType = "references"
 )
 
 type NotebookQueryBlockInput struct {
@@ -46,13 +49,47 @@ type NotebookSymbolBlockInput struct {
 	SymbolKind          string  `json:"symbolKind"`
 }
 
+type NotebookComputeBlockInput struct {
+	// Text is the compute expression, e.g. "content:output(.* -> $author)".
+	Text string `json:"text"`
+
+	// Output is the rendered output of the expression when the block was last
+	// run, so that the notebook can be displayed without running it again.
+	Output *string `json:"output,omitempty"`
+}
+
+type NotebookInsightBlockInput struct {
+	// SeriesID is the ID of the Code Insights series to embed.
+	SeriesID string `json:"seriesId"`
+}
+
+type NotebookReferencesBlockInput struct {
+	RepositoryName string `json:"repositoryName"`
+	FilePath       string `json:"filePath"`
+
+	// Commit is the absolute commit ID the references are pinned to, so that
+	// precise code intel data is available for it.
+	Commit string `json:"commit"`
+
+	// Line is the 0-based line of the symbol occurrence.
+	Line int32 `json:"line"`
+
+	// Character is the 0-based character offset of the symbol occurrence.
+	Character int32 `json:"character"`
+
+	SymbolName string `json:"symbolName"`
+}
+
 type NotebookBlock struct {
-	ID            string                      `json:"id"`
-	Type          NotebookBlockType           `json:"type"`
-	QueryInput    *NotebookQueryBlockInput    `json:"queryInput,omitempty"`
-	MarkdownInput *NotebookMarkdownBlockInput `json:"markdownInput,omitempty"`
-	FileInput     *NotebookFileBlockInput     `json:"fileInput,omitempty"`
-	SymbolInput   *NotebookSymbolBlockInput   `json:"symbolInput,omitempty"`
+	ID              string                        `json:"id"`
+	Type            NotebookBlockType             `json:"type"`
+	QueryInput      *NotebookQueryBlockInput      `json:"queryInput,omitempty"`
+	MarkdownInput   *NotebookMarkdownBlockInput   `json:"markdownInput,omitempty"`
+	FileInput       *NotebookFileBlockInput       `json:"fileInput,omitempty"`
+	SymbolInput     *NotebookSymbolBlockInput     `json:"symbolInput,omitempty"`
+	ComputeInput    *NotebookComputeBlockInput    `json:"computeInput,omitempty"`
+	InsightInput    *NotebookInsightBlockInput    `json:"insightInput,omitempty"`
+	ReferencesInput *NotebookReferencesBlockInput `json:"referencesInput,omitempty"`
 }
 
 type NotebookBlocks []NotebookBlock
@@ -12,6 +12,10 @@ func TestNotebookBlockMarshalling(t *testing.T) {
 	markdownBlockInput := NotebookMarkdownBlockInput{Text: "# Title"}
 	revision := "main"
 	fileBlockInput := NotebookFileBlockInput{RepositoryName: "sourcegraph/sourcegraph", FilePath: "a/b.ts", Revision: &revision, LineRange: &LineRange{1, 10}}
+	computeOutput := "a\nb"
+	computeBlockInput := NotebookComputeBlockInput{Text: "content:output(.* -> $author) type:commit", Output: &computeOutput}
+	insightBlockInput := NotebookInsightBlockInput{SeriesID: "series1"}
+	referencesBlockInput := NotebookReferencesBlockInput{RepositoryName: "sourcegraph/sourcegraph", FilePath: "a/b.go", Commit: "a9505a2947d3df53558e8c88ff8bcef390fc4e3e", Line: 1, Character: 5, SymbolName: "B"}
 
 	tests := []struct {
 		block NotebookBlock
@@ -29,6 +33,18 @@ func TestNotebookBlockMarshalling(t *testing.T) {
 			block: NotebookBlock{ID: "id1", Type: NotebookFileBlockType, FileInput: &fileBlockInput},
 			want:  autogold.Expect(`{"id":"id1","type":"file","fileInput":{"repositoryName":"sourcegraph/sourcegraph","filePath":"a/b.ts","revision":"main","lineRange":{"startLine":1,"endLine":10}}}`),
 		},
+		{
+			block: NotebookBlock{ID: "id1", Type: NotebookComputeBlockType, ComputeInput: &computeBlockInput},
+			want:  autogold.Expect(`{"id":"id1","type":"compute","computeInput":{"text":"content:output(.* -\u003e $author) type:commit","output":"a\nb"}}`),
+		},
+		{
+			block: NotebookBlock{ID: "id1", Type: NotebookInsightBlockType, InsightInput: &insightBlockInput},
+			want:  autogold.Expect(`{"id":"id1","type":"insight","insightInput":{"seriesId":"series1"}}`),
+		},
+		{
+			block: NotebookBlock{ID: "id1", Type: NotebookReferencesBlockType, ReferencesInput: &referencesBlockInput},
+			want:  autogold.Expect(`{"id":"id1","type":"references","referencesInput":{"repositoryName":"sourcegraph/sourcegraph","filePath":"a/b.go","commit":"a9505a2947d3df53558e8c88ff8bcef390fc4e3e","line":1,"character":5,"symbolName":"B"}}`),
+		},
 	}
 
 	for _, tt := range tests {
@@ -45,6 +61,10 @@ func TestNotebookBlockUnmarshalling(t *testing.T) {
 	markdownBlockInput := NotebookMarkdownBlockInput{Text: "# Title"}
 	revision := "main"
 	fileBlockInput := NotebookFileBlockInput{RepositoryName: "sourcegraph/sourcegraph", FilePath: "a/b.ts", Revision: &revision, LineRange: &LineRange{1, 10}}
+	computeOutput := "a\nb"
+	computeBlockInput := NotebookComputeBlockInput{Text: "content:output(.* -> $author) type:commit", Output: &computeOutput}
+	insightBlockInput := NotebookInsightBlockInput{SeriesID: "series1"}
+	referencesBlockInput := NotebookReferencesBlockInput{RepositoryName: "sourcegraph/sourcegraph", FilePath: "a/b.go", Commit: "a9505a2947d3df53558e8c88ff8bcef390fc4e3e", Line: 1, Character: 5, SymbolName: "B"}
 
 	tests := []struct {
 		json string
@@ -62,6 +82,18 @@ func TestNotebookBlockUnmarshalling(t *testing.T) {
 			json: `{"id":"id1","type":"file","fileInput":{"repositoryName":"sourcegraph/sourcegraph","filePath":"a/b.ts","revision":"main","lineRange":{"startLine":1,"endLine":10}}}`,
 			want: autogold.Expect(NotebookBlock{ID: "id1", Type: NotebookFileBlockType, FileInput: &fileBlockInput}),
 		},
+		{
+			json: `{"id":"id1","type":"compute","computeInput":{"text":"content:output(.* -> $author) type:commit","output":"a\nb"}}`,
+			want: autogold.Expect(NotebookBlock{ID: "id1", Type: NotebookComputeBlockType, ComputeInput: &computeBlockInput}),
+		},
+		{
+			json: `{"id":"id1","type":"insight","insightInput":{"seriesId":"series1"}}`,
+			want: autogold.Expect(NotebookBlock{ID: "id1", Type: NotebookInsightBlockType, InsightInput: &insightBlockInput}),
+		},
+		{
+			json: `{"id":"id1","type":"references","referencesInput":{"repositoryName":"sourcegraph/sourcegraph","filePath":"a/b.go","commit":"a9505a2947d3df53558e8c88ff8bcef390fc4e3e","line":1,"character":5,"symbolName":"B"}}`,
+			want: autogold.Expect(NotebookBlock{ID: "id1", Type: NotebookReferencesBlockType, ReferencesInput: &referencesBlockInput}),
+		},
 	}
 
 	for _, tt := range tests {
@@ -5,6 +5,7 @@ import (
 )
 
 func TestNotebookBlocksValidation(t *testing.T) {
+	commit := "a9505a2947d3df53558e8c88ff8bcef390fc4e3e"
 	tests := []struct {
 		blocks  NotebookBlocks
 		wantErr string
@@ -22,6 +23,24 @@ func TestNotebookBlocksValidation(t *testing.T) {
 		{blocks: NotebookBlocks{
 			{ID: "id1", SymbolInput: &NotebookSymbolBlockInput{LineContext: -10}, Type: NotebookSymbolBlockType},
 		}, wantErr: "symbol block line context cannot be negative, block id: id1"},
+		{blocks: NotebookBlocks{{ID: "id1", Type: NotebookComputeBlockType}}, wantErr: "invalid compute block with id: id1"},
+		{blocks: NotebookBlocks{
+			{ID: "id1", Type: NotebookComputeBlockType, ComputeInput: &NotebookComputeBlockInput{Text: "(a or b) and c"}},
+		}, wantErr: "invalid compute expression, block id: id1: compute endpoint cannot currently support expressions in patterns containing 'and', 'or', 'not' (or negation) right now!"},
+		{blocks: NotebookBlocks{{ID: "id1", Type: NotebookInsightBlockType}}, wantErr: "invalid insight block with id: id1"},
+		{blocks: NotebookBlocks{
+			{ID: "id1", Type: NotebookInsightBlockType, InsightInput: &NotebookInsightBlockInput{}},
+		}, wantErr: "insight block series id cannot be empty, block id: id1"},
+		{blocks: NotebookBlocks{{ID: "id1", Type: NotebookReferencesBlockType}}, wantErr: "invalid references block with id: id1"},
+		{blocks: NotebookBlocks{
+			{ID: "id1", Type: NotebookReferencesBlockType, ReferencesInput: &NotebookReferencesBlockInput{FilePath: "a.go", Commit: commit}},
+		}, wantErr: "references block requires a repository and file path, block id: id1"},
+		{blocks: NotebookBlocks{
+			{ID: "id1", Type: NotebookReferencesBlockType, ReferencesInput: &NotebookReferencesBlockInput{RepositoryName: "a", FilePath: "a.go", Commit: "main"}},
+		}, wantErr: "references block commit must be a full commit ID, block id: id1"},
+		{blocks: NotebookBlocks{
+			{ID: "id1", Type: NotebookReferencesBlockType, ReferencesInput: &NotebookReferencesBlockInput{RepositoryName: "a", FilePath: "a.go", Commit: commit, Line: -1}},
+		}, wantErr: "references block position cannot be negative, block id: id1"},
 	}
 
 	for _, tt := range tests {
@@ -33,3 +52,21 @@ func TestNotebookBlocksValidation(t *testing.T) {
 		}
 	}
 }
+
+func TestNotebookBlocksValidation_Valid(t *testing.T) {
+	blocks := NotebookBlocks{
+		{ID: "id1", Type: NotebookComputeBlockType, ComputeInput: &NotebookComputeBlockInput{Text: "content:output(.* -> $author) type:commit"}},
+		{ID: "id2", Type: NotebookInsightBlockType, InsightInput: &NotebookInsightBlockInput{SeriesID: "2Gv6Mj6NrO9aYrsXjvEyvbbQPkc"}},
+		{ID: "id3", Type: NotebookReferencesBlockType, ReferencesInput: &NotebookReferencesBlockInput{
+			RepositoryName: "github.com/sourcegraph/sourcegraph",
+			FilePath:       "internal/notebooks/types.go",
+			Commit:         "a9505a2947d3df53558e8c88ff8bcef390fc4e3e",
+			Line:           10,
+			Character:      5,
+			SymbolName:     "NotebookBlock",
+		}},
+	}
+	if err := validateNotebookBlocks(blocks); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
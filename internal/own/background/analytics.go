@@ -2,11 +2,13 @@ package background
 
 import (
 	"context"
+	"sort"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/sourcegraph/log"
+	"golang.org/x/exp/maps"
 
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
@@ -70,13 +72,18 @@ func (r *analyticsIndexer) indexRepo(ctx context.Context, repoId api.RepoID, che
 	if err != nil {
 		return errcode.MakeNonRetryable(errors.Wrapf(err, "cannot resolve HEAD"))
 	}
-	isOwnedViaCodeowners := r.codeowners(ctx, repo, commitID)
+	codeowners := r.codeowners(ctx, repo, commitID)
 	isOwnedViaAssignedOwnership := r.assignedOwners(ctx, repo, commitID)
 	var totalCount int
 	var ownCounts database.PathAggregateCounts
+	codeownersCounts := map[string]int{}
 	for _, f := range files {
 		totalCount++
-		countCodeowners := isOwnedViaCodeowners(f)
+		references := codeowners(f)
+		for _, ref := range references {
+			codeownersCounts[ref]++
+		}
+		countCodeowners := len(references) > 0
 		countAssignedOwnership := isOwnedViaAssignedOwnership(f)
 		if countCodeowners {
 			ownCounts.CodeownedFileCount++
@@ -105,24 +112,46 @@ func (r *analyticsIndexer) indexRepo(ctx context.Context, repoId api.RepoID, che
 	if rowCount == 0 {
 		return errors.New("expected CODEOWNERS-owned file count update")
 	}
+	// Owners that no longer own any files in the repo are reset to zero, so that
+	// the repo stops counting as theirs.
+	previousCounts, err := r.db.OwnershipStats().QueryIndividualCounts(ctx, database.TreeLocationOpts{RepoID: repo.ID}, nil)
+	if err != nil {
+		return errors.Wrap(err, "QueryIndividualCounts")
+	}
+	for _, c := range previousCounts {
+		if _, ok := codeownersCounts[c.CodeownersReference]; !ok {
+			codeownersCounts[c.CodeownersReference] = 0
+		}
+	}
+	if _, err := r.db.OwnershipStats().UpdateIndividualCounts(ctx, repo.ID, rootPathCodeownersIterator(codeownersCounts), timestamp); err != nil {
+		return errors.Wrap(err, "UpdateIndividualCounts")
+	}
 	ownAnalyticsFilesCounter.Add(float64(len(files)))
 	return nil
 }
 
-// codeowners pulls a path matcher for repo HEAD.
-// If result function is nil, then no CODEOWNERS file was found.
-func (r *analyticsIndexer) codeowners(ctx context.Context, repo *types.Repo, commitID api.CommitID) func(string) bool {
+// codeowners pulls a path matcher for repo HEAD, which returns the references
+// of the CODEOWNERS owners of a path. A reference is the handle of the owner
+// without @ in front, or their email.
+func (r *analyticsIndexer) codeowners(ctx context.Context, repo *types.Repo, commitID api.CommitID) func(string) []string {
 	ownService := own.NewService(r.client, r.db)
 	ruleset, err := ownService.RulesetForRepo(ctx, repo.Name, repo.ID, commitID)
 	if ruleset == nil || err != nil {
 		// TODO(#53155): Return error in case there is an issue,
 		// but return noRuleset and no error if CODEOWNERS is not found.
-		return noOwners
+		return noCodeowners
 	}
-	return func(path string) bool {
+	return func(path string) []string {
 		rule := ruleset.Match(path)
-		owners := rule.GetOwner()
-		return len(owners) > 0
+		var references []string
+		for _, o := range rule.GetOwner() {
+			if ref := o.GetHandle(); ref != "" {
+				references = append(references, ref)
+			} else if ref := o.GetEmail(); ref != "" {
+				references = append(references, ref)
+			}
+		}
+		return references
 	}
 }
 
@@ -152,6 +181,10 @@ func noOwners(string) bool {
 	return false
 }
 
+func noCodeowners(string) []string {
+	return nil
+}
+
 type rootPathIterator[T any] struct {
 	value T
 }
@@ -159,3 +192,18 @@ type rootPathIterator[T any] struct {
 func (i rootPathIterator[T]) Iterate(f func(path string, value T) error) error {
 	return f("", i.value)
 }
+
+// rootPathCodeownersIterator provides the number of files owned by each CODEOWNERS
+// reference at the repo root.
+type rootPathCodeownersIterator map[string]int
+
+func (i rootPathCodeownersIterator) Iterate(f func(path string, counts database.PathCodeownersCounts) error) error {
+	references := maps.Keys(i)
+	sort.Strings(references)
+	for _, ref := range references {
+		if err := f("", database.PathCodeownersCounts{CodeownersReference: ref, CodeownedFileCount: i[ref]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -96,6 +96,55 @@ func TestAnalyticsIndexerSuccess(t *testing.T) {
 	assert.Equal(t, wantCounts, gotCounts)
 }
 
+func TestAnalyticsIndexerIndividualCounts(t *testing.T) {
+	rcache.SetupForTest(t)
+	obsCtx := observation.TestContextTB(t)
+	logger := obsCtx.Logger
+	db := database.NewDB(logger, dbtest.NewDB(t))
+	ctx := context.Background()
+	var repoID api.RepoID = 1
+	require.NoError(t, db.Repos().Create(ctx, &types.Repo{Name: "repo", ID: repoID}))
+	files := []string{"notOwned.go", "owned/file1.go", "owned/file2.go", "docs/README.md"}
+	checker := authz.NewMockSubRepoPermissionChecker()
+	checker.EnabledFunc.SetDefaultReturn(true)
+	checker.EnabledForRepoIDFunc.SetDefaultReturn(false, nil)
+
+	client := fakeGitServer{
+		files: files,
+		fileContents: map[string]string{
+			"CODEOWNERS": "/owned/* @owner\n/docs/* @owner writer@example.com",
+		},
+	}
+	require.NoError(t, newAnalyticsIndexer(client, db, logger).indexRepo(ctx, repoID, checker))
+
+	gotCounts, err := db.OwnershipStats().QueryIndividualCounts(ctx, database.TreeLocationOpts{RepoID: repoID}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []database.PathCodeownersCounts{
+		{CodeownersReference: "owner", CodeownedFileCount: 3},
+		{CodeownersReference: "writer@example.com", CodeownedFileCount: 1},
+	}, gotCounts)
+	ownedRepos, err := db.OwnershipStats().QueryOwnedRepos(ctx, "writer@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []api.RepoID{repoID}, ownedRepos)
+
+	// Owners removed from CODEOWNERS no longer own the repo.
+	client.fileContents = map[string]string{"CODEOWNERS": "/owned/* @owner"}
+	require.NoError(t, newAnalyticsIndexer(client, db, logger).indexRepo(ctx, repoID, checker))
+
+	gotCounts, err = db.OwnershipStats().QueryIndividualCounts(ctx, database.TreeLocationOpts{RepoID: repoID}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []database.PathCodeownersCounts{
+		{CodeownersReference: "owner", CodeownedFileCount: 2},
+		{CodeownersReference: "writer@example.com", CodeownedFileCount: 0},
+	}, gotCounts)
+	ownedRepos, err = db.OwnershipStats().QueryOwnedRepos(ctx, "writer@example.com")
+	require.NoError(t, err)
+	assert.Empty(t, ownedRepos)
+	ownedRepos, err = db.OwnershipStats().QueryOwnedRepos(ctx, "owner")
+	require.NoError(t, err)
+	assert.Equal(t, []api.RepoID{repoID}, ownedRepos)
+}
+
 func TestAnalyticsIndexerSkipsReposWithSubRepoPerms(t *testing.T) {
 	rcache.SetupForTest(t)
 	obsCtx := observation.TestContextTB(t)
@@ -35,6 +35,13 @@ type Service interface {
 	// team of 'src/test' in a given repo transitively owns all files within the
 	// directory tree at that root like 'src/test/com/sourcegraph/Test.java'.
 	AssignedTeams(context.Context, api.RepoID, api.CommitID) (AssignedTeams, error)
+
+	// OwnedRepos returns the IDs of the repositories in which the given owner owns
+	// files, either via CODEOWNERS or via assigned ownership. The owner is a user or
+	// team handle like "@alice" or "@sourcegraph/search", with or without the leading
+	// @, or an email. Ownership via CODEOWNERS is based on the ownership statistics,
+	// so it is only as fresh as the last run of the analytics background job.
+	OwnedRepos(ctx context.Context, owner string) ([]api.RepoID, error)
 }
 
 type AssignedOwners map[string][]database.AssignedOwnerSummary
@@ -171,3 +178,7 @@ func (s *service) AssignedTeams(ctx context.Context, repoID api.RepoID, _ api.Co
 	}
 	return assignedTeams, nil
 }
+
+func (s *service) OwnedRepos(ctx context.Context, owner string) ([]api.RepoID, error) {
+	return s.db.OwnershipStats().QueryOwnedRepos(ctx, strings.TrimPrefix(owner, "@"))
+}
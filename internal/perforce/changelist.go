@@ -2,6 +2,8 @@ package perforce
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"time"
 
@@ -90,6 +92,25 @@ func GetP4ChangelistID(body string) (string, error) {
 	return matches[2], nil
 }
 
+// ChangelistRevSpecPrefix is the prefix of revision specifiers that refer to a
+// changelist instead of a git revision, e.g. `rev:changelist/12345`.
+const ChangelistRevSpecPrefix = "changelist/"
+
+// ParseChangelistRevSpec returns the changelist ID of a revision specifier of
+// the form "changelist/<id>". The second return value is false for all other
+// revision specifiers.
+func ParseChangelistRevSpec(rev string) (int64, bool) {
+	cid, ok := strings.CutPrefix(rev, ChangelistRevSpecPrefix)
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(cid, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
+
 // ChangelistNotFoundError is an error that reports a revision doesn't exist.
 type ChangelistNotFoundError struct {
 	RepoID api.RepoID
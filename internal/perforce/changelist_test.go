@@ -51,3 +51,19 @@ func TestGetP4ChangelistID(t *testing.T) {
 		}
 	}
 }
+
+func TestParseChangelistRevSpec(t *testing.T) {
+	for rev, want := range map[string]int64{
+		"changelist/12345":   12345,
+		"changelist/0":       0,
+		"changelist/-1":      0,
+		"changelist/abc":     0,
+		"changelist/":        0,
+		"main":               0,
+		"refs/changelist/12": 0,
+	} {
+		got, ok := ParseChangelistRevSpec(rev)
+		require.Equal(t, want, got, rev)
+		require.Equal(t, want != 0, ok, rev)
+	}
+}
@@ -31,11 +31,17 @@ const (
 	bucketAllowedBurstKeySuffix               = "allowed_burst"
 	bucketRateConfigKeySuffix                 = "config:bucket_rate"
 	bucketReplenishmentConfigKeySuffix        = "config:bucket_replenishment_interval_seconds"
+	bucketConsumersKeySuffix                  = "consumers"
 	defaultBurst                              = 10
+	// consumptionWindow is the window over which the token consumption of each
+	// consumer is tracked.
+	consumptionWindow = time.Hour
 )
 
 // GlobalLimiter is a Redis-backed rate limiter that implements the token bucket
 // algorithm.
+// The priority class and consumer of a request are taken from the context, see
+// WithPriority and WithConsumer.
 // NOTE: This limiter needs to be backed by a syncer that will dump its configurations into Redis.
 // See cmd/worker/internal/ratelimit/job.go for an example.
 type GlobalLimiter interface {
@@ -88,34 +94,47 @@ func (r *globalRateLimiter) Wait(ctx context.Context) error {
 }
 
 func (r *globalRateLimiter) WaitN(ctx context.Context, n int) (err error) {
-	now := r.now()
+	for {
+		now := r.now()
 
-	// Check if ctx is already cancelled.
-	select {
-	case <-ctx.Done():
-		return ctx.Err()
-	default:
-	}
+		// Check if ctx is already cancelled.
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
 
-	// Determine wait limit.
-	waitLimit := time.Duration(-1)
-	if deadline, ok := ctx.Deadline(); ok {
-		waitLimit = deadline.Sub(now)
-	}
+		// Determine wait limit.
+		waitLimit := time.Duration(-1)
+		if deadline, ok := ctx.Deadline(); ok {
+			waitLimit = deadline.Sub(now)
+		}
 
-	// Reserve a token from the bucket.
-	timeToWait, err := r.waitn(ctx, n, now, waitLimit)
-	if err != nil {
-		return err
-	}
+		// Reserve a token from the bucket.
+		timeToWait, retry, err := r.waitn(ctx, n, now, waitLimit)
+		if err != nil {
+			return err
+		}
 
-	// If no need to wait, return immediately.
-	if timeToWait == 0 {
-		return nil
+		// If no need to wait, return immediately.
+		if timeToWait == 0 {
+			return nil
+		}
+
+		// Wait for the required time before the token can be used, or before we
+		// can try again if the bucket headroom is reserved for higher priority
+		// requests.
+		if err := r.wait(ctx, timeToWait); err != nil {
+			return err
+		}
+		if !retry {
+			return nil
+		}
 	}
+}
 
-	// Wait for the required time before the token can be used.
-	ch, stop := r.newTimer(timeToWait)
+func (r *globalRateLimiter) wait(ctx context.Context, d time.Duration) error {
+	ch, stop := r.newTimer(d)
 	defer stop()
 	select {
 	case <-ch:
@@ -144,7 +163,9 @@ func (r *globalRateLimiter) newTimer(d time.Duration) (<-chan time.Time, func()
 	return timer.C, timer.Stop
 }
 
-func (r *globalRateLimiter) waitn(ctx context.Context, n int, requestTime time.Time, maxTimeToWait time.Duration) (timeToWait time.Duration, err error) {
+// waitn tries to consume n tokens from the bucket. If retry is true, no tokens
+// were consumed and the caller should try again after timeToWait.
+func (r *globalRateLimiter) waitn(ctx context.Context, n int, requestTime time.Time, maxTimeToWait time.Duration) (timeToWait time.Duration, retry bool, err error) {
 	metricLimiterAttempts.Inc()
 	metricLimiterWaiting.Inc()
 	defer metricLimiterWaiting.Dec()
@@ -152,6 +173,8 @@ func (r *globalRateLimiter) waitn(ctx context.Context, n int, requestTime time.T
 	connection := r.pool.Get()
 	defer connection.Close()
 
+	priority := PriorityFromContext(ctx)
+
 	fallbackRateLimit := -1 // equivalent of rate.Inf
 	// the rate limit in the config is in requests per hour, whereas rate.Limit is in
 	// requests per second.
@@ -167,13 +190,15 @@ func (r *globalRateLimiter) waitn(ctx context.Context, n int, requestTime time.T
 		ctx,
 		getTokensScript,
 		connection,
-		keys.BucketKey, keys.LastReplenishmentTimestampKey, keys.RateKey, keys.ReplenishmentIntervalSecondsKey, keys.BurstKey,
+		keys.BucketKey, keys.LastReplenishmentTimestampKey, keys.RateKey, keys.ReplenishmentIntervalSecondsKey, keys.BurstKey, keys.consumersKey(requestTime),
 		requestTime.Unix(),
 		maxWaitTime,
 		int32(fallbackRateLimit),
 		int32(time.Hour/time.Second),
 		defaultBurst,
 		n,
+		priority.headroomPercentage(),
+		ConsumerFromContext(ctx),
 	)
 	if err != nil {
 		metricLimiterFailedAcquire.Inc()
@@ -189,26 +214,31 @@ func (r *globalRateLimiter) waitn(ctx context.Context, n int, requestTime time.T
 			defaultRateLimit = *rate
 		}
 		rl := getInMemoryLimiter(r.bucketName, defaultRateLimit)
-		return 0, rl.WaitN(ctx, n)
+		return 0, false, rl.WaitN(ctx, n)
 	}
 
 	scriptResponse, ok := result.([]interface{})
 	if !ok || len(scriptResponse) != 2 {
-		return 0, errors.Newf("unexpected response from Redis when getting tokens from bucket: %s, response: %+v", keys.BucketKey)
+		return 0, false, errors.Newf("unexpected response from Redis when getting tokens from bucket: %s, response: %+v", keys.BucketKey)
 	}
 
 	allowedInt, ok := scriptResponse[0].(int64)
 	if !ok {
-		return 0, errors.Newf("unexpected response for allowed, expected int64 but got %T", allowedInt)
+		return 0, false, errors.Newf("unexpected response for allowed, expected int64 but got %T", allowedInt)
 	}
 
 	timeToWaitSeconds, ok := scriptResponse[1].(int64)
 	if !ok {
-		return 0, errors.Newf("unexpected response for timeToWait, expected int64, got %T", timeToWaitSeconds)
+		return 0, false, errors.Newf("unexpected response for timeToWait, expected int64, got %T", timeToWaitSeconds)
+	}
+
+	grantType := getTokenGrantType(allowedInt)
+	if grantType == deferredForHigherPriority {
+		metricLimiterDeferred.WithLabelValues(string(priority)).Inc()
 	}
 
 	timeToWait = time.Duration(timeToWaitSeconds) * time.Second
-	return timeToWait, getTokenBucketError(keys.BucketKey, getTokenGrantType(allowedInt), timeToWait)
+	return timeToWait, grantType == deferredForHigherPriority, getTokenBucketError(keys.BucketKey, grantType, timeToWait)
 }
 
 const (
@@ -240,7 +270,7 @@ func invokeScriptWithRetries(ctx context.Context, script *redis.Script, c redis.
 
 func getTokenBucketError(bucketKey string, allowed getTokenGrantType, timeToWait time.Duration) error {
 	switch allowed {
-	case tokenGranted:
+	case tokenGranted, deferredForHigherPriority:
 		return nil
 	case waitTimeExceedsDeadline:
 		return WaitTimeExceedsDeadlineError{
@@ -281,8 +311,15 @@ func getRateLimiterKeys(prefix, bucketName string) rateLimitBucketConfigKeys {
 	return keys
 }
 
+// consumersKey returns the key of the hash that tracks the token consumption per
+// consumer in the window t falls into.
+// e.g. v2:rate_limiters:github.com:consumers:1700000000
+func (k rateLimitBucketConfigKeys) consumersKey(t time.Time) string {
+	return fmt.Sprintf("%s:%s:%d", k.BucketKey, bucketConsumersKeySuffix, t.Truncate(consumptionWindow).Unix())
+}
+
 var (
-	getTokensScript        = redis.NewScript(5, getTokensFromBucketLuaScript)
+	getTokensScript        = redis.NewScript(6, getTokensFromBucketLuaScript)
 	setReplenishmentScript = redis.NewScript(3, setTokenBucketReplenishmentLuaScript)
 )
 
@@ -292,8 +329,11 @@ var (
 // bucket_quota_key: the key in Redis that stores how many tokens the bucket should refill in a `bucket_replenishment_interval` period of time, e.g. v2:rate_limiters:github.com:api_tokens:config:bucket_quota.
 // bucket_replenishment_interval_key: the key in Redis that stores how often (in seconds), the bucket should be replenished bucket_quota tokens, e.g. v2:rate_limiters:github.com:api_tokens:config:bucket_replenishment_interval_seconds.
 // burst: the amount of tokens the bucket can hold, always bucketMaxCapacity right now.
+// consumers_key: the key in Redis that stores the hash of tokens consumed per consumer in the current window, e.g. v2:rate_limiters:github.com:api_tokens:consumers:1700000000.
 // current_time: current time (seconds since epoch).
 // max_time_to_wait_for_token: the maximum amount of time (in seconds) the requester is willing to wait before acquiring/using a token.
+// headroom_percentage: the percentage of the burst the request may not consume, reserved for requests of higher priority classes.
+// consumer: the name of the consumer the tokens are accounted to.
 //
 //go:embed globallimitergettokens.lua
 var getTokensFromBucketLuaScript string
@@ -308,6 +348,10 @@ const (
 	waitTimeExceedsDeadline getTokenGrantType = -1
 	negativeTimeDifference  getTokenGrantType = -2
 	allBlocked              getTokenGrantType = -3
+	// deferredForHigherPriority means no tokens were granted because they are
+	// reserved for requests of higher priority classes. The request should be
+	// retried after the returned wait time.
+	deferredForHigherPriority getTokenGrantType = -4
 )
 
 type rateLimitBucketConfigKeys struct {
@@ -369,6 +413,9 @@ type GlobalLimiterInfo struct {
 	// Infinite is true if Limit is infinite. This is required since infinity cannot
 	// be marshalled in JSON.
 	Infinite bool
+	// Consumers is the number of tokens each consumer has used in the current
+	// hour, keyed by consumer name. See WithConsumer.
+	Consumers map[string]int
 }
 
 // GetGlobalLimiterState reports how all the existing rate limiters are configured,
@@ -431,12 +478,18 @@ func GetGlobalLimiterStateFromPool(ctx context.Context, pool *redis.Pool, prefix
 			return nil, errors.Wrap(err, "failed to read last replenishment")
 		}
 
+		consumers, err := readConsumers(conn, rlKeys.consumersKey(time.Now()))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read consumers")
+		}
+
 		info := GlobalLimiterInfo{
 			CurrentCapacity:   currentCapacity,
 			Burst:             burst,
 			Limit:             rate,
 			LastReplenishment: time.Unix(int64(lastReplenishment), 0),
 			Interval:          time.Duration(intervalSeconds) * time.Second,
+			Consumers:         consumers,
 		}
 		if rate == -1 {
 			info.Limit = 0
@@ -448,6 +501,14 @@ func GetGlobalLimiterStateFromPool(ctx context.Context, pool *redis.Pool, prefix
 	return m, nil
 }
 
+func readConsumers(conn redis.Conn, key string) (map[string]int, error) {
+	consumers, err := redis.IntMap(conn.Do("HGETALL", key))
+	if err != nil || len(consumers) == 0 {
+		return nil, err
+	}
+	return consumers, nil
+}
+
 var (
 	// inMemoryLimitersMapMu protects access to inMemoryLimitersMap.
 	inMemoryLimitersMapMu sync.Mutex
@@ -565,4 +626,8 @@ var (
 		Name: "src_globallimiter_failed_acquire",
 		Help: "Incremented each time requesting a token from a rate limiter fails after retries.",
 	})
+	metricLimiterDeferred = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "src_globallimiter_deferred",
+		Help: "Incremented each time a rate limiter request has to wait because the remaining tokens are reserved for higher priority requests.",
+	}, []string{"priority"})
 )
@@ -282,6 +282,77 @@ func TestGlobalRateLimiter_UnconfiguredLimiter(t *testing.T) {
 	}
 }
 
+func TestGlobalRateLimiter_Priority(t *testing.T) {
+	// This test is verifying that lower priority requests leave headroom in the
+	// bucket for higher priority requests.
+	prefix := "__test__" + t.Name()
+	pool := redisPoolForTest(t, prefix)
+	rl := getTestRateLimiter(prefix, pool, testBucketName)
+
+	clock := glock.NewMockClock()
+	rl.nowFunc = clock.Now
+	waitDurations := make(chan time.Duration)
+	t.Cleanup(func() { close(waitDurations) })
+	rl.timerFunc = func(d time.Duration) (<-chan time.Time, func() bool) {
+		waitDurations <- d
+		// Move the clock forward, so that the retry sees the replenished bucket.
+		clock.Advance(d)
+		ch := make(chan time.Time, 1)
+		ch <- clock.Now()
+		return ch, func() bool { return true }
+	}
+
+	conf.Mock(&conf.Unified{
+		SiteConfiguration: schema.SiteConfiguration{
+			RateLimitHeadroom: &schema.RateLimitHeadroom{
+				Background: pointers.Ptr(50),
+			},
+		},
+	})
+	defer conf.Mock(nil)
+
+	// Rate is 100 / 100s, so 1/s.
+	ctx := context.Background()
+	require.NoError(t, rl.SetTokenBucketConfig(ctx, 100, 100*time.Second))
+
+	background := WithPriority(ctx, PriorityBackground)
+	interactive := WithPriority(ctx, PriorityInteractive)
+
+	// Background requests can use the bucket down to the headroom of 5 tokens.
+	require.NoError(t, rl.WaitN(background, 5))
+
+	// Interactive requests can use the headroom.
+	require.NoError(t, rl.WaitN(interactive, 3))
+
+	// The next background request has to wait until the bucket is back at 6
+	// tokens, which takes 4s.
+	{
+		waitReturn := make(chan struct{})
+		go func() {
+			require.NoError(t, rl.Wait(background))
+			close(waitReturn)
+		}()
+		select {
+		case d := <-waitDurations:
+			require.Equal(t, 4*time.Second, d)
+		case <-time.After(100 * time.Millisecond):
+			t.Fatal("timed out waiting for wait function")
+		}
+		select {
+		case <-waitReturn:
+		case <-time.After(100 * time.Millisecond):
+			t.Fatal("timed out waiting for return")
+		}
+	}
+
+	// Background requests fail early if waiting for the headroom exceeds the
+	// deadline.
+	ctxWithDeadline, cancel := context.WithDeadline(background, clock.Now().Add(2*time.Second))
+	t.Cleanup(cancel)
+	var expectedErr WaitTimeExceedsDeadlineError
+	require.True(t, errors.As(rl.WaitN(ctxWithDeadline, 5), &expectedErr))
+}
+
 func Test_GetToken_CanceledContext(t *testing.T) {
 	// This test is verifying that if the context we give to GetToken is
 	// already canceled, then we get back a context.Canceled error.
@@ -375,7 +446,7 @@ func TestLimitInfo(t *testing.T) {
 	now := time.Now().Truncate(time.Second)
 	r1.nowFunc = func() time.Time { return now }
 	// Now claim 3 tokens from the limiter.
-	require.NoError(t, r1.WaitN(ctx, 3))
+	require.NoError(t, r1.WaitN(WithConsumer(ctx, "repo-sync"), 3))
 
 	info, err = GetGlobalLimiterStateFromPool(ctx, pool, prefix)
 	require.NoError(t, err)
@@ -388,6 +459,7 @@ func TestLimitInfo(t *testing.T) {
 			Limit:             3600,
 			Interval:          time.Hour,
 			LastReplenishment: now,
+			Consumers:         map[string]int{"repo-sync": 3},
 		},
 		"extsvc:github:2": {
 			Burst:             10,
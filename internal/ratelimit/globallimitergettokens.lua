@@ -3,12 +3,15 @@ local last_replenishment_timestamp_key = KEYS[2]
 local bucket_rate_key = KEYS[3]
 local bucket_replenishment_interval_key = KEYS[4]
 local burst_key = KEYS[5]
+local consumers_key = KEYS[6]
 local current_time = tonumber(ARGV[1])
 local max_time_to_wait_for_tokens = tonumber(ARGV[2])
 local default_rate = tonumber(ARGV[3])
 local default_replenishment_interval = tonumber(ARGV[4])
 local default_burst = tonumber(ARGV[5])
 local tokens_to_grant = tonumber(ARGV[6])
+local headroom_percentage = tonumber(ARGV[7])
+local consumer = ARGV[8]
 
 -- Ensure the bucket burst capacity is configured. Otherwise,
 -- fall back to the provided default.
@@ -83,6 +86,19 @@ if num_tokens_to_replenish > 0 then
     end
 end
 
+-- Requests of lower priority classes leave some headroom in the bucket for requests
+-- of higher priority classes. If granting the tokens would dip into the headroom, we
+-- don't consume any tokens and instead tell the requester how long to wait before
+-- trying again. The headroom never makes a request impossible to satisfy.
+local headroom = math.min(math.floor(burst * headroom_percentage / 100), burst - tokens_to_grant)
+if headroom > 0 and current_tokens - tokens_to_grant < headroom then
+    local time_to_wait_for_headroom = math.ceil((headroom + tokens_to_grant - current_tokens) / replenishment_rate)
+    if max_time_to_wait_for_tokens ~= -1 and time_to_wait_for_headroom >= max_time_to_wait_for_tokens then
+        return {-1, time_to_wait_for_headroom} -- Return -1 (token grant wait time exceeds limit)
+    end
+    return {-4, time_to_wait_for_headroom} -- Return -4 (retry after waiting for higher priority requests)
+end
+
 local time_to_wait_for_tokens = 0
 -- This is for calculations with us removing a token.
 local tokens_after_consumption = current_tokens - tokens_to_grant
@@ -102,4 +118,8 @@ end
 -- Decrement the token bucket by tokens_to_grant, we are granted the tokens
 redis.call('DECRBY', bucket_key, tokens_to_grant)
 
+-- Track how many tokens each consumer used in the current window.
+redis.call('HINCRBY', consumers_key, consumer, tokens_to_grant)
+redis.call('EXPIRE', consumers_key, 7200)
+
 return {1, time_to_wait_for_tokens}
@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+)
+
+// Priority is the priority class of a request for tokens from a GlobalLimiter.
+// All consumers of a code host share the same token bucket. To make sure that
+// requests users are actively waiting on are not starved by background work,
+// requests of lower priority classes leave some headroom in the bucket for
+// requests of higher priority classes.
+type Priority string
+
+const (
+	// PriorityInteractive is used for requests users are actively waiting on,
+	// such as user-triggered permission syncs and publishing changesets. These
+	// requests can always use the full bucket.
+	PriorityInteractive Priority = "interactive"
+	// PriorityDefault is used for requests that don't specify a priority.
+	PriorityDefault Priority = "default"
+	// PriorityBackground is used for periodic background work, such as listing
+	// the repositories of code host connections and syncing changesets.
+	PriorityBackground Priority = "background"
+)
+
+const (
+	// defaultHeadroomPercentageDefault is the percentage of the bucket burst
+	// requests with PriorityDefault leave untouched, unless configured otherwise.
+	defaultHeadroomPercentageDefault = 0
+	// defaultHeadroomPercentageBackground is the percentage of the bucket burst
+	// requests with PriorityBackground leave untouched, unless configured
+	// otherwise.
+	defaultHeadroomPercentageBackground = 50
+)
+
+// headroomPercentage returns the percentage of the bucket burst that requests of
+// the given priority class may not consume, according to the site configuration.
+func (p Priority) headroomPercentage() int {
+	cfg := conf.Get().RateLimitHeadroom
+
+	var percentage int
+	switch p {
+	case PriorityInteractive:
+		return 0
+	case PriorityBackground:
+		percentage = defaultHeadroomPercentageBackground
+		if cfg != nil && cfg.Background != nil {
+			percentage = *cfg.Background
+		}
+	default:
+		percentage = defaultHeadroomPercentageDefault
+		if cfg != nil && cfg.Default != nil {
+			percentage = *cfg.Default
+		}
+	}
+
+	return min(max(percentage, 0), 100)
+}
+
+type priorityKey struct{}
+
+// WithPriority returns a context that makes all GlobalLimiter requests made with
+// it use the given priority class.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFromContext returns the priority class set on the context, or
+// PriorityDefault if there is none.
+func PriorityFromContext(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok && p != "" {
+		return p
+	}
+	return PriorityDefault
+}
+
+// unknownConsumer is the consumer name recorded for requests that don't specify
+// a consumer.
+const unknownConsumer = "unknown"
+
+type consumerKey struct{}
+
+// WithConsumer returns a context that attributes all GlobalLimiter requests made
+// with it to the given consumer, e.g. "repo-sync" or "perms-sync". Consumption
+// per consumer is reported by GetGlobalLimiterState.
+func WithConsumer(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, consumerKey{}, name)
+}
+
+// ConsumerFromContext returns the consumer name set on the context, or "unknown"
+// if there is none.
+func ConsumerFromContext(ctx context.Context) string {
+	if name, ok := ctx.Value(consumerKey{}).(string); ok && name != "" {
+		return name
+	}
+	return unknownConsumer
+}
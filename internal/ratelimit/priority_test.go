@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/lib/pointers"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestPriorityFromContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, PriorityDefault, PriorityFromContext(ctx))
+	assert.Equal(t, PriorityBackground, PriorityFromContext(WithPriority(ctx, PriorityBackground)))
+	assert.Equal(t, PriorityDefault, PriorityFromContext(WithPriority(ctx, "")))
+
+	assert.Equal(t, "unknown", ConsumerFromContext(ctx))
+	assert.Equal(t, "perms-sync", ConsumerFromContext(WithConsumer(ctx, "perms-sync")))
+}
+
+func TestPriority_headroomPercentage(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		headroom *schema.RateLimitHeadroom
+		want     map[Priority]int
+	}{
+		{
+			name: "defaults",
+			want: map[Priority]int{
+				PriorityInteractive: 0,
+				PriorityDefault:     0,
+				PriorityBackground:  50,
+			},
+		},
+		{
+			name: "configured",
+			headroom: &schema.RateLimitHeadroom{
+				Default:    pointers.Ptr(10),
+				Background: pointers.Ptr(80),
+			},
+			want: map[Priority]int{
+				PriorityInteractive: 0,
+				PriorityDefault:     10,
+				PriorityBackground:  80,
+			},
+		},
+		{
+			name: "out of range",
+			headroom: &schema.RateLimitHeadroom{
+				Default:    pointers.Ptr(-10),
+				Background: pointers.Ptr(150),
+			},
+			want: map[Priority]int{
+				PriorityInteractive: 0,
+				PriorityDefault:     0,
+				PriorityBackground:  100,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{RateLimitHeadroom: tc.headroom}})
+			t.Cleanup(func() { conf.Mock(nil) })
+
+			for p, want := range tc.want {
+				assert.Equal(t, want, p.headroomPercentage(), string(p))
+			}
+		})
+	}
+}
@@ -451,6 +451,9 @@ func (s *Syncer) SyncExternalService(
 
 	// Ensure the job field is recorded when monitoring external API calls
 	ctx = metrics.ContextWithTask(ctx, "SyncExternalService")
+	// Listing repositories is background work, so leave code host rate limit
+	// budget for requests users are waiting on.
+	ctx = ratelimit.WithConsumer(ratelimit.WithPriority(ctx, ratelimit.PriorityBackground), "repo-sync")
 
 	var svc *types.ExternalService
 	ctx, save := s.observeSync(ctx, "Syncer.SyncExternalService")
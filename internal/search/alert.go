@@ -60,6 +60,10 @@ const (
 	// query. May be a number or string representing something approximate,
 	// like "500+".
 	ResultCount AnnotationName = "ResultCount"
+
+	// Line communicates the line number to jump to in the file results of a
+	// query, like the line of a stack trace frame.
+	Line AnnotationName = "Line"
 )
 
 func (q *QueryDescription) QueryString() string {
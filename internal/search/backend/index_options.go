@@ -1,6 +1,8 @@
 package backend
 
 import (
+	"strings"
+
 	"github.com/grafana/regexp"
 	"github.com/inconshreveable/log15" //nolint:logging // TODO move all logging to sourcegraph/log
 	"github.com/sourcegraph/zoekt"
@@ -150,6 +152,14 @@ type RepoIndexOptions struct {
 	// Archived is true if the repository is archived.
 	Archived bool
 
+	// KeyValuePairs is the metadata of the repository. It is matched against
+	// the meta condition of search.index.overrides rules.
+	KeyValuePairs map[string]*string
+
+	// SearchContexts are the specs of the search contexts referenced by
+	// search.index.overrides rules that contain the repository.
+	SearchContexts []string
+
 	// GetVersion is used to resolve revisions for a repo. If it fails, the
 	// error is encoded in the body. If the revision is missing, an empty
 	// string should be returned rather than an error.
@@ -172,12 +182,13 @@ func GetIndexOptions(
 	sema := make(chan struct{}, 32)
 	results := make([]ZoektIndexOptions, len(repos))
 	getSiteConfigRevisions := siteConfigRevisionsRuleFunc(c)
+	applySiteConfigOverrides := siteConfigOverridesRuleFunc(c)
 
 	for i := range repos {
 		sema <- struct{}{}
 		go func(i int) {
 			defer func() { <-sema }()
-			results[i] = getIndexOptions(c, repos[i], getRepoIndexOptions, getSearchContextRevisions, getSiteConfigRevisions)
+			results[i] = getIndexOptions(c, repos[i], getRepoIndexOptions, getSearchContextRevisions, getSiteConfigRevisions, applySiteConfigOverrides)
 		}(i)
 	}
 
@@ -195,6 +206,7 @@ func getIndexOptions(
 	getRepoIndexOptions func(repoID api.RepoID) (*RepoIndexOptions, error),
 	getSearchContextRevisions func(repoID api.RepoID) ([]string, error),
 	getSiteConfigRevisions revsRuleFunc,
+	applySiteConfigOverrides overridesRuleFunc,
 ) ZoektIndexOptions {
 	opts, err := getRepoIndexOptions(repoID)
 	if err != nil {
@@ -219,6 +231,11 @@ func getIndexOptions(
 		ShardConcurrency:     int32(c.SearchIndexShardConcurrency),
 	}
 
+	// Apply the search.index.overrides rules matching the repository.
+	if applySiteConfigOverrides != nil {
+		applySiteConfigOverrides(opts, &o)
+	}
+
 	// Set of branch names. Always index HEAD
 	branches := map[string]struct{}{"HEAD": {}}
 
@@ -329,6 +346,105 @@ func siteConfigRevisionsRuleFunc(c *schema.SiteConfiguration) revsRuleFunc {
 	}
 }
 
+type overridesRuleFunc func(*RepoIndexOptions, *ZoektIndexOptions)
+
+// siteConfigOverridesRuleFunc returns a function which applies the
+// search.index.overrides rules matching a repository to its index options.
+//
+// Only the large file patterns and symbols are overridable so far.
+//
+// TODO: override the maximum indexed file size and the language include and
+// exclude lists too. ZoektIndexOptions can't carry them yet, since the zoekt
+// configuration protocol has no fields for them; zoekt-sourcegraph-indexserver
+// configures them for all repositories.
+func siteConfigOverridesRuleFunc(c *schema.SiteConfiguration) overridesRuleFunc {
+	if c == nil || c.ExperimentalFeatures == nil || len(c.ExperimentalFeatures.SearchIndexOverrides) == 0 {
+		return nil
+	}
+
+	type rule struct {
+		matchers []func(*RepoIndexOptions) bool
+		*schema.SearchIndexOverridesRule
+	}
+
+	rules := make([]rule, 0, len(c.ExperimentalFeatures.SearchIndexOverrides))
+	for _, r := range c.ExperimentalFeatures.SearchIndexOverrides {
+		r := rule{SearchIndexOverridesRule: r}
+
+		if r.Name != "" {
+			namePattern, err := regexp.Compile(r.Name)
+			if err != nil {
+				log15.Error("error compiling regex from search.index.overrides", "regex", r.Name, "err", err)
+				continue
+			}
+			r.matchers = append(r.matchers, func(o *RepoIndexOptions) bool {
+				return namePattern.MatchString(o.Name)
+			})
+		}
+
+		if r.Meta != "" {
+			key, value, hasValue := strings.Cut(r.Meta, ":")
+			r.matchers = append(r.matchers, func(o *RepoIndexOptions) bool {
+				v, ok := o.KeyValuePairs[key]
+				if !ok || !hasValue {
+					return ok
+				}
+				return v != nil && *v == value
+			})
+		}
+
+		if r.SearchContext != "" {
+			spec := r.SearchContext
+			r.matchers = append(r.matchers, func(o *RepoIndexOptions) bool {
+				return slices.Contains(o.SearchContexts, spec)
+			})
+		}
+
+		// A rule without conditions would match every repository, which is
+		// what the top-level options are for.
+		if len(r.matchers) == 0 {
+			continue
+		}
+
+		rules = append(rules, r)
+	}
+
+	return func(opts *RepoIndexOptions, o *ZoektIndexOptions) {
+	next:
+		for _, r := range rules {
+			for _, matches := range r.matchers {
+				if !matches(opts) {
+					continue next
+				}
+			}
+
+			// Clip so that we never append to the slice in the site
+			// configuration, which is shared by all repositories.
+			o.LargeFiles = append(slices.Clip(o.LargeFiles), r.LargeFiles...)
+			if r.Symbols != nil {
+				o.Symbols = *r.Symbols
+			}
+		}
+	}
+}
+
+// SiteConfigOverridesSearchContexts returns the specs of the search contexts
+// referenced by search.index.overrides rules. Callers of GetIndexOptions use
+// it to fill in RepoIndexOptions.SearchContexts.
+func SiteConfigOverridesSearchContexts(c *schema.SiteConfiguration) []string {
+	if c == nil || c.ExperimentalFeatures == nil {
+		return nil
+	}
+
+	var specs []string
+	for _, rule := range c.ExperimentalFeatures.SearchIndexOverrides {
+		if rule.SearchContext != "" && !slices.Contains(specs, rule.SearchContext) {
+			specs = append(specs, rule.SearchContext)
+		}
+	}
+	return specs
+}
+
 func getBoolPtr(b *bool, default_ bool) bool {
 	if b == nil {
 		return default_
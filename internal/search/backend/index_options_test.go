@@ -43,6 +43,7 @@ func TestGetIndexOptions(t *testing.T) {
 		FORK
 		ARCHIVED
 		RANKED
+		DATA
 	)
 
 	name := func(repo api.RepoID) string {
@@ -254,6 +255,52 @@ func TestGetIndexOptions(t *testing.T) {
 			DocumentRanksVersion: "ranked",
 			LanguageMap:          ctags_config.DefaultEngines,
 		},
+	}, {
+		name: "conf index overrides",
+		conf: schema.SiteConfiguration{
+			SearchLargeFiles: []string{"go.sum"},
+			ExperimentalFeatures: &schema.ExperimentalFeatures{
+				SearchIndexOverrides: []*schema.SearchIndexOverridesRule{
+					{Meta: "kind:data", Symbols: pointers.Ptr(false)},
+					{Meta: "kind:code", Symbols: pointers.Ptr(true)},
+					{SearchContext: "@org/data", LargeFiles: []string{"**/*.csv"}},
+					{Name: "repo-.*", SearchContext: "@org/other", LargeFiles: []string{"**/*.bin"}},
+					{Name: "repo-09", Meta: "archived", LargeFiles: []string{"**/*.parquet"}},
+				},
+			},
+		},
+		repo: DATA,
+		want: ZoektIndexOptions{
+			RepoID:     9,
+			Name:       "repo-09",
+			LargeFiles: []string{"go.sum", "**/*.csv", "**/*.parquet"},
+			Branches: []zoekt.RepositoryBranch{
+				{Name: "HEAD", Version: "!HEAD"},
+			},
+			LanguageMap: ctags_config.DefaultEngines,
+		},
+	}, {
+		name: "conf index overrides not matching",
+		conf: schema.SiteConfiguration{
+			SearchLargeFiles: []string{"go.sum"},
+			ExperimentalFeatures: &schema.ExperimentalFeatures{
+				SearchIndexOverrides: []*schema.SearchIndexOverridesRule{
+					{Meta: "kind", Symbols: pointers.Ptr(false)},
+					{SearchContext: "@org/data", LargeFiles: []string{"**/*.csv"}},
+				},
+			},
+		},
+		repo: REPO,
+		want: ZoektIndexOptions{
+			RepoID:     1,
+			Name:       "repo-01",
+			Symbols:    true,
+			LargeFiles: []string{"go.sum"},
+			Branches: []zoekt.RepositoryBranch{
+				{Name: "HEAD", Version: "!HEAD"},
+			},
+			LanguageMap: ctags_config.DefaultEngines,
+		},
 	}}
 
 	{
@@ -292,6 +339,14 @@ func TestGetIndexOptions(t *testing.T) {
 		if repo == RANKED {
 			documentRanksVersion = "ranked"
 		}
+		var (
+			keyValuePairs  map[string]*string
+			searchContexts []string
+		)
+		if repo == DATA {
+			keyValuePairs = map[string]*string{"kind": pointers.Ptr("data"), "archived": nil}
+			searchContexts = []string{"@org/data"}
+		}
 		return &RepoIndexOptions{
 			RepoID:   repo,
 			Name:     name(repo),
@@ -304,6 +359,8 @@ func TestGetIndexOptions(t *testing.T) {
 			},
 
 			DocumentRanksVersion: documentRanksVersion,
+			KeyValuePairs:        keyValuePairs,
+			SearchContexts:       searchContexts,
 		}, nil
 	}
 
@@ -38,14 +38,18 @@ var validSelectors = object{
 			"added":   nil,
 			"removed": nil,
 		},
+		"author": nil,
 	},
 	Content: nil,
 	File: {
 		"directory": nil,
 		"path":      nil,
 		"owners":    nil,
+		"language":  nil,
+	},
+	Repository: object{
+		"contributors": nil,
 	},
-	Repository: nil,
 	Symbol: object{
 		/* cf. SymbolKind https://microsoft.github.io/language-server-protocol/specification */
 		"file":           nil,
@@ -0,0 +1,285 @@
+package jobutil
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/sourcegraph/conc/pool"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/exp/slices"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/linguist"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// FileKindFilter restricts results to files of a kind (Include), or to files
+// not of that kind (Exclude).
+type FileKindFilter struct {
+	Include bool
+	Exclude bool
+}
+
+func (f FileKindFilter) active() bool {
+	return f.Include || f.Exclude
+}
+
+func (f FileKindFilter) keep(is bool) bool {
+	return !(f.Include && !is) && !(f.Exclude && is)
+}
+
+// withFileKindFilters adds the path heuristics of file:is.vendored(),
+// -file:is.generated() and -file:is.vendored() to the query as file: and
+// -file: filters. The backends then skip files that the post-filter would drop,
+// rather than the post-filter dropping them from a result set that was limited
+// by count: already.
+//
+// As a consequence, .gitattributes can not mark a file as not generated or not
+// vendored if its path matches the heuristics, and file:is.vendored() only
+// matches files whose path matches the heuristics. file:is.generated() is not
+// pushed down, since generated files are also recognized by their header, so
+// it may return fewer results than count: asks for.
+func withFileKindFilters(b query.Basic, generated, vendored FileKindFilter) query.Basic {
+	var excludes []string
+	if generated.Exclude {
+		excludes = append(excludes, linguist.GeneratedPathPatterns()...)
+	}
+	if vendored.Exclude {
+		excludes = append(excludes, linguist.VendoredPathPatterns()...)
+	}
+	if len(excludes) == 0 && !vendored.Include {
+		return b
+	}
+
+	parameters := slices.Clip(b.Parameters)
+	if vendored.Include {
+		parameters = append(parameters, query.Parameter{
+			Field: query.FieldFile,
+			Value: query.UnionRegExps(linguist.VendoredPathPatterns()),
+		})
+	}
+	if len(excludes) > 0 {
+		parameters = append(parameters, query.Parameter{
+			Field:   query.FieldFile,
+			Value:   query.UnionRegExps(excludes),
+			Negated: true,
+		})
+	}
+	return b.MapParameters(parameters)
+}
+
+// NewFileIsJob creates a filter job to post-filter results for the
+// file:is.generated() and file:is.vendored() predicates.
+//
+// Files are classified with the linguist heuristics, unless the
+// .gitattributes file at the root of their repository sets the
+// linguist-generated or linguist-vendored attribute for them. Since it runs
+// on the results of all backends, the filter applies to both indexed and
+// unindexed search.
+func NewFileIsJob(child job.Job, generated, vendored FileKindFilter) job.Job {
+	return &fileIsJob{
+		child:     child,
+		generated: generated,
+		vendored:  vendored,
+	}
+}
+
+// fileIsConcurrency is the maximum number of files of a search event that are
+// classified at the same time.
+const fileIsConcurrency = 16
+
+type fileIsJob struct {
+	child job.Job
+
+	generated FileKindFilter
+	vendored  FileKindFilter
+}
+
+func (j *fileIsJob) Run(ctx context.Context, clients job.RuntimeClients, stream streaming.Sender) (alert *search.Alert, err error) {
+	_, ctx, stream, finish := job.StartSpan(ctx, stream, j)
+	defer func() { finish(alert, err) }()
+
+	var (
+		mu    sync.Mutex
+		errs  error
+		attrs = newGitAttributesCache(clients.Gitserver)
+	)
+
+	filteredStream := streaming.StreamFunc(func(event streaming.SearchEvent) {
+		// Classifying a file may read its header from gitserver, so the
+		// results of an event are classified concurrently.
+		keep := make([]bool, len(event.Results))
+		p := pool.New().WithMaxGoroutines(fileIsConcurrency)
+		for i, res := range event.Results {
+			// Filter out any result that is not a file
+			fm, ok := res.(*result.FileMatch)
+			if !ok {
+				continue
+			}
+
+			i := i
+			p.Go(func() {
+				ok, err := j.keep(ctx, clients.Gitserver, attrs, fm)
+				if err != nil {
+					mu.Lock()
+					errs = errors.Append(errs, err)
+					mu.Unlock()
+					return
+				}
+				keep[i] = ok
+			})
+		}
+		p.Wait()
+
+		filtered := event.Results[:0]
+		for i, res := range event.Results {
+			if keep[i] {
+				filtered = append(filtered, res)
+			}
+		}
+
+		event.Results = filtered
+		stream.Send(event)
+	})
+
+	alert, err = j.child.Run(ctx, clients, filteredStream)
+	if err != nil {
+		errs = errors.Append(errs, err)
+	}
+	return alert, errs
+}
+
+func (j *fileIsJob) keep(ctx context.Context, client gitserver.Client, cache *gitAttributesCache, fm *result.FileMatch) (bool, error) {
+	attrs, err := cache.get(ctx, fm.Repo.Name, fm.CommitID)
+	if err != nil {
+		return false, err
+	}
+
+	if j.vendored.active() {
+		vendored, ok := attrs.Vendored(fm.Path)
+		if !ok {
+			vendored = linguist.IsVendoredPath(fm.Path)
+		}
+		if !j.vendored.keep(vendored) {
+			return false, nil
+		}
+	}
+
+	if j.generated.active() {
+		generated, ok := attrs.Generated(fm.Path)
+		if !ok {
+			// Only read the file header if the path is not conclusive.
+			generated = linguist.IsGeneratedPath(fm.Path)
+			if !generated {
+				header, err := readFileHeader(ctx, client, fm)
+				if err != nil {
+					return false, err
+				}
+				generated = linguist.IsGenerated(fm.Path, header)
+			}
+		}
+		if !j.generated.keep(generated) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func readFileHeader(ctx context.Context, client gitserver.Client, fm *result.FileMatch) ([]byte, error) {
+	r, err := client.NewFileReader(ctx, fm.Repo.Name, fm.CommitID, fm.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(io.LimitReader(r, linguist.HeaderSize))
+}
+
+func (j *fileIsJob) MapChildren(fn job.MapFunc) job.Job {
+	cp := *j
+	cp.child = job.Map(j.child, fn)
+	return &cp
+}
+
+func (j *fileIsJob) Name() string {
+	return "FileIsFilterJob"
+}
+
+func (j *fileIsJob) Children() []job.Describer {
+	return []job.Describer{j.child}
+}
+
+func (j *fileIsJob) Attributes(v job.Verbosity) (res []attribute.KeyValue) {
+	switch v {
+	case job.VerbosityMax:
+		fallthrough
+	case job.VerbosityBasic:
+		res = append(res,
+			attribute.Bool("includeGenerated", j.generated.Include),
+			attribute.Bool("excludeGenerated", j.generated.Exclude),
+			attribute.Bool("includeVendored", j.vendored.Include),
+			attribute.Bool("excludeVendored", j.vendored.Exclude),
+		)
+	}
+	return res
+}
+
+// gitAttributesCache reads the .gitattributes file at the root of each
+// repository and commit once per search.
+type gitAttributesCache struct {
+	client gitserver.Client
+
+	mu    sync.Mutex
+	attrs map[gitAttributesKey]*linguist.Attributes
+}
+
+type gitAttributesKey struct {
+	repo   api.RepoName
+	commit api.CommitID
+}
+
+func newGitAttributesCache(client gitserver.Client) *gitAttributesCache {
+	return &gitAttributesCache{
+		client: client,
+		attrs:  make(map[gitAttributesKey]*linguist.Attributes),
+	}
+}
+
+// get returns the attributes of the repository at commit, which are nil if the
+// repository has no .gitattributes file.
+func (c *gitAttributesCache) get(ctx context.Context, repo api.RepoName, commit api.CommitID) (*linguist.Attributes, error) {
+	key := gitAttributesKey{repo: repo, commit: commit}
+
+	c.mu.Lock()
+	attrs, ok := c.attrs[key]
+	c.mu.Unlock()
+	if ok {
+		return attrs, nil
+	}
+
+	r, err := c.client.NewFileReader(ctx, repo, commit, ".gitattributes")
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		defer r.Close()
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		attrs = linguist.ParseAttributes(data)
+	}
+
+	c.mu.Lock()
+	c.attrs[key] = attrs
+	c.mu.Unlock()
+	return attrs, nil
+}
@@ -0,0 +1,142 @@
+package jobutil
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/grafana/regexp"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/mockjob"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+func TestFileIsJob(t *testing.T) {
+	files := map[string]string{
+		".gitattributes":           "gen/** linguist-generated\nvendor/ours/** -linguist-vendored\n",
+		"main.go":                  "package main\n",
+		"api/api.pb.go":            "// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage api\n",
+		"gen/schema.go":            "package gen\n",
+		"vendor/lib/lib.go":        "package lib\n",
+		"vendor/ours/ours.go":      "package ours\n",
+		"client/package-lock.json": "{}\n",
+	}
+
+	fm := func(path string) *result.FileMatch {
+		return &result.FileMatch{
+			File: result.File{
+				Repo:     types.MinimalRepo{Name: "repo"},
+				CommitID: "commit",
+				Path:     path,
+			},
+		}
+	}
+
+	run := func(t *testing.T, generated, vendored FileKindFilter) []string {
+		childJob := mockjob.NewMockJob()
+		childJob.RunFunc.SetDefaultHook(func(_ context.Context, _ job.RuntimeClients, s streaming.Sender) (*search.Alert, error) {
+			s.Send(streaming.SearchEvent{Results: result.Matches{
+				fm("main.go"),
+				fm("api/api.pb.go"),
+				fm("gen/schema.go"),
+				fm("vendor/lib/lib.go"),
+				fm("vendor/ours/ours.go"),
+				fm("client/package-lock.json"),
+				&result.RepoMatch{Name: "repo"},
+			}})
+			return nil, nil
+		})
+
+		gitserverClient := gitserver.NewMockClient()
+		gitserverClient.NewFileReaderFunc.SetDefaultHook(func(_ context.Context, _ api.RepoName, _ api.CommitID, name string) (io.ReadCloser, error) {
+			content, ok := files[name]
+			if !ok {
+				return nil, fs.ErrNotExist
+			}
+			return io.NopCloser(strings.NewReader(content)), nil
+		})
+
+		var paths []string
+		stream := streaming.StreamFunc(func(ev streaming.SearchEvent) {
+			for _, m := range ev.Results {
+				paths = append(paths, m.(*result.FileMatch).Path)
+			}
+		})
+
+		j := NewFileIsJob(childJob, generated, vendored)
+		alert, err := j.Run(context.Background(), job.RuntimeClients{Gitserver: gitserverClient}, stream)
+		require.Nil(t, alert)
+		require.NoError(t, err)
+		return paths
+	}
+
+	t.Run("include generated", func(t *testing.T) {
+		got := run(t, FileKindFilter{Include: true}, FileKindFilter{})
+		require.Equal(t, []string{"api/api.pb.go", "gen/schema.go", "client/package-lock.json"}, got)
+	})
+
+	t.Run("exclude generated", func(t *testing.T) {
+		got := run(t, FileKindFilter{Exclude: true}, FileKindFilter{})
+		require.Equal(t, []string{"main.go", "vendor/lib/lib.go", "vendor/ours/ours.go"}, got)
+	})
+
+	t.Run("include vendored", func(t *testing.T) {
+		got := run(t, FileKindFilter{}, FileKindFilter{Include: true})
+		require.Equal(t, []string{"vendor/lib/lib.go"}, got)
+	})
+
+	t.Run("exclude generated and vendored", func(t *testing.T) {
+		got := run(t, FileKindFilter{Exclude: true}, FileKindFilter{Exclude: true})
+		require.Equal(t, []string{"main.go", "vendor/ours/ours.go"}, got)
+	})
+}
+
+func TestWithFileKindFilters(t *testing.T) {
+	plan, err := query.Pipeline(query.InitLiteral("foo"))
+	require.NoError(t, err)
+	b := plan[0]
+
+	matches := func(values []string, path string) bool {
+		require.LessOrEqual(t, len(values), 1)
+		return len(values) == 1 && regexp.MustCompile(values[0]).MatchString(path)
+	}
+	included := func(b query.Basic, path string) bool {
+		include, _ := b.IncludeExcludeValues(query.FieldFile)
+		return matches(include, path)
+	}
+	excluded := func(b query.Basic, path string) bool {
+		_, exclude := b.IncludeExcludeValues(query.FieldFile)
+		return matches(exclude, path)
+	}
+
+	got := withFileKindFilters(b, FileKindFilter{}, FileKindFilter{Exclude: true})
+	require.True(t, excluded(got, "vendor/lib/lib.go"))
+	require.True(t, excluded(got, "web/node_modules/react/index.js"))
+	require.False(t, excluded(got, "main.go"))
+	require.False(t, excluded(got, "client/package-lock.json"))
+
+	got = withFileKindFilters(b, FileKindFilter{Exclude: true}, FileKindFilter{})
+	require.True(t, excluded(got, "client/package-lock.json"))
+	require.False(t, excluded(got, "vendor/lib/lib.go"))
+
+	got = withFileKindFilters(b, FileKindFilter{Exclude: true}, FileKindFilter{Include: true})
+	require.True(t, included(got, "vendor/lib/lib.go"))
+	require.False(t, included(got, "main.go"))
+	require.True(t, excluded(got, "client/package-lock.json"))
+	require.False(t, excluded(got, "vendor/lib/lib.go"))
+
+	// Generated files are also recognized by their header, so including them
+	// can't be pushed down.
+	got = withFileKindFilters(b, FileKindFilter{Include: true}, FileKindFilter{})
+	require.Equal(t, b, got)
+}
@@ -1,6 +1,7 @@
 package jobutil
 
 import (
+	"slices"
 	"strings"
 	"time"
 
@@ -32,6 +33,18 @@ import (
 
 // NewPlanJob converts a query.Plan into its job tree representation.
 func NewPlanJob(inputs *search.Inputs, plan query.Plan) (job.Job, error) {
+	// sort: orders the results of the whole query, but the parser only
+	// attaches it to one basic query of a compound query like `a or b
+	// sort:path`. Every basic query sorts its own results so that its limit
+	// keeps the right ones, and the results are merged into one order below.
+	order, maxResults, err := planSort(inputs, plan)
+	if err != nil {
+		return nil, err
+	}
+	if len(plan) > 1 && order != query.SortDefault {
+		plan = withSort(plan, order)
+	}
+
 	children := make([]job.Job, 0, len(plan))
 	for _, q := range plan {
 		child, err := NewBasicJob(inputs, q)
@@ -42,6 +55,10 @@ func NewPlanJob(inputs *search.Inputs, plan query.Plan) (job.Job, error) {
 	}
 
 	jobTree := NewOrJob(children...)
+	if len(plan) > 1 && order != query.SortDefault {
+		jobTree = NewSortJob(order, maxResults, jobTree)
+	}
+
 	newJob := func(b query.Basic) (job.Job, error) {
 		return NewBasicJob(inputs, b)
 	}
@@ -68,6 +85,37 @@ func NewPlanJob(inputs *search.Inputs, plan query.Plan) (job.Job, error) {
 	return logJob, nil
 }
 
+// planSort returns the order requested with `sort:` by the basic queries of
+// the plan, and the largest number of results those basic queries keep. It is
+// an error for basic queries to request different orders.
+func planSort(inputs *search.Inputs, plan query.Plan) (query.SortOrder, int, error) {
+	order, maxResults := query.SortDefault, 0
+	for _, b := range plan {
+		o := b.Sort()
+		if o == query.SortDefault {
+			continue
+		}
+		if order != query.SortDefault && o != order {
+			return query.SortDefault, 0, errors.Errorf("conflicting values for %q: %s and %s", query.FieldSort, order, o)
+		}
+		order = o
+		maxResults = max(maxResults, b.ToParseTree().MaxResults(inputs.DefaultLimit()))
+	}
+	return order, maxResults, nil
+}
+
+// withSort returns a copy of plan in which every basic query requests order.
+func withSort(plan query.Plan, order query.SortOrder) query.Plan {
+	sorted := make(query.Plan, 0, len(plan))
+	for _, b := range plan {
+		if b.Sort() == query.SortDefault {
+			b.Parameters = append(slices.Clone(b.Parameters), query.Parameter{Field: query.FieldSort, Value: string(order)})
+		}
+		sorted = append(sorted, b)
+	}
+	return sorted
+}
+
 // NewBasicJob converts a query.Basic into its job tree representation.
 func NewBasicJob(inputs *search.Inputs, b query.Basic) (job.Job, error) {
 
@@ -94,6 +142,13 @@ func NewBasicJob(inputs *search.Inputs, b query.Basic) (job.Job, error) {
 		b.Pattern = query.Operator{Operands: newNodes, Kind: query.And}
 	}
 
+	// Filter the paths of file:is.vendored(), -file:is.generated() and
+	// -file:is.vendored() in the backends already.
+	var generated, vendored FileKindFilter
+	generated.Include, generated.Exclude = b.FileIsGenerated()
+	vendored.Include, vendored.Exclude = b.FileIsVendored()
+	b = withFileKindFilters(b, generated, vendored)
+
 	{
 		// This block generates jobs that can be built directly from
 		// a basic query rather than first being expanded into
@@ -224,6 +279,12 @@ func NewBasicJob(inputs *search.Inputs, b query.Basic) (job.Job, error) {
 		}
 	}
 
+	{ // Apply file:is.generated() and file:is.vendored() post-search filter
+		if generated.active() || vendored.active() {
+			basicJob = NewFileIsJob(basicJob, generated, vendored)
+		}
+	}
+
 	{ // Apply subrepo permissions checks
 		checker := authz.DefaultSubRepoPermsChecker
 		if authz.SubRepoEnabled(checker) {
@@ -237,6 +298,9 @@ func NewBasicJob(inputs *search.Inputs, b query.Basic) (job.Job, error) {
 			if isSelectOwnersSearch(sp) {
 				// the select owners job is ran separately as it requires state and can return multiple owners from one match.
 				basicJob = ownsearch.NewSelectOwnersJob(basicJob)
+			} else if isSelectRepoContributorsSearch(sp) {
+				// contributors are fetched from gitserver, and each repository yields multiple contributors.
+				basicJob = NewSelectRepoContributorsJob(basicJob)
 			} else {
 				basicJob = NewSelectJob(sp, basicJob)
 			}
@@ -249,6 +313,13 @@ func NewBasicJob(inputs *search.Inputs, b query.Basic) (job.Job, error) {
 		}
 	}
 
+	{ // Apply sort:
+		if order := b.Sort(); order != query.SortDefault {
+			maxResults := b.ToParseTree().MaxResults(inputs.DefaultLimit())
+			basicJob = NewSortJob(order, maxResults, basicJob)
+		}
+	}
+
 	{ // Apply limit
 		maxResults := b.ToParseTree().MaxResults(inputs.DefaultLimit())
 		basicJob = NewLimitJob(maxResults, basicJob)
@@ -427,6 +498,7 @@ func NewFlatJob(searchInputs *search.Inputs, f query.Flat) (job.Job, error) {
 					query.FieldRepoHasCommitAfter: {},
 					query.FieldPatternType:        {},
 					query.FieldSelect:             {},
+					query.FieldSort:               {},
 				}
 
 				// Don't run a repo search if the search contains fields that aren't on the allowlist.
@@ -578,6 +650,10 @@ func isSelectOwnersSearch(sp filter.SelectPath) bool {
 	return sp.Root() == filter.File && len(sp) == 2 && sp[1] == "owners"
 }
 
+func isSelectLanguageSearch(sp filter.SelectPath) bool {
+	return sp.Root() == filter.File && len(sp) == 2 && sp[1] == "language"
+}
+
 func isContributorSearch(b query.Basic) (include, exclude []string, ok bool) {
 	if includeContributors, excludeContributors := b.FileHasContributor(); len(includeContributors) > 0 || len(excludeContributors) > 0 {
 		return includeContributors, excludeContributors, true
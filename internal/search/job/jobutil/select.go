@@ -2,6 +2,7 @@ package jobutil
 
 import (
 	"context"
+	"sort"
 	"sync"
 
 	"go.opentelemetry.io/otel/attribute"
@@ -28,6 +29,13 @@ func (j *selectJob) Run(ctx context.Context, clients job.RuntimeClients, stream
 	_, ctx, stream, finish := job.StartSpan(ctx, stream, j)
 	defer func() { finish(alert, err) }()
 
+	if isAggregatingSelect(j.path) {
+		aggregatingStream := newAggregatingSelectStream(stream, j.path)
+		alert, err = j.child.Run(ctx, clients, aggregatingStream)
+		aggregatingStream.Flush()
+		return alert, err
+	}
+
 	selectingStream := newSelectingStream(stream, j.path)
 	return j.child.Run(ctx, clients, selectingStream)
 }
@@ -95,3 +103,70 @@ func newSelectingStream(parent streaming.Sender, s filter.SelectPath) streaming.
 		parent.Send(e)
 	})
 }
+
+// isAggregatingSelect returns true if the selected values of all results are
+// merged into distinct values with counts, like the languages of file matches.
+func isAggregatingSelect(sp filter.SelectPath) bool {
+	return isSelectLanguageSearch(sp) || (sp.Root() == filter.Commit && len(sp) == 2 && sp[1] == "author")
+}
+
+// aggregatingSelectStream runs the select operation on each event, merging
+// the selected matches. Counts are only known once all results are in, so the
+// merged matches are held back until Flush, and only stats are passed on
+// until then.
+type aggregatingSelectStream struct {
+	parent streaming.Sender
+	path   filter.SelectPath
+
+	mu    sync.Mutex
+	dedup result.Deduper
+}
+
+func newAggregatingSelectStream(parent streaming.Sender, s filter.SelectPath) *aggregatingSelectStream {
+	return &aggregatingSelectStream{
+		parent: parent,
+		path:   s,
+		dedup:  result.NewDeduper(),
+	}
+}
+
+func (s *aggregatingSelectStream) Send(e streaming.SearchEvent) {
+	s.mu.Lock()
+	for _, match := range e.Results {
+		if current := match.Select(s.path); current != nil {
+			s.dedup.Add(current)
+		}
+	}
+	s.mu.Unlock()
+
+	e.Results = nil
+	s.parent.Send(e)
+}
+
+// Flush sends the merged matches, ordered by descending count.
+func (s *aggregatingSelectStream) Flush() {
+	s.mu.Lock()
+	results := s.dedup.Results()
+	s.mu.Unlock()
+
+	if len(results) == 0 {
+		return
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if ci, cj := aggregateCount(results[i]), aggregateCount(results[j]); ci != cj {
+			return ci > cj
+		}
+		return results[i].Key().Less(results[j].Key())
+	})
+	s.parent.Send(streaming.SearchEvent{Results: results})
+}
+
+func aggregateCount(m result.Match) int {
+	switch v := m.(type) {
+	case *result.LanguageMatch:
+		return v.Count
+	case *result.CommitAuthorMatch:
+		return v.Count
+	}
+	return 0
+}
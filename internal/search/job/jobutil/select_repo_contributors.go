@@ -0,0 +1,133 @@
+package jobutil
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// maxContributorsPerRepo is the number of top committers returned per
+// repository for select:repo.contributors.
+const maxContributorsPerRepo = 10
+
+// NewSelectRepoContributorsJob creates a job that replaces results with the
+// top committers of their repositories, for select:repo.contributors.
+func NewSelectRepoContributorsJob(child job.Job) job.Job {
+	return &selectRepoContributorsJob{
+		child: child,
+	}
+}
+
+type selectRepoContributorsJob struct {
+	child job.Job
+}
+
+func (j *selectRepoContributorsJob) Run(ctx context.Context, clients job.RuntimeClients, stream streaming.Sender) (alert *search.Alert, err error) {
+	_, ctx, stream, finish := job.StartSpan(ctx, stream, j)
+	defer finish(alert, err)
+
+	var (
+		mu    sync.Mutex
+		errs  error
+		dedup = result.NewDeduper()
+	)
+
+	repoPath := filter.SelectPath{filter.Repository}
+	filteredStream := streaming.StreamFunc(func(event streaming.SearchEvent) {
+		var repos []*result.RepoMatch
+		mu.Lock()
+		for _, m := range event.Results {
+			rm, ok := m.Select(repoPath).(*result.RepoMatch)
+			if !ok || dedup.Seen(rm) {
+				continue
+			}
+			dedup.Add(rm)
+			repos = append(repos, rm)
+		}
+		mu.Unlock()
+
+		// Contributors are fetched one repository at a time. We should quit
+		// early on context deadline exceeded.
+		var results result.Matches
+		for _, rm := range repos {
+			if err := ctx.Err(); err != nil {
+				mu.Lock()
+				errs = errors.Append(errs, err)
+				mu.Unlock()
+				break
+			}
+			contributors, err := getRepoContributors(ctx, clients.Gitserver, rm)
+			if err != nil {
+				mu.Lock()
+				errs = errors.Append(errs, err)
+				mu.Unlock()
+				continue
+			}
+			results = append(results, contributors...)
+		}
+
+		event.Results = results
+		stream.Send(event)
+	})
+
+	alert, err = j.child.Run(ctx, clients, filteredStream)
+	if err != nil {
+		errs = errors.Append(errs, err)
+	}
+	return alert, errs
+}
+
+func (j *selectRepoContributorsJob) Name() string {
+	return "SelectRepoContributorsJob"
+}
+
+func (j *selectRepoContributorsJob) Attributes(_ job.Verbosity) []attribute.KeyValue { return nil }
+
+func (j *selectRepoContributorsJob) Children() []job.Describer {
+	return []job.Describer{j.child}
+}
+
+func (j *selectRepoContributorsJob) MapChildren(fn job.MapFunc) job.Job {
+	cp := *j
+	cp.child = job.Map(j.child, fn)
+	return &cp
+}
+
+// getRepoContributors returns the top committers of the repository of the
+// given match, ordered by descending commit count.
+func getRepoContributors(ctx context.Context, client gitserver.Client, rm *result.RepoMatch) (result.Matches, error) {
+	contributors, err := client.ContributorCount(ctx, rm.Name, gitserver.ContributorOptions{
+		Range: rm.Rev,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(contributors) > maxContributorsPerRepo {
+		contributors = contributors[:maxContributorsPerRepo]
+	}
+	matches := make(result.Matches, 0, len(contributors))
+	for _, c := range contributors {
+		matches = append(matches, &result.ContributorMatch{
+			Repo:  rm.RepoName(),
+			Rev:   rm.Rev,
+			Name:  c.Name,
+			Email: c.Email,
+			Count: int(c.Count),
+		})
+	}
+	return matches, nil
+}
+
+func isSelectRepoContributorsSearch(sp filter.SelectPath) bool {
+	return sp.Root() == filter.Repository && len(sp) == 2 && sp[1] == "contributors"
+}
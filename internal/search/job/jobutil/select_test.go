@@ -1,14 +1,23 @@
 package jobutil
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
 	"github.com/hexops/autogold/v2"
+	"github.com/stretchr/testify/require"
 
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/mockjob"
 	"github.com/sourcegraph/sourcegraph/internal/search/result"
 	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
 )
 
 func TestWithSelect(t *testing.T) {
@@ -230,3 +239,105 @@ func TestWithSelect(t *testing.T) {
   }
 ]`).Equal(t, test("content"))
 }
+
+func TestAggregatingSelect(t *testing.T) {
+	file := func(repo, path string) *result.FileMatch {
+		return &result.FileMatch{
+			File: result.File{Repo: types.MinimalRepo{Name: api.RepoName(repo)}, Path: path},
+		}
+	}
+	commit := func(repo, name, email string) *result.CommitMatch {
+		return &result.CommitMatch{
+			Repo:   types.MinimalRepo{Name: api.RepoName(repo)},
+			Commit: gitdomain.Commit{Author: gitdomain.Signature{Name: name, Email: email}},
+		}
+	}
+
+	test := func(selector string, events ...streaming.SearchEvent) []streaming.SearchEvent {
+		selectPath, err := filter.SelectPathFromString(selector)
+		require.NoError(t, err)
+
+		var got []streaming.SearchEvent
+		s := newAggregatingSelectStream(streaming.StreamFunc(func(e streaming.SearchEvent) {
+			got = append(got, e)
+		}), selectPath)
+		for _, e := range events {
+			s.Send(e)
+		}
+		s.Flush()
+		return got
+	}
+
+	t.Run("file.language", func(t *testing.T) {
+		got := test("file.language",
+			streaming.SearchEvent{Results: result.Matches{file("a", "main.go"), file("a", "main.py")}},
+			streaming.SearchEvent{Results: result.Matches{file("b", "lib.go"), file("b", "README")}},
+		)
+
+		// Results are held back until Flush, ordered by descending count.
+		require.Len(t, got, 3)
+		require.Empty(t, got[0].Results)
+		require.Empty(t, got[1].Results)
+		require.Equal(t, result.Matches{
+			&result.LanguageMatch{Language: "Go", Count: 2, Repo: types.MinimalRepo{Name: "a"}},
+			&result.LanguageMatch{Language: "Python", Count: 1, Repo: types.MinimalRepo{Name: "a"}},
+		}, got[2].Results)
+	})
+
+	t.Run("commit.author", func(t *testing.T) {
+		got := test("commit.author",
+			streaming.SearchEvent{Results: result.Matches{
+				commit("a", "Bob", "bob@example.com"),
+				commit("a", "Alice", "alice@example.com"),
+				commit("b", "Alice", "ALICE@example.com"),
+			}},
+		)
+
+		require.Len(t, got, 2)
+		require.Equal(t, result.Matches{
+			&result.CommitAuthorMatch{Name: "Alice", Email: "alice@example.com", Count: 2, Repo: types.MinimalRepo{Name: "a"}},
+			&result.CommitAuthorMatch{Name: "Bob", Email: "bob@example.com", Count: 1, Repo: types.MinimalRepo{Name: "a"}},
+		}, got[1].Results)
+	})
+
+	t.Run("no results", func(t *testing.T) {
+		got := test("file.language", streaming.SearchEvent{Results: result.Matches{file("a", "README")}})
+		require.Len(t, got, 1)
+		require.Empty(t, got[0].Results)
+	})
+}
+
+func TestSelectRepoContributorsJob(t *testing.T) {
+	repo := types.MinimalRepo{ID: 1, Name: "a"}
+
+	childJob := mockjob.NewMockJob()
+	childJob.RunFunc.SetDefaultHook(func(_ context.Context, _ job.RuntimeClients, s streaming.Sender) (*search.Alert, error) {
+		s.Send(streaming.SearchEvent{Results: result.Matches{
+			&result.FileMatch{File: result.File{Repo: repo, Path: "a"}},
+			&result.FileMatch{File: result.File{Repo: repo, Path: "b"}},
+		}})
+		return nil, nil
+	})
+
+	var contributors []*gitdomain.ContributorCount
+	for i := 0; i < maxContributorsPerRepo+2; i++ {
+		contributors = append(contributors, &gitdomain.ContributorCount{Name: "c", Email: "c@example.com", Count: int32(100 - i)})
+	}
+	gitserverClient := gitserver.NewMockClient()
+	gitserverClient.ContributorCountFunc.SetDefaultReturn(contributors, nil)
+
+	var got result.Matches
+	stream := streaming.StreamFunc(func(e streaming.SearchEvent) {
+		got = append(got, e.Results...)
+	})
+
+	j := NewSelectRepoContributorsJob(childJob)
+	alert, err := j.Run(context.Background(), job.RuntimeClients{Gitserver: gitserverClient}, stream)
+	require.Nil(t, alert)
+	require.NoError(t, err)
+
+	// Contributors are fetched once per repository and capped.
+	require.Len(t, gitserverClient.ContributorCountFunc.History(), 1)
+	require.Len(t, got, maxContributorsPerRepo)
+	require.Equal(t, &result.ContributorMatch{Repo: repo, Name: "c", Email: "c@example.com", Count: 100}, got[0])
+}
@@ -0,0 +1,285 @@
+package jobutil
+
+import (
+	"container/heap"
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/conc/pool"
+	"github.com/sourcegraph/log"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+)
+
+// NewSortJob creates a job that orders the results of its child for the
+// `sort:` field. Results are held back until the child is done, and only the
+// first limit results in the requested order are kept, so memory use is
+// bounded by limit no matter how many results the child finds. Ties are
+// broken by the result key, which makes the order deterministic.
+func NewSortJob(order query.SortOrder, limit int, child job.Job) job.Job {
+	if _, ok := child.(*NoopJob); ok {
+		return child
+	}
+	return &sortJob{
+		order: order,
+		limit: limit,
+		child: child,
+	}
+}
+
+type sortJob struct {
+	order query.SortOrder
+	limit int
+	child job.Job
+}
+
+func (j *sortJob) Run(ctx context.Context, clients job.RuntimeClients, s streaming.Sender) (alert *search.Alert, err error) {
+	_, ctx, stream, finish := job.StartSpan(ctx, s, j)
+	defer func() { finish(alert, err) }()
+
+	var (
+		mu  sync.Mutex
+		top = newTopMatches(j.limit, j.less)
+	)
+
+	sortingStream := streaming.StreamFunc(func(event streaming.SearchEvent) {
+		// Sort values are looked up before taking the lock, since recency
+		// requires a request to gitserver per file. A failed lookup leaves
+		// the date unknown, which sorts the match last, instead of failing
+		// the search.
+		entries := make([]*sortEntry, len(event.Results))
+		p := pool.New().WithMaxGoroutines(sortConcurrency)
+		for i, m := range event.Results {
+			i, m := i, m
+			p.Go(func() {
+				entry, err := j.newSortEntry(ctx, clients.Gitserver, m)
+				if err != nil && ctx.Err() == nil {
+					clients.Logger.Warn("failed to look up sort value, sorting match last",
+						log.String("repo", string(m.RepoName().Name)),
+						log.Error(err))
+				}
+				entries[i] = entry
+			})
+		}
+		p.Wait()
+
+		mu.Lock()
+		for _, entry := range entries {
+			top.Add(entry)
+		}
+		mu.Unlock()
+
+		// Pass on stats right away, results are only sent once the child is
+		// done.
+		event.Results = nil
+		stream.Send(event)
+	})
+
+	alert, err = j.child.Run(ctx, clients, sortingStream)
+
+	mu.Lock()
+	matches := top.Sorted()
+	mu.Unlock()
+	if len(matches) > 0 {
+		stream.Send(streaming.SearchEvent{Results: matches})
+	}
+
+	return alert, err
+}
+
+// sortConcurrency is the maximum number of sort values of a search event that
+// are looked up at once.
+const sortConcurrency = 16
+
+func (j *sortJob) newSortEntry(ctx context.Context, client gitserver.Client, m result.Match) (*sortEntry, error) {
+	entry := &sortEntry{match: m, key: m.Key()}
+	if j.order != query.SortRecency {
+		return entry, nil
+	}
+
+	var err error
+	entry.date, err = lastModified(ctx, client, m)
+	return entry, err
+}
+
+// lastModified returns the date of the last commit touching the file of a
+// file match, or the commit date of a commit match.
+func lastModified(ctx context.Context, client gitserver.Client, m result.Match) (time.Time, error) {
+	switch v := m.(type) {
+	case *result.FileMatch:
+		rev := string(v.CommitID)
+		if rev == "" {
+			rev = "HEAD"
+		}
+		commits, err := client.Commits(ctx, v.Repo.Name, gitserver.CommitsOptions{
+			Range: rev,
+			Path:  v.Path,
+			N:     1,
+		})
+		if err != nil || len(commits) == 0 {
+			return time.Time{}, err
+		}
+		return commitDate(commits[0].Author, commits[0].Committer), nil
+	case *result.CommitMatch:
+		return commitDate(v.Commit.Author, v.Commit.Committer), nil
+	}
+	return time.Time{}, nil
+}
+
+// commitDate prefers the committer date, which is when a commit landed on the
+// branch, over the author date.
+func commitDate(author gitdomain.Signature, committer *gitdomain.Signature) time.Time {
+	if committer != nil {
+		return committer.Date
+	}
+	return author.Date
+}
+
+func (j *sortJob) less(a, b *sortEntry) bool {
+	switch j.order {
+	case query.SortPath:
+		if pa, pb := matchPath(a.match), matchPath(b.match); pa != pb {
+			return pa < pb
+		}
+	case query.SortRecency:
+		if !a.date.Equal(b.date) {
+			return a.date.After(b.date)
+		}
+	case query.SortRepoStars:
+		if sa, sb := a.match.RepoName().Stars, b.match.RepoName().Stars; sa != sb {
+			return sa > sb
+		}
+	}
+	// Keys order by repository name first, which is all sort:repo needs.
+	return a.key.Less(b.key)
+}
+
+func matchPath(m result.Match) string {
+	if fm, ok := m.(*result.FileMatch); ok {
+		return fm.Path
+	}
+	return ""
+}
+
+func (j *sortJob) Name() string {
+	return "SortJob"
+}
+
+func (j *sortJob) Attributes(v job.Verbosity) (res []attribute.KeyValue) {
+	switch v {
+	case job.VerbosityMax:
+		fallthrough
+	case job.VerbosityBasic:
+		res = append(res,
+			attribute.String("order", string(j.order)),
+			attribute.Int("limit", j.limit),
+		)
+	}
+	return res
+}
+
+func (j *sortJob) Children() []job.Describer {
+	return []job.Describer{j.child}
+}
+
+func (j *sortJob) MapChildren(fn job.MapFunc) job.Job {
+	cp := *j
+	cp.child = job.Map(j.child, fn)
+	return &cp
+}
+
+type sortEntry struct {
+	match result.Match
+	key   result.Key
+
+	// date is the last modification date of the match, only set for
+	// sort:recency.
+	date time.Time
+}
+
+// topMatches keeps the first limit matches according to less. Its entries
+// form a heap with the last of the kept matches on top, so that it can be
+// replaced when a match that sorts before it comes along.
+type topMatches struct {
+	limit   int
+	less    func(a, b *sortEntry) bool
+	entries []*sortEntry
+	byKey   map[result.Key]*sortEntry
+}
+
+func newTopMatches(limit int, less func(a, b *sortEntry) bool) *topMatches {
+	return &topMatches{
+		limit: limit,
+		less:  less,
+		byKey: make(map[result.Key]*sortEntry),
+	}
+}
+
+// Add adds entry if it sorts before the last kept match, evicting that match
+// if the limit is reached. Matches with the same key are merged.
+func (t *topMatches) Add(entry *sortEntry) {
+	if prev, ok := t.byKey[entry.key]; ok {
+		switch prevMatch := prev.match.(type) {
+		case *result.FileMatch:
+			prevMatch.AppendMatches(entry.match.(*result.FileMatch))
+		case *result.CommitMatch:
+			prevMatch.AppendMatches(entry.match.(*result.CommitMatch))
+		}
+		return
+	}
+
+	if len(t.entries) < t.limit {
+		heap.Push(t, entry)
+		t.byKey[entry.key] = entry
+		return
+	}
+
+	if len(t.entries) == 0 || !t.less(entry, t.entries[0]) {
+		return
+	}
+	delete(t.byKey, t.entries[0].key)
+	t.entries[0] = entry
+	t.byKey[entry.key] = entry
+	heap.Fix(t, 0)
+}
+
+// Sorted returns the kept matches in order.
+func (t *topMatches) Sorted() result.Matches {
+	sorted := make([]*sortEntry, len(t.entries))
+	copy(sorted, t.entries)
+	sort.Slice(sorted, func(i, j int) bool {
+		return t.less(sorted[i], sorted[j])
+	})
+
+	matches := make(result.Matches, 0, len(sorted))
+	for _, entry := range sorted {
+		matches = append(matches, entry.match)
+	}
+	return matches
+}
+
+// Len, Less, Swap, Push and Pop implement heap.Interface. The heap is
+// ordered in reverse, with the last match on top.
+
+func (t *topMatches) Len() int { return len(t.entries) }
+
+func (t *topMatches) Less(i, j int) bool { return t.less(t.entries[j], t.entries[i]) }
+
+func (t *topMatches) Swap(i, j int) { t.entries[i], t.entries[j] = t.entries[j], t.entries[i] }
+
+func (t *topMatches) Push(x any) { t.entries = append(t.entries, x.(*sortEntry)) }
+
+func (t *topMatches) Pop() any {
+	last := t.entries[len(t.entries)-1]
+	t.entries = t.entries[:len(t.entries)-1]
+	return last
+}
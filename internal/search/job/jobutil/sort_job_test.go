@@ -0,0 +1,143 @@
+package jobutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/log/logtest"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/search/job"
+	"github.com/sourcegraph/sourcegraph/internal/search/job/mockjob"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/search/result"
+	"github.com/sourcegraph/sourcegraph/internal/search/streaming"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestSortJob(t *testing.T) {
+	file := func(repo string, stars int, path string) *result.FileMatch {
+		return &result.FileMatch{
+			File: result.File{
+				Repo:     types.MinimalRepo{Name: api.RepoName(repo), Stars: stars},
+				CommitID: "deadbeef",
+				Path:     path,
+			},
+		}
+	}
+
+	// lastCommit maps file paths to the date of their last commit, in days
+	// since the epoch.
+	lastCommit := map[string]int{
+		"a.go": 3,
+		"b.go": 1,
+		"c.go": 2,
+		"d.go": 5,
+	}
+	// failing lists the paths whose last commit cannot be looked up.
+	failing := map[string]bool{}
+	gitserverClient := gitserver.NewMockClient()
+	gitserverClient.CommitsFunc.SetDefaultHook(func(_ context.Context, _ api.RepoName, opts gitserver.CommitsOptions) ([]*gitdomain.Commit, error) {
+		if failing[opts.Path] {
+			return nil, errors.New("gitserver unavailable")
+		}
+		date := time.Unix(0, 0).Add(time.Duration(lastCommit[opts.Path]) * 24 * time.Hour)
+		return []*gitdomain.Commit{{Author: gitdomain.Signature{Date: date}}}, nil
+	})
+
+	test := func(order query.SortOrder, limit int) []string {
+		childJob := mockjob.NewMockJob()
+		childJob.RunFunc.SetDefaultHook(func(_ context.Context, _ job.RuntimeClients, s streaming.Sender) (*search.Alert, error) {
+			s.Send(streaming.SearchEvent{Results: result.Matches{
+				file("r2", 10, "c.go"),
+				file("r1", 5, "d.go"),
+			}})
+			s.Send(streaming.SearchEvent{Results: result.Matches{
+				file("r3", 1, "a.go"),
+				file("r1", 5, "b.go"),
+				file("r2", 10, "c.go"),
+			}})
+			return nil, nil
+		})
+
+		var got []string
+		stream := streaming.StreamFunc(func(e streaming.SearchEvent) {
+			for _, m := range e.Results {
+				fm := m.(*result.FileMatch)
+				got = append(got, string(fm.Repo.Name)+"/"+fm.Path)
+			}
+		})
+
+		j := NewSortJob(order, limit, childJob)
+		alert, err := j.Run(context.Background(), job.RuntimeClients{Gitserver: gitserverClient, Logger: logtest.Scoped(t)}, stream)
+		require.Nil(t, alert)
+		require.NoError(t, err)
+		return got
+	}
+
+	t.Run("path", func(t *testing.T) {
+		require.Equal(t, []string{"r3/a.go", "r1/b.go", "r2/c.go", "r1/d.go"}, test(query.SortPath, 10))
+	})
+
+	t.Run("repo", func(t *testing.T) {
+		require.Equal(t, []string{"r1/b.go", "r1/d.go", "r2/c.go", "r3/a.go"}, test(query.SortRepo, 10))
+	})
+
+	t.Run("recency", func(t *testing.T) {
+		require.Equal(t, []string{"r1/d.go", "r3/a.go", "r2/c.go", "r1/b.go"}, test(query.SortRecency, 10))
+	})
+
+	t.Run("recency with a failed lookup sorts the match last", func(t *testing.T) {
+		failing["d.go"] = true
+		defer delete(failing, "d.go")
+		require.Equal(t, []string{"r3/a.go", "r2/c.go", "r1/b.go", "r1/d.go"}, test(query.SortRecency, 10))
+	})
+
+	t.Run("repo.stars", func(t *testing.T) {
+		require.Equal(t, []string{"r2/c.go", "r1/b.go", "r1/d.go", "r3/a.go"}, test(query.SortRepoStars, 10))
+	})
+
+	t.Run("limit keeps first results in order", func(t *testing.T) {
+		require.Equal(t, []string{"r3/a.go", "r1/b.go"}, test(query.SortPath, 2))
+	})
+}
+
+func TestPlanSort(t *testing.T) {
+	test := func(input string) (query.Plan, query.SortOrder, int, error) {
+		plan, err := query.Pipeline(query.Init(input, query.SearchTypeStandard))
+		require.NoError(t, err)
+		order, maxResults, err := planSort(&search.Inputs{}, plan)
+		return plan, order, maxResults, err
+	}
+
+	t.Run("compound query", func(t *testing.T) {
+		plan, order, maxResults, err := test("(repo:a x) or (repo:b y) sort:path count:5")
+		require.NoError(t, err)
+		require.Equal(t, query.SortPath, order)
+		require.Equal(t, 5, maxResults)
+
+		// Every basic query sorts its own results.
+		plan = withSort(plan, order)
+		require.Len(t, plan, 2)
+		for _, b := range plan {
+			require.Equal(t, query.SortPath, b.Sort())
+		}
+	})
+
+	t.Run("no sort", func(t *testing.T) {
+		_, order, _, err := test("(repo:a x) or (repo:b y)")
+		require.NoError(t, err)
+		require.Equal(t, query.SortDefault, order)
+	})
+
+	t.Run("conflicting orders", func(t *testing.T) {
+		_, _, _, err := test("(repo:a x sort:path) or (repo:b y sort:repo)")
+		require.Error(t, err)
+	})
+}
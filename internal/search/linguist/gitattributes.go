@@ -0,0 +1,77 @@
+package linguist
+
+import (
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitattributes"
+)
+
+const (
+	attrGenerated = "linguist-generated"
+	attrVendored  = "linguist-vendored"
+)
+
+// Attributes are the linguist attributes set in a .gitattributes file, which
+// override the heuristics for the files they match.
+type Attributes struct {
+	rules []gitattributes.MatchAttribute
+}
+
+// ParseAttributes parses the contents of the .gitattributes file at the root
+// of a repository. Invalid lines are skipped like git does.
+func ParseAttributes(data []byte) *Attributes {
+	var rules []gitattributes.MatchAttribute
+	for _, line := range strings.Split(string(data), "\n") {
+		rule, err := gitattributes.ParseAttributesLine(line, nil, true)
+		if err != nil || rule.Pattern == nil {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return &Attributes{rules: rules}
+}
+
+// Generated returns whether the linguist-generated attribute marks path as
+// generated. ok is false if the attribute is not specified for path.
+func (a *Attributes) Generated(path string) (generated, ok bool) {
+	return a.lookup(path, attrGenerated)
+}
+
+// Vendored returns whether the linguist-vendored attribute marks path as
+// vendored. ok is false if the attribute is not specified for path.
+func (a *Attributes) Vendored(path string) (vendored, ok bool) {
+	return a.lookup(path, attrVendored)
+}
+
+func (a *Attributes) lookup(path, name string) (value, ok bool) {
+	if a == nil {
+		return false, false
+	}
+
+	segments := strings.Split(path, "/")
+	// Later lines take precedence over earlier ones.
+	for i := len(a.rules) - 1; i >= 0; i-- {
+		rule := a.rules[i]
+		if !rule.Pattern.Match(segments) {
+			continue
+		}
+		for _, attr := range rule.Attributes {
+			if attr.Name() != name {
+				continue
+			}
+			switch {
+			case attr.IsSet():
+				return true, true
+			case attr.IsUnset():
+				return false, true
+			case attr.IsValueSet():
+				v := strings.ToLower(attr.Value())
+				return v == "true" || v == "1", true
+			default:
+				// "!attr" resets the attribute to unspecified.
+				return false, false
+			}
+		}
+	}
+	return false, false
+}
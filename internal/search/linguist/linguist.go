@@ -0,0 +1,99 @@
+// Package linguist classifies files as generated or vendored, following the
+// rules GitHub Linguist uses to exclude files from language statistics: path
+// heuristics, file header heuristics and the linguist-generated and
+// linguist-vendored attributes in .gitattributes.
+package linguist
+
+import (
+	"bufio"
+	"bytes"
+
+	"github.com/go-enry/go-enry/v2"
+	"github.com/go-enry/go-enry/v2/data"
+	"github.com/grafana/regexp"
+)
+
+// HeaderSize is the number of bytes at the start of a file that IsGenerated
+// looks at.
+const HeaderSize = 4096
+
+// headerLines is the number of lines at the start of a file that are checked
+// for a generated code marker.
+const headerLines = 20
+
+// generatedMarker matches the lines that code generators are expected to put
+// in the header of generated files, like the Go convention "// Code generated
+// by stringer. DO NOT EDIT." in any comment syntax, or the "@generated" tag.
+var generatedMarker = regexp.MustCompile(`^\s*(//|#|--|;|/?\*|<!--|\(\*)\s*(Code generated .*DO NOT EDIT|@generated\b)`)
+
+// IsVendoredPath returns whether path is vendored code according to the
+// linguist path heuristics.
+func IsVendoredPath(path string) bool {
+	return enry.IsVendor(path)
+}
+
+// VendoredPathPatterns returns regular expressions which together match the
+// paths IsVendoredPath classifies as vendored, so that search backends can
+// exclude them.
+func VendoredPathPatterns() []string {
+	patterns := make([]string, 0, len(data.VendorMatchers))
+	for _, m := range data.VendorMatchers {
+		// Rules with a syntax RE2 does not support are nil, and are skipped by
+		// IsVendoredPath too.
+		if m != nil {
+			patterns = append(patterns, m.String())
+		}
+	}
+	return patterns
+}
+
+// generatedPathPatterns mirrors the path heuristics of enry.IsGenerated, which
+// are only available as functions.
+var generatedPathPatterns = []string{
+	`(?i)\.(nib|xcworkspacedata|xcuserstate)$`,
+	`(^Pods|/Pods)/`,
+	`(^|/)Carthage/Build/`,
+	`(?i)\.designer\.(cs|vb)$`,
+	`\.feature\.cs$`,
+	`node_modules/`,
+	`vendor/([-0-9A-Za-z]+\.)+(com|edu|gov|in|me|net|org|fm|io)`,
+	`(Gopkg|glide|composer|Cargo|Pipfile|poetry)\.lock$`,
+	`(^|/)(\w+\.)?esy\.lock$`,
+	`(npm-shrinkwrap|package-lock)\.json$`,
+	`(^|/)\.pnp\..*$`,
+	`Godeps/`,
+	`.\.zep\.(c|h|php)$`,
+	`__generated__/`,
+	`(?i)\.(js|css)\.map$`,
+}
+
+// GeneratedPathPatterns returns regular expressions which together match the
+// paths IsGeneratedPath classifies as generated, so that search backends can
+// exclude them. Files that are only classified as generated by their header
+// are not matched.
+func GeneratedPathPatterns() []string {
+	return generatedPathPatterns
+}
+
+// IsGeneratedPath returns whether path is generated code according to the
+// linguist path heuristics alone. If it returns false, IsGenerated may still
+// classify the file as generated based on its header.
+func IsGeneratedPath(path string) bool {
+	return enry.IsGenerated(path, nil)
+}
+
+// IsGenerated returns whether the file at path is generated code, based on
+// its path and header, which are the first HeaderSize bytes of the file.
+func IsGenerated(path string, header []byte) bool {
+	if enry.IsGenerated(path, header) {
+		return true
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(header))
+	for i := 0; i < headerLines && scanner.Scan(); i++ {
+		if generatedMarker.Match(scanner.Bytes()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,169 @@
+package linguist
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grafana/regexp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsVendoredPath(t *testing.T) {
+	for path, want := range map[string]bool{
+		"vendor/github.com/pkg/errors/errors.go": true,
+		"web/node_modules/react/index.js":        true,
+		"third_party/zlib/zlib.h":                true,
+		"internal/search/search.go":              false,
+		"cmd/vendored.go":                        false,
+	} {
+		require.Equal(t, want, IsVendoredPath(path), path)
+	}
+}
+
+func TestIsGenerated(t *testing.T) {
+	cases := []struct {
+		name   string
+		path   string
+		header string
+		want   bool
+	}{{
+		name: "path heuristic",
+		path: "client/package-lock.json",
+		want: true,
+	}, {
+		name:   "go header",
+		path:   "internal/kind_string.go",
+		header: "// Code generated by \"stringer -type=Kind\"; DO NOT EDIT.\n\npackage internal\n",
+		want:   true,
+	}, {
+		name:   "go header without generator",
+		path:   "internal/mocks.go",
+		header: "// Code generated DO NOT EDIT.\n\npackage internal\n",
+		want:   true,
+	}, {
+		name:   "protobuf",
+		path:   "api/api.pb.go",
+		header: "// Code generated by protoc-gen-go. DO NOT EDIT.\n// versions:\n//   protoc-gen-go v1.31.0\n\npackage api\n",
+		want:   true,
+	}, {
+		name:   "python protobuf",
+		path:   "api/api_pb2.py",
+		header: "# -*- coding: utf-8 -*-\n# Generated by the protocol buffer compiler.  DO NOT EDIT!\n# source: api.proto\n",
+		want:   true,
+	}, {
+		name:   "generated tag",
+		path:   "web/__generated__/Query.graphql.ts",
+		header: "/**\n * @generated SignedSource<<abc>>\n */\n",
+		want:   true,
+	}, {
+		name:   "marker after the header",
+		path:   "internal/search.go",
+		header: "package search\n\n" + strings.Repeat("var x = 1\n", 50) + "// Code generated by hand. DO NOT EDIT.\n",
+		want:   false,
+	}, {
+		name:   "marker in a string",
+		path:   "scripts/gen.py",
+		header: "import sys\n\nHEADER = \"// Code generated by gen. DO NOT EDIT.\"\n",
+		want:   false,
+	}, {
+		name:   "hand written",
+		path:   "internal/search/search.go",
+		header: "package search\n",
+		want:   false,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, IsGenerated(tc.path, []byte(tc.header)))
+		})
+	}
+}
+
+func TestAttributes(t *testing.T) {
+	attrs := ParseAttributes([]byte(`
+# Generated code
+*.pb.go linguist-generated
+/gen/** linguist-generated=true
+gen/handwritten.go -linguist-generated
+
+third_party/** linguist-vendored
+third_party/ours/** linguist-vendored=false
+docs/** !linguist-vendored
+[attr]binary -diff -merge -text
+*.png binary
+`))
+
+	generated := func(path string) any {
+		if v, ok := attrs.Generated(path); ok {
+			return v
+		}
+		return nil
+	}
+	vendored := func(path string) any {
+		if v, ok := attrs.Vendored(path); ok {
+			return v
+		}
+		return nil
+	}
+
+	require.Equal(t, true, generated("api/v1/api.pb.go"))
+	require.Equal(t, true, generated("gen/a/b.go"))
+	require.Equal(t, false, generated("gen/handwritten.go"))
+	require.Equal(t, nil, generated("internal/search.go"))
+	require.Equal(t, nil, generated("logo.png"))
+
+	require.Equal(t, true, vendored("third_party/zlib/zlib.h"))
+	require.Equal(t, false, vendored("third_party/ours/lib.go"))
+	require.Equal(t, nil, vendored("docs/index.md"))
+	require.Equal(t, nil, vendored("internal/search.go"))
+
+	var none *Attributes
+	_, ok := none.Generated("api/v1/api.pb.go")
+	require.False(t, ok)
+}
+
+func TestPathPatterns(t *testing.T) {
+	matches := func(patterns []string, path string) bool {
+		for _, p := range patterns {
+			if regexp.MustCompile(p).MatchString(path) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, path := range []string{
+		"vendor/github.com/pkg/errors/errors.go",
+		"web/node_modules/react/index.js",
+		"third_party/zlib/zlib.h",
+		"internal/search/search.go",
+		"cmd/vendored.go",
+	} {
+		require.Equal(t, IsVendoredPath(path), matches(VendoredPathPatterns(), path), path)
+	}
+
+	for _, path := range []string{
+		"MainMenu.nib",
+		"ios/Pods/Alamofire/Source/AF.swift",
+		"Carthage/Build/iOS/Lib.framework/Lib",
+		"Form1.Designer.cs",
+		"features/login.feature.cs",
+		"web/node_modules/react/index.js",
+		"vendor/github.com/pkg/errors/errors.go",
+		"Gopkg.lock",
+		"Cargo.lock",
+		"esy.lock",
+		"client/package-lock.json",
+		"npm-shrinkwrap.json",
+		".pnp.cjs",
+		"Godeps/Godeps.json",
+		"ext/kernel.zep.c",
+		"src/__generated__/query.graphql.ts",
+		"dist/app.js.map",
+		"internal/search/search.go",
+		"web/src/index.js",
+		"cmd/generated.go",
+	} {
+		require.Equal(t, IsGeneratedPath(path), matches(GeneratedPathPatterns(), path), path)
+	}
+}
@@ -49,6 +49,8 @@ var DefaultPredicateRegistry = PredicateRegistry{
 		"has.content":      func() Predicate { return &FileContainsContentPredicate{} },
 		"has.owner":        func() Predicate { return &FileHasOwnerPredicate{} },
 		"has.contributor":  func() Predicate { return &FileHasContributorPredicate{} },
+		"is.generated":     func() Predicate { return &FileIsGeneratedPredicate{} },
+		"is.vendored":      func() Predicate { return &FileIsVendoredPredicate{} },
 	},
 }
 
@@ -614,3 +616,37 @@ func (f *FileHasContributorPredicate) Unmarshal(params string, negated bool) err
 
 func (f FileHasContributorPredicate) Field() string { return FieldFile }
 func (f FileHasContributorPredicate) Name() string  { return "has.contributor" }
+
+/* file:is.generated() */
+
+type FileIsGeneratedPredicate struct {
+	Negated bool
+}
+
+func (f *FileIsGeneratedPredicate) Unmarshal(params string, negated bool) error {
+	if strings.TrimSpace(params) != "" {
+		return errors.New("the file:is.generated() predicate does not take arguments")
+	}
+	f.Negated = negated
+	return nil
+}
+
+func (f FileIsGeneratedPredicate) Field() string { return FieldFile }
+func (f FileIsGeneratedPredicate) Name() string  { return "is.generated" }
+
+/* file:is.vendored() */
+
+type FileIsVendoredPredicate struct {
+	Negated bool
+}
+
+func (f *FileIsVendoredPredicate) Unmarshal(params string, negated bool) error {
+	if strings.TrimSpace(params) != "" {
+		return errors.New("the file:is.vendored() predicate does not take arguments")
+	}
+	f.Negated = negated
+	return nil
+}
+
+func (f FileIsVendoredPredicate) Field() string { return FieldFile }
+func (f FileIsVendoredPredicate) Name() string  { return "is.vendored" }
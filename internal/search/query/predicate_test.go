@@ -366,3 +366,33 @@ func TestFileHasContributorPredicate(t *testing.T) {
 		}
 	})
 }
+
+func TestFileIsPredicates(t *testing.T) {
+	p := &FileIsGeneratedPredicate{}
+	if err := p.Unmarshal("", true); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(&FileIsGeneratedPredicate{Negated: true}, p) {
+		t.Fatalf("expected negated predicate, got %#v", p)
+	}
+
+	want := "the file:is.vendored() predicate does not take arguments"
+	if err := (&FileIsVendoredPredicate{}).Unmarshal("vendor/", false); err == nil || err.Error() != want {
+		t.Fatalf("expected error %q, got %v", want, err)
+	}
+
+	ps, err := ParseStandard("-file:is.generated() file:is.vendored() foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ToBasicQuery(ps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if include, exclude := b.FileIsGenerated(); include || !exclude {
+		t.Fatalf("expected is.generated to be excluded, got include=%t exclude=%t", include, exclude)
+	}
+	if include, exclude := b.FileIsVendored(); !include || exclude {
+		t.Fatalf("expected is.vendored to be included, got include=%t exclude=%t", include, exclude)
+	}
+}
@@ -0,0 +1,44 @@
+package query
+
+import (
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// SortOrder is the order of search results requested with `sort:`.
+type SortOrder string
+
+const (
+	// SortDefault keeps results in the order backends return them.
+	SortDefault SortOrder = ""
+
+	// SortPath orders results by file path, then repository.
+	SortPath SortOrder = "path"
+
+	// SortRepo orders results by repository name.
+	SortRepo SortOrder = "repo"
+
+	// SortRecency orders results by the date of the last commit touching a
+	// file, or the commit date for commit results, newest first.
+	SortRecency SortOrder = "recency"
+
+	// SortRepoStars orders results by the number of stars of their
+	// repository, most starred first.
+	SortRepoStars SortOrder = "repo.stars"
+)
+
+var validSortOrders = []SortOrder{SortPath, SortRepo, SortRecency, SortRepoStars}
+
+func ParseSortOrder(s string) (SortOrder, error) {
+	for _, o := range validSortOrders {
+		if strings.EqualFold(s, string(o)) {
+			return o, nil
+		}
+	}
+	values := make([]string, 0, len(validSortOrders))
+	for _, o := range validSortOrders {
+		values = append(values, string(o))
+	}
+	return SortDefault, errors.Errorf("invalid value %q for field %q. Valid values are: %s", s, FieldSort, strings.Join(values, ", "))
+}
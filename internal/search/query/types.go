@@ -512,6 +512,32 @@ func (p Parameters) FileHasContributor() (include []string, exclude []string) {
 	return include, exclude
 }
 
+// FileIsGenerated returns whether the query contains file:is.generated()
+// (include) or -file:is.generated() (exclude).
+func (p Parameters) FileIsGenerated() (include, exclude bool) {
+	VisitTypedPredicate(toNodes(p), func(pred *FileIsGeneratedPredicate) {
+		if pred.Negated {
+			exclude = true
+		} else {
+			include = true
+		}
+	})
+	return include, exclude
+}
+
+// FileIsVendored returns whether the query contains file:is.vendored()
+// (include) or -file:is.vendored() (exclude).
+func (p Parameters) FileIsVendored() (include, exclude bool) {
+	VisitTypedPredicate(toNodes(p), func(pred *FileIsVendoredPredicate) {
+		if pred.Negated {
+			exclude = true
+		} else {
+			include = true
+		}
+	})
+	return include, exclude
+}
+
 // Exists returns whether a parameter exists in the query (whether negated or not).
 func (p Parameters) Exists(field string) bool {
 	found := false
@@ -566,6 +592,20 @@ func (p Parameters) GetTimeout() *time.Duration {
 	return timeout
 }
 
+// Sort returns the result order requested with the `sort:` field, or
+// SortDefault if there is none.
+func (p Parameters) Sort() SortOrder {
+	var order SortOrder
+	VisitField(toNodes(p), FieldSort, func(value string, _ bool, _ Annotation) {
+		o, err := ParseSortOrder(value)
+		if err != nil {
+			panic(fmt.Sprintf("Value %q for sort cannot be parsed: %s", value, err))
+		}
+		order = o
+	})
+	return order
+}
+
 func (p Parameters) VisitParameter(field string, f func(value string, negated bool, annotation Annotation)) {
 	for _, parameter := range p {
 		if parameter.Field == field {
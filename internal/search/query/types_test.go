@@ -56,3 +56,12 @@ func TestRepoHasKVPs(t *testing.T) {
 
 	require.Equal(t, want, ps.RepoHasKVPs())
 }
+
+func TestSort(t *testing.T) {
+	require.Equal(t, SortDefault, Parameters{}.Sort())
+
+	ps := Parameters{
+		Parameter{Field: FieldSort, Value: "Repo.Stars"},
+	}
+	require.Equal(t, SortRepoStars, ps.Sort())
+}
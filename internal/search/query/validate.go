@@ -222,6 +222,11 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 		return err
 	}
 
+	isValidSort := func() error {
+		_, err := ParseSortOrder(value)
+		return err
+	}
+
 	isValidGitDate := func() error {
 		_, err := ParseGitDate(value, time.Now)
 		return err
@@ -298,6 +303,9 @@ func validateField(field, value string, negated bool, seen map[string]struct{})
 	case
 		FieldSelect:
 		return satisfies(isSingular, isNotNegated, isValidSelect)
+	case
+		FieldSort:
+		return satisfies(isSingular, isNotNegated, isValidSort)
 	default:
 		return isUnrecognizedField()
 	}
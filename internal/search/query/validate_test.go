@@ -111,6 +111,14 @@ func TestValidation(t *testing.T) {
 			input: "type:symbol select:symbol.timelime",
 			want:  `invalid field "timelime" on select path "symbol.timelime"`,
 		},
+		{
+			input: "foo sort:size",
+			want:  `invalid value "size" for field "sort". Valid values are: path, repo, recency, repo.stars`,
+		},
+		{
+			input: "foo -sort:path",
+			want:  `field "sort" does not support negation`,
+		},
 		{
 			input:      "nice try type:repo",
 			want:       "this structural search query specifies `type:` and is not supported. Structural search syntax only applies to searching file contents",
@@ -0,0 +1,249 @@
+package query
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/grafana/regexp"
+	"github.com/grafana/regexp/syntax"
+
+	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// VariableType is the type of a query variable. It determines the values a
+// variable accepts and how a value is written into the query.
+type VariableType string
+
+const (
+	// VariableString accepts any text that is parsed as a single pattern,
+	// which is substituted as a single (quoted if necessary) value. Values
+	// like `repo:foo` or `-file:bar` are rejected, since they would be parsed
+	// as filters.
+	VariableString VariableType = "string"
+
+	// VariableRegexp accepts a valid regular expression.
+	VariableRegexp VariableType = "regexp"
+
+	// VariableRepo accepts a repository name, which is substituted as a
+	// regular expression matching exactly that repository, e.g.
+	// `repo:$service`.
+	VariableRepo VariableType = "repo"
+
+	// VariablePath accepts a file or directory path, which is substituted as
+	// a regular expression matching the path and everything below it, e.g.
+	// `file:$dir`.
+	VariablePath VariableType = "path"
+
+	// VariableDate accepts any date accepted by `after:` and `before:`, e.g.
+	// `after:$since`.
+	VariableDate VariableType = "date"
+
+	// VariableNumber accepts a non-negative integer, e.g. `count:$limit`.
+	VariableNumber VariableType = "number"
+)
+
+var validVariableTypes = []VariableType{VariableString, VariableRegexp, VariableRepo, VariablePath, VariableDate, VariableNumber}
+
+func ParseVariableType(s string) (VariableType, error) {
+	for _, t := range validVariableTypes {
+		if strings.EqualFold(s, string(t)) {
+			return t, nil
+		}
+	}
+	values := make([]string, 0, len(validVariableTypes))
+	for _, t := range validVariableTypes {
+		values = append(values, string(t))
+	}
+	return "", errors.Errorf("invalid variable type %q. Valid types are: %s", s, strings.Join(values, ", "))
+}
+
+// Variable is a typed variable declared for a query, like the query of a
+// saved search. The query references it as $name.
+type Variable struct {
+	Name string
+	Type VariableType
+}
+
+// exampleValue returns a valid value of type t, which is used to check that
+// a query is valid independently of the values its variables take.
+func (t VariableType) exampleValue() string {
+	switch t {
+	case VariableDate:
+		return "2006-01-02"
+	case VariableNumber:
+		return "1"
+	default:
+		return "x"
+	}
+}
+
+// format validates value and returns the string to substitute for a
+// reference to a variable of type t in a query of the search type.
+func (t VariableType) format(value string, searchType SearchType) (string, error) {
+	if value == "" {
+		return "", errors.New("value is empty")
+	}
+
+	switch t {
+	case VariableString:
+		quoted := quoteVariableValue(value)
+		if !isSinglePattern(quoted, searchType) {
+			return "", errors.Errorf("%q is not a single pattern", value)
+		}
+		return quoted, nil
+	case VariableRegexp:
+		if _, err := syntax.Parse(value, syntax.Perl); err != nil {
+			return "", err
+		}
+		return quoteVariableValue(value), nil
+	case VariableRepo:
+		if strings.IndexFunc(value, unicode.IsSpace) >= 0 {
+			return "", errors.Errorf("%q is not a repository name", value)
+		}
+		return "^" + regexp.QuoteMeta(value) + "$", nil
+	case VariablePath:
+		return quoteVariableValue("^" + regexp.QuoteMeta(strings.TrimPrefix(value, "/"))), nil
+	case VariableDate:
+		if _, err := ParseGitDate(value, time.Now); err != nil {
+			return "", errors.Errorf("%q is not a date", value)
+		}
+		return quoteVariableValue(value), nil
+	case VariableNumber:
+		if n, err := strconv.Atoi(value); err != nil || n < 0 {
+			return "", errors.Errorf("%q is not a non-negative integer", value)
+		}
+		return value, nil
+	}
+	return "", errors.Errorf("unknown variable type %q", t)
+}
+
+// quoteVariableValue quotes value if it would otherwise not be parsed as a
+// single value.
+func quoteVariableValue(value string) string {
+	if strings.ContainsAny(value, " \t\n\r\"'()") {
+		return Delimit(value, '"')
+	}
+	return value
+}
+
+// isSinglePattern returns whether value is parsed as a single pattern, rather
+// than as filters or several patterns.
+func isSinglePattern(value string, searchType SearchType) bool {
+	nodes, err := Parse(value, searchType)
+	if err != nil || len(nodes) != 1 {
+		return false
+	}
+	_, ok := nodes[0].(Pattern)
+	return ok
+}
+
+var (
+	variableName      = lazyregexp.New(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	variableReference = lazyregexp.New(`\$[A-Za-z_][A-Za-z0-9_]*`)
+)
+
+// ValidateVariables checks that the variables declared for a query are valid
+// and that the query is valid whatever values they take. Every declared
+// variable must be referenced in the query.
+func ValidateVariables(in string, searchType SearchType, variables []Variable) error {
+	seen := make(map[string]struct{}, len(variables))
+	for _, v := range variables {
+		if !variableName.MatchString(v.Name) {
+			return errors.Errorf("invalid variable name %q: names must start with a letter or underscore and contain only letters, digits and underscores", v.Name)
+		}
+		if _, ok := seen[v.Name]; ok {
+			return errors.Errorf("variable $%s is declared more than once", v.Name)
+		}
+		seen[v.Name] = struct{}{}
+		if _, err := ParseVariableType(string(v.Type)); err != nil {
+			return errors.Wrapf(err, "variable $%s", v.Name)
+		}
+	}
+
+	referenced := make(map[string]struct{}, len(variables))
+	for _, ref := range variableReferences(in, seen) {
+		referenced[in[ref[0]+1:ref[1]]] = struct{}{}
+	}
+	for _, v := range variables {
+		if _, ok := referenced[v.Name]; !ok {
+			return errors.Errorf("variable $%s is declared but not used in the query", v.Name)
+		}
+	}
+
+	examples := make(map[string]string, len(variables))
+	for _, v := range variables {
+		examples[v.Name] = v.Type.exampleValue()
+	}
+	_, err := SubstituteVariables(in, searchType, variables, examples)
+	return err
+}
+
+// SubstituteVariables replaces the references to variables in the query with
+// values, which must contain a value of the right type for each variable, and
+// checks that the resulting query is valid.
+//
+// Only references to declared variables are replaced, and a reference
+// preceded by a backslash is left alone, so that `$` keeps its meaning in
+// patterns like `\$this` or `foo$`.
+func SubstituteVariables(in string, searchType SearchType, variables []Variable, values map[string]string) (string, error) {
+	declared := make(map[string]struct{}, len(variables))
+	formatted := make(map[string]string, len(variables))
+	for _, v := range variables {
+		declared[v.Name] = struct{}{}
+		value, ok := values[v.Name]
+		if !ok {
+			return "", errors.Errorf("missing value for variable $%s", v.Name)
+		}
+		f, err := v.Type.format(value, searchType)
+		if err != nil {
+			return "", errors.Wrapf(err, "invalid value for variable $%s of type %s", v.Name, v.Type)
+		}
+		formatted[v.Name] = f
+	}
+
+	var unknown []string
+	for name := range values {
+		if _, ok := declared[name]; !ok {
+			unknown = append(unknown, "$"+name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return "", errors.Errorf("unknown variables: %s", strings.Join(unknown, ", "))
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, ref := range variableReferences(in, declared) {
+		b.WriteString(in[last:ref[0]])
+		b.WriteString(formatted[in[ref[0]+1:ref[1]]])
+		last = ref[1]
+	}
+	b.WriteString(in[last:])
+	out := b.String()
+
+	if _, err := Pipeline(Init(out, searchType)); err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+// variableReferences returns the start and end offsets of the references to
+// the declared variables in the query.
+func variableReferences(in string, declared map[string]struct{}) [][]int {
+	var refs [][]int
+	for _, loc := range variableReference.Re().FindAllStringIndex(in, -1) {
+		if loc[0] > 0 && in[loc[0]-1] == '\\' {
+			continue
+		}
+		if _, ok := declared[in[loc[0]+1:loc[1]]]; !ok {
+			continue
+		}
+		refs = append(refs, loc)
+	}
+	return refs
+}
@@ -0,0 +1,180 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubstituteVariables(t *testing.T) {
+	variables := []Variable{
+		{Name: "service", Type: VariableRepo},
+		{Name: "since", Type: VariableDate},
+		{Name: "dir", Type: VariablePath},
+		{Name: "term", Type: VariableString},
+		{Name: "limit", Type: VariableNumber},
+	}
+	in := `repo:$service file:$dir type:diff after:$since count:$limit $term patterntype:literal`
+
+	cases := []struct {
+		name    string
+		values  map[string]string
+		want    string
+		wantErr string
+	}{{
+		name: "substitutes values",
+		values: map[string]string{
+			"service": "github.com/sourcegraph/sourcegraph",
+			"since":   "2 weeks ago",
+			"dir":     "/cmd/frontend",
+			"term":    `log.Error("oops")`,
+			"limit":   "10",
+		},
+		want: `repo:^github\.com/sourcegraph/sourcegraph$ file:^cmd/frontend type:diff after:"2 weeks ago" count:10 "log.Error(\"oops\")" patterntype:literal`,
+	}, {
+		name: "missing value",
+		values: map[string]string{
+			"service": "r",
+			"since":   "yesterday",
+			"dir":     "d",
+			"term":    "t",
+		},
+		wantErr: "missing value for variable $limit",
+	}, {
+		name: "unknown variable",
+		values: map[string]string{
+			"service": "r",
+			"since":   "yesterday",
+			"dir":     "d",
+			"term":    "t",
+			"limit":   "1",
+			"repo":    "r",
+		},
+		wantErr: "unknown variables: $repo",
+	}, {
+		name: "invalid date",
+		values: map[string]string{
+			"service": "r",
+			"since":   "the day after tomorrow's tomorrow",
+			"dir":     "d",
+			"term":    "t",
+			"limit":   "1",
+		},
+		wantErr: `invalid value for variable $since of type date: "the day after tomorrow's tomorrow" is not a date`,
+	}, {
+		name: "invalid number",
+		values: map[string]string{
+			"service": "r",
+			"since":   "yesterday",
+			"dir":     "d",
+			"term":    "t",
+			"limit":   "-1",
+		},
+		wantErr: `invalid value for variable $limit of type number: "-1" is not a non-negative integer`,
+	}, {
+		name: "invalid repo",
+		values: map[string]string{
+			"service": "foo bar",
+			"since":   "yesterday",
+			"dir":     "d",
+			"term":    "t",
+			"limit":   "1",
+		},
+		wantErr: `invalid value for variable $service of type repo: "foo bar" is not a repository name`,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := SubstituteVariables(in, SearchTypeStandard, variables, tc.values)
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+
+	t.Run("leaves other dollar signs alone", func(t *testing.T) {
+		got, err := SubstituteVariables(`repo:$services$ \$service $service patterntype:regexp`, SearchTypeRegex, variables[:1], map[string]string{"service": "foo"})
+		require.NoError(t, err)
+		require.Equal(t, `repo:$services$ \$service ^foo$ patterntype:regexp`, got)
+	})
+
+	t.Run("rejects string values that are not a single pattern", func(t *testing.T) {
+		for _, value := range []string{"repo:x", "count:all", "-file:foo"} {
+			_, err := SubstituteVariables(`repo:foo $term`, SearchTypeRegex, variables[3:4], map[string]string{"term": value})
+			require.EqualError(t, err, `invalid value for variable $term of type string: "`+value+`" is not a single pattern`)
+		}
+
+		got, err := SubstituteVariables(`repo:foo $term`, SearchTypeRegex, variables[3:4], map[string]string{"term": "foo or bar"})
+		require.NoError(t, err)
+		require.Equal(t, `repo:foo "foo or bar"`, got)
+	})
+
+	t.Run("validates the result", func(t *testing.T) {
+		_, err := SubstituteVariables(`repo:$service count:$limit count:1`, SearchTypeStandard, []Variable{
+			{Name: "service", Type: VariableRepo},
+			{Name: "limit", Type: VariableNumber},
+		}, map[string]string{"service": "foo", "limit": "2"})
+		require.EqualError(t, err, `field "count" may not be used more than once`)
+	})
+}
+
+func TestValidateVariables(t *testing.T) {
+	cases := []struct {
+		name      string
+		query     string
+		variables []Variable
+		wantErr   string
+	}{{
+		name:  "valid",
+		query: `repo:$service after:$since type:commit fix`,
+		variables: []Variable{
+			{Name: "service", Type: VariableRepo},
+			{Name: "since", Type: VariableDate},
+		},
+	}, {
+		name:  "no variables",
+		query: `repo:foo$ bar`,
+	}, {
+		name:      "invalid name",
+		query:     `repo:$1service`,
+		variables: []Variable{{Name: "1service", Type: VariableRepo}},
+		wantErr:   `invalid variable name "1service": names must start with a letter or underscore and contain only letters, digits and underscores`,
+	}, {
+		name:  "duplicate",
+		query: `repo:$service`,
+		variables: []Variable{
+			{Name: "service", Type: VariableRepo},
+			{Name: "service", Type: VariableString},
+		},
+		wantErr: "variable $service is declared more than once",
+	}, {
+		name:      "invalid type",
+		query:     `repo:$service`,
+		variables: []Variable{{Name: "service", Type: "repository"}},
+		wantErr:   `variable $service: invalid variable type "repository". Valid types are: string, regexp, repo, path, date, number`,
+	}, {
+		name:      "unused",
+		query:     `repo:foo`,
+		variables: []Variable{{Name: "service", Type: VariableRepo}},
+		wantErr:   "variable $service is declared but not used in the query",
+	}, {
+		name:      "invalid query",
+		query:     `repo:$service index:sometimes`,
+		variables: []Variable{{Name: "service", Type: VariableRepo}},
+		wantErr:   `invalid value "sometimes" for field "index". Valid values are: yes, only, no`,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateVariables(tc.query, SearchTypeStandard, tc.variables)
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
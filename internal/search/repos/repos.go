@@ -25,9 +25,12 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/endpoint"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
 	"github.com/sourcegraph/sourcegraph/internal/grpc/defaults"
+	"github.com/sourcegraph/sourcegraph/internal/perforce"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/limits"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
@@ -483,11 +486,17 @@ func (r *Resolver) normalizeRefs(ctx context.Context, repoRevSpecs []RepoRevSpec
 		}
 	)
 
+	perforceRepos, err := r.perforceRepos(ctx, repoRevSpecs)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	p := pool.New().WithContext(ctx).WithMaxGoroutines(128)
 	for i, repoRev := range repoRevSpecs {
 		i, repoRev := i, repoRev
+		_, isPerforce := perforceRepos[repoRev.Repo.ID]
 		p.Go(func(ctx context.Context) error {
-			expanded, err := r.normalizeRepoRefs(ctx, repoRev.Repo, repoRev.Revs, addMissing)
+			expanded, err := r.normalizeRepoRefs(ctx, repoRev.Repo, isPerforce, repoRev.Revs, addMissing)
 			if err != nil {
 				return err
 			}
@@ -514,9 +523,39 @@ func (r *Resolver) normalizeRefs(ctx context.Context, repoRevSpecs []RepoRevSpec
 	return filteredResults, missing, nil
 }
 
+// perforceRepos returns the set of repositories that are synced from Perforce
+// among those that have a revision which looks like a Perforce changelist.
+func (r *Resolver) perforceRepos(ctx context.Context, repoRevSpecs []RepoRevSpecs) (map[api.RepoID]struct{}, error) {
+	var ids []api.RepoID
+	for _, repoRev := range repoRevSpecs {
+		for _, rev := range repoRev.Revs {
+			if _, ok := perforce.ParseChangelistRevSpec(rev.RevSpec); ok {
+				ids = append(ids, repoRev.Repo.ID)
+				break
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	repos, err := r.db.Repos().List(ctx, database.ReposListOptions{IDs: ids})
+	if err != nil {
+		return nil, err
+	}
+	perforceRepos := make(map[api.RepoID]struct{}, len(repos))
+	for _, repo := range repos {
+		if repo.ExternalRepo.ServiceType == extsvc.TypePerforce {
+			perforceRepos[repo.ID] = struct{}{}
+		}
+	}
+	return perforceRepos, nil
+}
+
 func (r *Resolver) normalizeRepoRefs(
 	ctx context.Context,
 	repo types.MinimalRepo,
+	isPerforce bool,
 	revSpecs []query.RevisionSpecifier,
 	reportMissing func(RepoRevSpecs),
 ) ([]string, error) {
@@ -535,6 +574,21 @@ func (r *Resolver) normalizeRepoRefs(
 			// so we could avoid resolving later.
 			revs = append(revs, rev.RevSpec)
 		case rev.RevSpec != "":
+			// Perforce changelists are not known to git, so we resolve them to
+			// the commit the changelist was converted to.
+			if cid, ok := perforce.ParseChangelistRevSpec(rev.RevSpec); ok && isPerforce {
+				repoCommit, err := r.db.RepoCommitsChangelists().GetRepoCommitChangelist(ctx, repo.ID, cid)
+				if err != nil {
+					if errcode.IsNotFound(err) {
+						reportMissing(RepoRevSpecs{Repo: repo, Revs: []query.RevisionSpecifier{rev}})
+						continue
+					}
+					return nil, err
+				}
+				revs = append(revs, string(repoCommit.CommitSHA))
+				continue
+			}
+
 			trimmedRev := strings.TrimPrefix(rev.RevSpec, "^")
 			_, err := r.gitserver.ResolveRevision(ctx, repo.Name, trimmedRev, gitserver.ResolveRevisionOptions{NoEnsureRevision: true})
 			if err != nil {
@@ -24,9 +24,11 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/database/dbmocks"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
 	"github.com/sourcegraph/sourcegraph/internal/endpoint"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/gitdomain"
 	"github.com/sourcegraph/sourcegraph/internal/grpc/defaults"
+	"github.com/sourcegraph/sourcegraph/internal/perforce"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	"github.com/sourcegraph/sourcegraph/internal/search/searcher"
@@ -65,8 +67,9 @@ func TestRevisionValidation(t *testing.T) {
 
 		// known revisions
 		m := map[string]struct{}{
-			"revBar": {},
-			"revBas": {},
+			"revBar":         {},
+			"revBas":         {},
+			"changelist/123": {},
 		}
 		if _, ok := m[spec]; ok {
 			return "", nil
@@ -83,6 +86,7 @@ func TestRevisionValidation(t *testing.T) {
 
 	tests := []struct {
 		repoFilters  []string
+		serviceType  string
 		wantRepoRevs []*search.RepositoryRevisions
 		wantErr      error
 	}{
@@ -138,14 +142,57 @@ func TestRevisionValidation(t *testing.T) {
 			}},
 			wantErr: nil,
 		},
+		{
+			repoFilters: []string{"repoFoo@changelist/123"},
+			serviceType: extsvc.TypePerforce,
+			wantRepoRevs: []*search.RepositoryRevisions{{
+				Repo: types.MinimalRepo{Name: "repoFoo"},
+				Revs: []string{"98d3ec26623660f17f6c298943f55aa339aa894a"},
+			}},
+		},
+		{
+			// A git branch that looks like a changelist is resolved by git.
+			repoFilters: []string{"repoFoo@changelist/123"},
+			serviceType: extsvc.TypeGitHub,
+			wantRepoRevs: []*search.RepositoryRevisions{{
+				Repo: types.MinimalRepo{Name: "repoFoo"},
+				Revs: []string{"changelist/123"},
+			}},
+		},
+		{
+			repoFilters: []string{"repoFoo@revBar:changelist/999"},
+			serviceType: extsvc.TypePerforce,
+			wantRepoRevs: []*search.RepositoryRevisions{{
+				Repo: types.MinimalRepo{Name: "repoFoo"},
+				Revs: []string{"revBar"},
+			}},
+			wantErr: &MissingRepoRevsError{
+				Missing: []RepoRevSpecs{{
+					Repo: types.MinimalRepo{Name: "repoFoo"},
+					Revs: []query.RevisionSpecifier{{
+						RevSpec: "changelist/999",
+					}},
+				}},
+			},
+		},
 	}
 
+	changelists := dbmocks.NewMockRepoCommitsChangelistsStore()
+	changelists.GetRepoCommitChangelistFunc.SetDefaultHook(func(_ context.Context, repoID api.RepoID, cid int64) (*types.RepoCommit, error) {
+		if cid == 123 {
+			return &types.RepoCommit{RepoID: repoID, CommitSHA: "98d3ec26623660f17f6c298943f55aa339aa894a", PerforceChangelistID: cid}, nil
+		}
+		return nil, &perforce.ChangelistNotFoundError{RepoID: repoID, ID: cid}
+	})
+
 	for _, tt := range tests {
 		t.Run(tt.repoFilters[0], func(t *testing.T) {
 			repos := dbmocks.NewMockRepoStore()
 			repos.ListMinimalReposFunc.SetDefaultReturn([]types.MinimalRepo{{Name: "repoFoo"}}, nil)
+			repos.ListFunc.SetDefaultReturn([]*types.Repo{{Name: "repoFoo", ExternalRepo: api.ExternalRepoSpec{ServiceType: tt.serviceType}}}, nil)
 			db := dbmocks.NewMockDB()
 			db.ReposFunc.SetDefaultReturn(repos)
+			db.RepoCommitsChangelistsFunc.SetDefaultReturn(changelists)
 
 			op := search.RepoOptions{RepoFilters: toParsedRepoFilters(tt.repoFilters...)}
 			repositoryResolver := NewResolver(logtest.Scoped(t), db, nil, nil, defaults.NewConnectionCache(logtest.Scoped(t)), nil)
@@ -133,6 +133,14 @@ func (cm *CommitMatch) Select(path filter.SelectPath) Match {
 			}
 			return nil
 		}
+		if len(fields) == 1 && fields[0] == "author" {
+			return &CommitAuthorMatch{
+				Name:  cm.Commit.Author.Name,
+				Email: cm.Commit.Author.Email,
+				Count: 1,
+				Repo:  cm.Repo,
+			}
+		}
 		return cm
 	}
 	return nil
@@ -0,0 +1,56 @@
+package result
+
+import (
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// CommitAuthorMatch is an author of matching commits, as selected by
+// select:commit.author. Authors are identified by their case-insensitive email
+// address, are distinct across repositories, and are merged by the Deduper.
+type CommitAuthorMatch struct {
+	Name  string
+	Email string
+
+	// Count is the number of matching commits by the author.
+	Count int
+
+	// Repo is the repository of the first commit the author was selected from.
+	// It is used to check that the match is visible to the searcher.
+	Repo types.MinimalRepo `json:"-"`
+}
+
+func (am *CommitAuthorMatch) RepoName() types.MinimalRepo {
+	return am.Repo
+}
+
+func (am *CommitAuthorMatch) ResultCount() int {
+	return 1
+}
+
+func (am *CommitAuthorMatch) Select(path filter.SelectPath) Match {
+	if path.Root() == filter.Commit && len(path) == 2 && path[1] == "author" {
+		return am
+	}
+	return nil
+}
+
+func (am *CommitAuthorMatch) Limit(limit int) int {
+	return limit - 1
+}
+
+// AppendMatches merges the commit count of src into am.
+func (am *CommitAuthorMatch) AppendMatches(src *CommitAuthorMatch) {
+	am.Count += src.Count
+}
+
+func (am *CommitAuthorMatch) Key() Key {
+	return Key{
+		TypeRank:    rankCommitAuthorMatch,
+		SelectValue: strings.ToLower(am.Email),
+	}
+}
+
+func (am *CommitAuthorMatch) searchResultMarker() {}
@@ -0,0 +1,52 @@
+package result
+
+import (
+	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// ContributorMatch is a top committer of a matching repository, as selected by
+// select:repo.contributors.
+type ContributorMatch struct {
+	Repo types.MinimalRepo
+
+	// Rev is the revision the commits were counted on. Empty means the default
+	// branch.
+	Rev string
+
+	Name  string
+	Email string
+
+	// Count is the number of commits by the contributor.
+	Count int
+}
+
+func (cm *ContributorMatch) RepoName() types.MinimalRepo {
+	return cm.Repo
+}
+
+func (cm *ContributorMatch) ResultCount() int {
+	return 1
+}
+
+func (cm *ContributorMatch) Select(path filter.SelectPath) Match {
+	if path.Root() == filter.Repository && len(path) == 2 && path[1] == "contributors" {
+		return cm
+	}
+	return nil
+}
+
+func (cm *ContributorMatch) Limit(limit int) int {
+	return limit - 1
+}
+
+func (cm *ContributorMatch) Key() Key {
+	return Key{
+		TypeRank:    rankContributorMatch,
+		Repo:        cm.Repo.Name,
+		Rev:         cm.Rev,
+		SelectValue: cm.Email,
+	}
+}
+
+func (cm *ContributorMatch) searchResultMarker() {}
@@ -23,6 +23,10 @@ func (d *Deduper) Add(m Match) {
 			prevMatch.AppendMatches(m.(*FileMatch))
 		case *CommitMatch:
 			prevMatch.AppendMatches(m.(*CommitMatch))
+		case *LanguageMatch:
+			prevMatch.AppendMatches(m.(*LanguageMatch))
+		case *CommitAuthorMatch:
+			prevMatch.AppendMatches(m.(*CommitAuthorMatch))
 		}
 		return
 	}
@@ -101,6 +101,30 @@ func TestDeduper(t *testing.T) {
 				diff("a", "b"),
 			},
 		},
+		{
+			name: "merge languages",
+			input: []Match{
+				&LanguageMatch{Language: "Go", Count: 1, Repo: types.MinimalRepo{Name: "a"}},
+				&LanguageMatch{Language: "Python", Count: 1, Repo: types.MinimalRepo{Name: "a"}},
+				&LanguageMatch{Language: "Go", Count: 2, Repo: types.MinimalRepo{Name: "b"}},
+			},
+			expected: []Match{
+				&LanguageMatch{Language: "Go", Count: 3, Repo: types.MinimalRepo{Name: "a"}},
+				&LanguageMatch{Language: "Python", Count: 1, Repo: types.MinimalRepo{Name: "a"}},
+			},
+		},
+		{
+			name: "merge commit authors by email",
+			input: []Match{
+				&CommitAuthorMatch{Name: "Alice", Email: "alice@example.com", Count: 1},
+				&CommitAuthorMatch{Name: "alice", Email: "Alice@example.com", Count: 1},
+				&CommitAuthorMatch{Name: "Bob", Email: "bob@example.com", Count: 1},
+			},
+			expected: []Match{
+				&CommitAuthorMatch{Name: "Alice", Email: "alice@example.com", Count: 2},
+				&CommitAuthorMatch{Name: "Bob", Email: "bob@example.com", Count: 1},
+			},
+		},
 		{
 			name: "different revs not deduped",
 			input: []Match{
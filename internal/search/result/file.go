@@ -140,6 +140,17 @@ func (fm *FileMatch) Select(selectPath filter.SelectPath) Match {
 		if len(selectPath) > 1 && selectPath[1] == "directory" {
 			fm.Path = path.Clean(path.Dir(fm.Path)) + "/" // Add trailing slash for clarity.
 		}
+		if len(selectPath) > 1 && selectPath[1] == "language" {
+			lang := fm.MostLikelyLanguage()
+			if lang == "" {
+				return nil // Remove file match if its language is unknown
+			}
+			return &LanguageMatch{
+				Language: lang,
+				Count:    1,
+				Repo:     fm.Repo,
+			}
+		}
 		return fm
 	case filter.Symbol:
 		if len(fm.Symbols) > 0 {
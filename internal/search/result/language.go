@@ -0,0 +1,52 @@
+package result
+
+import (
+	"github.com/sourcegraph/sourcegraph/internal/search/filter"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+// LanguageMatch is a language of matching files, as selected by select:file.language.
+// Language matches are distinct across repositories, and are merged by the Deduper.
+type LanguageMatch struct {
+	Language string
+
+	// Count is the number of matching files in the language.
+	Count int
+
+	// Repo is the repository of the first file the language was selected from.
+	// It is used to check that the match is visible to the searcher.
+	Repo types.MinimalRepo `json:"-"`
+}
+
+func (lm *LanguageMatch) RepoName() types.MinimalRepo {
+	return lm.Repo
+}
+
+func (lm *LanguageMatch) ResultCount() int {
+	return 1
+}
+
+func (lm *LanguageMatch) Select(path filter.SelectPath) Match {
+	if path.Root() == filter.File && len(path) == 2 && path[1] == "language" {
+		return lm
+	}
+	return nil
+}
+
+func (lm *LanguageMatch) Limit(limit int) int {
+	return limit - 1
+}
+
+// AppendMatches merges the file count of src into lm.
+func (lm *LanguageMatch) AppendMatches(src *LanguageMatch) {
+	lm.Count += src.Count
+}
+
+func (lm *LanguageMatch) Key() Key {
+	return Key{
+		TypeRank:    rankLanguageMatch,
+		SelectValue: lm.Language,
+	}
+}
+
+func (lm *LanguageMatch) searchResultMarker() {}
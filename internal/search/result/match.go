@@ -35,6 +35,9 @@ var (
 	_ Match = (*CommitMatch)(nil)
 	_ Match = (*CommitDiffMatch)(nil)
 	_ Match = (*OwnerMatch)(nil)
+	_ Match = (*LanguageMatch)(nil)
+	_ Match = (*CommitAuthorMatch)(nil)
+	_ Match = (*ContributorMatch)(nil)
 )
 
 // Match ranks are used for sorting the different match types.
@@ -46,6 +49,10 @@ const (
 	rankDiffMatch   = 2
 	rankRepoMatch   = 3
 	rankOwnerMatch  = 4
+
+	rankLanguageMatch     = 5
+	rankCommitAuthorMatch = 6
+	rankContributorMatch  = 7
 )
 
 // Key is a sorting or deduplicating key for a Match. It contains all the
@@ -80,6 +87,10 @@ type Key struct {
 	// Empty if this is not a Key for an OwnerMatch.
 	OwnerMetadata string
 
+	// SelectValue is the value selected by a match that aggregates other
+	// matches, such as the language of a LanguageMatch. Empty for other matches.
+	SelectValue string
+
 	// TypeRank is the sorting rank of the type this key belongs to.
 	TypeRank int
 }
@@ -110,6 +121,10 @@ func (k Key) Less(other Key) bool {
 		return k.OwnerMetadata < other.OwnerMetadata
 	}
 
+	if k.SelectValue != other.SelectValue {
+		return k.SelectValue < other.SelectValue
+	}
+
 	return k.TypeRank < other.TypeRank
 }
 
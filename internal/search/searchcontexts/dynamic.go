@@ -0,0 +1,131 @@
+package searchcontexts
+
+import (
+	"context"
+	"sort"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/search/query"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// OwnedReposLister lists the repositories in which an owner owns files. It is
+// implemented by own.Service.
+type OwnedReposLister interface {
+	OwnedRepos(ctx context.Context, owner string) ([]api.RepoID, error)
+}
+
+// DependencyReposLister lists the repositories a repository transitively
+// depends on. It is implemented by the codeintel dependencies service.
+type DependencyReposLister interface {
+	ListTransitiveDependencyRepoIDs(ctx context.Context, repositoryID int) ([]int, error)
+}
+
+// validateSearchContextDynamic validates the dynamic definition of a search
+// context, if it has one. Dynamic search contexts can have neither a query
+// nor repository revisions, since their repositories are computed.
+func validateSearchContextDynamic(ctx context.Context, db database.DB, searchContext *types.SearchContext, repositoryRevisions []*types.SearchContextRepositoryRevisions) error {
+	dynamic := searchContext.Dynamic
+	if dynamic == nil {
+		return nil
+	}
+
+	if searchContext.Query != "" || len(repositoryRevisions) > 0 {
+		return errors.New("dynamic search contexts cannot have a query or repository revisions")
+	}
+
+	if dynamic.Value == "" {
+		return errors.Errorf("dynamic search context %s cannot be empty", dynamic.Source)
+	}
+
+	switch dynamic.Source {
+	case types.SearchContextDynamicSourceOwner:
+		return nil
+	case types.SearchContextDynamicSourceMeta:
+		_, err := parseDynamicMetaValue(dynamic.Value)
+		return err
+	case types.SearchContextDynamicSourceDependencies:
+		// Resolving the repository as the current user also ensures that they can
+		// only define contexts for the dependencies of repositories they can see.
+		_, err := db.Repos().GetByName(ctx, api.RepoName(dynamic.Value))
+		return err
+	default:
+		return errors.Errorf("unknown dynamic search context source %q", dynamic.Source)
+	}
+}
+
+// parseDynamicMetaValue parses the value of a dynamic search context defined by
+// repository metadata. It accepts the same "key" and "key:value" syntax as the
+// has.meta() repo predicate.
+func parseDynamicMetaValue(value string) (database.RepoKVPFilter, error) {
+	var p query.RepoHasMetaPredicate
+	if err := p.Unmarshal(value, false); err != nil {
+		return database.RepoKVPFilter{}, errors.Wrapf(err, "invalid repository metadata %q", value)
+	}
+	return database.RepoKVPFilter{Key: p.Key, Value: p.Value, KeyOnly: p.KeyOnly}, nil
+}
+
+// EvaluateDynamicSearchContext computes the repositories of a dynamic search
+// context, sorted by ID.
+//
+// 🚨 SECURITY: The repositories are not filtered by the permissions of any
+// user, so the caller must be an internal actor. Permissions are enforced when
+// the repositories of a search context are read.
+func EvaluateDynamicSearchContext(
+	ctx context.Context,
+	db database.DB,
+	ownedRepos OwnedReposLister,
+	dependencyRepos DependencyReposLister,
+	searchContext *types.SearchContext,
+) ([]api.RepoID, error) {
+	dynamic := searchContext.Dynamic
+	if dynamic == nil {
+		return nil, errors.Errorf("search context %d is not dynamic", searchContext.ID)
+	}
+
+	var repoIDs []api.RepoID
+	switch dynamic.Source {
+	case types.SearchContextDynamicSourceOwner:
+		ids, err := ownedRepos.OwnedRepos(ctx, dynamic.Value)
+		if err != nil {
+			return nil, err
+		}
+		repoIDs = ids
+
+	case types.SearchContextDynamicSourceMeta:
+		filter, err := parseDynamicMetaValue(dynamic.Value)
+		if err != nil {
+			return nil, err
+		}
+		repos, err := db.Repos().ListMinimalRepos(ctx, database.ReposListOptions{KVPFilters: []database.RepoKVPFilter{filter}})
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range repos {
+			repoIDs = append(repoIDs, repo.ID)
+		}
+
+	case types.SearchContextDynamicSourceDependencies:
+		repo, err := db.Repos().GetByName(ctx, api.RepoName(dynamic.Value))
+		if err != nil {
+			return nil, err
+		}
+		ids, err := dependencyRepos.ListTransitiveDependencyRepoIDs(ctx, int(repo.ID))
+		if err != nil {
+			return nil, err
+		}
+		// The context contains the repository itself and everything it depends on.
+		repoIDs = append(repoIDs, repo.ID)
+		for _, id := range ids {
+			repoIDs = append(repoIDs, api.RepoID(id))
+		}
+
+	default:
+		return nil, errors.Errorf("unknown dynamic search context source %q", dynamic.Source)
+	}
+
+	sort.Slice(repoIDs, func(i, j int) bool { return repoIDs[i] < repoIDs[j] })
+	return repoIDs, nil
+}
@@ -0,0 +1,81 @@
+package searchcontexts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/database/dbmocks"
+	"github.com/sourcegraph/sourcegraph/internal/types"
+)
+
+type fakeOwnedRepos map[string][]api.RepoID
+
+func (f fakeOwnedRepos) OwnedRepos(_ context.Context, owner string) ([]api.RepoID, error) {
+	return f[owner], nil
+}
+
+type fakeDependencyRepos map[int][]int
+
+func (f fakeDependencyRepos) ListTransitiveDependencyRepoIDs(_ context.Context, repositoryID int) ([]int, error) {
+	return f[repositoryID], nil
+}
+
+func TestEvaluateDynamicSearchContext(t *testing.T) {
+	repos := dbmocks.NewMockRepoStore()
+	repos.GetByNameFunc.SetDefaultHook(func(_ context.Context, name api.RepoName) (*types.Repo, error) {
+		if name != "github.com/example/service" {
+			return nil, &database.RepoNotFoundErr{Name: name}
+		}
+		return &types.Repo{ID: 5, Name: name}, nil
+	})
+	repos.ListMinimalReposFunc.SetDefaultHook(func(_ context.Context, opts database.ReposListOptions) ([]types.MinimalRepo, error) {
+		require.Len(t, opts.KVPFilters, 1)
+		if f := opts.KVPFilters[0]; f.Key != "team" || f.Value == nil || *f.Value != "search" {
+			return nil, nil
+		}
+		return []types.MinimalRepo{{ID: 9}, {ID: 3}}, nil
+	})
+	db := dbmocks.NewMockDB()
+	db.ReposFunc.SetDefaultReturn(repos)
+
+	ownedRepos := fakeOwnedRepos{"@search": {4, 2}}
+	dependencyRepos := fakeDependencyRepos{5: {7, 1}}
+
+	evaluate := func(source types.SearchContextDynamicSource, value string) ([]api.RepoID, error) {
+		return EvaluateDynamicSearchContext(context.Background(), db, ownedRepos, dependencyRepos, &types.SearchContext{
+			Dynamic: &types.SearchContextDynamic{Source: source, Value: value},
+		})
+	}
+
+	t.Run("owner", func(t *testing.T) {
+		got, err := evaluate(types.SearchContextDynamicSourceOwner, "@search")
+		require.NoError(t, err)
+		require.Equal(t, []api.RepoID{2, 4}, got)
+	})
+
+	t.Run("meta", func(t *testing.T) {
+		got, err := evaluate(types.SearchContextDynamicSourceMeta, "team:search")
+		require.NoError(t, err)
+		require.Equal(t, []api.RepoID{3, 9}, got)
+	})
+
+	t.Run("dependencies include the repository", func(t *testing.T) {
+		got, err := evaluate(types.SearchContextDynamicSourceDependencies, "github.com/example/service")
+		require.NoError(t, err)
+		require.Equal(t, []api.RepoID{1, 5, 7}, got)
+	})
+
+	t.Run("dependencies of unknown repository", func(t *testing.T) {
+		_, err := evaluate(types.SearchContextDynamicSourceDependencies, "github.com/example/unknown")
+		require.EqualError(t, err, `repo not found: name="github.com/example/unknown"`)
+	})
+
+	t.Run("not dynamic", func(t *testing.T) {
+		_, err := EvaluateDynamicSearchContext(context.Background(), db, ownedRepos, dependencyRepos, &types.SearchContext{ID: 1})
+		require.EqualError(t, err, "search context 1 is not dynamic")
+	})
+}
@@ -304,6 +304,11 @@ func CreateSearchContextWithRepositoryRevisions(
 		return nil, err
 	}
 
+	err = validateSearchContextDynamic(ctx, db, searchContext, repositoryRevisions)
+	if err != nil {
+		return nil, err
+	}
+
 	err = validateSearchContextDoesNotExist(ctx, db, searchContext)
 	if err != nil {
 		return nil, err
@@ -350,6 +355,11 @@ func UpdateSearchContextWithRepositoryRevisions(ctx context.Context, db database
 		return nil, err
 	}
 
+	err = validateSearchContextDynamic(ctx, db, searchContext, repositoryRevisions)
+	if err != nil {
+		return nil, err
+	}
+
 	searchContext, err = db.SearchContexts().UpdateSearchContextWithRepositoryRevisions(ctx, searchContext, repositoryRevisions)
 	if err != nil {
 		return nil, err
@@ -441,6 +451,46 @@ func RepoRevs(ctx context.Context, db database.DB, repoIDs []api.RepoID) (map[ap
 	return revs, nil
 }
 
+// RepoSearchContexts returns, for the given repo IDs, which of the search
+// contexts identified by specs they are listed in. Repositories matched by a
+// search context query are not taken into account.
+func RepoSearchContexts(ctx context.Context, db database.DB, specs []string, repoIDs []api.RepoID) (map[api.RepoID][]string, error) {
+	if a := actor.FromContext(ctx); !a.IsInternal() {
+		return nil, errors.New("searchcontexts.RepoSearchContexts can only be accessed by an internal actor")
+	}
+
+	contexts := make(map[api.RepoID][]string, len(repoIDs))
+	for _, spec := range specs {
+		searchContext, err := ResolveSearchContextSpec(ctx, db, spec)
+		if err != nil {
+			// A renamed or deleted search context matches no repositories. Only fail
+			// on other errors, so that a stale spec doesn't fail all repositories.
+			if errors.IsAny(err, database.ErrSearchContextNotFound, database.ErrNamespaceNotFound) {
+				log15.Warn("RepoSearchContexts: search context not found", "spec", spec, "error", err)
+				continue
+			}
+			return nil, errors.Wrapf(err, "resolve search context %q", spec)
+		}
+		// Auto-defined search contexts do not list their repositories.
+		if IsAutoDefinedSearchContext(searchContext) {
+			continue
+		}
+
+		repos, err := db.Repos().ListMinimalRepos(ctx, database.ReposListOptions{
+			IDs:             repoIDs,
+			SearchContextID: searchContext.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range repos {
+			contexts[repo.ID] = append(contexts[repo.ID], spec)
+		}
+	}
+
+	return contexts, nil
+}
+
 // RepoOpts contains the database.ReposListOptions and RevSpecs parsed from
 // a search context query.
 type RepoOpts struct {
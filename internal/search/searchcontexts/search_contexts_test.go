@@ -18,6 +18,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/database"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbmocks"
 	"github.com/sourcegraph/sourcegraph/internal/database/dbtest"
@@ -467,6 +468,29 @@ func TestCreatingSearchContexts(t *testing.T) {
 			userID:        user1.ID,
 			wantErr:       fmt.Sprintf("unsupported rev glob in search context query: %q", "foo/bar@*!refs/tags/*"),
 		},
+		{
+			name:          "can create dynamic search context",
+			searchContext: &types.SearchContext{Name: "dynamic_deps", Dynamic: &types.SearchContextDynamic{Source: types.SearchContextDynamicSourceDependencies, Value: string(repos[0].Name)}},
+			userID:        user1.ID,
+		},
+		{
+			name:          "cannot create dynamic search context with query",
+			searchContext: &types.SearchContext{Name: "dynamic_query", Query: "repo:foo", Dynamic: &types.SearchContextDynamic{Source: types.SearchContextDynamicSourceOwner, Value: "@team"}},
+			userID:        user1.ID,
+			wantErr:       "dynamic search contexts cannot have a query or repository revisions",
+		},
+		{
+			name:          "cannot create dynamic search context with invalid metadata",
+			searchContext: &types.SearchContext{Name: "dynamic_meta", Dynamic: &types.SearchContextDynamic{Source: types.SearchContextDynamicSourceMeta, Value: ":value"}},
+			userID:        user1.ID,
+			wantErr:       `invalid repository metadata ":value"`,
+		},
+		{
+			name:          "cannot create dynamic search context for unknown repository",
+			searchContext: &types.SearchContext{Name: "dynamic_unknown", Dynamic: &types.SearchContextDynamic{Source: types.SearchContextDynamicSourceDependencies, Value: "github.com/example/unknown"}},
+			userID:        user1.ID,
+			wantErr:       "repo not found",
+		},
 	}
 
 	for _, tt := range tests {
@@ -602,6 +626,63 @@ func TestDeletingAutoDefinedSearchContext(t *testing.T) {
 	}
 }
 
+func TestRepoSearchContexts(t *testing.T) {
+	ns := dbmocks.NewMockNamespaceStore()
+	ns.GetByNameFunc.SetDefaultReturn(&database.Namespace{Name: "org", Organization: 1}, nil)
+
+	sc := dbmocks.NewMockSearchContextsStore()
+	sc.GetSearchContextFunc.SetDefaultHook(func(_ context.Context, opts database.GetSearchContextOptions) (*types.SearchContext, error) {
+		switch opts.Name {
+		case "deleted":
+			return nil, database.ErrSearchContextNotFound
+		case "broken":
+			return nil, errors.New("connection refused")
+		}
+		ids := map[string]int64{"protos": 1, "data": 2}
+		return &types.SearchContext{ID: ids[opts.Name], Name: opts.Name, NamespaceOrgID: opts.NamespaceOrgID}, nil
+	})
+
+	repos := dbmocks.NewMockRepoStore()
+	repos.ListMinimalReposFunc.SetDefaultHook(func(_ context.Context, opts database.ReposListOptions) ([]types.MinimalRepo, error) {
+		require.Equal(t, []api.RepoID{1, 2, 3}, opts.IDs)
+		listed := map[int64][]types.MinimalRepo{
+			1: {{ID: 1}, {ID: 2}},
+			2: {{ID: 2}},
+		}
+		return listed[opts.SearchContextID], nil
+	})
+
+	db := dbmocks.NewMockDB()
+	db.NamespacesFunc.SetDefaultReturn(ns)
+	db.SearchContextsFunc.SetDefaultReturn(sc)
+	db.ReposFunc.SetDefaultReturn(repos)
+
+	specs := []string{"@org/protos", "@org/data", "@org/deleted", "global"}
+
+	t.Run("internal actor", func(t *testing.T) {
+		ctx := actor.WithInternalActor(context.Background())
+		got, err := RepoSearchContexts(ctx, db, specs, []api.RepoID{1, 2, 3})
+		require.NoError(t, err)
+		require.Equal(t, map[api.RepoID][]string{
+			1: {"@org/protos"},
+			2: {"@org/protos", "@org/data"},
+		}, got)
+		mockrequire.CalledN(t, repos.ListMinimalReposFunc, 2)
+	})
+
+	t.Run("transient error", func(t *testing.T) {
+		ctx := actor.WithInternalActor(context.Background())
+		_, err := RepoSearchContexts(ctx, db, []string{"@org/protos", "@org/broken"}, []api.RepoID{1, 2, 3})
+		require.Error(t, err)
+	})
+
+	t.Run("user", func(t *testing.T) {
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		_, err := RepoSearchContexts(ctx, db, specs, []api.RepoID{1, 2, 3})
+		require.EqualError(t, err, "searchcontexts.RepoSearchContexts can only be accessed by an internal actor")
+	})
+}
+
 func TestParseRepoOpts(t *testing.T) {
 	for _, tc := range []struct {
 		in  string
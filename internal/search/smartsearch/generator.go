@@ -5,6 +5,7 @@ import (
 
 	"gonum.org/v1/gonum/stat/combin"
 
+	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
 )
 
@@ -82,6 +83,7 @@ func NewGenerator(seed query.Basic, narrow, widen []rule) next {
 	n = func(phase PHASE, k int, c *cg, w int) next {
 		var transform []transform
 		var descriptions []string
+		var applied []rule
 		var generated *query.Basic
 
 		narrowing_exhausted := k == 0
@@ -99,6 +101,7 @@ func NewGenerator(seed query.Basic, narrow, widen []rule) next {
 
 			transform = append(transform, widen[w].transform...)
 			descriptions = append(descriptions, widen[w].description)
+			applied = append(applied, widen[w])
 			w += 1 // advance to next widening rule.
 
 		case TWO:
@@ -127,11 +130,13 @@ func NewGenerator(seed query.Basic, narrow, widen []rule) next {
 			for _, idx := range c.Combination(nil) {
 				transform = append(transform, narrow[idx].transform...)
 				descriptions = append(descriptions, narrow[idx].description)
+				applied = append(applied, narrow[idx])
 			}
 
 			// Compose narrow rules with a widen rule.
 			transform = append(transform, widen[w].transform...)
 			descriptions = append(descriptions, widen[w].description)
+			applied = append(applied, widen[w])
 
 		case ONE:
 			if narrowing_exhausted && !widening_active {
@@ -154,6 +159,7 @@ func NewGenerator(seed query.Basic, narrow, widen []rule) next {
 			for _, idx := range c.Combination(nil) {
 				transform = append(transform, narrow[idx].transform...)
 				descriptions = append(descriptions, narrow[idx].description)
+				applied = append(applied, narrow[idx])
 			}
 		}
 
@@ -169,6 +175,7 @@ func NewGenerator(seed query.Basic, narrow, widen []rule) next {
 		q := autoQuery{
 			description: strings.Join(descriptions, " ⚬ "),
 			query:       *generated,
+			annotations: annotate(seed, applied),
 		}
 
 		return func() (*autoQuery, next) {
@@ -206,6 +213,23 @@ func pruneRules(seed query.Basic, rules []rule) []rule {
 	return applies
 }
 
+// annotate collects the annotations of the rules applied to seed.
+func annotate(seed query.Basic, rules []rule) map[search.AnnotationName]string {
+	var annotations map[search.AnnotationName]string
+	for _, r := range rules {
+		if r.annotate == nil {
+			continue
+		}
+		for name, value := range r.annotate(seed) {
+			if annotations == nil {
+				annotations = make(map[search.AnnotationName]string)
+			}
+			annotations[name] = value
+		}
+	}
+	return annotations
+}
+
 // applyTransformation applies a transformation on `b`. If any function does not apply, it returns nil.
 func applyTransformation(b query.Basic, transform []transform) *query.Basic {
 	for _, apply := range transform {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/hexops/autogold/v2"
 
+	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
 )
 
@@ -13,6 +14,7 @@ type want struct {
 	Description string
 	Input       string
 	Query       string
+	Annotations map[search.AnnotationName]string `json:",omitempty"`
 }
 
 func TestNewGenerator(t *testing.T) {
@@ -37,6 +39,30 @@ func TestNewGenerator(t *testing.T) {
 	}
 }
 
+func TestPastedInput(t *testing.T) {
+	test := func(input string) string {
+		q, _ := query.ParseStandard(input)
+		b, _ := query.ToBasicQuery(q)
+		g := NewGenerator(b, rulesNarrow, rulesWiden)
+		result, _ := json.MarshalIndent(generateAll(g, input), "", "  ")
+		return string(result)
+	}
+
+	cases := []string{
+		`NewSearchJob`,
+		`http.Client`,
+		`pkg/foo/bar.go`,
+		`File "/app/foo/bar.py", line 42, in main`,
+		`panic: runtime error /home/me/src/acme/api/server.go:128 +0x1d`,
+	}
+
+	for _, c := range cases {
+		t.Run("pasted input", func(t *testing.T) {
+			autogold.ExpectFile(t, autogold.Raw(test(c)))
+		})
+	}
+}
+
 func TestSkippedRules(t *testing.T) {
 	test := func(input string) string {
 		q, _ := query.ParseStandard(input)
@@ -81,6 +107,7 @@ func generateAll(g next, input string) []want {
 				Description: autoQ.description,
 				Input:       input,
 				Query:       query.StringHuman(autoQ.query.ToParseTree()),
+				Annotations: autoQ.annotations,
 			})
 	}
 	return generated
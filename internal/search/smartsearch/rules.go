@@ -8,6 +8,8 @@ import (
 
 	"github.com/go-enry/go-enry/v2"
 	"github.com/grafana/regexp"
+
+	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
 )
 
@@ -18,6 +20,10 @@ import (
 type rule struct {
 	description string
 	transform   []transform
+
+	// annotate optionally returns annotations for the queries this rule
+	// generates, like the line to jump to. It is called with the seed query.
+	annotate func(query.Basic) map[search.AnnotationName]string
 }
 
 type transform func(query.Basic) *query.Basic
@@ -47,6 +53,19 @@ var rulesNarrow = []rule{
 		description: "rewrite repo URLs",
 		transform:   []transform{rewriteRepoFilter},
 	},
+	{
+		description: "search for identifier as symbol",
+		transform:   []transform{identifierPatterns},
+	},
+	{
+		description: "search for pattern as file path",
+		transform:   []transform{pathPatterns},
+	},
+	{
+		description: "jump to file and line of stack trace",
+		transform:   []transform{stackTracePatterns},
+		annotate:    stackTraceLine,
+	},
 }
 
 var rulesWiden = []rule{
@@ -706,3 +725,205 @@ func patternsToCodeHostFilters(b query.Basic) *query.Basic {
 
 	return &newBasic
 }
+
+// singlePattern returns the pattern of b if it is a single literal pattern,
+// which is how pasted input like an identifier or a stack trace line parses.
+// It does not apply if b already sets the result type, since the rules using
+// it set the type.
+func singlePattern(b query.Basic) (query.Pattern, bool) {
+	if b.Exists(query.FieldType) || b.Exists(query.FieldSelect) {
+		return query.Pattern{}, false
+	}
+	p, ok := b.Pattern.(query.Pattern)
+	if !ok || p.Negated || p.Annotation.Labels.IsSet(query.Regexp) {
+		return query.Pattern{}, false
+	}
+	return p, true
+}
+
+// hasLanguageExtension returns whether name ends in the file extension of a
+// known language, which tells file names like main.go apart from qualified
+// identifiers like http.Client.
+func hasLanguageExtension(name string) bool {
+	return len(enry.GetLanguagesByExtension(name, nil, nil)) > 0
+}
+
+var (
+	identifierPattern = regexp.MustCompile(`^[A-Za-z_$][\w$]*$`)
+	// camelCaseHump matches the start of an inner word in CamelCase
+	// identifiers like parseQuery, NewSearchJob or HTTPClient.
+	camelCaseHump           = regexp.MustCompile(`[a-z0-9][A-Z]|[A-Z][A-Z][a-z]`)
+	dottedIdentifierPattern = regexp.MustCompile(`^[A-Za-z_$][\w$]*(\.[A-Za-z_$][\w$]*)+$`)
+)
+
+// identifierPatterns searches for a pattern that looks like a CamelCase
+// identifier or a dotted, qualified identifier as a symbol. Since symbols are
+// indexed by their unqualified name, a qualified identifier like http.Client
+// searches for the symbol Client.
+func identifierPatterns(b query.Basic) *query.Basic {
+	p, ok := singlePattern(b)
+	if !ok {
+		return nil
+	}
+
+	var name string
+	switch {
+	case identifierPattern.MatchString(p.Value) && camelCaseHump.MatchString(p.Value):
+		name = p.Value
+	case dottedIdentifierPattern.MatchString(p.Value) && !hasLanguageExtension(p.Value):
+		name = p.Value[strings.LastIndex(p.Value, ".")+1:]
+	default:
+		return nil
+	}
+
+	typeParam := query.Parameter{
+		Field:      query.FieldType,
+		Value:      "symbol",
+		Negated:    false,
+		Annotation: query.Annotation{},
+	}
+	pattern := query.Pattern{
+		Value:      name,
+		Negated:    false,
+		Annotation: query.Annotation{Labels: p.Annotation.Labels},
+	}
+
+	return &query.Basic{
+		Parameters: append(b.Parameters, typeParam),
+		Pattern:    pattern,
+	}
+}
+
+var pathPattern = regexp.MustCompile(`^(\./)?[\w.@+\-]+(/[\w.@+\-]+)*/?$`)
+
+// pathPatterns searches for a pattern that looks like a file path, like
+// pkg/foo/bar.go or package.json, as a file filter, and selects the matching
+// files.
+func pathPatterns(b query.Basic) *query.Basic {
+	p, ok := singlePattern(b)
+	if !ok || !pathPattern.MatchString(p.Value) {
+		return nil
+	}
+
+	path := strings.TrimPrefix(p.Value, "./")
+	segments := strings.Split(strings.TrimSuffix(path, "/"), "/")
+	if len(segments) == 1 && (strings.HasSuffix(path, "/") || !hasLanguageExtension(path)) {
+		// A single word is only a path if it is a file name.
+		return nil
+	}
+	if _, ok := lookup[strings.TrimPrefix(segments[0], "www.")]; ok {
+		// Code host URLs are handled by patternsToCodeHostFilters.
+		return nil
+	}
+
+	return fileQuery(b, path)
+}
+
+// fileQuery returns b with its pattern replaced by a file filter for path,
+// selecting the matching files.
+func fileQuery(b query.Basic, path string) *query.Basic {
+	value := regexp.QuoteMeta(path)
+	if !strings.HasSuffix(path, "/") && strings.Contains(path[strings.LastIndex(path, "/")+1:], ".") {
+		// Anchor file names, but not directories.
+		value += "$"
+	}
+
+	fileParam := query.Parameter{
+		Field:      query.FieldFile,
+		Value:      value,
+		Negated:    false,
+		Annotation: query.Annotation{},
+	}
+	selectParam := query.Parameter{
+		Field:      query.FieldSelect,
+		Value:      "file",
+		Negated:    false,
+		Annotation: query.Annotation{},
+	}
+
+	return &query.Basic{
+		Parameters: append(b.Parameters, fileParam, selectParam),
+		Pattern:    nil,
+	}
+}
+
+// stackFrameRegexps match the file and line of a frame in common stack trace
+// formats. The first submatch is the file path, the second the line number.
+var stackFrameRegexps = []*regexp.Regexp{
+	// Python: File "/app/foo/bar.py", line 42, in main
+	regexp.MustCompile(`File "([^"]+)", line (\d+)`),
+	// Go, Java, Node.js, Ruby, Rust, .NET and compiler errors, like
+	// /app/server.go:42 +0x1d, at Foo.bar(Foo.java:42) or
+	// at main (/app/index.js:42:7).
+	regexp.MustCompile(`((?:[A-Za-z]:)?[\w.@+\-/\\]*[\w\-]\.[A-Za-z]\w*):(?:line )?(\d+)`),
+}
+
+// parseStackFrame returns the file path and line number of the stack trace
+// frame in s. Absolute paths are specific to the machine that produced the
+// stack trace, so only their file name and parent directory are kept.
+func parseStackFrame(s string) (path, line string, ok bool) {
+	for _, re := range stackFrameRegexps {
+		if m := re.FindStringSubmatch(s); m != nil {
+			path, line = m[1], m[2]
+			break
+		}
+	}
+	if path == "" {
+		return "", "", false
+	}
+
+	path = strings.ReplaceAll(path, "\\", "/")
+	if len(path) > 1 && path[1] == ':' {
+		// Windows drive letter.
+		path = path[2:]
+	}
+
+	var segments []string
+	for _, segment := range strings.Split(path, "/") {
+		switch segment {
+		case "", ".", "..":
+			continue
+		}
+		// Strip module versions, like in /go/pkg/mod/github.com/org/repo@v1.2.3/file.go.
+		if i := strings.Index(segment, "@"); i > 0 {
+			segment = segment[:i]
+		}
+		segments = append(segments, segment)
+	}
+	if len(segments) == 0 {
+		return "", "", false
+	}
+	if strings.HasPrefix(path, "/") && len(segments) > 2 {
+		segments = segments[len(segments)-2:]
+	}
+	return strings.Join(segments, "/"), line, true
+}
+
+// stackTracePatterns searches for the file of a pasted stack trace frame, like
+// `File "/app/foo/bar.py", line 42, in main` or `/app/server.go:42 +0x1d`.
+// The line of the frame is returned by stackTraceLine.
+func stackTracePatterns(b query.Basic) *query.Basic {
+	p, ok := singlePattern(b)
+	if !ok {
+		return nil
+	}
+	path, _, ok := parseStackFrame(p.Value)
+	if !ok {
+		return nil
+	}
+	return fileQuery(b, path)
+}
+
+// stackTraceLine annotates the query generated by stackTracePatterns with the
+// line of the stack trace frame, so that clients can jump to it.
+func stackTraceLine(b query.Basic) map[search.AnnotationName]string {
+	p, ok := singlePattern(b)
+	if !ok {
+		return nil
+	}
+	_, line, ok := parseStackFrame(p.Value)
+	if !ok {
+		return nil
+	}
+	return map[search.AnnotationName]string{search.Line: line}
+}
@@ -189,3 +189,75 @@ func Test_rewriteRepoFilter(t *testing.T) {
 		})
 	}
 }
+
+func Test_identifierPatterns(t *testing.T) {
+	rule := []transform{identifierPatterns}
+	test := func(input string) string {
+		return apply(input, rule)
+	}
+
+	cases := []string{
+		`NewSearchJob`,
+		`parseQuery`,
+		`HTTPClient`,
+		`http.Client`,
+		`os.path.join`,
+		`repo:sourcegraph ToBasicQuery`,
+		`main.go`,
+		`monitor`,
+		`type:commit NewSearchJob`,
+	}
+
+	for _, c := range cases {
+		t.Run("identifier patterns", func(t *testing.T) {
+			autogold.ExpectFile(t, autogold.Raw(test(c)))
+		})
+	}
+}
+
+func Test_pathPatterns(t *testing.T) {
+	rule := []transform{pathPatterns}
+	test := func(input string) string {
+		return apply(input, rule)
+	}
+
+	cases := []string{
+		`pkg/foo/bar.go`,
+		`./client/web/src/index.tsx`,
+		`internal/search/`,
+		`repo:sourcegraph package.json`,
+		`monitor`,
+		`github.com/sourcegraph/sourcegraph`,
+		`pkg/foo/bar.go:42`,
+	}
+
+	for _, c := range cases {
+		t.Run("path patterns", func(t *testing.T) {
+			autogold.ExpectFile(t, autogold.Raw(test(c)))
+		})
+	}
+}
+
+func Test_stackTracePatterns(t *testing.T) {
+	rule := []transform{stackTracePatterns}
+	test := func(input string) string {
+		return apply(input, rule)
+	}
+
+	cases := []string{
+		`File "/usr/lib/python3/site-packages/foo/bar.py", line 42, in main`,
+		`/home/me/go/pkg/mod/github.com/acme/api@v1.2.0/server.go:128 +0x1d`,
+		`at com.example.foo.Bar.baz(Bar.java:42)`,
+		`at handler (/app/src/server/index.js:10:5)`,
+		`at Acme.Api.Server.Start() in C:\src\Acme.Api\Server.cs:line 17`,
+		`src/main.rs:10:5`,
+		`repo:acme/api internal/search.go:12:3`,
+		`parse error`,
+	}
+
+	for _, c := range cases {
+		t.Run("stack trace patterns", func(t *testing.T) {
+			autogold.ExpectFile(t, autogold.Raw(test(c)))
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 type autoQuery struct {
 	description string
 	query       query.Basic
+	annotations map[search.AnnotationName]string
 }
 
 // newJob is a function that converts a query to a job, and one which lucky
@@ -225,15 +226,16 @@ func (n *notifier) New(count int) error {
 	} else {
 		resultCountString = fmt.Sprintf("%d additional results", count)
 	}
-	annotations := make(map[search.AnnotationName]string)
+	annotations := make(map[search.AnnotationName]string, len(n.annotations)+1)
+	for name, value := range n.annotations {
+		annotations[name] = value
+	}
 	annotations[search.ResultCount] = resultCountString
 
 	return &alertobserver.ErrLuckyQueries{
 		ProposedQueries: []*search.QueryDescription{{
 			Description: n.description,
-			Annotations: map[search.AnnotationName]string{
-				search.ResultCount: resultCountString,
-			},
+			Annotations: annotations,
 			Query:       query.StringHuman(n.query.ToParseTree()),
 			PatternType: query.SearchTypeLucky,
 		}},
@@ -0,0 +1,35 @@
+package http
+
+import "strconv"
+
+// csvHeader is the header row of CSV exports.
+var csvHeader = []string{"type", "repository", "commit", "path", "line", "column", "preview"}
+
+// csvRecords returns the CSV rows of m: one per location of the match, or a
+// single one without line and column if it has no locations.
+func csvRecords(m exportMatch) [][]string {
+	if len(m.locations) == 0 {
+		return [][]string{{m.typ.name(), m.repository, m.commit, m.path, "", "", m.text}}
+	}
+
+	records := make([][]string, 0, len(m.locations))
+	for _, loc := range m.locations {
+		records = append(records, []string{
+			m.typ.name(),
+			m.repository,
+			m.commit,
+			m.path,
+			csvPosition(loc.line),
+			csvPosition(loc.column),
+			loc.preview,
+		})
+	}
+	return records
+}
+
+func csvPosition(p int) string {
+	if p == 0 {
+		return ""
+	}
+	return strconv.Itoa(p)
+}
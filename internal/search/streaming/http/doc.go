@@ -1,3 +1,3 @@
 // package http contains Sourcegraph's streaming HTTP protocol, which is based
-// on Server Sent Events (SSE).
+// on Server Sent Events (SSE), and the export encodings of search results.
 package http
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+)
+
+// Encoding is a media type the search streaming endpoint can respond with.
+type Encoding string
+
+const (
+	// EncodingEventStream is the Server Sent Events protocol read by
+	// FrontendStreamDecoder, which is used by the web app and src-cli.
+	EncodingEventStream Encoding = "text/event-stream"
+
+	// EncodingNDJSON streams every match as a JSON encoded EventMatch on its
+	// own line.
+	EncodingNDJSON Encoding = "application/x-ndjson"
+
+	// EncodingCSV streams a CSV row per match location.
+	EncodingCSV Encoding = "text/csv"
+
+	// EncodingSARIF streams a SARIF 2.1.0 log with a result per match.
+	EncodingSARIF Encoding = "application/sarif+json"
+)
+
+// NegotiateEncoding returns the encoding to respond with for the Accept header
+// of a request. The supported media type with the highest quality wins, and
+// ties go to the first one listed. EncodingEventStream is returned if accept
+// does not list any other supported media type.
+func NegotiateEncoding(accept string) Encoding {
+	best, bestQuality := EncodingEventStream, 0.0
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(mediaRange)
+		if err != nil {
+			continue
+		}
+
+		switch encoding := Encoding(mediaType); encoding {
+		case EncodingEventStream, EncodingNDJSON, EncodingCSV, EncodingSARIF:
+			quality := 1.0
+			if q, err := strconv.ParseFloat(params["q"], 64); err == nil {
+				quality = q
+			}
+			if quality > bestQuality {
+				best, bestQuality = encoding, quality
+			}
+		}
+	}
+	return best
+}
@@ -167,6 +167,10 @@ type EventCommitMatch struct {
 	Content         string     `json:"content"`
 	// [line, character, length]
 	Ranges [][3]int32 `json:"ranges"`
+	// PerforceChangelistID is the ID of the changelist the commit was
+	// converted from, if the repository is a Perforce depot. For those
+	// commits, AuthorName is the Perforce user who submitted the changelist.
+	PerforceChangelistID string `json:"perforceChangelistID,omitempty"`
 }
 
 func (e *EventCommitMatch) eventMatch() {}
@@ -204,6 +208,54 @@ type EventTeamMatch struct {
 
 func (e *EventTeamMatch) eventMatch() {}
 
+// EventLanguageMatch is a language of matching files, as selected by
+// select:file.language.
+type EventLanguageMatch struct {
+	// Type is always LanguageMatchType. Included here for marshalling.
+	Type MatchType `json:"type"`
+
+	Language string `json:"language"`
+
+	// Count is the number of matching files in the language.
+	Count int `json:"count"`
+}
+
+func (e *EventLanguageMatch) eventMatch() {}
+
+// EventCommitAuthorMatch is an author of matching commits, as selected by
+// select:commit.author.
+type EventCommitAuthorMatch struct {
+	// Type is always CommitAuthorMatchType. Included here for marshalling.
+	Type MatchType `json:"type"`
+
+	Name  string `json:"name"`
+	Email string `json:"email"`
+
+	// Count is the number of matching commits by the author.
+	Count int `json:"count"`
+}
+
+func (e *EventCommitAuthorMatch) eventMatch() {}
+
+// EventContributorMatch is a top committer of a matching repository, as
+// selected by select:repo.contributors.
+type EventContributorMatch struct {
+	// Type is always ContributorMatchType. Included here for marshalling.
+	Type MatchType `json:"type"`
+
+	RepositoryID int32    `json:"repositoryID"`
+	Repository   string   `json:"repository"`
+	Branches     []string `json:"branches,omitempty"`
+
+	Name  string `json:"name"`
+	Email string `json:"email"`
+
+	// Count is the number of commits by the contributor.
+	Count int `json:"count"`
+}
+
+func (e *EventContributorMatch) eventMatch() {}
+
 // EventFilter is a suggestion for a search filter. Currently has a 1-1
 // correspondance with the SearchFilter graphql type.
 type EventFilter struct {
@@ -251,6 +303,9 @@ const (
 	PathMatchType
 	PersonMatchType
 	TeamMatchType
+	LanguageMatchType
+	CommitAuthorMatchType
+	ContributorMatchType
 )
 
 func (t MatchType) MarshalJSON() ([]byte, error) {
@@ -269,6 +324,12 @@ func (t MatchType) MarshalJSON() ([]byte, error) {
 		return []byte(`"person"`), nil
 	case TeamMatchType:
 		return []byte(`"team"`), nil
+	case LanguageMatchType:
+		return []byte(`"language"`), nil
+	case CommitAuthorMatchType:
+		return []byte(`"author"`), nil
+	case ContributorMatchType:
+		return []byte(`"contributor"`), nil
 	default:
 		return nil, errors.Errorf("unknown MatchType: %d", t)
 	}
@@ -289,6 +350,12 @@ func (t *MatchType) UnmarshalJSON(b []byte) error {
 		*t = PersonMatchType
 	} else if bytes.Equal(b, []byte(`"team"`)) {
 		*t = TeamMatchType
+	} else if bytes.Equal(b, []byte(`"language"`)) {
+		*t = LanguageMatchType
+	} else if bytes.Equal(b, []byte(`"author"`)) {
+		*t = CommitAuthorMatchType
+	} else if bytes.Equal(b, []byte(`"contributor"`)) {
+		*t = ContributorMatchType
 	} else {
 		return errors.Errorf("unknown MatchType: %s", b)
 	}
@@ -0,0 +1,360 @@
+package http
+
+import (
+	"bytes"
+	"encoding/csv"
+	"net/http"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+const (
+	// TrailerError is the HTTP trailer of CSV exports set to the error
+	// message if the search failed, since CSV cannot represent errors.
+	TrailerError = "X-Sourcegraph-Search-Error"
+
+	// TrailerAlert is the HTTP trailer of CSV exports set to the title of
+	// the alert of the search, if any.
+	TrailerAlert = "X-Sourcegraph-Search-Alert"
+)
+
+// ExportOptions configure an ExportWriter.
+type ExportOptions struct {
+	// FlushSize is the size in bytes at which batches of matches are
+	// written out.
+	FlushSize int
+
+	// Query is the search query. It describes the rule of SARIF logs.
+	Query string
+
+	// RuleID is the rule ID of the results in SARIF logs. It defaults to
+	// DefaultSARIFRuleID.
+	RuleID string
+}
+
+// ExportWriter writes search results to an HTTP response in one of the export
+// encodings: EncodingNDJSON, EncodingCSV or EncodingSARIF. Matches are batched
+// like the matches events of Writer and written out synchronously, so a slow
+// client slows down the search in the same way.
+type ExportWriter struct {
+	w        http.ResponseWriter
+	flush    func()
+	encoding Encoding
+	ruleID   string
+
+	// json batches NDJSON lines or SARIF results.
+	json *JSONArrayBuf
+
+	// csvBuf batches the CSV rows written by csv.
+	csv       *csv.Writer
+	csvBuf    bytes.Buffer
+	flushSize int
+
+	sarifResults  bool
+	notifications []sarifNotification
+}
+
+// NewExportWriter creates a writer for encoding that sets the headers for it.
+// Like with NewWriter, users should only interact with *ExportWriter once it is
+// used.
+func NewExportWriter(w http.ResponseWriter, encoding Encoding, opts ExportOptions) (*ExportWriter, error) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, errors.New("http flushing not supported")
+	}
+
+	e := &ExportWriter{
+		w:         w,
+		flush:     flusher.Flush,
+		encoding:  encoding,
+		ruleID:    opts.RuleID,
+		flushSize: opts.FlushSize,
+	}
+	if e.ruleID == "" {
+		e.ruleID = DefaultSARIFRuleID
+	}
+
+	contentType := string(encoding)
+	switch encoding {
+	case EncodingNDJSON:
+		e.json = NewJSONLinesBuf(opts.FlushSize, e.write)
+	case EncodingSARIF:
+		e.json = NewJSONArrayBuf(opts.FlushSize, e.writeSARIFResults)
+	case EncodingCSV:
+		contentType += "; charset=utf-8; header=present"
+		e.csv = csv.NewWriter(&e.csvBuf)
+		w.Header().Set("Trailer", TrailerError+", "+TrailerAlert)
+	default:
+		return nil, errors.Errorf("unsupported export encoding %q", encoding)
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Transfer-Encoding", "chunked")
+	// See NewWriter.
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	switch encoding {
+	case EncodingSARIF:
+		prefix, err := sarifLogPrefix(e.ruleID, opts.Query)
+		if err != nil {
+			return nil, err
+		}
+		if err := e.write(prefix); err != nil {
+			return nil, err
+		}
+	case EncodingCSV:
+		if err := e.appendCSV(csvHeader); err != nil {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+// Append adds m to the batch of matches. If the batch exceeds the flush size
+// it is written out.
+func (e *ExportWriter) Append(m EventMatch) error {
+	switch e.encoding {
+	case EncodingNDJSON:
+		return e.json.Append(m)
+	case EncodingSARIF:
+		return e.json.Append(newSARIFResult(e.ruleID, newExportMatch(m)))
+	case EncodingCSV:
+		return e.appendCSV(csvRecords(newExportMatch(m))...)
+	}
+	return nil
+}
+
+// Flush writes out the batch of matches if there is one.
+func (e *ExportWriter) Flush() error {
+	if e.csv != nil {
+		if e.csvBuf.Len() == 0 {
+			return nil
+		}
+		buf := e.csvBuf.Bytes()
+		e.csvBuf.Reset()
+		return e.write(buf)
+	}
+	return e.json.Flush()
+}
+
+// Error reports that the search failed. It is written as an object with type
+// "error" in NDJSON, as a tool execution notification in SARIF and as the
+// TrailerError trailer in CSV.
+func (e *ExportWriter) Error(err error) error {
+	switch e.encoding {
+	case EncodingNDJSON:
+		return e.appendJSONLine(exportError{Type: "error", Message: err.Error()})
+	case EncodingSARIF:
+		e.notifications = append(e.notifications, sarifNotification{Level: "error", Message: sarifMessage{Text: err.Error()}})
+	case EncodingCSV:
+		e.w.Header().Set(TrailerError, trailerValue(err.Error()))
+	}
+	return nil
+}
+
+// Alert reports the alert of a search, like that the query is invalid. It is
+// written as an object with type "alert" in NDJSON, as a tool execution
+// notification in SARIF and as the TrailerAlert trailer in CSV.
+func (e *ExportWriter) Alert(title, description string) error {
+	switch e.encoding {
+	case EncodingNDJSON:
+		return e.appendJSONLine(exportAlert{Type: "alert", Title: title, Description: description})
+	case EncodingSARIF:
+		text := title
+		if description != "" {
+			text += ": " + description
+		}
+		e.notifications = append(e.notifications, sarifNotification{Level: "warning", Message: sarifMessage{Text: text}})
+	case EncodingCSV:
+		e.w.Header().Set(TrailerAlert, trailerValue(title))
+	}
+	return nil
+}
+
+// Done writes out the remaining matches and terminates the response.
+func (e *ExportWriter) Done() error {
+	if err := e.Flush(); err != nil {
+		return err
+	}
+	if e.encoding == EncodingSARIF {
+		suffix, err := sarifLogSuffix(e.notifications)
+		if err != nil {
+			return err
+		}
+		return e.write(suffix)
+	}
+	return nil
+}
+
+func (e *ExportWriter) write(b []byte) error {
+	_, err := e.w.Write(b)
+	e.flush()
+	return err
+}
+
+// writeSARIFResults writes a JSON array of SARIF results as the continuation
+// of the results array of the SARIF log.
+func (e *ExportWriter) writeSARIFResults(array []byte) error {
+	results := array[1 : len(array)-1]
+	if e.sarifResults {
+		results = append([]byte{','}, results...)
+	}
+	e.sarifResults = true
+	return e.write(results)
+}
+
+func (e *ExportWriter) appendJSONLine(v any) error {
+	if err := e.json.Append(v); err != nil {
+		return err
+	}
+	return e.json.Flush()
+}
+
+func (e *ExportWriter) appendCSV(records ...[]string) error {
+	if err := e.csv.WriteAll(records); err != nil {
+		return err
+	}
+	if e.csvBuf.Len() >= e.flushSize {
+		return e.Flush()
+	}
+	return nil
+}
+
+type exportError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+type exportAlert struct {
+	Type        string `json:"type"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+// trailerValue makes s a valid HTTP header value.
+func trailerValue(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// exportMatch is the flattened form of an EventMatch shared by the CSV and
+// SARIF encodings.
+type exportMatch struct {
+	typ        MatchType
+	repository string
+	commit     string
+	path       string
+
+	// text describes the match, like the first matched line.
+	text      string
+	locations []exportLocation
+}
+
+// exportLocation is a location of a match in a file. Lines and columns are
+// 1-based, and end columns are exclusive. Unknown positions are 0.
+type exportLocation struct {
+	line, column       int
+	endLine, endColumn int
+
+	// preview is the matched line or symbol.
+	preview string
+}
+
+func newExportMatch(m EventMatch) exportMatch {
+	switch v := m.(type) {
+	case *EventContentMatch:
+		em := exportMatch{typ: v.Type, repository: v.Repository, commit: v.Commit, path: v.Path}
+		for _, cm := range v.ChunkMatches {
+			lines := strings.Split(cm.Content, "\n")
+			for _, r := range cm.Ranges {
+				var preview string
+				if i := r.Start.Line - cm.ContentStart.Line; i >= 0 && i < len(lines) {
+					preview = lines[i]
+				}
+				em.locations = append(em.locations, exportLocation{
+					line:      r.Start.Line + 1,
+					column:    r.Start.Column + 1,
+					endLine:   r.End.Line + 1,
+					endColumn: r.End.Column + 1,
+					preview:   preview,
+				})
+			}
+		}
+		for _, lm := range v.LineMatches {
+			for _, ol := range lm.OffsetAndLengths {
+				em.locations = append(em.locations, exportLocation{
+					line:      int(lm.LineNumber) + 1,
+					column:    int(ol[0]) + 1,
+					endLine:   int(lm.LineNumber) + 1,
+					endColumn: int(ol[0]+ol[1]) + 1,
+					preview:   lm.Line,
+				})
+			}
+		}
+		if len(em.locations) > 0 {
+			em.text = strings.TrimSpace(em.locations[0].preview)
+		}
+		if em.text == "" {
+			em.text = v.Path
+		}
+		return em
+
+	case *EventPathMatch:
+		return exportMatch{typ: v.Type, repository: v.Repository, commit: v.Commit, path: v.Path, text: v.Path}
+
+	case *EventSymbolMatch:
+		em := exportMatch{typ: v.Type, repository: v.Repository, commit: v.Commit, path: v.Path}
+		names := make([]string, 0, len(v.Symbols))
+		for _, sym := range v.Symbols {
+			names = append(names, sym.Name)
+			em.locations = append(em.locations, exportLocation{line: int(sym.Line), preview: sym.Name})
+		}
+		em.text = strings.Join(names, ", ")
+		return em
+
+	case *EventRepoMatch:
+		return exportMatch{typ: v.Type, repository: v.Repository, text: v.Repository}
+
+	case *EventCommitMatch:
+		text, _, _ := strings.Cut(strings.TrimSpace(v.Message), "\n")
+		return exportMatch{typ: v.Type, repository: v.Repository, commit: v.OID, text: text}
+
+	case *EventPersonMatch:
+		text := v.Handle
+		if text == "" {
+			text = v.Email
+		}
+		return exportMatch{typ: v.Type, text: text}
+
+	case *EventTeamMatch:
+		return exportMatch{typ: v.Type, text: v.Handle}
+
+	case *EventLanguageMatch:
+		return exportMatch{typ: v.Type, text: v.Language}
+
+	case *EventCommitAuthorMatch:
+		return exportMatch{typ: v.Type, text: nameAndEmail(v.Name, v.Email)}
+
+	case *EventContributorMatch:
+		return exportMatch{typ: v.Type, repository: v.Repository, text: nameAndEmail(v.Name, v.Email)}
+	}
+	return exportMatch{}
+}
+
+func nameAndEmail(name, email string) string {
+	if email == "" {
+		return name
+	}
+	return name + " <" + email + ">"
+}
+
+// name returns the name of t in the JSON encoding, like "content".
+func (t MatchType) name() string {
+	b, err := t.MarshalJSON()
+	if err != nil {
+		return ""
+	}
+	return strings.Trim(string(b), `"`)
+}
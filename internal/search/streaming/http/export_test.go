@@ -0,0 +1,166 @@
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	for accept, want := range map[string]Encoding{
+		"":                                  EncodingEventStream,
+		"*/*":                               EncodingEventStream,
+		"text/event-stream":                 EncodingEventStream,
+		"application/x-ndjson":              EncodingNDJSON,
+		"text/csv; charset=utf-8":           EncodingCSV,
+		"application/sarif+json, */*;q=0.8": EncodingSARIF,
+		"text/csv;q=0.5, application/x-ndjson;q=0.9": EncodingNDJSON,
+		"text/csv;q=0, application/json":             EncodingEventStream,
+	} {
+		require.Equal(t, want, NegotiateEncoding(accept), accept)
+	}
+}
+
+func TestExportWriter(t *testing.T) {
+	matches := []EventMatch{
+		&EventContentMatch{
+			Type:       ContentMatchType,
+			Repository: "github.com/sourcegraph/sourcegraph",
+			Commit:     "deadbeef",
+			Path:       "cmd/main.go",
+			ChunkMatches: []ChunkMatch{{
+				Content:      "func main() {\n\tfmt.Println(\"hello\")",
+				ContentStart: Location{Line: 9},
+				Ranges: []Range{{
+					Start: Location{Line: 10, Column: 5},
+					End:   Location{Line: 10, Column: 12},
+				}},
+			}},
+		},
+		&EventPathMatch{
+			Type:       PathMatchType,
+			Repository: "github.com/sourcegraph/sourcegraph",
+			Commit:     "deadbeef",
+			Path:       "docs/read me.md",
+		},
+		&EventCommitMatch{
+			Type:       CommitMatchType,
+			Repository: "github.com/sourcegraph/sourcegraph",
+			OID:        "cafebabe",
+			Message:    "Fix the build\n\nIt was broken.",
+		},
+	}
+
+	export := func(t *testing.T, encoding Encoding, flushSize int) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		e, err := NewExportWriter(w, encoding, ExportOptions{
+			FlushSize: flushSize,
+			Query:     "fmt.Println",
+			RuleID:    "no-println",
+		})
+		require.NoError(t, err)
+		for _, m := range matches {
+			require.NoError(t, e.Append(m))
+		}
+		require.NoError(t, e.Alert("Some repositories could not be searched", ""))
+		require.NoError(t, e.Error(errors.New("search timed out")))
+		require.NoError(t, e.Done())
+		return w
+	}
+
+	t.Run("ndjson", func(t *testing.T) {
+		w := export(t, EncodingNDJSON, 32*1024)
+		require.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+
+		lines := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+		require.Len(t, lines, 5)
+
+		var first EventContentMatch
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+		require.Equal(t, "cmd/main.go", first.Path)
+		require.JSONEq(t, `{"type":"alert","title":"Some repositories could not be searched"}`, lines[3])
+		require.JSONEq(t, `{"type":"error","message":"search timed out"}`, lines[4])
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		// A flush size of 1 writes out every row on its own.
+		w := export(t, EncodingCSV, 1)
+		require.Equal(t, "text/csv; charset=utf-8; header=present", w.Header().Get("Content-Type"))
+
+		records, err := csv.NewReader(w.Body).ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, [][]string{
+			{"type", "repository", "commit", "path", "line", "column", "preview"},
+			{"content", "github.com/sourcegraph/sourcegraph", "deadbeef", "cmd/main.go", "11", "6", "\tfmt.Println(\"hello\")"},
+			{"path", "github.com/sourcegraph/sourcegraph", "deadbeef", "docs/read me.md", "", "", "docs/read me.md"},
+			{"commit", "github.com/sourcegraph/sourcegraph", "cafebabe", "", "", "", "Fix the build"},
+		}, records)
+
+		trailer := w.Result().Trailer
+		require.Equal(t, "search timed out", trailer.Get(TrailerError))
+		require.Equal(t, "Some repositories could not be searched", trailer.Get(TrailerAlert))
+	})
+
+	t.Run("sarif", func(t *testing.T) {
+		// A flush size of 1 writes out every result in its own batch.
+		w := export(t, EncodingSARIF, 1)
+		require.Equal(t, "application/sarif+json", w.Header().Get("Content-Type"))
+
+		require.JSONEq(t, `{
+  "version": "2.1.0",
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "runs": [{
+    "tool": {"driver": {
+      "name": "Sourcegraph",
+      "informationUri": "https://sourcegraph.com",
+      "rules": [{"id": "no-println", "shortDescription": {"text": "fmt.Println"}}]
+    }},
+    "results": [{
+      "ruleId": "no-println",
+      "message": {"text": "fmt.Println(\"hello\")"},
+      "locations": [{"physicalLocation": {
+        "artifactLocation": {"uri": "cmd/main.go"},
+        "region": {"startLine": 11, "startColumn": 6, "endLine": 11, "endColumn": 13, "snippet": {"text": "\tfmt.Println(\"hello\")"}}
+      }}],
+      "properties": {"type": "content", "repository": "github.com/sourcegraph/sourcegraph", "commit": "deadbeef"}
+    }, {
+      "ruleId": "no-println",
+      "message": {"text": "docs/read me.md"},
+      "locations": [{"physicalLocation": {"artifactLocation": {"uri": "docs/read%20me.md"}}}],
+      "properties": {"type": "path", "repository": "github.com/sourcegraph/sourcegraph", "commit": "deadbeef"}
+    }, {
+      "ruleId": "no-println",
+      "message": {"text": "Fix the build"},
+      "properties": {"type": "commit", "repository": "github.com/sourcegraph/sourcegraph", "commit": "cafebabe"}
+    }],
+    "invocations": [{
+      "executionSuccessful": false,
+      "toolExecutionNotifications": [
+        {"level": "warning", "message": {"text": "Some repositories could not be searched"}},
+        {"level": "error", "message": {"text": "search timed out"}}
+      ]
+    }]
+  }]
+}`, w.Body.String())
+	})
+}
+
+func TestJSONLinesBuf(t *testing.T) {
+	var writes []string
+	buf := NewJSONLinesBuf(10, func(b []byte) error {
+		writes = append(writes, string(b))
+		return nil
+	})
+	require.NoError(t, buf.Append(map[string]int{"a": 1}))
+	require.NoError(t, buf.Append(map[string]int{"b": 2}))
+	require.NoError(t, buf.Append(3))
+	require.NoError(t, buf.Flush())
+	// The first write happens once the buffer reaches the flush size.
+	require.Equal(t, []string{"{\"a\":1}\n{\"b\":2}\n", "3\n"}, writes)
+}
@@ -8,11 +8,15 @@ import (
 // JSONArrayBuf builds up a JSON array by marshalling per item. Once the array
 // has reached FlushSize it will be written out via Write and the buffer will
 // be reset.
+//
+// If created with NewJSONLinesBuf, items are written as newline-delimited JSON
+// instead of an array.
 type JSONArrayBuf struct {
 	FlushSize int
 	Write     func([]byte) error
 
-	buf bytes.Buffer
+	buf   bytes.Buffer
+	lines bool
 }
 
 func NewJSONArrayBuf(flushSize int, write func([]byte) error) *JSONArrayBuf {
@@ -26,15 +30,25 @@ func NewJSONArrayBuf(flushSize int, write func([]byte) error) *JSONArrayBuf {
 	return b
 }
 
+// NewJSONLinesBuf is like NewJSONArrayBuf, but builds up newline-delimited JSON:
+// every item is terminated by a newline rather than part of an array.
+func NewJSONLinesBuf(flushSize int, write func([]byte) error) *JSONArrayBuf {
+	b := NewJSONArrayBuf(flushSize, write)
+	b.lines = true
+	return b
+}
+
 // Append marshals v and adds it to the json array buffer. If the size of the
 // buffer exceed FlushSize the buffer is written out.
 func (j *JSONArrayBuf) Append(v any) error {
 	oldLen := j.buf.Len()
 
-	if j.buf.Len() == 0 {
-		j.buf.WriteByte('[')
-	} else {
-		j.buf.WriteByte(',')
+	if !j.lines {
+		if j.buf.Len() == 0 {
+			j.buf.WriteByte('[')
+		} else {
+			j.buf.WriteByte(',')
+		}
 	}
 
 	enc := json.NewEncoder(&j.buf)
@@ -44,8 +58,11 @@ func (j *JSONArrayBuf) Append(v any) error {
 		return err
 	}
 
-	// Trim the trailing newline left by the JSON encoder
-	j.buf.Truncate(j.buf.Len() - 1)
+	if !j.lines {
+		// Trim the trailing newline left by the JSON encoder. In lines mode
+		// it terminates the item.
+		j.buf.Truncate(j.buf.Len() - 1)
+	}
 
 	if j.buf.Len() >= j.FlushSize {
 		return j.Flush()
@@ -59,8 +76,10 @@ func (j *JSONArrayBuf) Flush() error {
 		return nil
 	}
 
-	// Terminate array
-	j.buf.WriteByte(']')
+	if !j.lines {
+		// Terminate array
+		j.buf.WriteByte(']')
+	}
 
 	buf := j.buf.Bytes()
 	j.buf.Reset()
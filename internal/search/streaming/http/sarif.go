@@ -0,0 +1,164 @@
+package http
+
+import (
+	"encoding/json"
+	"net/url"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	// DefaultSARIFRuleID is the rule ID of the results in SARIF exports if
+	// none is specified.
+	DefaultSARIFRuleID = "sourcegraph-search"
+)
+
+// A SARIF export is a log with a single run, whose results are streamed. The
+// log is written in three parts: sarifLogPrefix up to the opening of the
+// results array, the results, and sarifLogSuffix with the invocation of the
+// search.
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string        `json:"id"`
+	ShortDescription *sarifMessage `json:"shortDescription,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string          `json:"ruleId"`
+	Message    sarifMessage    `json:"message"`
+	Locations  []sarifLocation `json:"locations,omitempty"`
+	Properties sarifProperties `json:"properties"`
+}
+
+type sarifProperties struct {
+	Type       string `json:"type"`
+	Repository string `json:"repository,omitempty"`
+	Commit     string `json:"commit,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int           `json:"startLine"`
+	StartColumn int           `json:"startColumn,omitempty"`
+	EndLine     int           `json:"endLine,omitempty"`
+	EndColumn   int           `json:"endColumn,omitempty"`
+	Snippet     *sarifMessage `json:"snippet,omitempty"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level   string       `json:"level"`
+	Message sarifMessage `json:"message"`
+}
+
+// newSARIFResult returns the SARIF result for m. Paths are relative to the
+// root of the repository, which is a property of the result.
+func newSARIFResult(ruleID string, m exportMatch) sarifResult {
+	res := sarifResult{
+		RuleID:  ruleID,
+		Message: sarifMessage{Text: m.text},
+		Properties: sarifProperties{
+			Type:       m.typ.name(),
+			Repository: m.repository,
+			Commit:     m.commit,
+		},
+	}
+	if m.path == "" {
+		return res
+	}
+
+	artifact := sarifArtifactLocation{URI: (&url.URL{Path: m.path}).String()}
+	if len(m.locations) == 0 {
+		res.Locations = []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: artifact}}}
+		return res
+	}
+	for _, loc := range m.locations {
+		region := &sarifRegion{
+			StartLine:   loc.line,
+			StartColumn: loc.column,
+			EndLine:     loc.endLine,
+			EndColumn:   loc.endColumn,
+		}
+		if loc.preview != "" {
+			region.Snippet = &sarifMessage{Text: loc.preview}
+		}
+		res.Locations = append(res.Locations, sarifLocation{
+			PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: artifact, Region: region},
+		})
+	}
+	return res
+}
+
+// sarifLogPrefix returns the start of a SARIF log for the results of query,
+// up to the opening of the results array.
+func sarifLogPrefix(ruleID, query string) ([]byte, error) {
+	rule := sarifRule{ID: ruleID}
+	if query != "" {
+		rule.ShortDescription = &sarifMessage{Text: query}
+	}
+	tool, err := json.Marshal(sarifTool{Driver: sarifDriver{
+		Name:           "Sourcegraph",
+		InformationURI: "https://sourcegraph.com",
+		Rules:          []sarifRule{rule},
+	}})
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := []byte(`{"version":"` + sarifVersion + `","$schema":"` + sarifSchema + `","runs":[{"tool":`)
+	prefix = append(prefix, tool...)
+	return append(prefix, `,"results":[`...), nil
+}
+
+// sarifLogSuffix returns the end of a SARIF log after the results array. The
+// search is successful unless there is an error notification.
+func sarifLogSuffix(notifications []sarifNotification) ([]byte, error) {
+	invocation := sarifInvocation{
+		ExecutionSuccessful:        true,
+		ToolExecutionNotifications: notifications,
+	}
+	for _, n := range notifications {
+		if n.Level == "error" {
+			invocation.ExecutionSuccessful = false
+		}
+	}
+	b, err := json.Marshal([]sarifInvocation{invocation})
+	if err != nil {
+		return nil, err
+	}
+
+	suffix := []byte(`],"invocations":`)
+	suffix = append(suffix, b...)
+	return append(suffix, `}]}`...), nil
+}
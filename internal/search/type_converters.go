@@ -20,6 +20,21 @@ func FromMatch(match result.Match, repoCache map[api.RepoID]*types.SearchedRepo,
 		return fromCommit(v, repoCache)
 	case *result.OwnerMatch:
 		return fromOwner(v)
+	case *result.LanguageMatch:
+		return &http.EventLanguageMatch{
+			Type:     http.LanguageMatchType,
+			Language: v.Language,
+			Count:    v.Count,
+		}
+	case *result.CommitAuthorMatch:
+		return &http.EventCommitAuthorMatch{
+			Type:  http.CommitAuthorMatchType,
+			Name:  v.Name,
+			Email: v.Email,
+			Count: v.Count,
+		}
+	case *result.ContributorMatch:
+		return fromContributor(v)
 	default:
 		panic(fmt.Sprintf("unknown match type %T", v))
 	}
@@ -274,3 +289,20 @@ func fromOwner(owner *result.OwnerMatch) http.EventMatch {
 		panic(fmt.Sprintf("unknown owner match type %T", v))
 	}
 }
+
+func fromContributor(c *result.ContributorMatch) *http.EventContributorMatch {
+	var branches []string
+	if c.Rev != "" {
+		branches = []string{c.Rev}
+	}
+
+	return &http.EventContributorMatch{
+		Type:         http.ContributorMatchType,
+		RepositoryID: int32(c.Repo.ID),
+		Repository:   string(c.Repo.Name),
+		Branches:     branches,
+		Name:         c.Name,
+		Email:        c.Email,
+		Count:        c.Count,
+	}
+}
@@ -1870,15 +1870,18 @@ type CodeMonitoringUsageStatistics struct {
 }
 
 type NotebooksUsageStatistics struct {
-	NotebookPageViews                *int32
-	EmbeddedNotebookPageViews        *int32
-	NotebooksListPageViews           *int32
-	NotebooksCreatedCount            *int32
-	NotebookAddedStarsCount          *int32
-	NotebookAddedMarkdownBlocksCount *int32
-	NotebookAddedQueryBlocksCount    *int32
-	NotebookAddedFileBlocksCount     *int32
-	NotebookAddedSymbolBlocksCount   *int32
+	NotebookPageViews                  *int32
+	EmbeddedNotebookPageViews          *int32
+	NotebooksListPageViews             *int32
+	NotebooksCreatedCount              *int32
+	NotebookAddedStarsCount            *int32
+	NotebookAddedMarkdownBlocksCount   *int32
+	NotebookAddedQueryBlocksCount      *int32
+	NotebookAddedFileBlocksCount       *int32
+	NotebookAddedSymbolBlocksCount     *int32
+	NotebookAddedComputeBlocksCount    *int32
+	NotebookAddedInsightBlocksCount    *int32
+	NotebookAddedReferencesBlocksCount *int32
 }
 
 type OwnershipUsageStatistics struct {
@@ -1975,6 +1978,11 @@ type SearchContext struct {
 	// e.g. repo:^github\.com/org rev:bar archive:no f:sub/dir
 	Query string
 
+	// Dynamic, if set, defines the repositories of the search context by a source that is
+	// re-evaluated periodically, e.g. everything a team owns. Dynamic search contexts have
+	// neither a query nor user-defined repository revisions.
+	Dynamic *SearchContextDynamic
+
 	// Whether the search context is auto-defined by Sourcegraph. Auto-defined search contexts are not editable by users.
 	AutoDefined bool
 
@@ -1985,6 +1993,30 @@ type SearchContext struct {
 	Starred bool
 }
 
+// SearchContextDynamicSource is the source the repositories of a dynamic search context are
+// computed from.
+type SearchContextDynamicSource string
+
+const (
+	// SearchContextDynamicSourceOwner selects the repositories that contain files owned by a
+	// user or team, e.g. "@team-search".
+	SearchContextDynamicSourceOwner SearchContextDynamicSource = "owner"
+	// SearchContextDynamicSourceMeta selects the repositories that have a key-value pair or tag
+	// of repository metadata, e.g. "team:search" or "deprecated".
+	SearchContextDynamicSourceMeta SearchContextDynamicSource = "meta"
+	// SearchContextDynamicSourceDependencies selects a repository and the repositories it
+	// transitively depends on according to precise code intelligence, e.g.
+	// "github.com/sourcegraph/sourcegraph".
+	SearchContextDynamicSourceDependencies SearchContextDynamicSource = "dependencies"
+)
+
+type SearchContextDynamic struct {
+	Source SearchContextDynamicSource
+	Value  string
+	// EvaluatedAt is when the repositories were last computed, or zero if they haven't been yet.
+	EvaluatedAt time.Time
+}
+
 // SearchContextRepositoryRevisions is a simple wrapper for a repository and its revisions
 // contained in a search context. It is made compatible with search.RepositoryRevisions, so it can be easily
 // converted when needed. We could use search.RepositoryRevisions directly instead, but it
@@ -0,0 +1,362 @@
+package uploadstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/sourcegraph/log"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/iterator"
+)
+
+type azureStore struct {
+	container    string
+	manageBucket bool
+	client       *azblob.Client
+	operations   *Operations
+}
+
+var _ Store = &azureStore{}
+
+type AzureConfig struct {
+	// AccountName is the name of the storage account. It is used to derive the
+	// default endpoint and to authenticate with an account key.
+	AccountName string
+
+	// Endpoint overrides the blob service endpoint, e.g. for Azurite or sovereign
+	// clouds. Defaults to https://<account>.blob.core.windows.net/.
+	Endpoint string
+
+	// AccountKey authenticates with a shared key. Takes precedence over SASToken.
+	AccountKey string
+
+	// SASToken authenticates with a shared access signature. The token must grant
+	// access to the target container.
+	SASToken string
+
+	// ManagedIdentityClientID selects a user-assigned managed identity. It is only
+	// used if neither AccountKey nor SASToken are set, in which case the
+	// system-assigned identity is used if it is empty.
+	ManagedIdentityClientID string
+}
+
+const (
+	// azureBlockSize is the size of the blocks staged by uploads. Block blobs can
+	// have at most 50,000 blocks, so this limits objects to ~400GiB.
+	azureBlockSize = 8 * 1024 * 1024
+
+	// azureUploadConcurrency is the number of blocks staged in parallel by a
+	// single upload.
+	azureUploadConcurrency = 4
+)
+
+// newAzureFromConfig creates a new store backed by Azure Blob Storage.
+func newAzureFromConfig(_ context.Context, config Config, operations *Operations) (Store, error) {
+	client, err := azureClient(config.Azure)
+	if err != nil {
+		return nil, err
+	}
+
+	return newAzureWithClient(client, config.Bucket, config.ManageBucket, operations), nil
+}
+
+func newAzureWithClient(client *azblob.Client, container string, manageBucket bool, operations *Operations) *azureStore {
+	return &azureStore{
+		container:    container,
+		manageBucket: manageBucket,
+		client:       client,
+		operations:   operations,
+	}
+}
+
+func (s *azureStore) Init(ctx context.Context) error {
+	if !s.manageBucket {
+		return nil
+	}
+
+	if _, err := s.client.CreateContainer(ctx, s.container, nil); err != nil && !bloberror.HasCode(err, bloberror.ContainerAlreadyExists) {
+		return errors.Wrap(err, "failed to create container")
+	}
+
+	return nil
+}
+
+func (s *azureStore) List(ctx context.Context, prefix string) (_ *iterator.Iterator[string], err error) {
+	ctx, _, endObservation := s.operations.List.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("prefix", prefix),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	pager := s.client.NewListBlobsFlatPager(s.container, &azblob.ListBlobsFlatOptions{
+		Prefix: listPrefix(prefix),
+	})
+
+	next := func() ([]string, error) {
+		if !pager.More() {
+			return nil, nil
+		}
+
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		keys := make([]string, 0, len(page.Segment.BlobItems))
+		for _, item := range page.Segment.BlobItems {
+			if item.Name != nil {
+				keys = append(keys, *item.Name)
+			}
+		}
+
+		return keys, nil
+	}
+
+	return iterator.New[string](next), nil
+}
+
+func (s *azureStore) Get(ctx context.Context, key string) (_ io.ReadCloser, err error) {
+	ctx, _, endObservation := s.operations.Get.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("key", key),
+	}})
+	done := func() { endObservation(1, observation.Args{}) }
+
+	resp, err := s.client.DownloadStream(ctx, s.container, key, nil)
+	if err != nil {
+		done()
+		return nil, errors.Wrap(err, "failed to get object")
+	}
+
+	// The retry reader resumes the download from the last received byte on
+	// transient errors, similar to the connection reset handling of the S3 store.
+	rc := resp.NewRetryReader(ctx, &azblob.RetryReaderOptions{
+		MaxRetries: maxZeroReads,
+		OnFailedRead: func(failureCount int32, lastError error, _ azblob.HTTPRange, willRetry bool) {
+			s.operations.Get.Logger.Warn("Transient error while reading payload",
+				log.String("key", key),
+				log.Int32("failureCount", failureCount),
+				log.Bool("willRetry", willRetry),
+				log.Error(lastError))
+		},
+	})
+
+	return NewExtraCloser(rc, done), nil
+}
+
+func (s *azureStore) Upload(ctx context.Context, key string, r io.Reader) (_ int64, err error) {
+	ctx, _, endObservation := s.operations.Upload.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("key", key),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	n, err := s.upload(ctx, key, r)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to upload object")
+	}
+
+	return n, nil
+}
+
+func (s *azureStore) Compose(ctx context.Context, destination string, sources ...string) (_ int64, err error) {
+	ctx, _, endObservation := s.operations.Compose.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("destination", destination),
+		attribute.StringSlice("sources", sources),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	defer func() {
+		if err == nil {
+			// Delete sources on success
+			if err := s.deleteSources(ctx, sources); err != nil {
+				s.operations.Compose.Logger.Error("failed to delete source objects", log.Error(err))
+			}
+		}
+	}()
+
+	// Copying blocks from URLs requires the source blobs to be readable with the
+	// credentials of the destination, which is not the case for shared keys.
+	// Instead, we stream the sources into a new block blob. Blocks are only
+	// committed once all sources have been read, so a failure leaves the
+	// destination untouched.
+	r := &composeReader{ctx: ctx, store: s, sources: sources}
+	defer r.Close()
+
+	n, err := s.upload(ctx, destination, r)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to compose objects")
+	}
+
+	return n, nil
+}
+
+func (s *azureStore) Delete(ctx context.Context, key string) (err error) {
+	ctx, _, endObservation := s.operations.Delete.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("key", key),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	_, err = s.client.DeleteBlob(ctx, s.container, key, nil)
+	return errors.Wrap(err, "failed to delete object")
+}
+
+func (s *azureStore) ExpireObjects(ctx context.Context, prefix string, maxAge time.Duration) (err error) {
+	ctx, _, endObservation := s.operations.ExpireObjects.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("prefix", prefix),
+		attribute.Stringer("maxAge", maxAge),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	pager := s.client.NewListBlobsFlatPager(s.container, &azblob.ListBlobsFlatOptions{
+		Prefix: listPrefix(prefix),
+	})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			s.operations.ExpireObjects.Logger.Error("Failed to paginate Azure container", log.Error(err))
+			break // we'll try again later
+		}
+
+		for _, item := range page.Segment.BlobItems {
+			if item.Name == nil || item.Properties == nil || item.Properties.LastModified == nil {
+				continue
+			}
+			if time.Since(*item.Properties.LastModified) < maxAge {
+				continue
+			}
+
+			if _, err := s.client.DeleteBlob(ctx, s.container, *item.Name, nil); err != nil && !bloberror.HasCode(err, bloberror.BlobNotFound) {
+				s.operations.ExpireObjects.Logger.Error("Failed to delete expired Azure blob",
+					log.Error(err),
+					log.String("container", s.container),
+					log.String("blob", *item.Name))
+			}
+		}
+	}
+
+	return nil
+}
+
+// upload writes the content of the given reader to a block blob. The content is
+// staged in blocks that are uploaded in parallel and committed at the end.
+func (s *azureStore) upload(ctx context.Context, key string, r io.Reader) (int64, error) {
+	cr := &countingReader{r: r}
+
+	if _, err := s.client.UploadStream(ctx, s.container, key, cr, &azblob.UploadStreamOptions{
+		BlockSize:   azureBlockSize,
+		Concurrency: azureUploadConcurrency,
+	}); err != nil {
+		return 0, err
+	}
+
+	return int64(cr.n), nil
+}
+
+func (s *azureStore) deleteSources(ctx context.Context, sources []string) error {
+	return ForEachString(sources, func(index int, source string) error {
+		if _, err := s.client.DeleteBlob(ctx, s.container, source, nil); err != nil {
+			return errors.Wrap(err, "failed to delete source object")
+		}
+
+		return nil
+	})
+}
+
+// composeReader reads the given source blobs in order. Each source is only
+// opened once the previous one has been read completely.
+type composeReader struct {
+	ctx     context.Context
+	store   *azureStore
+	sources []string
+	current io.ReadCloser
+}
+
+func (r *composeReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if len(r.sources) == 0 {
+				return 0, io.EOF
+			}
+
+			resp, err := r.store.client.DownloadStream(r.ctx, r.store.container, r.sources[0], nil)
+			if err != nil {
+				return 0, errors.Wrapf(err, "failed to get source object %q", r.sources[0])
+			}
+			r.current = resp.NewRetryReader(r.ctx, &azblob.RetryReaderOptions{MaxRetries: maxZeroReads})
+			r.sources = r.sources[1:]
+		}
+
+		n, err := r.current.Read(p)
+		if err == io.EOF {
+			_ = r.current.Close()
+			r.current = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+
+		return n, err
+	}
+}
+
+func (r *composeReader) Close() error {
+	if r.current == nil {
+		return nil
+	}
+
+	err := r.current.Close()
+	r.current = nil
+	return err
+}
+
+func azureClient(config AzureConfig) (*azblob.Client, error) {
+	serviceURL := config.Endpoint
+	if serviceURL == "" {
+		if config.AccountName == "" {
+			return nil, errors.New("either an Azure storage account name or endpoint must be configured")
+		}
+		serviceURL = fmt.Sprintf("https://%s.blob.core.windows.net/", config.AccountName)
+	}
+
+	if config.AccountKey != "" {
+		cred, err := azblob.NewSharedKeyCredential(config.AccountName, config.AccountKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid Azure storage account key")
+		}
+
+		return azblob.NewClientWithSharedKeyCredential(serviceURL, cred, nil)
+	}
+
+	if config.SASToken != "" {
+		return azblob.NewClientWithNoCredential(serviceURL+"?"+strings.TrimPrefix(config.SASToken, "?"), nil)
+	}
+
+	var options *azidentity.ManagedIdentityCredentialOptions
+	if config.ManagedIdentityClientID != "" {
+		options = &azidentity.ManagedIdentityCredentialOptions{ID: azidentity.ClientID(config.ManagedIdentityClientID)}
+	}
+	cred, err := azidentity.NewManagedIdentityCredential(options)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create Azure managed identity credential")
+	}
+
+	return azblob.NewClient(serviceURL, cred, nil)
+}
+
+// listPrefix returns the prefix filter for listing blobs. An empty prefix is
+// omitted from the request entirely.
+func listPrefix(prefix string) *string {
+	if prefix == "" {
+		return nil
+	}
+
+	return &prefix
+}
@@ -0,0 +1,386 @@
+package uploadstore
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/iterator"
+)
+
+// azuriteAccountName and azuriteAccountKey are the well-known development
+// credentials of Azurite, the Azure Storage emulator.
+const (
+	azuriteAccountName = "devstoreaccount1"
+	azuriteAccountKey  = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+)
+
+func TestAzureStore(t *testing.T) {
+	server := newFakeAzurite(t)
+	testStoreBehavior(t, testAzureClient(t, server, AzureConfig{
+		AccountName: azuriteAccountName,
+		AccountKey:  azuriteAccountKey,
+	}, true))
+}
+
+func TestAzureStoreSASToken(t *testing.T) {
+	server := newFakeAzurite(t)
+	server.createContainer("test-bucket")
+
+	client := testAzureClient(t, server, AzureConfig{
+		SASToken: "?sv=2021-08-06&ss=b&srt=co&sp=rwdlac&sig=signature",
+	}, false)
+
+	if _, err := client.Upload(context.Background(), "test-key", strings.NewReader("TEST PAYLOAD")); err != nil {
+		t.Fatalf("unexpected error uploading object: %s", err)
+	}
+	if have := server.contents("test-bucket", "test-key"); have != "TEST PAYLOAD" {
+		t.Errorf("unexpected contents. want=%q have=%q", "TEST PAYLOAD", have)
+	}
+}
+
+func TestAzureStoreUnauthenticated(t *testing.T) {
+	server := newFakeAzurite(t)
+	server.createContainer("test-bucket")
+
+	// A SAS token without a signature is rejected
+	client := testAzureClient(t, server, AzureConfig{SASToken: "sv=2021-08-06"}, false)
+
+	if _, err := client.Upload(context.Background(), "test-key", strings.NewReader("TEST PAYLOAD")); err == nil {
+		t.Fatalf("expected error uploading object without signature")
+	}
+}
+
+func TestAzureStoreLargeUpload(t *testing.T) {
+	server := newFakeAzurite(t)
+	client := testAzureClient(t, server, AzureConfig{
+		AccountName: azuriteAccountName,
+		AccountKey:  azuriteAccountKey,
+	}, true)
+
+	// Large enough to be split into multiple staged blocks
+	payload := strings.Repeat("x", 2*azureBlockSize+123)
+
+	n, err := client.Upload(context.Background(), "test-key", strings.NewReader(payload))
+	if err != nil {
+		t.Fatalf("unexpected error uploading object: %s", err)
+	}
+	if n != int64(len(payload)) {
+		t.Errorf("unexpected size. want=%d have=%d", len(payload), n)
+	}
+	if blocks := server.committedBlocks("test-bucket", "test-key"); blocks != 3 {
+		t.Errorf("unexpected number of blocks. want=%d have=%d", 3, blocks)
+	}
+	if have := server.contents("test-bucket", "test-key"); have != payload {
+		t.Errorf("unexpected contents")
+	}
+}
+
+func TestAzureStoreExpireObjects(t *testing.T) {
+	server := newFakeAzurite(t)
+	client := testAzureClient(t, server, AzureConfig{
+		AccountName: azuriteAccountName,
+		AccountKey:  azuriteAccountKey,
+	}, true)
+
+	for _, key := range []string{"uploads/old", "uploads/new", "other/old"} {
+		if _, err := client.Upload(context.Background(), key, strings.NewReader("payload")); err != nil {
+			t.Fatalf("unexpected error uploading object: %s", err)
+		}
+	}
+	server.setLastModified("test-bucket", "uploads/old", time.Now().Add(-48*time.Hour))
+	server.setLastModified("test-bucket", "other/old", time.Now().Add(-48*time.Hour))
+
+	if err := client.ExpireObjects(context.Background(), "uploads/", 24*time.Hour); err != nil {
+		t.Fatalf("unexpected error expiring objects: %s", err)
+	}
+
+	keys, err := iterator.Collect(mustList(t, client, ""))
+	if err != nil {
+		t.Fatalf("unexpected error listing objects: %s", err)
+	}
+	if diff := cmp.Diff([]string{"other/old", "uploads/new"}, keys); diff != "" {
+		t.Errorf("unexpected keys (-want +got):\n%s", diff)
+	}
+}
+
+func testAzureClient(t *testing.T, server *fakeAzurite, config AzureConfig, manageBucket bool) Store {
+	t.Helper()
+
+	config.Endpoint = server.URL + "/" + azuriteAccountName + "/"
+	client, err := azureClient(config)
+	if err != nil {
+		t.Fatalf("unexpected error creating client: %s", err)
+	}
+
+	store := newAzureWithClient(client, "test-bucket", manageBucket, NewOperations(&observation.TestContext, "test", "brittlestore"))
+	if err := store.Init(context.Background()); err != nil {
+		t.Fatalf("unexpected error initializing store: %s", err)
+	}
+	return store
+}
+
+// fakeAzurite is an in-memory implementation of the subset of the Azure Blob
+// Storage REST API used by azureStore, in the spirit of Azurite.
+type fakeAzurite struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	containers map[string]map[string]*fakeBlob
+	// staged holds uncommitted blocks by container, blob and block ID.
+	staged map[string]map[string]map[string][]byte
+}
+
+type fakeBlob struct {
+	content      []byte
+	blocks       int
+	lastModified time.Time
+}
+
+// fakeAzuriteListPageSize is intentionally small to exercise pagination.
+const fakeAzuriteListPageSize = 2
+
+func newFakeAzurite(t *testing.T) *fakeAzurite {
+	f := &fakeAzurite{
+		containers: map[string]map[string]*fakeBlob{},
+		staged:     map[string]map[string]map[string][]byte{},
+	}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serveHTTP))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeAzurite) createContainer(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.containers[name] = map[string]*fakeBlob{}
+}
+
+func (f *fakeAzurite) contents(container, name string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if blob, ok := f.containers[container][name]; ok {
+		return string(blob.content)
+	}
+	return ""
+}
+
+func (f *fakeAzurite) committedBlocks(container, name string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if blob, ok := f.containers[container][name]; ok {
+		return blob.blocks
+	}
+	return 0
+}
+
+func (f *fakeAzurite) setLastModified(container, name string, t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.containers[container][name].lastModified = t
+}
+
+func (f *fakeAzurite) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "SharedKey "+azuriteAccountName+":") && r.URL.Query().Get("sig") == "" {
+		azuriteError(w, http.StatusForbidden, "AuthenticationFailed")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/"+azuriteAccountName+"/")
+	container, name, _ := strings.Cut(path, "/")
+	query := r.URL.Query()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	blobs, ok := f.containers[container]
+	if query.Get("restype") == "container" {
+		switch {
+		case r.Method == http.MethodPut:
+			if ok {
+				azuriteError(w, http.StatusConflict, "ContainerAlreadyExists")
+				return
+			}
+			f.containers[container] = map[string]*fakeBlob{}
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodGet && query.Get("comp") == "list":
+			if !ok {
+				azuriteError(w, http.StatusNotFound, "ContainerNotFound")
+				return
+			}
+			f.listBlobs(w, container, blobs, query.Get("prefix"), query.Get("marker"))
+		default:
+			azuriteError(w, http.StatusBadRequest, "UnsupportedHttpVerb")
+		}
+		return
+	}
+
+	if !ok {
+		azuriteError(w, http.StatusNotFound, "ContainerNotFound")
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodPut && query.Get("comp") == "block":
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			azuriteError(w, http.StatusBadRequest, "InvalidInput")
+			return
+		}
+		if f.staged[container] == nil {
+			f.staged[container] = map[string]map[string][]byte{}
+		}
+		if f.staged[container][name] == nil {
+			f.staged[container][name] = map[string][]byte{}
+		}
+		f.staged[container][name][query.Get("blockid")] = body
+		w.WriteHeader(http.StatusCreated)
+
+	case r.Method == http.MethodPut && query.Get("comp") == "blocklist":
+		var blockList struct {
+			Latest []string `xml:"Latest"`
+		}
+		if err := xml.NewDecoder(r.Body).Decode(&blockList); err != nil {
+			azuriteError(w, http.StatusBadRequest, "InvalidXmlDocument")
+			return
+		}
+		var content []byte
+		for _, id := range blockList.Latest {
+			block, ok := f.staged[container][name][id]
+			if !ok {
+				azuriteError(w, http.StatusBadRequest, "InvalidBlockList")
+				return
+			}
+			content = append(content, block...)
+		}
+		delete(f.staged[container], name)
+		blobs[name] = &fakeBlob{content: content, blocks: len(blockList.Latest), lastModified: time.Now()}
+		writeBlobHeaders(w, blobs[name])
+		w.WriteHeader(http.StatusCreated)
+
+	case r.Method == http.MethodPut && query.Get("comp") == "":
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			azuriteError(w, http.StatusBadRequest, "InvalidInput")
+			return
+		}
+		blobs[name] = &fakeBlob{content: body, blocks: 1, lastModified: time.Now()}
+		writeBlobHeaders(w, blobs[name])
+		w.WriteHeader(http.StatusCreated)
+
+	case r.Method == http.MethodGet:
+		blob, ok := blobs[name]
+		if !ok {
+			azuriteError(w, http.StatusNotFound, "BlobNotFound")
+			return
+		}
+		content := blob.content
+		status := http.StatusOK
+		if rng := r.Header.Get("x-ms-range"); rng != "" {
+			start, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+			if start > len(content) {
+				start = len(content)
+			}
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(content)-1, len(content)))
+			content = content[start:]
+			status = http.StatusPartialContent
+		}
+		writeBlobHeaders(w, blob)
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.Header().Set("x-ms-blob-type", "BlockBlob")
+		w.WriteHeader(status)
+		_, _ = w.Write(content)
+
+	case r.Method == http.MethodDelete:
+		if _, ok := blobs[name]; !ok {
+			azuriteError(w, http.StatusNotFound, "BlobNotFound")
+			return
+		}
+		delete(blobs, name)
+		w.WriteHeader(http.StatusAccepted)
+
+	default:
+		azuriteError(w, http.StatusBadRequest, "UnsupportedHttpVerb")
+	}
+}
+
+func (f *fakeAzurite) listBlobs(w http.ResponseWriter, container string, blobs map[string]*fakeBlob, prefix, marker string) {
+	var names []string
+	for name := range blobs {
+		if strings.HasPrefix(name, prefix) && name >= marker {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var nextMarker string
+	if len(names) > fakeAzuriteListPageSize {
+		nextMarker = names[fakeAzuriteListPageSize]
+		names = names[:fakeAzuriteListPageSize]
+	}
+
+	type properties struct {
+		LastModified  string `xml:"Last-Modified"`
+		ContentLength int    `xml:"Content-Length"`
+		BlobType      string `xml:"BlobType"`
+	}
+	type blobItem struct {
+		Name       string     `xml:"Name"`
+		Properties properties `xml:"Properties"`
+	}
+	type enumerationResults struct {
+		XMLName       xml.Name   `xml:"EnumerationResults"`
+		ServiceURL    string     `xml:"ServiceEndpoint,attr"`
+		ContainerName string     `xml:"ContainerName,attr"`
+		Prefix        string     `xml:"Prefix"`
+		Blobs         []blobItem `xml:"Blobs>Blob"`
+		NextMarker    string     `xml:"NextMarker"`
+	}
+
+	results := enumerationResults{
+		ServiceURL:    f.URL + "/" + azuriteAccountName + "/",
+		ContainerName: container,
+		Prefix:        prefix,
+		NextMarker:    nextMarker,
+	}
+	for _, name := range names {
+		results.Blobs = append(results.Blobs, blobItem{
+			Name: name,
+			Properties: properties{
+				LastModified:  blobs[name].lastModified.UTC().Format(http.TimeFormat),
+				ContentLength: len(blobs[name].content),
+				BlobType:      "BlockBlob",
+			},
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.WriteString(w, xml.Header)
+	_ = xml.NewEncoder(w).Encode(results)
+}
+
+func writeBlobHeaders(w http.ResponseWriter, blob *fakeBlob) {
+	w.Header().Set("ETag", fmt.Sprintf("%q", strconv.FormatInt(blob.lastModified.UnixNano(), 16)))
+	w.Header().Set("Last-Modified", blob.lastModified.UTC().Format(http.TimeFormat))
+}
+
+func azuriteError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("x-ms-error-code", code)
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = fmt.Fprintf(w, "%s<Error><Code>%s</Code><Message>%s</Message></Error>", xml.Header, code, code)
+}
@@ -14,6 +14,8 @@ type Config struct {
 	TTL          time.Duration
 	S3           S3Config
 	GCS          GCSConfig
+	Azure        AzureConfig
+	Filesystem   FilesystemConfig
 }
 
 func normalizeConfig(t Config) Config {
@@ -30,5 +32,11 @@ func normalizeConfig(t Config) Config {
 		// No subdomains on built-in blobstore.
 		o.S3.UsePathStyle = true
 	}
+
+	if o.Backend == "filesystem" {
+		// Bucket directories are cheap to create, and there is nobody else to
+		// provision them.
+		o.ManageBucket = true
+	}
 	return o
 }
@@ -0,0 +1,338 @@
+package uploadstore
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/log"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+	"github.com/sourcegraph/sourcegraph/lib/iterator"
+)
+
+// filesystemStore stores objects as files in a local directory. It is intended
+// for single-node installations, where running a blobstore is not necessary.
+type filesystemStore struct {
+	root         string
+	manageBucket bool
+	operations   *Operations
+}
+
+var _ Store = &filesystemStore{}
+
+type FilesystemConfig struct {
+	// Dir is the directory in which buckets are created. Each bucket is a
+	// subdirectory of Dir.
+	Dir string
+}
+
+// filesystemTempPrefix is the prefix of files that are being written. They are
+// renamed to their final name once complete and are skipped by List.
+const filesystemTempPrefix = ".uploadstore-tmp-"
+
+// newFilesystemFromConfig creates a new store backed by the local filesystem.
+func newFilesystemFromConfig(_ context.Context, config Config, operations *Operations) (Store, error) {
+	if config.Filesystem.Dir == "" {
+		return nil, errors.New("no directory configured for filesystem upload store")
+	}
+
+	return newFilesystemWithRoot(filepath.Join(config.Filesystem.Dir, config.Bucket), config.ManageBucket, operations), nil
+}
+
+func newFilesystemWithRoot(root string, manageBucket bool, operations *Operations) *filesystemStore {
+	return &filesystemStore{
+		root:         root,
+		manageBucket: manageBucket,
+		operations:   operations,
+	}
+}
+
+func (s *filesystemStore) Init(ctx context.Context) error {
+	if !s.manageBucket {
+		if _, err := os.Stat(s.root); err != nil {
+			return errors.Wrap(err, "failed to stat bucket directory")
+		}
+
+		return nil
+	}
+
+	if err := os.MkdirAll(s.root, 0o755); err != nil {
+		return errors.Wrap(err, "failed to create bucket directory")
+	}
+
+	return nil
+}
+
+func (s *filesystemStore) List(ctx context.Context, prefix string) (_ *iterator.Iterator[string], err error) {
+	ctx, _, endObservation := s.operations.List.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("prefix", prefix),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	var keys []string
+	if err := s.walk(prefix, func(key string, _ fs.FileInfo) error {
+		keys = append(keys, key)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return iterator.From[string](keys), nil
+}
+
+func (s *filesystemStore) Get(ctx context.Context, key string) (_ io.ReadCloser, err error) {
+	ctx, _, endObservation := s.operations.Get.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("key", key),
+	}})
+	done := func() { endObservation(1, observation.Args{}) }
+
+	path, err := s.path(key)
+	if err != nil {
+		done()
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		done()
+		return nil, errors.Wrap(err, "failed to get object")
+	}
+
+	return NewExtraCloser(f, done), nil
+}
+
+func (s *filesystemStore) Upload(ctx context.Context, key string, r io.Reader) (_ int64, err error) {
+	ctx, _, endObservation := s.operations.Upload.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("key", key),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	n, err := s.write(key, func(w io.Writer) (int64, error) {
+		return io.Copy(w, r)
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to upload object")
+	}
+
+	return n, nil
+}
+
+func (s *filesystemStore) Compose(ctx context.Context, destination string, sources ...string) (_ int64, err error) {
+	ctx, _, endObservation := s.operations.Compose.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("destination", destination),
+		attribute.StringSlice("sources", sources),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	n, err := s.write(destination, func(w io.Writer) (int64, error) {
+		var total int64
+		for _, source := range sources {
+			n, err := s.copyFrom(w, source)
+			total += n
+			if err != nil {
+				return total, err
+			}
+		}
+
+		return total, nil
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to compose objects")
+	}
+
+	// Delete sources on success
+	for _, source := range sources {
+		if err := s.remove(source); err != nil {
+			s.operations.Compose.Logger.Error("failed to delete source object", log.String("key", source), log.Error(err))
+		}
+	}
+
+	return n, nil
+}
+
+func (s *filesystemStore) Delete(ctx context.Context, key string) (err error) {
+	ctx, _, endObservation := s.operations.Delete.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("key", key),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	return errors.Wrap(s.remove(key), "failed to delete object")
+}
+
+func (s *filesystemStore) ExpireObjects(ctx context.Context, prefix string, maxAge time.Duration) (err error) {
+	ctx, _, endObservation := s.operations.ExpireObjects.With(ctx, &err, observation.Args{Attrs: []attribute.KeyValue{
+		attribute.String("prefix", prefix),
+		attribute.Stringer("maxAge", maxAge),
+	}})
+	defer endObservation(1, observation.Args{})
+
+	if err := s.walk(prefix, func(key string, info fs.FileInfo) error {
+		if time.Since(info.ModTime()) < maxAge {
+			return nil
+		}
+
+		if err := s.remove(key); err != nil && !os.IsNotExist(err) {
+			s.operations.ExpireObjects.Logger.Error("Failed to delete expired file",
+				log.Error(err),
+				log.String("root", s.root),
+				log.String("key", key))
+		}
+
+		return nil
+	}); err != nil {
+		s.operations.ExpireObjects.Logger.Error("Failed to walk upload store directory", log.Error(err))
+	}
+
+	return nil
+}
+
+// path returns the path of the file storing the given key. Keys must not refer
+// to files outside of the root directory.
+func (s *filesystemStore) path(key string) (string, error) {
+	if key == "" || !filepath.IsLocal(filepath.FromSlash(key)) {
+		return "", errors.Errorf("invalid key %q", key)
+	}
+
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+// write atomically replaces the file at the given key with the content written
+// by the given function.
+func (s *filesystemStore) write(key string, f func(w io.Writer) (int64, error)) (_ int64, err error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filesystemTempPrefix+"*")
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	n, err := f(tmp)
+	if err != nil {
+		return 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+func (s *filesystemStore) copyFrom(w io.Writer, key string) (int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get source object")
+	}
+	defer f.Close()
+
+	return io.Copy(w, f)
+}
+
+// remove deletes the file at the given key, along with any parent directories
+// below the root that become empty.
+func (s *filesystemStore) remove(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+
+	for dir := filepath.Dir(path); dir != s.root; dir = filepath.Dir(dir) {
+		// Fails if the directory is not empty, in which case its parents are not
+		// empty either.
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+
+	return nil
+}
+
+// walk calls the given function for each stored object with the given key
+// prefix, in lexicographical key order.
+func (s *filesystemStore) walk(prefix string, f func(key string, info fs.FileInfo) error) error {
+	var keys []string
+	infos := map[string]fs.FileInfo{}
+
+	err := filepath.WalkDir(s.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		rel, err := filepath.Rel(s.root, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			// Skip directories that cannot contain keys with the given prefix.
+			if path != s.root && !strings.HasPrefix(key+"/", prefix) && !strings.HasPrefix(prefix, key+"/") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), filesystemTempPrefix) || !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		keys = append(keys, key)
+		infos[key] = info
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// WalkDir visits entries in lexical order per directory, which differs from
+	// key order when names contain characters that sort before '/'.
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if err := f(key, infos[key]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
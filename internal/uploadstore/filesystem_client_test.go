@@ -0,0 +1,73 @@
+package uploadstore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/observation"
+)
+
+func TestFilesystemStore(t *testing.T) {
+	testStoreBehavior(t, testFilesystemClient(t, filepath.Join(t.TempDir(), "test-bucket")))
+}
+
+func TestFilesystemStoreUnmanagedInit(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "test-bucket")
+	client := newFilesystemWithRoot(root, false, NewOperations(&observation.TestContext, "test", "brittlestore"))
+
+	if err := client.Init(context.Background()); err == nil {
+		t.Fatalf("expected error initializing store without bucket directory")
+	}
+
+	if err := os.Mkdir(root, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Init(context.Background()); err != nil {
+		t.Fatalf("unexpected error initializing store: %s", err)
+	}
+}
+
+func TestFilesystemStoreInvalidKeys(t *testing.T) {
+	client := testFilesystemClient(t, filepath.Join(t.TempDir(), "test-bucket"))
+
+	for _, key := range []string{"", "../escape", "a/../../escape", "/absolute"} {
+		if _, err := client.Upload(context.Background(), key, strings.NewReader("payload")); err == nil {
+			t.Errorf("expected error uploading invalid key %q", key)
+		}
+	}
+}
+
+func TestFilesystemStoreCleansUp(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "test-bucket")
+	client := testFilesystemClient(t, root)
+	ctx := context.Background()
+
+	if _, err := client.Upload(ctx, "a/b/c", strings.NewReader("payload")); err != nil {
+		t.Fatalf("unexpected error uploading object: %s", err)
+	}
+	if err := client.Delete(ctx, "a/b/c"); err != nil {
+		t.Fatalf("unexpected error deleting object: %s", err)
+	}
+
+	// Empty parent directories are removed, but not the bucket itself
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		t.Fatalf("unexpected error reading bucket directory: %s", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected empty bucket directory, have %d entries", len(entries))
+	}
+}
+
+func testFilesystemClient(t *testing.T, root string) Store {
+	t.Helper()
+
+	client := newFilesystemWithRoot(root, true, NewOperations(&observation.TestContext, "test", "brittlestore"))
+	if err := client.Init(context.Background()); err != nil {
+		t.Fatalf("unexpected error initializing store: %s", err)
+	}
+	return client
+}
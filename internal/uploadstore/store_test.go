@@ -1,11 +1,19 @@
 package uploadstore
 
 import (
+	"context"
 	"flag"
+	"io"
 	"os"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/inconshreveable/log15" //nolint:logging // TODO move all logging to sourcegraph/log
+
+	"github.com/sourcegraph/sourcegraph/lib/iterator"
 )
 
 func TestMain(m *testing.M) {
@@ -15,3 +23,137 @@ func TestMain(m *testing.M) {
 	}
 	os.Exit(m.Run())
 }
+
+// testStoreBehavior exercises a Store implementation end to end. It is shared by
+// the backends that can be tested against a real or faked storage service.
+func testStoreBehavior(t *testing.T, store Store) {
+	ctx := context.Background()
+
+	if err := store.Init(ctx); err != nil {
+		t.Fatalf("unexpected error initializing store: %s", err)
+	}
+	// Init is idempotent
+	if err := store.Init(ctx); err != nil {
+		t.Fatalf("unexpected error re-initializing store: %s", err)
+	}
+
+	upload := func(key, payload string) {
+		t.Helper()
+
+		n, err := store.Upload(ctx, key, strings.NewReader(payload))
+		if err != nil {
+			t.Fatalf("unexpected error uploading %q: %s", key, err)
+		}
+		if n != int64(len(payload)) {
+			t.Errorf("unexpected size for %q. want=%d have=%d", key, len(payload), n)
+		}
+	}
+
+	get := func(key string) string {
+		t.Helper()
+
+		rc, err := store.Get(ctx, key)
+		if err != nil {
+			t.Fatalf("unexpected error getting %q: %s", key, err)
+		}
+		defer rc.Close()
+
+		contents, err := io.ReadAll(rc)
+		if err != nil {
+			t.Fatalf("unexpected error reading %q: %s", key, err)
+		}
+		return string(contents)
+	}
+
+	list := func(prefix string) []string {
+		t.Helper()
+
+		keys, err := iterator.Collect(mustList(t, store, prefix))
+		if err != nil {
+			t.Fatalf("unexpected error listing %q: %s", prefix, err)
+		}
+		sort.Strings(keys)
+		return keys
+	}
+
+	upload("uploads/1", "TEST PAYLOAD")
+	upload("uploads/2", "")
+	upload("parts/1/a", "foo")
+	upload("parts/1/b", "bar")
+	upload("parts/1/c", "baz")
+	upload("other", "other")
+
+	if have := get("uploads/1"); have != "TEST PAYLOAD" {
+		t.Errorf("unexpected contents. want=%q have=%q", "TEST PAYLOAD", have)
+	}
+	if have := get("uploads/2"); have != "" {
+		t.Errorf("unexpected contents. want=%q have=%q", "", have)
+	}
+
+	// Uploads overwrite existing objects
+	upload("uploads/1", "NEW PAYLOAD")
+	if have := get("uploads/1"); have != "NEW PAYLOAD" {
+		t.Errorf("unexpected contents. want=%q have=%q", "NEW PAYLOAD", have)
+	}
+
+	if diff := cmp.Diff([]string{"uploads/1", "uploads/2"}, list("uploads/")); diff != "" {
+		t.Errorf("unexpected keys (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"other", "parts/1/a", "parts/1/b", "parts/1/c", "uploads/1", "uploads/2"}, list("")); diff != "" {
+		t.Errorf("unexpected keys (-want +got):\n%s", diff)
+	}
+
+	n, err := store.Compose(ctx, "composed", "parts/1/a", "parts/1/b", "parts/1/c")
+	if err != nil {
+		t.Fatalf("unexpected error composing objects: %s", err)
+	}
+	if n != 9 {
+		t.Errorf("unexpected composed size. want=%d have=%d", 9, n)
+	}
+	if have := get("composed"); have != "foobarbaz" {
+		t.Errorf("unexpected contents. want=%q have=%q", "foobarbaz", have)
+	}
+	if keys := list("parts/"); len(keys) != 0 {
+		t.Errorf("expected sources to be deleted, have %v", keys)
+	}
+
+	if _, err := store.Compose(ctx, "broken", "uploads/1", "missing"); err == nil {
+		t.Errorf("expected error composing missing source")
+	}
+	if have := get("uploads/1"); have != "NEW PAYLOAD" {
+		t.Errorf("expected sources to remain after failed compose, have %q", have)
+	}
+
+	if err := store.Delete(ctx, "other"); err != nil {
+		t.Fatalf("unexpected error deleting object: %s", err)
+	}
+	if diff := cmp.Diff([]string{"composed", "uploads/1", "uploads/2"}, list("")); diff != "" {
+		t.Errorf("unexpected keys (-want +got):\n%s", diff)
+	}
+
+	// Nothing is older than an hour
+	if err := store.ExpireObjects(ctx, "", time.Hour); err != nil {
+		t.Fatalf("unexpected error expiring objects: %s", err)
+	}
+	if diff := cmp.Diff([]string{"composed", "uploads/1", "uploads/2"}, list("")); diff != "" {
+		t.Errorf("unexpected keys (-want +got):\n%s", diff)
+	}
+
+	// Everything is at least zero seconds old
+	if err := store.ExpireObjects(ctx, "uploads/", 0); err != nil {
+		t.Fatalf("unexpected error expiring objects: %s", err)
+	}
+	if diff := cmp.Diff([]string{"composed"}, list("")); diff != "" {
+		t.Errorf("unexpected keys (-want +got):\n%s", diff)
+	}
+}
+
+func mustList(t *testing.T, store Store, prefix string) *iterator.Iterator[string] {
+	t.Helper()
+
+	it, err := store.List(context.Background(), prefix)
+	if err != nil {
+		t.Fatalf("unexpected error listing %q: %s", prefix, err)
+	}
+	return it
+}
@@ -18,7 +18,10 @@ SELECT
 	COUNT(*) FILTER (WHERE name = 'SearchNotebookAddBlock' AND argument->>'type' = 'md') AS added_notebook_markdown_blocks_count,
 	COUNT(*) FILTER (WHERE name = 'SearchNotebookAddBlock' AND argument->>'type' = 'query') AS added_notebook_query_blocks_count,
 	COUNT(*) FILTER (WHERE name = 'SearchNotebookAddBlock' AND argument->>'type' = 'file') AS added_notebook_file_blocks_count,
-	COUNT(*) FILTER (WHERE name = 'SearchNotebookAddBlock' AND argument->>'type' = 'symbol') AS added_notebook_symbol_blocks_count
+	COUNT(*) FILTER (WHERE name = 'SearchNotebookAddBlock' AND argument->>'type' = 'symbol') AS added_notebook_symbol_blocks_count,
+	COUNT(*) FILTER (WHERE name = 'SearchNotebookAddBlock' AND argument->>'type' = 'compute') AS added_notebook_compute_blocks_count,
+	COUNT(*) FILTER (WHERE name = 'SearchNotebookAddBlock' AND argument->>'type' = 'insight') AS added_notebook_insight_blocks_count,
+	COUNT(*) FILTER (WHERE name = 'SearchNotebookAddBlock' AND argument->>'type' = 'references') AS added_notebook_references_blocks_count
 FROM event_logs
 WHERE name IN (
 	'ViewSearchNotebookPage',
@@ -44,6 +47,9 @@ WHERE name IN (
 		&notebooksUsageStats.NotebookAddedQueryBlocksCount,
 		&notebooksUsageStats.NotebookAddedFileBlocksCount,
 		&notebooksUsageStats.NotebookAddedSymbolBlocksCount,
+		&notebooksUsageStats.NotebookAddedComputeBlocksCount,
+		&notebooksUsageStats.NotebookAddedInsightBlocksCount,
+		&notebooksUsageStats.NotebookAddedReferencesBlocksCount,
 	); err != nil {
 		return nil, err
 	}
@@ -39,7 +39,10 @@ VALUES
 	(13, 'SearchNotebookPageViewed', '{}', '', 1, '420657f0-d443-4d16-ac7d-003d8cdc91ef', 'WEB', 'version', $1::timestamp - interval '1 day'),
 	(14, 'SearchNotebooksListPageViewed', '{}', '', 1, '420657f0-d443-4d16-ac7d-003d8cdc91ef', 'WEB', 'version', $1::timestamp - interval '1 day'),
 	(15, 'SearchNotebooksListPageViewed', '{}', '', 1, '420657f0-d443-4d16-ac7d-003d8cdc91ef', 'WEB', 'version', $1::timestamp - interval '1 day'),
-	(16, 'EmbeddedNotebookPageViewed', '{}', '', 1, '420657f0-d443-4d16-ac7d-003d8cdc91ef', 'WEB', 'version', $1::timestamp - interval '1 day')
+	(16, 'EmbeddedNotebookPageViewed', '{}', '', 1, '420657f0-d443-4d16-ac7d-003d8cdc91ef', 'WEB', 'version', $1::timestamp - interval '1 day'),
+	(17, 'SearchNotebookAddBlock', '{"type":"compute"}', '', 1, '420657f0-d443-4d16-ac7d-003d8cdc91ef', 'WEB', 'version', $1::timestamp - interval '1 day'),
+	(18, 'SearchNotebookAddBlock', '{"type":"insight"}', '', 1, '420657f0-d443-4d16-ac7d-003d8cdc91ef', 'WEB', 'version', $1::timestamp - interval '1 day'),
+	(19, 'SearchNotebookAddBlock', '{"type":"references"}', '', 1, '420657f0-d443-4d16-ac7d-003d8cdc91ef', 'WEB', 'version', $1::timestamp - interval '1 day')
 `, now)
 	if err != nil {
 		t.Fatal(err)
@@ -55,15 +58,18 @@ VALUES
 	fourInt := int32(4)
 
 	want := &types.NotebooksUsageStatistics{
-		NotebookPageViews:                &fourInt,
-		NotebooksListPageViews:           &fourInt,
-		EmbeddedNotebookPageViews:        &twoInt,
-		NotebooksCreatedCount:            &oneInt,
-		NotebookAddedStarsCount:          &oneInt,
-		NotebookAddedMarkdownBlocksCount: &oneInt,
-		NotebookAddedQueryBlocksCount:    &oneInt,
-		NotebookAddedFileBlocksCount:     &oneInt,
-		NotebookAddedSymbolBlocksCount:   &oneInt,
+		NotebookPageViews:                  &fourInt,
+		NotebooksListPageViews:             &fourInt,
+		EmbeddedNotebookPageViews:          &twoInt,
+		NotebooksCreatedCount:              &oneInt,
+		NotebookAddedStarsCount:            &oneInt,
+		NotebookAddedMarkdownBlocksCount:   &oneInt,
+		NotebookAddedQueryBlocksCount:      &oneInt,
+		NotebookAddedFileBlocksCount:       &oneInt,
+		NotebookAddedSymbolBlocksCount:     &oneInt,
+		NotebookAddedComputeBlocksCount:    &oneInt,
+		NotebookAddedInsightBlocksCount:    &oneInt,
+		NotebookAddedReferencesBlocksCount: &oneInt,
 	}
 	if diff := cmp.Diff(want, got); diff != "" {
 		t.Fatal(diff)
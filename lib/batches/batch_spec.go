@@ -37,6 +37,8 @@ type BatchSpec struct {
 	TransformChanges  *TransformChanges        `json:"transformChanges,omitempty" yaml:"transformChanges,omitempty"`
 	ImportChangesets  []ImportChangeset        `json:"importChangesets,omitempty" yaml:"importChangesets"`
 	ChangesetTemplate *ChangesetTemplate       `json:"changesetTemplate,omitempty" yaml:"changesetTemplate"`
+	Continuous        *Continuous              `json:"continuous,omitempty" yaml:"continuous,omitempty"`
+	Rollout           *Rollout                 `json:"rollout,omitempty" yaml:"rollout,omitempty"`
 }
 
 type ChangesetTemplate struct {
@@ -177,6 +179,14 @@ func parseBatchSpec(schema string, data []byte) (*BatchSpec, error) {
 		}
 	}
 
+	if spec.Continuous != nil {
+		errs = errors.Append(errs, spec.Continuous.validate())
+	}
+
+	if spec.Rollout != nil {
+		errs = errors.Append(errs, spec.Rollout.validate())
+	}
+
 	return &spec, errs
 }
 
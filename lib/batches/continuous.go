@@ -0,0 +1,47 @@
+package batches
+
+import (
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Continuous configures a batch change to be kept up to date by re-running
+// its batch spec periodically.
+type Continuous struct {
+	Interval  string `json:"interval,omitempty" yaml:"interval,omitempty"`
+	AutoApply bool   `json:"autoApply,omitempty" yaml:"autoApply,omitempty"`
+}
+
+const (
+	defaultContinuousInterval = 24 * time.Hour
+	minContinuousInterval     = 1 * time.Hour
+)
+
+// IntervalDuration returns the interval in which the batch spec is re-run.
+// Invalid intervals are rejected when the batch spec is parsed, so the
+// default is returned for them.
+func (c *Continuous) IntervalDuration() time.Duration {
+	if c.Interval == "" {
+		return defaultContinuousInterval
+	}
+	d, err := time.ParseDuration(c.Interval)
+	if err != nil || d < minContinuousInterval {
+		return defaultContinuousInterval
+	}
+	return d
+}
+
+func (c *Continuous) validate() error {
+	if c.Interval == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(c.Interval)
+	if err != nil {
+		return NewValidationError(errors.Newf("continuous.interval %q is not a valid duration", c.Interval))
+	}
+	if d < minContinuousInterval {
+		return NewValidationError(errors.Newf("continuous.interval must be at least %s", minContinuousInterval))
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package batches
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContinuous_IntervalDuration(t *testing.T) {
+	for interval, want := range map[string]time.Duration{
+		"":      24 * time.Hour,
+		"168h":  168 * time.Hour,
+		"1h":    1 * time.Hour,
+		"5m":    24 * time.Hour,
+		"bogus": 24 * time.Hour,
+	} {
+		assert.Equal(t, want, (&Continuous{Interval: interval}).IntervalDuration(), interval)
+	}
+}
+
+func TestParseBatchSpec_Continuous(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		const spec = `
+name: hello-world
+on:
+  - repositoriesMatchingQuery: file:README.md
+continuous:
+  interval: 12h
+  autoApply: true
+`
+
+		have, err := ParseBatchSpec([]byte(spec))
+		if err != nil {
+			t.Fatalf("parsing valid spec returned error: %s", err)
+		}
+		assert.Equal(t, &Continuous{Interval: "12h", AutoApply: true}, have.Continuous)
+	})
+
+	for name, tc := range map[string]struct {
+		interval string
+		wantErr  string
+	}{
+		"invalid interval":   {interval: "daily", wantErr: `continuous.interval "daily" is not a valid duration`},
+		"interval too short": {interval: "10m", wantErr: "continuous.interval must be at least 1h0m0s"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			spec := `
+name: hello-world
+on:
+  - repositoriesMatchingQuery: file:README.md
+continuous:
+  interval: ` + tc.interval + `
+`
+
+			_, err := ParseBatchSpec([]byte(spec))
+			if err == nil {
+				t.Fatal("no error returned")
+			}
+			assert.Equal(t, tc.wantErr, err.Error())
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package batches
+
+import (
+	"hash/fnv"
+
+	"github.com/gobwas/glob"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// Rollout describes how the changesets of a batch change are published in
+// consecutive waves.
+type Rollout struct {
+	Waves     []RolloutWave `json:"waves,omitempty" yaml:"waves"`
+	Threshold float64       `json:"threshold,omitempty" yaml:"threshold,omitempty"`
+
+	// patterns are the compiled repository patterns of each wave. They are
+	// set by Compile.
+	patterns [][]glob.Glob
+}
+
+// RolloutWave is a single wave of a Rollout. Exactly one of Repositories and
+// Percentage is set.
+type RolloutWave struct {
+	Repositories []string `json:"repositories,omitempty" yaml:"repositories,omitempty"`
+	Percentage   int      `json:"percentage,omitempty" yaml:"percentage,omitempty"`
+}
+
+// defaultRolloutThreshold is used when the rollout doesn't define a
+// threshold: every changeset of a wave has to be merged or have passing
+// checks before the next wave is published.
+const defaultRolloutThreshold = 1.0
+
+// RequiredFraction returns the fraction of changesets of a wave that need to
+// be merged or have passing checks before the next wave can be published.
+func (r *Rollout) RequiredFraction() float64 {
+	if r.Threshold <= 0 || r.Threshold > 1 {
+		return defaultRolloutThreshold
+	}
+	return r.Threshold
+}
+
+// NumWaves returns the number of waves of the rollout, including the implicit
+// final wave that contains all repositories not matched by any explicit wave.
+func (r *Rollout) NumWaves() int {
+	return len(r.Waves) + 1
+}
+
+// WaveForRepo returns the zero-based index of the wave the given repository
+// is published in. Waves are evaluated in order and the first match wins.
+// Repositories that aren't matched by any wave are assigned to the implicit
+// final wave, whose index is len(r.Waves).
+//
+// The repository patterns have to be compiled with Compile first, which
+// ParseBatchSpec does.
+func (r *Rollout) WaveForRepo(repoName string) (int, error) {
+	if len(r.patterns) != len(r.Waves) {
+		return 0, errors.New("rollout patterns are not compiled")
+	}
+
+	bucket := rolloutBucket(repoName)
+
+	for i, wave := range r.Waves {
+		if wave.Percentage > 0 {
+			if bucket < wave.Percentage {
+				return i, nil
+			}
+			continue
+		}
+
+		for _, g := range r.patterns[i] {
+			if g.Match(repoName) {
+				return i, nil
+			}
+		}
+	}
+
+	return len(r.Waves), nil
+}
+
+// Compile compiles the repository patterns of the waves. Rollouts that are
+// unmarshalled without ParseBatchSpec have to be compiled before
+// WaveForRepo is called.
+func (r *Rollout) Compile() error {
+	patterns := make([][]glob.Glob, len(r.Waves))
+	for i, wave := range r.Waves {
+		for _, pattern := range wave.Repositories {
+			g, err := glob.Compile(pattern)
+			if err != nil {
+				return errors.Wrapf(err, "compiling pattern %q of rollout wave %d", pattern, i+1)
+			}
+			patterns[i] = append(patterns[i], g)
+		}
+	}
+	r.patterns = patterns
+	return nil
+}
+
+// validate returns an error for every wave that is not well-formed, and
+// compiles the repository patterns if all of them are valid.
+func (r *Rollout) validate() (errs error) {
+	patterns := make([][]glob.Glob, len(r.Waves))
+	lastPercentage := 0
+	for i, wave := range r.Waves {
+		if wave.Percentage != 0 && len(wave.Repositories) != 0 {
+			errs = errors.Append(errs, NewValidationError(errors.Newf("rollout wave %d defines both repositories and percentage", i+1)))
+		}
+		if wave.Percentage < 0 || wave.Percentage > 100 {
+			errs = errors.Append(errs, NewValidationError(errors.Newf("rollout wave %d percentage must be between 1 and 100", i+1)))
+		} else if wave.Percentage != 0 {
+			// Percentages are cumulative, so a smaller percentage than that
+			// of an earlier wave would always be empty.
+			if wave.Percentage < lastPercentage {
+				errs = errors.Append(errs, NewValidationError(errors.Newf("rollout wave %d percentage must not be smaller than that of an earlier wave", i+1)))
+			}
+			lastPercentage = wave.Percentage
+		}
+		for _, pattern := range wave.Repositories {
+			g, err := glob.Compile(pattern)
+			if err != nil {
+				errs = errors.Append(errs, NewValidationError(errors.Newf("rollout wave %d contains invalid repository pattern %q", i+1, pattern)))
+				continue
+			}
+			patterns[i] = append(patterns[i], g)
+		}
+	}
+	if errs == nil {
+		r.patterns = patterns
+	}
+	return errs
+}
+
+// rolloutBucket deterministically assigns the given repository name to a
+// bucket in the range [0, 100).
+func rolloutBucket(repoName string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(repoName))
+	return int(h.Sum32() % 100)
+}
@@ -0,0 +1,201 @@
+package batches
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollout_WaveForRepo(t *testing.T) {
+	rollout := &Rollout{
+		Waves: []RolloutWave{
+			{Repositories: []string{"github.com/sourcegraph/canary-*"}},
+			{Percentage: 100},
+		},
+	}
+	assert.NoError(t, rollout.Compile())
+
+	tests := []struct {
+		repo string
+		want int
+	}{
+		{repo: "github.com/sourcegraph/canary-one", want: 0},
+		{repo: "github.com/sourcegraph/canary-two", want: 0},
+		{repo: "github.com/sourcegraph/sourcegraph", want: 1},
+		{repo: "github.com/other/repo", want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repo, func(t *testing.T) {
+			have, err := rollout.WaveForRepo(tt.repo)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, have)
+		})
+	}
+
+	t.Run("unmatched repos are in the implicit final wave", func(t *testing.T) {
+		rollout := &Rollout{Waves: []RolloutWave{{Repositories: []string{"github.com/sourcegraph/*"}}}}
+		assert.NoError(t, rollout.Compile())
+
+		have, err := rollout.WaveForRepo("gitlab.com/sourcegraph/sourcegraph")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, have)
+		assert.Equal(t, 2, rollout.NumWaves())
+	})
+
+	t.Run("percentages are cumulative and deterministic", func(t *testing.T) {
+		rollout := &Rollout{Waves: []RolloutWave{{Percentage: 10}, {Percentage: 50}}}
+		assert.NoError(t, rollout.Compile())
+
+		counts := make([]int, rollout.NumWaves())
+		for i := 0; i < 1000; i++ {
+			repo := "github.com/sourcegraph/repo-" + string(rune('a'+i%26)) + string(rune('a'+i/26))
+			first, err := rollout.WaveForRepo(repo)
+			assert.NoError(t, err)
+			second, err := rollout.WaveForRepo(repo)
+			assert.NoError(t, err)
+			assert.Equal(t, first, second)
+			counts[first]++
+		}
+
+		// Roughly 10% of the repos should be in the first wave, 40% in the
+		// second wave and the remainder in the implicit final wave.
+		assert.InDelta(t, 100, counts[0], 50)
+		assert.InDelta(t, 400, counts[1], 100)
+		assert.InDelta(t, 500, counts[2], 100)
+	})
+
+	t.Run("patterns have to be compiled", func(t *testing.T) {
+		rollout := &Rollout{Waves: []RolloutWave{{Repositories: []string{"github.com/sourcegraph/*"}}}}
+
+		_, err := rollout.WaveForRepo("github.com/sourcegraph/sourcegraph")
+		assert.EqualError(t, err, "rollout patterns are not compiled")
+	})
+}
+
+func TestRollout_RequiredFraction(t *testing.T) {
+	assert.Equal(t, 1.0, (&Rollout{}).RequiredFraction())
+	assert.Equal(t, 0.5, (&Rollout{Threshold: 0.5}).RequiredFraction())
+}
+
+func TestParseBatchSpec_Rollout(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		const spec = `
+name: hello-world
+on:
+  - repositoriesMatchingQuery: file:README.md
+rollout:
+  waves:
+    - repositories: ["github.com/sourcegraph/canary-*"]
+    - percentage: 10
+  threshold: 0.8
+`
+
+		have, err := ParseBatchSpec([]byte(spec))
+		if err != nil {
+			t.Fatalf("parsing valid spec returned error: %s", err)
+		}
+
+		wantWaves := []RolloutWave{
+			{Repositories: []string{"github.com/sourcegraph/canary-*"}},
+			{Percentage: 10},
+		}
+		assert.Equal(t, wantWaves, have.Rollout.Waves)
+		assert.Equal(t, 0.8, have.Rollout.Threshold)
+
+		// Parsing compiles the repository patterns.
+		wave, err := have.Rollout.WaveForRepo("github.com/sourcegraph/canary-one")
+		assert.NoError(t, err)
+		assert.Equal(t, 0, wave)
+	})
+
+	t.Run("wave with repositories and percentage", func(t *testing.T) {
+		const spec = `
+name: hello-world
+on:
+  - repositoriesMatchingQuery: file:README.md
+rollout:
+  waves:
+    - repositories: ["github.com/sourcegraph/*"]
+      percentage: 10
+`
+
+		_, err := ParseBatchSpec([]byte(spec))
+		if err == nil {
+			t.Fatal("no error returned")
+		}
+	})
+
+	t.Run("invalid repository pattern", func(t *testing.T) {
+		const spec = `
+name: hello-world
+on:
+  - repositoriesMatchingQuery: file:README.md
+rollout:
+  waves:
+    - repositories: ["github.com/[sourcegraph"]
+`
+
+		_, err := ParseBatchSpec([]byte(spec))
+		if err == nil {
+			t.Fatal("no error returned")
+		}
+
+		wantErr := `rollout wave 1 contains invalid repository pattern "github.com/[sourcegraph"`
+		assert.Equal(t, wantErr, err.Error())
+	})
+
+	t.Run("decreasing percentage", func(t *testing.T) {
+		const spec = `
+name: hello-world
+on:
+  - repositoriesMatchingQuery: file:README.md
+rollout:
+  waves:
+    - percentage: 50
+    - repositories: ["github.com/sourcegraph/*"]
+    - percentage: 10
+`
+
+		_, err := ParseBatchSpec([]byte(spec))
+		if err == nil {
+			t.Fatal("no error returned")
+		}
+
+		wantErr := "rollout wave 3 percentage must not be smaller than that of an earlier wave"
+		assert.Equal(t, wantErr, err.Error())
+	})
+}
+
+func TestRollout_validate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		waves   []RolloutWave
+		wantErr string
+	}{
+		"increasing percentages": {
+			waves: []RolloutWave{{Percentage: 10}, {Percentage: 10}, {Percentage: 100}},
+		},
+		"negative percentage": {
+			waves:   []RolloutWave{{Percentage: -1}},
+			wantErr: "rollout wave 1 percentage must be between 1 and 100",
+		},
+		"percentage above 100": {
+			waves:   []RolloutWave{{Percentage: 10}, {Percentage: 101}},
+			wantErr: "rollout wave 2 percentage must be between 1 and 100",
+		},
+		"decreasing percentages": {
+			waves:   []RolloutWave{{Percentage: 50}, {Percentage: 20}},
+			wantErr: "rollout wave 2 percentage must not be smaller than that of an earlier wave",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := &Rollout{Waves: tc.waves}
+			err := r.validate()
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tc.wantErr)
+		})
+	}
+}
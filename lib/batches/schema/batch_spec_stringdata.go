@@ -401,6 +401,65 @@ const BatchSpecJSON = `{
           ]
         }
       }
+    },
+    "continuous": {
+      "type": "object",
+      "description": "Keeps the batch change up to date by periodically re-running the batch spec. New revisions of the batch spec are only created if repositories start or stop matching, or if the base branch of a repository moved.",
+      "additionalProperties": false,
+      "properties": {
+        "interval": {
+          "type": "string",
+          "description": "How often the batch spec is re-run, as a Go duration string. Defaults to 24h. The minimum is 1h.",
+          "examples": ["24h", "168h"]
+        },
+        "autoApply": {
+          "type": "boolean",
+          "description": "Whether a new revision of the batch spec is applied automatically once its execution completed successfully. If false, the revision has to be previewed and applied by the owner of the batch change."
+        }
+      }
+    },
+    "rollout": {
+      "type": "object",
+      "description": "Publishes the changesets of the batch change in consecutive waves. A wave is only published once enough changesets of the previous wave are merged or have passing checks.",
+      "additionalProperties": false,
+      "required": ["waves"],
+      "properties": {
+        "waves": {
+          "type": "array",
+          "description": "The waves in the order in which they are published. Repositories that aren't matched by any wave are published in an implicit final wave.",
+          "minItems": 1,
+          "items": {
+            "title": "RolloutWave",
+            "type": "object",
+            "description": "A single rollout wave, defined either by a list of repository name patterns or by a percentage of all repositories.",
+            "additionalProperties": false,
+            "oneOf": [{ "required": ["repositories"] }, { "required": ["percentage"] }],
+            "properties": {
+              "repositories": {
+                "type": "array",
+                "description": "A list of glob patterns to match repository names against. Matching repositories are published in this wave.",
+                "minItems": 1,
+                "items": {
+                  "type": "string"
+                },
+                "examples": [["github.com/sourcegraph/*"]]
+              },
+              "percentage": {
+                "type": "integer",
+                "description": "The cumulative percentage of all repositories that should be published once this wave is reached. Repositories are assigned to percentages deterministically based on their name.",
+                "minimum": 1,
+                "maximum": 100
+              }
+            }
+          }
+        },
+        "threshold": {
+          "type": "number",
+          "description": "The fraction of the changesets in a wave that need to be merged or have passing checks before the next wave is published. Defaults to 1.",
+          "exclusiveMinimum": 0,
+          "maximum": 1
+        }
+      }
     }
   }
 }
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS repo_update_schedule;
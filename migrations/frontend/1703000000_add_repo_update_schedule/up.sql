@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS repo_update_schedule (
+    repo_id integer NOT NULL PRIMARY KEY REFERENCES repo(id) ON DELETE CASCADE,
+    update_interval_seconds integer NOT NULL,
+    due_at timestamp with time zone NOT NULL,
+    last_webhook_at timestamp with time zone,
+    updated_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS repo_update_schedule_last_webhook_at ON repo_update_schedule (last_webhook_at) WHERE last_webhook_at IS NOT NULL;
+
+COMMENT ON TABLE repo_update_schedule IS 'The persisted state of the repo-updater update scheduler, so that learned update intervals survive restarts.';
+COMMENT ON COLUMN repo_update_schedule.update_interval_seconds IS 'The interval in which the repository is fetched. Zero if the scheduler has not learned an interval yet.';
+COMMENT ON COLUMN repo_update_schedule.last_webhook_at IS 'The last time a push webhook was received for the repository.';
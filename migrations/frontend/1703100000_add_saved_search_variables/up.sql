@@ -0,0 +1,3 @@
+ALTER TABLE saved_searches ADD COLUMN IF NOT EXISTS variables jsonb;
+
+COMMENT ON COLUMN saved_searches.variables IS 'The typed variables declared by the saved search, as a JSON array of {"name", "type"} objects, or NULL if it declares none. The query references them as $name.';
@@ -0,0 +1,3 @@
+ALTER TABLE search_contexts DROP COLUMN IF EXISTS dynamic_evaluated_at;
+ALTER TABLE search_contexts DROP COLUMN IF EXISTS dynamic_value;
+ALTER TABLE search_contexts DROP COLUMN IF EXISTS dynamic_source;
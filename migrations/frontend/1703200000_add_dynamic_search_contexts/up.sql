@@ -0,0 +1,7 @@
+ALTER TABLE search_contexts ADD COLUMN IF NOT EXISTS dynamic_source text;
+ALTER TABLE search_contexts ADD COLUMN IF NOT EXISTS dynamic_value text;
+ALTER TABLE search_contexts ADD COLUMN IF NOT EXISTS dynamic_evaluated_at timestamp with time zone;
+
+COMMENT ON COLUMN search_contexts.dynamic_source IS 'The source the repositories of a dynamic search context are computed from: owner, meta or dependencies. NULL for static and query-based search contexts.';
+COMMENT ON COLUMN search_contexts.dynamic_value IS 'The owner, repository metadata key (and value) or repository the repositories of a dynamic search context are computed from.';
+COMMENT ON COLUMN search_contexts.dynamic_evaluated_at IS 'When the repositories of a dynamic search context were last computed and stored in search_context_repos, or NULL if they have not been computed yet.';
@@ -0,0 +1 @@
+ALTER TABLE batch_specs DROP COLUMN IF EXISTS continuous_revision;
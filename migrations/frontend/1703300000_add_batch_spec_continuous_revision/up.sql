@@ -0,0 +1,3 @@
+ALTER TABLE batch_specs ADD COLUMN IF NOT EXISTS continuous_revision boolean DEFAULT false NOT NULL;
+
+COMMENT ON COLUMN batch_specs.continuous_revision IS 'Whether the batch spec is a revision of the applied batch spec that was created by the continuous mode of its batch change. Only such revisions are executed, applied and deleted automatically.';
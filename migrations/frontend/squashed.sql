@@ -1194,9 +1194,12 @@ CREATE TABLE batch_specs (
     allow_ignored boolean DEFAULT false NOT NULL,
     no_cache boolean DEFAULT false NOT NULL,
     batch_change_id bigint,
+    continuous_revision boolean DEFAULT false NOT NULL,
     CONSTRAINT batch_specs_has_1_namespace CHECK (((namespace_user_id IS NULL) <> (namespace_org_id IS NULL)))
 );
 
+COMMENT ON COLUMN batch_specs.continuous_revision IS 'Whether the batch spec is a revision of the applied batch spec that was created by the continuous mode of its batch change. Only such revisions are executed, applied and deleted automatically.';
+
 CREATE SEQUENCE batch_specs_id_seq
     START WITH 1
     INCREMENT BY 1
@@ -4453,6 +4456,20 @@ COMMENT ON COLUMN repo_statistics.failed_fetch IS 'Number of repositories that a
 
 COMMENT ON COLUMN repo_statistics.corrupted IS 'Number of repositories that are NOT soft-deleted and not blocked and have corrupted_at set in gitserver_repos table';
 
+CREATE TABLE repo_update_schedule (
+    repo_id integer NOT NULL,
+    update_interval_seconds integer NOT NULL,
+    due_at timestamp with time zone NOT NULL,
+    last_webhook_at timestamp with time zone,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+COMMENT ON TABLE repo_update_schedule IS 'The persisted state of the repo-updater update scheduler, so that learned update intervals survive restarts.';
+
+COMMENT ON COLUMN repo_update_schedule.update_interval_seconds IS 'The interval in which the repository is fetched. Zero if the scheduler has not learned an interval yet.';
+
+COMMENT ON COLUMN repo_update_schedule.last_webhook_at IS 'The last time a push webhook was received for the repository.';
+
 CREATE TABLE role_permissions (
     role_id integer NOT NULL,
     permission_id integer NOT NULL,
@@ -4489,10 +4506,13 @@ CREATE TABLE saved_searches (
     user_id integer,
     org_id integer,
     slack_webhook_url text,
+    variables jsonb,
     CONSTRAINT saved_searches_notifications_disabled CHECK (((notify_owner = false) AND (notify_slack = false))),
     CONSTRAINT user_or_org_id_not_null CHECK ((((user_id IS NOT NULL) AND (org_id IS NULL)) OR ((org_id IS NOT NULL) AND (user_id IS NULL))))
 );
 
+COMMENT ON COLUMN saved_searches.variables IS 'The typed variables declared by the saved search, as a JSON array of {"name", "type"} objects, or NULL if it declares none. The query references them as $name.';
+
 CREATE SEQUENCE saved_searches_id_seq
     START WITH 1
     INCREMENT BY 1
@@ -4534,11 +4554,20 @@ CREATE TABLE search_contexts (
     updated_at timestamp with time zone DEFAULT now() NOT NULL,
     deleted_at timestamp with time zone,
     query text,
+    dynamic_source text,
+    dynamic_value text,
+    dynamic_evaluated_at timestamp with time zone,
     CONSTRAINT search_contexts_has_one_or_no_namespace CHECK (((namespace_user_id IS NULL) OR (namespace_org_id IS NULL)))
 );
 
 COMMENT ON COLUMN search_contexts.deleted_at IS 'This column is unused as of Sourcegraph 3.34. Do not refer to it anymore. It will be dropped in a future version.';
 
+COMMENT ON COLUMN search_contexts.dynamic_source IS 'The source the repositories of a dynamic search context are computed from: owner, meta or dependencies. NULL for static and query-based search contexts.';
+
+COMMENT ON COLUMN search_contexts.dynamic_value IS 'The owner, repository metadata key (and value) or repository the repositories of a dynamic search context are computed from.';
+
+COMMENT ON COLUMN search_contexts.dynamic_evaluated_at IS 'When the repositories of a dynamic search context were last computed and stored in search_context_repos, or NULL if they have not been computed yet.';
+
 CREATE SEQUENCE search_contexts_id_seq
     START WITH 1
     INCREMENT BY 1
@@ -5729,6 +5758,9 @@ ALTER TABLE ONLY repo_permissions
 ALTER TABLE ONLY repo
     ADD CONSTRAINT repo_pkey PRIMARY KEY (id);
 
+ALTER TABLE ONLY repo_update_schedule
+    ADD CONSTRAINT repo_update_schedule_pkey PRIMARY KEY (repo_id);
+
 ALTER TABLE ONLY role_permissions
     ADD CONSTRAINT role_permissions_pkey PRIMARY KEY (permission_id, role_id);
 
@@ -6280,6 +6312,8 @@ CREATE INDEX repo_stars_desc_id_desc_idx ON repo USING btree (stars DESC NULLS L
 
 CREATE INDEX repo_stars_idx ON repo USING btree (stars DESC NULLS LAST);
 
+CREATE INDEX repo_update_schedule_last_webhook_at ON repo_update_schedule USING btree (last_webhook_at) WHERE (last_webhook_at IS NOT NULL);
+
 CREATE INDEX repo_uri_idx ON repo USING btree (uri);
 
 CREATE UNIQUE INDEX search_contexts_name_namespace_org_id_unique ON search_contexts USING btree (name, namespace_org_id) WHERE (namespace_org_id IS NOT NULL);
@@ -6901,6 +6935,9 @@ ALTER TABLE ONLY repo_paths
 ALTER TABLE ONLY repo_paths
     ADD CONSTRAINT repo_paths_repo_id_fkey FOREIGN KEY (repo_id) REFERENCES repo(id) ON DELETE CASCADE DEFERRABLE;
 
+ALTER TABLE ONLY repo_update_schedule
+    ADD CONSTRAINT repo_update_schedule_repo_id_fkey FOREIGN KEY (repo_id) REFERENCES repo(id) ON DELETE CASCADE;
+
 ALTER TABLE ONLY role_permissions
     ADD CONSTRAINT role_permissions_permission_id_fkey FOREIGN KEY (permission_id) REFERENCES permissions(id) ON DELETE CASCADE DEFERRABLE;
 
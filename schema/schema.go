@@ -802,6 +802,7 @@ func (v *EncryptionKey) UnmarshalJSON(data []byte) error {
 // EncryptionKeys description: Configuration for encryption keys used to encrypt data at rest in the database.
 type EncryptionKeys struct {
 	BatchChangesCredentialKey *EncryptionKey `json:"batchChangesCredentialKey,omitempty"`
+	BlobstoreKey              *EncryptionKey `json:"blobstoreKey,omitempty"`
 	// CacheSize description: number of values to keep in LRU cache
 	CacheSize int `json:"cacheSize,omitempty"`
 	// EnableCache description: enable LRU cache for decryption APIs
@@ -959,6 +960,8 @@ type ExperimentalFeatures struct {
 	RustPackages string `json:"rustPackages,omitempty"`
 	// SearchIndexBranches description: A map from repository name to a list of extra revs (branch, ref, tag, commit sha, etc) to index for a repository. We always index the default branch ("HEAD") and revisions in version contexts. This allows specifying additional revisions. Sourcegraph can index up to 64 branches per repository.
 	SearchIndexBranches map[string][]string `json:"search.index.branches,omitempty"`
+	// SearchIndexOverrides description: An array of rules that override search indexing options for the repositories that match them. A rule matches a repository when all of its name, meta and searchContext conditions match. When several rules match, their large file patterns are combined and the last rule that sets symbols wins. The maximum indexed file size and the indexed languages cannot be overridden yet.
+	SearchIndexOverrides []*SearchIndexOverridesRule `json:"search.index.overrides,omitempty"`
 	// SearchIndexQueryContexts description: Enables indexing of revisions of repos matching any query defined in search contexts.
 	SearchIndexQueryContexts bool `json:"search.index.query.contexts,omitempty"`
 	// SearchIndexRevisions description: An array of objects describing rules for extra revisions (branch, ref, tag, commit sha, etc) to be indexed for all repositories that match them. We always index the default branch ("HEAD") and revisions in version contexts. This allows specifying additional revisions. Sourcegraph can index up to 64 branches per repository.
@@ -2095,6 +2098,14 @@ type Ranking struct {
 	// RepoScores description: a map of URI directories to numeric scores for specifying search result importance, like {"github.com": 500, "github.com/sourcegraph": 300, "github.com/sourcegraph/sourcegraph": 100}. Would rank "github.com/sourcegraph/sourcegraph" as 500+300+100=900, and "github.com/other/foo" as 500.
 	RepoScores map[string]float64 `json:"repoScores,omitempty"`
 }
+
+// RateLimitHeadroom description: The percentage of each code host rate limit burst that requests of lower priority leave untouched for requests of higher priority. Interactive requests, such as user-triggered permission syncs and publishing changesets, can always use the full burst.
+type RateLimitHeadroom struct {
+	// Background description: The percentage of the burst that background requests, such as listing the repositories of code host connections and syncing changesets, leave for all other requests.
+	Background *int `json:"background,omitempty"`
+	// Default description: The percentage of the burst that requests without a specific priority leave for interactive requests.
+	Default *int `json:"default,omitempty"`
+}
 type RateLimits struct {
 	// GraphQLMaxAliases description: Maximum number of aliases allowed in a GraphQL query
 	GraphQLMaxAliases int `json:"graphQLMaxAliases,omitempty"`
@@ -2245,6 +2256,18 @@ type SMTPServerConfig struct {
 	// Username description: The username to use when communicating with the SMTP server.
 	Username string `json:"username,omitempty"`
 }
+type SearchIndexOverridesRule struct {
+	// LargeFiles description: File glob patterns where matching files will be indexed regardless of their size. They are added to search.largeFiles.
+	LargeFiles []string `json:"largeFiles,omitempty"`
+	// Meta description: Repository metadata the repository must have, either as "key" or "key:value".
+	Meta string `json:"meta,omitempty"`
+	// Name description: Regular expression which matches against the name of a repository (e.g. "^github\.com/owner/name$").
+	Name string `json:"name,omitempty"`
+	// SearchContext description: Search context the repository must be part of (e.g. "@org/context"). Only the repositories listed in a search context are matched, not those matched by a search context query.
+	SearchContext string `json:"searchContext,omitempty"`
+	// Symbols description: Whether symbols are indexed for the matching repositories. Overrides search.index.symbols.enabled.
+	Symbols *bool `json:"symbols,omitempty"`
+}
 type SearchIndexRevisionsRule struct {
 	// Name description: Regular expression which matches against the name of a repository (e.g. "^github\.com/owner/name$").
 	Name string `json:"name,omitempty"`
@@ -2891,8 +2914,10 @@ type SiteConfiguration struct {
 	// PermissionsUserMapping description: Settings for Sourcegraph explicit permissions, which allow the site admin to explicitly manage repository permissions via the GraphQL API. This will mark repositories as restricted by default.
 	PermissionsUserMapping *PermissionsUserMapping `json:"permissions.userMapping,omitempty"`
 	// ProductResearchPageEnabled description: Enables users access to the product research page in their settings.
-	ProductResearchPageEnabled *bool       `json:"productResearchPage.enabled,omitempty"`
-	RateLimits                 *RateLimits `json:"rateLimits,omitempty"`
+	ProductResearchPageEnabled *bool `json:"productResearchPage.enabled,omitempty"`
+	// RateLimitHeadroom description: The percentage of each code host rate limit burst that requests of lower priority leave untouched for requests of higher priority. Interactive requests, such as user-triggered permission syncs and publishing changesets, can always use the full burst.
+	RateLimitHeadroom *RateLimitHeadroom `json:"rateLimitHeadroom,omitempty"`
+	RateLimits        *RateLimits        `json:"rateLimits,omitempty"`
 	// RedactOutboundRequestHeaders description: Enables redacting sensitive information from outbound requests. Important: We only respect this setting in development environments. In production, we always redact outbound requests.
 	RedactOutboundRequestHeaders *bool `json:"redactOutboundRequestHeaders,omitempty"`
 	// RepoConcurrentExternalServiceSyncers description: The number of concurrent external service syncers that can run.
@@ -3078,6 +3103,7 @@ func (v *SiteConfiguration) UnmarshalJSON(data []byte) error {
 	delete(m, "permissions.syncUsersMaxConcurrency")
 	delete(m, "permissions.userMapping")
 	delete(m, "productResearchPage.enabled")
+	delete(m, "rateLimitHeadroom")
 	delete(m, "rateLimits")
 	delete(m, "redactOutboundRequestHeaders")
 	delete(m, "repoConcurrentExternalServiceSyncers")